@@ -16,32 +16,56 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
+	"time"
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hiveintv1alpha1 "github.com/openshift/hive/pkg/apis/hiveinternal/v1alpha1"
 	"github.com/openshift/operator-custom-metrics/pkg/metrics"
 	operatorconfig "github.com/openshift/pagerduty-operator/config"
 	"github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
 	"github.com/openshift/pagerduty-operator/pkg/controller"
+	"github.com/openshift/pagerduty-operator/pkg/controller/pagerdutyintegration"
+	"github.com/openshift/pagerduty-operator/pkg/debug"
+	"github.com/openshift/pagerduty-operator/pkg/dryrun"
+	"github.com/openshift/pagerduty-operator/pkg/fleetaudit"
+	"github.com/openshift/pagerduty-operator/pkg/heartbeat"
 	"github.com/openshift/pagerduty-operator/pkg/localmetrics"
-	"github.com/operator-framework/operator-sdk/pkg/leader"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/preflight"
+	"github.com/openshift/pagerduty-operator/pkg/selfservice"
+	"github.com/openshift/pagerduty-operator/pkg/validation"
+	incidentwebhook "github.com/openshift/pagerduty-operator/pkg/webhook"
 	"github.com/operator-framework/operator-sdk/pkg/log/zap"
 
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 
 	routev1 "github.com/openshift/api/route/v1"
 	sdkVersion "github.com/operator-framework/operator-sdk/version"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/pflag"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 // Change below variables to serve metrics on different host or port.
@@ -50,6 +74,13 @@ var (
 	metricsPort = "8081"
 	metricsPath = "/metrics"
 )
+
+// leaderElectionID names the ConfigMap the replicas of the operator elect
+// their leader with. It differs from the pagerduty-operator-lock ConfigMap
+// of the leader-for-life election it replaces, which is garbage collected
+// with the pod that held it.
+const leaderElectionID = "pagerduty-operator-leader"
+
 var log = logf.Log.WithName("cmd")
 
 func printVersion() {
@@ -62,11 +93,50 @@ func main() {
 	// Add the zap logger flag set to the CLI. The flag set must
 	// be added before calling pflag.Parse().
 	pflag.CommandLine.AddFlagSet(zap.FlagSet())
+	pflag.CommandLine.AddFlagSet(logging.FlagSet())
+	pflag.CommandLine.AddFlagSet(dryrun.FlagSet())
+	pflag.CommandLine.AddFlagSet(pagerdutyintegration.FlagSet())
+	pflag.CommandLine.AddFlagSet(pd.FlagSet())
 
 	// Add flags registered by imported packages (e.g. glog and
 	// controller-runtime)
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 
+	// The operator can be installed in any namespace, the default comes from
+	// the downward API
+	operatorNamespace := pflag.String("operator-namespace", operatorconfig.GetOperatorNamespace(),
+		"Namespace the operator runs in, holding its metrics service and PagerDuty API key secret")
+	apiSecretName := pflag.String("api-secret-name", operatorconfig.PagerDutyAPISecretName,
+		"Name of the secret in the operator namespace holding the PagerDuty API key used for heartbeat metrics and the readiness check")
+	leaderElect := pflag.Bool("leader-elect", true,
+		"Elect a leader among the replicas of the operator, only the leader reconciles")
+	probeAddr := pflag.String("health-probe-bind-address", ":8082",
+		"Address the /healthz and /readyz probe endpoints bind to, 0 disables them")
+	pdReadinessThreshold := pflag.Duration("pagerduty-readiness-threshold", 10*time.Minute,
+		"How long the PagerDuty API may be unreachable, or refuse an API key, before /readyz fails, 0 disables the check")
+	enableWebhooks := pflag.Bool("enable-webhooks", true,
+		"Serve the PagerDutyIntegration conversion and ClusterDeployment validating webhooks, disable them to run the operator outside of a cluster")
+	webhookPort := pflag.Int("webhook-port", 9443,
+		"Port the webhook server listens on")
+	webhookCertDir := pflag.String("webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs",
+		"Directory holding the tls.crt and tls.key the webhook server serves")
+	heartbeatAddr := pflag.String("heartbeat-bind-address", ":8083",
+		"Address the heartbeat check-ins of the clusters are served on, 0 disables them")
+	incidentWebhookAddr := pflag.String("incident-webhook-bind-address", "0",
+		"Address the PagerDuty incident webhooks are received on, with the certificate of --webhook-cert-dir, 0 disables them")
+	incidentWebhookInsecure := pflag.Bool("incident-webhook-insecure", false,
+		"Accept unauthenticated incident webhooks when the pagerduty-webhook-auth secret is missing or enables no check")
+	debugAddr := pflag.String("debug-bind-address", "0",
+		"Address the unauthenticated expvar debug endpoint, with the PagerDuty client stats, is served on, 0 disables it")
+	enablePprof := pflag.Bool("enable-pprof", false,
+		"Serve the pprof profiles on --debug-bind-address too")
+	preflightOnly := pflag.Bool("preflight", false,
+		"Check the RBAC, PagerDuty API keys, webhook certificate and CRDs of the install, print a report and exit non-zero if a check failed")
+	auditOnly := pflag.Bool("audit", false,
+		"Compare every managed ClusterDeployment with its service in PagerDuty, print a report and exit")
+	auditFormat := pflag.String("audit-format", "json",
+		"Format of the --audit report, json or csv")
+
 	pflag.Parse()
 
 	// Use a zap logr.Logger implementation. If none of the zap
@@ -76,11 +146,18 @@ func main() {
 	// The logger instantiated here can be changed to any logger
 	// implementing the logr.Logger interface. This logger will
 	// be propagated through the whole operator, generating
-	// uniform and structured logs.
-	logf.SetLogger(zap.Logger())
+	// uniform and structured logs. Key material is scrubbed from every
+	// line.
+	logf.SetLogger(logging.NewRedactingLogger(zap.Logger()))
 
 	printVersion()
 
+	// Call the PagerDuty API through the proxy and trusting the CAs set
+	if err := pd.ConfigureTransport(); err != nil {
+		log.Error(err, "Failed to configure the PagerDuty HTTP client")
+		os.Exit(1)
+	}
+
 	// Get a config to talk to the apiserver
 	cfg, err := config.GetConfig()
 	if err != nil {
@@ -88,25 +165,68 @@ func main() {
 		os.Exit(1)
 	}
 
-	ctx := context.TODO()
-	// Become the leader before proceeding
-	err = leader.Become(ctx, "pagerduty-operator-lock")
-	if err != nil {
-		log.Error(err, "")
-		os.Exit(1)
+	// Check the install without starting the controllers
+	if *preflightOnly {
+		certDir := ""
+		if *enableWebhooks {
+			certDir = *webhookCertDir
+		}
+		os.Exit(runPreflight(cfg, certDir, *operatorNamespace))
+	}
+
+	// Report on the fleet without starting the controllers
+	if *auditOnly {
+		os.Exit(runAudit(cfg, *auditFormat))
 	}
 
-	// Create a new Cmd to provide shared dependencies and start components
+	// Create a new Cmd to provide shared dependencies and start components.
+	// Replicas wait to be elected before starting the controllers, so
+	// several can run with only one reconciling. A standby takes over once
+	// the lease of a stopped leader expires.
 	mgr, err := manager.New(cfg, manager.Options{
 		Namespace: "",
 		// disable the controller-runtime metrics
-		MetricsBindAddress: "0",
+		MetricsBindAddress:      "0",
+		LeaderElection:          *leaderElect,
+		LeaderElectionID:        leaderElectionID,
+		LeaderElectionNamespace: *operatorNamespace,
+		HealthProbeBindAddress:  *probeAddr,
+		Port:                    *webhookPort,
+		CertDir:                 *webhookCertDir,
 	})
 	if err != nil {
 		log.Error(err, "")
 		os.Exit(1)
 	}
 
+	if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		log.Error(err, "unable to set up health check")
+		os.Exit(1)
+	}
+	if err := mgr.AddReadyzCheck("ping", healthz.Ping); err != nil {
+		log.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+	// operatorAPIKey returns the PagerDuty API key of the operator
+	// namespace, "" if it can't be read
+	operatorAPIKey := func() string {
+		pdAPISecret := &corev1.Secret{}
+		err := mgr.GetClient().Get(context.TODO(), types.NamespacedName{Namespace: *operatorNamespace, Name: *apiSecretName}, pdAPISecret)
+		if err != nil {
+			log.Error(err, "Failed to get secret")
+			return ""
+		}
+		return string(pdAPISecret.Data[operatorconfig.PagerDutyAPISecretKey])
+	}
+
+	// a replica that can't get through to PagerDuty is silently broken
+	if *pdReadinessThreshold > 0 {
+		if err := mgr.AddReadyzCheck("pagerduty", pd.ReadinessCheck(*pdReadinessThreshold, operatorAPIKey)); err != nil {
+			log.Error(err, "unable to set up the PagerDuty ready check")
+			os.Exit(1)
+		}
+	}
+
 	log.Info("Registering Components.")
 
 	// Setup Scheme for all resources
@@ -120,18 +240,89 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := hiveintv1alpha1.AddToScheme(mgr.GetScheme()); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
 	if err := routev1.AddToScheme(mgr.GetScheme()); err != nil {
 		log.Error(err, "error registering prometheus monitoring objects")
 		os.Exit(1)
 	}
 
+	// Serve the conversion between the versions of PagerDutyIntegration on
+	// every replica, the API server calls whichever the Service picks
+	if *enableWebhooks {
+		if err := builder.WebhookManagedBy(mgr).For(&pagerdutyv1alpha1.PagerDutyIntegration{}).Complete(); err != nil {
+			log.Error(err, "unable to set up the conversion webhook")
+			os.Exit(1)
+		}
+
+		// and the validation of the self-service onboarding requests
+		mgr.GetWebhookServer().Register(selfservice.ValidatePath, &webhook.Admission{
+			Handler: &selfservice.Validator{Client: mgr.GetClient(), Reader: mgr.GetAPIReader()},
+		})
+
+		// and the validation of the PagerDutyIntegrations
+		mgr.GetWebhookServer().Register(validation.ValidatePath, &webhook.Admission{
+			Handler: &validation.Validator{OperatorNamespace: *operatorNamespace},
+		})
+	}
+
 	// Setup all Controllers
 	if err := controller.AddToManager(mgr); err != nil {
 		log.Error(err, "")
 		os.Exit(1)
 	}
 
-	metricsServer := metrics.NewBuilder(operatorconfig.OperatorNamespace, operatorconfig.OperatorName).
+	// Serve the heartbeat check-ins on every replica, clusters keep
+	// checking in while a new leader is elected
+	if *heartbeatAddr != "0" {
+		err = mgr.Add(&heartbeat.Server{
+			Addr:    *heartbeatAddr,
+			Handler: heartbeat.NewHandler(mgr.GetAPIReader(), mgr.GetClient()),
+		})
+		if err != nil {
+			log.Error(err, "unable to set up the heartbeat server")
+			os.Exit(1)
+		}
+	}
+
+	// Serve the debug endpoints on every replica too, each has its own
+	// clients and caches
+	if *debugAddr != "0" {
+		err = mgr.Add(&debug.Server{
+			Addr:   *debugAddr,
+			Pprof:  *enablePprof,
+			Reader: mgr.GetClient(),
+		})
+		if err != nil {
+			log.Error(err, "unable to set up the debug server")
+			os.Exit(1)
+		}
+	}
+
+	// Receive the incident webhooks on every replica too, PagerDuty
+	// delivers them to whichever the Service picks
+	if *incidentWebhookAddr != "0" {
+		auth, err := loadWebhookAuth(mgr.GetAPIReader(), *operatorNamespace, *incidentWebhookInsecure)
+		if err != nil {
+			log.Error(err, "unable to load the authentication of the incident webhook receiver")
+			os.Exit(1)
+		}
+		err = mgr.Add(&incidentwebhook.Server{
+			Addr:    *incidentWebhookAddr,
+			CertDir: *webhookCertDir,
+			Auth:    auth,
+			Handler: incidentwebhook.NewReceiver(mgr.GetClient()),
+		})
+		if err != nil {
+			log.Error(err, "unable to set up the incident webhook receiver")
+			os.Exit(1)
+		}
+	}
+
+	metricsServer := metrics.NewBuilder(*operatorNamespace, operatorconfig.OperatorName).
 		WithPort(metricsPort).
 		WithPath(metricsPath).
 		WithCollectors(localmetrics.MetricsList).
@@ -146,16 +337,8 @@ func main() {
 
 	// Add runnable custom metrics
 	err = mgr.Add(manager.RunnableFunc(func(s <-chan struct{}) error {
-		client := mgr.GetClient()
-		pdAPISecret := &corev1.Secret{}
-		err = client.Get(context.TODO(), types.NamespacedName{Namespace: operatorconfig.OperatorNamespace, Name: operatorconfig.PagerDutyAPISecretName}, pdAPISecret)
-		if err != nil {
-			log.Error(err, "Failed to get secret")
-			return err
-		}
-		var APIKey = string(pdAPISecret.Data[operatorconfig.PagerDutyAPISecretKey])
 		timer := prometheus.NewTimer(localmetrics.MetricPagerDutyHeartbeat)
-		localmetrics.UpdateAPIMetrics(APIKey, timer)
+		localmetrics.UpdateAPIMetrics(operatorAPIKey, timer)
 
 		<-s
 		return nil
@@ -173,3 +356,117 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runPreflight runs the preflight checks, prints their report as JSON and
+// returns the exit code of the operator
+func runPreflight(cfg *rest.Config, webhookCertDir, operatorNamespace string) int {
+	scheme := k8sruntime.NewScheme()
+	for _, addToScheme := range []func(*k8sruntime.Scheme) error{
+		clientgoscheme.AddToScheme,
+		apis.AddToScheme,
+		apiextensionsv1beta1.AddToScheme,
+	} {
+		if err := addToScheme(scheme); err != nil {
+			log.Error(err, "")
+			return 1
+		}
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		log.Error(err, "unable to create a client")
+		return 1
+	}
+
+	report := preflight.Run(preflight.Options{
+		Client: c,
+		PDClient: func(apiKey string, opts ...pd.ClientOption) pd.Client {
+			return pd.NewClient(apiKey, "preflight", opts...)
+		},
+		WebhookCertDir: webhookCertDir,
+		WebhookHost:    fmt.Sprintf("%s.%s.svc", operatorconfig.WebhookServiceName, operatorNamespace),
+	})
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Error(err, "")
+		return 1
+	}
+	fmt.Println(string(out))
+
+	if !report.Passed {
+		return 1
+	}
+	return 0
+}
+
+// runAudit prints the audit of the PagerDuty services of the fleet in the
+// given format and returns the exit code
+func runAudit(cfg *rest.Config, format string) int {
+	write := map[string]func(io.Writer, []fleetaudit.Entry) error{
+		"json": fleetaudit.WriteJSON,
+		"csv":  fleetaudit.WriteCSV,
+	}[format]
+	if write == nil {
+		log.Error(fmt.Errorf("unknown format %q, expected json or csv", format), "invalid --audit-format")
+		return 1
+	}
+
+	scheme := k8sruntime.NewScheme()
+	for _, addToScheme := range []func(*k8sruntime.Scheme) error{
+		clientgoscheme.AddToScheme,
+		apis.AddToScheme,
+		hivev1.AddToScheme,
+	} {
+		if err := addToScheme(scheme); err != nil {
+			log.Error(err, "")
+			return 1
+		}
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		log.Error(err, "unable to create a client")
+		return 1
+	}
+
+	entries, err := fleetaudit.Run(fleetaudit.Options{
+		Client: c,
+		PDClient: func(apiKey string, opts ...pd.ClientOption) pd.Client {
+			return pd.NewClient(apiKey, "audit", opts...)
+		},
+	})
+	if err != nil {
+		log.Error(err, "failed to audit the fleet")
+		return 1
+	}
+	if err := write(os.Stdout, entries); err != nil {
+		log.Error(err, "")
+		return 1
+	}
+	return 0
+}
+
+// loadWebhookAuth returns the authentication of the incident webhook
+// receiver the operator Secret configures. A missing Secret, or one enabling
+// no check, is an error unless insecure is set, since every webhook would be
+// accepted, which is only fine on a network PagerDuty alone can reach.
+func loadWebhookAuth(reader client.Reader, namespace string, insecure bool) (incidentwebhook.Auth, error) {
+	secret := &corev1.Secret{}
+	err := reader.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: operatorconfig.WebhookAuthSecretName}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return incidentwebhook.Auth{}, err
+	}
+
+	auth := incidentwebhook.Auth{}
+	if err == nil {
+		auth, err = incidentwebhook.AuthFromSecret(secret)
+		if err != nil {
+			return incidentwebhook.Auth{}, err
+		}
+	}
+	if auth.Disabled() {
+		if !insecure {
+			return incidentwebhook.Auth{}, fmt.Errorf("secret %s/%s enables no authentication of the incident webhooks, configure it or set --incident-webhook-insecure", namespace, operatorconfig.WebhookAuthSecretName)
+		}
+		log.Info("Incident webhooks are not authenticated, every request is accepted", "Namespace", namespace, "Name", operatorconfig.WebhookAuthSecretName)
+	}
+	return auth, nil
+}
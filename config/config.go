@@ -14,21 +14,210 @@
 
 package config
 
+import (
+	"os"
+	"time"
+)
+
+const (
+	// ResyncPeriod is how often the PagerDuty service of each cluster is
+	// verified when nothing changed
+	ResyncPeriod time.Duration = 10 * time.Hour
+
+	// ResyncSpreadPerCluster is how much the resync window grows for each
+	// cluster a PagerDutyIntegration selects, so verifications of a large
+	// fleet are spread over up to ResyncPeriod instead of all starting at once
+	ResyncSpreadPerCluster time.Duration = 10 * time.Second
+
+	// HibernationWindowDuration is how long the PagerDuty maintenance window
+	// of a hibernating clusterdeployment lasts
+	HibernationWindowDuration time.Duration = 7 * 24 * time.Hour
+
+	// HibernationWindowRenewBefore is how long before the maintenance window
+	// of a clusterdeployment still hibernating ends a new one is created
+	HibernationWindowRenewBefore time.Duration = 24 * time.Hour
+
+	// OnboardingFastPathWindow is how long after its managed label turned
+	// true a clusterdeployment is set up ahead of the rest of the fleet
+	OnboardingFastPathWindow time.Duration = 30 * time.Minute
+
+	// ResyncMinInterval is the shortest time between two resyncs of a
+	// PagerDutyIntegration. Each resync verifies all clusters whose slot
+	// passed since the previous one
+	ResyncMinInterval time.Duration = 5 * time.Minute
+
+	// DeprovisioningEventRuleDefaultDuration is how long the rule suppressing
+	// the events of a cluster being deprovisioned stays active when the
+	// pagerdutyintegration does not set a duration
+	DeprovisioningEventRuleDefaultDuration time.Duration = 2 * time.Hour
+
+	// AuditPollMinInterval is the shortest time between two polls of the
+	// PagerDuty audit records, so the poller stays slow
+	AuditPollMinInterval time.Duration = 15 * time.Minute
+
+	// MaxConcurrentClusterSyncsDefault is how many clusters of a
+	// pagerdutyintegration are set up at once unless
+	// --max-concurrent-cluster-syncs says otherwise
+	MaxConcurrentClusterSyncsDefault int = 10
+
+	// DecommissionDefaultBatchSize is how many services are disabled or
+	// deleted per reconcile when decommissioning an account
+	DecommissionDefaultBatchSize int = 20
+
+	// DecommissionBatchInterval is the time between two batches when
+	// decommissioning an account
+	DecommissionBatchInterval time.Duration = time.Minute
+
+	// TestAlertMinInterval is the shortest time between two synthetic test
+	// alerts of a cluster, so the test alerts don't flood PagerDuty
+	TestAlertMinInterval time.Duration = time.Hour
+
+	// HeartbeatMinInterval is the shortest interval clusters can be
+	// expected to check in at, the resync period of a missed check-in
+	HeartbeatMinInterval time.Duration = 5 * time.Minute
+
+	// AlertVolumeMinWindow is the shortest period the incidents of the
+	// clusters are counted over, so the analytics are polled slowly
+	AlertVolumeMinWindow time.Duration = 6 * time.Hour
+
+	// AlertVolumeDefaultWindow is the period the incidents of the clusters
+	// are counted over when the pagerdutyintegration does not set one
+	AlertVolumeDefaultWindow time.Duration = 24 * time.Hour
+
+	// AlertVolumeDefaultDeviationFactor is how many times the fleet median
+	// incident count a cluster must reach to be flagged when the
+	// pagerdutyintegration does not set it
+	AlertVolumeDefaultDeviationFactor int = 5
+
+	// ServiceTuningMinWindow is the shortest period the incidents of the
+	// clusters are listed over to suggest how to tune their services, as
+	// each listing pages through all the incidents of the fleet
+	ServiceTuningMinWindow time.Duration = 6 * time.Hour
+
+	// ServiceTuningDefaultWindow is the period the incidents of the clusters
+	// are listed over when the pagerdutyintegration does not set one
+	ServiceTuningDefaultWindow time.Duration = 7 * 24 * time.Hour
+
+	// ServiceTuningDefaultMinIncidents is how many incidents a service must
+	// get over the window to be suggested anything when the
+	// pagerdutyintegration does not set it
+	ServiceTuningDefaultMinIncidents int = 10
+
+	// ServiceTuningDuplicateRatio is the share of the incidents of a service
+	// repeating an earlier one from which alert grouping is suggested
+	ServiceTuningDuplicateRatio float64 = 0.5
+
+	// ServiceTuningAutoResolvedRatio is the share of the incidents of a
+	// service resolving on their own from which pausing transient alerts is
+	// suggested
+	ServiceTuningAutoResolvedRatio float64 = 0.8
+
+	// OrphanedServiceSweepMinInterval is the shortest time between two sweeps
+	// of the PagerDuty account for orphaned services, as each lists all the
+	// services of the servicePrefix
+	OrphanedServiceSweepMinInterval time.Duration = time.Hour
+
+	// OrphanedServiceSweepDefaultInterval is the time between two sweeps of
+	// the PagerDuty account for orphaned services when the
+	// pagerdutyintegration does not set one
+	OrphanedServiceSweepDefaultInterval time.Duration = 24 * time.Hour
+
+	// ErrorBudgetMinWindow is the shortest period the per-cluster operations
+	// are accounted over
+	ErrorBudgetMinWindow time.Duration = time.Hour
+
+	// ErrorBudgetDefaultWindow is the period the per-cluster operations are
+	// accounted over when the pagerdutyintegration does not set one
+	ErrorBudgetDefaultWindow time.Duration = 7 * 24 * time.Hour
+
+	// ErrorBudgetDefaultObjective is the percentage of the per-cluster
+	// operations that must succeed when the pagerdutyintegration does not
+	// set one
+	ErrorBudgetDefaultObjective float64 = 99
+
+	// ErrorBudgetBuckets is how many periods the window of the error budget
+	// is split into, the window rolling forward one period at a time
+	ErrorBudgetBuckets int = 24
+
+	// CoverageReportMinInterval is the shortest time between two coverage
+	// reports
+	CoverageReportMinInterval time.Duration = time.Hour
+
+	// CoverageReportDefaultInterval is the time between two coverage reports
+	// when the pagerdutyintegration does not set one
+	CoverageReportDefaultInterval time.Duration = 24 * time.Hour
+
+	// CoverageReportWebhookTimeout is how long posting a coverage report to
+	// its webhook may take
+	CoverageReportWebhookTimeout time.Duration = 30 * time.Second
+)
+
 const (
-	OperatorConfigMapName  string = "pagerduty-config"
-	OperatorName           string = "pagerduty-operator"
-	OperatorNamespace      string = "pagerduty-operator"
-	PagerDutyAPISecretName string = "pagerduty-api-key"
-	PagerDutyAPISecretKey  string = "PAGERDUTY_API_KEY"
-	PagerDutySecretKey     string = "PAGERDUTY_KEY"
+	OperatorConfigMapName string = "pagerduty-config"
+	OperatorName          string = "pagerduty-operator"
+	// OperatorNamespace is the namespace the operator runs in when
+	// OperatorNamespaceEnvVar is not set, use GetOperatorNamespace instead
+	OperatorNamespace string = "pagerduty-operator"
+	// OperatorNamespaceEnvVar is the environment variable, set from the
+	// downward API, holding the namespace the operator runs in
+	OperatorNamespaceEnvVar string = "OPERATOR_NAMESPACE"
+	// PagerDutyProxyEnvVar and PagerDutyCABundleEnvVar are the environment
+	// variables defaulting --pagerduty-proxy and --pagerduty-ca-bundle
+	PagerDutyProxyEnvVar    string = "PAGERDUTY_PROXY"
+	PagerDutyCABundleEnvVar string = "PAGERDUTY_CA_BUNDLE"
+	PagerDutyAPISecretName  string = "pagerduty-api-key"
+	PagerDutyAPISecretKey   string = "PAGERDUTY_API_KEY"
+	PagerDutySecretKey      string = "PAGERDUTY_KEY"
+	// WebhookServiceName is the Service the API server calls the webhook of
+	// the operator through
+	WebhookServiceName string = "pagerduty-operator-webhook"
+	// PagerDutyMigrationSecretKey is the default key of the synced secret
+	// holding the integration key of the account being migrated to
+	PagerDutyMigrationSecretKey string = "PAGERDUTY_KEY_MIGRATION"
+	// PagerDutyHeartbeatSecretKey is the default key of the synced secret
+	// holding the URL the cluster checks in at
+	PagerDutyHeartbeatSecretKey string = "PAGERDUTY_HEARTBEAT_URL"
+	// WebhookAuthSecretName is the secret in the operator namespace
+	// configuring the authentication of the incident webhook receiver
+	WebhookAuthSecretName string = "pagerduty-webhook-auth"
+	// WebhookSigningSecretsKey holds the signing secrets of the PagerDuty
+	// webhook subscriptions, one per line
+	WebhookSigningSecretsKey string = "signing-secrets"
+	// WebhookClientCAKey holds the PEM bundle of the CAs verifying the
+	// client certificates of mTLS
+	WebhookClientCAKey string = "ca.crt"
+	// ActiveIncidentsAnnotation is set by the incident webhook receiver on
+	// the PagerDutyServices and ClusterDeployments to their number of
+	// triggered or acknowledged incidents
+	ActiveIncidentsAnnotation string = "pd.managed.openshift.io/active-incidents"
+	// ActiveIncidentIDsAnnotation lists the IDs of the active incidents of a
+	// PagerDutyService, so a webhook delivered twice is only counted once
+	ActiveIncidentIDsAnnotation string = "pd.managed.openshift.io/active-incident-ids"
+	// LastIncidentURLAnnotation is set by the incident webhook receiver on
+	// the PagerDutyServices and ClusterDeployments to the URL of their last
+	// triggered incident
+	LastIncidentURLAnnotation string = "pd.managed.openshift.io/last-incident-url"
 	// PagerDutyFinalizerPrefix prefix used for finalizers on resources other than PDI
 	PagerDutyFinalizerPrefix string = "pd.managed.openshift.io/"
 	// PagerDutyIntegrationFinalizer name of finalizer used for PDI
 	PagerDutyIntegrationFinalizer string = "pd.managed.openshift.io/pagerduty"
+	// PagerDutySilenceFinalizer name of finalizer used for PagerDutySilence
+	PagerDutySilenceFinalizer string = "pd.managed.openshift.io/silence"
+	// PagerDutyRulesetFinalizer name of finalizer used for PagerDutyRuleset
+	PagerDutyRulesetFinalizer string = "pd.managed.openshift.io/ruleset"
+	// PagerDutyRulesetRollbackAnnotation can be set to "true" on a
+	// pagerdutyruleset to restore the rules applied before the last change
+	PagerDutyRulesetRollbackAnnotation string = "pd.managed.openshift.io/rollback"
+	// ReconcilePausedUntilAnnotation can be set on the operator Deployment
+	// to an RFC3339 time to pause the reconciles of every controller until
+	// then, for example while Hive is upgraded
+	ReconcilePausedUntilAnnotation string = "pd.managed.openshift.io/pause-reconcile-until"
+	// DryRunAnnotation can be set to "true" on a pagerdutyintegration,
+	// pagerdutysilence or pagerdutyruleset to log the changes its reconciles
+	// would make instead of making them, as the --dry-run flag does for all
+	DryRunAnnotation string = "pd.managed.openshift.io/dry-run"
 	// LegacyPagerDutyFinalizer name of legacy finalizer, always to be deleted
 	LegacyPagerDutyFinalizer string = "pd.managed.openshift.io/pagerduty"
-	SecretSuffix             string = "-pd-secret"
-	ConfigMapSuffix          string = "-pd-config"
 
 	// PagerDutyUrgencyRule is the type of IncidentUrgencyRule for new incidents
 	// coming into the Service. This is for the creation of NEW SERVICES ONLY
@@ -41,11 +230,134 @@ const (
 	// ClusterDeploymentManagedLabel is the label the clusterdeployment will have that determines
 	// if the cluster is OSD (managed) or not
 	ClusterDeploymentManagedLabel string = "api.openshift.com/managed"
+
+	// ClusterDeploymentNoalertsLabel is the label set to "true" on a
+	// clusterdeployment whose alerts are intentionally muted
+	ClusterDeploymentNoalertsLabel string = "api.openshift.com/noalerts"
+
+	// ClusterDeploymentNoalertsSinceAnnotation records when the operator first
+	// saw the noalerts label on a clusterdeployment, in RFC3339 format
+	ClusterDeploymentNoalertsSinceAnnotation string = "pd.managed.openshift.io/noalerts-since"
+
+	// ClusterDeploymentSilencedAnnotation is the annotation set to "true" on
+	// a clusterdeployment, such as one in limited support, whose PagerDuty
+	// service is disabled for as long as it is set
+	ClusterDeploymentSilencedAnnotation string = "pd.managed.openshift.io/silenced"
+
+	// ClusterDeploymentResolveTimeoutAnnotation and
+	// ClusterDeploymentAcknowledgeTimeoutAnnotation can be set on a
+	// clusterdeployment to a number of seconds overriding the resolveTimeout
+	// and acknowledgeTimeout of the pagerdutyintegration for its service, 0
+	// disabling the timeout
+	ClusterDeploymentResolveTimeoutAnnotation     string = "pd.managed.openshift.io/resolve-timeout"
+	ClusterDeploymentAcknowledgeTimeoutAnnotation string = "pd.managed.openshift.io/acknowledge-timeout"
+
+	// ClusterDeploymentIntegrationAnnotation can be set on a
+	// clusterdeployment to the name of a pagerdutyintegration with
+	// selfServiceOnboarding to opt into it, whatever its labels
+	ClusterDeploymentIntegrationAnnotation string = "pd.managed.openshift.io/integration"
+
+	// PagerDutyIntegrationLabel is set on the ConfigMaps, Secrets and SyncSets
+	// created for a clusterdeployment to the name of the pagerdutyintegration
+	// that manages them
+	PagerDutyIntegrationLabel string = "pd.managed.openshift.io/pagerdutyintegration"
+
+	// PagerDutyIntegrationClearQuotaAnnotation can be set on a
+	// pagerdutyintegration to clear its AccountQuotaExceeded condition once
+	// the account's service limit was raised, so service creation resumes
+	PagerDutyIntegrationClearQuotaAnnotation string = "pd.managed.openshift.io/clear-account-quota-exceeded"
+
+	// PagerDutyIntegrationClearLeakedServiceAnnotation can be set on a
+	// pagerdutyintegration to clear its LeakedService condition once the
+	// leaked service was deleted by hand
+	PagerDutyIntegrationClearLeakedServiceAnnotation string = "pd.managed.openshift.io/clear-leaked-service"
+
+	// TenantNamespaceLabel can be set on a namespace to the namespace of the
+	// pagerdutyintegrations, outside of the operator namespace, allowed to
+	// set up the clusterdeployments it holds
+	TenantNamespaceLabel string = "pd.managed.openshift.io/tenant"
+
+	// HibernationWindowIDKey is the key of the ConfigMap of a
+	// clusterdeployment holding the ID of the maintenance window created
+	// while it hibernates, and HibernationWindowEndKey its end time
+	HibernationWindowIDKey  string = "HIBERNATION_WINDOW_ID"
+	HibernationWindowEndKey string = "HIBERNATION_WINDOW_END"
+
+	// HeartbeatIntegrationIDKey is the key of the ConfigMap of a
+	// clusterdeployment holding the ID of the heartbeat integration of its
+	// service
+	HeartbeatIntegrationIDKey string = "HEARTBEAT_INTEGRATION_ID"
+
+	// HeartbeatTokenAnnotation is set on the Lease recording the check-ins
+	// of a clusterdeployment to the token of its check-in URL
+	HeartbeatTokenAnnotation string = "pd.managed.openshift.io/heartbeat-token"
+
+	// RotateIntegrationKeyAnnotation can be set on a clusterdeployment, or on
+	// a pagerdutyintegration for all of its clusters, to an RFC 3339 time to
+	// replace the integration keys issued before it, as when a key leaked
+	RotateIntegrationKeyAnnotation string = "pd.openshift.io/rotate-integration-key"
+
+	// IntegrationKeyRotatedAtKey is the key of the ConfigMap of a
+	// clusterdeployment holding when its integration key was last rotated
+	IntegrationKeyRotatedAtKey string = "INTEGRATION_KEY_ROTATED_AT"
+
+	// ServiceDisabledKey is the key of the ConfigMap of a clusterdeployment
+	// set to "true" while its service is disabled for the silenced
+	// annotation
+	ServiceDisabledKey string = "SERVICE_DISABLED"
+
+	// DecommissionAnnotation is set on the ConfigMap of a clusterdeployment
+	// to "disabled" or "deleted" as its service is decommissioned after an
+	// account migration
+	DecommissionAnnotation string = "pd.managed.openshift.io/decommission"
+
+	// DeliveryProbeResultLabel is set by the delivery probe on its own
+	// cronjob in the target cluster to the result of its last run
+	DeliveryProbeResultLabel string = "pd.managed.openshift.io/probe-result"
+
+	// DeliveryProbeDefaultSchedule is the schedule of the delivery probe when
+	// the pagerdutyintegration does not set one
+	DeliveryProbeDefaultSchedule string = "0 */6 * * *"
+
+	// AlertmanagerConfigKey is the key of the Alertmanager configuration in
+	// the object synced to the target cluster
+	AlertmanagerConfigKey string = "alertmanager.yaml"
+
+	// CoverageReportKey is the key of the coverage report in its ConfigMap
+	CoverageReportKey string = "report.json"
+
+	// AlertmanagerDefaultReceiver is the name of the receiver of the
+	// Alertmanager configuration when the pagerdutyintegration does not set one
+	AlertmanagerDefaultReceiver string = "pagerduty"
+
+	// AlertmanagerHeartbeatReceiver is the name of the receiver of the
+	// Alertmanager configuration checking in to the heartbeat of the cluster
+	AlertmanagerHeartbeatReceiver string = "pagerduty-operator-heartbeat"
+
+	// AlertmanagerHeartbeatAlert is the always firing alert whose
+	// notifications check in to the heartbeat of the cluster
+	AlertmanagerHeartbeatAlert string = "Watchdog"
+
+	// DeprovisioningEventRuleDefaultDetail is the custom detail of the events
+	// holding the cluster ID when the pagerdutyintegration does not set one
+	DeprovisioningEventRuleDefaultDetail string = "cluster_id"
+
+	// EventRuleDefaultClusterIDDetail is the custom detail of the events
+	// holding the cluster ID when the pagerdutyruleset does not set one
+	EventRuleDefaultClusterIDDetail string = "cluster_id"
+
+	// ServiceTagCostCenter, ServiceTagOwner and ServiceTagEnvironment are the
+	// keys of the ownership tags set on PagerDuty services
+	ServiceTagCostCenter  string = "cost-center"
+	ServiceTagOwner       string = "owner"
+	ServiceTagEnvironment string = "environment"
 )
 
-// Name is used to generate the name of secondary resources (SyncSets,
-// Secrets, ConfigMaps) for a ClusterDeployment that are created by
-// the PagerDutyIntegration controller.
-func Name(servicePrefix, clusterDeploymentName, suffix string) string {
-	return servicePrefix + "-" + clusterDeploymentName + suffix
+// GetOperatorNamespace returns the namespace the operator runs in, read from
+// OperatorNamespaceEnvVar and defaulting to OperatorNamespace.
+func GetOperatorNamespace() string {
+	if ns := os.Getenv(OperatorNamespaceEnvVar); ns != "" {
+		return ns
+	}
+	return OperatorNamespace
 }
@@ -7,6 +7,7 @@ require (
 	github.com/go-logr/logr v0.2.1
 	github.com/go-openapi/spec v0.19.4
 	github.com/golang/mock v1.4.4
+	github.com/google/gofuzz v1.1.0
 	github.com/openshift/api v3.9.1-0.20191111211345-a27ff30ebf09+incompatible
 	github.com/openshift/hive v1.0.16-0.20201211144432-f97557354336
 	github.com/openshift/operator-custom-metrics v0.3.1-0.20200901174648-463079905232
@@ -16,6 +17,7 @@ require (
 	github.com/stretchr/testify v1.6.1
 	gotest.tools v2.2.0+incompatible
 	k8s.io/api v0.19.0
+	k8s.io/apiextensions-apiserver v0.19.0
 	k8s.io/apimachinery v0.19.0
 	k8s.io/client-go v12.0.0+incompatible
 	k8s.io/kube-openapi v0.0.0-20200805222855-6aeccd4b50c6
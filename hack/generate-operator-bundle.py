@@ -125,7 +125,7 @@ if __name__ == '__main__':
                         print('Adding Deployment to CSV: {}'.format(file_path))
                         csv['spec']['install']['spec']['deployments'][0]['spec'] = obj['spec']
                         csv['spec']['install']['spec']['deployments'][0]['name'] = operator_name
-                    if obj['kind'] == 'ClusterRole' or obj['kind'] == 'Role' or obj['kind'] == 'RoleBinding' or obj['kind'] == 'ClusterRoleBinding':
+                    if obj['kind'] == 'ClusterRole' or obj['kind'] == 'Role' or obj['kind'] == 'RoleBinding' or obj['kind'] == 'ClusterRoleBinding' or obj['kind'] == 'Service':
                         if obj['kind'] in ('RoleBinding', 'ClusterRoleBinding'):
                             try:
                                 print(obj['roleRef']['kind'])
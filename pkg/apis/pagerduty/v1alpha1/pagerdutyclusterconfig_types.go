@@ -0,0 +1,67 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PagerDutyClusterConfigSpec overrides, for a single cluster, settings of
+// the PagerDutyIntegrations selecting it. Fields left empty keep the
+// setting of the PagerDutyIntegration.
+// +k8s:openapi-gen=true
+type PagerDutyClusterConfigSpec struct {
+	// Reference to the ClusterDeployment, in the same namespace as the
+	// PagerDutyClusterConfig, whose PagerDuty services are configured.
+	ClusterDeploymentRef corev1.LocalObjectReference `json:"clusterDeploymentRef"`
+
+	// Name of the PagerDutyIntegration whose settings are overridden.
+	// Omitting this field overrides those of every PagerDutyIntegration
+	// selecting the cluster.
+	// +optional
+	PagerDutyIntegration string `json:"pagerDutyIntegration,omitempty"`
+
+	// ID of the escalation policy of the cluster's PagerDuty service,
+	// instead of the one of the PagerDutyIntegration.
+	// +optional
+	EscalationPolicy string `json:"escalationPolicy,omitempty"`
+
+	// Urgency of the incidents of the cluster's PagerDuty service, instead
+	// of the incidentUrgency of the PagerDutyIntegration.
+	// +optional
+	IncidentUrgency *IncidentUrgency `json:"incidentUrgency,omitempty"`
+
+	// Disable the cluster's PagerDuty service, so the cluster doesn't
+	// page, until this field is unset or the PagerDutyClusterConfig is
+	// deleted.
+	// +optional
+	DisablePaging bool `json:"disablePaging,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PagerDutyClusterConfig overrides the PagerDuty settings of a single
+// cluster
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=pagerdutyclusterconfigs,shortName=pdcc,scope=Namespaced
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterDeploymentRef.name"
+// +kubebuilder:printcolumn:name="Integration",type="string",JSONPath=".spec.pagerDutyIntegration"
+// +kubebuilder:printcolumn:name="Paging Disabled",type="boolean",JSONPath=".spec.disablePaging"
+type PagerDutyClusterConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PagerDutyClusterConfigSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PagerDutyClusterConfigList contains a list of PagerDutyClusterConfig
+type PagerDutyClusterConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PagerDutyClusterConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PagerDutyClusterConfig{}, &PagerDutyClusterConfigList{})
+}
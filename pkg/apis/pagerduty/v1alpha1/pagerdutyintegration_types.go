@@ -17,29 +17,1237 @@ type PagerDutyIntegrationSpec struct {
 	// ID of an existing Escalation Policy in PagerDuty.
 	EscalationPolicy string `json:"escalationPolicy"`
 
+	// Escalation policies picked by the value of a label of each selected
+	// ClusterDeployment, such as tier=gold or tier=silver, so one
+	// PagerDutyIntegration serves every tier. Clusters without the label,
+	// or whose value is not mapped, get escalationPolicy. Services whose
+	// policy no longer matches the label are set to the mapped one when
+	// verified. Omitting this field gives every cluster escalationPolicy.
+	EscalationPolicyMapping *EscalationPolicyMapping `json:"escalationPolicyMapping,omitempty"`
+
+	// ID or name of the PagerDuty team the service of each cluster is
+	// assigned to, so team-scoped PagerDuty users see it. A service the
+	// team is removed from is assigned to it again when verified, other
+	// teams of the service are left alone. Omitting this field leaves the
+	// teams of services alone.
+	Team string `json:"team,omitempty"`
+
 	// Time in seconds that an incident is automatically resolved if left
 	// open for that long. Value must not be negative. Omitting or setting
 	// this field to 0 will disable the feature.
 	// +kubebuilder:validation:Minimum=0
 	ResolveTimeout uint `json:"resolveTimeout,omitempty"`
 
+	// PagerDutyIntegrationTemplate, in the namespace of this
+	// PagerDutyIntegration, whose settings are inherited. A field set on
+	// this PagerDutyIntegration overrides the one of the template as a
+	// whole, a timeout of 0 inherits the one of the template. Omitting
+	// this field inherits nothing.
+	TemplateRef *corev1.LocalObjectReference `json:"templateRef,omitempty"`
+
+	// Urgency of the incidents of the PagerDuty service of each cluster,
+	// optionally depending on support hours. Services whose urgency
+	// drifted are set back when verified. Omitting this field makes the
+	// urgency follow the severity of the incidents.
+	IncidentUrgency *IncidentUrgency `json:"incidentUrgency,omitempty"`
+
+	// How the alerts of the PagerDuty service of each cluster are grouped
+	// into incidents, so a noisy cluster doesn't open an incident per
+	// alert. Services whose grouping drifted are set back when verified.
+	// Omitting this field leaves the grouping of the services alone.
+	AlertGrouping *AlertGrouping `json:"alertGrouping,omitempty"`
+
 	// Prefix to set on the PagerDuty Service name.
 	ServicePrefix string `json:"servicePrefix"`
 
+	// Normalize the names of the PagerDuty services: lower case, with any
+	// run of characters other than ASCII letters, digits, '-' and '.'
+	// replaced with a '-', and names longer than PagerDuty accepts
+	// truncated and suffixed with a hash of the full name. Existing
+	// services still named as before are renamed when verified.
+	NormalizeServiceNames bool `json:"normalizeServiceNames,omitempty"`
+
+	// Go template naming the PagerDuty service of each cluster instead of
+	// <servicePrefix>-<cluster name>.<base domain>-hive-cluster, such as
+	// "{{.Prefix}}-{{.ClusterID}}-{{.Labels.environment}}". It is
+	// executed with .Prefix, .ClusterID, .BaseDomain and the .Labels of the
+	// ClusterDeployment. Clusters missing a label the template refers to
+	// aren't set up. Existing services still named as before are renamed
+	// when verified.
+	ServiceNameTemplate string `json:"serviceNameTemplate,omitempty"`
+
+	// Type of the integration created on the PagerDuty service of each
+	// cluster, whose key is synced to the cluster, for receivers that need
+	// one other than Events API v2: "events_api_v2", the default,
+	// "prometheus", or "generic_events_api" for the Events API v1. A change
+	// applies to the integrations created from then on, rotating the keys
+	// switches existing clusters.
+	// +kubebuilder:validation:Enum=events_api_v2;prometheus;generic_events_api
+	IntegrationType IntegrationType `json:"integrationType,omitempty"`
+
 	// Reference to the secret containing PAGERDUTY_API_KEY.
 	PagerdutyApiKeySecretRef corev1.SecretReference `json:"pagerdutyApiKeySecretRef"`
 
+	// URL of the PagerDuty REST API of the account, such as
+	// https://api.eu.pagerduty.com for an account in the EU service region,
+	// or of a staging or mock API. Defaults to https://api.pagerduty.com.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	APIEndpoint string `json:"apiEndpoint,omitempty"`
+
 	// A label selector used to find which clusterdeployment CRs receive a
 	// PD integration based on this configuration.
 	ClusterDeploymentSelector metav1.LabelSelector `json:"clusterDeploymentSelector"`
 
+	// Lets ClusterDeployments opt into this PagerDutyIntegration, whatever
+	// their labels, by setting the pd.managed.openshift.io/integration
+	// annotation to its name, so teams can request paging without a change
+	// of the labels by the hub admins. Omitting this field ignores the
+	// annotation.
+	SelfServiceOnboarding *SelfServiceOnboarding `json:"selfServiceOnboarding,omitempty"`
+
+	// Name and namespace in the target cluster where the secret is synced.
+	TargetSecretRef corev1.SecretReference `json:"targetSecretRef"`
+
+	// More names and namespaces in the target cluster the secret is synced
+	// to by the same SyncSet, such as a customer namespace or the secret of
+	// user-workload monitoring. They are delivered like TargetSecretRef.
+	AdditionalTargetSecretRefs []corev1.SecretReference `json:"additionalTargetSecretRefs,omitempty"`
+
+	// How the integration key is delivered to TargetSecretRef. "Secret",
+	// the default, syncs a standalone secret. "Patch" merges the key into
+	// an existing secret, for clusters where monitoring config is a single
+	// aggregated secret.
+	// +kubebuilder:validation:Enum=Secret;Patch
+	SecretDeliveryMode SecretDeliveryMode `json:"secretDeliveryMode,omitempty"`
+
+	// Type of the secret synced to TargetSecretRef, Opaque by default.
+	// Ignored in Patch mode, where the secret already exists.
+	SecretType corev1.SecretType `json:"secretType,omitempty"`
+
+	// Make the secret synced to TargetSecretRef immutable. An immutable
+	// secret cannot be updated, so it is named after TargetSecretRef with a
+	// hash of the integration key appended, and a new key is delivered in
+	// a new secret replacing the previous one. Ignored in Patch mode.
+	ImmutableSecret bool `json:"immutableSecret,omitempty"`
+
+	// Longest time a selected cluster may stay muted, by a PagerDutySilence
+	// or the noalerts label. Once exceeded the silence is considered stale
+	// and alerting is re-enabled. Omitting this field disables the feature.
+	MaxSilenceDuration *metav1.Duration `json:"maxSilenceDuration,omitempty"`
+
+	// Sync a CronJob to each cluster that checks the integration key was
+	// delivered and events.pagerduty.com is reachable, and report its
+	// result in the DeliveryVerificationFailed condition of the cluster.
+	// Omitting this field disables the probe.
+	DeliveryProbe *DeliveryProbe `json:"deliveryProbe,omitempty"`
+
+	// Sync to each cluster an Alertmanager configuration with a receiver
+	// sending all alerts to the cluster's PagerDuty service, so the
+	// in-cluster Alertmanager is wired to it without manual configuration.
+	// Omitting this field only syncs the integration key.
+	AlertmanagerConfig *AlertmanagerConfig `json:"alertmanagerConfig,omitempty"`
+
+	// Deliver the integration key of a single PagerDuty service to every
+	// selected cluster through one Hive SelectorSyncSet matching
+	// ClusterDeploymentSelector, instead of creating a service and a
+	// SyncSet per cluster. Clusters set up on their own are torn down, and
+	// the per-cluster features such as silences and verification don't
+	// apply. Omitting this field sets up each cluster on its own.
+	SharedIntegrationKey *SharedIntegrationKey `json:"sharedIntegrationKey,omitempty"`
+
+	// Add a rule to a PagerDuty global ruleset suppressing the events of a
+	// cluster once its ClusterDeployment is deleted, so the alerts raised
+	// while it tears itself down page nobody. Omitting this field disables
+	// the rule.
+	DeprovisioningEventRule *DeprovisioningEventRule `json:"deprovisioningEventRule,omitempty"`
+
+	// Ownership tags set on the PagerDuty service of each cluster and
+	// reconciled on every resync, so PagerDuty reporting can be sliced by
+	// ownership. Omitting this field leaves the tags of services alone.
+	ServiceTags *ServiceTags `json:"serviceTags,omitempty"`
+
+	// PagerDuty services the service of each cluster is related to in the
+	// service graph of PagerDuty, registered as service dependencies and
+	// reconciled on every resync, so responders see which clusters an
+	// incident of the hub affects. Dependencies removed from this field are
+	// left in PagerDuty. Omitting this field registers none.
+	ServiceDependencies *ServiceDependencies `json:"serviceDependencies,omitempty"`
+
+	// PagerDuty service sent a change event whenever the settings of a
+	// cluster's service are found to have drifted from this
+	// PagerDutyIntegration and are repaired. Omitting this field still
+	// repairs drift, without reporting it.
+	FleetHygieneService *FleetHygieneService `json:"fleetHygieneService,omitempty"`
+
+	// How often the PagerDuty audit records are polled for changes made to
+	// the services of the selected clusters outside of the operator, such as
+	// a service disabled by hand. Each change is reported as a Warning event
+	// on this PagerDutyIntegration. Values below 15 minutes are raised to 15
+	// minutes. Omitting this field disables the poller.
+	AuditPollInterval *metav1.Duration `json:"auditPollInterval,omitempty"`
+
+	// How often a synthetic test alert is triggered and resolved right away
+	// through the integration of each selected cluster, with the result
+	// recorded in the testAlert of the cluster's status as evidence of
+	// paging coverage. Values below 1 hour are raised to 1 hour. Omitting
+	// this field disables test alerts.
+	TestAlertInterval *metav1.Duration `json:"testAlertInterval,omitempty"`
+
+	// How long the PagerDuty service and integration key of a deleted
+	// cluster are kept, so a cluster reinstalled with the same
+	// ClusterDeployment namespace, name and cluster name reuses them and
+	// keeps its incident history. Services not reused in time are deleted.
+	// Omitting this field deletes the service along with the cluster.
+	ReinstallServiceRetention *metav1.Duration `json:"reinstallServiceRetention,omitempty"`
+
+	// How long the PagerDuty service of a deleted cluster is kept disabled
+	// before it is deleted, so the accidental deletion of a
+	// ClusterDeployment doesn't wipe its incident history right away. The
+	// service is recorded in status.retainedServices meanwhile. Omitting
+	// this field deletes the service along with the cluster.
+	DeprovisionGracePeriod *metav1.Duration `json:"deprovisionGracePeriod,omitempty"`
+
+	// What is done with the PagerDuty service of a deleted cluster: Delete
+	// deletes it, once reinstallServiceRetention and deprovisionGracePeriod
+	// are over if set, Retain leaves it as is and Disable disables it, both
+	// keeping its incident history in PagerDuty. Defaults to Delete.
+	// +kubebuilder:validation:Enum=Delete;Retain;Disable
+	ServiceDeletionPolicy ServiceDeletionPolicy `json:"serviceDeletionPolicy,omitempty"`
+
+	// PagerDuty account the clusters are being migrated to. While set, each
+	// selected cluster also gets a service in that account, and its
+	// integration key is synced to TargetSecretRef next to the one of the
+	// current account, so alerting can switch accounts without a gap.
+	// Omitting this field uses the current account only.
+	AccountMigration *AccountMigration `json:"accountMigration,omitempty"`
+
+	// Secret on the hub a copy of the integration key of every selected
+	// cluster is written to, under the key <namespace>.<name> of the
+	// cluster's ClusterDeployment, so SREs can still retrieve a key when
+	// Hive sync is broken, without PagerDuty API access. Omitting this field
+	// keeps no copy.
+	EscrowSecretRef *corev1.SecretReference `json:"escrowSecretRef,omitempty"`
+
+	// Further PagerDuty services set up for the clusters each one selects,
+	// next to the service of this PagerDutyIntegration, for example one
+	// paging the customer in addition to SRE. Each service is tracked in its
+	// own ConfigMap, Secret and SyncSet and is deleted with the cluster.
+	// Only the timeouts, team, incidentUrgency, alertGrouping,
+	// normalizeServiceNames and serviceTags apply to them, the other
+	// features only apply to the service of this PagerDutyIntegration.
+	AdditionalServices []AdditionalService `json:"additionalServices,omitempty"`
+
+	// Flag the selected clusters whose PagerDuty service gets far more
+	// incidents than the rest of the fleet, from the PagerDuty analytics
+	// polled a few times a day, so noisy clusters can be found. Omitting
+	// this field disables the check.
+	AlertVolumeAnomaly *AlertVolumeAnomaly `json:"alertVolumeAnomaly,omitempty"`
+
+	// Suggest how to tune the PagerDuty service of each selected cluster,
+	// such as enabling intelligent alert grouping, from its incidents
+	// listed every window. Suggestions are reported in status.clusters and
+	// as events, they are never applied. Omitting this field disables the
+	// suggestions.
+	ServiceTuning *ServiceTuning `json:"serviceTuning,omitempty"`
+
+	// Sweep the PagerDuty account for services named after servicePrefix
+	// whose cluster no longer exists, such as those left behind when the
+	// teardown of a cluster fails. Omitting this field disables the sweep.
+	OrphanedServiceSweep *OrphanedServiceSweep `json:"orphanedServiceSweep,omitempty"`
+
+	// Account the attempts to set up, tear down and verify the selected
+	// clusters against an objective over a rolling window, reported in
+	// status.errorBudget, so SLOs can be set on the provisioning of paging
+	// itself. Omitting this field disables the accounting.
+	ErrorBudget *ErrorBudget `json:"errorBudget,omitempty"`
+
+	// Create a heartbeat integration on the PagerDuty service of each
+	// selected cluster, and sync the URL the cluster is expected to check
+	// in at to TargetSecretRef. A cluster that stops checking in, such as
+	// one whose Alertmanager stopped, is paged on through the heartbeat
+	// integration. Omitting this field disables heartbeats.
+	Heartbeat *Heartbeat `json:"heartbeat,omitempty"`
+
+	// Write a report of the paging coverage of the selected clusters, which
+	// of them have an active PagerDuty service, are silenced or failed, to
+	// a ConfigMap every interval, and optionally send it on as a change
+	// event or to a webhook. Omitting this field disables the report.
+	CoverageReport *CoverageReport `json:"coverageReport,omitempty"`
+
+	// How often the PagerDuty service of each selected cluster is verified
+	// when nothing changed, the verifications being spread over the fleet.
+	// Large fleets may want a slower resync, dev environments a faster one.
+	// Values below 5 minutes are raised to 5 minutes. Omitting this field
+	// uses the --resync-period of the operator.
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+
+	// How long to wait before reconciling again once a call to the
+	// PagerDuty API failed with a server error or the API could not be
+	// reached, after the call itself was retried. Omitting this field uses
+	// the --pagerduty-error-requeue-delay of the operator.
+	ErrorRequeueDelay *metav1.Duration `json:"errorRequeueDelay,omitempty"`
+}
+
+// ErrorBudget configures the accounting of the per-cluster operations
+// against an objective
+// +k8s:openapi-gen=true
+type ErrorBudget struct {
+	// Rolling period over which the operations are accounted. Values below
+	// 1 hour are raised to 1 hour. Defaults to 7 days.
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// Percentage of the operations that must succeed, such as "99.5". The
+	// failures it allows are the error budget. Defaults to "99".
+	// +kubebuilder:validation:Pattern=`^[0-9]{1,2}(\.[0-9]+)?$`
+	Objective string `json:"objective,omitempty"`
+}
+
+// ServiceDeletionPolicy is what is done with the PagerDuty service of a
+// deleted cluster
+type ServiceDeletionPolicy string
+
+const (
+	// ServiceDeletionPolicyDelete deletes the service
+	ServiceDeletionPolicyDelete ServiceDeletionPolicy = "Delete"
+	// ServiceDeletionPolicyRetain leaves the service as is
+	ServiceDeletionPolicyRetain ServiceDeletionPolicy = "Retain"
+	// ServiceDeletionPolicyDisable disables the service
+	ServiceDeletionPolicyDisable ServiceDeletionPolicy = "Disable"
+)
+
+// OrphanedServiceAction is what is done with a PagerDuty service whose
+// cluster no longer exists
+type OrphanedServiceAction string
+
+const (
+	// OrphanedServiceActionReport lists the service in
+	// status.orphanedServices
+	OrphanedServiceActionReport OrphanedServiceAction = "Report"
+	// OrphanedServiceActionDelete deletes the service
+	OrphanedServiceActionDelete OrphanedServiceAction = "Delete"
+)
+
+// OrphanedServiceSweep configures the sweep of the PagerDuty services whose
+// cluster no longer exists
+// +k8s:openapi-gen=true
+type OrphanedServiceSweep struct {
+	// What is done with each orphaned service: Report lists it in
+	// status.orphanedServices, Delete deletes it. Defaults to Report.
+	// +kubebuilder:validation:Enum=Report;Delete
+	Action OrphanedServiceAction `json:"action,omitempty"`
+
+	// How often the account is swept. Values below 1 hour are raised to 1
+	// hour. Defaults to 24 hours.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// AlertVolumeAnomaly configures the flagging of clusters getting far more
+// incidents than the fleet
+// +k8s:openapi-gen=true
+type AlertVolumeAnomaly struct {
+	// Period over which the incidents of each cluster are counted, which is
+	// also how often they are counted. Values below 6 hours are raised to 6
+	// hours. Defaults to 24 hours.
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// How many times the median incident count of the fleet a cluster must
+	// reach to be flagged. Defaults to 5.
+	// +kubebuilder:validation:Minimum=2
+	DeviationFactor int `json:"deviationFactor,omitempty"`
+}
+
+// ServiceTuning configures the suggestions to tune the PagerDuty services of
+// the clusters
+// +k8s:openapi-gen=true
+type ServiceTuning struct {
+	// Period over which the incidents of each cluster are listed, which is
+	// also how often the suggestions are computed. Values below 6 hours are
+	// raised to 6 hours. Defaults to 7 days.
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// How many incidents a service must get over the window for its
+	// incidents to tell anything. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	MinIncidents int `json:"minIncidents,omitempty"`
+}
+
+// AdditionalService is a further PagerDuty service set up for the clusters it
+// selects
+// +k8s:openapi-gen=true
+type AdditionalService struct {
+	// Prefix to set on the PagerDuty Service name. It must differ from the
+	// servicePrefix of this PagerDutyIntegration and of its other additional
+	// services.
+	ServicePrefix string `json:"servicePrefix"`
+
+	// ID of an existing Escalation Policy in PagerDuty.
+	EscalationPolicy string `json:"escalationPolicy"`
+
+	// A label selector used to find which clusterdeployment CRs receive
+	// this service.
+	ClusterDeploymentSelector metav1.LabelSelector `json:"clusterDeploymentSelector"`
+
 	// Name and namespace in the target cluster where the secret is synced.
 	TargetSecretRef corev1.SecretReference `json:"targetSecretRef"`
 }
 
+// AccountMigration configures the PagerDuty account clusters are migrated to
+// +k8s:openapi-gen=true
+type AccountMigration struct {
+	// Reference to the secret containing the PAGERDUTY_API_KEY of the
+	// account being migrated to.
+	PagerdutyApiKeySecretRef corev1.SecretReference `json:"pagerdutyApiKeySecretRef"`
+
+	// URL of the PagerDuty REST API of the account being migrated to, so
+	// clusters can move to another service region. Defaults to
+	// https://api.pagerduty.com.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	APIEndpoint string `json:"apiEndpoint,omitempty"`
+
+	// ID of an existing Escalation Policy in the account being migrated to.
+	EscalationPolicy string `json:"escalationPolicy"`
+
+	// Key of TargetSecretRef holding the integration key of the account
+	// being migrated to. Defaults to PAGERDUTY_KEY_MIGRATION.
+	SecretKey string `json:"secretKey,omitempty"`
+
+	// Phase of the migration. "DualWrite", the default, keeps the services
+	// of both accounts. "Decommission" disables the services of the current
+	// account, then deletes them, a batch at a time. Once all are deleted,
+	// point PagerdutyApiKeySecretRef and EscalationPolicy to the new account
+	// and remove AccountMigration, the services of the new account then take
+	// over.
+	// +kubebuilder:validation:Enum=DualWrite;Decommission
+	Phase MigrationPhase `json:"phase,omitempty"`
+
+	// How many services of the current account are disabled or deleted per
+	// reconcile in the Decommission phase. Defaults to 20.
+	// +kubebuilder:validation:Minimum=1
+	DecommissionBatchSize int `json:"decommissionBatchSize,omitempty"`
+}
+
+// MigrationPhase is a valid value for AccountMigration.Phase
+type MigrationPhase string
+
+const (
+	// MigrationPhaseDualWrite keeps the services of both accounts
+	MigrationPhaseDualWrite MigrationPhase = "DualWrite"
+	// MigrationPhaseDecommission disables then deletes the services of the
+	// account being migrated from
+	MigrationPhaseDecommission MigrationPhase = "Decommission"
+)
+
+// FleetHygieneService is the PagerDuty service informed of repaired drift
+// +k8s:openapi-gen=true
+type FleetHygieneService struct {
+	// Reference to the secret containing the PAGERDUTY_KEY of an Events API
+	// v2 integration of the service.
+	IntegrationKeySecretRef corev1.SecretReference `json:"integrationKeySecretRef"`
+}
+
+// ServiceTags are the ownership tags of the PagerDuty services. Each one is
+// set as a "<key>:<value>" tag, an empty value removes the tag.
+// +k8s:openapi-gen=true
+type ServiceTags struct {
+	// Cost center the services are billed to, tagged cost-center:<value>.
+	CostCenter string `json:"costCenter,omitempty"`
+
+	// Team owning the services, tagged owner:<value>.
+	Owner string `json:"owner,omitempty"`
+
+	// Environment of the clusters, such as production or staging, tagged
+	// environment:<value>.
+	Environment string `json:"environment,omitempty"`
+}
+
+// ServiceDependencies are the PagerDuty services related to the services of
+// the clusters
+// +k8s:openapi-gen=true
+type ServiceDependencies struct {
+	// ID of the PagerDuty technical service of the hub cluster, which the
+	// service of each cluster depends on.
+	HubServiceID string `json:"hubServiceID,omitempty"`
+
+	// ID of a PagerDuty business service, such as the offering the fleet
+	// provides, depending on the service of each cluster.
+	BusinessServiceID string `json:"businessServiceID,omitempty"`
+}
+
+// DeliveryProbe configures the CronJob verifying the integration key on
+// each cluster
+// +k8s:openapi-gen=true
+type DeliveryProbe struct {
+	// Image the probe runs, it must provide sh, curl and oc.
+	Image string `json:"image"`
+
+	// Schedule of the probe in cron format. Defaults to every 6 hours.
+	Schedule string `json:"schedule,omitempty"`
+}
+
+// Heartbeat configures the check-ins expected from each cluster
+// +k8s:openapi-gen=true
+type Heartbeat struct {
+	// URL the clusters reach the heartbeat endpoint of the operator at, such
+	// as the https URL of a Route to the pagerduty-operator-heartbeat
+	// Service. The URL a cluster checks in at is this URL followed by a path
+	// identifying the cluster.
+	URL string `json:"url"`
+
+	// How often each cluster is expected to check in. A cluster that did
+	// not check in for twice this long is paged on. Values below 5 minutes
+	// are raised to 5 minutes.
+	Interval metav1.Duration `json:"interval"`
+
+	// Key of the synced secret holding the URL the cluster checks in at,
+	// PAGERDUTY_HEARTBEAT_URL by default.
+	SecretKey string `json:"secretKey,omitempty"`
+}
+
+// CoverageReport configures the periodic report of the paging coverage of
+// the selected clusters
+// +k8s:openapi-gen=true
+type CoverageReport struct {
+	// How often the report is written. Values below 1 hour are raised to 1
+	// hour. Defaults to 24 hours.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Reference to the secret containing the PAGERDUTY_KEY of an Events API
+	// v2 integration of a service sent a change event summarizing each
+	// report. Omitting this field sends none.
+	ChangeEventSecretRef *corev1.SecretReference `json:"changeEventSecretRef,omitempty"`
+
+	// URL each report is posted to as JSON. Omitting this field posts none.
+	WebhookURL string `json:"webhookURL,omitempty"`
+}
+
+// SharedIntegrationKey is the integration key delivered to all clusters
+// +k8s:openapi-gen=true
+type SharedIntegrationKey struct {
+	// Reference to the secret containing the PAGERDUTY_KEY of an Events API
+	// v2 integration of the service. It is synced as is to TargetSecretRef
+	// in each cluster, SecretDeliveryMode and ImmutableSecret are ignored.
+	IntegrationKeySecretRef corev1.SecretReference `json:"integrationKeySecretRef"`
+}
+
+// AlertmanagerConfigKind is the kind of the object holding the Alertmanager
+// configuration in the target cluster
+type AlertmanagerConfigKind string
+
+const (
+	// AlertmanagerConfigKindSecret holds the configuration in a Secret
+	AlertmanagerConfigKindSecret AlertmanagerConfigKind = "Secret"
+	// AlertmanagerConfigKindConfigMap holds the configuration in a ConfigMap
+	AlertmanagerConfigKindConfigMap AlertmanagerConfigKind = "ConfigMap"
+)
+
+// AlertmanagerConfig configures the Alertmanager configuration synced to
+// each cluster
+// +k8s:openapi-gen=true
+type AlertmanagerConfig struct {
+	// Name of the object holding the configuration in the target cluster,
+	// under the alertmanager.yaml key.
+	Name string `json:"name"`
+
+	// Namespace of the object in the target cluster.
+	Namespace string `json:"namespace"`
+
+	// Kind of the object: Secret, the default, or ConfigMap. The
+	// configuration holds the integration key, which a ConfigMap leaves
+	// readable to anyone allowed to read ConfigMaps in the namespace.
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	Kind AlertmanagerConfigKind `json:"kind,omitempty"`
+
+	// Name of the receiver alerts are routed to. Defaults to pagerduty.
+	Receiver string `json:"receiver,omitempty"`
+}
+
+// DeprovisioningEventRule configures the rule suppressing the events of
+// clusters being deprovisioned
+// +k8s:openapi-gen=true
+type DeprovisioningEventRule struct {
+	// ID of the PagerDuty global ruleset the rule is added to.
+	RulesetID string `json:"rulesetID"`
+
+	// Custom detail of the events holding the cluster ID. Events whose
+	// detail equals the clusterName of the ClusterDeployment are
+	// suppressed. Defaults to cluster_id.
+	ClusterIDDetail string `json:"clusterIDDetail,omitempty"`
+
+	// How long the rule stays active once deprovisioning starts, after
+	// which it no longer matches and is cleaned up. Defaults to 2 hours.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// SelfServiceOnboarding configures which ClusterDeployments may opt into a
+// PagerDutyIntegration with an annotation
+// +k8s:openapi-gen=true
+type SelfServiceOnboarding struct {
+	// Selects the namespaces whose ClusterDeployments may opt in. The
+	// validating webhook rejects the annotation of the others, and the
+	// operator ignores it. Omitting this field or leaving it empty allows
+	// every namespace.
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// EscalationPolicyMapping maps the values of a ClusterDeployment label to
+// escalation policies
+// +k8s:openapi-gen=true
+type EscalationPolicyMapping struct {
+	// Key of the ClusterDeployment label whose value picks the policy.
+	LabelKey string `json:"labelKey"`
+
+	// ID of an existing Escalation Policy in PagerDuty for each value of
+	// the label.
+	Policies map[string]string `json:"policies"`
+}
+
+// IncidentUrgency configures the urgency rule of the PagerDuty services
+// +k8s:openapi-gen=true
+type IncidentUrgency struct {
+	// Urgency of the incidents, or of those raised during support hours
+	// when supportHours is set. Defaults to severity_based.
+	// +kubebuilder:validation:Enum=high;low;severity_based
+	Urgency Urgency `json:"urgency,omitempty"`
+
+	// Urgency of the incidents raised outside support hours, when
+	// supportHours is set. Defaults to low.
+	// +kubebuilder:validation:Enum=high;low;severity_based
+	OutsideSupportHoursUrgency Urgency `json:"outsideSupportHoursUrgency,omitempty"`
+
+	// Support hours of the services. Omitting this field applies urgency
+	// at all times.
+	SupportHours *SupportHours `json:"supportHours,omitempty"`
+}
+
+// SupportHours are the hours of each day of the week during which support
+// is provided
+// +k8s:openapi-gen=true
+type SupportHours struct {
+	// Time zone of the support hours, such as America/New_York.
+	TimeZone string `json:"timeZone"`
+
+	// Time at which support starts each day, such as 09:00:00.
+	StartTime string `json:"startTime"`
+
+	// Time at which support ends each day, such as 17:00:00.
+	EndTime string `json:"endTime"`
+
+	// Days of the week with support, 1 for Monday to 7 for Sunday.
+	DaysOfWeek []uint `json:"daysOfWeek"`
+}
+
+// AlertGrouping configures how the alerts of the PagerDuty services are
+// grouped into incidents
+// +k8s:openapi-gen=true
+type AlertGrouping struct {
+	// How alerts are grouped: intelligent, by PagerDuty's judgement of
+	// which are related, time, within a timeout of the first alert of an
+	// incident, or content_based, when they share the values of fields.
+	// +kubebuilder:validation:Enum=intelligent;time;content_based
+	Type AlertGroupingType `json:"type"`
+
+	// Minutes after the first alert of an incident during which the alerts
+	// are grouped into it, for time grouping. Omitting this field or
+	// setting it to 0 groups them until the incident is resolved.
+	// +kubebuilder:validation:Minimum=0
+	Timeout uint `json:"timeout,omitempty"`
+
+	// Whether content_based alerts are grouped when all or any of fields
+	// have the same values. Defaults to all.
+	// +kubebuilder:validation:Enum=all;any
+	Aggregate string `json:"aggregate,omitempty"`
+
+	// Alert fields content_based alerts are grouped on, such as summary,
+	// component or custom_details.<name>. Required for content_based
+	// grouping.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// AlertGroupingType is how alerts are grouped into incidents
+type AlertGroupingType string
+
+const (
+	// AlertGroupingIntelligent groups the alerts PagerDuty finds related
+	AlertGroupingIntelligent AlertGroupingType = "intelligent"
+	// AlertGroupingTime groups the alerts raised within a timeout
+	AlertGroupingTime AlertGroupingType = "time"
+	// AlertGroupingContentBased groups the alerts sharing field values
+	AlertGroupingContentBased AlertGroupingType = "content_based"
+)
+
+// IntegrationType is the type of the integration whose key is synced to
+// the cluster
+type IntegrationType string
+
+const (
+	// IntegrationTypeEventsAPIV2 is an Events API v2 integration
+	IntegrationTypeEventsAPIV2 IntegrationType = "events_api_v2"
+	// IntegrationTypePrometheus is an integration of the Prometheus vendor
+	IntegrationTypePrometheus IntegrationType = "prometheus"
+	// IntegrationTypeGenericEventsAPI is an Events API v1 integration
+	IntegrationTypeGenericEventsAPI IntegrationType = "generic_events_api"
+)
+
+// Urgency is the urgency of PagerDuty incidents
+type Urgency string
+
+const (
+	// UrgencyHigh treats all incidents as high urgency
+	UrgencyHigh Urgency = "high"
+	// UrgencyLow treats all incidents as low urgency
+	UrgencyLow Urgency = "low"
+	// UrgencySeverityBased maps the severity of incidents to their urgency
+	UrgencySeverityBased Urgency = "severity_based"
+)
+
+// SecretDeliveryMode describes how the integration key reaches the cluster
+type SecretDeliveryMode string
+
+const (
+	// SecretDeliveryModeSecret syncs a standalone secret holding the key
+	SecretDeliveryModeSecret SecretDeliveryMode = "Secret"
+	// SecretDeliveryModePatch merges the key into an existing secret
+	SecretDeliveryModePatch SecretDeliveryMode = "Patch"
+)
+
+// SilenceSource describes what muted a cluster
+type SilenceSource string
+
+const (
+	// SilenceSourcePagerDutySilence means the cluster is muted by a PagerDutySilence
+	SilenceSourcePagerDutySilence SilenceSource = "PagerDutySilence"
+	// SilenceSourceNoalertsLabel means the cluster is muted by the noalerts label
+	SilenceSourceNoalertsLabel SilenceSource = "NoalertsLabel"
+	// SilenceSourceSilencedAnnotation means the service of the cluster is
+	// disabled by the silenced annotation
+	SilenceSourceSilencedAnnotation SilenceSource = "SilencedAnnotation"
+	// SilenceSourcePagerDutyClusterConfig means the service of the cluster
+	// is disabled by a PagerDutyClusterConfig disabling paging
+	SilenceSourcePagerDutyClusterConfig SilenceSource = "PagerDutyClusterConfig"
+)
+
+// ActiveSilence describes a cluster that is intentionally muted
+// +k8s:openapi-gen=true
+type ActiveSilence struct {
+	// Namespace of the muted ClusterDeployment.
+	ClusterDeploymentNamespace string `json:"clusterDeploymentNamespace"`
+
+	// Name of the muted ClusterDeployment.
+	ClusterDeploymentName string `json:"clusterDeploymentName"`
+
+	// What muted the cluster: PagerDutySilence, NoalertsLabel,
+	// SilencedAnnotation or PagerDutyClusterConfig.
+	Source SilenceSource `json:"source"`
+
+	// Name of the PagerDutySilence muting the cluster, if any.
+	SilenceName string `json:"silenceName,omitempty"`
+
+	// Why the cluster is muted, if known.
+	Reason string `json:"reason,omitempty"`
+
+	// Who muted the cluster, if known.
+	Requester string `json:"requester,omitempty"`
+
+	// Time at which the silence expires. Unset when the silence has no
+	// expiry, as is the case for the noalerts label.
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// StaleSilence describes a silence that outlived maxSilenceDuration and was
+// lifted by the operator
+// +k8s:openapi-gen=true
+type StaleSilence struct {
+	// Namespace of the ClusterDeployment that was muted.
+	ClusterDeploymentNamespace string `json:"clusterDeploymentNamespace"`
+
+	// Name of the ClusterDeployment that was muted.
+	ClusterDeploymentName string `json:"clusterDeploymentName"`
+
+	// What muted the cluster: PagerDutySilence or NoalertsLabel.
+	Source SilenceSource `json:"source"`
+
+	// Name of the PagerDutySilence that muted the cluster, if any.
+	SilenceName string `json:"silenceName,omitempty"`
+
+	// Who muted the cluster, if known.
+	Requester string `json:"requester,omitempty"`
+
+	// Time at which the operator lifted the silence.
+	LiftedAt metav1.Time `json:"liftedAt"`
+}
+
+// ClusterConditionType is a valid value for ClusterCondition.Type
+type ClusterConditionType string
+
+const (
+	// ClusterConditionSyncSetFailed is true when Hive failed to apply the
+	// SyncSet delivering the integration key to the cluster
+	ClusterConditionSyncSetFailed ClusterConditionType = "SyncSetFailed"
+
+	// ClusterConditionServiceVerificationFailed is true when the periodic
+	// check that the cluster's PagerDuty service still exists failed
+	ClusterConditionServiceVerificationFailed ClusterConditionType = "ServiceVerificationFailed"
+
+	// ClusterConditionDeliveryVerificationFailed is true when the delivery
+	// probe on the cluster reported the integration key missing or
+	// events.pagerduty.com unreachable
+	ClusterConditionDeliveryVerificationFailed ClusterConditionType = "DeliveryVerificationFailed"
+
+	// ClusterConditionHeartbeatMissed is true when the cluster did not check
+	// in for twice spec.heartbeat.interval, and was paged on through its
+	// heartbeat integration
+	ClusterConditionHeartbeatMissed ClusterConditionType = "HeartbeatMissed"
+
+	// ClusterConditionReady is true when the cluster's PagerDuty integration
+	// is fully set up, its state is Ready
+	ClusterConditionReady ClusterConditionType = "Ready"
+
+	// ClusterConditionDegraded is true when the cluster's PagerDuty service
+	// or the delivery of its integration key failed verification
+	ClusterConditionDegraded ClusterConditionType = "Degraded"
+
+	// ClusterConditionDeleting is true while the ClusterDeployment is being
+	// deleted, once its PagerDuty integration was torn down
+	ClusterConditionDeleting ClusterConditionType = "Deleting"
+)
+
+// RetryReason is why setting up the PagerDuty integration of a cluster was
+// skipped or will be retried. The values are stable, the same ones are used
+// in status, Events, logs and metrics.
+type RetryReason string
+
+const (
+	// RetryReasonNotInstalled means the cluster is not installed yet
+	RetryReasonNotInstalled RetryReason = "NotInstalled"
+
+	// RetryReasonUnmanaged means the cluster is no longer selected by the
+	// PagerDutyIntegration and its integration is being removed
+	RetryReasonUnmanaged RetryReason = "Unmanaged"
+
+	// RetryReasonSecretSyncPending means Hive has not applied the SyncSet
+	// delivering the integration key yet, or failed to
+	RetryReasonSecretSyncPending RetryReason = "SecretSyncPending"
+
+	// RetryReasonPDRateLimited means the PagerDuty API rate limited the operator
+	RetryReasonPDRateLimited RetryReason = "PDRateLimited"
+
+	// RetryReasonPDError means setting up the PagerDuty service failed or
+	// was refused
+	RetryReasonPDError RetryReason = "PDError"
+
+	// RetryReasonConflict means the objects of the cluster belong to another
+	// PagerDutyIntegration with the same ServicePrefix
+	RetryReasonConflict RetryReason = "Conflict"
+
+	// RetryReasonServiceNameTooLong means the name of the cluster's
+	// PagerDuty service, made of the ServicePrefix, cluster name and base
+	// domain, is longer than PagerDuty accepts
+	RetryReasonServiceNameTooLong RetryReason = "ServiceNameTooLong"
+
+	// RetryReasonServiceNameTemplateFailed means the ServiceNameTemplate
+	// can't name the cluster's PagerDuty service, as when it refers to a
+	// label the ClusterDeployment doesn't have
+	RetryReasonServiceNameTemplateFailed RetryReason = "ServiceNameTemplateFailed"
+)
+
+// ClusterState is a valid value for ClusterStatus.State
+type ClusterState string
+
+const (
+	// ClusterStateReady is the state of a cluster whose PagerDuty
+	// integration is fully set up
+	ClusterStateReady ClusterState = "Ready"
+	// ClusterStatePending is the state of a cluster whose PagerDuty
+	// integration is not fully set up yet, without anything failing
+	ClusterStatePending ClusterState = "Pending"
+	// ClusterStateFailed is the state of a cluster whose PagerDuty
+	// integration failed to be set up or verified
+	ClusterStateFailed ClusterState = "Failed"
+	// ClusterStateDeleting is the state of a cluster whose ClusterDeployment
+	// is being deleted
+	ClusterStateDeleting ClusterState = "Deleting"
+)
+
+// ClusterCondition describes one aspect of the state of a cluster's
+// PagerDuty integration
+// +k8s:openapi-gen=true
+type ClusterCondition struct {
+	// Type of the condition.
+	Type ClusterConditionType `json:"type"`
+
+	// Status of the condition: True, False or Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+
+	// Machine readable reason for the last transition.
+	Reason string `json:"reason,omitempty"`
+
+	// Human readable detail about the last transition.
+	Message string `json:"message,omitempty"`
+
+	// Time at which the condition last changed status.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// ClusterStatus is the observed state of the PagerDuty integration of one
+// selected cluster
+// +k8s:openapi-gen=true
+type ClusterStatus struct {
+	// Namespace of the ClusterDeployment.
+	ClusterDeploymentNamespace string `json:"clusterDeploymentNamespace"`
+
+	// Name of the ClusterDeployment.
+	ClusterDeploymentName string `json:"clusterDeploymentName"`
+
+	// ID of the cluster's PagerDuty service, once created.
+	ServiceID string `json:"serviceID,omitempty"`
+
+	// Summary of the conditions and retryReason: Ready, Pending, Failed or
+	// Deleting.
+	State ClusterState `json:"state,omitempty"`
+
+	// Why the cluster is Failed, taken from the error setting it up or the
+	// message of the failed condition.
+	LastError string `json:"lastError,omitempty"`
+
+	// Incidents of the cluster's PagerDuty service over the last window,
+	// when alertVolumeAnomaly is set.
+	AlertVolume *AlertVolumeStatus `json:"alertVolume,omitempty"`
+
+	// Suggestions to tune the cluster's PagerDuty service, when
+	// serviceTuning is set.
+	Suggestions []ServiceSuggestion `json:"suggestions,omitempty"`
+
+	// Conditions of the cluster's PagerDuty integration.
+	Conditions []ClusterCondition `json:"conditions,omitempty"`
+
+	// Why setting up the cluster's PagerDuty integration was skipped or will
+	// be retried, unset once it is complete.
+	RetryReason RetryReason `json:"retryReason,omitempty"`
+
+	// Time at which the cluster's PagerDuty service was last verified.
+	// Verifications are staggered across the fleet, each cluster getting a
+	// fixed slot in the resync period.
+	LastVerifiedTime *metav1.Time `json:"lastVerifiedTime,omitempty"`
+
+	// Result of the last synthetic test alert sent through the cluster's
+	// integration, when testAlertInterval is set.
+	TestAlert *TestAlertStatus `json:"testAlert,omitempty"`
+
+	// Time at which the integration key of the cluster was last rotated for
+	// the pd.openshift.io/rotate-integration-key annotation.
+	LastKeyRotationTime *metav1.Time `json:"lastKeyRotationTime,omitempty"`
+}
+
+// AlertVolumeStatus is the incident count of a cluster compared to the fleet
+// +k8s:openapi-gen=true
+type AlertVolumeStatus struct {
+	// Number of incidents created on the cluster's PagerDuty service over
+	// the last window.
+	Incidents int `json:"incidents"`
+
+	// Whether the cluster reached deviationFactor times the median incident
+	// count of the fleet.
+	Anomalous bool `json:"anomalous,omitempty"`
+
+	// How the cluster compares to the fleet, when it is anomalous.
+	Note string `json:"note,omitempty"`
+}
+
+// ServiceSuggestionType is a valid value for ServiceSuggestion.Type
+type ServiceSuggestionType string
+
+const (
+	// ServiceSuggestionEnableAlertGrouping is suggested when many incidents
+	// of the service repeat an earlier one, which intelligent alert grouping
+	// would have merged.
+	ServiceSuggestionEnableAlertGrouping ServiceSuggestionType = "EnableAlertGrouping"
+
+	// ServiceSuggestionPauseTransientAlerts is suggested when most incidents
+	// of the service resolve on their own, which pausing the notifications
+	// of transient alerts would have kept from paging.
+	ServiceSuggestionPauseTransientAlerts ServiceSuggestionType = "PauseTransientAlerts"
+)
+
+// ServiceSuggestion is a change suggested to the PagerDuty service of a
+// cluster
+// +k8s:openapi-gen=true
+type ServiceSuggestion struct {
+	// Kind of change suggested.
+	Type ServiceSuggestionType `json:"type"`
+
+	// What in the incidents of the service led to the suggestion.
+	Message string `json:"message"`
+}
+
+// TestAlertStatus is the result of a synthetic test alert sent through the
+// integration of one cluster
+// +k8s:openapi-gen=true
+type TestAlertStatus struct {
+	// Time at which the test alert was sent.
+	LastTestTime metav1.Time `json:"lastTestTime"`
+
+	// Deduplication key of the test alert in PagerDuty.
+	DedupKey string `json:"dedupKey,omitempty"`
+
+	// Whether PagerDuty accepted the test alert.
+	Accepted bool `json:"accepted"`
+
+	// Time PagerDuty took to accept the test alert.
+	Latency *metav1.Duration `json:"latency,omitempty"`
+
+	// Why the test alert was not accepted, if it was not.
+	Message string `json:"message,omitempty"`
+}
+
+// PagerDutyIntegrationConditionType is a valid value for PagerDutyIntegrationCondition.Type
+type PagerDutyIntegrationConditionType string
+
+const (
+	// PagerDutyIntegrationConditionAccountQuotaExceeded is true when the
+	// PagerDuty account refused to create a service because it reached its
+	// service limit. No services are created while it is true.
+	PagerDutyIntegrationConditionAccountQuotaExceeded PagerDutyIntegrationConditionType = "AccountQuotaExceeded"
+
+	// PagerDutyIntegrationConditionReferencesValid is true when all PagerDuty
+	// resources the PagerDutyIntegration refers to exist. No services are
+	// created while it is false.
+	PagerDutyIntegrationConditionReferencesValid PagerDutyIntegrationConditionType = "ReferencesValid"
+
+	// PagerDutyIntegrationConditionAPIKeyValid is false when the API key of
+	// pagerdutyApiKeySecretRef cannot be loaded or is refused by PagerDuty.
+	// No services are created while it is false. It is only set once a key
+	// was found invalid, and turns true when a valid key is rotated in.
+	PagerDutyIntegrationConditionAPIKeyValid PagerDutyIntegrationConditionType = "APIKeyValid"
+
+	// PagerDutyIntegrationConditionLeakedService is true when the service of
+	// a cluster torn down without its ConfigMap could not be looked up by
+	// name, so it may be left in PagerDuty. It turns false after the next
+	// orphanedServiceSweep, which reports or deletes such services.
+	PagerDutyIntegrationConditionLeakedService PagerDutyIntegrationConditionType = "LeakedService"
+
+	// PagerDutyIntegrationConditionTenancyValid is false when a
+	// PagerDutyIntegration outside of the operator namespace refers to a
+	// secret of another namespace, or has the name of an older
+	// PagerDutyIntegration of another namespace. Nothing is set up or torn
+	// down while it is false. It is only set once the PagerDutyIntegration
+	// was found reaching outside of its namespace.
+	PagerDutyIntegrationConditionTenancyValid PagerDutyIntegrationConditionType = "TenancyValid"
+)
+
+// PagerDutyIntegrationCondition describes one aspect of the state of a
+// PagerDutyIntegration as a whole
+// +k8s:openapi-gen=true
+type PagerDutyIntegrationCondition struct {
+	// Type of the condition.
+	Type PagerDutyIntegrationConditionType `json:"type"`
+
+	// Status of the condition: True, False or Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+
+	// Machine readable reason for the last transition.
+	Reason string `json:"reason,omitempty"`
+
+	// Human readable detail about the last transition.
+	Message string `json:"message,omitempty"`
+
+	// Time at which the condition last changed status.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
 // PagerDutyIntegrationStatus defines the observed state of PagerDutyIntegration
 // +k8s:openapi-gen=true
-type PagerDutyIntegrationStatus struct{}
+type PagerDutyIntegrationStatus struct {
+	// Conditions of the PagerDutyIntegration that are not specific to one
+	// cluster.
+	Conditions []PagerDutyIntegrationCondition `json:"conditions,omitempty"`
+
+	// Clusters selected by this PagerDutyIntegration that are currently
+	// intentionally muted, by a PagerDutySilence or the noalerts label.
+	ActiveSilences []ActiveSilence `json:"activeSilences,omitempty"`
+
+	// Clusters selected by this PagerDutyIntegration whose silence outlived
+	// maxSilenceDuration and was lifted by the operator.
+	StaleSilences []StaleSilence `json:"staleSilences,omitempty"`
+
+	// State of the PagerDuty integration of each installed cluster selected
+	// by this PagerDutyIntegration.
+	Clusters []ClusterStatus `json:"clusters,omitempty"`
+
+	// Number of clusters in status.clusters in the Ready state.
+	ReadyClusters int `json:"readyClusters,omitempty"`
+
+	// Number of clusters in status.clusters in the Pending state.
+	PendingClusters int `json:"pendingClusters,omitempty"`
+
+	// Number of clusters in status.clusters in the Failed state.
+	FailedClusters int `json:"failedClusters,omitempty"`
+
+	// Number of clusters in status.clusters whose alert volume is anomalous.
+	AnomalousClusters int `json:"anomalousClusters,omitempty"`
+
+	// Number of clusters in status.clusters Hive failed to apply the
+	// SyncSet of the integration key to, whose SyncSetFailed condition is
+	// True.
+	SecretSyncFailedClusters int `json:"secretSyncFailedClusters,omitempty"`
+
+	// Time at which the incidents of the clusters were last counted, when
+	// alertVolumeAnomaly is set.
+	LastAlertVolumeTime *metav1.Time `json:"lastAlertVolumeTime,omitempty"`
+
+	// Time at which the suggestions to tune the services of the clusters
+	// were last computed, when serviceTuning is set.
+	LastServiceTuningTime *metav1.Time `json:"lastServiceTuningTime,omitempty"`
+
+	// Time up to which the PagerDuty audit records were polled, when
+	// auditPollInterval is set.
+	LastAuditPollTime *metav1.Time `json:"lastAuditPollTime,omitempty"`
+
+	// PagerDuty services named after servicePrefix whose cluster no longer
+	// exists, found by the last sweep when orphanedServiceSweep is set.
+	// Deleted services are only listed if their deletion failed.
+	OrphanedServices []OrphanedService `json:"orphanedServices,omitempty"`
+
+	// Time at which the PagerDuty account was last swept for orphaned
+	// services, when orphanedServiceSweep is set.
+	LastOrphanedServiceSweepTime *metav1.Time `json:"lastOrphanedServiceSweepTime,omitempty"`
+
+	// Time at which the last coverage report was written, when
+	// coverageReport is set.
+	LastCoverageReportTime *metav1.Time `json:"lastCoverageReportTime,omitempty"`
+
+	// Outcome of the per-cluster operations over the window, when
+	// errorBudget is set.
+	ErrorBudget *ErrorBudgetStatus `json:"errorBudget,omitempty"`
+
+	// PagerDuty services of deleted clusters kept for a reinstall to reuse,
+	// when reinstallServiceRetention is set, or until their
+	// deprovisionGracePeriod is over.
+	RetainedServices []RetainedService `json:"retainedServices,omitempty"`
+
+	// Progress of the decommission of the services of the account being
+	// migrated from, when accountMigration is set.
+	AccountMigration *AccountMigrationStatus `json:"accountMigration,omitempty"`
+
+	// Progress of the teardown of what the PagerDutyIntegration set up, once
+	// it is deleted. The finalizer stays until all of it is torn down.
+	Cleanup *CleanupStatus `json:"cleanup,omitempty"`
+
+	// servicePrefix the secondary resources and PagerDuty services of the
+	// clusters are named after. While it differs from spec.servicePrefix,
+	// they are being renamed to it.
+	ServicePrefix string `json:"servicePrefix,omitempty"`
+}
+
+// CleanupStatus is the progress of the teardown of what a deleted
+// PagerDutyIntegration set up
+// +k8s:openapi-gen=true
+type CleanupStatus struct {
+	// Number of clusters torn down by the last attempt.
+	ClustersCleanedUp int `json:"clustersCleanedUp"`
+
+	// Number of clusters whose teardown failed and is retried.
+	ClustersRemaining int `json:"clustersRemaining"`
+
+	// Number of PagerDuty services, ConfigMaps, Secrets and SyncSets left
+	// once the clusters were torn down whose deletion failed and is retried.
+	ObjectsRemaining int `json:"objectsRemaining"`
+
+	// Error of the last attempt, if it failed.
+	Message string `json:"message,omitempty"`
+
+	// Time of the last attempt.
+	LastAttemptTime metav1.Time `json:"lastAttemptTime"`
+}
+
+// AccountMigrationStatus is the progress of the decommission of the services
+// of the account being migrated from
+// +k8s:openapi-gen=true
+type AccountMigrationStatus struct {
+	// Number of selected clusters with a service in the account being
+	// migrated to.
+	Clusters int `json:"clusters"`
+
+	// Number of services of the account being migrated from that are
+	// disabled and not deleted yet.
+	ServicesDisabled int `json:"servicesDisabled"`
+
+	// Number of services of the account being migrated from that are
+	// deleted.
+	ServicesDeleted int `json:"servicesDeleted"`
+}
+
+// ErrorBudgetStatus is the outcome of the per-cluster operations over the
+// window of the error budget
+// +k8s:openapi-gen=true
+type ErrorBudgetStatus struct {
+	// Number of operations that succeeded over the window.
+	Succeeded int `json:"succeeded"`
+
+	// Number of operations that failed over the window.
+	Failed int `json:"failed"`
+
+	// Share of the operations over the window that succeeded, from 0 to 1.
+	// It is 1 when there were none.
+	SuccessRatio string `json:"successRatio"`
+
+	// Share of the error budget left, 1 when no operation failed and
+	// negative once it is exhausted.
+	Remaining string `json:"remaining"`
+
+	// Operation counts of each period of the window, oldest first, that
+	// roll the window forward.
+	Buckets []OperationBucket `json:"buckets,omitempty"`
+}
+
+// OperationBucket counts the per-cluster operations of one period
+// +k8s:openapi-gen=true
+type OperationBucket struct {
+	// Time at which the period starts.
+	Start metav1.Time `json:"start"`
+
+	// Number of operations of the period that succeeded.
+	Succeeded int `json:"succeeded,omitempty"`
+
+	// Number of operations of the period that failed.
+	Failed int `json:"failed,omitempty"`
+}
+
+// OrphanedService is a PagerDuty service whose cluster no longer exists
+// +k8s:openapi-gen=true
+type OrphanedService struct {
+	// ID of the PagerDuty service.
+	ServiceID string `json:"serviceID"`
+
+	// Name of the PagerDuty service.
+	Name string `json:"name"`
+}
+
+// RetainedService is the PagerDuty service of a deleted cluster kept for a
+// reinstall of the cluster to reuse
+// +k8s:openapi-gen=true
+type RetainedService struct {
+	// Namespace of the deleted ClusterDeployment.
+	ClusterDeploymentNamespace string `json:"clusterDeploymentNamespace"`
+
+	// Name of the deleted ClusterDeployment.
+	ClusterDeploymentName string `json:"clusterDeploymentName"`
+
+	// Cluster name of the deleted ClusterDeployment.
+	ClusterID string `json:"clusterID"`
+
+	// ID of the PagerDuty service.
+	ServiceID string `json:"serviceID"`
+
+	// ID of the integration of the PagerDuty service.
+	IntegrationID string `json:"integrationID"`
+
+	// Time at which the cluster was deleted.
+	RetainedAt metav1.Time `json:"retainedAt"`
+
+	// Whether the PagerDuty service was disabled for the
+	// deprovisionGracePeriod. A reinstall reusing it enables it again.
+	Disabled bool `json:"disabled,omitempty"`
+}
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -47,6 +1255,11 @@ type PagerDutyIntegrationStatus struct{}
 // +k8s:openapi-gen=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=pagerdutyintegrations,shortName=pdi,scope=Namespaced
+// +kubebuilder:printcolumn:name="Service Prefix",type="string",JSONPath=".spec.servicePrefix"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyClusters"
+// +kubebuilder:printcolumn:name="Pending",type="integer",JSONPath=".status.pendingClusters"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failedClusters"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type PagerDutyIntegration struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
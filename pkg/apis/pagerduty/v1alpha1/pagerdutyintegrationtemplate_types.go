@@ -0,0 +1,64 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PagerDutyIntegrationTemplateSpec defines the settings inherited by the
+// PagerDutyIntegrations referring to the template. Each field has the
+// meaning of the PagerDutyIntegration field of the same name.
+// +k8s:openapi-gen=true
+type PagerDutyIntegrationTemplateSpec struct {
+	// Time in seconds that an incident changes to the Triggered State after
+	// being Acknowledged.
+	// +kubebuilder:validation:Minimum=0
+	AcknowledgeTimeout uint `json:"acknowledgeTimeout,omitempty"`
+
+	// Time in seconds that an incident is automatically resolved if left
+	// open for that long.
+	// +kubebuilder:validation:Minimum=0
+	ResolveTimeout uint `json:"resolveTimeout,omitempty"`
+
+	// Urgency of the incidents of the PagerDuty service of each cluster.
+	IncidentUrgency *IncidentUrgency `json:"incidentUrgency,omitempty"`
+
+	// Longest time a selected cluster may stay muted.
+	MaxSilenceDuration *metav1.Duration `json:"maxSilenceDuration,omitempty"`
+
+	// Rule of a PagerDuty global ruleset suppressing the events of deleted
+	// clusters.
+	DeprovisioningEventRule *DeprovisioningEventRule `json:"deprovisioningEventRule,omitempty"`
+
+	// Ownership tags set on the PagerDuty service of each cluster.
+	ServiceTags *ServiceTags `json:"serviceTags,omitempty"`
+
+	// PagerDuty service sent a change event whenever drift is repaired.
+	FleetHygieneService *FleetHygieneService `json:"fleetHygieneService,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PagerDutyIntegrationTemplate holds settings shared by several
+// PagerDutyIntegrations
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=pagerdutyintegrationtemplates,shortName=pdit,scope=Namespaced
+type PagerDutyIntegrationTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PagerDutyIntegrationTemplateSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PagerDutyIntegrationTemplateList contains a list of
+// PagerDutyIntegrationTemplate
+type PagerDutyIntegrationTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PagerDutyIntegrationTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PagerDutyIntegrationTemplate{}, &PagerDutyIntegrationTemplateList{})
+}
@@ -0,0 +1,153 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PagerDutyRulesetSpec defines the desired state of PagerDutyRuleset
+// +k8s:openapi-gen=true
+type PagerDutyRulesetSpec struct {
+	// Reference to the PagerDutyIntegration, in the same namespace as the
+	// PagerDutyRuleset, whose clusters the rules apply to. Its API key
+	// manages the rules.
+	PagerDutyIntegrationRef corev1.LocalObjectReference `json:"pagerDutyIntegrationRef"`
+
+	// ID of the PagerDuty global ruleset the rules are added to.
+	RulesetID string `json:"rulesetID"`
+
+	// Name of the custom detail of the events holding the cluster's ID,
+	// "cluster_id" by default.
+	// +optional
+	ClusterIDDetail string `json:"clusterIDDetail,omitempty"`
+
+	// Rules added for each cluster of the PagerDutyIntegration that has a
+	// PagerDuty service. Each only matches the events of its cluster.
+	Rules []EventRule `json:"rules"`
+}
+
+// EventRule describes how the events of a cluster matching its conditions
+// are handled.
+// +k8s:openapi-gen=true
+type EventRule struct {
+	// Name of the rule, unique within the PagerDutyRuleset.
+	Name string `json:"name"`
+
+	// Conditions on the custom details of the events, such as the alert
+	// labels, that all must hold for the rule to apply.
+	// +optional
+	Conditions []EventRuleCondition `json:"conditions,omitempty"`
+
+	// Route the matching events to the cluster's PagerDuty service.
+	// +optional
+	Route bool `json:"route,omitempty"`
+
+	// Suppress the matching events, so their alerts page nobody.
+	// +optional
+	Suppress bool `json:"suppress,omitempty"`
+
+	// Severity overriding the one of the matching events.
+	// +kubebuilder:validation:Enum=critical;error;warning;info
+	// +optional
+	Severity string `json:"severity,omitempty"`
+}
+
+// EventRuleCondition matches a custom detail of an event.
+// +k8s:openapi-gen=true
+type EventRuleCondition struct {
+	// Name of the custom detail, such as an alert label like "alertname".
+	Label string `json:"label"`
+
+	// How the custom detail is compared to the value. "matches" takes a
+	// regular expression, "exists" ignores the value.
+	// +kubebuilder:validation:Enum=equals;not_equals;contains;not_contains;matches;exists
+	Operator string `json:"operator"`
+
+	// Value the custom detail is compared to.
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+// ManagedEventRule records a PagerDuty ruleset rule created for one cluster
+// from one of the rules of a PagerDutyRuleset.
+// +k8s:openapi-gen=true
+type ManagedEventRule struct {
+	// Name of the rule of the PagerDutyRuleset.
+	Name string `json:"name"`
+
+	// Name of the cluster's ClusterDeployment.
+	ClusterDeploymentName string `json:"clusterDeploymentName"`
+
+	// Namespace of the cluster's ClusterDeployment.
+	ClusterDeploymentNamespace string `json:"clusterDeploymentNamespace"`
+
+	// ID of the PagerDuty global ruleset holding the rule.
+	RulesetID string `json:"rulesetID"`
+
+	// ID of the PagerDuty ruleset rule.
+	RuleID string `json:"ruleID"`
+
+	// Checksum of the rule as last written to PagerDuty, it is updated when
+	// it changes.
+	Checksum string `json:"checksum"`
+}
+
+// AppliedEventRules is a version of the rules of a PagerDutyRuleset that was
+// applied to all its clusters.
+// +k8s:openapi-gen=true
+type AppliedEventRules struct {
+	// Checksum of the rules.
+	Checksum string `json:"checksum"`
+
+	// The rules, as they were in the spec.
+	// +optional
+	Rules []EventRule `json:"rules,omitempty"`
+}
+
+// PagerDutyRulesetStatus defines the observed state of PagerDutyRuleset
+// +k8s:openapi-gen=true
+type PagerDutyRulesetStatus struct {
+	// PagerDuty ruleset rules managed for the PagerDutyRuleset.
+	Rules []ManagedEventRule `json:"rules,omitempty"`
+
+	// Rules last applied to all the clusters.
+	AppliedRules *AppliedEventRules `json:"appliedRules,omitempty"`
+
+	// Rules applied before appliedRules, which the rollback annotation
+	// restores.
+	PreviousRules *AppliedEventRules `json:"previousRules,omitempty"`
+
+	// Human readable detail about the last reconcile of the ruleset.
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PagerDutyRuleset manages PagerDuty ruleset rules routing, suppressing or
+// overriding the severity of the events of the clusters of a
+// PagerDutyIntegration
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=pagerdutyrulesets,shortName=pdrs,scope=Namespaced
+// +kubebuilder:printcolumn:name="PagerDutyIntegration",type="string",JSONPath=".spec.pagerDutyIntegrationRef.name"
+// +kubebuilder:printcolumn:name="Ruleset",type="string",JSONPath=".spec.rulesetID"
+type PagerDutyRuleset struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PagerDutyRulesetSpec   `json:"spec,omitempty"`
+	Status PagerDutyRulesetStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PagerDutyRulesetList contains a list of PagerDutyRuleset
+type PagerDutyRulesetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PagerDutyRuleset `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PagerDutyRuleset{}, &PagerDutyRulesetList{})
+}
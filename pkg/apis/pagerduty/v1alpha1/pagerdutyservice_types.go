@@ -0,0 +1,81 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PagerDutyServiceSpec defines the desired state of PagerDutyService
+// +k8s:openapi-gen=true
+type PagerDutyServiceSpec struct {
+	// Reference to the ClusterDeployment, in the same namespace as the
+	// PagerDutyService, that the PagerDuty service pages for.
+	ClusterDeploymentRef corev1.LocalObjectReference `json:"clusterDeploymentRef"`
+
+	// Name of the PagerDutyIntegration that created the PagerDuty service,
+	// <name>.<servicePrefix> for its additional services.
+	PagerDutyIntegration string `json:"pagerDutyIntegration"`
+
+	// ID of the PagerDuty service.
+	ServiceID string `json:"serviceID"`
+
+	// ID of the integration of the PagerDuty service whose key is delivered
+	// to the cluster.
+	IntegrationID string `json:"integrationID"`
+}
+
+// PagerDutyServiceStatus defines the observed state of PagerDutyService
+// +k8s:openapi-gen=true
+type PagerDutyServiceStatus struct {
+	// Summary of the conditions and retryReason: Ready, Pending, Failed or
+	// Deleting.
+	State ClusterState `json:"state,omitempty"`
+
+	// Why the cluster is Failed, taken from the error setting it up or the
+	// message of the failed condition.
+	LastError string `json:"lastError,omitempty"`
+
+	// Why setting up the cluster's PagerDuty integration was skipped or will
+	// be retried, unset once it is complete.
+	RetryReason RetryReason `json:"retryReason,omitempty"`
+
+	// Conditions of the cluster's PagerDuty integration.
+	Conditions []ClusterCondition `json:"conditions,omitempty"`
+
+	// Time at which the PagerDuty service was last verified.
+	LastVerifiedTime *metav1.Time `json:"lastVerifiedTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PagerDutyService records the PagerDuty service of a cluster, replacing the
+// SERVICE_ID and INTEGRATION_ID of its ConfigMap. It is created and kept up
+// to date by the operator, and shares the name of the ConfigMap.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=pagerdutyservices,shortName=pdsvc,scope=Namespaced
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterDeploymentRef.name"
+// +kubebuilder:printcolumn:name="Integration",type="string",JSONPath=".spec.pagerDutyIntegration"
+// +kubebuilder:printcolumn:name="Service",type="string",JSONPath=".spec.serviceID"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type PagerDutyService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PagerDutyServiceSpec   `json:"spec,omitempty"`
+	Status PagerDutyServiceStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PagerDutyServiceList contains a list of PagerDutyService
+type PagerDutyServiceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PagerDutyService `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PagerDutyService{}, &PagerDutyServiceList{})
+}
@@ -0,0 +1,97 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PagerDutySilencePhase is the lifecycle phase of a PagerDutySilence
+type PagerDutySilencePhase string
+
+const (
+	// SilencePhasePending means the silence has not been enforced in PagerDuty yet
+	SilencePhasePending PagerDutySilencePhase = "Pending"
+	// SilencePhaseActive means every PagerDuty service of the cluster is in maintenance
+	SilencePhaseActive PagerDutySilencePhase = "Active"
+	// SilencePhaseExpired means the silence duration has elapsed and paging is restored
+	SilencePhaseExpired PagerDutySilencePhase = "Expired"
+)
+
+// PagerDutySilenceSpec defines the desired state of PagerDutySilence
+// +k8s:openapi-gen=true
+type PagerDutySilenceSpec struct {
+	// Reference to the ClusterDeployment, in the same namespace as the
+	// PagerDutySilence, whose PagerDuty services are silenced.
+	ClusterDeploymentRef corev1.LocalObjectReference `json:"clusterDeploymentRef"`
+
+	// How long the cluster stays silenced, counted from the creation of
+	// the PagerDutySilence. For example "2h" or "90m".
+	Duration metav1.Duration `json:"duration"`
+
+	// Why the cluster is silenced.
+	Reason string `json:"reason"`
+
+	// Who requested the silence.
+	Requester string `json:"requester"`
+}
+
+// SilenceMaintenanceWindow records a PagerDuty maintenance window created to
+// enforce a PagerDutySilence.
+// +k8s:openapi-gen=true
+type SilenceMaintenanceWindow struct {
+	// Name of the PagerDutyIntegration that owns the silenced service.
+	PagerDutyIntegration string `json:"pagerDutyIntegration"`
+
+	// ID of the silenced PagerDuty service.
+	ServiceID string `json:"serviceID"`
+
+	// ID of the PagerDuty maintenance window.
+	MaintenanceWindowID string `json:"maintenanceWindowID"`
+}
+
+// PagerDutySilenceStatus defines the observed state of PagerDutySilence
+// +k8s:openapi-gen=true
+type PagerDutySilenceStatus struct {
+	// Phase of the silence: Pending, Active or Expired.
+	Phase PagerDutySilencePhase `json:"phase,omitempty"`
+
+	// Time at which the silence expires and paging is restored.
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// PagerDuty maintenance windows enforcing the silence.
+	MaintenanceWindows []SilenceMaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// Human readable detail about the last reconcile of the silence.
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PagerDutySilence temporarily removes a cluster from paging
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=pagerdutysilences,shortName=pds,scope=Namespaced
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterDeploymentRef.name"
+// +kubebuilder:printcolumn:name="Requester",type="string",JSONPath=".spec.requester"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Expires",type="date",JSONPath=".status.expiresAt"
+type PagerDutySilence struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PagerDutySilenceSpec   `json:"spec,omitempty"`
+	Status PagerDutySilenceStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PagerDutySilenceList contains a list of PagerDutySilence
+type PagerDutySilenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PagerDutySilence `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PagerDutySilence{}, &PagerDutySilenceList{})
+}
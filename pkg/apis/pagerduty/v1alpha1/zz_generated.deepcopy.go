@@ -5,31 +5,1207 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountMigration) DeepCopyInto(out *AccountMigration) {
+	*out = *in
+	out.PagerdutyApiKeySecretRef = in.PagerdutyApiKeySecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountMigration.
+func (in *AccountMigration) DeepCopy() *AccountMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountMigrationStatus) DeepCopyInto(out *AccountMigrationStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountMigrationStatus.
+func (in *AccountMigrationStatus) DeepCopy() *AccountMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveSilence) DeepCopyInto(out *ActiveSilence) {
+	*out = *in
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveSilence.
+func (in *ActiveSilence) DeepCopy() *ActiveSilence {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveSilence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalService) DeepCopyInto(out *AdditionalService) {
+	*out = *in
+	in.ClusterDeploymentSelector.DeepCopyInto(&out.ClusterDeploymentSelector)
+	out.TargetSecretRef = in.TargetSecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalService.
+func (in *AdditionalService) DeepCopy() *AdditionalService {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertGrouping) DeepCopyInto(out *AlertGrouping) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertGrouping.
+func (in *AlertGrouping) DeepCopy() *AlertGrouping {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertGrouping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertVolumeAnomaly) DeepCopyInto(out *AlertVolumeAnomaly) {
+	*out = *in
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertVolumeAnomaly.
+func (in *AlertVolumeAnomaly) DeepCopy() *AlertVolumeAnomaly {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertVolumeAnomaly)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertVolumeStatus) DeepCopyInto(out *AlertVolumeStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertVolumeStatus.
+func (in *AlertVolumeStatus) DeepCopy() *AlertVolumeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertVolumeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertmanagerConfig) DeepCopyInto(out *AlertmanagerConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerConfig.
+func (in *AlertmanagerConfig) DeepCopy() *AlertmanagerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertmanagerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedEventRules) DeepCopyInto(out *AppliedEventRules) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]EventRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedEventRules.
+func (in *AppliedEventRules) DeepCopy() *AppliedEventRules {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedEventRules)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CleanupStatus) DeepCopyInto(out *CleanupStatus) {
+	*out = *in
+	in.LastAttemptTime.DeepCopyInto(&out.LastAttemptTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupStatus.
+func (in *CleanupStatus) DeepCopy() *CleanupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CleanupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCondition) DeepCopyInto(out *ClusterCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCondition.
+func (in *ClusterCondition) DeepCopy() *ClusterCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
+	*out = *in
+	if in.AlertVolume != nil {
+		in, out := &in.AlertVolume, &out.AlertVolume
+		*out = new(AlertVolumeStatus)
+		**out = **in
+	}
+	if in.Suggestions != nil {
+		in, out := &in.Suggestions, &out.Suggestions
+		*out = make([]ServiceSuggestion, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ClusterCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastVerifiedTime != nil {
+		in, out := &in.LastVerifiedTime, &out.LastVerifiedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.TestAlert != nil {
+		in, out := &in.TestAlert, &out.TestAlert
+		*out = new(TestAlertStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastKeyRotationTime != nil {
+		in, out := &in.LastKeyRotationTime, &out.LastKeyRotationTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
+func (in *ClusterStatus) DeepCopy() *ClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoverageReport) DeepCopyInto(out *CoverageReport) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ChangeEventSecretRef != nil {
+		in, out := &in.ChangeEventSecretRef, &out.ChangeEventSecretRef
+		*out = new(corev1.SecretReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoverageReport.
+func (in *CoverageReport) DeepCopy() *CoverageReport {
+	if in == nil {
+		return nil
+	}
+	out := new(CoverageReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeliveryProbe) DeepCopyInto(out *DeliveryProbe) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeliveryProbe.
+func (in *DeliveryProbe) DeepCopy() *DeliveryProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(DeliveryProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeprovisioningEventRule) DeepCopyInto(out *DeprovisioningEventRule) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeprovisioningEventRule.
+func (in *DeprovisioningEventRule) DeepCopy() *DeprovisioningEventRule {
+	if in == nil {
+		return nil
+	}
+	out := new(DeprovisioningEventRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorBudget) DeepCopyInto(out *ErrorBudget) {
+	*out = *in
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorBudget.
+func (in *ErrorBudget) DeepCopy() *ErrorBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorBudgetStatus) DeepCopyInto(out *ErrorBudgetStatus) {
+	*out = *in
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]OperationBucket, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorBudgetStatus.
+func (in *ErrorBudgetStatus) DeepCopy() *ErrorBudgetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorBudgetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EscalationPolicyMapping) DeepCopyInto(out *EscalationPolicyMapping) {
+	*out = *in
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EscalationPolicyMapping.
+func (in *EscalationPolicyMapping) DeepCopy() *EscalationPolicyMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(EscalationPolicyMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventRule) DeepCopyInto(out *EventRule) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]EventRuleCondition, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventRule.
+func (in *EventRule) DeepCopy() *EventRule {
+	if in == nil {
+		return nil
+	}
+	out := new(EventRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventRuleCondition) DeepCopyInto(out *EventRuleCondition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventRuleCondition.
+func (in *EventRuleCondition) DeepCopy() *EventRuleCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(EventRuleCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetHygieneService) DeepCopyInto(out *FleetHygieneService) {
+	*out = *in
+	out.IntegrationKeySecretRef = in.IntegrationKeySecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetHygieneService.
+func (in *FleetHygieneService) DeepCopy() *FleetHygieneService {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetHygieneService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Heartbeat) DeepCopyInto(out *Heartbeat) {
+	*out = *in
+	out.Interval = in.Interval
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Heartbeat.
+func (in *Heartbeat) DeepCopy() *Heartbeat {
+	if in == nil {
+		return nil
+	}
+	out := new(Heartbeat)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IncidentUrgency) DeepCopyInto(out *IncidentUrgency) {
+	*out = *in
+	if in.SupportHours != nil {
+		in, out := &in.SupportHours, &out.SupportHours
+		*out = new(SupportHours)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncidentUrgency.
+func (in *IncidentUrgency) DeepCopy() *IncidentUrgency {
+	if in == nil {
+		return nil
+	}
+	out := new(IncidentUrgency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedEventRule) DeepCopyInto(out *ManagedEventRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedEventRule.
+func (in *ManagedEventRule) DeepCopy() *ManagedEventRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedEventRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationBucket) DeepCopyInto(out *OperationBucket) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationBucket.
+func (in *OperationBucket) DeepCopy() *OperationBucket {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationBucket)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrphanedService) DeepCopyInto(out *OrphanedService) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanedService.
+func (in *OrphanedService) DeepCopy() *OrphanedService {
+	if in == nil {
+		return nil
+	}
+	out := new(OrphanedService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrphanedServiceSweep) DeepCopyInto(out *OrphanedServiceSweep) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanedServiceSweep.
+func (in *OrphanedServiceSweep) DeepCopy() *OrphanedServiceSweep {
+	if in == nil {
+		return nil
+	}
+	out := new(OrphanedServiceSweep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyClusterConfig) DeepCopyInto(out *PagerDutyClusterConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyClusterConfig.
+func (in *PagerDutyClusterConfig) DeepCopy() *PagerDutyClusterConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyClusterConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PagerDutyClusterConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyClusterConfigList) DeepCopyInto(out *PagerDutyClusterConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PagerDutyClusterConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyClusterConfigList.
+func (in *PagerDutyClusterConfigList) DeepCopy() *PagerDutyClusterConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyClusterConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PagerDutyClusterConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyClusterConfigSpec) DeepCopyInto(out *PagerDutyClusterConfigSpec) {
+	*out = *in
+	out.ClusterDeploymentRef = in.ClusterDeploymentRef
+	if in.IncidentUrgency != nil {
+		in, out := &in.IncidentUrgency, &out.IncidentUrgency
+		*out = new(IncidentUrgency)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyClusterConfigSpec.
+func (in *PagerDutyClusterConfigSpec) DeepCopy() *PagerDutyClusterConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyClusterConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PagerDutyIntegration) DeepCopyInto(out *PagerDutyIntegration) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyIntegration.
+func (in *PagerDutyIntegration) DeepCopy() *PagerDutyIntegration {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyIntegration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PagerDutyIntegration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyIntegrationCondition) DeepCopyInto(out *PagerDutyIntegrationCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyIntegrationCondition.
+func (in *PagerDutyIntegrationCondition) DeepCopy() *PagerDutyIntegrationCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyIntegrationCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyIntegrationList) DeepCopyInto(out *PagerDutyIntegrationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PagerDutyIntegration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyIntegrationList.
+func (in *PagerDutyIntegrationList) DeepCopy() *PagerDutyIntegrationList {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyIntegrationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PagerDutyIntegrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyIntegrationSpec) DeepCopyInto(out *PagerDutyIntegrationSpec) {
+	*out = *in
+	if in.EscalationPolicyMapping != nil {
+		in, out := &in.EscalationPolicyMapping, &out.EscalationPolicyMapping
+		*out = new(EscalationPolicyMapping)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.IncidentUrgency != nil {
+		in, out := &in.IncidentUrgency, &out.IncidentUrgency
+		*out = new(IncidentUrgency)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AlertGrouping != nil {
+		in, out := &in.AlertGrouping, &out.AlertGrouping
+		*out = new(AlertGrouping)
+		(*in).DeepCopyInto(*out)
+	}
+	out.PagerdutyApiKeySecretRef = in.PagerdutyApiKeySecretRef
+	in.ClusterDeploymentSelector.DeepCopyInto(&out.ClusterDeploymentSelector)
+	if in.SelfServiceOnboarding != nil {
+		in, out := &in.SelfServiceOnboarding, &out.SelfServiceOnboarding
+		*out = new(SelfServiceOnboarding)
+		(*in).DeepCopyInto(*out)
+	}
+	out.TargetSecretRef = in.TargetSecretRef
+	if in.AdditionalTargetSecretRefs != nil {
+		in, out := &in.AdditionalTargetSecretRefs, &out.AdditionalTargetSecretRefs
+		*out = make([]corev1.SecretReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxSilenceDuration != nil {
+		in, out := &in.MaxSilenceDuration, &out.MaxSilenceDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DeliveryProbe != nil {
+		in, out := &in.DeliveryProbe, &out.DeliveryProbe
+		*out = new(DeliveryProbe)
+		**out = **in
+	}
+	if in.AlertmanagerConfig != nil {
+		in, out := &in.AlertmanagerConfig, &out.AlertmanagerConfig
+		*out = new(AlertmanagerConfig)
+		**out = **in
+	}
+	if in.SharedIntegrationKey != nil {
+		in, out := &in.SharedIntegrationKey, &out.SharedIntegrationKey
+		*out = new(SharedIntegrationKey)
+		**out = **in
+	}
+	if in.DeprovisioningEventRule != nil {
+		in, out := &in.DeprovisioningEventRule, &out.DeprovisioningEventRule
+		*out = new(DeprovisioningEventRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceTags != nil {
+		in, out := &in.ServiceTags, &out.ServiceTags
+		*out = new(ServiceTags)
+		**out = **in
+	}
+	if in.ServiceDependencies != nil {
+		in, out := &in.ServiceDependencies, &out.ServiceDependencies
+		*out = new(ServiceDependencies)
+		**out = **in
+	}
+	if in.FleetHygieneService != nil {
+		in, out := &in.FleetHygieneService, &out.FleetHygieneService
+		*out = new(FleetHygieneService)
+		**out = **in
+	}
+	if in.AuditPollInterval != nil {
+		in, out := &in.AuditPollInterval, &out.AuditPollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TestAlertInterval != nil {
+		in, out := &in.TestAlertInterval, &out.TestAlertInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ReinstallServiceRetention != nil {
+		in, out := &in.ReinstallServiceRetention, &out.ReinstallServiceRetention
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DeprovisionGracePeriod != nil {
+		in, out := &in.DeprovisionGracePeriod, &out.DeprovisionGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AccountMigration != nil {
+		in, out := &in.AccountMigration, &out.AccountMigration
+		*out = new(AccountMigration)
+		**out = **in
+	}
+	if in.EscrowSecretRef != nil {
+		in, out := &in.EscrowSecretRef, &out.EscrowSecretRef
+		*out = new(corev1.SecretReference)
+		**out = **in
+	}
+	if in.AdditionalServices != nil {
+		in, out := &in.AdditionalServices, &out.AdditionalServices
+		*out = make([]AdditionalService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AlertVolumeAnomaly != nil {
+		in, out := &in.AlertVolumeAnomaly, &out.AlertVolumeAnomaly
+		*out = new(AlertVolumeAnomaly)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceTuning != nil {
+		in, out := &in.ServiceTuning, &out.ServiceTuning
+		*out = new(ServiceTuning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OrphanedServiceSweep != nil {
+		in, out := &in.OrphanedServiceSweep, &out.OrphanedServiceSweep
+		*out = new(OrphanedServiceSweep)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ErrorBudget != nil {
+		in, out := &in.ErrorBudget, &out.ErrorBudget
+		*out = new(ErrorBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Heartbeat != nil {
+		in, out := &in.Heartbeat, &out.Heartbeat
+		*out = new(Heartbeat)
+		**out = **in
+	}
+	if in.CoverageReport != nil {
+		in, out := &in.CoverageReport, &out.CoverageReport
+		*out = new(CoverageReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResyncPeriod != nil {
+		in, out := &in.ResyncPeriod, &out.ResyncPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ErrorRequeueDelay != nil {
+		in, out := &in.ErrorRequeueDelay, &out.ErrorRequeueDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyIntegrationSpec.
+func (in *PagerDutyIntegrationSpec) DeepCopy() *PagerDutyIntegrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyIntegrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyIntegrationStatus) DeepCopyInto(out *PagerDutyIntegrationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]PagerDutyIntegrationCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ActiveSilences != nil {
+		in, out := &in.ActiveSilences, &out.ActiveSilences
+		*out = make([]ActiveSilence, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StaleSilences != nil {
+		in, out := &in.StaleSilences, &out.StaleSilences
+		*out = make([]StaleSilence, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]ClusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastAlertVolumeTime != nil {
+		in, out := &in.LastAlertVolumeTime, &out.LastAlertVolumeTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastServiceTuningTime != nil {
+		in, out := &in.LastServiceTuningTime, &out.LastServiceTuningTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastAuditPollTime != nil {
+		in, out := &in.LastAuditPollTime, &out.LastAuditPollTime
+		*out = (*in).DeepCopy()
+	}
+	if in.OrphanedServices != nil {
+		in, out := &in.OrphanedServices, &out.OrphanedServices
+		*out = make([]OrphanedService, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastOrphanedServiceSweepTime != nil {
+		in, out := &in.LastOrphanedServiceSweepTime, &out.LastOrphanedServiceSweepTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastCoverageReportTime != nil {
+		in, out := &in.LastCoverageReportTime, &out.LastCoverageReportTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ErrorBudget != nil {
+		in, out := &in.ErrorBudget, &out.ErrorBudget
+		*out = new(ErrorBudgetStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetainedServices != nil {
+		in, out := &in.RetainedServices, &out.RetainedServices
+		*out = make([]RetainedService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AccountMigration != nil {
+		in, out := &in.AccountMigration, &out.AccountMigration
+		*out = new(AccountMigrationStatus)
+		**out = **in
+	}
+	if in.Cleanup != nil {
+		in, out := &in.Cleanup, &out.Cleanup
+		*out = new(CleanupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyIntegrationStatus.
+func (in *PagerDutyIntegrationStatus) DeepCopy() *PagerDutyIntegrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyIntegrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyIntegrationTemplate) DeepCopyInto(out *PagerDutyIntegrationTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyIntegrationTemplate.
+func (in *PagerDutyIntegrationTemplate) DeepCopy() *PagerDutyIntegrationTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyIntegrationTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PagerDutyIntegrationTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyIntegrationTemplateList) DeepCopyInto(out *PagerDutyIntegrationTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PagerDutyIntegrationTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyIntegrationTemplateList.
+func (in *PagerDutyIntegrationTemplateList) DeepCopy() *PagerDutyIntegrationTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyIntegrationTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PagerDutyIntegrationTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyIntegrationTemplateSpec) DeepCopyInto(out *PagerDutyIntegrationTemplateSpec) {
+	*out = *in
+	if in.IncidentUrgency != nil {
+		in, out := &in.IncidentUrgency, &out.IncidentUrgency
+		*out = new(IncidentUrgency)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxSilenceDuration != nil {
+		in, out := &in.MaxSilenceDuration, &out.MaxSilenceDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DeprovisioningEventRule != nil {
+		in, out := &in.DeprovisioningEventRule, &out.DeprovisioningEventRule
+		*out = new(DeprovisioningEventRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceTags != nil {
+		in, out := &in.ServiceTags, &out.ServiceTags
+		*out = new(ServiceTags)
+		**out = **in
+	}
+	if in.FleetHygieneService != nil {
+		in, out := &in.FleetHygieneService, &out.FleetHygieneService
+		*out = new(FleetHygieneService)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyIntegrationTemplateSpec.
+func (in *PagerDutyIntegrationTemplateSpec) DeepCopy() *PagerDutyIntegrationTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyIntegrationTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyRuleset) DeepCopyInto(out *PagerDutyRuleset) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyRuleset.
+func (in *PagerDutyRuleset) DeepCopy() *PagerDutyRuleset {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyRuleset)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PagerDutyRuleset) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyRulesetList) DeepCopyInto(out *PagerDutyRulesetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PagerDutyRuleset, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyRulesetList.
+func (in *PagerDutyRulesetList) DeepCopy() *PagerDutyRulesetList {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyRulesetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PagerDutyRulesetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyRulesetSpec) DeepCopyInto(out *PagerDutyRulesetSpec) {
+	*out = *in
+	out.PagerDutyIntegrationRef = in.PagerDutyIntegrationRef
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]EventRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyRulesetSpec.
+func (in *PagerDutyRulesetSpec) DeepCopy() *PagerDutyRulesetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyRulesetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyRulesetStatus) DeepCopyInto(out *PagerDutyRulesetStatus) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]ManagedEventRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppliedRules != nil {
+		in, out := &in.AppliedRules, &out.AppliedRules
+		*out = new(AppliedEventRules)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreviousRules != nil {
+		in, out := &in.PreviousRules, &out.PreviousRules
+		*out = new(AppliedEventRules)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyRulesetStatus.
+func (in *PagerDutyRulesetStatus) DeepCopy() *PagerDutyRulesetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutyRulesetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutyService) DeepCopyInto(out *PagerDutyService) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyIntegration.
-func (in *PagerDutyIntegration) DeepCopy() *PagerDutyIntegration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyService.
+func (in *PagerDutyService) DeepCopy() *PagerDutyService {
 	if in == nil {
 		return nil
 	}
-	out := new(PagerDutyIntegration)
+	out := new(PagerDutyService)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PagerDutyIntegration) DeepCopyObject() runtime.Object {
+func (in *PagerDutyService) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -37,13 +1213,13 @@ func (in *PagerDutyIntegration) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PagerDutyIntegrationList) DeepCopyInto(out *PagerDutyIntegrationList) {
+func (in *PagerDutyServiceList) DeepCopyInto(out *PagerDutyServiceList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]PagerDutyIntegration, len(*in))
+		*out = make([]PagerDutyService, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -51,18 +1227,18 @@ func (in *PagerDutyIntegrationList) DeepCopyInto(out *PagerDutyIntegrationList)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyIntegrationList.
-func (in *PagerDutyIntegrationList) DeepCopy() *PagerDutyIntegrationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyServiceList.
+func (in *PagerDutyServiceList) DeepCopy() *PagerDutyServiceList {
 	if in == nil {
 		return nil
 	}
-	out := new(PagerDutyIntegrationList)
+	out := new(PagerDutyServiceList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PagerDutyIntegrationList) DeepCopyObject() runtime.Object {
+func (in *PagerDutyServiceList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -70,36 +1246,345 @@ func (in *PagerDutyIntegrationList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PagerDutyIntegrationSpec) DeepCopyInto(out *PagerDutyIntegrationSpec) {
+func (in *PagerDutyServiceSpec) DeepCopyInto(out *PagerDutyServiceSpec) {
 	*out = *in
-	out.PagerdutyApiKeySecretRef = in.PagerdutyApiKeySecretRef
-	in.ClusterDeploymentSelector.DeepCopyInto(&out.ClusterDeploymentSelector)
-	out.TargetSecretRef = in.TargetSecretRef
+	out.ClusterDeploymentRef = in.ClusterDeploymentRef
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyIntegrationSpec.
-func (in *PagerDutyIntegrationSpec) DeepCopy() *PagerDutyIntegrationSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyServiceSpec.
+func (in *PagerDutyServiceSpec) DeepCopy() *PagerDutyServiceSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PagerDutyIntegrationSpec)
+	out := new(PagerDutyServiceSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PagerDutyIntegrationStatus) DeepCopyInto(out *PagerDutyIntegrationStatus) {
+func (in *PagerDutyServiceStatus) DeepCopyInto(out *PagerDutyServiceStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ClusterCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastVerifiedTime != nil {
+		in, out := &in.LastVerifiedTime, &out.LastVerifiedTime
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyIntegrationStatus.
-func (in *PagerDutyIntegrationStatus) DeepCopy() *PagerDutyIntegrationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutyServiceStatus.
+func (in *PagerDutyServiceStatus) DeepCopy() *PagerDutyServiceStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PagerDutyIntegrationStatus)
+	out := new(PagerDutyServiceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutySilence) DeepCopyInto(out *PagerDutySilence) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutySilence.
+func (in *PagerDutySilence) DeepCopy() *PagerDutySilence {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutySilence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PagerDutySilence) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutySilenceList) DeepCopyInto(out *PagerDutySilenceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PagerDutySilence, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutySilenceList.
+func (in *PagerDutySilenceList) DeepCopy() *PagerDutySilenceList {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutySilenceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PagerDutySilenceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutySilenceSpec) DeepCopyInto(out *PagerDutySilenceSpec) {
+	*out = *in
+	out.ClusterDeploymentRef = in.ClusterDeploymentRef
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutySilenceSpec.
+func (in *PagerDutySilenceSpec) DeepCopy() *PagerDutySilenceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutySilenceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PagerDutySilenceStatus) DeepCopyInto(out *PagerDutySilenceStatus) {
+	*out = *in
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]SilenceMaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PagerDutySilenceStatus.
+func (in *PagerDutySilenceStatus) DeepCopy() *PagerDutySilenceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PagerDutySilenceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetainedService) DeepCopyInto(out *RetainedService) {
+	*out = *in
+	in.RetainedAt.DeepCopyInto(&out.RetainedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetainedService.
+func (in *RetainedService) DeepCopy() *RetainedService {
+	if in == nil {
+		return nil
+	}
+	out := new(RetainedService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelfServiceOnboarding) DeepCopyInto(out *SelfServiceOnboarding) {
+	*out = *in
+	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfServiceOnboarding.
+func (in *SelfServiceOnboarding) DeepCopy() *SelfServiceOnboarding {
+	if in == nil {
+		return nil
+	}
+	out := new(SelfServiceOnboarding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceDependencies) DeepCopyInto(out *ServiceDependencies) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceDependencies.
+func (in *ServiceDependencies) DeepCopy() *ServiceDependencies {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceDependencies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceSuggestion) DeepCopyInto(out *ServiceSuggestion) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceSuggestion.
+func (in *ServiceSuggestion) DeepCopy() *ServiceSuggestion {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceSuggestion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceTags) DeepCopyInto(out *ServiceTags) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceTags.
+func (in *ServiceTags) DeepCopy() *ServiceTags {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceTags)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceTuning) DeepCopyInto(out *ServiceTuning) {
+	*out = *in
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceTuning.
+func (in *ServiceTuning) DeepCopy() *ServiceTuning {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceTuning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedIntegrationKey) DeepCopyInto(out *SharedIntegrationKey) {
+	*out = *in
+	out.IntegrationKeySecretRef = in.IntegrationKeySecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedIntegrationKey.
+func (in *SharedIntegrationKey) DeepCopy() *SharedIntegrationKey {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedIntegrationKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SilenceMaintenanceWindow) DeepCopyInto(out *SilenceMaintenanceWindow) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SilenceMaintenanceWindow.
+func (in *SilenceMaintenanceWindow) DeepCopy() *SilenceMaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(SilenceMaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaleSilence) DeepCopyInto(out *StaleSilence) {
+	*out = *in
+	in.LiftedAt.DeepCopyInto(&out.LiftedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaleSilence.
+func (in *StaleSilence) DeepCopy() *StaleSilence {
+	if in == nil {
+		return nil
+	}
+	out := new(StaleSilence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupportHours) DeepCopyInto(out *SupportHours) {
+	*out = *in
+	if in.DaysOfWeek != nil {
+		in, out := &in.DaysOfWeek, &out.DaysOfWeek
+		*out = make([]uint, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportHours.
+func (in *SupportHours) DeepCopy() *SupportHours {
+	if in == nil {
+		return nil
+	}
+	out := new(SupportHours)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestAlertStatus) DeepCopyInto(out *TestAlertStatus) {
+	*out = *in
+	in.LastTestTime.DeepCopyInto(&out.LastTestTime)
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestAlertStatus.
+func (in *TestAlertStatus) DeepCopy() *TestAlertStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TestAlertStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -13,100 +13,244 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegration":       schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegration(ref),
-		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationSpec":   schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegrationSpec(ref),
-		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationStatus": schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegrationStatus(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AccountMigration":                 schema_pkg_apis_pagerduty_v1alpha1_AccountMigration(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AccountMigrationStatus":           schema_pkg_apis_pagerduty_v1alpha1_AccountMigrationStatus(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ActiveSilence":                    schema_pkg_apis_pagerduty_v1alpha1_ActiveSilence(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AdditionalService":                schema_pkg_apis_pagerduty_v1alpha1_AdditionalService(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AlertGrouping":                    schema_pkg_apis_pagerduty_v1alpha1_AlertGrouping(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AlertVolumeAnomaly":               schema_pkg_apis_pagerduty_v1alpha1_AlertVolumeAnomaly(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AlertVolumeStatus":                schema_pkg_apis_pagerduty_v1alpha1_AlertVolumeStatus(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AlertmanagerConfig":               schema_pkg_apis_pagerduty_v1alpha1_AlertmanagerConfig(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AppliedEventRules":                schema_pkg_apis_pagerduty_v1alpha1_AppliedEventRules(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.CleanupStatus":                    schema_pkg_apis_pagerduty_v1alpha1_CleanupStatus(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ClusterCondition":                 schema_pkg_apis_pagerduty_v1alpha1_ClusterCondition(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ClusterStatus":                    schema_pkg_apis_pagerduty_v1alpha1_ClusterStatus(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.CoverageReport":                   schema_pkg_apis_pagerduty_v1alpha1_CoverageReport(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.DeliveryProbe":                    schema_pkg_apis_pagerduty_v1alpha1_DeliveryProbe(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.DeprovisioningEventRule":          schema_pkg_apis_pagerduty_v1alpha1_DeprovisioningEventRule(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ErrorBudget":                      schema_pkg_apis_pagerduty_v1alpha1_ErrorBudget(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ErrorBudgetStatus":                schema_pkg_apis_pagerduty_v1alpha1_ErrorBudgetStatus(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.EscalationPolicyMapping":          schema_pkg_apis_pagerduty_v1alpha1_EscalationPolicyMapping(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.EventRule":                        schema_pkg_apis_pagerduty_v1alpha1_EventRule(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.EventRuleCondition":               schema_pkg_apis_pagerduty_v1alpha1_EventRuleCondition(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.FleetHygieneService":              schema_pkg_apis_pagerduty_v1alpha1_FleetHygieneService(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.Heartbeat":                        schema_pkg_apis_pagerduty_v1alpha1_Heartbeat(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.IncidentUrgency":                  schema_pkg_apis_pagerduty_v1alpha1_IncidentUrgency(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ManagedEventRule":                 schema_pkg_apis_pagerduty_v1alpha1_ManagedEventRule(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.OperationBucket":                  schema_pkg_apis_pagerduty_v1alpha1_OperationBucket(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.OrphanedService":                  schema_pkg_apis_pagerduty_v1alpha1_OrphanedService(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.OrphanedServiceSweep":             schema_pkg_apis_pagerduty_v1alpha1_OrphanedServiceSweep(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyClusterConfig":           schema_pkg_apis_pagerduty_v1alpha1_PagerDutyClusterConfig(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyClusterConfigSpec":       schema_pkg_apis_pagerduty_v1alpha1_PagerDutyClusterConfigSpec(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegration":             schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegration(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationCondition":    schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegrationCondition(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationSpec":         schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegrationSpec(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationStatus":       schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegrationStatus(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationTemplate":     schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegrationTemplate(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationTemplateSpec": schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegrationTemplateSpec(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyRuleset":                 schema_pkg_apis_pagerduty_v1alpha1_PagerDutyRuleset(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyRulesetSpec":             schema_pkg_apis_pagerduty_v1alpha1_PagerDutyRulesetSpec(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyRulesetStatus":           schema_pkg_apis_pagerduty_v1alpha1_PagerDutyRulesetStatus(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyService":                 schema_pkg_apis_pagerduty_v1alpha1_PagerDutyService(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyServiceSpec":             schema_pkg_apis_pagerduty_v1alpha1_PagerDutyServiceSpec(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyServiceStatus":           schema_pkg_apis_pagerduty_v1alpha1_PagerDutyServiceStatus(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutySilence":                 schema_pkg_apis_pagerduty_v1alpha1_PagerDutySilence(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutySilenceSpec":             schema_pkg_apis_pagerduty_v1alpha1_PagerDutySilenceSpec(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutySilenceStatus":           schema_pkg_apis_pagerduty_v1alpha1_PagerDutySilenceStatus(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.RetainedService":                  schema_pkg_apis_pagerduty_v1alpha1_RetainedService(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.SelfServiceOnboarding":            schema_pkg_apis_pagerduty_v1alpha1_SelfServiceOnboarding(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ServiceDependencies":              schema_pkg_apis_pagerduty_v1alpha1_ServiceDependencies(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ServiceSuggestion":                schema_pkg_apis_pagerduty_v1alpha1_ServiceSuggestion(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ServiceTags":                      schema_pkg_apis_pagerduty_v1alpha1_ServiceTags(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ServiceTuning":                    schema_pkg_apis_pagerduty_v1alpha1_ServiceTuning(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.SharedIntegrationKey":             schema_pkg_apis_pagerduty_v1alpha1_SharedIntegrationKey(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.SilenceMaintenanceWindow":         schema_pkg_apis_pagerduty_v1alpha1_SilenceMaintenanceWindow(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.StaleSilence":                     schema_pkg_apis_pagerduty_v1alpha1_StaleSilence(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.SupportHours":                     schema_pkg_apis_pagerduty_v1alpha1_SupportHours(ref),
+		"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.TestAlertStatus":                  schema_pkg_apis_pagerduty_v1alpha1_TestAlertStatus(ref),
 	}
 }
 
-func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_pagerduty_v1alpha1_AccountMigration(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PagerDutyIntegration is the Schema for the pagerdutyintegrations API",
+				Description: "AccountMigration configures the PagerDuty account clusters are migrated to",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"pagerdutyApiKeySecretRef": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Description: "Reference to the secret containing the PAGERDUTY_API_KEY of the account being migrated to.",
+							Ref:         ref("k8s.io/api/core/v1.SecretReference"),
+						},
+					},
+					"apiEndpoint": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL of the PagerDuty REST API of the account being migrated to, so clusters can move to another service region. Defaults to https://api.pagerduty.com.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"apiVersion": {
+					"escalationPolicy": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Description: "ID of an existing Escalation Policy in the account being migrated to.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"metadata": {
+					"secretKey": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+							Description: "Key of TargetSecretRef holding the integration key of the account being migrated to. Defaults to PAGERDUTY_KEY_MIGRATION.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"spec": {
+					"phase": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationSpec"),
+							Description: "Phase of the migration. \"DualWrite\", the default, keeps the services of both accounts. \"Decommission\" disables the services of the current account, then deletes them, a batch at a time. Once all are deleted, point PagerdutyApiKeySecretRef and EscalationPolicy to the new account and remove AccountMigration, the services of the new account then take over.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"status": {
+					"decommissionBatchSize": {
 						SchemaProps: spec.SchemaProps{
-							Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationStatus"),
+							Description: "How many services of the current account are disabled or deleted per reconcile in the Decommission phase. Defaults to 20.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
 				},
+				Required: []string{"pagerdutyApiKeySecretRef", "escalationPolicy"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationSpec", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"k8s.io/api/core/v1.SecretReference"},
 	}
 }
 
-func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegrationSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_pagerduty_v1alpha1_AccountMigrationStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PagerDutyIntegrationSpec defines the desired state of PagerDutyIntegration",
+				Description: "AccountMigrationStatus is the progress of the decommission of the services of the account being migrated from",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"acknowledgeTimeout": {
+					"clusters": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Time in seconds that an incident changes to the Triggered State after being Acknowledged. Value must not be negative. Omitting or setting this field to 0 will disable the feature.",
+							Description: "Number of selected clusters with a service in the account being migrated to.",
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
 					},
-					"escalationPolicy": {
+					"servicesDisabled": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ID of an existing Escalation Policy in PagerDuty.",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Number of services of the account being migrated from that are disabled and not deleted yet.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"resolveTimeout": {
+					"servicesDeleted": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Time in seconds that an incident is automatically resolved if left open for that long. Value must not be negative. Omitting or setting this field to 0 will disable the feature.",
+							Description: "Number of services of the account being migrated from that are deleted.",
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
 					},
+				},
+				Required: []string{"clusters", "servicesDisabled", "servicesDeleted"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_ActiveSilence(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ActiveSilence describes a cluster that is intentionally muted",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"clusterDeploymentNamespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace of the muted ClusterDeployment.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clusterDeploymentName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the muted ClusterDeployment.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"source": {
+						SchemaProps: spec.SchemaProps{
+							Description: "What muted the cluster: PagerDutySilence, NoalertsLabel, SilencedAnnotation or PagerDutyClusterConfig.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"silenceName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the PagerDutySilence muting the cluster, if any.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Why the cluster is muted, if known.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"requester": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Who muted the cluster, if known.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"expiresAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the silence expires. Unset when the silence has no expiry, as is the case for the noalerts label.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"clusterDeploymentNamespace", "clusterDeploymentName", "source"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_AdditionalService(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AdditionalService is a further PagerDuty service set up for the clusters it selects",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
 					"servicePrefix": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Prefix to set on the PagerDuty Service name.",
+							Description: "Prefix to set on the PagerDuty Service name. It must differ from the servicePrefix of this PagerDutyIntegration and of its other additional services.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"pagerdutyApiKeySecretRef": {
+					"escalationPolicy": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Reference to the secret containing PAGERDUTY_API_KEY.",
-							Ref:         ref("k8s.io/api/core/v1.SecretReference"),
+							Description: "ID of an existing Escalation Policy in PagerDuty.",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 					"clusterDeploymentSelector": {
 						SchemaProps: spec.SchemaProps{
-							Description: "A label selector used to find which clusterdeployment CRs receive a PD integration based on this configuration.",
+							Description: "A label selector used to find which clusterdeployment CRs receive this service.",
 							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
 						},
 					},
@@ -117,7 +261,7 @@ func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegrationSpec(ref common.Refe
 						},
 					},
 				},
-				Required: []string{"escalationPolicy", "servicePrefix", "pagerdutyApiKeySecretRef", "clusterDeploymentSelector", "targetSecretRef"},
+				Required: []string{"servicePrefix", "escalationPolicy", "clusterDeploymentSelector", "targetSecretRef"},
 			},
 		},
 		Dependencies: []string{
@@ -125,13 +269,2558 @@ func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegrationSpec(ref common.Refe
 	}
 }
 
-func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegrationStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_pagerduty_v1alpha1_AlertGrouping(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PagerDutyIntegrationStatus defines the observed state of PagerDutyIntegration",
+				Description: "AlertGrouping configures how the alerts of the PagerDuty services are grouped into incidents",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How alerts are grouped: intelligent, by PagerDuty's judgement of which are related, time, within a timeout of the first alert of an incident, or content_based, when they share the values of fields.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"timeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Minutes after the first alert of an incident during which the alerts are grouped into it, for time grouping. Omitting this field or setting it to 0 groups them until the incident is resolved.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"aggregate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether content_based alerts are grouped when all or any of fields have the same values. Defaults to all.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"fields": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Alert fields content_based alerts are grouped on, such as summary, component or custom_details.<name>. Required for content_based grouping.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"type"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_AlertVolumeAnomaly(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AlertVolumeAnomaly configures the flagging of clusters getting far more incidents than the fleet",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"window": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Period over which the incidents of each cluster are counted, which is also how often they are counted. Values below 6 hours are raised to 6 hours. Defaults to 24 hours.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"deviationFactor": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How many times the median incident count of the fleet a cluster must reach to be flagged. Defaults to 5.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_AlertVolumeStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AlertVolumeStatus is the incident count of a cluster compared to the fleet",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"incidents": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of incidents created on the cluster's PagerDuty service over the last window.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"anomalous": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether the cluster reached deviationFactor times the median incident count of the fleet.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"note": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How the cluster compares to the fleet, when it is anomalous.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"incidents"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_AlertmanagerConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AlertmanagerConfig configures the Alertmanager configuration synced to each cluster",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the object holding the configuration in the target cluster, under the alertmanager.yaml key.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace of the object in the target cluster.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind of the object: Secret, the default, or ConfigMap. The configuration holds the integration key, which a ConfigMap leaves readable to anyone allowed to read ConfigMaps in the namespace.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"receiver": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the receiver alerts are routed to. Defaults to pagerduty.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "namespace"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_AppliedEventRules(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AppliedEventRules is a version of the rules of a PagerDutyRuleset that was applied to all its clusters.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"checksum": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Checksum of the rules.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"rules": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The rules, as they were in the spec.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.EventRule"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"checksum"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.EventRule"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_CleanupStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CleanupStatus is the progress of the teardown of what a deleted PagerDutyIntegration set up",
 				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"clustersCleanedUp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of clusters torn down by the last attempt.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"clustersRemaining": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of clusters whose teardown failed and is retried.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"objectsRemaining": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of PagerDuty services, ConfigMaps, Secrets and SyncSets left once the clusters were torn down whose deletion failed and is retried.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Error of the last attempt, if it failed.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastAttemptTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time of the last attempt.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"clustersCleanedUp", "clustersRemaining", "objectsRemaining", "lastAttemptTime"},
 			},
 		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_ClusterCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ClusterCondition describes one aspect of the state of a cluster's PagerDuty integration",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type of the condition.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status of the condition: True, False or Unknown.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Machine readable reason for the last transition.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Human readable detail about the last transition.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the condition last changed status.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"type", "status"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_ClusterStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ClusterStatus is the observed state of the PagerDuty integration of one selected cluster",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"clusterDeploymentNamespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace of the ClusterDeployment.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clusterDeploymentName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the ClusterDeployment.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"serviceID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of the cluster's PagerDuty service, once created.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Summary of the conditions and retryReason: Ready, Pending, Failed or Deleting.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastError": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Why the cluster is Failed, taken from the error setting it up or the message of the failed condition.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"alertVolume": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Incidents of the cluster's PagerDuty service over the last window, when alertVolumeAnomaly is set.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AlertVolumeStatus"),
+						},
+					},
+					"suggestions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Suggestions to tune the cluster's PagerDuty service, when serviceTuning is set.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ServiceSuggestion"),
+									},
+								},
+							},
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions of the cluster's PagerDuty integration.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ClusterCondition"),
+									},
+								},
+							},
+						},
+					},
+					"retryReason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Why setting up the cluster's PagerDuty integration was skipped or will be retried, unset once it is complete.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastVerifiedTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the cluster's PagerDuty service was last verified. Verifications are staggered across the fleet, each cluster getting a fixed slot in the resync period.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"testAlert": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Result of the last synthetic test alert sent through the cluster's integration, when testAlertInterval is set.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.TestAlertStatus"),
+						},
+					},
+					"lastKeyRotationTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the integration key of the cluster was last rotated for the pd.openshift.io/rotate-integration-key annotation.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"clusterDeploymentNamespace", "clusterDeploymentName"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AlertVolumeStatus", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ClusterCondition", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ServiceSuggestion", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.TestAlertStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_CoverageReport(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CoverageReport configures the periodic report of the paging coverage of the selected clusters",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"interval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How often the report is written. Values below 1 hour are raised to 1 hour. Defaults to 24 hours.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"changeEventSecretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reference to the secret containing the PAGERDUTY_KEY of an Events API v2 integration of a service sent a change event summarizing each report. Omitting this field sends none.",
+							Ref:         ref("k8s.io/api/core/v1.SecretReference"),
+						},
+					},
+					"webhookURL": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL each report is posted to as JSON. Omitting this field posts none.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.SecretReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_DeliveryProbe(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DeliveryProbe configures the CronJob verifying the integration key on each cluster",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Image the probe runs, it must provide sh, curl and oc.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"schedule": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Schedule of the probe in cron format. Defaults to every 6 hours.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"image"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_DeprovisioningEventRule(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DeprovisioningEventRule configures the rule suppressing the events of clusters being deprovisioned",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"rulesetID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of the PagerDuty global ruleset the rule is added to.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clusterIDDetail": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Custom detail of the events holding the cluster ID. Events whose detail equals the clusterName of the ClusterDeployment are suppressed. Defaults to cluster_id.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"duration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How long the rule stays active once deprovisioning starts, after which it no longer matches and is cleaned up. Defaults to 2 hours.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+				Required: []string{"rulesetID"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_ErrorBudget(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ErrorBudget configures the accounting of the per-cluster operations against an objective",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"window": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Rolling period over which the operations are accounted. Values below 1 hour are raised to 1 hour. Defaults to 7 days.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"objective": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Percentage of the operations that must succeed, such as \"99.5\". The failures it allows are the error budget. Defaults to \"99\".",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_ErrorBudgetStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ErrorBudgetStatus is the outcome of the per-cluster operations over the window of the error budget",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"succeeded": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of operations that succeeded over the window.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"failed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of operations that failed over the window.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"successRatio": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Share of the operations over the window that succeeded, from 0 to 1. It is 1 when there were none.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"remaining": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Share of the error budget left, 1 when no operation failed and negative once it is exhausted.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"buckets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Operation counts of each period of the window, oldest first, that roll the window forward.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.OperationBucket"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"succeeded", "failed", "successRatio", "remaining"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.OperationBucket"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_EscalationPolicyMapping(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "EscalationPolicyMapping maps the values of a ClusterDeployment label to escalation policies",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"labelKey": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Key of the ClusterDeployment label whose value picks the policy.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"policies": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of an existing Escalation Policy in PagerDuty for each value of the label.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"labelKey", "policies"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_EventRule(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "EventRule describes how the events of a cluster matching its conditions are handled.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the rule, unique within the PagerDutyRuleset.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions on the custom details of the events, such as the alert labels, that all must hold for the rule to apply.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.EventRuleCondition"),
+									},
+								},
+							},
+						},
+					},
+					"route": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Route the matching events to the cluster's PagerDuty service.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"suppress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Suppress the matching events, so their alerts page nobody.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"severity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Severity overriding the one of the matching events.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.EventRuleCondition"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_EventRuleCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "EventRuleCondition matches a custom detail of an event.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"label": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the custom detail, such as an alert label like \"alertname\".",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"operator": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How the custom detail is compared to the value. \"matches\" takes a regular expression, \"exists\" ignores the value.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Value the custom detail is compared to.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"label", "operator"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_FleetHygieneService(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "FleetHygieneService is the PagerDuty service informed of repaired drift",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"integrationKeySecretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reference to the secret containing the PAGERDUTY_KEY of an Events API v2 integration of the service.",
+							Ref:         ref("k8s.io/api/core/v1.SecretReference"),
+						},
+					},
+				},
+				Required: []string{"integrationKeySecretRef"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.SecretReference"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_Heartbeat(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Heartbeat configures the check-ins expected from each cluster",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL the clusters reach the heartbeat endpoint of the operator at, such as the https URL of a Route to the pagerduty-operator-heartbeat Service. The URL a cluster checks in at is this URL followed by a path identifying the cluster.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"interval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How often each cluster is expected to check in. A cluster that did not check in for twice this long is paged on. Values below 5 minutes are raised to 5 minutes.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"secretKey": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Key of the synced secret holding the URL the cluster checks in at, PAGERDUTY_HEARTBEAT_URL by default.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"url", "interval"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_IncidentUrgency(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "IncidentUrgency configures the urgency rule of the PagerDuty services",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"urgency": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Urgency of the incidents, or of those raised during support hours when supportHours is set. Defaults to severity_based.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"outsideSupportHoursUrgency": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Urgency of the incidents raised outside support hours, when supportHours is set. Defaults to low.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"supportHours": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Support hours of the services. Omitting this field applies urgency at all times.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.SupportHours"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.SupportHours"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_ManagedEventRule(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ManagedEventRule records a PagerDuty ruleset rule created for one cluster from one of the rules of a PagerDutyRuleset.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the rule of the PagerDutyRuleset.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clusterDeploymentName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the cluster's ClusterDeployment.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clusterDeploymentNamespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace of the cluster's ClusterDeployment.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"rulesetID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of the PagerDuty global ruleset holding the rule.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ruleID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of the PagerDuty ruleset rule.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"checksum": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Checksum of the rule as last written to PagerDuty, it is updated when it changes.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "clusterDeploymentName", "clusterDeploymentNamespace", "rulesetID", "ruleID", "checksum"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_OperationBucket(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "OperationBucket counts the per-cluster operations of one period",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"start": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the period starts.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"succeeded": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of operations of the period that succeeded.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"failed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of operations of the period that failed.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"start"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_OrphanedService(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "OrphanedService is a PagerDuty service whose cluster no longer exists",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"serviceID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of the PagerDuty service.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the PagerDuty service.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"serviceID", "name"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_OrphanedServiceSweep(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "OrphanedServiceSweep configures the sweep of the PagerDuty services whose cluster no longer exists",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"action": {
+						SchemaProps: spec.SchemaProps{
+							Description: "What is done with each orphaned service: Report lists it in status.orphanedServices, Delete deletes it. Defaults to Report.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"interval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How often the account is swept. Values below 1 hour are raised to 1 hour. Defaults to 24 hours.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyClusterConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PagerDutyClusterConfig overrides the PagerDuty settings of a single cluster",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyClusterConfigSpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyClusterConfigSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyClusterConfigSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PagerDutyClusterConfigSpec overrides, for a single cluster, settings of the PagerDutyIntegrations selecting it. Fields left empty keep the setting of the PagerDutyIntegration.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"clusterDeploymentRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reference to the ClusterDeployment, in the same namespace as the PagerDutyClusterConfig, whose PagerDuty services are configured.",
+							Ref:         ref("k8s.io/api/core/v1.LocalObjectReference"),
+						},
+					},
+					"pagerDutyIntegration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the PagerDutyIntegration whose settings are overridden. Omitting this field overrides those of every PagerDutyIntegration selecting the cluster.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"escalationPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of the escalation policy of the cluster's PagerDuty service, instead of the one of the PagerDutyIntegration.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"incidentUrgency": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Urgency of the incidents of the cluster's PagerDuty service, instead of the incidentUrgency of the PagerDutyIntegration.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.IncidentUrgency"),
+						},
+					},
+					"disablePaging": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Disable the cluster's PagerDuty service, so the cluster doesn't page, until this field is unset or the PagerDutyClusterConfig is deleted.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"clusterDeploymentRef"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.IncidentUrgency", "k8s.io/api/core/v1.LocalObjectReference"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PagerDutyIntegration is the Schema for the pagerdutyintegrations API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationSpec", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegrationCondition(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PagerDutyIntegrationCondition describes one aspect of the state of a PagerDutyIntegration as a whole",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type of the condition.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status of the condition: True, False or Unknown.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Machine readable reason for the last transition.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Human readable detail about the last transition.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the condition last changed status.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"type", "status"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegrationSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PagerDutyIntegrationSpec defines the desired state of PagerDutyIntegration",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"acknowledgeTimeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time in seconds that an incident changes to the Triggered State after being Acknowledged. Value must not be negative. Omitting or setting this field to 0 will disable the feature.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"escalationPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of an existing Escalation Policy in PagerDuty.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"escalationPolicyMapping": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Escalation policies picked by the value of a label of each selected ClusterDeployment, such as tier=gold or tier=silver, so one PagerDutyIntegration serves every tier. Clusters without the label, or whose value is not mapped, get escalationPolicy. Services whose policy no longer matches the label are set to the mapped one when verified. Omitting this field gives every cluster escalationPolicy.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.EscalationPolicyMapping"),
+						},
+					},
+					"team": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID or name of the PagerDuty team the service of each cluster is assigned to, so team-scoped PagerDuty users see it. A service the team is removed from is assigned to it again when verified, other teams of the service are left alone. Omitting this field leaves the teams of services alone.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resolveTimeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time in seconds that an incident is automatically resolved if left open for that long. Value must not be negative. Omitting or setting this field to 0 will disable the feature.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"templateRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PagerDutyIntegrationTemplate, in the namespace of this PagerDutyIntegration, whose settings are inherited. A field set on this PagerDutyIntegration overrides the one of the template as a whole, a timeout of 0 inherits the one of the template. Omitting this field inherits nothing.",
+							Ref:         ref("k8s.io/api/core/v1.LocalObjectReference"),
+						},
+					},
+					"incidentUrgency": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Urgency of the incidents of the PagerDuty service of each cluster, optionally depending on support hours. Services whose urgency drifted are set back when verified. Omitting this field makes the urgency follow the severity of the incidents.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.IncidentUrgency"),
+						},
+					},
+					"alertGrouping": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How the alerts of the PagerDuty service of each cluster are grouped into incidents, so a noisy cluster doesn't open an incident per alert. Services whose grouping drifted are set back when verified. Omitting this field leaves the grouping of the services alone.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AlertGrouping"),
+						},
+					},
+					"servicePrefix": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Prefix to set on the PagerDuty Service name.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"normalizeServiceNames": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Normalize the names of the PagerDuty services: lower case, with any run of characters other than ASCII letters, digits, '-' and '.' replaced with a '-', and names longer than PagerDuty accepts truncated and suffixed with a hash of the full name. Existing services still named as before are renamed when verified.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"serviceNameTemplate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Go template naming the PagerDuty service of each cluster instead of <servicePrefix>-<cluster name>.<base domain>-hive-cluster, such as \"{{.Prefix}}-{{.ClusterID}}-{{.Labels.environment}}\". It is executed with .Prefix, .ClusterID, .BaseDomain and the .Labels of the ClusterDeployment. Clusters missing a label the template refers to aren't set up. Existing services still named as before are renamed when verified.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"integrationType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type of the integration created on the PagerDuty service of each cluster, whose key is synced to the cluster, for receivers that need one other than Events API v2: \"events_api_v2\", the default, \"prometheus\", or \"generic_events_api\" for the Events API v1. A change applies to the integrations created from then on, rotating the keys switches existing clusters.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pagerdutyApiKeySecretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reference to the secret containing PAGERDUTY_API_KEY.",
+							Ref:         ref("k8s.io/api/core/v1.SecretReference"),
+						},
+					},
+					"apiEndpoint": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL of the PagerDuty REST API of the account, such as https://api.eu.pagerduty.com for an account in the EU service region, or of a staging or mock API. Defaults to https://api.pagerduty.com.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clusterDeploymentSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A label selector used to find which clusterdeployment CRs receive a PD integration based on this configuration.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"selfServiceOnboarding": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Lets ClusterDeployments opt into this PagerDutyIntegration, whatever their labels, by setting the pd.managed.openshift.io/integration annotation to its name, so teams can request paging without a change of the labels by the hub admins. Omitting this field ignores the annotation.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.SelfServiceOnboarding"),
+						},
+					},
+					"targetSecretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name and namespace in the target cluster where the secret is synced.",
+							Ref:         ref("k8s.io/api/core/v1.SecretReference"),
+						},
+					},
+					"additionalTargetSecretRefs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "More names and namespaces in the target cluster the secret is synced to by the same SyncSet, such as a customer namespace or the secret of user-workload monitoring. They are delivered like TargetSecretRef.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.SecretReference"),
+									},
+								},
+							},
+						},
+					},
+					"secretDeliveryMode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How the integration key is delivered to TargetSecretRef. \"Secret\", the default, syncs a standalone secret. \"Patch\" merges the key into an existing secret, for clusters where monitoring config is a single aggregated secret.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"secretType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type of the secret synced to TargetSecretRef, Opaque by default. Ignored in Patch mode, where the secret already exists.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"immutableSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Make the secret synced to TargetSecretRef immutable. An immutable secret cannot be updated, so it is named after TargetSecretRef with a hash of the integration key appended, and a new key is delivered in a new secret replacing the previous one. Ignored in Patch mode.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"maxSilenceDuration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Longest time a selected cluster may stay muted, by a PagerDutySilence or the noalerts label. Once exceeded the silence is considered stale and alerting is re-enabled. Omitting this field disables the feature.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"deliveryProbe": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Sync a CronJob to each cluster that checks the integration key was delivered and events.pagerduty.com is reachable, and report its result in the DeliveryVerificationFailed condition of the cluster. Omitting this field disables the probe.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.DeliveryProbe"),
+						},
+					},
+					"alertmanagerConfig": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Sync to each cluster an Alertmanager configuration with a receiver sending all alerts to the cluster's PagerDuty service, so the in-cluster Alertmanager is wired to it without manual configuration. Omitting this field only syncs the integration key.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AlertmanagerConfig"),
+						},
+					},
+					"sharedIntegrationKey": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Deliver the integration key of a single PagerDuty service to every selected cluster through one Hive SelectorSyncSet matching ClusterDeploymentSelector, instead of creating a service and a SyncSet per cluster. Clusters set up on their own are torn down, and the per-cluster features such as silences and verification don't apply. Omitting this field sets up each cluster on its own.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.SharedIntegrationKey"),
+						},
+					},
+					"deprovisioningEventRule": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Add a rule to a PagerDuty global ruleset suppressing the events of a cluster once its ClusterDeployment is deleted, so the alerts raised while it tears itself down page nobody. Omitting this field disables the rule.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.DeprovisioningEventRule"),
+						},
+					},
+					"serviceTags": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ownership tags set on the PagerDuty service of each cluster and reconciled on every resync, so PagerDuty reporting can be sliced by ownership. Omitting this field leaves the tags of services alone.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ServiceTags"),
+						},
+					},
+					"serviceDependencies": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PagerDuty services the service of each cluster is related to in the service graph of PagerDuty, registered as service dependencies and reconciled on every resync, so responders see which clusters an incident of the hub affects. Dependencies removed from this field are left in PagerDuty. Omitting this field registers none.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ServiceDependencies"),
+						},
+					},
+					"fleetHygieneService": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PagerDuty service sent a change event whenever the settings of a cluster's service are found to have drifted from this PagerDutyIntegration and are repaired. Omitting this field still repairs drift, without reporting it.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.FleetHygieneService"),
+						},
+					},
+					"auditPollInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How often the PagerDuty audit records are polled for changes made to the services of the selected clusters outside of the operator, such as a service disabled by hand. Each change is reported as a Warning event on this PagerDutyIntegration. Values below 15 minutes are raised to 15 minutes. Omitting this field disables the poller.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"testAlertInterval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How often a synthetic test alert is triggered and resolved right away through the integration of each selected cluster, with the result recorded in the testAlert of the cluster's status as evidence of paging coverage. Values below 1 hour are raised to 1 hour. Omitting this field disables test alerts.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"reinstallServiceRetention": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How long the PagerDuty service and integration key of a deleted cluster are kept, so a cluster reinstalled with the same ClusterDeployment namespace, name and cluster name reuses them and keeps its incident history. Services not reused in time are deleted. Omitting this field deletes the service along with the cluster.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"deprovisionGracePeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How long the PagerDuty service of a deleted cluster is kept disabled before it is deleted, so the accidental deletion of a ClusterDeployment doesn't wipe its incident history right away. The service is recorded in status.retainedServices meanwhile. Omitting this field deletes the service along with the cluster.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"serviceDeletionPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "What is done with the PagerDuty service of a deleted cluster: Delete deletes it, once reinstallServiceRetention and deprovisionGracePeriod are over if set, Retain leaves it as is and Disable disables it, both keeping its incident history in PagerDuty. Defaults to Delete.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"accountMigration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PagerDuty account the clusters are being migrated to. While set, each selected cluster also gets a service in that account, and its integration key is synced to TargetSecretRef next to the one of the current account, so alerting can switch accounts without a gap. Omitting this field uses the current account only.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AccountMigration"),
+						},
+					},
+					"escrowSecretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Secret on the hub a copy of the integration key of every selected cluster is written to, under the key <namespace>.<name> of the cluster's ClusterDeployment, so SREs can still retrieve a key when Hive sync is broken, without PagerDuty API access. Omitting this field keeps no copy.",
+							Ref:         ref("k8s.io/api/core/v1.SecretReference"),
+						},
+					},
+					"additionalServices": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Further PagerDuty services set up for the clusters each one selects, next to the service of this PagerDutyIntegration, for example one paging the customer in addition to SRE. Each service is tracked in its own ConfigMap, Secret and SyncSet and is deleted with the cluster. Only the timeouts, team, incidentUrgency, alertGrouping, normalizeServiceNames and serviceTags apply to them, the other features only apply to the service of this PagerDutyIntegration.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AdditionalService"),
+									},
+								},
+							},
+						},
+					},
+					"alertVolumeAnomaly": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Flag the selected clusters whose PagerDuty service gets far more incidents than the rest of the fleet, from the PagerDuty analytics polled a few times a day, so noisy clusters can be found. Omitting this field disables the check.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AlertVolumeAnomaly"),
+						},
+					},
+					"serviceTuning": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Suggest how to tune the PagerDuty service of each selected cluster, such as enabling intelligent alert grouping, from its incidents listed every window. Suggestions are reported in status.clusters and as events, they are never applied. Omitting this field disables the suggestions.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ServiceTuning"),
+						},
+					},
+					"orphanedServiceSweep": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Sweep the PagerDuty account for services named after servicePrefix whose cluster no longer exists, such as those left behind when the teardown of a cluster fails. Omitting this field disables the sweep.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.OrphanedServiceSweep"),
+						},
+					},
+					"errorBudget": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Account the attempts to set up, tear down and verify the selected clusters against an objective over a rolling window, reported in status.errorBudget, so SLOs can be set on the provisioning of paging itself. Omitting this field disables the accounting.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ErrorBudget"),
+						},
+					},
+					"heartbeat": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Create a heartbeat integration on the PagerDuty service of each selected cluster, and sync the URL the cluster is expected to check in at to TargetSecretRef. A cluster that stops checking in, such as one whose Alertmanager stopped, is paged on through the heartbeat integration. Omitting this field disables heartbeats.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.Heartbeat"),
+						},
+					},
+					"coverageReport": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Write a report of the paging coverage of the selected clusters, which of them have an active PagerDuty service, are silenced or failed, to a ConfigMap every interval, and optionally send it on as a change event or to a webhook. Omitting this field disables the report.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.CoverageReport"),
+						},
+					},
+					"resyncPeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How often the PagerDuty service of each selected cluster is verified when nothing changed, the verifications being spread over the fleet. Large fleets may want a slower resync, dev environments a faster one. Values below 5 minutes are raised to 5 minutes. Omitting this field uses the --resync-period of the operator.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"errorRequeueDelay": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How long to wait before reconciling again once a call to the PagerDuty API failed with a server error or the API could not be reached, after the call itself was retried. Omitting this field uses the --pagerduty-error-requeue-delay of the operator.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+				Required: []string{"escalationPolicy", "servicePrefix", "pagerdutyApiKeySecretRef", "clusterDeploymentSelector", "targetSecretRef"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AccountMigration", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AdditionalService", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AlertGrouping", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AlertVolumeAnomaly", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AlertmanagerConfig", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.CoverageReport", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.DeliveryProbe", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.DeprovisioningEventRule", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ErrorBudget", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.EscalationPolicyMapping", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.FleetHygieneService", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.Heartbeat", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.IncidentUrgency", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.OrphanedServiceSweep", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.SelfServiceOnboarding", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ServiceDependencies", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ServiceTags", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ServiceTuning", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.SharedIntegrationKey", "k8s.io/api/core/v1.LocalObjectReference", "k8s.io/api/core/v1.SecretReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegrationStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PagerDutyIntegrationStatus defines the observed state of PagerDutyIntegration",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions of the PagerDutyIntegration that are not specific to one cluster.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationCondition"),
+									},
+								},
+							},
+						},
+					},
+					"activeSilences": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Clusters selected by this PagerDutyIntegration that are currently intentionally muted, by a PagerDutySilence or the noalerts label.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ActiveSilence"),
+									},
+								},
+							},
+						},
+					},
+					"staleSilences": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Clusters selected by this PagerDutyIntegration whose silence outlived maxSilenceDuration and was lifted by the operator.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.StaleSilence"),
+									},
+								},
+							},
+						},
+					},
+					"clusters": {
+						SchemaProps: spec.SchemaProps{
+							Description: "State of the PagerDuty integration of each installed cluster selected by this PagerDutyIntegration.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ClusterStatus"),
+									},
+								},
+							},
+						},
+					},
+					"readyClusters": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of clusters in status.clusters in the Ready state.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"pendingClusters": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of clusters in status.clusters in the Pending state.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"failedClusters": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of clusters in status.clusters in the Failed state.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"anomalousClusters": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of clusters in status.clusters whose alert volume is anomalous.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"secretSyncFailedClusters": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of clusters in status.clusters Hive failed to apply the SyncSet of the integration key to, whose SyncSetFailed condition is True.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"lastAlertVolumeTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the incidents of the clusters were last counted, when alertVolumeAnomaly is set.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"lastServiceTuningTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the suggestions to tune the services of the clusters were last computed, when serviceTuning is set.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"lastAuditPollTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time up to which the PagerDuty audit records were polled, when auditPollInterval is set.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"orphanedServices": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PagerDuty services named after servicePrefix whose cluster no longer exists, found by the last sweep when orphanedServiceSweep is set. Deleted services are only listed if their deletion failed.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.OrphanedService"),
+									},
+								},
+							},
+						},
+					},
+					"lastOrphanedServiceSweepTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the PagerDuty account was last swept for orphaned services, when orphanedServiceSweep is set.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"lastCoverageReportTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the last coverage report was written, when coverageReport is set.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"errorBudget": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Outcome of the per-cluster operations over the window, when errorBudget is set.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ErrorBudgetStatus"),
+						},
+					},
+					"retainedServices": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PagerDuty services of deleted clusters kept for a reinstall to reuse, when reinstallServiceRetention is set, or until their deprovisionGracePeriod is over.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.RetainedService"),
+									},
+								},
+							},
+						},
+					},
+					"accountMigration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Progress of the decommission of the services of the account being migrated from, when accountMigration is set.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AccountMigrationStatus"),
+						},
+					},
+					"cleanup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Progress of the teardown of what the PagerDutyIntegration set up, once it is deleted. The finalizer stays until all of it is torn down.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.CleanupStatus"),
+						},
+					},
+					"servicePrefix": {
+						SchemaProps: spec.SchemaProps{
+							Description: "servicePrefix the secondary resources and PagerDuty services of the clusters are named after. While it differs from spec.servicePrefix, they are being renamed to it.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AccountMigrationStatus", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ActiveSilence", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.CleanupStatus", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ClusterStatus", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ErrorBudgetStatus", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.OrphanedService", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationCondition", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.RetainedService", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.StaleSilence", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegrationTemplate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PagerDutyIntegrationTemplate holds settings shared by several PagerDutyIntegrations",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationTemplateSpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyIntegrationTemplateSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyIntegrationTemplateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PagerDutyIntegrationTemplateSpec defines the settings inherited by the PagerDutyIntegrations referring to the template. Each field has the meaning of the PagerDutyIntegration field of the same name.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"acknowledgeTimeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time in seconds that an incident changes to the Triggered State after being Acknowledged.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"resolveTimeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time in seconds that an incident is automatically resolved if left open for that long.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"incidentUrgency": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Urgency of the incidents of the PagerDuty service of each cluster.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.IncidentUrgency"),
+						},
+					},
+					"maxSilenceDuration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Longest time a selected cluster may stay muted.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"deprovisioningEventRule": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Rule of a PagerDuty global ruleset suppressing the events of deleted clusters.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.DeprovisioningEventRule"),
+						},
+					},
+					"serviceTags": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ownership tags set on the PagerDuty service of each cluster.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ServiceTags"),
+						},
+					},
+					"fleetHygieneService": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PagerDuty service sent a change event whenever drift is repaired.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.FleetHygieneService"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.DeprovisioningEventRule", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.FleetHygieneService", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.IncidentUrgency", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ServiceTags", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyRuleset(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PagerDutyRuleset manages PagerDuty ruleset rules routing, suppressing or overriding the severity of the events of the clusters of a PagerDutyIntegration",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyRulesetSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyRulesetStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyRulesetSpec", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyRulesetStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyRulesetSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PagerDutyRulesetSpec defines the desired state of PagerDutyRuleset",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"pagerDutyIntegrationRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reference to the PagerDutyIntegration, in the same namespace as the PagerDutyRuleset, whose clusters the rules apply to. Its API key manages the rules.",
+							Ref:         ref("k8s.io/api/core/v1.LocalObjectReference"),
+						},
+					},
+					"rulesetID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of the PagerDuty global ruleset the rules are added to.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clusterIDDetail": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the custom detail of the events holding the cluster's ID, \"cluster_id\" by default.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"rules": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Rules added for each cluster of the PagerDutyIntegration that has a PagerDuty service. Each only matches the events of its cluster.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.EventRule"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"pagerDutyIntegrationRef", "rulesetID", "rules"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.EventRule", "k8s.io/api/core/v1.LocalObjectReference"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyRulesetStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PagerDutyRulesetStatus defines the observed state of PagerDutyRuleset",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"rules": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PagerDuty ruleset rules managed for the PagerDutyRuleset.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ManagedEventRule"),
+									},
+								},
+							},
+						},
+					},
+					"appliedRules": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Rules last applied to all the clusters.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AppliedEventRules"),
+						},
+					},
+					"previousRules": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Rules applied before appliedRules, which the rollback annotation restores.",
+							Ref:         ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AppliedEventRules"),
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Human readable detail about the last reconcile of the ruleset.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.AppliedEventRules", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ManagedEventRule"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyService(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PagerDutyService records the PagerDuty service of a cluster, replacing the SERVICE_ID and INTEGRATION_ID of its ConfigMap. It is created and kept up to date by the operator, and shares the name of the ConfigMap.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyServiceSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyServiceStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyServiceSpec", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutyServiceStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyServiceSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PagerDutyServiceSpec defines the desired state of PagerDutyService",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"clusterDeploymentRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reference to the ClusterDeployment, in the same namespace as the PagerDutyService, that the PagerDuty service pages for.",
+							Ref:         ref("k8s.io/api/core/v1.LocalObjectReference"),
+						},
+					},
+					"pagerDutyIntegration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the PagerDutyIntegration that created the PagerDuty service, <name>.<servicePrefix> for its additional services.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"serviceID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of the PagerDuty service.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"integrationID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of the integration of the PagerDuty service whose key is delivered to the cluster.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"clusterDeploymentRef", "pagerDutyIntegration", "serviceID", "integrationID"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.LocalObjectReference"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_PagerDutyServiceStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PagerDutyServiceStatus defines the observed state of PagerDutyService",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Summary of the conditions and retryReason: Ready, Pending, Failed or Deleting.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastError": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Why the cluster is Failed, taken from the error setting it up or the message of the failed condition.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"retryReason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Why setting up the cluster's PagerDuty integration was skipped or will be retried, unset once it is complete.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions of the cluster's PagerDuty integration.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ClusterCondition"),
+									},
+								},
+							},
+						},
+					},
+					"lastVerifiedTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the PagerDuty service was last verified.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.ClusterCondition", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_PagerDutySilence(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PagerDutySilence temporarily removes a cluster from paging",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutySilenceSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutySilenceStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutySilenceSpec", "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.PagerDutySilenceStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_PagerDutySilenceSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PagerDutySilenceSpec defines the desired state of PagerDutySilence",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"clusterDeploymentRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reference to the ClusterDeployment, in the same namespace as the PagerDutySilence, whose PagerDuty services are silenced.",
+							Ref:         ref("k8s.io/api/core/v1.LocalObjectReference"),
+						},
+					},
+					"duration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How long the cluster stays silenced, counted from the creation of the PagerDutySilence. For example \"2h\" or \"90m\".",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Why the cluster is silenced.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"requester": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Who requested the silence.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"clusterDeploymentRef", "duration", "reason", "requester"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.LocalObjectReference", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_PagerDutySilenceStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PagerDutySilenceStatus defines the observed state of PagerDutySilence",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phase of the silence: Pending, Active or Expired.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"expiresAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the silence expires and paging is restored.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"maintenanceWindows": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PagerDuty maintenance windows enforcing the silence.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.SilenceMaintenanceWindow"),
+									},
+								},
+							},
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Human readable detail about the last reconcile of the silence.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1.SilenceMaintenanceWindow", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_RetainedService(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RetainedService is the PagerDuty service of a deleted cluster kept for a reinstall of the cluster to reuse",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"clusterDeploymentNamespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace of the deleted ClusterDeployment.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clusterDeploymentName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the deleted ClusterDeployment.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clusterID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Cluster name of the deleted ClusterDeployment.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"serviceID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of the PagerDuty service.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"integrationID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of the integration of the PagerDuty service.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"retainedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the cluster was deleted.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"disabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether the PagerDuty service was disabled for the deprovisionGracePeriod. A reinstall reusing it enables it again.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"clusterDeploymentNamespace", "clusterDeploymentName", "clusterID", "serviceID", "integrationID", "retainedAt"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_SelfServiceOnboarding(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SelfServiceOnboarding configures which ClusterDeployments may opt into a PagerDutyIntegration with an annotation",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespaceSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Selects the namespaces whose ClusterDeployments may opt in. The validating webhook rejects the annotation of the others, and the operator ignores it. Omitting this field or leaving it empty allows every namespace.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_ServiceDependencies(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ServiceDependencies are the PagerDuty services related to the services of the clusters",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"hubServiceID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of the PagerDuty technical service of the hub cluster, which the service of each cluster depends on.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"businessServiceID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of a PagerDuty business service, such as the offering the fleet provides, depending on the service of each cluster.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_ServiceSuggestion(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ServiceSuggestion is a change suggested to the PagerDuty service of a cluster",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind of change suggested.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "What in the incidents of the service led to the suggestion.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"type", "message"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_ServiceTags(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ServiceTags are the ownership tags of the PagerDuty services. Each one is set as a \"<key>:<value>\" tag, an empty value removes the tag.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"costCenter": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Cost center the services are billed to, tagged cost-center:<value>.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"owner": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Team owning the services, tagged owner:<value>.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"environment": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Environment of the clusters, such as production or staging, tagged environment:<value>.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_ServiceTuning(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ServiceTuning configures the suggestions to tune the PagerDuty services of the clusters",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"window": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Period over which the incidents of each cluster are listed, which is also how often the suggestions are computed. Values below 6 hours are raised to 6 hours. Defaults to 7 days.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"minIncidents": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How many incidents a service must get over the window for its incidents to tell anything. Defaults to 10.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_SharedIntegrationKey(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SharedIntegrationKey is the integration key delivered to all clusters",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"integrationKeySecretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Reference to the secret containing the PAGERDUTY_KEY of an Events API v2 integration of the service. It is synced as is to TargetSecretRef in each cluster, SecretDeliveryMode and ImmutableSecret are ignored.",
+							Ref:         ref("k8s.io/api/core/v1.SecretReference"),
+						},
+					},
+				},
+				Required: []string{"integrationKeySecretRef"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.SecretReference"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_SilenceMaintenanceWindow(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SilenceMaintenanceWindow records a PagerDuty maintenance window created to enforce a PagerDutySilence.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"pagerDutyIntegration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the PagerDutyIntegration that owns the silenced service.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"serviceID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of the silenced PagerDuty service.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"maintenanceWindowID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of the PagerDuty maintenance window.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"pagerDutyIntegration", "serviceID", "maintenanceWindowID"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_StaleSilence(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "StaleSilence describes a silence that outlived maxSilenceDuration and was lifted by the operator",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"clusterDeploymentNamespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace of the ClusterDeployment that was muted.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clusterDeploymentName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the ClusterDeployment that was muted.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"source": {
+						SchemaProps: spec.SchemaProps{
+							Description: "What muted the cluster: PagerDutySilence or NoalertsLabel.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"silenceName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the PagerDutySilence that muted the cluster, if any.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"requester": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Who muted the cluster, if known.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"liftedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the operator lifted the silence.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"clusterDeploymentNamespace", "clusterDeploymentName", "source", "liftedAt"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_SupportHours(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SupportHours are the hours of each day of the week during which support is provided",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"timeZone": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time zone of the support hours, such as America/New_York.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"startTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which support starts each day, such as 09:00:00.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"endTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which support ends each day, such as 17:00:00.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"daysOfWeek": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Days of the week with support, 1 for Monday to 7 for Sunday.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"integer"},
+										Format: "int32",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"timeZone", "startTime", "endTime", "daysOfWeek"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_pagerduty_v1alpha1_TestAlertStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TestAlertStatus is the result of a synthetic test alert sent through the integration of one cluster",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"lastTestTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time at which the test alert was sent.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"dedupKey": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Deduplication key of the test alert in PagerDuty.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"accepted": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether PagerDuty accepted the test alert.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"latency": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time PagerDuty took to accept the test alert.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Why the test alert was not accepted, if it was not.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"lastTestTime", "accepted"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
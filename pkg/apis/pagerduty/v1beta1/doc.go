@@ -0,0 +1,4 @@
+// Package v1beta1 contains API Schema definitions for the pagerduty v1beta1 API group
+// +k8s:deepcopy-gen=package,register
+// +groupName=pagerduty.openshift.io
+package v1beta1
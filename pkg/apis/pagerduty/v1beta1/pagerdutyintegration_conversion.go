@@ -0,0 +1,129 @@
+package v1beta1
+
+import (
+	"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this PagerDutyIntegration to v1alpha1, the hub version
+func (src *PagerDutyIntegration) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.PagerDutyIntegration)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+
+	dst.Spec = v1alpha1.PagerDutyIntegrationSpec{
+		TemplateRef:               src.Spec.TemplateRef,
+		PagerdutyApiKeySecretRef:  src.Spec.PagerdutyApiKeySecretRef,
+		APIEndpoint:               src.Spec.APIEndpoint,
+		ClusterDeploymentSelector: src.Spec.ClusterDeploymentSelector,
+		SelfServiceOnboarding:     src.Spec.SelfServiceOnboarding,
+
+		ServicePrefix:           src.Spec.Service.Prefix,
+		NormalizeServiceNames:   src.Spec.Service.NormalizeNames,
+		ServiceNameTemplate:     src.Spec.Service.NameTemplate,
+		IntegrationType:         src.Spec.Service.IntegrationType,
+		EscalationPolicy:        src.Spec.Service.EscalationPolicy,
+		EscalationPolicyMapping: src.Spec.Service.EscalationPolicyMapping,
+		Team:                    src.Spec.Service.Team,
+		ResolveTimeout:          src.Spec.Service.ResolveTimeout,
+		AcknowledgeTimeout:      src.Spec.Service.AcknowledgeTimeout,
+		IncidentUrgency:         src.Spec.Service.IncidentUrgency,
+		AlertGrouping:           src.Spec.Service.AlertGrouping,
+		ServiceTags:             src.Spec.Service.Tags,
+		ServiceDependencies:     src.Spec.Service.Dependencies,
+
+		TargetSecretRef:            src.Spec.Delivery.TargetSecretRef,
+		AdditionalTargetSecretRefs: src.Spec.Delivery.AdditionalTargetSecretRefs,
+		SecretDeliveryMode:         src.Spec.Delivery.Mode,
+		SecretType:                 src.Spec.Delivery.SecretType,
+		ImmutableSecret:            src.Spec.Delivery.ImmutableSecret,
+		SharedIntegrationKey:       src.Spec.Delivery.SharedIntegrationKey,
+		DeliveryProbe:              src.Spec.Delivery.Probe,
+		AlertmanagerConfig:         src.Spec.Delivery.AlertmanagerConfig,
+
+		MaxSilenceDuration:        src.Spec.MaxSilenceDuration,
+		DeprovisioningEventRule:   src.Spec.DeprovisioningEventRule,
+		FleetHygieneService:       src.Spec.FleetHygieneService,
+		AuditPollInterval:         src.Spec.AuditPollInterval,
+		TestAlertInterval:         src.Spec.TestAlertInterval,
+		ReinstallServiceRetention: src.Spec.ReinstallServiceRetention,
+		DeprovisionGracePeriod:    src.Spec.DeprovisionGracePeriod,
+		ServiceDeletionPolicy:     src.Spec.ServiceDeletionPolicy,
+		AccountMigration:          src.Spec.AccountMigration,
+		EscrowSecretRef:           src.Spec.EscrowSecretRef,
+		AdditionalServices:        src.Spec.AdditionalServices,
+		AlertVolumeAnomaly:        src.Spec.AlertVolumeAnomaly,
+		ServiceTuning:             src.Spec.ServiceTuning,
+		OrphanedServiceSweep:      src.Spec.OrphanedServiceSweep,
+		ErrorBudget:               src.Spec.ErrorBudget,
+		Heartbeat:                 src.Spec.Heartbeat,
+		CoverageReport:            src.Spec.CoverageReport,
+		ResyncPeriod:              src.Spec.ResyncPeriod,
+		ErrorRequeueDelay:         src.Spec.ErrorRequeueDelay,
+	}
+	return nil
+}
+
+// ConvertFrom converts from v1alpha1, the hub version, to this
+// PagerDutyIntegration
+func (dst *PagerDutyIntegration) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.PagerDutyIntegration)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+
+	dst.Spec = PagerDutyIntegrationSpec{
+		TemplateRef:               src.Spec.TemplateRef,
+		PagerdutyApiKeySecretRef:  src.Spec.PagerdutyApiKeySecretRef,
+		APIEndpoint:               src.Spec.APIEndpoint,
+		ClusterDeploymentSelector: src.Spec.ClusterDeploymentSelector,
+		SelfServiceOnboarding:     src.Spec.SelfServiceOnboarding,
+
+		Service: ServiceSettings{
+			Prefix:                  src.Spec.ServicePrefix,
+			NormalizeNames:          src.Spec.NormalizeServiceNames,
+			NameTemplate:            src.Spec.ServiceNameTemplate,
+			IntegrationType:         src.Spec.IntegrationType,
+			EscalationPolicy:        src.Spec.EscalationPolicy,
+			EscalationPolicyMapping: src.Spec.EscalationPolicyMapping,
+			Team:                    src.Spec.Team,
+			ResolveTimeout:          src.Spec.ResolveTimeout,
+			AcknowledgeTimeout:      src.Spec.AcknowledgeTimeout,
+			IncidentUrgency:         src.Spec.IncidentUrgency,
+			AlertGrouping:           src.Spec.AlertGrouping,
+			Tags:                    src.Spec.ServiceTags,
+			Dependencies:            src.Spec.ServiceDependencies,
+		},
+
+		Delivery: Delivery{
+			TargetSecretRef:            src.Spec.TargetSecretRef,
+			AdditionalTargetSecretRefs: src.Spec.AdditionalTargetSecretRefs,
+			Mode:                       src.Spec.SecretDeliveryMode,
+			SecretType:                 src.Spec.SecretType,
+			ImmutableSecret:            src.Spec.ImmutableSecret,
+			SharedIntegrationKey:       src.Spec.SharedIntegrationKey,
+			Probe:                      src.Spec.DeliveryProbe,
+			AlertmanagerConfig:         src.Spec.AlertmanagerConfig,
+		},
+
+		MaxSilenceDuration:        src.Spec.MaxSilenceDuration,
+		DeprovisioningEventRule:   src.Spec.DeprovisioningEventRule,
+		FleetHygieneService:       src.Spec.FleetHygieneService,
+		AuditPollInterval:         src.Spec.AuditPollInterval,
+		TestAlertInterval:         src.Spec.TestAlertInterval,
+		ReinstallServiceRetention: src.Spec.ReinstallServiceRetention,
+		DeprovisionGracePeriod:    src.Spec.DeprovisionGracePeriod,
+		ServiceDeletionPolicy:     src.Spec.ServiceDeletionPolicy,
+		AccountMigration:          src.Spec.AccountMigration,
+		EscrowSecretRef:           src.Spec.EscrowSecretRef,
+		AdditionalServices:        src.Spec.AdditionalServices,
+		AlertVolumeAnomaly:        src.Spec.AlertVolumeAnomaly,
+		ServiceTuning:             src.Spec.ServiceTuning,
+		OrphanedServiceSweep:      src.Spec.OrphanedServiceSweep,
+		ErrorBudget:               src.Spec.ErrorBudget,
+		Heartbeat:                 src.Spec.Heartbeat,
+		CoverageReport:            src.Spec.CoverageReport,
+		ResyncPeriod:              src.Spec.ResyncPeriod,
+		ErrorRequeueDelay:         src.Spec.ErrorRequeueDelay,
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+
+	fuzz "github.com/google/gofuzz"
+	"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Fuzzing every field catches those a later change forgets to convert. The
+// TypeMeta isn't converted, the webhook sets it.
+func TestConvertRoundTrip(t *testing.T) {
+	for _, nilChance := range []float64{0, 0.5} {
+		f := fuzz.New().NilChance(nilChance).NumElements(1, 3)
+		for i := 0; i < 20; i++ {
+			hub := &v1alpha1.PagerDutyIntegration{}
+			f.Fuzz(hub)
+			hub.TypeMeta = metav1.TypeMeta{}
+
+			spoke := &PagerDutyIntegration{}
+			assert.NoError(t, spoke.ConvertFrom(hub))
+			back := &v1alpha1.PagerDutyIntegration{}
+			assert.NoError(t, spoke.ConvertTo(back))
+			assert.Equal(t, hub, back)
+
+			spoke = &PagerDutyIntegration{}
+			f.Fuzz(spoke)
+			spoke.TypeMeta = metav1.TypeMeta{}
+
+			hub = &v1alpha1.PagerDutyIntegration{}
+			assert.NoError(t, spoke.ConvertTo(hub))
+			spokeBack := &PagerDutyIntegration{}
+			assert.NoError(t, spokeBack.ConvertFrom(hub))
+			assert.Equal(t, spoke, spokeBack)
+		}
+	}
+}
+
+func TestConvertRestructuresSpec(t *testing.T) {
+	hub := &v1alpha1.PagerDutyIntegration{
+		Spec: v1alpha1.PagerDutyIntegrationSpec{
+			ServicePrefix:      "osd",
+			EscalationPolicy:   "PA12345",
+			ResolveTimeout:     300,
+			SecretDeliveryMode: v1alpha1.SecretDeliveryModePatch,
+			DeliveryProbe:      &v1alpha1.DeliveryProbe{},
+		},
+	}
+	hub.Spec.TargetSecretRef.Name = "pd-secret"
+
+	spoke := &PagerDutyIntegration{}
+	assert.NoError(t, spoke.ConvertFrom(hub))
+	assert.Equal(t, "osd", spoke.Spec.Service.Prefix)
+	assert.Equal(t, "PA12345", spoke.Spec.Service.EscalationPolicy)
+	assert.Equal(t, uint(300), spoke.Spec.Service.ResolveTimeout)
+	assert.Equal(t, "pd-secret", spoke.Spec.Delivery.TargetSecretRef.Name)
+	assert.Equal(t, v1alpha1.SecretDeliveryModePatch, spoke.Spec.Delivery.Mode)
+	assert.NotNil(t, spoke.Spec.Delivery.Probe)
+}
@@ -0,0 +1,341 @@
+package v1beta1
+
+import (
+	"github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PagerDutyIntegrationSpec defines the desired state of PagerDutyIntegration.
+// It holds the settings of v1alpha1, with those of the PagerDuty service of
+// each cluster grouped under service and those of the delivery of its
+// integration key under delivery.
+// +k8s:openapi-gen=true
+type PagerDutyIntegrationSpec struct {
+	// PagerDutyIntegrationTemplate, in the namespace of this
+	// PagerDutyIntegration, whose settings are inherited. A field set on
+	// this PagerDutyIntegration overrides the one of the template as a
+	// whole, a timeout of 0 inherits the one of the template. Omitting
+	// this field inherits nothing.
+	TemplateRef *corev1.LocalObjectReference `json:"templateRef,omitempty"`
+
+	// Reference to the secret containing PAGERDUTY_API_KEY.
+	PagerdutyApiKeySecretRef corev1.SecretReference `json:"pagerdutyApiKeySecretRef"`
+
+	// URL of the PagerDuty REST API of the account, such as
+	// https://api.eu.pagerduty.com for an account in the EU service region,
+	// or of a staging or mock API. Defaults to https://api.pagerduty.com.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	APIEndpoint string `json:"apiEndpoint,omitempty"`
+
+	// A label selector used to find which clusterdeployment CRs receive a
+	// PD integration based on this configuration.
+	ClusterDeploymentSelector metav1.LabelSelector `json:"clusterDeploymentSelector"`
+
+	// Lets ClusterDeployments opt into this PagerDutyIntegration, whatever
+	// their labels, by setting the pd.managed.openshift.io/integration
+	// annotation to its name, so teams can request paging without a change
+	// of the labels by the hub admins. Omitting this field ignores the
+	// annotation.
+	SelfServiceOnboarding *v1alpha1.SelfServiceOnboarding `json:"selfServiceOnboarding,omitempty"`
+
+	// Settings of the PagerDuty service of each cluster.
+	Service ServiceSettings `json:"service"`
+
+	// How the integration key of each cluster is delivered to it.
+	Delivery Delivery `json:"delivery"`
+
+	// Longest time a selected cluster may stay muted, by a PagerDutySilence
+	// or the noalerts label. Once exceeded the silence is considered stale
+	// and alerting is re-enabled. Omitting this field disables the feature.
+	MaxSilenceDuration *metav1.Duration `json:"maxSilenceDuration,omitempty"`
+
+	// Add a rule to a PagerDuty global ruleset suppressing the events of a
+	// cluster once its ClusterDeployment is deleted, so the alerts raised
+	// while it tears itself down page nobody. Omitting this field disables
+	// the rule.
+	DeprovisioningEventRule *v1alpha1.DeprovisioningEventRule `json:"deprovisioningEventRule,omitempty"`
+
+	// PagerDuty service sent a change event whenever the settings of a
+	// cluster's service are found to have drifted from this
+	// PagerDutyIntegration and are repaired. Omitting this field still
+	// repairs drift, without reporting it.
+	FleetHygieneService *v1alpha1.FleetHygieneService `json:"fleetHygieneService,omitempty"`
+
+	// How often the PagerDuty audit records are polled for changes made to
+	// the services of the selected clusters outside of the operator, such as
+	// a service disabled by hand. Each change is reported as a Warning event
+	// on this PagerDutyIntegration. Values below 15 minutes are raised to 15
+	// minutes. Omitting this field disables the poller.
+	AuditPollInterval *metav1.Duration `json:"auditPollInterval,omitempty"`
+
+	// How often a synthetic test alert is triggered and resolved right away
+	// through the integration of each selected cluster, with the result
+	// recorded in the testAlert of the cluster's status as evidence of
+	// paging coverage. Values below 1 hour are raised to 1 hour. Omitting
+	// this field disables test alerts.
+	TestAlertInterval *metav1.Duration `json:"testAlertInterval,omitempty"`
+
+	// How long the PagerDuty service and integration key of a deleted
+	// cluster are kept, so a cluster reinstalled with the same
+	// ClusterDeployment namespace, name and cluster name reuses them and
+	// keeps its incident history. Services not reused in time are deleted.
+	// Omitting this field deletes the service along with the cluster.
+	ReinstallServiceRetention *metav1.Duration `json:"reinstallServiceRetention,omitempty"`
+
+	// How long the PagerDuty service of a deleted cluster is kept disabled
+	// before it is deleted, so the accidental deletion of a
+	// ClusterDeployment doesn't wipe its incident history right away. The
+	// service is recorded in status.retainedServices meanwhile. Omitting
+	// this field deletes the service along with the cluster.
+	DeprovisionGracePeriod *metav1.Duration `json:"deprovisionGracePeriod,omitempty"`
+
+	// What is done with the PagerDuty service of a deleted cluster: Delete
+	// deletes it, once reinstallServiceRetention and deprovisionGracePeriod
+	// are over if set, Retain leaves it as is and Disable disables it, both
+	// keeping its incident history in PagerDuty. Defaults to Delete.
+	// +kubebuilder:validation:Enum=Delete;Retain;Disable
+	ServiceDeletionPolicy v1alpha1.ServiceDeletionPolicy `json:"serviceDeletionPolicy,omitempty"`
+
+	// PagerDuty account the clusters are being migrated to. While set, each
+	// selected cluster also gets a service in that account, and its
+	// integration key is synced to delivery.targetSecretRef next to the
+	// one of the current account, so alerting can switch accounts without
+	// a gap.
+	// Omitting this field uses the current account only.
+	AccountMigration *v1alpha1.AccountMigration `json:"accountMigration,omitempty"`
+
+	// Secret on the hub a copy of the integration key of every selected
+	// cluster is written to, under the key <namespace>.<name> of the
+	// cluster's ClusterDeployment, so SREs can still retrieve a key when
+	// Hive sync is broken, without PagerDuty API access. Omitting this field
+	// keeps no copy.
+	EscrowSecretRef *corev1.SecretReference `json:"escrowSecretRef,omitempty"`
+
+	// Further PagerDuty services set up for the clusters each one selects,
+	// next to the service of this PagerDutyIntegration, for example one
+	// paging the customer in addition to SRE. Each service is tracked in its
+	// own ConfigMap, Secret and SyncSet and is deleted with the cluster.
+	// Only the timeouts, service.team, service.incidentUrgency,
+	// service.alertGrouping, service.normalizeNames and service.tags apply
+	// to them, the other features only apply to the service of this
+	// PagerDutyIntegration.
+	AdditionalServices []v1alpha1.AdditionalService `json:"additionalServices,omitempty"`
+
+	// Flag the selected clusters whose PagerDuty service gets far more
+	// incidents than the rest of the fleet, from the PagerDuty analytics
+	// polled a few times a day, so noisy clusters can be found. Omitting
+	// this field disables the check.
+	AlertVolumeAnomaly *v1alpha1.AlertVolumeAnomaly `json:"alertVolumeAnomaly,omitempty"`
+
+	// Suggest how to tune the PagerDuty service of each selected cluster,
+	// such as enabling intelligent alert grouping, from its incidents
+	// listed every window. Suggestions are reported in status.clusters and
+	// as events, they are never applied. Omitting this field disables the
+	// suggestions.
+	ServiceTuning *v1alpha1.ServiceTuning `json:"serviceTuning,omitempty"`
+
+	// Sweep the PagerDuty account for services named after service.prefix
+	// whose cluster no longer exists, such as those left behind when the
+	// teardown of a cluster fails. Omitting this field disables the sweep.
+	OrphanedServiceSweep *v1alpha1.OrphanedServiceSweep `json:"orphanedServiceSweep,omitempty"`
+
+	// Account the attempts to set up, tear down and verify the selected
+	// clusters against an objective over a rolling window, reported in
+	// status.errorBudget, so SLOs can be set on the provisioning of paging
+	// itself. Omitting this field disables the accounting.
+	ErrorBudget *v1alpha1.ErrorBudget `json:"errorBudget,omitempty"`
+
+	// Create a heartbeat integration on the PagerDuty service of each
+	// selected cluster, and sync the URL the cluster is expected to check
+	// in at to delivery.targetSecretRef. A cluster that stops checking in,
+	// such as one whose Alertmanager stopped, is paged on through the
+	// heartbeat integration. Omitting this field disables heartbeats.
+	Heartbeat *v1alpha1.Heartbeat `json:"heartbeat,omitempty"`
+
+	// Write a report of the paging coverage of the selected clusters, which
+	// of them have an active PagerDuty service, are silenced or failed, to
+	// a ConfigMap every interval, and optionally send it on as a change
+	// event or to a webhook. Omitting this field disables the report.
+	CoverageReport *v1alpha1.CoverageReport `json:"coverageReport,omitempty"`
+
+	// How often the PagerDuty service of each selected cluster is verified
+	// when nothing changed, the verifications being spread over the fleet.
+	// Large fleets may want a slower resync, dev environments a faster one.
+	// Values below 5 minutes are raised to 5 minutes. Omitting this field
+	// uses the --resync-period of the operator.
+	ResyncPeriod *metav1.Duration `json:"resyncPeriod,omitempty"`
+
+	// How long to wait before reconciling again once a call to the
+	// PagerDuty API failed with a server error or the API could not be
+	// reached, after the call itself was retried. Omitting this field uses
+	// the --pagerduty-error-requeue-delay of the operator.
+	ErrorRequeueDelay *metav1.Duration `json:"errorRequeueDelay,omitempty"`
+}
+
+// ServiceSettings are the settings of the PagerDuty service of each cluster
+// +k8s:openapi-gen=true
+type ServiceSettings struct {
+	// Prefix to set on the PagerDuty Service name.
+	Prefix string `json:"prefix"`
+
+	// Normalize the names of the PagerDuty services: lower case, with any
+	// run of characters other than ASCII letters, digits, '-' and '.'
+	// replaced with a '-', and names longer than PagerDuty accepts
+	// truncated and suffixed with a hash of the full name. Existing
+	// services still named as before are renamed when verified.
+	NormalizeNames bool `json:"normalizeNames,omitempty"`
+
+	// Go template naming the PagerDuty service of each cluster instead of
+	// <prefix>-<cluster name>.<base domain>-hive-cluster, such as
+	// "{{.Prefix}}-{{.ClusterID}}-{{.Labels.environment}}". It is
+	// executed with .Prefix, .ClusterID, .BaseDomain and the .Labels of the
+	// ClusterDeployment. Clusters missing a label the template refers to
+	// aren't set up. Existing services still named as before are renamed
+	// when verified.
+	NameTemplate string `json:"nameTemplate,omitempty"`
+
+	// Type of the integration created on the PagerDuty service of each
+	// cluster, whose key is synced to the cluster, for receivers that need
+	// one other than Events API v2: "events_api_v2", the default,
+	// "prometheus", or "generic_events_api" for the Events API v1. A change
+	// applies to the integrations created from then on, rotating the keys
+	// switches existing clusters.
+	// +kubebuilder:validation:Enum=events_api_v2;prometheus;generic_events_api
+	IntegrationType v1alpha1.IntegrationType `json:"integrationType,omitempty"`
+
+	// ID of an existing Escalation Policy in PagerDuty.
+	EscalationPolicy string `json:"escalationPolicy"`
+
+	// Escalation policies picked by the value of a label of each selected
+	// ClusterDeployment, such as tier=gold or tier=silver, so one
+	// PagerDutyIntegration serves every tier. Clusters without the label,
+	// or whose value is not mapped, get escalationPolicy. Services whose
+	// policy no longer matches the label are set to the mapped one when
+	// verified. Omitting this field gives every cluster escalationPolicy.
+	EscalationPolicyMapping *v1alpha1.EscalationPolicyMapping `json:"escalationPolicyMapping,omitempty"`
+
+	// ID or name of the PagerDuty team the service of each cluster is
+	// assigned to, so team-scoped PagerDuty users see it. A service the
+	// team is removed from is assigned to it again when verified, other
+	// teams of the service are left alone. Omitting this field leaves the
+	// teams of services alone.
+	Team string `json:"team,omitempty"`
+
+	// Time in seconds that an incident is automatically resolved if left
+	// open for that long. Value must not be negative. Omitting or setting
+	// this field to 0 will disable the feature.
+	// +kubebuilder:validation:Minimum=0
+	ResolveTimeout uint `json:"resolveTimeout,omitempty"`
+
+	// Time in seconds that an incident changes to the Triggered State after
+	// being Acknowledged. Value must not be negative. Omitting or setting
+	// this field to 0 will disable the feature.
+	// +kubebuilder:validation:Minimum=0
+	AcknowledgeTimeout uint `json:"acknowledgeTimeout,omitempty"`
+
+	// Urgency of the incidents of the PagerDuty service of each cluster,
+	// optionally depending on support hours. Services whose urgency
+	// drifted are set back when verified. Omitting this field makes the
+	// urgency follow the severity of the incidents.
+	IncidentUrgency *v1alpha1.IncidentUrgency `json:"incidentUrgency,omitempty"`
+
+	// How the alerts of the PagerDuty service of each cluster are grouped
+	// into incidents, so a noisy cluster doesn't open an incident per
+	// alert. Services whose grouping drifted are set back when verified.
+	// Omitting this field leaves the grouping of the services alone.
+	AlertGrouping *v1alpha1.AlertGrouping `json:"alertGrouping,omitempty"`
+
+	// Ownership tags set on the PagerDuty service of each cluster and
+	// reconciled on every resync, so PagerDuty reporting can be sliced by
+	// ownership. Omitting this field leaves the tags of services alone.
+	Tags *v1alpha1.ServiceTags `json:"tags,omitempty"`
+
+	// PagerDuty services the service of each cluster is related to in the
+	// service graph of PagerDuty, registered as service dependencies and
+	// reconciled on every resync, so responders see which clusters an
+	// incident of the hub affects. Dependencies removed from this field are
+	// left in PagerDuty. Omitting this field registers none.
+	Dependencies *v1alpha1.ServiceDependencies `json:"dependencies,omitempty"`
+}
+
+// Delivery is how the integration key of each cluster is delivered to it
+// +k8s:openapi-gen=true
+type Delivery struct {
+	// Name and namespace in the target cluster where the secret is synced.
+	TargetSecretRef corev1.SecretReference `json:"targetSecretRef"`
+
+	// More names and namespaces in the target cluster the secret is synced
+	// to by the same SyncSet, such as a customer namespace or the secret of
+	// user-workload monitoring. They are delivered like targetSecretRef.
+	AdditionalTargetSecretRefs []corev1.SecretReference `json:"additionalTargetSecretRefs,omitempty"`
+
+	// How the integration key is delivered to targetSecretRef. "Secret",
+	// the default, syncs a standalone secret. "Patch" merges the key into
+	// an existing secret, for clusters where monitoring config is a single
+	// aggregated secret.
+	// +kubebuilder:validation:Enum=Secret;Patch
+	Mode v1alpha1.SecretDeliveryMode `json:"mode,omitempty"`
+
+	// Type of the secret synced to targetSecretRef, Opaque by default.
+	// Ignored in Patch mode, where the secret already exists.
+	SecretType corev1.SecretType `json:"secretType,omitempty"`
+
+	// Make the secret synced to targetSecretRef immutable. An immutable
+	// secret cannot be updated, so it is named after targetSecretRef with a
+	// hash of the integration key appended, and a new key is delivered in
+	// a new secret replacing the previous one. Ignored in Patch mode.
+	ImmutableSecret bool `json:"immutableSecret,omitempty"`
+
+	// Deliver the integration key of a single PagerDuty service to every
+	// selected cluster through one Hive SelectorSyncSet matching
+	// ClusterDeploymentSelector, instead of creating a service and a
+	// SyncSet per cluster. Clusters set up on their own are torn down, and
+	// the per-cluster features such as silences and verification don't
+	// apply. Omitting this field sets up each cluster on its own.
+	SharedIntegrationKey *v1alpha1.SharedIntegrationKey `json:"sharedIntegrationKey,omitempty"`
+
+	// Sync a CronJob to each cluster that checks the integration key was
+	// delivered and events.pagerduty.com is reachable, and report its
+	// result in the DeliveryVerificationFailed condition of the cluster.
+	// Omitting this field disables the probe.
+	Probe *v1alpha1.DeliveryProbe `json:"probe,omitempty"`
+
+	// Sync to each cluster an Alertmanager configuration with a receiver
+	// sending all alerts to the cluster's PagerDuty service, so the
+	// in-cluster Alertmanager is wired to it without manual configuration.
+	// Omitting this field only syncs the integration key.
+	AlertmanagerConfig *v1alpha1.AlertmanagerConfig `json:"alertmanagerConfig,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PagerDutyIntegration is the Schema for the pagerdutyintegrations API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=pagerdutyintegrations,shortName=pdi,scope=Namespaced
+// +kubebuilder:printcolumn:name="Service Prefix",type="string",JSONPath=".spec.service.prefix"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyClusters"
+// +kubebuilder:printcolumn:name="Pending",type="integer",JSONPath=".status.pendingClusters"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failedClusters"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type PagerDutyIntegration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PagerDutyIntegrationSpec            `json:"spec,omitempty"`
+	Status v1alpha1.PagerDutyIntegrationStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PagerDutyIntegrationList contains a list of PagerDutyIntegration
+type PagerDutyIntegrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PagerDutyIntegration `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PagerDutyIntegration{}, &PagerDutyIntegrationList{})
+}
@@ -0,0 +1,189 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namingmigration
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hiveintv1alpha1 "github.com/openshift/hive/pkg/apis/hiveinternal/v1alpha1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/dryrun"
+	"github.com/openshift/pagerduty-operator/pkg/localmetrics"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	"github.com/openshift/pagerduty-operator/pkg/pause"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "namingmigration"
+
+	// pendingRetryInterval is how often a cluster still having objects
+	// named under a previous scheme is checked again, in case a change of
+	// its ClusterSync was missed
+	pendingRetryInterval = 5 * time.Minute
+)
+
+var log = logf.Log.WithName("controller_namingmigration")
+
+// Add creates a new naming migration Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, pause.Wrap(newReconciler(mgr), mgr.GetAPIReader(), config.GetOperatorNamespace()))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileNamingMigration{
+		client: utils.NewClientWithMetricsOrDie(log, mgr, controllerName),
+		scheme: mgr.GetScheme(),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("namingmigration-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource ClusterDeployment
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// A ClusterSync is named after its ClusterDeployment, and reports when
+	// the replacement of an old SyncSet is applied
+	return c.Watch(&source.Kind{Type: &hiveintv1alpha1.ClusterSync{}}, &handler.EnqueueRequestForObject{})
+}
+
+// blank assignment to verify that ReconcileNamingMigration implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileNamingMigration{}
+
+// ReconcileNamingMigration renames the secondary resources of a
+// ClusterDeployment created under a previous naming scheme
+type ReconcileNamingMigration struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client    client.Client
+	scheme    *runtime.Scheme
+	reqLogger logr.Logger
+}
+
+// Reconcile migrates the objects named under a previous scheme of every
+// PagerDuty service set up for the ClusterDeployment to the current scheme,
+// in place, so that the PagerDuty services are kept and both generations of
+// objects don't stay around. It requeues while objects are kept until their
+// replacement is applied to the cluster.
+func (r *ReconcileNamingMigration) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+
+	r.reqLogger = logging.WithReconcileID(log).WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	r.reqLogger.Info("Reconciling ClusterDeployment naming")
+
+	defer func() {
+		dur := time.Since(start)
+		localmetrics.SetReconcileDuration(controllerName, dur.Seconds())
+		r.reqLogger.WithValues("Duration", dur).Info("Reconcile complete")
+	}()
+
+	cd := &hivev1.ClusterDeployment{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, cd)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return r.doNotRequeue()
+		}
+		return r.requeueOnErr(err)
+	}
+	if cd.DeletionTimestamp != nil {
+		// the PagerDutyIntegration controller migrates before cleaning up
+		return r.doNotRequeue()
+	}
+
+	pdiList := &pagerdutyv1alpha1.PagerDutyIntegrationList{}
+	err = r.client.List(context.TODO(), pdiList, &client.ListOptions{})
+	if err != nil {
+		return r.requeueOnErr(err)
+	}
+
+	pending := false
+	for i := range pdiList.Items {
+		pdi := &pdiList.Items[i]
+		if pdi.DeletionTimestamp != nil {
+			continue
+		}
+
+		c := r.client
+		if dryrun.Enabled(pdi) {
+			// log the renames instead of making them
+			c = dryrun.NewClient(r.client, r.scheme, &r.reqLogger)
+		}
+
+		// only the services set up for the cluster leave their finalizer
+		// on it, additional services are owned by <pdi name>.<prefix>
+		prefixes := map[string]string{pdi.Name: pdi.Spec.ServicePrefix}
+		for _, svc := range pdi.Spec.AdditionalServices {
+			prefixes[pdi.Name+"."+svc.ServicePrefix] = svc.ServicePrefix
+		}
+		for owner, servicePrefix := range prefixes {
+			if !utils.HasFinalizer(cd, config.PagerDutyFinalizerPrefix+owner) {
+				continue
+			}
+
+			err = naming.Migrate(c, r.reqLogger, cd.Namespace, servicePrefix, cd.Name)
+			if err != nil {
+				return r.requeueOnErr(err)
+			}
+
+			left, err := naming.Pending(r.client, cd.Namespace, servicePrefix, cd.Name)
+			if err != nil {
+				return r.requeueOnErr(err)
+			}
+			pending = pending || left
+		}
+	}
+
+	if pending {
+		return r.requeueAfter(pendingRetryInterval)
+	}
+	return r.doNotRequeue()
+}
+
+func (r *ReconcileNamingMigration) doNotRequeue() (reconcile.Result, error) {
+	return reconcile.Result{}, nil
+}
+
+func (r *ReconcileNamingMigration) requeueOnErr(err error) (reconcile.Result, error) {
+	return reconcile.Result{}, err
+}
+
+func (r *ReconcileNamingMigration) requeueAfter(t time.Duration) (reconcile.Result, error) {
+	return reconcile.Result{RequeueAfter: t}, nil
+}
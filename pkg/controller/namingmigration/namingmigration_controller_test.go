@@ -0,0 +1,169 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namingmigration
+
+import (
+	"context"
+	"testing"
+
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	testPagerDutyIntegrationName = "testPagerDutyIntegration"
+	testClusterName              = "testCluster"
+	testNamespace                = "testNamespace"
+	testServiceID                = "DEF456"
+	testServicePrefix            = "test-service-prefix"
+	testAdditionalServicePrefix  = "test-additional-prefix"
+)
+
+func testPagerDutyIntegration() *pagerdutyv1alpha1.PagerDutyIntegration {
+	return &pagerdutyv1alpha1.PagerDutyIntegration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testPagerDutyIntegrationName,
+			Namespace: config.OperatorNamespace,
+		},
+		Spec: pagerdutyv1alpha1.PagerDutyIntegrationSpec{
+			ServicePrefix: testServicePrefix,
+			AdditionalServices: []pagerdutyv1alpha1.AdditionalService{
+				{ServicePrefix: testAdditionalServicePrefix},
+			},
+		},
+	}
+}
+
+func testClusterDeployment(finalizers ...string) *hivev1.ClusterDeployment {
+	return &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testClusterName,
+			Namespace:  testNamespace,
+			Finalizers: finalizers,
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			ClusterName: testClusterName,
+			Installed:   true,
+		},
+	}
+}
+
+// testLegacyObjects returns the ConfigMap and Secret of a service named
+// under the legacy scheme, which didn't use the service prefix
+func testLegacyObjects() []runtime.Object {
+	return []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testClusterName + naming.ConfigMapSuffix},
+			Data:       map[string]string{"SERVICE_ID": testServiceID},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testClusterName + naming.SecretSuffix},
+			Data:       map[string][]byte{config.PagerDutySecretKey: []byte("key")},
+		},
+	}
+}
+
+func TestReconcileNamingMigration(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	dryRun := testPagerDutyIntegration()
+	dryRun.Annotations = map[string]string{config.DryRunAnnotation: "true"}
+	legacySyncSet := &hivev1.SyncSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testClusterName + naming.LegacySyncSetSuffix},
+	}
+
+	tests := []struct {
+		name          string
+		localObjects  []runtime.Object
+		servicePrefix string
+		expectMigrate bool
+		expectRequeue bool
+	}{
+		{
+			name:          "legacy objects of the service are renamed",
+			localObjects:  []runtime.Object{testPagerDutyIntegration(), testClusterDeployment(config.PagerDutyFinalizerPrefix + testPagerDutyIntegrationName)},
+			servicePrefix: testServicePrefix,
+			expectMigrate: true,
+		},
+		{
+			name:          "legacy objects of an additional service are renamed",
+			localObjects:  []runtime.Object{testPagerDutyIntegration(), testClusterDeployment(config.PagerDutyFinalizerPrefix + testPagerDutyIntegrationName + "." + testAdditionalServicePrefix)},
+			servicePrefix: testAdditionalServicePrefix,
+			expectMigrate: true,
+		},
+		{
+			name:          "legacy SyncSet is kept until its replacement is applied",
+			localObjects:  []runtime.Object{testPagerDutyIntegration(), testClusterDeployment(config.PagerDutyFinalizerPrefix + testPagerDutyIntegrationName), legacySyncSet},
+			servicePrefix: testServicePrefix,
+			expectMigrate: true,
+			expectRequeue: true,
+		},
+		{
+			name:          "cluster without a service is left alone",
+			localObjects:  []runtime.Object{testPagerDutyIntegration(), testClusterDeployment()},
+			servicePrefix: testServicePrefix,
+			expectMigrate: false,
+		},
+		{
+			name:          "dry run only logs the renames",
+			localObjects:  []runtime.Object{dryRun, testClusterDeployment(config.PagerDutyFinalizerPrefix + testPagerDutyIntegrationName)},
+			servicePrefix: testServicePrefix,
+			expectMigrate: false,
+			expectRequeue: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mocks := fakekubeclient.NewFakeClient(append(test.localObjects, testLegacyObjects()...)...)
+			rnm := &ReconcileNamingMigration{
+				client: mocks,
+				scheme: scheme.Scheme,
+			}
+
+			result, err := rnm.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: testClusterName},
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectRequeue, result.RequeueAfter > 0)
+
+			cm := &corev1.ConfigMap{}
+			err = mocks.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: naming.ConfigMapName(test.servicePrefix, testClusterName)}, cm)
+			oldErr := mocks.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testClusterName + naming.ConfigMapSuffix}, &corev1.ConfigMap{})
+			if test.expectMigrate {
+				assert.NoError(t, err)
+				assert.Equal(t, testServiceID, cm.Data["SERVICE_ID"])
+				assert.True(t, errors.IsNotFound(oldErr))
+			} else {
+				assert.True(t, errors.IsNotFound(err))
+				assert.NoError(t, oldErr)
+			}
+		})
+	}
+}
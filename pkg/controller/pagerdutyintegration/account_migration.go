@@ -0,0 +1,116 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/kube"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// migrationClient returns a client of the PagerDuty account being migrated
+// to, along with the cluster's data in that account. The service IDs are
+// loaded from the migration ConfigMap when it exists.
+func (r *ReconcilePagerDutyIntegration) migrationClient(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) (pd.Client, *pd.Data, error) {
+	migration := pdi.Spec.AccountMigration
+	apiKey, err := utils.LoadSecretData(r.client, migration.PagerdutyApiKeySecretRef.Name, migration.PagerdutyApiKeySecretRef.Namespace, config.PagerDutyAPISecretKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pdData := &pd.Data{
+		ClusterID:          cd.Spec.ClusterName,
+		BaseDomain:         cd.Spec.BaseDomain,
+		EscalationPolicyID: migration.EscalationPolicy,
+		ServicePrefix:      pdi.Spec.ServicePrefix,
+		NormalizeName:      pdi.Spec.NormalizeServiceNames,
+		IntegrationType:    string(pdi.Spec.IntegrationType),
+		APIKey:             apiKey,
+	}
+	r.setTimeouts(pdi, cd, pdData)
+	setServiceName(pdi, cd, pdData)
+	setIncidentUrgency(pdi, pdData)
+	setAlertGrouping(pdi, pdData)
+	err = pdData.ParseClusterConfig(r.client, cd.Namespace, naming.MigrationConfigMapName(pdi.Spec.ServicePrefix, cd.Name))
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, nil, err
+	}
+
+	return r.pdclient(apiKey, controllerName, pd.WithAPIEndpoint(migration.APIEndpoint)), pdData, nil
+}
+
+// migrationIntegrationKey returns the integration key of the cluster's
+// service in the account being migrated to, creating the service first if
+// the cluster has none there yet.
+func (r *ReconcilePagerDutyIntegration) migrationIntegrationKey(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) (string, error) {
+	pdclient, pdData, err := r.migrationClient(pdi, cd)
+	if err != nil {
+		return "", err
+	}
+
+	if pdData.ServiceID == "" {
+		r.reqLogger.Info("Creating PD service in the account being migrated to", "ClusterID", pdData.ClusterID, "BaseDomain", pdData.BaseDomain)
+		_, err = pdclient.CreateService(pdData)
+		if err != nil {
+			return "", err
+		}
+
+		cm := kube.GenerateConfigMap(cd.Namespace, naming.MigrationConfigMapName(pdi.Spec.ServicePrefix, cd.Name), pdData.ServiceID, pdData.IntegrationID)
+		setOwnerLabel(cm, pdi)
+		if err = controllerutil.SetControllerReference(cd, cm, r.scheme); err != nil {
+			return "", err
+		}
+		if err = r.client.Create(context.TODO(), cm); err != nil {
+			return "", err
+		}
+	}
+
+	return pdclient.GetIntegrationKey(pdData)
+}
+
+// deleteMigrationService deletes the cluster's service in the account being
+// migrated to, if it has one. Failures are only logged, like those of the
+// service in the current account.
+func (r *ReconcilePagerDutyIntegration) deleteMigrationService(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) {
+	pdclient, pdData, err := r.migrationClient(pdi, cd)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed loading PD service in the account being migrated to")
+		return
+	}
+	if pdData.ServiceID == "" {
+		return
+	}
+
+	err = pdclient.DeleteService(pdData)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed cleaning up pagerduty in the account being migrated to")
+		return
+	}
+
+	configMapName := naming.MigrationConfigMapName(pdi.Spec.ServicePrefix, cd.Name)
+	r.reqLogger.Info("Deleting PD migration ConfigMap", "Namespace", cd.Namespace, "Name", configMapName)
+	err = utils.DeleteConfigMap(configMapName, cd.Namespace, r.client, r.reqLogger)
+	if err != nil {
+		r.reqLogger.Error(err, "Error deleting ConfigMap", "Namespace", cd.Namespace, "Name", configMapName)
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"strings"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/selfservice"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// additionalServiceIntegration returns the PagerDutyIntegration handleCreate
+// and handleDelete are given to set up or tear down an additional service.
+// It is named <pdi name>.<service prefix>, so the finalizer and owner label
+// of the service's resources differ from those of the PagerDutyIntegration's
+// own service, and only keeps the features that apply to additional services.
+func additionalServiceIntegration(pdi *pagerdutyv1alpha1.PagerDutyIntegration, svc pagerdutyv1alpha1.AdditionalService) *pagerdutyv1alpha1.PagerDutyIntegration {
+	as := pdi.DeepCopy()
+	as.Name = additionalServiceOwner(pdi, svc.ServicePrefix)
+	as.Spec.ServicePrefix = svc.ServicePrefix
+	as.Spec.EscalationPolicy = svc.EscalationPolicy
+	as.Spec.EscalationPolicyMapping = nil
+	as.Spec.ClusterDeploymentSelector = svc.ClusterDeploymentSelector
+	as.Spec.TargetSecretRef = svc.TargetSecretRef
+	as.Spec.AdditionalTargetSecretRefs = nil
+	as.Spec.AdditionalServices = nil
+	// opting in only applies to the PagerDutyIntegration's own service
+	as.Spec.SelfServiceOnboarding = nil
+
+	as.Spec.MaxSilenceDuration = nil
+	as.Spec.DeliveryProbe = nil
+	as.Spec.AlertmanagerConfig = nil
+	as.Spec.SharedIntegrationKey = nil
+	as.Spec.DeprovisioningEventRule = nil
+	as.Spec.FleetHygieneService = nil
+	as.Spec.AuditPollInterval = nil
+	as.Spec.TestAlertInterval = nil
+	as.Spec.ReinstallServiceRetention = nil
+	as.Spec.DeprovisionGracePeriod = nil
+	as.Spec.ServiceDeletionPolicy = ""
+	as.Spec.AccountMigration = nil
+	as.Spec.EscrowSecretRef = nil
+	as.Spec.Heartbeat = nil
+	as.Spec.ServiceDependencies = nil
+	as.Spec.CoverageReport = nil
+	as.Spec.ServiceNameTemplate = ""
+	return as
+}
+
+// additionalServiceOwner returns the value of the owner label of the
+// resources of an additional service.
+func additionalServiceOwner(pdi *pagerdutyv1alpha1.PagerDutyIntegration, servicePrefix string) string {
+	return pdi.Name + "." + servicePrefix
+}
+
+// isOwner returns true if owner, the owner label of a resource, is the
+// PagerDutyIntegration or one of its additional services.
+func isOwner(pdi *pagerdutyv1alpha1.PagerDutyIntegration, owner string) bool {
+	return owner == pdi.Name || strings.HasPrefix(owner, pdi.Name+".")
+}
+
+// selectsClusterDeployment returns true if the PagerDutyIntegration or any
+// of its additional services selects the ClusterDeployment, or if it opted
+// into the PagerDutyIntegration, whose policy is checked when reconciled.
+func selectsClusterDeployment(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd metav1.Object) bool {
+	cdLabels := cd.GetLabels()
+	if selects(pdi.Spec.ClusterDeploymentSelector, cdLabels) {
+		return true
+	}
+	if pdi.Spec.SelfServiceOnboarding != nil && selfservice.Requests(cd, pdi) {
+		return true
+	}
+	for _, svc := range pdi.Spec.AdditionalServices {
+		if selects(svc.ClusterDeploymentSelector, cdLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+// additionalServicePrefixes returns the service prefixes of the additional
+// services of the PagerDutyIntegration set up for a ClusterDeployment, read
+// from its finalizers, including those since removed from the spec.
+func additionalServicePrefixes(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) []string {
+	finalizerPrefix := config.PagerDutyFinalizerPrefix + pdi.Name + "."
+
+	prefixes := []string{}
+	for _, finalizer := range cd.GetFinalizers() {
+		if strings.HasPrefix(finalizer, finalizerPrefix) {
+			prefixes = append(prefixes, strings.TrimPrefix(finalizer, finalizerPrefix))
+		}
+	}
+	return prefixes
+}
+
+// reconcileAdditionalServices sets up the additional services of the
+// PagerDutyIntegration for the ClusterDeployments they select, and tears
+// down those of clusters being deleted, no longer selected or whose service
+// was removed from the spec. Like for the PagerDutyIntegration's own
+// service, a failing cluster does not hold up the others and the first
+// error setting one up is returned once all were handled.
+func (r *ReconcilePagerDutyIntegration) reconcileAdditionalServices(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cds []hivev1.ClusterDeployment, createServices bool) error {
+	// a tenant's additional services only set up the clusters of its
+	// namespaces too
+	namespaces, err := r.tenantNamespaces(pdi)
+	if err != nil {
+		return err
+	}
+
+	var createErr error
+	for i := range cds {
+		// the cluster's finalizers may have changed since it was listed, and
+		// patching a stale copy would undo the change
+		cd := &hivev1.ClusterDeployment{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: cds[i].Namespace, Name: cds[i].Name}, cd)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		for _, prefix := range additionalServicePrefixes(pdi, cd) {
+			svc := findAdditionalService(pdi, prefix)
+			if svc != nil && cd.DeletionTimestamp == nil && pdi.DeletionTimestamp == nil && selects(svc.ClusterDeploymentSelector, cd.GetLabels()) && ownsNamespace(namespaces, cd.Namespace) {
+				continue
+			}
+			if svc == nil {
+				// the service was removed from the spec, its prefix is all
+				// that is needed to find its resources
+				svc = &pagerdutyv1alpha1.AdditionalService{ServicePrefix: prefix}
+			}
+
+			err = r.handleDelete(pdclient, additionalServiceIntegration(pdi, *svc), cd)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !createServices || cd.DeletionTimestamp != nil || pdi.DeletionTimestamp != nil || !ownsNamespace(namespaces, cd.Namespace) {
+			continue
+		}
+		for _, svc := range pdi.Spec.AdditionalServices {
+			if !selects(svc.ClusterDeploymentSelector, cd.GetLabels()) {
+				continue
+			}
+
+			as := additionalServiceIntegration(pdi, svc)
+			err = r.handleCreate(pdclient, as, cd)
+			if err != nil {
+				r.setRetryReason(cd, retryReasonFor(err), err)
+				if createErr == nil {
+					createErr = err
+				}
+			}
+			// an account that is out of services is out of them for all
+			pdi.Status.Conditions = as.Status.Conditions
+		}
+	}
+
+	return createErr
+}
+
+// findAdditionalService returns the additional service of the
+// PagerDutyIntegration with the given service prefix, or nil.
+func findAdditionalService(pdi *pagerdutyv1alpha1.PagerDutyIntegration, servicePrefix string) *pagerdutyv1alpha1.AdditionalService {
+	for i := range pdi.Spec.AdditionalServices {
+		if pdi.Spec.AdditionalServices[i].ServicePrefix == servicePrefix {
+			return &pdi.Spec.AdditionalServices[i]
+		}
+	}
+	return nil
+}
+
+// selects returns true if the label selector selects a ClusterDeployment
+// with the given labels.
+func selects(labelSelector metav1.LabelSelector, cdLabels map[string]string) bool {
+	selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(cdLabels))
+}
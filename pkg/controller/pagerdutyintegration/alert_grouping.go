@@ -0,0 +1,46 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+)
+
+// setAlertGrouping passes spec.alertGrouping on to the PagerDuty services
+// created for pdData. Without it the grouping of the services is left to
+// PagerDuty.
+func setAlertGrouping(pdi *pagerdutyv1alpha1.PagerDutyIntegration, pdData *pd.Data) {
+	grouping := pdi.Spec.AlertGrouping
+	if grouping == nil {
+		return
+	}
+
+	pdData.AlertGrouping = &pd.AlertGrouping{Type: string(grouping.Type)}
+	switch grouping.Type {
+	case pagerdutyv1alpha1.AlertGroupingTime:
+		timeout := grouping.Timeout
+		pdData.AlertGrouping.Config = &pd.AlertGroupingConfig{Timeout: &timeout}
+	case pagerdutyv1alpha1.AlertGroupingContentBased:
+		aggregate := grouping.Aggregate
+		if aggregate == "" {
+			aggregate = "all"
+		}
+		pdData.AlertGrouping.Config = &pd.AlertGroupingConfig{
+			Aggregate: aggregate,
+			Fields:    grouping.Fields,
+		}
+	}
+}
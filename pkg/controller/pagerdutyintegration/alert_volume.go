@@ -0,0 +1,129 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// flagAlertVolumeAnomalies counts the incidents of the services of the given
+// clusters once the window of spec.alertVolumeAnomaly has passed since the
+// last count, and flags the clusters reaching deviationFactor times the
+// median count of the fleet. In between, clusters keep their previous
+// alertVolume. It returns the time of the last count and how long until the
+// next, 0 when the check is disabled.
+func (r *ReconcilePagerDutyIntegration) flagAlertVolumeAnomalies(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, clusters []pagerdutyv1alpha1.ClusterStatus) (*metav1.Time, time.Duration, error) {
+	if pdi.Spec.AlertVolumeAnomaly == nil {
+		for i := range clusters {
+			clusters[i].AlertVolume = nil
+		}
+		return nil, 0, nil
+	}
+
+	window := config.AlertVolumeDefaultWindow
+	if pdi.Spec.AlertVolumeAnomaly.Window != nil {
+		window = pdi.Spec.AlertVolumeAnomaly.Window.Duration
+	}
+	if window < config.AlertVolumeMinWindow {
+		window = config.AlertVolumeMinWindow
+	}
+	factor := pdi.Spec.AlertVolumeAnomaly.DeviationFactor
+	if factor == 0 {
+		factor = config.AlertVolumeDefaultDeviationFactor
+	}
+
+	now := r.now()
+	if last := pdi.Status.LastAlertVolumeTime; last != nil {
+		if wait := last.Add(window).Sub(now); wait > 0 {
+			return last, wait, nil
+		}
+	}
+
+	serviceIDs := []string{}
+	for _, cluster := range clusters {
+		if cluster.ServiceID != "" && cluster.State != pagerdutyv1alpha1.ClusterStateDeleting {
+			serviceIDs = append(serviceIDs, cluster.ServiceID)
+		}
+	}
+
+	counts, err := pdclient.CountServiceIncidents(serviceIDs, now.Add(-window), now)
+	if err != nil {
+		// counting again in a window is good enough
+		r.reqLogger.Error(err, "Failed to count the incidents of the PagerDuty services")
+		return pdi.Status.LastAlertVolumeTime, window, nil
+	}
+
+	median := medianIncidents(counts)
+	threshold := factor * median
+	if threshold < factor {
+		// a quiet fleet still needs a few incidents to single a cluster out
+		threshold = factor
+	}
+
+	for i := range clusters {
+		cluster := &clusters[i]
+		if cluster.State == pagerdutyv1alpha1.ClusterStateDeleting {
+			continue
+		}
+		count, ok := counts[cluster.ServiceID]
+		if !ok {
+			cluster.AlertVolume = nil
+			continue
+		}
+
+		cluster.AlertVolume = &pagerdutyv1alpha1.AlertVolumeStatus{Incidents: count}
+		if count >= threshold {
+			cluster.AlertVolume.Anomalous = true
+			cluster.AlertVolume.Note = fmt.Sprintf("%d incidents in the last %s, the fleet median is %d", count, window, median)
+			r.reqLogger.Info("Cluster alert volume is anomalous", "ClusterDeployment.Namespace", cluster.ClusterDeploymentNamespace, "ClusterDeployment.Name", cluster.ClusterDeploymentName, "Incidents", count, "FleetMedian", median)
+		}
+	}
+
+	return &metav1.Time{Time: now}, window, nil
+}
+
+// medianIncidents returns the median of the incident counts, 0 when there
+// are none.
+func medianIncidents(counts map[string]int) int {
+	if len(counts) == 0 {
+		return 0
+	}
+
+	values := make([]int, 0, len(counts))
+	for _, count := range counts {
+		values = append(values, count)
+	}
+	sort.Ints(values)
+	return values[len(values)/2]
+}
+
+// countAnomalousClusters returns how many of the clusters have an anomalous
+// alert volume.
+func countAnomalousClusters(clusters []pagerdutyv1alpha1.ClusterStatus) int {
+	anomalous := 0
+	for _, cluster := range clusters {
+		if cluster.AlertVolume != nil && cluster.AlertVolume.Anomalous {
+			anomalous++
+		}
+	}
+	return anomalous
+}
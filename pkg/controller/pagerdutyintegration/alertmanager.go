@@ -0,0 +1,68 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/kube"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// reconcileAlertmanagerSyncSet makes the Alertmanager configuration SyncSet
+// of the cluster match spec.alertmanagerConfig, the integration key and the
+// URL the cluster checks in at, if any, deleting it when the configuration
+// is disabled.
+func (r *ReconcilePagerDutyIntegration) reconcileAlertmanagerSyncSet(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, pdIntegrationKey string, heartbeatURL string) error {
+	name := naming.AlertmanagerSyncSetName(pdi.Spec.ServicePrefix, cd.Name)
+
+	if pdi.Spec.AlertmanagerConfig == nil {
+		return utils.DeleteSyncSet(name, cd.Namespace, r.client, r.reqLogger)
+	}
+
+	expected, err := kube.GenerateAlertmanagerSyncSetWithHeartbeat(cd.Namespace, name, cd.Name, pdIntegrationKey, heartbeatURL, pdi)
+	if err != nil {
+		return err
+	}
+
+	ss := &hivev1.SyncSet{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cd.Namespace}, ss)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		r.reqLogger.Info("Creating Alertmanager config syncset", "Name", name)
+		setOwnerLabel(expected, pdi)
+		if err = controllerutil.SetControllerReference(cd, expected, r.scheme); err != nil {
+			r.reqLogger.Error(err, "Error setting controller reference on Alertmanager config syncset")
+			return err
+		}
+		return r.client.Create(context.TODO(), expected)
+	}
+
+	if !kube.ProbeResourcesEqual(ss.Spec.Resources, expected.Spec.Resources) {
+		r.reqLogger.Info("Updating Alertmanager config syncset", "Name", name)
+		ss.Spec.Resources = expected.Spec.Resources
+		return r.client.Update(context.TODO(), ss)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"testing"
+
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/kube"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileAlertmanagerSyncSet(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	pdi := testPagerDutyIntegration()
+	pdi.Spec.AlertmanagerConfig = &pagerdutyv1alpha1.AlertmanagerConfig{
+		Name:      "alertmanager-pd",
+		Namespace: "openshift-monitoring",
+	}
+	cd := testClusterDeployment(true, true, true, false)
+	r := &ReconcilePagerDutyIntegration{
+		client:    fakekubeclient.NewFakeClient(cd),
+		scheme:    scheme.Scheme,
+		reqLogger: log,
+	}
+	key := types.NamespacedName{Name: naming.AlertmanagerSyncSetName(testServicePrefix, testClusterName), Namespace: testNamespace}
+
+	// created with the key
+	assert.NoError(t, r.reconcileAlertmanagerSyncSet(pdi, cd, "KEY1", ""))
+	ss := &hivev1.SyncSet{}
+	assert.NoError(t, r.client.Get(context.TODO(), key, ss))
+	expected, err := kube.GenerateAlertmanagerSyncSet(testNamespace, key.Name, testClusterName, "KEY1", pdi)
+	assert.NoError(t, err)
+	assert.True(t, kube.ProbeResourcesEqual(expected.Spec.Resources, ss.Spec.Resources))
+	assert.Len(t, ss.OwnerReferences, 1)
+
+	// updated when the key changes
+	assert.NoError(t, r.reconcileAlertmanagerSyncSet(pdi, cd, "KEY2", ""))
+	assert.NoError(t, r.client.Get(context.TODO(), key, ss))
+	expected, err = kube.GenerateAlertmanagerSyncSet(testNamespace, key.Name, testClusterName, "KEY2", pdi)
+	assert.NoError(t, err)
+	assert.True(t, kube.ProbeResourcesEqual(expected.Spec.Resources, ss.Spec.Resources))
+
+	// deleted once disabled
+	pdi.Spec.AlertmanagerConfig = nil
+	assert.NoError(t, r.reconcileAlertmanagerSyncSet(pdi, cd, "KEY2", ""))
+	err = r.client.Get(context.TODO(), key, ss)
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// setAPIKeyValid sets the APIKeyValid condition of the PDI, and records a
+// Warning event when the key becomes invalid. A valid key only updates a
+// condition already there, so PDIs whose key never failed don't carry it.
+func (r *ReconcilePagerDutyIntegration) setAPIKeyValid(pdi *pagerdutyv1alpha1.PagerDutyIntegration, valid bool, reason, message string) {
+	previous := findPagerDutyIntegrationCondition(pdi.Status.Conditions, pagerdutyv1alpha1.PagerDutyIntegrationConditionAPIKeyValid)
+	if valid && previous == nil {
+		return
+	}
+
+	condition := pagerdutyv1alpha1.PagerDutyIntegrationCondition{
+		Type:    pagerdutyv1alpha1.PagerDutyIntegrationConditionAPIKeyValid,
+		Status:  corev1.ConditionTrue,
+		Reason:  reason,
+		Message: logging.Redact(message),
+	}
+	if !valid {
+		condition.Status = corev1.ConditionFalse
+		if previous == nil || previous.Status != corev1.ConditionFalse {
+			r.recorder.Eventf(pdi, corev1.EventTypeWarning, "APIKeyInvalid",
+				"PagerDuty API key of secret %s/%s: %s", pdi.Spec.PagerdutyApiKeySecretRef.Namespace, pdi.Spec.PagerdutyApiKeySecretRef.Name, message)
+		}
+	}
+	setPagerDutyIntegrationCondition(&pdi.Status.Conditions, condition)
+}
+
+// saveAPIKeyInvalid marks the API key of the PDI invalid and persists the
+// status right away, as the reconcile stops short of its status update
+// without a key
+func (r *ReconcilePagerDutyIntegration) saveAPIKeyInvalid(pdi *pagerdutyv1alpha1.PagerDutyIntegration, reason, message string) {
+	previousConditions := pdi.Status.DeepCopy().Conditions
+	r.setAPIKeyValid(pdi, false, reason, message)
+	if equality.Semantic.DeepEqual(pdi.Status.Conditions, previousConditions) {
+		return
+	}
+	err := r.updateStatus(pdi)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to update the APIKeyValid condition")
+	}
+}
+
+// findPagerDutyIntegrationCondition returns the condition of the given type,
+// or nil if there is none
+func findPagerDutyIntegrationCondition(conditions []pagerdutyv1alpha1.PagerDutyIntegrationCondition, conditionType pagerdutyv1alpha1.PagerDutyIntegrationConditionType) *pagerdutyv1alpha1.PagerDutyIntegrationCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
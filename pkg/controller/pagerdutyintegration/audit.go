@@ -0,0 +1,93 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// auditServices polls the PagerDuty audit records when spec.auditPollInterval
+// has passed since the last poll, and records a Warning event on the PDI for
+// each change made to the service of one of the given ClusterDeployments
+// outside of the operator. It returns the time up to which records were
+// polled and how long until the next poll, 0 when the poller is disabled.
+func (r *ReconcilePagerDutyIntegration) auditServices(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cds []hivev1.ClusterDeployment) (*metav1.Time, time.Duration, error) {
+	if pdi.Spec.AuditPollInterval == nil {
+		return nil, 0, nil
+	}
+
+	interval := pdi.Spec.AuditPollInterval.Duration
+	if interval < config.AuditPollMinInterval {
+		interval = config.AuditPollMinInterval
+	}
+
+	now := r.now()
+	since := now.Add(-interval)
+	if last := pdi.Status.LastAuditPollTime; last != nil {
+		if wait := last.Add(interval).Sub(now); wait > 0 {
+			return last, wait, nil
+		}
+		since = last.Time
+	}
+
+	services := map[string]*hivev1.ClusterDeployment{}
+	for i := range cds {
+		cd := &cds[i]
+		if !cd.Spec.Installed || cd.DeletionTimestamp != nil {
+			continue
+		}
+		pdData := &pd.Data{}
+		err := pdData.ParseClusterConfig(r.client, cd.Namespace, naming.ConfigMapName(pdi.Spec.ServicePrefix, cd.Name))
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, 0, err
+		}
+		services[pdData.ServiceID] = cd
+	}
+
+	changes, err := pdclient.ListServiceChanges(since)
+	if err != nil {
+		// polling again on the next reconcile is good enough
+		r.reqLogger.Error(err, "Failed to poll PagerDuty audit records")
+		return pdi.Status.LastAuditPollTime, interval, nil
+	}
+
+	for _, change := range changes {
+		cd, ok := services[change.RootResource.ID]
+		if !ok {
+			continue
+		}
+		changer := change.Changer()
+		if changer == "" {
+			changer = "unknown"
+		}
+		r.reqLogger.Info("PagerDuty service changed outside of the operator", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name, "ServiceID", change.RootResource.ID, "Action", change.Action, "Changer", changer)
+		r.recorder.Eventf(pdi, corev1.EventTypeWarning, "ServiceModifiedOutOfBand",
+			"PagerDuty service %s of cluster %s/%s: %s by %s at %s", change.RootResource.ID, cd.Namespace, cd.Name, change.Action, changer, change.ExecutionTime)
+	}
+
+	return &metav1.Time{Time: now}, interval, nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"fmt"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cleanupIntegration tears down what the deleted PDI set up: the clusters
+// still carrying its finalizer, their additional services, the shared key
+// SyncSet and the retained services, then whatever PD service, ConfigMap,
+// Secret and SyncSet labeled with the PDI is left over, such as those a
+// cluster teardown failed to delete after its finalizer was removed. The
+// progress is persisted in the status, the finalizer of the PDI must only
+// be removed once no error is returned.
+func (r *ReconcilePagerDutyIntegration) cleanupIntegration(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cds []hivev1.ClusterDeployment) error {
+	status := &pagerdutyv1alpha1.CleanupStatus{LastAttemptTime: metav1.NewTime(r.now())}
+	err := r.teardownIntegration(pdclient, pdi, cds, status)
+	if err != nil {
+		status.Message = logging.Redact(err.Error())
+		r.reqLogger.Error(err, "PagerDutyIntegration not fully torn down", "ClustersRemaining", status.ClustersRemaining, "ObjectsRemaining", status.ObjectsRemaining)
+	}
+
+	pdi.Status.Cleanup = status
+	if updateErr := r.updateStatus(pdi); updateErr != nil && err == nil {
+		return updateErr
+	}
+	return err
+}
+
+// teardownIntegration does the work of cleanupIntegration, counting its
+// progress in status
+func (r *ReconcilePagerDutyIntegration) teardownIntegration(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cds []hivev1.ClusterDeployment, status *pagerdutyv1alpha1.CleanupStatus) error {
+	finalizer := config.PagerDutyFinalizerPrefix + pdi.Name
+
+	// a failing cluster does not hold up the others
+	var errs []error
+	for i := range cds {
+		cd := &cds[i]
+		if !utils.HasFinalizer(cd, finalizer) {
+			continue
+		}
+		if err := r.clusterHandler().Delete(pdclient, pdi, cd); err != nil {
+			status.ClustersRemaining++
+			errs = append(errs, err)
+			continue
+		}
+		status.ClustersCleanedUp++
+	}
+	if len(errs) > 0 {
+		// the objects of the remaining clusters are still theirs
+		return utilerrors.NewAggregate(errs)
+	}
+
+	// and the additional services of all clusters
+	err := r.reconcileAdditionalServices(pdclient, pdi, cds, false)
+	if err != nil {
+		return err
+	}
+
+	// the fleet no longer gets the shared integration key
+	err = r.deleteSelectorSyncSet(pdi)
+	if err != nil {
+		return err
+	}
+
+	// nothing will reuse the retained services anymore
+	_, err = r.expireRetainedServices(pdclient, pdi)
+	if err != nil {
+		return err
+	}
+
+	return r.deleteLeftoverObjects(pdclient, pdi, status)
+}
+
+// deleteLeftoverObjects deletes the ConfigMaps, PagerDutyServices, Secrets
+// and SyncSets labeled with the PDI, and the PD service of each ConfigMap,
+// counting those whose deletion failed in status
+func (r *ReconcilePagerDutyIntegration) deleteLeftoverObjects(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, status *pagerdutyv1alpha1.CleanupStatus) error {
+	owned := client.MatchingLabels{config.PagerDutyIntegrationLabel: pdi.Name}
+	var errs []error
+
+	configMaps := &corev1.ConfigMapList{}
+	if err := r.client.List(context.TODO(), configMaps, owned); err != nil {
+		return err
+	}
+	for i := range configMaps.Items {
+		cm := &configMaps.Items[i]
+		if err := r.deleteLeftoverService(pdclient, cm); err != nil {
+			status.ObjectsRemaining++
+			errs = append(errs, err)
+			// the ConfigMap is all that is left to find the service with
+			continue
+		}
+		if err := r.deleteLeftoverObject("ConfigMap", cm); err != nil {
+			status.ObjectsRemaining++
+			errs = append(errs, err)
+		}
+	}
+
+	// the ConfigMaps still hold the IDs of the services whose deletion failed
+	pdServices := &pagerdutyv1alpha1.PagerDutyServiceList{}
+	if err := r.client.List(context.TODO(), pdServices, owned); err != nil {
+		return err
+	}
+	for i := range pdServices.Items {
+		if err := r.deleteLeftoverObject("PagerDutyService", &pdServices.Items[i]); err != nil {
+			status.ObjectsRemaining++
+			errs = append(errs, err)
+		}
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := r.client.List(context.TODO(), secrets, owned); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		if err := r.deleteLeftoverObject("Secret", &secrets.Items[i]); err != nil {
+			status.ObjectsRemaining++
+			errs = append(errs, err)
+		}
+	}
+
+	syncSets := &hivev1.SyncSetList{}
+	if err := r.client.List(context.TODO(), syncSets, owned); err != nil {
+		return err
+	}
+	for i := range syncSets.Items {
+		if err := r.deleteLeftoverObject("SyncSet", &syncSets.Items[i]); err != nil {
+			status.ObjectsRemaining++
+			errs = append(errs, err)
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// deleteLeftoverService deletes the PD service whose ID the ConfigMap holds,
+// if any. A service already gone is not an error.
+func (r *ReconcilePagerDutyIntegration) deleteLeftoverService(pdclient pd.Client, cm *corev1.ConfigMap) error {
+	serviceID := cm.Data["SERVICE_ID"]
+	if serviceID == "" {
+		return nil
+	}
+
+	r.reqLogger.Info("Deleting leftover PD service", "Namespace", cm.Namespace, "ConfigMap", cm.Name, "ServiceID", serviceID)
+	err := pdclient.DeleteService(&pd.Data{ServiceID: serviceID, IntegrationID: cm.Data["INTEGRATION_ID"]})
+	if err != nil && !pd.IsNotFound(err) {
+		return fmt.Errorf("deleting PD service %s of ConfigMap %s/%s: %w", serviceID, cm.Namespace, cm.Name, err)
+	}
+	return nil
+}
+
+// deleteLeftoverObject deletes obj of the given kind, which may already be
+// gone
+func (r *ReconcilePagerDutyIntegration) deleteLeftoverObject(kind string, obj runtime.Object) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	r.reqLogger.Info("Deleting leftover "+kind, "Namespace", accessor.GetNamespace(), "Name", accessor.GetName())
+	err = r.client.Delete(context.TODO(), obj)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	mockpd "github.com/openshift/pagerduty-operator/pkg/pagerduty/mock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCleanupIntegration(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	owned := map[string]string{config.PagerDutyIntegrationLabel: testPagerDutyIntegrationName}
+	// left over by a cluster whose finalizer was removed although its
+	// service could not be deleted
+	leftoverConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "leftover-pd-config", Namespace: testNamespace, Labels: owned},
+		Data:       map[string]string{"SERVICE_ID": "leftover-service-id", "INTEGRATION_ID": "leftover-integration-id"},
+	}
+	leftoverSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "leftover-pd-secret", Namespace: testNamespace, Labels: owned},
+	}
+	leftoverSyncSet := &hivev1.SyncSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "leftover-pd-sync", Namespace: testNamespace, Labels: owned},
+	}
+	// another PDI's
+	otherConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "other-pd-config", Namespace: testNamespace, Labels: map[string]string{config.PagerDutyIntegrationLabel: "other"}},
+		Data:       map[string]string{"SERVICE_ID": "other-service-id"},
+	}
+	fleet := []hivev1.ClusterDeployment{
+		fleetClusterDeployment("cluster-1", false),
+		fleetClusterDeployment("cluster-2", false),
+	}
+	fleet[1].Finalizers = nil
+
+	tests := []struct {
+		name                  string
+		errs                  map[string]error
+		deleteServiceErr      error
+		expectErr             bool
+		expectCleanedUp       int
+		expectClustersLeft    int
+		expectObjectsLeft     int
+		expectDeleteService   bool
+		expectConfigMapExists bool
+	}{
+		{
+			name:                "Everything Torn Down",
+			expectCleanedUp:     1,
+			expectDeleteService: true,
+		},
+		{
+			name:                "Service Already Gone",
+			deleteServiceErr:    fmt.Errorf("HTTP response code: 404"),
+			expectCleanedUp:     1,
+			expectDeleteService: true,
+		},
+		{
+			name: "Cluster Teardown Failed",
+			errs: map[string]error{
+				"cluster-1": fmt.Errorf("HTTP response code: 500"),
+			},
+			expectErr:             true,
+			expectClustersLeft:    1,
+			expectConfigMapExists: true,
+		},
+		{
+			name:                  "Leftover Service Deletion Failed",
+			deleteServiceErr:      fmt.Errorf("HTTP response code: 500"),
+			expectErr:             true,
+			expectCleanedUp:       1,
+			expectObjectsLeft:     1,
+			expectDeleteService:   true,
+			expectConfigMapExists: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pdi := testPagerDutyIntegration()
+			deleted := metav1.NewTime(now)
+			pdi.DeletionTimestamp = &deleted
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockPDClient := mockpd.NewMockClient(mockCtrl)
+			if test.expectDeleteService {
+				mockPDClient.EXPECT().DeleteService(&pd.Data{ServiceID: "leftover-service-id", IntegrationID: "leftover-integration-id"}).Return(test.deleteServiceErr).Times(1)
+			}
+
+			r := &ReconcilePagerDutyIntegration{
+				client:    fakekubeclient.NewFakeClient(pdi, leftoverConfigMap, leftoverSecret, leftoverSyncSet, otherConfigMap),
+				scheme:    scheme.Scheme,
+				reqLogger: log,
+				clusters:  &fakeClusterHandler{errs: test.errs},
+				clock:     func() time.Time { return now },
+			}
+
+			// Act
+			err := r.cleanupIntegration(mockPDClient, pdi, fleet)
+
+			// Assert
+			assert.Equal(t, test.expectErr, err != nil)
+			persisted := testPagerDutyIntegration()
+			assert.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: pdi.Name, Namespace: pdi.Namespace}, persisted))
+			if assert.NotNil(t, persisted.Status.Cleanup) {
+				assert.Equal(t, test.expectCleanedUp, persisted.Status.Cleanup.ClustersCleanedUp)
+				assert.Equal(t, test.expectClustersLeft, persisted.Status.Cleanup.ClustersRemaining)
+				assert.Equal(t, test.expectObjectsLeft, persisted.Status.Cleanup.ObjectsRemaining)
+				assert.Equal(t, test.expectErr, persisted.Status.Cleanup.Message != "")
+				assert.True(t, now.Equal(persisted.Status.Cleanup.LastAttemptTime.Time))
+			}
+
+			err = r.client.Get(context.TODO(), types.NamespacedName{Name: leftoverConfigMap.Name, Namespace: testNamespace}, &corev1.ConfigMap{})
+			assert.Equal(t, test.expectConfigMapExists, err == nil)
+			err = r.client.Get(context.TODO(), types.NamespacedName{Name: leftoverSecret.Name, Namespace: testNamespace}, &corev1.Secret{})
+			assert.Equal(t, !test.expectErr || test.expectObjectsLeft > 0, kerrors.IsNotFound(err))
+			err = r.client.Get(context.TODO(), types.NamespacedName{Name: leftoverSyncSet.Name, Namespace: testNamespace}, &hivev1.SyncSet{})
+			assert.Equal(t, !test.expectErr || test.expectObjectsLeft > 0, kerrors.IsNotFound(err))
+			assert.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: otherConfigMap.Name, Namespace: testNamespace}, &corev1.ConfigMap{}))
+		})
+	}
+}
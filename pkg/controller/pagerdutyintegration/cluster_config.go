@@ -0,0 +1,87 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"sort"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// clusterConfig returns the PagerDutyClusterConfig overriding the settings
+// the PDI gives the service of cd, nil if there is none.
+func (r *ReconcilePagerDutyIntegration) clusterConfig(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) (*pagerdutyv1alpha1.PagerDutyClusterConfig, error) {
+	configList := &pagerdutyv1alpha1.PagerDutyClusterConfigList{}
+	err := r.client.List(context.TODO(), configList, client.InNamespace(cd.Namespace))
+	if err != nil {
+		return nil, err
+	}
+	return selectClusterConfig(configList.Items, pdi, cd), nil
+}
+
+// selectClusterConfig returns the config among configs that overrides the
+// settings the PDI gives the service of cd, nil if there is none. A config
+// naming the PDI takes precedence over one applying to every PDI, and among
+// several of the same kind the first by name wins.
+func selectClusterConfig(configs []pagerdutyv1alpha1.PagerDutyClusterConfig, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) *pagerdutyv1alpha1.PagerDutyClusterConfig {
+	candidates := []*pagerdutyv1alpha1.PagerDutyClusterConfig{}
+	for i := range configs {
+		cfg := &configs[i]
+		if cfg.Namespace != cd.Namespace || cfg.Spec.ClusterDeploymentRef.Name != cd.Name {
+			continue
+		}
+		if cfg.Spec.PagerDutyIntegration != "" && cfg.Spec.PagerDutyIntegration != pdi.Name {
+			continue
+		}
+		candidates = append(candidates, cfg)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		iNamed, jNamed := candidates[i].Spec.PagerDutyIntegration != "", candidates[j].Spec.PagerDutyIntegration != ""
+		if iNamed != jNamed {
+			return iNamed
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	return candidates[0]
+}
+
+// applyClusterConfig merges the overrides of cfg on top of the settings
+// the PDI gave pdData. Fields left empty in cfg keep those of the PDI.
+func applyClusterConfig(cfg *pagerdutyv1alpha1.PagerDutyClusterConfig, pdData *pd.Data) {
+	if cfg == nil {
+		return
+	}
+
+	if cfg.Spec.EscalationPolicy != "" {
+		pdData.EscalationPolicyID = cfg.Spec.EscalationPolicy
+	}
+	if cfg.Spec.IncidentUrgency != nil {
+		// the urgency of the config replaces the one of the PDI as a whole
+		setUrgency(cfg.Spec.IncidentUrgency, pdData)
+	}
+}
+
+// pagingDisabled returns true if cfg disables paging of the cluster
+func pagingDisabled(cfg *pagerdutyv1alpha1.PagerDutyClusterConfig) bool {
+	return cfg != nil && cfg.Spec.DisablePaging
+}
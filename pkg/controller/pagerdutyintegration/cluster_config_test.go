@@ -0,0 +1,140 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"testing"
+
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestClusterConfig(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	clusterConfig := func(name, namespace, cdName, pdiName string) *pagerdutyv1alpha1.PagerDutyClusterConfig {
+		return &pagerdutyv1alpha1.PagerDutyClusterConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: pagerdutyv1alpha1.PagerDutyClusterConfigSpec{
+				ClusterDeploymentRef: corev1.LocalObjectReference{Name: cdName},
+				PagerDutyIntegration: pdiName,
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		objects      []runtime.Object
+		expectConfig string
+	}{
+		{
+			name: "Test No Config",
+		},
+		{
+			name: "Test Configs Of Other Clusters And PDIs Ignored",
+			objects: []runtime.Object{
+				clusterConfig("other-cluster", testNamespace, "other", ""),
+				clusterConfig("other-namespace", "other", testClusterName, ""),
+				clusterConfig("other-pdi", testNamespace, testClusterName, "other"),
+			},
+		},
+		{
+			name: "Test Config Of Every PDI",
+			objects: []runtime.Object{
+				clusterConfig("b", testNamespace, testClusterName, ""),
+				clusterConfig("a", testNamespace, testClusterName, ""),
+			},
+			expectConfig: "a",
+		},
+		{
+			name: "Test Config Naming The PDI Preferred",
+			objects: []runtime.Object{
+				clusterConfig("a", testNamespace, testClusterName, ""),
+				clusterConfig("b", testNamespace, testClusterName, testPagerDutyIntegrationName),
+			},
+			expectConfig: "b",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &ReconcilePagerDutyIntegration{
+				client:    fakekubeclient.NewFakeClient(test.objects...),
+				reqLogger: log,
+			}
+
+			cfg, err := r.clusterConfig(testPagerDutyIntegration(), testClusterDeployment(true, true, true, false))
+
+			assert.NoError(t, err)
+			if test.expectConfig == "" {
+				assert.Nil(t, cfg)
+				return
+			}
+			assert.NotNil(t, cfg)
+			assert.Equal(t, test.expectConfig, cfg.Name)
+		})
+	}
+}
+
+func TestApplyClusterConfig(t *testing.T) {
+	pdi := testPagerDutyIntegration()
+	pdi.Spec.IncidentUrgency = &pagerdutyv1alpha1.IncidentUrgency{
+		Urgency:                    pagerdutyv1alpha1.UrgencyHigh,
+		OutsideSupportHoursUrgency: pagerdutyv1alpha1.UrgencyLow,
+		SupportHours: &pagerdutyv1alpha1.SupportHours{
+			TimeZone:   "UTC",
+			StartTime:  "09:00:00",
+			EndTime:    "17:00:00",
+			DaysOfWeek: []uint{1, 2, 3, 4, 5},
+		},
+	}
+	newData := func() *pd.Data {
+		pdData := &pd.Data{EscalationPolicyID: pdi.Spec.EscalationPolicy}
+		setIncidentUrgency(pdi, pdData)
+		return pdData
+	}
+
+	// no config keeps the settings of the PDI
+	pdData := newData()
+	applyClusterConfig(nil, pdData)
+	assert.Equal(t, newData(), pdData)
+
+	// empty fields keep them too
+	pdData = newData()
+	applyClusterConfig(&pagerdutyv1alpha1.PagerDutyClusterConfig{}, pdData)
+	assert.Equal(t, newData(), pdData)
+
+	// overrides replace them
+	pdData = newData()
+	applyClusterConfig(&pagerdutyv1alpha1.PagerDutyClusterConfig{
+		Spec: pagerdutyv1alpha1.PagerDutyClusterConfigSpec{
+			EscalationPolicy: "OVERRIDE",
+			IncidentUrgency:  &pagerdutyv1alpha1.IncidentUrgency{Urgency: pagerdutyv1alpha1.UrgencyLow},
+		},
+	}, pdData)
+	assert.Equal(t, "OVERRIDE", pdData.EscalationPolicyID)
+	assert.Equal(t, string(pagerdutyv1alpha1.UrgencyLow), pdData.Urgency)
+	assert.Equal(t, "", pdData.OutsideSupportHoursUrgency)
+	assert.Nil(t, pdData.SupportHours)
+}
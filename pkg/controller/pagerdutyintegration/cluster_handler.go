@@ -0,0 +1,169 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// clusterHandler sets up, tears down and verifies the PagerDuty service of
+// one cluster for a PagerDutyIntegration. Reconcile walks the fleet and
+// leaves each cluster to it, so how a fleet is handled, such as a cluster
+// failing halfway through, can be tested without setting each cluster up.
+type clusterHandler interface {
+	// Create sets up the cluster's PagerDuty service, secret and syncset
+	Create(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) error
+	// Delete tears down what Create set up and removes the cluster's finalizer
+	Delete(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) error
+	// Verify checks the cluster's PagerDuty service still exists and
+	// repairs its drift
+	Verify(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) (pagerdutyv1alpha1.ClusterCondition, error)
+}
+
+// defaultClusterHandler is the clusterHandler of the operator
+type defaultClusterHandler struct {
+	r *ReconcilePagerDutyIntegration
+}
+
+func (h defaultClusterHandler) Create(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) error {
+	return h.r.handleCreate(pdclient, pdi, cd)
+}
+
+func (h defaultClusterHandler) Delete(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) error {
+	return h.r.handleDelete(pdclient, pdi, cd)
+}
+
+func (h defaultClusterHandler) Verify(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) (pagerdutyv1alpha1.ClusterCondition, error) {
+	return h.r.serviceVerificationCondition(pdclient, pdi, cd)
+}
+
+// clusterHandler returns the handler of the clusters, the operator's unless
+// a test set another
+func (r *ReconcilePagerDutyIntegration) clusterHandler() clusterHandler {
+	if r.clusters != nil {
+		return r.clusters
+	}
+	return defaultClusterHandler{r: r}
+}
+
+// now returns the current time, from the clock of the reconciler if a test
+// set one
+func (r *ReconcilePagerDutyIntegration) now() time.Time {
+	if r.clock != nil {
+		return r.clock()
+	}
+	return time.Now()
+}
+
+// deleteClusters tears down the clusters with the PDI's finalizer that are
+// being deleted or are no longer selected by it. It stops at the first
+// cluster failing to be torn down.
+func (r *ReconcilePagerDutyIntegration) deleteClusters(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, finalizer string, all []hivev1.ClusterDeployment, matching []hivev1.ClusterDeployment) error {
+	for _, cd := range all {
+		if !utils.HasFinalizer(&cd, finalizer) {
+			continue
+		}
+		if cd.DeletionTimestamp == nil {
+			// it has a finalizer and is NOT being deleted.
+			// check if it should have PD setup or not (did it drop out of the PDI?)
+			if findClusterDeployment(matching, cd.Namespace, cd.Name) != nil {
+				continue
+			}
+			r.setRetryReason(&cd, pagerdutyv1alpha1.RetryReasonUnmanaged, nil)
+		}
+
+		err := r.clusterHandler().Delete(pdclient, pdi, &cd)
+		r.recordOperation(err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createClusters sets up the selected clusters that are not being deleted,
+// up to maxConcurrentClusterSyncs at once. A failing cluster does not hold
+// up the others, their errors are returned together once all were handled.
+func (r *ReconcilePagerDutyIntegration) createClusters(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, matching []hivev1.ClusterDeployment, referencesValid bool) error {
+	var errs []error
+	var concurrent []*hivev1.ClusterDeployment
+	for i := range matching {
+		cd := &matching[i]
+		if cd.DeletionTimestamp != nil {
+			continue
+		}
+		if !referencesValid {
+			// the ReferencesValid condition tells which are missing
+			r.setRetryReason(cd, pagerdutyv1alpha1.RetryReasonPDError, nil)
+			continue
+		}
+		// restoring a retained service persists the status of the PDI
+		// right away, which is left to the reconcile itself
+		if r.maxConcurrentClusterSyncs > 1 && findRetainedService(pdi, cd) == nil {
+			concurrent = append(concurrent, cd)
+			continue
+		}
+		if err := r.createCluster(pdclient, pdi, cd); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(concurrent) > 0 {
+		for _, err := range r.createClustersConcurrently(pdclient, pdi, concurrent) {
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	for _, err := range errs {
+		if pd.IsRateLimited(err) {
+			// so the reconcile is requeued once the API accepts calls again
+			return err
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// createCluster sets up one cluster and records the outcome
+func (r *ReconcilePagerDutyIntegration) createCluster(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) error {
+	err := r.clusterHandler().Create(pdclient, pdi, cd)
+	r.recordOperation(err)
+	if err != nil {
+		r.setRetryReason(cd, retryReasonFor(err), err)
+		if pd.IsAPIError(err) {
+			r.recordClusterEvent(pdi, cd, corev1.EventTypeWarning, eventPDAPIError,
+				"Setting up the PD service failed: %v", err)
+		}
+	}
+	return err
+}
+
+// findClusterDeployment returns the ClusterDeployment namespace/name of cds,
+// or nil if there is none
+func findClusterDeployment(cds []hivev1.ClusterDeployment, namespace, name string) *hivev1.ClusterDeployment {
+	for i := range cds {
+		if cds[i].Namespace == namespace && cds[i].Name == name {
+			return &cds[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,437 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeClusterHandler records the clusters it handles and fails those listed
+// in errs
+type fakeClusterHandler struct {
+	mu       sync.Mutex
+	errs     map[string]error
+	created  []string
+	deleted  []string
+	verified []string
+}
+
+func (h *fakeClusterHandler) Create(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.created = append(h.created, cd.Name)
+	return h.errs[cd.Name]
+}
+
+func (h *fakeClusterHandler) Delete(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) error {
+	h.deleted = append(h.deleted, cd.Name)
+	return h.errs[cd.Name]
+}
+
+func (h *fakeClusterHandler) Verify(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) (pagerdutyv1alpha1.ClusterCondition, error) {
+	h.verified = append(h.verified, cd.Name)
+	return pagerdutyv1alpha1.ClusterCondition{
+		Type:   pagerdutyv1alpha1.ClusterConditionServiceVerificationFailed,
+		Status: corev1.ConditionFalse,
+		Reason: "ServiceFound",
+	}, h.errs[cd.Name]
+}
+
+// fleetClusterDeployment returns an installed ClusterDeployment of the test
+// namespace with the PDI's finalizer
+func fleetClusterDeployment(name string, isDeleting bool) hivev1.ClusterDeployment {
+	cd := hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  testNamespace,
+			Finalizers: []string{config.PagerDutyFinalizerPrefix + testPagerDutyIntegrationName},
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			ClusterName: name,
+			Installed:   true,
+		},
+	}
+	if isDeleting {
+		now := metav1.Now()
+		cd.DeletionTimestamp = &now
+	}
+	return cd
+}
+
+func TestCreateClusters(t *testing.T) {
+	fleet := []hivev1.ClusterDeployment{
+		fleetClusterDeployment("cluster-1", false),
+		fleetClusterDeployment("cluster-2", false),
+		fleetClusterDeployment("cluster-3", true),
+		fleetClusterDeployment("cluster-4", false),
+	}
+
+	tests := []struct {
+		name            string
+		errs            map[string]error
+		referencesValid bool
+		expectCreated   []string
+		expectErr       string
+		expectReasons   map[string]pagerdutyv1alpha1.RetryReason
+	}{
+		{
+			name:            "All Clusters Created",
+			referencesValid: true,
+			expectCreated:   []string{"cluster-1", "cluster-2", "cluster-4"},
+		},
+		{
+			name: "Failure Mid-Fleet",
+			errs: map[string]error{
+				"cluster-2": fmt.Errorf("HTTP response code: 500"),
+			},
+			referencesValid: true,
+			expectCreated:   []string{"cluster-1", "cluster-2", "cluster-4"},
+			expectErr:       "HTTP response code: 500",
+			expectReasons: map[string]pagerdutyv1alpha1.RetryReason{
+				testNamespace + "/cluster-2": pagerdutyv1alpha1.RetryReasonPDError,
+			},
+		},
+		{
+			name: "Rate Limited Error Returned",
+			errs: map[string]error{
+				"cluster-1": &pd.RateLimitError{RetryAfter: time.Minute},
+				"cluster-4": fmt.Errorf("HTTP response code: 500"),
+			},
+			referencesValid: true,
+			expectCreated:   []string{"cluster-1", "cluster-2", "cluster-4"},
+			expectErr:       (&pd.RateLimitError{RetryAfter: time.Minute}).Error(),
+			expectReasons: map[string]pagerdutyv1alpha1.RetryReason{
+				testNamespace + "/cluster-1": pagerdutyv1alpha1.RetryReasonPDRateLimited,
+				testNamespace + "/cluster-4": pagerdutyv1alpha1.RetryReasonPDError,
+			},
+		},
+		{
+			name: "Errors Aggregated",
+			errs: map[string]error{
+				"cluster-1": fmt.Errorf("HTTP response code: 500"),
+				"cluster-4": fmt.Errorf("HTTP response code: 502"),
+			},
+			referencesValid: true,
+			expectCreated:   []string{"cluster-1", "cluster-2", "cluster-4"},
+			expectErr:       "[HTTP response code: 500, HTTP response code: 502]",
+			expectReasons: map[string]pagerdutyv1alpha1.RetryReason{
+				testNamespace + "/cluster-1": pagerdutyv1alpha1.RetryReasonPDError,
+				testNamespace + "/cluster-4": pagerdutyv1alpha1.RetryReasonPDError,
+			},
+		},
+		{
+			name:            "References Invalid",
+			referencesValid: false,
+			expectReasons: map[string]pagerdutyv1alpha1.RetryReason{
+				testNamespace + "/cluster-1": pagerdutyv1alpha1.RetryReasonPDError,
+				testNamespace + "/cluster-2": pagerdutyv1alpha1.RetryReasonPDError,
+				testNamespace + "/cluster-4": pagerdutyv1alpha1.RetryReasonPDError,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := &fakeClusterHandler{errs: test.errs}
+			r := &ReconcilePagerDutyIntegration{reqLogger: log, clusters: handler}
+
+			err := r.createClusters(nil, testPagerDutyIntegration(), fleet, test.referencesValid)
+
+			if test.expectErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectErr)
+			}
+			assert.Equal(t, test.expectCreated, handler.created)
+			assert.Len(t, r.retryReasons, len(test.expectReasons))
+			for key, reason := range test.expectReasons {
+				assert.Equal(t, reason, r.retryReasons[key], key)
+			}
+		})
+	}
+}
+
+func TestCreateClustersConcurrently(t *testing.T) {
+	var fleet []hivev1.ClusterDeployment
+	var names []string
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("cluster-%d", i)
+		fleet = append(fleet, fleetClusterDeployment(name, false))
+		names = append(names, name)
+	}
+	retained := fleetClusterDeployment("cluster-retained", false)
+	fleet = append(fleet, retained)
+	names = append(names, retained.Name)
+
+	pdi := testPagerDutyIntegration()
+	pdi.Status.RetainedServices = []pagerdutyv1alpha1.RetainedService{{
+		ClusterDeploymentNamespace: retained.Namespace,
+		ClusterDeploymentName:      retained.Name,
+		ClusterID:                  retained.Spec.ClusterName,
+		ServiceID:                  "retained-service-id",
+	}}
+	handler := &fakeClusterHandler{errs: map[string]error{
+		"cluster-3": fmt.Errorf("HTTP response code: 500"),
+		"cluster-7": fmt.Errorf("HTTP response code: 502"),
+	}}
+	r := &ReconcilePagerDutyIntegration{reqLogger: log, clusters: handler, maxConcurrentClusterSyncs: 4}
+
+	err := r.createClusters(nil, pdi, fleet, true)
+
+	// the errors come in the order of the fleet whichever worker was first
+	assert.EqualError(t, err, "[HTTP response code: 500, HTTP response code: 502]")
+	assert.ElementsMatch(t, names, handler.created)
+	// the cluster with a retained service is set up before the workers start
+	assert.Equal(t, retained.Name, handler.created[0])
+	assert.Equal(t, map[string]pagerdutyv1alpha1.RetryReason{
+		testNamespace + "/cluster-3": pagerdutyv1alpha1.RetryReasonPDError,
+		testNamespace + "/cluster-7": pagerdutyv1alpha1.RetryReasonPDError,
+	}, r.retryReasons)
+	assert.Equal(t, operationCounts{succeeded: 9, failed: 2}, r.operations)
+	assert.Nil(t, r.mu)
+}
+
+// escrowingClusterHandler escrows the integration key "key-<cluster name>"
+// of each cluster it sets up, as handleCreate does
+type escrowingClusterHandler struct {
+	*fakeClusterHandler
+	r *ReconcilePagerDutyIntegration
+}
+
+func (h escrowingClusterHandler) Create(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) error {
+	if err := h.fakeClusterHandler.Create(pdclient, pdi, cd); err != nil {
+		return err
+	}
+	return h.r.escrowIntegrationKey(pdi, cd, "key-"+cd.Name)
+}
+
+// secretWritesClient counts the creates and updates of Secrets, and fails
+// them with err if set
+type secretWritesClient struct {
+	client.Client
+	writes *int32
+	err    error
+}
+
+func (c secretWritesClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	if _, ok := obj.(*corev1.Secret); ok {
+		atomic.AddInt32(c.writes, 1)
+		if c.err != nil {
+			return c.err
+		}
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c secretWritesClient) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	if _, ok := obj.(*corev1.Secret); ok {
+		atomic.AddInt32(c.writes, 1)
+		if c.err != nil {
+			return c.err
+		}
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func TestCreateClustersConcurrentlyEscrow(t *testing.T) {
+	escrowSecretName := "pd-escrow"
+
+	tests := []struct {
+		name       string
+		writeErr   error
+		existing   map[string][]byte
+		expectData map[string][]byte
+	}{
+		{
+			name: "Test Escrow Secret Created Once",
+		},
+		{
+			name:     "Test Escrow Secret Updated Once",
+			existing: map[string][]byte{"other-namespace.other-cluster": []byte("other-key")},
+		},
+		{
+			name:     "Test Escrow Failed",
+			writeErr: fmt.Errorf("secrets is forbidden"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			var fleet []hivev1.ClusterDeployment
+			expectData := map[string][]byte{}
+			for key, value := range test.existing {
+				expectData[key] = value
+			}
+			for i := 0; i < 6; i++ {
+				cd := fleetClusterDeployment(fmt.Sprintf("cluster-%d", i), false)
+				fleet = append(fleet, cd)
+				expectData[escrowKey(&cd)] = []byte("key-" + cd.Name)
+			}
+
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.EscrowSecretRef = &corev1.SecretReference{Namespace: config.OperatorNamespace, Name: escrowSecretName}
+			var objects []runtime.Object
+			if test.existing != nil {
+				objects = append(objects, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: config.OperatorNamespace, Name: escrowSecretName},
+					Data:       test.existing,
+				})
+			}
+			writes := new(int32)
+			r := &ReconcilePagerDutyIntegration{
+				client:                    secretWritesClient{Client: fakekubeclient.NewFakeClient(objects...), writes: writes, err: test.writeErr},
+				reqLogger:                 log,
+				maxConcurrentClusterSyncs: 3,
+			}
+			r.clusters = escrowingClusterHandler{fakeClusterHandler: &fakeClusterHandler{}, r: r}
+
+			// Act
+			err := r.createClusters(nil, pdi, fleet, true)
+
+			// Assert
+			assert.Equal(t, int32(1), atomic.LoadInt32(writes), "escrow secret not written once")
+			assert.Nil(t, r.escrowKeys)
+			if test.writeErr != nil {
+				assert.Error(t, err)
+				assert.Len(t, r.retryReasons, len(fleet))
+				assert.Equal(t, operationCounts{failed: len(fleet)}, r.operations)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, operationCounts{succeeded: len(fleet)}, r.operations)
+			escrow := &corev1.Secret{}
+			assert.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: config.OperatorNamespace, Name: escrowSecretName}, escrow))
+			assert.Equal(t, expectData, escrow.Data)
+		})
+	}
+}
+
+func TestDeleteClusters(t *testing.T) {
+	unselected := fleetClusterDeployment("cluster-unselected", false)
+	withoutFinalizer := fleetClusterDeployment("cluster-without-finalizer", true)
+	withoutFinalizer.Finalizers = nil
+	all := []hivev1.ClusterDeployment{
+		fleetClusterDeployment("cluster-1", true),
+		fleetClusterDeployment("cluster-selected", false),
+		unselected,
+		withoutFinalizer,
+		fleetClusterDeployment("cluster-2", true),
+	}
+	matching := []hivev1.ClusterDeployment{all[1]}
+
+	tests := []struct {
+		name          string
+		errs          map[string]error
+		expectDeleted []string
+		expectErr     bool
+	}{
+		{
+			name:          "Deleted And Unselected Clusters Torn Down",
+			expectDeleted: []string{"cluster-1", "cluster-unselected", "cluster-2"},
+		},
+		{
+			name: "Failure Stops Teardown",
+			errs: map[string]error{
+				"cluster-unselected": fmt.Errorf("HTTP response code: 500"),
+			},
+			expectDeleted: []string{"cluster-1", "cluster-unselected"},
+			expectErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := &fakeClusterHandler{errs: test.errs}
+			r := &ReconcilePagerDutyIntegration{reqLogger: log, clusters: handler}
+
+			err := r.deleteClusters(nil, testPagerDutyIntegration(), config.PagerDutyFinalizerPrefix+testPagerDutyIntegrationName, all, matching)
+
+			assert.Equal(t, test.expectErr, err != nil)
+			assert.Equal(t, test.expectDeleted, handler.deleted)
+			assert.Equal(t, pagerdutyv1alpha1.RetryReasonUnmanaged, r.retryReason(&unselected))
+		})
+	}
+}
+
+func TestClusterStatusesVerificationClock(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	lastVerified := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		now            time.Time
+		expectVerified []string
+	}{
+		{
+			name: "Slot Not Reached",
+			now:  lastVerified.Add(time.Minute),
+		},
+		{
+			name:           "Slot Passed",
+			now:            lastVerified.Add(config.ResyncPeriod),
+			expectVerified: []string{"cluster-1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pdi := testPagerDutyIntegration()
+			pdi.Status.Clusters = []pagerdutyv1alpha1.ClusterStatus{
+				{
+					ClusterDeploymentNamespace: testNamespace,
+					ClusterDeploymentName:      "cluster-1",
+					LastVerifiedTime:           &metav1.Time{Time: lastVerified},
+				},
+			}
+			handler := &fakeClusterHandler{}
+			r := &ReconcilePagerDutyIntegration{
+				client:    fakekubeclient.NewFakeClient(),
+				reqLogger: log,
+				clusters:  handler,
+				clock:     func() time.Time { return test.now },
+			}
+
+			statuses, _, err := r.clusterStatuses(nil, pdi, []hivev1.ClusterDeployment{fleetClusterDeployment("cluster-1", false)})
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectVerified, handler.verified)
+			if assert.Len(t, statuses, 1) && test.expectVerified != nil {
+				assert.Equal(t, test.now, statuses[0].LastVerifiedTime.Time)
+			}
+		})
+	}
+}
@@ -0,0 +1,491 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hiveintv1alpha1 "github.com/openshift/hive/pkg/apis/hiveinternal/v1alpha1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/resync"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// clusterStatuses returns the status of each of the given ClusterDeployments
+// that is installed. Clusters being deleted keep their last status, marked
+// as Deleting, until their ClusterDeployment is gone. Conditions that did not
+// change status keep their previous transition time. The PagerDuty service of
+// clusters whose resync slot has passed is verified on the way, test alerts
+// that are due are sent, and missed heartbeats are paged on. It also returns
+// how long until the next slot, test alert or heartbeat deadline of any of
+// the clusters.
+func (r *ReconcilePagerDutyIntegration) clusterStatuses(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cds []hivev1.ClusterDeployment) ([]pagerdutyv1alpha1.ClusterStatus, time.Duration, error) {
+	now := r.now()
+	period := r.resyncPeriodOf(pdi)
+	window := resync.Window(period, config.ResyncSpreadPerCluster, len(cds))
+	next := period
+
+	statuses := []pagerdutyv1alpha1.ClusterStatus{}
+	for i := range cds {
+		cd := &cds[i]
+		if !cd.Spec.Installed {
+			continue
+		}
+		if cd.DeletionTimestamp != nil {
+			if previous := findClusterStatus(pdi.Status.Clusters, cd.Namespace, cd.Name); previous != nil {
+				statuses = append(statuses, deletingClusterStatus(pdi, previous))
+			}
+			continue
+		}
+
+		status := pagerdutyv1alpha1.ClusterStatus{
+			ClusterDeploymentNamespace: cd.Namespace,
+			ClusterDeploymentName:      cd.Name,
+		}
+		if previous := findClusterStatus(pdi.Status.Clusters, cd.Namespace, cd.Name); previous != nil {
+			status.Conditions = append(status.Conditions, previous.Conditions...)
+			status.LastVerifiedTime = previous.LastVerifiedTime
+			status.TestAlert = previous.TestAlert
+			status.AlertVolume = previous.AlertVolume
+			status.Suggestions = previous.Suggestions
+		}
+
+		condition, err := r.syncSetCondition(pdi, cd)
+		if err != nil {
+			return nil, 0, err
+		}
+		if condition.Status == corev1.ConditionTrue && !syncSetFailed(status.Conditions) {
+			r.recordClusterEvent(pdi, cd, corev1.EventTypeWarning, eventSecretSyncFailed,
+				"Hive failed to apply the integration key to the cluster: %s", condition.Message)
+		}
+		setClusterCondition(&status.Conditions, condition)
+
+		status.RetryReason = r.retryReason(cd)
+		if status.RetryReason == "" && condition.Status != corev1.ConditionFalse {
+			status.RetryReason = pagerdutyv1alpha1.RetryReasonSecretSyncPending
+		}
+
+		key := cd.Namespace + "/" + cd.Name
+		if status.LastVerifiedTime == nil {
+			// the service was just set up by handleCreate, start the schedule from now
+			status.LastVerifiedTime = &metav1.Time{Time: now}
+		} else if !resync.NextSlot(key, period, window, status.LastVerifiedTime.Time).After(now) {
+			condition, err = r.clusterHandler().Verify(pdclient, pdi, cd)
+			r.recordOperation(err)
+			if err != nil {
+				return nil, 0, err
+			}
+			setClusterCondition(&status.Conditions, condition)
+			if pdi.Spec.DeliveryProbe != nil {
+				setClusterCondition(&status.Conditions, r.deliveryProbeCondition(pdi, cd))
+			}
+			status.LastVerifiedTime = &metav1.Time{Time: now}
+		}
+		if pdi.Spec.DeliveryProbe == nil {
+			removeClusterCondition(&status.Conditions, pagerdutyv1alpha1.ClusterConditionDeliveryVerificationFailed)
+		}
+
+		cm := &corev1.ConfigMap{}
+		err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: naming.ConfigMapName(pdi.Spec.ServicePrefix, cd.Name)}, cm)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, 0, err
+		}
+		pdService, err := r.pagerDutyService(cd.Namespace, naming.ConfigMapName(pdi.Spec.ServicePrefix, cd.Name))
+		if err != nil {
+			return nil, 0, err
+		}
+		status.ServiceID = cm.Data["SERVICE_ID"]
+		if pdService != nil {
+			status.ServiceID = pdService.Spec.ServiceID
+		}
+		status.LastKeyRotationTime, _ = keyRotationTime(cm)
+		status.State, status.LastError = clusterState(&status, r.retryError(cd))
+		setClusterCondition(&status.Conditions, readyCondition(&status))
+		setClusterCondition(&status.Conditions, degradedCondition(&status))
+		removeClusterCondition(&status.Conditions, pagerdutyv1alpha1.ClusterConditionDeleting)
+		if wait := resync.NextSlot(key, period, window, status.LastVerifiedTime.Time).Sub(now); wait < next {
+			next = wait
+		}
+
+		testAlert, wait, err := r.testAlertStatus(pdclient, pdi, cd, status.TestAlert, now)
+		if err != nil {
+			return nil, 0, err
+		}
+		status.TestAlert = testAlert
+		if wait > 0 && wait < next {
+			next = wait
+		}
+
+		if pdi.Spec.Heartbeat != nil {
+			hb, ok, wait, err := r.heartbeatCondition(pdclient, pdi, cd, status.Conditions, now)
+			if err != nil {
+				return nil, 0, err
+			}
+			if ok {
+				setClusterCondition(&status.Conditions, hb)
+			}
+			if wait > 0 && wait < next {
+				next = wait
+			}
+		} else {
+			removeClusterCondition(&status.Conditions, pagerdutyv1alpha1.ClusterConditionHeartbeatMissed)
+		}
+
+		if pdService != nil {
+			err = r.updatePagerDutyServiceStatus(pdService, &status)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	if next < config.ResyncMinInterval {
+		next = config.ResyncMinInterval
+	}
+	return statuses, next, nil
+}
+
+// serviceVerificationCondition checks that the PagerDuty service recorded in
+// the cluster's ConfigMap still exists, and if it does recreates its
+// integration if it was deleted, repairs its drift and reconciles its tags.
+func (r *ReconcilePagerDutyIntegration) serviceVerificationCondition(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) (pagerdutyv1alpha1.ClusterCondition, error) {
+	condition := pagerdutyv1alpha1.ClusterCondition{
+		Type:   pagerdutyv1alpha1.ClusterConditionServiceVerificationFailed,
+		Status: corev1.ConditionFalse,
+		Reason: "ServiceFound",
+	}
+
+	pdData := &pd.Data{
+		ClusterID:          cd.Spec.ClusterName,
+		BaseDomain:         cd.Spec.BaseDomain,
+		EscalationPolicyID: escalationPolicy(pdi, cd),
+		Team:               pdi.Spec.Team,
+		ServicePrefix:      pdi.Spec.ServicePrefix,
+		NormalizeName:      pdi.Spec.NormalizeServiceNames,
+		IntegrationType:    string(pdi.Spec.IntegrationType),
+	}
+	r.setTimeouts(pdi, cd, pdData)
+	setServiceName(pdi, cd, pdData)
+	setClusterMetadata(cd, pdData)
+	setIncidentUrgency(pdi, pdData)
+	setAlertGrouping(pdi, pdData)
+	clusterConfig, err := r.clusterConfig(pdi, cd)
+	if err != nil {
+		return condition, err
+	}
+	applyClusterConfig(clusterConfig, pdData)
+	err = pdData.ParseClusterConfig(r.client, cd.Namespace, naming.ConfigMapName(pdi.Spec.ServicePrefix, cd.Name))
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// handleCreate will set the service up again
+			condition.Status = corev1.ConditionUnknown
+			condition.Reason = "ConfigMapNotFound"
+			return condition, nil
+		}
+		return condition, err
+	}
+
+	state, err := r.decommissionState(cd, naming.ConfigMapName(pdi.Spec.ServicePrefix, cd.Name))
+	if err != nil {
+		return condition, err
+	}
+	if state == decommissionDeleted {
+		// the service of the account migrated to takes over once the migration is completed
+		condition.Reason = "ServiceDecommissioned"
+		return condition, nil
+	}
+
+	service, err := pdclient.GetService(pdData)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to verify PagerDuty service", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name, "ServiceID", pdData.ServiceID)
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "GetServiceFailed"
+		condition.Message = logging.Redact(err.Error())
+		return condition, nil
+	}
+
+	recreated, err := r.recreateMissingIntegration(pdclient, pdi, cd, naming.ConfigMapName(pdi.Spec.ServicePrefix, cd.Name), pdData)
+	if err != nil {
+		return condition, err
+	}
+	if recreated {
+		// handleCreate syncs the key of the new integration to a missing Secret
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: cd.Namespace, Name: naming.SecretName(pdi.Spec.ServicePrefix, cd.Name)}}
+		err = r.client.Delete(context.TODO(), secret)
+		if err != nil && !errors.IsNotFound(err) {
+			return condition, err
+		}
+	}
+
+	r.repairServiceDrift(pdclient, pdi, cd, pdData, service)
+	r.reconcileServiceTags(pdclient, pdi, cd, pdData)
+	r.reconcileServiceDependencies(pdclient, pdi, cd, pdData)
+	return condition, nil
+}
+
+// clusterState summarizes the status of a cluster, and returns why it is
+// Failed if it is. An error setting the cluster up in this reconcile takes
+// precedence over a failed condition.
+func clusterState(status *pagerdutyv1alpha1.ClusterStatus, retryError string) (pagerdutyv1alpha1.ClusterState, string) {
+	if retryError != "" {
+		return pagerdutyv1alpha1.ClusterStateFailed, retryError
+	}
+	if condition := failedCondition(status); condition != nil {
+		message := condition.Message
+		if message == "" {
+			message = condition.Reason
+		}
+		return pagerdutyv1alpha1.ClusterStateFailed, message
+	}
+	if status.RetryReason != "" {
+		return pagerdutyv1alpha1.ClusterStatePending, ""
+	}
+	return pagerdutyv1alpha1.ClusterStateReady, ""
+}
+
+// failedCondition returns the first condition of the cluster reporting a
+// failure, or nil.
+func failedCondition(status *pagerdutyv1alpha1.ClusterStatus) *pagerdutyv1alpha1.ClusterCondition {
+	for i := range status.Conditions {
+		switch status.Conditions[i].Type {
+		case pagerdutyv1alpha1.ClusterConditionSyncSetFailed,
+			pagerdutyv1alpha1.ClusterConditionServiceVerificationFailed,
+			pagerdutyv1alpha1.ClusterConditionDeliveryVerificationFailed:
+			if status.Conditions[i].Status == corev1.ConditionTrue {
+				return &status.Conditions[i]
+			}
+		}
+	}
+	return nil
+}
+
+// readyCondition reports whether the cluster is in the Ready state, and if
+// not why.
+func readyCondition(status *pagerdutyv1alpha1.ClusterStatus) pagerdutyv1alpha1.ClusterCondition {
+	condition := pagerdutyv1alpha1.ClusterCondition{
+		Type:   pagerdutyv1alpha1.ClusterConditionReady,
+		Status: corev1.ConditionTrue,
+		Reason: "IntegrationReady",
+	}
+	if status.State == pagerdutyv1alpha1.ClusterStateReady {
+		return condition
+	}
+
+	condition.Status = corev1.ConditionFalse
+	condition.Message = status.LastError
+	if failed := failedCondition(status); failed != nil {
+		condition.Reason = string(failed.Type)
+	} else {
+		condition.Reason = string(status.RetryReason)
+	}
+	return condition
+}
+
+// degradedCondition reports whether the cluster's PagerDuty service or the
+// delivery of its integration key failed verification.
+func degradedCondition(status *pagerdutyv1alpha1.ClusterStatus) pagerdutyv1alpha1.ClusterCondition {
+	condition := pagerdutyv1alpha1.ClusterCondition{
+		Type:   pagerdutyv1alpha1.ClusterConditionDegraded,
+		Status: corev1.ConditionFalse,
+		Reason: "AsExpected",
+	}
+	for _, verification := range status.Conditions {
+		if verification.Type != pagerdutyv1alpha1.ClusterConditionServiceVerificationFailed &&
+			verification.Type != pagerdutyv1alpha1.ClusterConditionDeliveryVerificationFailed {
+			continue
+		}
+		if verification.Status == corev1.ConditionTrue {
+			condition.Status = corev1.ConditionTrue
+			condition.Reason = string(verification.Type)
+			condition.Message = verification.Message
+			break
+		}
+	}
+	return condition
+}
+
+// deletingClusterStatus returns the last status of a cluster whose
+// ClusterDeployment is being deleted, once handleDelete tore down its
+// PagerDuty integration.
+func deletingClusterStatus(pdi *pagerdutyv1alpha1.PagerDutyIntegration, previous *pagerdutyv1alpha1.ClusterStatus) pagerdutyv1alpha1.ClusterStatus {
+	status := *previous.DeepCopy()
+	status.State = pagerdutyv1alpha1.ClusterStateDeleting
+	status.RetryReason = ""
+	status.LastError = ""
+
+	condition := pagerdutyv1alpha1.ClusterCondition{
+		Type:    pagerdutyv1alpha1.ClusterConditionDeleting,
+		Status:  corev1.ConditionTrue,
+		Reason:  "ServiceDeleted",
+		Message: "The ClusterDeployment is being deleted, its PagerDuty service was deleted",
+	}
+	for _, retained := range pdi.Status.RetainedServices {
+		if retained.ClusterDeploymentNamespace == status.ClusterDeploymentNamespace && retained.ClusterDeploymentName == status.ClusterDeploymentName {
+			condition.Reason = "ServiceRetained"
+			condition.Message = "The ClusterDeployment is being deleted, its PagerDuty service is retained for a reinstall"
+		}
+	}
+	setClusterCondition(&status.Conditions, condition)
+	setClusterCondition(&status.Conditions, pagerdutyv1alpha1.ClusterCondition{
+		Type:   pagerdutyv1alpha1.ClusterConditionReady,
+		Status: corev1.ConditionFalse,
+		Reason: "Deleting",
+	})
+	return status
+}
+
+// countClusterStates returns how many of the clusters are Ready, Pending and
+// Failed.
+func countClusterStates(clusters []pagerdutyv1alpha1.ClusterStatus) (ready, pending, failed int) {
+	for _, cluster := range clusters {
+		switch cluster.State {
+		case pagerdutyv1alpha1.ClusterStateReady:
+			ready++
+		case pagerdutyv1alpha1.ClusterStatePending:
+			pending++
+		case pagerdutyv1alpha1.ClusterStateFailed:
+			failed++
+		}
+	}
+	return ready, pending, failed
+}
+
+// countSecretSyncFailures returns how many of the clusters Hive failed to
+// apply the SyncSet of the integration key to
+func countSecretSyncFailures(clusters []pagerdutyv1alpha1.ClusterStatus) int {
+	failed := 0
+	for _, cluster := range clusters {
+		if syncSetFailed(cluster.Conditions) {
+			failed++
+		}
+	}
+	return failed
+}
+
+// syncSetFailed returns true if the SyncSetFailed condition is True
+func syncSetFailed(conditions []pagerdutyv1alpha1.ClusterCondition) bool {
+	for _, condition := range conditions {
+		if condition.Type == pagerdutyv1alpha1.ClusterConditionSyncSetFailed {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// countServices returns how many of the clusters have a PD service
+func countServices(clusters []pagerdutyv1alpha1.ClusterStatus) int {
+	services := 0
+	for _, cluster := range clusters {
+		if cluster.ServiceID != "" {
+			services++
+		}
+	}
+	return services
+}
+
+// syncSetCondition reports whether Hive managed to apply the SyncSet
+// delivering the integration key, as recorded in the cluster's ClusterSync.
+func (r *ReconcilePagerDutyIntegration) syncSetCondition(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) (pagerdutyv1alpha1.ClusterCondition, error) {
+	condition := pagerdutyv1alpha1.ClusterCondition{
+		Type:    pagerdutyv1alpha1.ClusterConditionSyncSetFailed,
+		Status:  corev1.ConditionUnknown,
+		Reason:  "SyncSetPending",
+		Message: "Hive has not reported applying the SyncSet yet",
+	}
+
+	clusterSync := &hiveintv1alpha1.ClusterSync{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: cd.Name}, clusterSync)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return condition, nil
+		}
+		return condition, err
+	}
+
+	syncSetName := naming.SyncSetName(pdi.Spec.ServicePrefix, cd.Name)
+	for _, syncStatus := range clusterSync.Status.SyncSets {
+		if syncStatus.Name != syncSetName {
+			continue
+		}
+		switch syncStatus.Result {
+		case hiveintv1alpha1.FailureSyncSetResult:
+			condition.Status = corev1.ConditionTrue
+			condition.Reason = "SyncSetApplyFailed"
+			condition.Message = syncStatus.FailureMessage
+		case hiveintv1alpha1.SuccessSyncSetResult:
+			condition.Status = corev1.ConditionFalse
+			condition.Reason = "SyncSetApplied"
+			condition.Message = ""
+		}
+		condition.LastTransitionTime = syncStatus.LastTransitionTime
+	}
+
+	return condition, nil
+}
+
+// findClusterStatus returns the status of the given ClusterDeployment, or nil.
+func findClusterStatus(statuses []pagerdutyv1alpha1.ClusterStatus, namespace, name string) *pagerdutyv1alpha1.ClusterStatus {
+	for i := range statuses {
+		if statuses[i].ClusterDeploymentNamespace == namespace && statuses[i].ClusterDeploymentName == name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+// removeClusterCondition removes the condition of the given type, if any.
+func removeClusterCondition(conditions *[]pagerdutyv1alpha1.ClusterCondition, conditionType pagerdutyv1alpha1.ClusterConditionType) {
+	kept := []pagerdutyv1alpha1.ClusterCondition{}
+	for _, condition := range *conditions {
+		if condition.Type != conditionType {
+			kept = append(kept, condition)
+		}
+	}
+	*conditions = kept
+}
+
+// setClusterCondition adds or replaces the condition of the same type. The
+// previous transition time is kept when the status did not change.
+func setClusterCondition(conditions *[]pagerdutyv1alpha1.ClusterCondition, condition pagerdutyv1alpha1.ClusterCondition) {
+	for i := range *conditions {
+		existing := &(*conditions)[i]
+		if existing.Type != condition.Type {
+			continue
+		}
+		if existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		} else if condition.LastTransitionTime.IsZero() {
+			condition.LastTransitionTime = metav1.Now()
+		}
+		*existing = condition
+		return
+	}
+
+	if condition.LastTransitionTime.IsZero() {
+		condition.LastTransitionTime = metav1.Now()
+	}
+	*conditions = append(*conditions, condition)
+}
@@ -0,0 +1,136 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"sync"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/spf13/pflag"
+)
+
+// maxConcurrentClusterSyncs is set by --max-concurrent-cluster-syncs
+var maxConcurrentClusterSyncs = config.MaxConcurrentClusterSyncsDefault
+
+// FlagSet returns the flags setting how many clusters of a
+// PagerDutyIntegration are set up at once, --max-concurrent-cluster-syncs,
+// how often they are resynced, --resync-period, and how long to wait after a
+// transient PagerDuty error, --pagerduty-error-requeue-delay
+func FlagSet() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("pagerdutyintegration", pflag.ExitOnError)
+	fs.IntVar(&maxConcurrentClusterSyncs, "max-concurrent-cluster-syncs", config.MaxConcurrentClusterSyncsDefault,
+		"How many clusters of a PagerDutyIntegration are set up at once, so a slow PagerDuty call doesn't hold up the fleet")
+	fs.DurationVar(&resyncPeriod, "resync-period", config.ResyncPeriod,
+		"How often the PagerDuty service of each cluster is verified when nothing changed, unless the PagerDutyIntegration sets spec.resyncPeriod")
+	fs.DurationVar(&errorRequeueDelay, "pagerduty-error-requeue-delay", 0,
+		"How long to wait before reconciling a PagerDutyIntegration again after a PagerDuty server error, unless it sets spec.errorRequeueDelay. 0 backs off exponentially")
+	return fs
+}
+
+// lock locks the PagerDutyIntegration and the state of the current reconcile
+// while workers set clusters up, and returns the func unlocking them.
+func (r *ReconcilePagerDutyIntegration) lock() func() {
+	if r.mu == nil {
+		return func() {}
+	}
+	r.mu.Lock()
+	return r.mu.Unlock
+}
+
+// createClustersConcurrently sets up the clusters with up to
+// maxConcurrentClusterSyncs workers, and returns the error of each cluster in
+// the order of clusters.
+//
+// Each cluster is set up by a copy of the reconciler, so the logger scoped
+// to it and the retry reasons and operations it records are its own, until
+// they are merged back. The PagerDutyIntegration is shared: the workers read
+// its spec and metadata, and its AccountQuotaExceeded condition, the only
+// part of its status they read or write, under lock. The integration keys
+// to escrow are collected and written once the workers are done, as the
+// workers would race each other on the escrow secret.
+func (r *ReconcilePagerDutyIntegration) createClustersConcurrently(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, clusters []*hivev1.ClusterDeployment) []error {
+	errs := make([]error, len(clusters))
+	r.mu = &sync.Mutex{}
+	r.escrowKeys = map[string]string{}
+	defer func() {
+		r.mu = nil
+		r.escrowKeys = nil
+	}()
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, r.maxConcurrentClusterSyncs)
+	for i, cd := range clusters {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(i int, cd *hivev1.ClusterDeployment) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			w := r.worker()
+			errs[i] = w.createCluster(pdclient, pdi, cd)
+			r.merge(w)
+		}(i, cd)
+	}
+	wg.Wait()
+
+	// the clusters whose key could not be escrowed are not set up
+	if err := r.escrowIntegrationKeys(pdi, r.escrowKeys); err != nil {
+		for i, cd := range clusters {
+			if _, ok := r.escrowKeys[escrowKey(cd)]; !ok || errs[i] != nil {
+				continue
+			}
+			errs[i] = err
+			// the worker counted the setup as succeeded
+			r.operations.succeeded--
+			r.recordOperation(err)
+			r.setRetryReason(cd, retryReasonFor(err), err)
+		}
+	}
+	return errs
+}
+
+// worker returns a copy of the reconciler setting up one cluster
+func (r *ReconcilePagerDutyIntegration) worker() *ReconcilePagerDutyIntegration {
+	defer r.lock()()
+	w := *r
+	w.retryReasons = map[string]pagerdutyv1alpha1.RetryReason{}
+	w.retryErrors = map[string]string{}
+	w.operations = operationCounts{}
+	return &w
+}
+
+// merge adds the retry reasons and operations recorded by the worker w to
+// those of the reconcile
+func (r *ReconcilePagerDutyIntegration) merge(w *ReconcilePagerDutyIntegration) {
+	defer r.lock()()
+	if r.retryReasons == nil {
+		r.retryReasons = map[string]pagerdutyv1alpha1.RetryReason{}
+	}
+	for key, reason := range w.retryReasons {
+		r.retryReasons[key] = reason
+	}
+	if r.retryErrors == nil {
+		r.retryErrors = map[string]string{}
+	}
+	for key, message := range w.retryErrors {
+		r.retryErrors[key] = message
+	}
+	r.operations.succeeded += w.operations.succeeded
+	r.operations.failed += w.operations.failed
+}
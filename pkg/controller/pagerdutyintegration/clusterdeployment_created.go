@@ -16,9 +16,11 @@ package pagerdutyintegration
 
 import (
 	"context"
+	"fmt"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -27,6 +29,8 @@ import (
 	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
 	"github.com/openshift/pagerduty-operator/pkg/kube"
 	"github.com/openshift/pagerduty-operator/pkg/localmetrics"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
 	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
 	"github.com/openshift/pagerduty-operator/pkg/utils"
 
@@ -36,13 +40,16 @@ import (
 func (r *ReconcilePagerDutyIntegration) handleCreate(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) error {
 	var (
 		// secretName is the name of the Secret deployed to the target
-		// cluster, and also the name of the SyncSet that causes it to
-		// be deployed.
-		secretName string = config.Name(pdi.Spec.ServicePrefix, cd.Name, config.SecretSuffix)
+		// cluster.
+		secretName string = naming.SecretName(pdi.Spec.ServicePrefix, cd.Name)
+
+		// syncSetName is the name of the SyncSet that causes the Secret
+		// to be deployed.
+		syncSetName string = naming.SyncSetName(pdi.Spec.ServicePrefix, cd.Name)
 
 		// configMapName is the name of the ConfigMap containing the
 		// SERVICE_ID and INTEGRATION_ID
-		configMapName string = config.Name(pdi.Spec.ServicePrefix, cd.Name, config.ConfigMapSuffix)
+		configMapName string = naming.ConfigMapName(pdi.Spec.ServicePrefix, cd.Name)
 
 		// There can be more than one PagerDutyIntegration that causes
 		// creation of resources for a ClusterDeployment, and each one
@@ -51,21 +58,47 @@ func (r *ReconcilePagerDutyIntegration) handleCreate(pdclient pd.Client, pdi *pa
 		finalizer string = config.PagerDutyFinalizerPrefix + pdi.Name
 	)
 
+	defer r.scopeLogger(logging.ForCluster(r.reqLogger, cd))()
+
 	if !cd.Spec.Installed {
 		// Cluster isn't installed yet, return
+		r.setRetryReason(cd, pagerdutyv1alpha1.RetryReasonNotInstalled, nil)
 		return nil
 	}
 
 	if !utils.HasFinalizer(cd, finalizer) {
 		baseToPatch := client.MergeFrom(cd.DeepCopy())
 		utils.AddFinalizer(cd, finalizer)
-		return r.client.Patch(context.TODO(), cd, baseToPatch)
+		err := r.client.Patch(context.TODO(), cd, baseToPatch)
+		// the patch requeues the PDI, a dry run carries on to log the
+		// rest of the setup
+		if err != nil || !r.dryRun {
+			return err
+		}
+	}
+
+	// rename anything created under an older naming scheme before looking it up
+	err := naming.Migrate(r.client, r.reqLogger, cd.Namespace, pdi.Spec.ServicePrefix, cd.Name)
+	if err != nil {
+		return err
+	}
+
+	err = r.claimSecondaryResources(pdi, cd)
+	if err != nil {
+		if isManagedByOther(err) {
+			// requeueing will not help until one of the PDIs changes its ServicePrefix
+			r.setRetryReason(cd, pagerdutyv1alpha1.RetryReasonConflict, err)
+			r.recorder.Eventf(cd, corev1.EventTypeWarning, string(pagerdutyv1alpha1.RetryReasonConflict),
+				"PagerDutyIntegration %s/%s not set up: %v", pdi.Namespace, pdi.Name, err)
+			return nil
+		}
+		return err
 	}
 
 	ClusterID := cd.Spec.ClusterName
 
 	pdAPISecret := &corev1.Secret{}
-	err := r.client.Get(
+	err = r.client.Get(
 		context.TODO(),
 		types.NamespacedName{
 			Name:      pdi.Spec.PagerdutyApiKeySecretRef.Name,
@@ -85,34 +118,114 @@ func (r *ReconcilePagerDutyIntegration) handleCreate(pdclient pd.Client, pdi *pa
 	pdData := &pd.Data{
 		ClusterID:          cd.Spec.ClusterName,
 		BaseDomain:         cd.Spec.BaseDomain,
-		EscalationPolicyID: pdi.Spec.EscalationPolicy,
-		AutoResolveTimeout: pdi.Spec.ResolveTimeout,
-		AcknowledgeTimeOut: pdi.Spec.AcknowledgeTimeout,
+		EscalationPolicyID: escalationPolicy(pdi, cd),
+		Team:               pdi.Spec.Team,
 		ServicePrefix:      pdi.Spec.ServicePrefix,
+		NormalizeName:      pdi.Spec.NormalizeServiceNames,
+		IntegrationType:    string(pdi.Spec.IntegrationType),
 		APIKey:             apiKey,
 	}
+	r.setTimeouts(pdi, cd, pdData)
+	setServiceName(pdi, cd, pdData)
+	setClusterMetadata(cd, pdData)
+	setIncidentUrgency(pdi, pdData)
+	setAlertGrouping(pdi, pdData)
+
+	// a PagerDutyClusterConfig overrides the settings of the PDI for this cluster
+	clusterConfig, err := r.clusterConfig(pdi, cd)
+	if err != nil {
+		return err
+	}
+	applyClusterConfig(clusterConfig, pdData)
 
 	// To prevent scoping issues in the err check below.
-	var pdIntegrationKey string
+	var pdIntegrationKey, migrationIntegrationKey string
+
+	// a reinstalled cluster takes over the service of its previous install
+	err = r.restoreRetainedService(pdclient, pdi, cd, configMapName)
+	if err != nil {
+		return err
+	}
+
+	// the account migration was completed, switch to the service of the new account
+	if pdi.Spec.AccountMigration == nil {
+		err = r.promoteMigrationService(pdi, cd, configMapName, secretName)
+		if err != nil {
+			return err
+		}
+	}
 
 	// load configuration
 	err = pdData.ParseClusterConfig(r.client, cd.Namespace, configMapName)
 
 	if err != nil || pdData.ServiceID == "" {
 		// unable to load configuration, therefore create the PD service
+		if pdData.ServiceNameTemplate != "" {
+			if _, err := pd.ExecuteServiceNameTemplate(pdData); err != nil {
+				// the service would get the default name otherwise
+				r.setRetryReason(cd, pagerdutyv1alpha1.RetryReasonServiceNameTemplateFailed, fmt.Errorf("PD service name template failed: %v", err))
+				return nil
+			}
+		}
+		if name := pd.ServiceName(pdData); len(name) > pd.MaxServiceNameLength {
+			// PagerDuty would refuse it, a shorter ServicePrefix is needed
+			r.setRetryReason(cd, pagerdutyv1alpha1.RetryReasonServiceNameTooLong, fmt.Errorf("PD service name %s is %d characters long, PagerDuty accepts at most %d", name, len(name), pd.MaxServiceNameLength))
+			return nil
+		}
+		unlock := r.lock()
+		quotaExceeded := accountQuotaExceeded(pdi)
+		unlock()
+		if quotaExceeded {
+			// retrying would only fail again, wait for the condition to be cleared
+			r.setRetryReason(cd, pagerdutyv1alpha1.RetryReasonPDError, fmt.Errorf("PD account service quota exceeded, not creating PD service"))
+			localmetrics.UpdateMetricPagerDutyCreateFailure(1, ClusterID, pdi.Name)
+			return nil
+		}
+
+		// the service may outlive its ConfigMap, for example when the
+		// operator was reinstalled, adopt it rather than create another
+		existing, findErr := pdclient.FindServiceByName(pdData)
+		if findErr != nil {
+			return findErr
+		}
+
 		var createErr error
-		r.reqLogger.Info("Creating PD service", "ClusterID", pdData.ClusterID, "BaseDomain", pdData.BaseDomain)
-		_, createErr = pdclient.CreateService(pdData)
+		if existing != nil {
+			r.reqLogger.Info("Adopting existing PD service", "ClusterID", pdData.ClusterID, "BaseDomain", pdData.BaseDomain, "ServiceID", existing.ID)
+			createErr = pdclient.AdoptService(pdData, existing)
+			if createErr == nil {
+				r.recorder.Eventf(cd, corev1.EventTypeNormal, "ServiceAdopted",
+					"PagerDutyIntegration %s/%s adopted existing PD service %s", pdi.Namespace, pdi.Name, existing.ID)
+			}
+		} else {
+			r.reqLogger.Info("Creating PD service", "ClusterID", pdData.ClusterID, "BaseDomain", pdData.BaseDomain)
+			_, createErr = pdclient.CreateService(pdData)
+			if createErr == nil {
+				r.recordClusterEvent(pdi, cd, corev1.EventTypeNormal, eventPDServiceCreated,
+					"Created PD service %s", pdData.ServiceID)
+			}
+		}
 		if createErr != nil {
 			localmetrics.UpdateMetricPagerDutyCreateFailure(1, ClusterID, pdi.Name)
+			if pd.IsAccountQuotaExceeded(createErr) {
+				// no more PD services will be created until it is cleared
+				r.setRetryReason(cd, pagerdutyv1alpha1.RetryReasonPDError, createErr)
+				unlock := r.lock()
+				setAccountQuotaExceeded(pdi, true, "ServiceLimitReached", createErr.Error())
+				unlock()
+				return nil
+			}
 			return createErr
 		}
 		localmetrics.UpdateMetricPagerDutyCreateFailure(0, ClusterID, pdi.Name)
+		r.reconcileServiceTags(pdclient, pdi, cd, pdData)
+		r.reconcileServiceDependencies(pdclient, pdi, cd, pdData)
 
 		r.reqLogger.Info("Creating configmap")
 
 		// save config map
 		newCM := kube.GenerateConfigMap(cd.Namespace, configMapName, pdData.ServiceID, pdData.IntegrationID)
+		setOwnerLabel(newCM, pdi)
 		if err = controllerutil.SetControllerReference(cd, newCM, r.scheme); err != nil {
 			r.reqLogger.Error(err, "Error setting controller reference on configmap")
 			return err
@@ -130,16 +243,53 @@ func (r *ReconcilePagerDutyIntegration) handleCreate(pdclient pd.Client, pdi *pa
 		}
 	}
 
+	r.reqLogger = logging.ForService(r.reqLogger, pdData.ServiceID)
+
+	// hibernating clusters don't page
+	err = r.reconcileHibernationWindow(pdclient, cd, configMapName, pdData)
+	if err != nil {
+		return err
+	}
+
+	// neither do silenced ones
+	err = r.reconcileSilencedAnnotation(pdclient, cd, clusterConfig, configMapName, pdData)
+	if err != nil {
+		return err
+	}
+
+	// a leaked integration key is replaced on request
+	rotated, err := r.reconcileKeyRotation(pdclient, pdi, cd, configMapName, pdData)
+	if err != nil {
+		return err
+	}
+
+	err = r.savePagerDutyService(pdi, cd, configMapName, pdData.ServiceID, pdData.IntegrationID)
+	if err != nil {
+		return err
+	}
+
+	localmetrics.UpdateMetricPagerDutyClusterServiceInfo(ClusterID, pdData.ServiceID, pdData.IntegrationID, pdi.Name)
+
 	// try to load integration key (secret)
 	sc := &corev1.Secret{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: cd.Namespace}, sc)
-	if err == nil {
+	if err == nil && !rotated {
 		// successfully loaded secret, snag the integration key
 		r.reqLogger.Info("pdIntegrationKey found, skipping create", "ClusterID", pdData.ClusterID, "BaseDomain", pdData.BaseDomain)
 		pdIntegrationKey = string(sc.Data[config.PagerDutySecretKey])
+		migrationIntegrationKey = string(sc.Data[kube.MigrationSecretKey(pdi)])
 	} else {
+		if err == nil {
+			// the secret holds the rotated key, only its migration key is kept
+			migrationIntegrationKey = string(sc.Data[kube.MigrationSecretKey(pdi)])
+		}
 		// unable to load an integration key, create one.
 		r.reqLogger.Info("pdIntegrationKey not found, creating one", "ClusterID", pdData.ClusterID, "BaseDomain", pdData.BaseDomain)
+		// the integration may have been deleted in PagerDuty
+		_, err = r.recreateMissingIntegration(pdclient, pdi, cd, configMapName, pdData)
+		if err != nil {
+			return err
+		}
 		pdIntegrationKey, err = pdclient.GetIntegrationKey(pdData)
 		if err != nil {
 			// unable to get an integration key
@@ -147,15 +297,38 @@ func (r *ReconcilePagerDutyIntegration) handleCreate(pdclient pd.Client, pdi *pa
 		}
 	}
 
+	if pdi.Spec.AccountMigration != nil && migrationIntegrationKey == "" {
+		migrationIntegrationKey, err = r.migrationIntegrationKey(pdi, cd)
+		if err != nil {
+			return err
+		}
+	}
+
+	heartbeatURL, err := r.reconcileHeartbeat(pdclient, pdi, cd, configMapName, pdData)
+	if err != nil {
+		return err
+	}
+
 	//add secret part
-	secret := kube.GeneratePdSecret(cd.Namespace, secretName, pdIntegrationKey)
+	secret := kube.GeneratePdSecret(cd.Namespace, secretName, pdIntegrationKey, pdi)
+	if pdi.Spec.AccountMigration != nil {
+		// both keys are delivered while the fleet moves to the new account
+		secret.Data[kube.MigrationSecretKey(pdi)] = []byte(migrationIntegrationKey)
+	}
+	if heartbeatURL != "" {
+		secret.Data[kube.HeartbeatSecretKey(pdi)] = []byte(heartbeatURL)
+	}
+	setOwnerLabel(secret, pdi)
 	r.reqLogger.Info("creating pd secret")
 	//add reference
 	if err = controllerutil.SetControllerReference(cd, secret, r.scheme); err != nil {
 		r.reqLogger.Error(err, "Error setting controller reference on secret")
 		return err
 	}
-	if err = r.client.Create(context.TODO(), secret); err != nil {
+	if err = r.client.Create(context.TODO(), secret); err == nil {
+		r.recordClusterEvent(pdi, cd, corev1.EventTypeNormal, eventIntegrationKeySynced,
+			"Created Secret %s holding the integration key of PD service %s", secret.Name, pdData.ServiceID)
+	} else {
 		if !errors.IsAlreadyExists(err) {
 			return err
 		}
@@ -166,8 +339,12 @@ func (r *ReconcilePagerDutyIntegration) handleCreate(pdclient pd.Client, pdi *pa
 		if err != nil {
 			return nil
 		}
-		if string(sc.Data[config.PagerDutySecretKey]) != pdIntegrationKey {
-			r.reqLogger.Info("pdIntegrationKey is changed, delete the secret first")
+		// neither the type nor the data of an immutable secret can be
+		// updated, so any change is done by replacing the secret
+		if !equality.Semantic.DeepEqual(sc.Data, secret.Data) ||
+			sc.Type != secret.Type ||
+			!equality.Semantic.DeepEqual(sc.Immutable, secret.Immutable) {
+			r.reqLogger.Info("pdIntegrationKey, type or immutability is changed, delete the secret first")
 			if err = r.client.Delete(context.TODO(), secret); err != nil {
 				log.Info("failed to delete existing pd secret")
 				return err
@@ -176,19 +353,37 @@ func (r *ReconcilePagerDutyIntegration) handleCreate(pdclient pd.Client, pdi *pa
 			if err = r.client.Create(context.TODO(), secret); err != nil {
 				return err
 			}
+			r.recordClusterEvent(pdi, cd, corev1.EventTypeNormal, eventIntegrationKeySynced,
+				"Replaced Secret %s holding the integration key of PD service %s", secret.Name, pdData.ServiceID)
 		}
 	}
 
+	err = r.escrowIntegrationKey(pdi, cd, pdIntegrationKey)
+	if err != nil {
+		return err
+	}
+
+	err = r.reconcileProbeSyncSet(pdi, cd, kube.TargetSecretName(pdi, kube.IntegrationKeys(pdi, secret)...))
+	if err != nil {
+		return err
+	}
+
+	err = r.reconcileAlertmanagerSyncSet(pdi, cd, pdIntegrationKey, heartbeatURL)
+	if err != nil {
+		return err
+	}
+
 	r.reqLogger.Info("Creating syncset")
 	ss := &hivev1.SyncSet{}
-	err = r.client.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: cd.Namespace}, ss)
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: syncSetName, Namespace: cd.Namespace}, ss)
 	if err != nil {
 		r.reqLogger.Info("error finding the old syncset")
 		if !errors.IsNotFound(err) {
 			return err
 		}
 		r.reqLogger.Info("syncset not found , create a new one on this ")
-		ss = kube.GenerateSyncSet(cd.Namespace, cd.Name, secret, pdi)
+		ss = kube.GenerateSyncSet(cd.Namespace, syncSetName, cd.Name, secret, pdi)
+		setOwnerLabel(ss, pdi)
 		if err = controllerutil.SetControllerReference(cd, ss, r.scheme); err != nil {
 			r.reqLogger.Error(err, "Error setting controller reference on syncset")
 			return err
@@ -196,6 +391,19 @@ func (r *ReconcilePagerDutyIntegration) handleCreate(pdclient pd.Client, pdi *pa
 		if err := r.client.Create(context.TODO(), ss); err != nil {
 			return err
 		}
+		return nil
+	}
+
+	// the delivery mode or the key may have changed since the syncset was created
+	expected := kube.GenerateSyncSet(cd.Namespace, syncSetName, cd.Name, secret, pdi)
+	if !equality.Semantic.DeepEqual(ss.Spec.Secrets, expected.Spec.Secrets) ||
+		!equality.Semantic.DeepEqual(ss.Spec.Patches, expected.Spec.Patches) {
+		r.reqLogger.Info("Updating syncset", "Name", syncSetName)
+		ss.Spec.Secrets = expected.Spec.Secrets
+		ss.Spec.Patches = expected.Spec.Patches
+		if err := r.client.Update(context.TODO(), ss); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -22,6 +22,8 @@ import (
 	"github.com/openshift/pagerduty-operator/config"
 	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
 	metrics "github.com/openshift/pagerduty-operator/pkg/localmetrics"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
 	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
 	"github.com/openshift/pagerduty-operator/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
@@ -37,13 +39,16 @@ func (r *ReconcilePagerDutyIntegration) handleDelete(pdclient pd.Client, pdi *pa
 
 	var (
 		// secretName is the name of the Secret deployed to the target
-		// cluster, and also the name of the SyncSet that causes it to
-		// be deployed.
-		secretName string = config.Name(pdi.Spec.ServicePrefix, cd.Name, config.SecretSuffix)
+		// cluster.
+		secretName string = naming.SecretName(pdi.Spec.ServicePrefix, cd.Name)
+
+		// syncSetName is the name of the SyncSet that causes the Secret
+		// to be deployed.
+		syncSetName string = naming.SyncSetName(pdi.Spec.ServicePrefix, cd.Name)
 
 		// configMapName is the name of the ConfigMap containing the
 		// SERVICE_ID and INTEGRATION_ID
-		configMapName string = config.Name(pdi.Spec.ServicePrefix, cd.Name, config.ConfigMapSuffix)
+		configMapName string = naming.ConfigMapName(pdi.Spec.ServicePrefix, cd.Name)
 
 		// There can be more than one PagerDutyIntegration that causes
 		// creation of resources for a ClusterDeployment, and each one
@@ -52,16 +57,37 @@ func (r *ReconcilePagerDutyIntegration) handleDelete(pdclient pd.Client, pdi *pa
 		finalizer string = config.PagerDutyFinalizerPrefix + pdi.Name
 	)
 
+	defer r.scopeLogger(logging.ForCluster(r.reqLogger, cd))()
+
 	if !utils.HasFinalizer(cd, finalizer) {
 		return nil
 	}
 
+	// rename anything created under an older naming scheme so it can be found and cleaned up
+	err := naming.Migrate(r.client, r.reqLogger, cd.Namespace, pdi.Spec.ServicePrefix, cd.Name)
+	if err != nil {
+		return err
+	}
+
 	ClusterID := cd.Spec.ClusterName
 
 	deletePDService := true
 
+	// only clean up what this PDI manages, another PDI sharing the
+	// ServicePrefix still needs its objects
+	deleteResources := true
+	err = r.claimSecondaryResources(pdi, cd)
+	if err != nil {
+		if !isManagedByOther(err) {
+			return err
+		}
+		r.reqLogger.Info("Leaving resources of another PagerDutyIntegration in place", "Reason", err.Error())
+		deleteResources = false
+		deletePDService = false
+	}
+
 	pdAPISecret := &corev1.Secret{}
-	err := r.client.Get(
+	err = r.client.Get(
 		context.TODO(),
 		types.NamespacedName{
 			Name:      pdi.Spec.PagerdutyApiKeySecretRef.Name,
@@ -93,13 +119,16 @@ func (r *ReconcilePagerDutyIntegration) handleDelete(pdclient pd.Client, pdi *pa
 	pdData := &pd.Data{
 		ClusterID:          cd.Spec.ClusterName,
 		BaseDomain:         cd.Spec.BaseDomain,
-		EscalationPolicyID: pdi.Spec.EscalationPolicy,
+		EscalationPolicyID: escalationPolicy(pdi, cd),
 		AutoResolveTimeout: pdi.Spec.ResolveTimeout,
 		AcknowledgeTimeOut: pdi.Spec.AcknowledgeTimeout,
 		ServicePrefix:      pdi.Spec.ServicePrefix,
 		APIKey:             apiKey,
 	}
 
+	// the cluster keeps alerting while it tears itself down
+	r.suppressDeprovisioningEvents(pdclient, pdi, cd)
+
 	if deletePDService {
 		err = pdData.ParseClusterConfig(r.client, cd.Namespace, configMapName)
 
@@ -112,18 +141,59 @@ func (r *ReconcilePagerDutyIntegration) handleDelete(pdclient pd.Client, pdi *pa
 				Something was not found if we are here.
 
 				The missing object will never be created as we're in the handleDelete function.
-				Skip service deletion in this case and continue with deletion.
+				Look the service up by its name instead, so it isn't left behind in PagerDuty,
+				and skip service deletion if there is none.
 			*/
+			deletePDService = r.findServiceOfLostConfigMap(pdclient, pdi, cd, pdData)
+		}
+		r.reqLogger = logging.ForService(r.reqLogger, pdData.ServiceID)
+	}
+
+	if deletePDService {
+		state, err := r.decommissionState(cd, configMapName)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if state == decommissionDeleted {
+			// deleted along with the account it was in
 			deletePDService = false
 		}
 	}
 
+	if deletePDService && cd.DeletionTimestamp != nil && keepsServices(pdi) {
+		// the fleet keeps the services of its torn down clusters
+		err = r.keepService(pdclient, pdi, cd, pdData, configMapName)
+		if err != nil {
+			return err
+		}
+		deletePDService = false
+	}
+
+	if deletePDService && cd.DeletionTimestamp != nil && retainsServices(pdi) {
+		// keep the service for a reinstall of the cluster or its grace period,
+		// expireRetainedServices deletes it once over
+		err = r.retainService(pdclient, pdi, cd, pdData)
+		if err != nil {
+			return err
+		}
+		deletePDService = false
+	}
+
 	if deletePDService {
 		// we have everything necessary to attempt deletion of the PD service
 		err = pdclient.DeleteService(pdData)
 		if err != nil {
 			r.reqLogger.Error(err, "Failed cleaning up pagerduty.")
+			r.recordClusterEvent(pdi, cd, corev1.EventTypeWarning, eventPDAPIError,
+				"Deleting PD service %s failed: %v", pdData.ServiceID, err)
 		} else {
+			r.recordClusterEvent(pdi, cd, corev1.EventTypeNormal, eventPDServiceDeleted,
+				"Deleted PD service %s", pdData.ServiceID)
+			if accountQuotaExceeded(pdi) {
+				// the deleted service freed room for a new one
+				setAccountQuotaExceeded(pdi, false, "ServiceDeleted", "A PagerDuty service was deleted, creation is retried")
+			}
+
 			// NOTE: not deleting the configmap if we didn't delete
 			// the service with the assumption that the config can
 			// be used later for cleanup find the PD configmap and
@@ -134,21 +204,57 @@ func (r *ReconcilePagerDutyIntegration) handleDelete(pdclient pd.Client, pdi *pa
 			if err != nil {
 				r.reqLogger.Error(err, "Error deleting ConfigMap", "Namespace", cd.Namespace, "Name", configMapName)
 			}
+			err = utils.DeletePagerDutyService(configMapName, cd.Namespace, r.client, r.reqLogger)
+			if err != nil {
+				r.reqLogger.Error(err, "Error deleting PagerDutyService", "Namespace", cd.Namespace, "Name", configMapName)
+			}
 		}
 	}
-	// find the pd secret and delete id
-	r.reqLogger.Info("Deleting PD secret", "Namespace", cd.Namespace, "Name", secretName)
-	err = utils.DeleteSecret(secretName, cd.Namespace, r.client, r.reqLogger)
-	if err != nil {
-		r.reqLogger.Error(err, "Error deleting Secret", "Namespace", cd.Namespace, "Name", secretName)
+	if deleteResources && pdi.Spec.AccountMigration != nil {
+		r.deleteMigrationService(pdi, cd)
 	}
 
-	// find the PD syncset and delete it
-	r.reqLogger.Info("Deleting PD SyncSet", "Namespace", cd.Namespace, "Name", secretName)
-	err = utils.DeleteSyncSet(secretName, cd.Namespace, r.client, r.reqLogger)
+	if deleteResources {
+		// find the pd secret and delete id
+		r.reqLogger.Info("Deleting PD secret", "Namespace", cd.Namespace, "Name", secretName)
+		err = utils.DeleteSecret(secretName, cd.Namespace, r.client, r.reqLogger)
+		if err != nil {
+			r.reqLogger.Error(err, "Error deleting Secret", "Namespace", cd.Namespace, "Name", secretName)
+		}
 
-	if err != nil {
-		r.reqLogger.Error(err, "Error deleting SyncSet", "Namespace", cd.Namespace, "Name", secretName)
+		// find the PD syncset and delete it
+		r.reqLogger.Info("Deleting PD SyncSet", "Namespace", cd.Namespace, "Name", syncSetName)
+		err = utils.DeleteSyncSet(syncSetName, cd.Namespace, r.client, r.reqLogger)
+
+		if err != nil {
+			r.reqLogger.Error(err, "Error deleting SyncSet", "Namespace", cd.Namespace, "Name", syncSetName)
+		}
+
+		if pdi.Spec.EscrowSecretRef != nil {
+			r.reqLogger.Info("Deleting escrowed integration key", "Namespace", cd.Namespace, "Name", cd.Name)
+			err = r.removeEscrowedIntegrationKey(pdi, cd)
+			if err != nil {
+				r.reqLogger.Error(err, "Error deleting escrowed integration key", "Namespace", cd.Namespace, "Name", cd.Name)
+			}
+		}
+
+		probeSyncSetName := naming.ProbeSyncSetName(pdi.Spec.ServicePrefix, cd.Name)
+		err = utils.DeleteSyncSet(probeSyncSetName, cd.Namespace, r.client, r.reqLogger)
+		if err != nil {
+			r.reqLogger.Error(err, "Error deleting SyncSet", "Namespace", cd.Namespace, "Name", probeSyncSetName)
+		}
+
+		alertmanagerSyncSetName := naming.AlertmanagerSyncSetName(pdi.Spec.ServicePrefix, cd.Name)
+		err = utils.DeleteSyncSet(alertmanagerSyncSetName, cd.Namespace, r.client, r.reqLogger)
+		if err != nil {
+			r.reqLogger.Error(err, "Error deleting SyncSet", "Namespace", cd.Namespace, "Name", alertmanagerSyncSetName)
+		}
+
+		heartbeatLeaseName := naming.HeartbeatLeaseName(pdi.Spec.ServicePrefix, cd.Name)
+		err = r.deleteHeartbeatLease(cd.Namespace, heartbeatLeaseName)
+		if err != nil {
+			r.reqLogger.Error(err, "Error deleting Lease", "Namespace", cd.Namespace, "Name", heartbeatLeaseName)
+		}
 	}
 
 	if utils.HasFinalizer(cd, finalizer) {
@@ -173,6 +279,7 @@ func (r *ReconcilePagerDutyIntegration) handleDelete(pdclient pd.Client, pdi *pa
 	}
 
 	metrics.UpdateMetricPagerDutyDeleteFailure(0, ClusterID, pdi.Name)
+	metrics.DeleteMetricPagerDutyClusterServiceInfo(ClusterID, pdi.Name)
 
 	return nil
 }
@@ -0,0 +1,251 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// coverageReportClient posts the coverage reports to their webhook
+var coverageReportClient = &http.Client{Timeout: config.CoverageReportWebhookTimeout}
+
+// coverageReport is the paging coverage of the clusters selected by a
+// PagerDutyIntegration
+type coverageReport struct {
+	PagerDutyIntegration string    `json:"pagerDutyIntegration"`
+	GeneratedTime        time.Time `json:"generatedTime"`
+	// Clusters is the number of selected clusters not being deleted
+	Clusters int `json:"clusters"`
+	// Covered are the clusters with an active PagerDuty service
+	Covered []string `json:"covered"`
+	// Uncovered are the clusters whose service is not set up yet
+	Uncovered []string `json:"uncovered"`
+	// Silenced are the clusters intentionally muted, whether covered or not
+	Silenced []string `json:"silenced"`
+	// Failed are the clusters whose service failed to be set up or verified
+	Failed []coverageFailure `json:"failed"`
+}
+
+// coverageFailure is a cluster of a coverage report that failed
+type coverageFailure struct {
+	Cluster string `json:"cluster"`
+	Error   string `json:"error,omitempty"`
+}
+
+// reportCoverage writes the coverage report of the given clusters to the
+// ConfigMap of the PDI once spec.coverageReport.interval has passed since the
+// last report, then sends it on to the change event service and webhook, if
+// any. Failing to send it on is only logged, the next report is sent on time.
+// The ConfigMap is deleted when the report is disabled. It returns the time
+// of the last report and how long until the next, 0 when the report is
+// disabled.
+func (r *ReconcilePagerDutyIntegration) reportCoverage(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, clusters []pagerdutyv1alpha1.ClusterStatus) (*metav1.Time, time.Duration, error) {
+	spec := pdi.Spec.CoverageReport
+	if spec == nil {
+		if pdi.Status.LastCoverageReportTime != nil {
+			return nil, 0, r.deleteCoverageReport(pdi)
+		}
+		return nil, 0, nil
+	}
+
+	interval := config.CoverageReportDefaultInterval
+	if spec.Interval != nil {
+		interval = spec.Interval.Duration
+	}
+	if interval < config.CoverageReportMinInterval {
+		interval = config.CoverageReportMinInterval
+	}
+
+	now := r.now()
+	if last := pdi.Status.LastCoverageReportTime; last != nil {
+		if wait := last.Add(interval).Sub(now); wait > 0 {
+			return last, wait, nil
+		}
+	}
+
+	report := newCoverageReport(pdi, clusters, now)
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = r.writeCoverageReport(pdi, data)
+	if err != nil {
+		return nil, 0, err
+	}
+	r.reqLogger.Info("Wrote paging coverage report", "Clusters", report.Clusters, "Covered", len(report.Covered), "Silenced", len(report.Silenced), "Failed", len(report.Failed))
+
+	if spec.ChangeEventSecretRef != nil {
+		r.sendCoverageChangeEvent(pdclient, spec.ChangeEventSecretRef, report)
+	}
+	if spec.WebhookURL != "" {
+		r.postCoverageReport(spec.WebhookURL, data)
+	}
+
+	return &metav1.Time{Time: now}, interval, nil
+}
+
+// newCoverageReport sorts the clusters into a coverage report, leaving out
+// those being deleted
+func newCoverageReport(pdi *pagerdutyv1alpha1.PagerDutyIntegration, clusters []pagerdutyv1alpha1.ClusterStatus, now time.Time) *coverageReport {
+	report := &coverageReport{
+		PagerDutyIntegration: pdi.Namespace + "/" + pdi.Name,
+		GeneratedTime:        now.UTC(),
+		Covered:              []string{},
+		Uncovered:            []string{},
+		Silenced:             []string{},
+		Failed:               []coverageFailure{},
+	}
+
+	for _, cluster := range clusters {
+		name := cluster.ClusterDeploymentNamespace + "/" + cluster.ClusterDeploymentName
+		switch {
+		case cluster.State == pagerdutyv1alpha1.ClusterStateDeleting:
+			continue
+		case cluster.State == pagerdutyv1alpha1.ClusterStateFailed:
+			report.Failed = append(report.Failed, coverageFailure{Cluster: name, Error: cluster.LastError})
+		case cluster.State == pagerdutyv1alpha1.ClusterStateReady && cluster.ServiceID != "":
+			report.Covered = append(report.Covered, name)
+		default:
+			report.Uncovered = append(report.Uncovered, name)
+		}
+		report.Clusters++
+	}
+
+	for _, silence := range pdi.Status.ActiveSilences {
+		report.Silenced = append(report.Silenced, silence.ClusterDeploymentNamespace+"/"+silence.ClusterDeploymentName)
+	}
+
+	sort.Strings(report.Covered)
+	sort.Strings(report.Uncovered)
+	sort.Strings(report.Silenced)
+	sort.Slice(report.Failed, func(i, j int) bool { return report.Failed[i].Cluster < report.Failed[j].Cluster })
+	return report
+}
+
+// writeCoverageReport creates or updates the ConfigMap of the PDI holding
+// its coverage report
+func (r *ReconcilePagerDutyIntegration) writeCoverageReport(pdi *pagerdutyv1alpha1.PagerDutyIntegration, data []byte) error {
+	name := naming.CoverageReportConfigMapName(pdi.Name)
+
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: pdi.Namespace}, cm)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: pdi.Namespace,
+			},
+			Data: map[string]string{config.CoverageReportKey: string(data)},
+		}
+		setOwnerLabel(cm, pdi)
+		if err = controllerutil.SetControllerReference(pdi, cm, r.scheme); err != nil {
+			return err
+		}
+		return r.client.Create(context.TODO(), cm)
+	}
+
+	cm.Data = map[string]string{config.CoverageReportKey: string(data)}
+	return r.client.Update(context.TODO(), cm)
+}
+
+// deleteCoverageReport deletes the ConfigMap holding the coverage report of
+// the PDI, if there is one
+func (r *ReconcilePagerDutyIntegration) deleteCoverageReport(pdi *pagerdutyv1alpha1.PagerDutyIntegration) error {
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: naming.CoverageReportConfigMapName(pdi.Name), Namespace: pdi.Namespace}, cm)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	r.reqLogger.Info("Deleting paging coverage report", "Name", cm.Name)
+	err = r.client.Delete(context.TODO(), cm)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// sendCoverageChangeEvent sends a change event summarizing the coverage
+// report to the service whose integration key is in secretRef
+func (r *ReconcilePagerDutyIntegration) sendCoverageChangeEvent(pdclient pd.Client, secretRef *corev1.SecretReference, report *coverageReport) {
+	routingKey, err := utils.LoadSecretData(r.client, secretRef.Name, secretRef.Namespace, config.PagerDutySecretKey)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to load the integration key of the coverage report service")
+		return
+	}
+
+	failed := make([]string, 0, len(report.Failed))
+	for _, failure := range report.Failed {
+		failed = append(failed, failure.Cluster)
+	}
+	summary := fmt.Sprintf("Paging coverage of %s: %d of %d clusters covered, %d silenced, %d failed", report.PagerDutyIntegration, len(report.Covered), report.Clusters, len(report.Silenced), len(report.Failed))
+	details := map[string]string{
+		"pagerdutyintegration": report.PagerDutyIntegration,
+		"clusters":             strconv.Itoa(report.Clusters),
+		"covered":              strconv.Itoa(len(report.Covered)),
+		"uncovered":            strings.Join(report.Uncovered, ", "),
+		"silenced":             strings.Join(report.Silenced, ", "),
+		"failed":               strings.Join(failed, ", "),
+	}
+	err = pdclient.SendChangeEvent(routingKey, summary, details)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to send the coverage report as a change event")
+	}
+}
+
+// postCoverageReport posts the coverage report to its webhook, a dry run
+// only logs it
+func (r *ReconcilePagerDutyIntegration) postCoverageReport(url string, data []byte) {
+	if r.dryRun {
+		r.reqLogger.Info("Dry run, would post coverage report", "URL", url)
+		return
+	}
+
+	resp, err := coverageReportClient.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to post the coverage report to its webhook", "URL", url)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		r.reqLogger.Error(fmt.Errorf("HTTP response code: %d", resp.StatusCode), "Failed to post the coverage report to its webhook", "URL", url)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	mockpd "github.com/openshift/pagerduty-operator/pkg/pagerduty/mock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReportCoverage(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	posted := [][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		posted = append(posted, body)
+	}))
+	defer server.Close()
+
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	pdi := testPagerDutyIntegration()
+	pdi.Spec.CoverageReport = &pagerdutyv1alpha1.CoverageReport{
+		Interval:             &metav1.Duration{Duration: 12 * time.Hour},
+		ChangeEventSecretRef: &corev1.SecretReference{Name: "coverage", Namespace: config.OperatorNamespace},
+		WebhookURL:           server.URL,
+	}
+	pdi.Status.ActiveSilences = []pagerdutyv1alpha1.ActiveSilence{
+		{ClusterDeploymentNamespace: "ns", ClusterDeploymentName: "muted", Source: pagerdutyv1alpha1.SilenceSourceSilencedAnnotation},
+	}
+	clusters := []pagerdutyv1alpha1.ClusterStatus{
+		{ClusterDeploymentNamespace: "ns", ClusterDeploymentName: "ready", ServiceID: "P1", State: pagerdutyv1alpha1.ClusterStateReady},
+		{ClusterDeploymentNamespace: "ns", ClusterDeploymentName: "muted", ServiceID: "P2", State: pagerdutyv1alpha1.ClusterStateReady},
+		{ClusterDeploymentNamespace: "ns", ClusterDeploymentName: "pending", State: pagerdutyv1alpha1.ClusterStatePending},
+		{ClusterDeploymentNamespace: "ns", ClusterDeploymentName: "failed", State: pagerdutyv1alpha1.ClusterStateFailed, LastError: "HTTP response code: 500"},
+		{ClusterDeploymentNamespace: "ns", ClusterDeploymentName: "deleting", ServiceID: "P3", State: pagerdutyv1alpha1.ClusterStateDeleting},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "coverage", Namespace: config.OperatorNamespace},
+		Data:       map[string][]byte{config.PagerDutySecretKey: []byte("routing-key")},
+	}
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPDClient := mockpd.NewMockClient(mockCtrl)
+	mockPDClient.EXPECT().SendChangeEvent("routing-key", "Paging coverage of "+config.OperatorNamespace+"/"+pdi.Name+": 2 of 4 clusters covered, 1 silenced, 1 failed", gomock.Any()).Return(nil).Times(1)
+
+	r := &ReconcilePagerDutyIntegration{
+		client:    fakekubeclient.NewFakeClient(pdi, secret),
+		scheme:    scheme.Scheme,
+		reqLogger: log,
+		clock:     func() time.Time { return now },
+	}
+	key := types.NamespacedName{Name: naming.CoverageReportConfigMapName(pdi.Name), Namespace: pdi.Namespace}
+
+	// Act
+	last, wait, err := r.reportCoverage(mockPDClient, pdi, clusters)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, now, last.Time)
+	assert.Equal(t, 12*time.Hour, wait)
+
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, r.client.Get(context.TODO(), key, cm))
+	assert.Len(t, cm.OwnerReferences, 1)
+	report := coverageReport{}
+	assert.NoError(t, json.Unmarshal([]byte(cm.Data[config.CoverageReportKey]), &report))
+	assert.Equal(t, 4, report.Clusters)
+	assert.Equal(t, []string{"ns/muted", "ns/ready"}, report.Covered)
+	assert.Equal(t, []string{"ns/pending"}, report.Uncovered)
+	assert.Equal(t, []string{"ns/muted"}, report.Silenced)
+	assert.Equal(t, []coverageFailure{{Cluster: "ns/failed", Error: "HTTP response code: 500"}}, report.Failed)
+	assert.Equal(t, [][]byte{[]byte(cm.Data[config.CoverageReportKey])}, posted)
+
+	// nothing is reported again before the interval has passed
+	pdi.Status.LastCoverageReportTime = last
+	now = now.Add(time.Hour)
+	last, wait, err = r.reportCoverage(mockPDClient, pdi, clusters)
+	assert.NoError(t, err)
+	assert.Equal(t, pdi.Status.LastCoverageReportTime, last)
+	assert.Equal(t, 11*time.Hour, wait)
+	assert.Len(t, posted, 1)
+
+	// the ConfigMap is deleted once the report is disabled
+	pdi.Spec.CoverageReport = nil
+	last, wait, err = r.reportCoverage(mockPDClient, pdi, clusters)
+	assert.NoError(t, err)
+	assert.Nil(t, last)
+	assert.Equal(t, time.Duration(0), wait)
+	assert.True(t, kerrors.IsNotFound(r.client.Get(context.TODO(), key, cm)))
+}
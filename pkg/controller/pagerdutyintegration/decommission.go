@@ -0,0 +1,193 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Values of config.DecommissionAnnotation
+const (
+	decommissionDisabled = "disabled"
+	decommissionDeleted  = "deleted"
+)
+
+// decommissionServices reports how far the decommission of the services of
+// the account being migrated from went, and in the Decommission phase
+// disables then deletes the next batch of them. Only clusters that already
+// have a service in the account being migrated to are decommissioned. It
+// also returns how long until the next batch, or 0 when there is none.
+func (r *ReconcilePagerDutyIntegration) decommissionServices(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cds []hivev1.ClusterDeployment) (*pagerdutyv1alpha1.AccountMigrationStatus, time.Duration, error) {
+	migration := pdi.Spec.AccountMigration
+	if migration == nil {
+		return nil, 0, nil
+	}
+
+	budget := migration.DecommissionBatchSize
+	if budget <= 0 {
+		budget = config.DecommissionDefaultBatchSize
+	}
+
+	status := &pagerdutyv1alpha1.AccountMigrationStatus{}
+	for i := range cds {
+		cd := &cds[i]
+		if !cd.Spec.Installed || cd.DeletionTimestamp != nil {
+			continue
+		}
+
+		err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: naming.MigrationConfigMapName(pdi.Spec.ServicePrefix, cd.Name)}, &corev1.ConfigMap{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				// not safe to decommission before alerts can go to the new account
+				continue
+			}
+			return nil, 0, err
+		}
+		status.Clusters++
+
+		cm := &corev1.ConfigMap{}
+		err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: naming.ConfigMapName(pdi.Spec.ServicePrefix, cd.Name)}, cm)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, 0, err
+		}
+		if cm.Labels[config.PagerDutyIntegrationLabel] != pdi.Name {
+			// another PDI manages the service
+			continue
+		}
+
+		state := cm.Annotations[config.DecommissionAnnotation]
+		if migration.Phase == pagerdutyv1alpha1.MigrationPhaseDecommission && state != decommissionDeleted && budget > 0 {
+			budget--
+			state, err = r.decommissionService(pdclient, cd, cm, state)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+
+		switch state {
+		case decommissionDisabled:
+			status.ServicesDisabled++
+		case decommissionDeleted:
+			status.ServicesDeleted++
+		}
+	}
+
+	if migration.Phase != pagerdutyv1alpha1.MigrationPhaseDecommission || status.ServicesDeleted == status.Clusters {
+		return status, 0, nil
+	}
+	return status, config.DecommissionBatchInterval, nil
+}
+
+// decommissionService takes the service recorded in cm one step further, from
+// enabled to disabled or from disabled to deleted, and returns its new state.
+// A PagerDuty failure is logged and retried with the next batch.
+func (r *ReconcilePagerDutyIntegration) decommissionService(pdclient pd.Client, cd *hivev1.ClusterDeployment, cm *corev1.ConfigMap, state string) (string, error) {
+	pdData := &pd.Data{}
+	err := pdData.ParseClusterConfig(r.client, cm.Namespace, cm.Name)
+	if err != nil {
+		return state, err
+	}
+
+	next := decommissionDisabled
+	if state == decommissionDisabled {
+		next = decommissionDeleted
+		r.reqLogger.Info("Deleting PD service of the account being migrated from", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name, "ServiceID", pdData.ServiceID)
+		err = pdclient.DeleteService(pdData)
+	} else {
+		r.reqLogger.Info("Disabling PD service of the account being migrated from", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name, "ServiceID", pdData.ServiceID)
+		err = pdclient.DisableService(pdData)
+	}
+	if err != nil && !pd.IsNotFound(err) {
+		r.reqLogger.Error(err, "Failed decommissioning PD service", "ServiceID", pdData.ServiceID)
+		return state, nil
+	}
+
+	if cm.Annotations == nil {
+		cm.Annotations = map[string]string{}
+	}
+	cm.Annotations[config.DecommissionAnnotation] = next
+	return next, r.client.Update(context.TODO(), cm)
+}
+
+// decommissionState returns the value of config.DecommissionAnnotation on
+// the cluster's ConfigMap, "" if it has none.
+func (r *ReconcilePagerDutyIntegration) decommissionState(cd *hivev1.ClusterDeployment, configMapName string) (string, error) {
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: configMapName}, cm)
+	if err != nil {
+		return "", err
+	}
+	return cm.Annotations[config.DecommissionAnnotation], nil
+}
+
+// promoteMigrationService finishes the migration of a cluster once the PDI
+// no longer sets AccountMigration: the service of the account migrated to
+// replaces the deleted one in the cluster's ConfigMap, and the secret is
+// deleted so handleCreate fetches the integration key of the new service.
+func (r *ReconcilePagerDutyIntegration) promoteMigrationService(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, configMapName, secretName string) error {
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: configMapName}, cm)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if cm.Annotations[config.DecommissionAnnotation] != decommissionDeleted {
+		return nil
+	}
+
+	migrationConfigMapName := naming.MigrationConfigMapName(pdi.Spec.ServicePrefix, cd.Name)
+	migrationCM := &corev1.ConfigMap{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: migrationConfigMapName}, migrationCM)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	r.reqLogger.Info("Promoting PD service of the account migrated to", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name, "ServiceID", migrationCM.Data["SERVICE_ID"])
+	err = r.savePagerDutyService(pdi, cd, configMapName, migrationCM.Data["SERVICE_ID"], migrationCM.Data["INTEGRATION_ID"])
+	if err != nil {
+		return err
+	}
+	cm.Data = migrationCM.Data
+	delete(cm.Annotations, config.DecommissionAnnotation)
+	err = r.client.Update(context.TODO(), cm)
+	if err != nil {
+		return err
+	}
+
+	err = utils.DeleteConfigMap(migrationConfigMapName, cd.Namespace, r.client, r.reqLogger)
+	if err != nil {
+		return err
+	}
+	return utils.DeleteSecret(secretName, cd.Namespace, r.client, r.reqLogger)
+}
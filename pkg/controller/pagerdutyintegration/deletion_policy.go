@@ -0,0 +1,64 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// keepsServices returns true if the PagerDuty service of a deleted cluster
+// is left in PagerDuty rather than deleted, as spec.serviceDeletionPolicy
+// asks. The services are still deleted along with the PDI.
+func keepsServices(pdi *pagerdutyv1alpha1.PagerDutyIntegration) bool {
+	switch pdi.Spec.ServiceDeletionPolicy {
+	case pagerdutyv1alpha1.ServiceDeletionPolicyRetain, pagerdutyv1alpha1.ServiceDeletionPolicyDisable:
+		return pdi.DeletionTimestamp == nil
+	}
+	return false
+}
+
+// keepService leaves the PagerDuty service of a deleted cluster in
+// PagerDuty, disabled first with the Disable policy, and forgets about it:
+// the ConfigMap and PagerDutyService recording it are deleted like those of
+// a deleted service. A service failing to be disabled is retried, so it
+// doesn't keep paging once forgotten.
+func (r *ReconcilePagerDutyIntegration) keepService(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, pdData *pd.Data, configMapName string) error {
+	if pdi.Spec.ServiceDeletionPolicy == pagerdutyv1alpha1.ServiceDeletionPolicyDisable {
+		r.reqLogger.Info("Disabling PD service of the deleted cluster", "ServiceID", pdData.ServiceID)
+		err := pdclient.DisableService(pdData)
+		if err != nil && !pd.IsNotFound(err) {
+			r.recordClusterEvent(pdi, cd, corev1.EventTypeWarning, eventPDAPIError,
+				"Disabling PD service %s failed: %v", pdData.ServiceID, err)
+			return err
+		}
+	}
+	r.recordClusterEvent(pdi, cd, corev1.EventTypeNormal, eventPDServiceRetained,
+		"Kept PD service %s as serviceDeletionPolicy is %s", pdData.ServiceID, pdi.Spec.ServiceDeletionPolicy)
+
+	r.reqLogger.Info("Deleting PD ConfigMap", "Namespace", cd.Namespace, "Name", configMapName)
+	err := utils.DeleteConfigMap(configMapName, cd.Namespace, r.client, r.reqLogger)
+	if err != nil {
+		r.reqLogger.Error(err, "Error deleting ConfigMap", "Namespace", cd.Namespace, "Name", configMapName)
+	}
+	err = utils.DeletePagerDutyService(configMapName, cd.Namespace, r.client, r.reqLogger)
+	if err != nil {
+		r.reqLogger.Error(err, "Error deleting PagerDutyService", "Namespace", cd.Namespace, "Name", configMapName)
+	}
+	return nil
+}
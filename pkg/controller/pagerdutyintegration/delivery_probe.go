@@ -0,0 +1,145 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"fmt"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/kube"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// adminKubeconfigSecretKey is the key of the kubeconfig in the admin
+// kubeconfig Secret Hive creates for each cluster
+const adminKubeconfigSecretKey = "kubeconfig"
+
+// newRemoteClient returns a client for a target cluster from its kubeconfig.
+func newRemoteClient(kubeconfig []byte) (client.Client, error) {
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return client.New(restConfig, client.Options{})
+}
+
+// reconcileProbeSyncSet makes the delivery probe SyncSet of the cluster
+// match spec.deliveryProbe, deleting it when the probe is disabled.
+func (r *ReconcilePagerDutyIntegration) reconcileProbeSyncSet(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, targetSecretName string) error {
+	name := naming.ProbeSyncSetName(pdi.Spec.ServicePrefix, cd.Name)
+
+	if pdi.Spec.DeliveryProbe == nil {
+		return utils.DeleteSyncSet(name, cd.Namespace, r.client, r.reqLogger)
+	}
+
+	expected, err := kube.GenerateProbeSyncSet(cd.Namespace, name, cd.Name, targetSecretName, pdi)
+	if err != nil {
+		return err
+	}
+
+	ss := &hivev1.SyncSet{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cd.Namespace}, ss)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		r.reqLogger.Info("Creating delivery probe syncset", "Name", name)
+		setOwnerLabel(expected, pdi)
+		if err = controllerutil.SetControllerReference(cd, expected, r.scheme); err != nil {
+			r.reqLogger.Error(err, "Error setting controller reference on delivery probe syncset")
+			return err
+		}
+		return r.client.Create(context.TODO(), expected)
+	}
+
+	if !kube.ProbeResourcesEqual(ss.Spec.Resources, expected.Spec.Resources) {
+		r.reqLogger.Info("Updating delivery probe syncset", "Name", name)
+		ss.Spec.Resources = expected.Spec.Resources
+		return r.client.Update(context.TODO(), ss)
+	}
+
+	return nil
+}
+
+// deliveryProbeCondition reads the result the delivery probe left on its
+// CronJob in the target cluster. Failing to reach the cluster is reported
+// as Unknown rather than an error, the probe result is only informative.
+func (r *ReconcilePagerDutyIntegration) deliveryProbeCondition(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) pagerdutyv1alpha1.ClusterCondition {
+	condition := pagerdutyv1alpha1.ClusterCondition{
+		Type:   pagerdutyv1alpha1.ClusterConditionDeliveryVerificationFailed,
+		Status: corev1.ConditionUnknown,
+	}
+
+	if cd.Spec.ClusterMetadata == nil {
+		condition.Reason = "KubeconfigNotFound"
+		condition.Message = "The ClusterDeployment has no admin kubeconfig"
+		return condition
+	}
+
+	kubeconfig := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: cd.Spec.ClusterMetadata.AdminKubeconfigSecretRef.Name, Namespace: cd.Namespace}, kubeconfig)
+	if err != nil {
+		condition.Reason = "KubeconfigNotFound"
+		condition.Message = logging.Redact(err.Error())
+		return condition
+	}
+
+	remoteClient, err := r.remoteClient(kubeconfig.Data[adminKubeconfigSecretKey])
+	if err != nil {
+		condition.Reason = "ClusterUnreachable"
+		condition.Message = logging.Redact(err.Error())
+		return condition
+	}
+
+	cronJob := &batchv1beta1.CronJob{}
+	err = remoteClient.Get(context.TODO(), types.NamespacedName{Name: kube.ProbeName(pdi), Namespace: pdi.Spec.TargetSecretRef.Namespace}, cronJob)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			condition.Reason = "ProbeNotFound"
+			condition.Message = "Hive has not delivered the probe yet"
+			return condition
+		}
+		condition.Reason = "ClusterUnreachable"
+		condition.Message = logging.Redact(err.Error())
+		return condition
+	}
+
+	result, ok := cronJob.Labels[config.DeliveryProbeResultLabel]
+	switch {
+	case !ok:
+		condition.Reason = "ProbeNotReported"
+		condition.Message = "The probe has not run yet"
+	case result == kube.ProbeResultSuccess:
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "ProbeSucceeded"
+	default:
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = result
+		condition.Message = fmt.Sprintf("The probe on the cluster reported %s", result)
+	}
+	return condition
+}
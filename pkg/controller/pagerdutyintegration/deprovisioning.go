@@ -0,0 +1,51 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+)
+
+// suppressDeprovisioningEvents adds the rule suppressing the events of a
+// cluster being deprovisioned, if spec.deprovisioningEventRule is set. The
+// rule only reduces noise, so failing to add it does not hold up the
+// deletion.
+func (r *ReconcilePagerDutyIntegration) suppressDeprovisioningEvents(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) {
+	rule := pdi.Spec.DeprovisioningEventRule
+	if rule == nil {
+		return
+	}
+
+	detail := rule.ClusterIDDetail
+	if detail == "" {
+		detail = config.DeprovisioningEventRuleDefaultDetail
+	}
+
+	duration := config.DeprovisioningEventRuleDefaultDuration
+	if rule.Duration != nil {
+		duration = rule.Duration.Duration
+	}
+
+	start := r.now()
+	ruleID, err := pdclient.CreateSuppressionRule(rule.RulesetID, detail, cd.Spec.ClusterName, start, start.Add(duration))
+	if err != nil {
+		r.reqLogger.Error(err, "Failed adding rule suppressing events of the deprovisioning cluster", "RulesetID", rule.RulesetID, "ClusterID", cd.Spec.ClusterName)
+		return
+	}
+	r.reqLogger.Info("Suppressing events of the deprovisioning cluster", "RulesetID", rule.RulesetID, "RuleID", ruleID, "ClusterID", cd.Spec.ClusterName)
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"fmt"
+	"strings"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+)
+
+// repairServiceDrift sets the settings of the cluster's PagerDuty service
+// that were changed outside of the operator back to those of the
+// PagerDutyIntegration, and reports the repair to spec.fleetHygieneService.
+// Failures are logged and retried on the next resync.
+func (r *ReconcilePagerDutyIntegration) repairServiceDrift(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, pdData *pd.Data, service *pdApi.Service) {
+	serviceDrift := pd.ServiceDrift(pdData, service)
+	// the alert grouping costs a call, only make it for the groupings set
+	groupingDrift := []string{}
+	if pdData.AlertGrouping != nil {
+		var err error
+		groupingDrift, err = pdclient.AlertGroupingDrift(pdData)
+		if err != nil {
+			r.reqLogger.Error(err, "Failed to look up the alert grouping of the PagerDuty service", "ServiceID", pdData.ServiceID)
+		}
+	}
+	drift := append(serviceDrift, groupingDrift...)
+	if len(drift) == 0 {
+		return
+	}
+
+	// look the change up before the repair becomes the most recent one
+	changer := ""
+	if pdi.Spec.FleetHygieneService != nil {
+		var err error
+		changer, err = pdclient.LastServiceChanger(pdData)
+		if err != nil {
+			r.reqLogger.Error(err, "Failed to look up who changed the PagerDuty service", "ServiceID", pdData.ServiceID)
+		}
+	}
+
+	var err error
+	if len(serviceDrift) > 0 {
+		err = pdclient.RepairService(pdData, service)
+	}
+	if err == nil && len(groupingDrift) > 0 {
+		err = pdclient.SetAlertGrouping(pdData)
+	}
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to repair PagerDuty service drift", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name, "ServiceID", pdData.ServiceID, "Drift", drift)
+		return
+	}
+	r.reqLogger.Info("Repaired PagerDuty service drift", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name, "ServiceID", pdData.ServiceID, "Drift", drift)
+
+	if pdi.Spec.FleetHygieneService == nil {
+		return
+	}
+
+	routingKey, err := utils.LoadSecretData(
+		r.client,
+		pdi.Spec.FleetHygieneService.IntegrationKeySecretRef.Name,
+		pdi.Spec.FleetHygieneService.IntegrationKeySecretRef.Namespace,
+		config.PagerDutySecretKey,
+	)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to load the integration key of the fleet hygiene service")
+		return
+	}
+
+	if changer == "" {
+		changer = "unknown"
+	}
+	summary := fmt.Sprintf("Repaired drifted PagerDuty service %s of cluster %s/%s", pdData.ServiceID, cd.Namespace, cd.Name)
+	details := map[string]string{
+		"cluster":              cd.Namespace + "/" + cd.Name,
+		"pagerdutyintegration": pdi.Namespace + "/" + pdi.Name,
+		"service_id":           pdData.ServiceID,
+		"drift":                strings.Join(drift, "; "),
+		"likely_changed_by":    changer,
+	}
+	err = pdclient.SendChangeEvent(routingKey, summary, details)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to report PagerDuty service drift to the fleet hygiene service")
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"github.com/openshift/pagerduty-operator/pkg/dryrun"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+)
+
+// startDryRun makes the current reconcile log the changes it would make to
+// the hub and PagerDuty instead of making them, and returns the func ending
+// the dry run
+func (r *ReconcilePagerDutyIntegration) startDryRun() func() {
+	c, recorder, pdclient := r.client, r.recorder, r.pdclient
+
+	r.reqLogger.Info("Dry run, no change is made")
+	r.dryRun = true
+	r.client = dryrun.NewClient(c, r.scheme, &r.reqLogger)
+	r.recorder = dryrun.NewRecorder(&r.reqLogger)
+	r.pdclient = func(APIKey string, controllerName string, opts ...pd.ClientOption) pd.Client {
+		return dryrun.NewPDClient(pdclient(APIKey, controllerName, opts...), &r.reqLogger)
+	}
+
+	return func() {
+		r.dryRun = false
+		r.client, r.recorder, r.pdclient = c, recorder, pdclient
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	"github.com/openshift/pagerduty-operator/pkg/pagerduty/fake"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// envtest names must be valid for a real API server, unlike those of the
+// unit tests
+const envtestNamespace = "envtest"
+
+// envtestObjects returns the namespaces, the API key secret, a
+// PagerDutyIntegration named name and an installed ClusterDeployment named
+// clusterName it selects, in the order they are created
+func envtestObjects(server *fake.Server, name, clusterName string) (*pagerdutyv1alpha1.PagerDutyIntegration, *hivev1.ClusterDeployment, []runtime.Object) {
+	pdi := testPagerDutyIntegration()
+	pdi.Name = name
+	pdi.Spec.TargetSecretRef = corev1.SecretReference{Name: "pd-secret", Namespace: "openshift-monitoring"}
+	cd := testClusterDeployment(true, true, false, false)
+	cd.Name = clusterName
+	cd.Namespace = envtestNamespace
+	cd.Spec.ClusterName = clusterName
+	return pdi, cd, []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: config.OperatorNamespace}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: envtestNamespace}},
+		testEnvAPIKeySecret(server),
+		pdi,
+		cd,
+	}
+}
+
+// waitForClusterSetUp waits for the cluster cd to be set up by pdi: the
+// finalizer added, the service created in PagerDuty, recorded in the
+// ConfigMap, delivered by a SyncSet and reported in the status
+func waitForClusterSetUp(t *testing.T, c client.Client, server *fake.Server, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) {
+	finalizer := config.PagerDutyFinalizerPrefix + pdi.Name
+	configMapName := naming.ConfigMapName(pdi.Spec.ServicePrefix, cd.Name)
+	syncSetName := naming.SyncSetName(pdi.Spec.ServicePrefix, cd.Name)
+	err := wait.PollImmediate(envtestInterval, envtestTimeout, func() (bool, error) {
+		current := &hivev1.ClusterDeployment{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: cd.Name}, current); err != nil {
+			return false, err
+		}
+		cm := &corev1.ConfigMap{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: configMapName}, cm); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		if _, ok := server.Service(cm.Data["SERVICE_ID"]); !ok {
+			return false, nil
+		}
+		ss := &hivev1.SyncSet{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: syncSetName}, ss); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		currentPDI := &pagerdutyv1alpha1.PagerDutyIntegration{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Namespace: pdi.Namespace, Name: pdi.Name}, currentPDI); err != nil {
+			return false, err
+		}
+		status := findClusterStatus(currentPDI.Status.Clusters, cd.Namespace, cd.Name)
+		return utils.HasFinalizer(current, finalizer) && status != nil, nil
+	})
+	if err != nil {
+		t.Fatalf("cluster not set up: %v", err)
+	}
+}
+
+// TestEnvtestClusterDeploymentLifecycle sets up and tears down a cluster
+// through the watches of a running controller, against a real API server
+func TestEnvtestClusterDeploymentLifecycle(t *testing.T) {
+	c, server := startTestManager(t, nil)
+	pdi, cd, objects := envtestObjects(server, "envtest-lifecycle", "envtest-lifecycle")
+	createTestObjects(t, c, objects...)
+
+	waitForClusterSetUp(t, c, server, pdi, cd)
+	assert.Len(t, server.ServiceIDs(), 1)
+
+	// deleting the ClusterDeployment deletes its service and removes the
+	// finalizer, letting the deletion complete
+	assert.NoError(t, c.Delete(context.TODO(), cd))
+	err := wait.PollImmediate(envtestInterval, envtestTimeout, func() (bool, error) {
+		err := c.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: cd.Name}, &hivev1.ClusterDeployment{})
+		return errors.IsNotFound(err), client.IgnoreNotFound(err)
+	})
+	assert.NoError(t, err, "ClusterDeployment not deleted")
+	assert.Empty(t, server.ServiceIDs())
+}
+
+// TestEnvtestPagerDutyIntegrationDeletion tears down the clusters of a
+// deleted PagerDutyIntegration before its finalizer is removed
+func TestEnvtestPagerDutyIntegrationDeletion(t *testing.T) {
+	c, server := startTestManager(t, nil)
+	pdi, cd, objects := envtestObjects(server, "envtest-deletion", "envtest-deletion")
+	createTestObjects(t, c, objects...)
+	waitForClusterSetUp(t, c, server, pdi, cd)
+
+	// the cluster finalizer and the service are removed, then the
+	// PagerDutyIntegration's own finalizer, letting the deletion complete
+	assert.NoError(t, c.Delete(context.TODO(), pdi))
+	err := wait.PollImmediate(envtestInterval, envtestTimeout, func() (bool, error) {
+		err := c.Get(context.TODO(), types.NamespacedName{Namespace: pdi.Namespace, Name: pdi.Name}, &pagerdutyv1alpha1.PagerDutyIntegration{})
+		return errors.IsNotFound(err), client.IgnoreNotFound(err)
+	})
+	assert.NoError(t, err, "PagerDutyIntegration not deleted")
+
+	current := &hivev1.ClusterDeployment{}
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: cd.Name}, current))
+	assert.False(t, utils.HasFinalizer(current, config.PagerDutyFinalizerPrefix+pdi.Name))
+	err = c.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: naming.ConfigMapName(pdi.Spec.ServicePrefix, cd.Name)}, &corev1.ConfigMap{})
+	assert.True(t, errors.IsNotFound(err), "ConfigMap not deleted")
+	assert.Empty(t, server.ServiceIDs())
+}
+
+// TestEnvtestTemplateWatch sets up the clusters of a PagerDutyIntegration
+// once the template it refers to is created, through the watch of the
+// templates rather than the 10 minutes requeue of a missing template, with
+// the settings inherited from it
+func TestEnvtestTemplateWatch(t *testing.T) {
+	c, server := startTestManager(t, nil)
+	pdi, cd, objects := envtestObjects(server, "envtest-template", "envtest-template")
+	pdi.Spec.AcknowledgeTimeout = 0
+	pdi.Spec.TemplateRef = &corev1.LocalObjectReference{Name: "envtest-template"}
+	createTestObjects(t, c, objects...)
+
+	createTestObjects(t, c, &pagerdutyv1alpha1.PagerDutyIntegrationTemplate{
+		ObjectMeta: metav1.ObjectMeta{Namespace: pdi.Namespace, Name: "envtest-template"},
+		Spec:       pagerdutyv1alpha1.PagerDutyIntegrationTemplateSpec{AcknowledgeTimeout: 900},
+	})
+	waitForClusterSetUp(t, c, server, pdi, cd)
+
+	serviceIDs := server.ServiceIDs()
+	assert.Len(t, serviceIDs, 1)
+	service, _ := server.Service(serviceIDs[0])
+	assert.EqualValues(t, 900, service["acknowledgement_timeout"])
+}
+
+// conflictingClient has the API server refuse the first status update of a
+// PagerDutyIntegration with a conflict, by updating the PagerDutyIntegration
+// through direct right before it
+type conflictingClient struct {
+	client.Client
+	direct client.Client
+	// conflicted is set to 1 once the conflict happened
+	conflicted *int32
+}
+
+func (c conflictingClient) Status() client.StatusWriter {
+	return conflictingStatusWriter{StatusWriter: c.Client.Status(), client: c}
+}
+
+type conflictingStatusWriter struct {
+	client.StatusWriter
+	client conflictingClient
+}
+
+func (w conflictingStatusWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	pdi, ok := obj.(*pagerdutyv1alpha1.PagerDutyIntegration)
+	if !ok || atomic.LoadInt32(w.client.conflicted) == 1 {
+		return w.StatusWriter.Update(ctx, obj, opts...)
+	}
+
+	current := &pagerdutyv1alpha1.PagerDutyIntegration{}
+	if err := w.client.direct.Get(ctx, types.NamespacedName{Namespace: pdi.Namespace, Name: pdi.Name}, current); err != nil {
+		return err
+	}
+	if current.Annotations == nil {
+		current.Annotations = map[string]string{}
+	}
+	current.Annotations["example.com/touched"] = "true"
+	if err := w.client.direct.Update(ctx, current); err != nil {
+		return err
+	}
+
+	err := w.StatusWriter.Update(ctx, obj, opts...)
+	if errors.IsConflict(err) {
+		atomic.StoreInt32(w.client.conflicted, 1)
+	}
+	return err
+}
+
+// TestEnvtestStatusUpdateConflict retries a reconcile whose status update
+// the API server refuses for a stale resourceVersion, without creating the
+// service twice
+func TestEnvtestStatusUpdateConflict(t *testing.T) {
+	conflicted := new(int32)
+	c, server := startTestManager(t, func(r *ReconcilePagerDutyIntegration) {
+		direct, err := client.New(testEnvConfig, client.Options{Scheme: scheme.Scheme})
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.client = conflictingClient{Client: r.client, direct: direct, conflicted: conflicted}
+	})
+	pdi, cd, objects := envtestObjects(server, "envtest-conflict", "envtest-conflict")
+	createTestObjects(t, c, objects...)
+
+	waitForClusterSetUp(t, c, server, pdi, cd)
+	assert.Equal(t, int32(1), atomic.LoadInt32(conflicted), "no status update conflicted")
+	assert.Len(t, server.ServiceIDs(), 1)
+}
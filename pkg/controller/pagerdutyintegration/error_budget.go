@@ -0,0 +1,131 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/localmetrics"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// operationCounts counts the attempts to set up, tear down and verify a
+// cluster made by the current reconcile
+type operationCounts struct {
+	succeeded int
+	failed    int
+}
+
+// recordOperation counts a per-cluster operation that ended with err
+func (r *ReconcilePagerDutyIntegration) recordOperation(err error) {
+	if err != nil {
+		r.operations.failed++
+		return
+	}
+	r.operations.succeeded++
+}
+
+// errorBudgetStatus adds the operations of the current reconcile to the
+// bucket of the current period of the PDI's error budget, drops the buckets
+// that left the window, and reports the result in the metrics. It returns
+// nil when spec.errorBudget is not set.
+func (r *ReconcilePagerDutyIntegration) errorBudgetStatus(pdi *pagerdutyv1alpha1.PagerDutyIntegration) *pagerdutyv1alpha1.ErrorBudgetStatus {
+	budget := pdi.Spec.ErrorBudget
+	if budget == nil {
+		localmetrics.DeleteMetricPagerDutyIntegrationErrorBudget(pdi.Name)
+		return nil
+	}
+
+	window := config.ErrorBudgetDefaultWindow
+	if budget.Window != nil {
+		window = budget.Window.Duration
+	}
+	if window < config.ErrorBudgetMinWindow {
+		window = config.ErrorBudgetMinWindow
+	}
+	period := window / time.Duration(config.ErrorBudgetBuckets)
+
+	now := r.now()
+	start := now.Truncate(period)
+	horizon := now.Add(-window)
+
+	status := &pagerdutyv1alpha1.ErrorBudgetStatus{}
+	if pdi.Status.ErrorBudget != nil {
+		for _, bucket := range pdi.Status.ErrorBudget.Buckets {
+			if bucket.Start.Add(period).After(horizon) {
+				status.Buckets = append(status.Buckets, bucket)
+			}
+		}
+	}
+	if r.operations.succeeded+r.operations.failed > 0 {
+		last := len(status.Buckets) - 1
+		if last < 0 || !status.Buckets[last].Start.Time.Equal(start) {
+			status.Buckets = append(status.Buckets, pagerdutyv1alpha1.OperationBucket{Start: metav1.NewTime(start)})
+			last++
+		}
+		status.Buckets[last].Succeeded += r.operations.succeeded
+		status.Buckets[last].Failed += r.operations.failed
+	}
+
+	for _, bucket := range status.Buckets {
+		status.Succeeded += bucket.Succeeded
+		status.Failed += bucket.Failed
+	}
+	successRatio, remaining := errorBudgetFigures(status.Succeeded, status.Failed, errorBudgetObjective(budget))
+	status.SuccessRatio = strconv.FormatFloat(successRatio, 'f', 4, 64)
+	status.Remaining = strconv.FormatFloat(remaining, 'f', 4, 64)
+	localmetrics.UpdateMetricPagerDutyIntegrationErrorBudget(successRatio, remaining, pdi.Name)
+	return status
+}
+
+// errorBudgetObjective returns the percentage of operations that must
+// succeed, the default one if budget does not set a valid one
+func errorBudgetObjective(budget *pagerdutyv1alpha1.ErrorBudget) float64 {
+	objective, err := strconv.ParseFloat(budget.Objective, 64)
+	if err != nil || objective <= 0 || objective >= 100 {
+		return config.ErrorBudgetDefaultObjective
+	}
+	return objective
+}
+
+// errorBudgetFigures returns the share of the operations that succeeded and
+// the share of the failures allowed by objective that is left
+func errorBudgetFigures(succeeded, failed int, objective float64) (float64, float64) {
+	total := succeeded + failed
+	if total == 0 {
+		return 1, 1
+	}
+	allowed := (1 - objective/100) * float64(total)
+	return float64(succeeded) / float64(total), 1 - float64(failed)/allowed
+}
+
+// requeueOnOperationErr saves the error budget before requeueing on err, so
+// the failed operation behind err is accounted even though the reconcile
+// stops short of its status update
+func (r *ReconcilePagerDutyIntegration) requeueOnOperationErr(pdi *pagerdutyv1alpha1.PagerDutyIntegration, err error) (reconcile.Result, error) {
+	errorBudget := r.errorBudgetStatus(pdi)
+	if !equality.Semantic.DeepEqual(pdi.Status.ErrorBudget, errorBudget) {
+		pdi.Status.ErrorBudget = errorBudget
+		if updateErr := r.updateStatus(pdi); updateErr != nil {
+			r.reqLogger.Error(updateErr, "Failed to save the error budget")
+		}
+	}
+	return r.requeueOnErr(err)
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestErrorBudgetStatus(t *testing.T) {
+	now := time.Date(2020, 6, 2, 12, 30, 0, 0, time.UTC)
+	bucket := func(start time.Time, succeeded, failed int) pagerdutyv1alpha1.OperationBucket {
+		return pagerdutyv1alpha1.OperationBucket{Start: metav1.NewTime(start), Succeeded: succeeded, Failed: failed}
+	}
+
+	tests := []struct {
+		name         string
+		budget       *pagerdutyv1alpha1.ErrorBudget
+		previous     []pagerdutyv1alpha1.OperationBucket
+		operations   []error
+		expectNil    bool
+		expectStatus pagerdutyv1alpha1.ErrorBudgetStatus
+	}{
+		{
+			name:      "Disabled",
+			expectNil: true,
+		},
+		{
+			name:   "No Operations",
+			budget: &pagerdutyv1alpha1.ErrorBudget{},
+			expectStatus: pagerdutyv1alpha1.ErrorBudgetStatus{
+				SuccessRatio: "1.0000",
+				Remaining:    "1.0000",
+			},
+		},
+		{
+			name:       "Operations Added To Current Period",
+			budget:     &pagerdutyv1alpha1.ErrorBudget{Window: &metav1.Duration{Duration: 24 * time.Hour}, Objective: "90"},
+			previous:   []pagerdutyv1alpha1.OperationBucket{bucket(now.Add(-5*time.Hour).Truncate(time.Hour), 10, 0), bucket(now.Truncate(time.Hour), 7, 0)},
+			operations: []error{nil, nil, fmt.Errorf("HTTP response code: 500")},
+			expectStatus: pagerdutyv1alpha1.ErrorBudgetStatus{
+				Succeeded:    19,
+				Failed:       1,
+				SuccessRatio: "0.9500",
+				Remaining:    "0.5000",
+				Buckets:      []pagerdutyv1alpha1.OperationBucket{bucket(now.Add(-5*time.Hour).Truncate(time.Hour), 10, 0), bucket(now.Truncate(time.Hour), 9, 1)},
+			},
+		},
+		{
+			name:       "Buckets Out Of Window Dropped",
+			budget:     &pagerdutyv1alpha1.ErrorBudget{Window: &metav1.Duration{Duration: 24 * time.Hour}, Objective: "99.5"},
+			previous:   []pagerdutyv1alpha1.OperationBucket{bucket(now.Add(-26*time.Hour).Truncate(time.Hour), 0, 50), bucket(now.Add(-2*time.Hour).Truncate(time.Hour), 199, 1)},
+			operations: []error{fmt.Errorf("HTTP response code: 500")},
+			expectStatus: pagerdutyv1alpha1.ErrorBudgetStatus{
+				Succeeded:    199,
+				Failed:       2,
+				SuccessRatio: "0.9900",
+				Remaining:    "-0.9900",
+				Buckets:      []pagerdutyv1alpha1.OperationBucket{bucket(now.Add(-2*time.Hour).Truncate(time.Hour), 199, 1), bucket(now.Truncate(time.Hour), 0, 1)},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.ErrorBudget = test.budget
+			if test.previous != nil {
+				pdi.Status.ErrorBudget = &pagerdutyv1alpha1.ErrorBudgetStatus{Buckets: test.previous}
+			}
+			r := &ReconcilePagerDutyIntegration{reqLogger: log, clock: func() time.Time { return now }}
+			for _, err := range test.operations {
+				r.recordOperation(err)
+			}
+
+			status := r.errorBudgetStatus(pdi)
+
+			if test.expectNil {
+				assert.Nil(t, status)
+				return
+			}
+			if assert.NotNil(t, status) {
+				assert.Equal(t, test.expectStatus, *status)
+			}
+		})
+	}
+}
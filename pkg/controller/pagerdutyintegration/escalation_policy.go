@@ -0,0 +1,58 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"sort"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+)
+
+// escalationPolicy returns the escalation policy the service of cd gets, the
+// one spec.escalationPolicyMapping maps the value of its label to, if any,
+// else spec.escalationPolicy
+func escalationPolicy(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) string {
+	mapping := pdi.Spec.EscalationPolicyMapping
+	if mapping == nil {
+		return pdi.Spec.EscalationPolicy
+	}
+	value, ok := cd.Labels[mapping.LabelKey]
+	if !ok {
+		return pdi.Spec.EscalationPolicy
+	}
+	if policy, ok := mapping.Policies[value]; ok && policy != "" {
+		return policy
+	}
+	return pdi.Spec.EscalationPolicy
+}
+
+// mappedEscalationPolicies returns the escalation policies of
+// spec.escalationPolicyMapping other than spec.escalationPolicy, sorted
+func mappedEscalationPolicies(pdi *pagerdutyv1alpha1.PagerDutyIntegration) []string {
+	if pdi.Spec.EscalationPolicyMapping == nil {
+		return nil
+	}
+	seen := map[string]bool{pdi.Spec.EscalationPolicy: true, "": true}
+	policies := []string{}
+	for _, policy := range pdi.Spec.EscalationPolicyMapping.Policies {
+		if !seen[policy] {
+			seen[policy] = true
+			policies = append(policies, policy)
+		}
+	}
+	sort.Strings(policies)
+	return policies
+}
@@ -0,0 +1,56 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"testing"
+
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMappedEscalationPolicies(t *testing.T) {
+	tests := []struct {
+		name           string
+		mapping        *pagerdutyv1alpha1.EscalationPolicyMapping
+		expectPolicies []string
+	}{
+		{
+			name: "Test No Mapping",
+		},
+		{
+			name: "Test Policies Listed Once",
+			mapping: &pagerdutyv1alpha1.EscalationPolicyMapping{
+				LabelKey: "tier",
+				Policies: map[string]string{
+					"gold":     "gold-escalation-policy",
+					"platinum": "gold-escalation-policy",
+					"silver":   "silver-escalation-policy",
+					"bronze":   testEscalationPolicy,
+				},
+			},
+			expectPolicies: []string{"gold-escalation-policy", "silver-escalation-policy"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.EscalationPolicyMapping = test.mapping
+
+			assert.Equal(t, test.expectPolicies, mappedEscalationPolicies(pdi))
+		})
+	}
+}
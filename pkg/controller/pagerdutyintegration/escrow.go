@@ -0,0 +1,118 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// escrowKey returns the key of the escrow secret holding the integration key
+// of the cluster.
+func escrowKey(cd *hivev1.ClusterDeployment) string {
+	return cd.Namespace + "." + cd.Name
+}
+
+// escrowIntegrationKey copies the cluster's integration key into the escrow
+// secret of the PDI, creating the secret if needed. While workers set
+// clusters up, the key is only collected in r.escrowKeys, the workers would
+// otherwise race each other to create and update the secret.
+func (r *ReconcilePagerDutyIntegration) escrowIntegrationKey(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, pdIntegrationKey string) error {
+	if pdi.Spec.EscrowSecretRef == nil {
+		return nil
+	}
+	if r.escrowKeys != nil {
+		defer r.lock()()
+		r.escrowKeys[escrowKey(cd)] = pdIntegrationKey
+		return nil
+	}
+	return r.escrowIntegrationKeys(pdi, map[string]string{escrowKey(cd): pdIntegrationKey})
+}
+
+// escrowIntegrationKeys copies the integration keys, by escrowKey, into the
+// escrow secret of the PDI with a single write, creating the secret if
+// needed.
+func (r *ReconcilePagerDutyIntegration) escrowIntegrationKeys(pdi *pagerdutyv1alpha1.PagerDutyIntegration, keys map[string]string) error {
+	ref := pdi.Spec.EscrowSecretRef
+	if ref == nil || len(keys) == 0 {
+		return nil
+	}
+
+	escrow := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, escrow)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		escrow = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: ref.Namespace,
+				Name:      ref.Name,
+			},
+			Data: map[string][]byte{},
+		}
+		for key, value := range keys {
+			escrow.Data[key] = []byte(value)
+		}
+		setOwnerLabel(escrow, pdi)
+		r.reqLogger.Info("Creating escrow secret", "Namespace", ref.Namespace, "Name", ref.Name)
+		return r.client.Create(context.TODO(), escrow)
+	}
+
+	if escrow.Data == nil {
+		escrow.Data = map[string][]byte{}
+	}
+	changed := false
+	for key, value := range keys {
+		if string(escrow.Data[key]) != value {
+			escrow.Data[key] = []byte(value)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return r.client.Update(context.TODO(), escrow)
+}
+
+// removeEscrowedIntegrationKey removes the cluster's integration key from the
+// escrow secret of the PDI, if it holds one.
+func (r *ReconcilePagerDutyIntegration) removeEscrowedIntegrationKey(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) error {
+	ref := pdi.Spec.EscrowSecretRef
+	if ref == nil {
+		return nil
+	}
+
+	escrow := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, escrow)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if _, ok := escrow.Data[escrowKey(cd)]; !ok {
+		return nil
+	}
+	delete(escrow.Data, escrowKey(cd))
+	return r.client.Update(context.TODO(), escrow)
+}
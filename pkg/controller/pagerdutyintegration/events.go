@@ -0,0 +1,49 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"fmt"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+)
+
+// Reasons of the events recorded at the milestones of the setup and
+// teardown of a cluster
+const (
+	eventPDServiceCreated      = "PDServiceCreated"
+	eventPDServiceDeleted      = "PDServiceDeleted"
+	eventPDServiceRetained     = "PDServiceRetained"
+	eventPDServiceLeaked       = "PDServiceLeaked"
+	eventIntegrationKeySynced  = "IntegrationKeySynced"
+	eventIntegrationKeyRotated = "IntegrationKeyRotated"
+	eventIntegrationRecreated  = "IntegrationRecreated"
+	eventPDAPIError            = "PDAPIError"
+	eventSecretSyncFailed      = "SecretSyncFailed"
+)
+
+// recordClusterEvent records an event on both the ClusterDeployment and the
+// PagerDutyIntegration, so describing either tells how the setup of the
+// cluster went. The event of the PDI names the cluster. Nothing is recorded
+// without a recorder, as in the tests not looking at events.
+func (r *ReconcilePagerDutyIntegration) recordClusterEvent(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, eventtype, reason, messageFmt string, args ...interface{}) {
+	if r.recorder == nil {
+		return
+	}
+	message := fmt.Sprintf(messageFmt, args...)
+	r.recorder.Event(cd, eventtype, reason, message)
+	r.recorder.Eventf(pdi, eventtype, reason, "ClusterDeployment %s/%s: %s", cd.Namespace, cd.Name, message)
+}
@@ -0,0 +1,206 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/heartbeat"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// reconcileHeartbeat makes sure the service of the cluster has a heartbeat
+// integration, recorded in the cluster's ConfigMap, and the cluster a Lease
+// recording its check-ins, and returns the URL the cluster checks in at. The
+// Lease is deleted, and "" returned, when spec.heartbeat is unset.
+func (r *ReconcilePagerDutyIntegration) reconcileHeartbeat(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, configMapName string, pdData *pd.Data) (string, error) {
+	leaseName := naming.HeartbeatLeaseName(pdi.Spec.ServicePrefix, cd.Name)
+	if pdi.Spec.Heartbeat == nil {
+		return "", r.deleteHeartbeatLease(cd.Namespace, leaseName)
+	}
+
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: configMapName, Namespace: cd.Namespace}, cm)
+	if err != nil {
+		return "", err
+	}
+	if cm.Data[config.HeartbeatIntegrationIDKey] == "" {
+		r.reqLogger.Info("Creating PD heartbeat integration")
+		id, err := pdclient.CreateHeartbeatIntegration(pdData)
+		if err != nil {
+			return "", err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[config.HeartbeatIntegrationIDKey] = id
+		err = r.client.Update(context.TODO(), cm)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	lease := &coordinationv1.Lease{}
+	err = r.uncachedReader().Get(context.TODO(), types.NamespacedName{Name: leaseName, Namespace: cd.Namespace}, lease)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return "", err
+		}
+		r.reqLogger.Info("Creating heartbeat lease", "Name", leaseName)
+		lease, err = heartbeat.NewLease(cd.Namespace, leaseName, r.now())
+		if err != nil {
+			return "", err
+		}
+		setOwnerLabel(lease, pdi)
+		if err = controllerutil.SetControllerReference(cd, lease, r.scheme); err != nil {
+			r.reqLogger.Error(err, "Error setting controller reference on heartbeat lease")
+			return "", err
+		}
+		err = r.client.Create(context.TODO(), lease)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return heartbeat.URL(pdi.Spec.Heartbeat, lease), nil
+}
+
+// deleteHeartbeatLease deletes the Lease recording the check-ins of a
+// cluster, if any
+func (r *ReconcilePagerDutyIntegration) deleteHeartbeatLease(namespace, name string) error {
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	err := r.client.Delete(context.TODO(), lease)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// heartbeatCondition reports whether the cluster missed its heartbeat, and
+// pages on it through the heartbeat integration of its service when it
+// starts to, resolving the incident once the cluster checks in again. It
+// returns false when there is nothing to report, as before handleCreate
+// created the Lease, along with how long until the heartbeat is to be
+// checked again. The Lease of a hibernating cluster, whose Alertmanager is
+// stopped, is renewed instead, so the cluster has two intervals to check in
+// once it resumes.
+func (r *ReconcilePagerDutyIntegration) heartbeatCondition(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, previous []pagerdutyv1alpha1.ClusterCondition, now time.Time) (pagerdutyv1alpha1.ClusterCondition, bool, time.Duration, error) {
+	condition := pagerdutyv1alpha1.ClusterCondition{
+		Type:   pagerdutyv1alpha1.ClusterConditionHeartbeatMissed,
+		Status: corev1.ConditionFalse,
+		Reason: "CheckedIn",
+	}
+	hb := pdi.Spec.Heartbeat
+	interval := heartbeat.Interval(hb)
+
+	lease := &coordinationv1.Lease{}
+	err := r.uncachedReader().Get(context.TODO(), types.NamespacedName{Name: naming.HeartbeatLeaseName(pdi.Spec.ServicePrefix, cd.Name), Namespace: cd.Namespace}, lease)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// handleCreate will create it
+			return condition, false, 0, nil
+		}
+		return condition, false, 0, err
+	}
+
+	hibernating := cd.Spec.PowerState == hivev1.HibernatingClusterPowerState
+	missed := !hibernating && heartbeat.Missed(hb, lease, now)
+	lastCheckIn := heartbeat.LastCheckIn(lease).UTC().Format(time.RFC3339)
+	wasMissed := false
+	for _, c := range previous {
+		if c.Type == pagerdutyv1alpha1.ClusterConditionHeartbeatMissed {
+			wasMissed = c.Status == corev1.ConditionTrue
+		}
+	}
+	if missed {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "CheckInMissed"
+		condition.Message = "The cluster did not check in since " + lastCheckIn
+	} else if hibernating {
+		condition.Reason = "Hibernating"
+	}
+
+	if missed != wasMissed {
+		err = r.sendHeartbeatEvent(pdclient, pdi, cd, missed)
+		r.recordOperation(err)
+		if err != nil {
+			// keep the previous status, so the event is sent again
+			r.reqLogger.Error(err, "Failed to send heartbeat event", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name, "Missed", missed)
+			return condition, false, config.HeartbeatMinInterval, nil
+		}
+		if missed {
+			r.recorder.Eventf(cd, corev1.EventTypeWarning, "HeartbeatMissed",
+				"Cluster did not check in to the heartbeat of PagerDutyIntegration %s/%s since %s", pdi.Namespace, pdi.Name, lastCheckIn)
+		} else {
+			r.recorder.Eventf(cd, corev1.EventTypeNormal, "HeartbeatResumed",
+				"Cluster checks in to the heartbeat of PagerDutyIntegration %s/%s again", pdi.Namespace, pdi.Name)
+		}
+	}
+
+	if hibernating {
+		if heartbeat.Deadline(hb, lease).Sub(now) < interval {
+			renewTime := metav1.NewMicroTime(now)
+			lease.Spec.RenewTime = &renewTime
+			if err := r.client.Update(context.TODO(), lease); err != nil {
+				return condition, false, 0, err
+			}
+		}
+		return condition, true, interval, nil
+	}
+	if missed {
+		// check-ins don't trigger reconciles, look for them once per interval
+		return condition, true, interval, nil
+	}
+	return condition, true, heartbeat.Deadline(hb, lease).Sub(now), nil
+}
+
+// sendHeartbeatEvent triggers, or resolves when missed is false, the alert
+// of the missed heartbeat of the cluster through its heartbeat integration
+func (r *ReconcilePagerDutyIntegration) sendHeartbeatEvent(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, missed bool) error {
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: naming.ConfigMapName(pdi.Spec.ServicePrefix, cd.Name), Namespace: cd.Namespace}, cm)
+	if err != nil {
+		return err
+	}
+
+	integrationKey, err := pdclient.GetIntegrationKey(&pd.Data{
+		ServiceID:     cm.Data["SERVICE_ID"],
+		IntegrationID: cm.Data[config.HeartbeatIntegrationIDKey],
+	})
+	if err != nil {
+		return err
+	}
+	return pdclient.SendHeartbeatEvent(integrationKey, cd.Spec.ClusterName, missed)
+}
+
+// uncachedReader returns the reader of the heartbeat Leases and Namespaces
+func (r *ReconcilePagerDutyIntegration) uncachedReader() client.Reader {
+	if r.reader != nil {
+		return r.reader
+	}
+	return r.client
+}
@@ -0,0 +1,238 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/heartbeat"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	mockpd "github.com/openshift/pagerduty-operator/pkg/pagerduty/mock"
+	"github.com/stretchr/testify/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testHeartbeatIntegrationID = "HB123"
+
+func testHeartbeat() *pagerdutyv1alpha1.Heartbeat {
+	return &pagerdutyv1alpha1.Heartbeat{
+		URL:      "https://heartbeat.example.com",
+		Interval: metav1.Duration{Duration: time.Hour},
+	}
+}
+
+func TestReconcileHeartbeat(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	pdi := testPagerDutyIntegration()
+	pdi.Spec.Heartbeat = testHeartbeat()
+	cd := testClusterDeployment(true, true, true, false)
+	cm := testCDConfigMap()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPDClient := mockpd.NewMockClient(mockCtrl)
+	// created once, then read from the ConfigMap
+	mockPDClient.EXPECT().CreateHeartbeatIntegration(gomock.Any()).Return(testHeartbeatIntegrationID, nil).Times(1)
+
+	r := &ReconcilePagerDutyIntegration{
+		client:    fakekubeclient.NewFakeClient(cd, cm),
+		scheme:    scheme.Scheme,
+		reqLogger: log,
+		clock:     func() time.Time { return now },
+	}
+	leaseKey := types.NamespacedName{Name: naming.HeartbeatLeaseName(testServicePrefix, testClusterName), Namespace: testNamespace}
+
+	// Act, twice to confirm the second run keeps the URL
+	url1, err1 := r.reconcileHeartbeat(mockPDClient, pdi, cd, cm.Name, &pd.Data{ServiceID: testServiceID})
+	url2, err2 := r.reconcileHeartbeat(mockPDClient, pdi, cd, cm.Name, &pd.Data{ServiceID: testServiceID})
+
+	// Assert
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, url1, url2)
+
+	updated := &corev1.ConfigMap{}
+	assert.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, updated))
+	assert.Equal(t, testHeartbeatIntegrationID, updated.Data[config.HeartbeatIntegrationIDKey])
+
+	lease := &coordinationv1.Lease{}
+	assert.NoError(t, r.client.Get(context.TODO(), leaseKey, lease))
+	assert.Equal(t, heartbeat.URL(pdi.Spec.Heartbeat, lease), url1)
+	assert.Equal(t, now, lease.Spec.RenewTime.Time.UTC())
+	assert.Len(t, lease.OwnerReferences, 1)
+
+	// the Lease is deleted once heartbeats are disabled
+	pdi.Spec.Heartbeat = nil
+	url, err := r.reconcileHeartbeat(mockPDClient, pdi, cd, cm.Name, &pd.Data{ServiceID: testServiceID})
+	assert.NoError(t, err)
+	assert.Equal(t, "", url)
+	assert.True(t, kerrors.IsNotFound(r.client.Get(context.TODO(), leaseKey, lease)))
+}
+
+func TestHeartbeatCondition(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	missedCondition := []pagerdutyv1alpha1.ClusterCondition{
+		{Type: pagerdutyv1alpha1.ClusterConditionHeartbeatMissed, Status: corev1.ConditionTrue},
+	}
+
+	tests := []struct {
+		name          string
+		lastCheckIn   time.Time
+		powerState    hivev1.ClusterPowerState
+		previous      []pagerdutyv1alpha1.ClusterCondition
+		sendErr       error
+		expectPage    bool
+		expectResolve bool
+		expectOK      bool
+		expectStatus  corev1.ConditionStatus
+		expectReason  string
+		expectWait    time.Duration
+		expectRenewed bool
+	}{
+		{
+			name:         "Test Checked In",
+			lastCheckIn:  now.Add(-30 * time.Minute),
+			expectOK:     true,
+			expectStatus: corev1.ConditionFalse,
+			expectReason: "CheckedIn",
+			expectWait:   90 * time.Minute,
+		},
+		{
+			name:         "Test Missed Pages",
+			lastCheckIn:  now.Add(-3 * time.Hour),
+			expectPage:   true,
+			expectOK:     true,
+			expectStatus: corev1.ConditionTrue,
+			expectReason: "CheckInMissed",
+			expectWait:   time.Hour,
+		},
+		{
+			name:         "Test Still Missed Pages Once",
+			lastCheckIn:  now.Add(-3 * time.Hour),
+			previous:     missedCondition,
+			expectOK:     true,
+			expectStatus: corev1.ConditionTrue,
+			expectReason: "CheckInMissed",
+			expectWait:   time.Hour,
+		},
+		{
+			name:          "Test Resumed Resolves",
+			lastCheckIn:   now.Add(-time.Minute),
+			previous:      missedCondition,
+			expectResolve: true,
+			expectOK:      true,
+			expectStatus:  corev1.ConditionFalse,
+			expectReason:  "CheckedIn",
+			expectWait:    119 * time.Minute,
+		},
+		{
+			name:          "Test Hibernating Not Paged",
+			lastCheckIn:   now.Add(-3 * time.Hour),
+			powerState:    hivev1.HibernatingClusterPowerState,
+			expectOK:      true,
+			expectStatus:  corev1.ConditionFalse,
+			expectReason:  "Hibernating",
+			expectWait:    time.Hour,
+			expectRenewed: true,
+		},
+		{
+			name:          "Test Hibernating Resolves Missed",
+			lastCheckIn:   now.Add(-3 * time.Hour),
+			powerState:    hivev1.HibernatingClusterPowerState,
+			previous:      missedCondition,
+			expectResolve: true,
+			expectOK:      true,
+			expectStatus:  corev1.ConditionFalse,
+			expectReason:  "Hibernating",
+			expectWait:    time.Hour,
+			expectRenewed: true,
+		},
+		{
+			name:         "Test Failed Page Retried",
+			lastCheckIn:  now.Add(-3 * time.Hour),
+			sendErr:      errors.New("HTTP response code: 500"),
+			expectPage:   true,
+			expectOK:     false,
+			expectStatus: corev1.ConditionTrue,
+			expectReason: "CheckInMissed",
+			expectWait:   config.HeartbeatMinInterval,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.Heartbeat = testHeartbeat()
+			cd := testClusterDeployment(true, true, true, false)
+			cd.Spec.PowerState = test.powerState
+			cm := testCDConfigMap()
+			cm.Data[config.HeartbeatIntegrationIDKey] = testHeartbeatIntegrationID
+			lease, err := heartbeat.NewLease(testNamespace, naming.HeartbeatLeaseName(testServicePrefix, testClusterName), test.lastCheckIn)
+			assert.NoError(t, err)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockPDClient := mockpd.NewMockClient(mockCtrl)
+			if test.expectPage || test.expectResolve {
+				mockPDClient.EXPECT().GetIntegrationKey(&pd.Data{ServiceID: testServiceID, IntegrationID: testHeartbeatIntegrationID}).Return("HBKEY", nil).Times(1)
+				mockPDClient.EXPECT().SendHeartbeatEvent("HBKEY", testClusterName, test.expectPage).Return(test.sendErr).Times(1)
+			}
+
+			r := &ReconcilePagerDutyIntegration{
+				client:    fakekubeclient.NewFakeClient(cd, cm, lease),
+				reqLogger: log,
+				recorder:  record.NewFakeRecorder(10),
+			}
+
+			// Act
+			condition, ok, wait, err := r.heartbeatCondition(mockPDClient, pdi, cd, test.previous, now)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectOK, ok)
+			assert.Equal(t, test.expectStatus, condition.Status)
+			assert.Equal(t, test.expectReason, condition.Reason)
+			assert.Equal(t, test.expectWait, wait)
+
+			renewed := &coordinationv1.Lease{}
+			assert.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Name: lease.Name, Namespace: lease.Namespace}, renewed))
+			assert.Equal(t, test.expectRenewed, renewed.Spec.RenewTime.Time.Equal(now))
+		})
+	}
+}
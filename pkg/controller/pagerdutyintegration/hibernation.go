@@ -0,0 +1,85 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reconcileHibernationWindow keeps the service of a hibernating cluster in
+// a PagerDuty maintenance window, so it doesn't page while its machines are
+// stopped, and ends the window once the cluster resumes. The window is
+// recorded in the cluster's ConfigMap, and replaced before it ends when the
+// cluster hibernates for longer than config.HibernationWindowDuration.
+func (r *ReconcilePagerDutyIntegration) reconcileHibernationWindow(pdclient pd.Client, cd *hivev1.ClusterDeployment, configMapName string, pdData *pd.Data) error {
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: configMapName, Namespace: cd.Namespace}, cm)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// the service isn't created yet
+			return nil
+		}
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+
+	now := r.now()
+	windowID := cm.Data[config.HibernationWindowIDKey]
+	// an unreadable end time is handled as a window already over
+	windowEnd, _ := time.Parse(time.RFC3339, cm.Data[config.HibernationWindowEndKey])
+
+	if cd.Spec.PowerState == hivev1.HibernatingClusterPowerState {
+		if windowID != "" && windowEnd.Sub(now) > config.HibernationWindowRenewBefore {
+			return nil
+		}
+
+		end := now.Add(config.HibernationWindowDuration)
+		r.reqLogger.Info("Putting PD service of hibernating cluster in maintenance", "End", end)
+		id, err := pdclient.CreateMaintenanceWindow(pdData, fmt.Sprintf("Cluster %s is hibernating", cd.Spec.ClusterName), now, end)
+		if err != nil {
+			return err
+		}
+		cm.Data[config.HibernationWindowIDKey] = id
+		cm.Data[config.HibernationWindowEndKey] = end.UTC().Format(time.RFC3339)
+		return r.client.Update(context.TODO(), cm)
+	}
+
+	if windowID == "" {
+		return nil
+	}
+
+	// PagerDuty refuses to delete a window that is over
+	if windowEnd.After(now) {
+		r.reqLogger.Info("Ending PD maintenance window of resumed cluster", "MaintenanceWindowID", windowID)
+		err = pdclient.DeleteMaintenanceWindow(windowID)
+		if err != nil && !pd.IsNotFound(err) {
+			return err
+		}
+	}
+	delete(cm.Data, config.HibernationWindowIDKey)
+	delete(cm.Data, config.HibernationWindowEndKey)
+	return r.client.Update(context.TODO(), cm)
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	mockpd "github.com/openshift/pagerduty-operator/pkg/pagerduty/mock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileHibernationWindow(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	window := func(id string, end time.Time) map[string]string {
+		return map[string]string{
+			config.HibernationWindowIDKey:  id,
+			config.HibernationWindowEndKey: end.Format(time.RFC3339),
+		}
+	}
+
+	tests := []struct {
+		name        string
+		powerState  hivev1.ClusterPowerState
+		window      map[string]string
+		setupPDMock func(*mockpd.MockClientMockRecorder)
+		expectID    string
+	}{
+		{
+			name:       "Test Hibernating Cluster Put In Maintenance",
+			powerState: hivev1.HibernatingClusterPowerState,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateMaintenanceWindow(gomock.Any(), gomock.Any(), now, now.Add(config.HibernationWindowDuration)).Return("MW1", nil).Times(1)
+			},
+			expectID: "MW1",
+		},
+		{
+			name:       "Test Window Kept While Hibernating",
+			powerState: hivev1.HibernatingClusterPowerState,
+			window:     window("MW1", now.Add(3*24*time.Hour)),
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateMaintenanceWindow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectID: "MW1",
+		},
+		{
+			name:       "Test Window Renewed Before It Ends",
+			powerState: hivev1.HibernatingClusterPowerState,
+			window:     window("MW1", now.Add(time.Hour)),
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateMaintenanceWindow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return("MW2", nil).Times(1)
+			},
+			expectID: "MW2",
+		},
+		{
+			name:       "Test Window Ended On Resume",
+			powerState: hivev1.RunningClusterPowerState,
+			window:     window("MW1", now.Add(3*24*time.Hour)),
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DeleteMaintenanceWindow("MW1").Return(nil).Times(1)
+			},
+		},
+		{
+			name:   "Test Window Already Over On Resume",
+			window: window("MW1", now.Add(-time.Hour)),
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DeleteMaintenanceWindow(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Test Running Cluster Left Alone",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateMaintenanceWindow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			cd := testClusterDeployment(true, true, true, false)
+			cd.Spec.PowerState = test.powerState
+			cm := testCDConfigMap()
+			for key, value := range test.window {
+				cm.Data[key] = value
+			}
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockPDClient := mockpd.NewMockClient(mockCtrl)
+			test.setupPDMock(mockPDClient.EXPECT())
+
+			r := &ReconcilePagerDutyIntegration{
+				client:    fakekubeclient.NewFakeClient(cd, cm),
+				reqLogger: log,
+				clock:     func() time.Time { return now },
+			}
+
+			// Act
+			err := r.reconcileHibernationWindow(mockPDClient, cd, cm.Name, &pd.Data{ServiceID: testServiceID})
+
+			// Assert
+			assert.NoError(t, err)
+			updated := &corev1.ConfigMap{}
+			err = r.client.Get(context.TODO(), types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, updated)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectID, updated.Data[config.HibernationWindowIDKey])
+			assert.Equal(t, testServiceID, updated.Data["SERVICE_ID"])
+		})
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+)
+
+// setIncidentUrgency passes spec.incidentUrgency on to the PagerDuty
+// services created for pdData. Without it the services keep the default
+// severity based urgency.
+func setIncidentUrgency(pdi *pagerdutyv1alpha1.PagerDutyIntegration, pdData *pd.Data) {
+	if pdi.Spec.IncidentUrgency == nil {
+		return
+	}
+	setUrgency(pdi.Spec.IncidentUrgency, pdData)
+}
+
+// setUrgency sets the urgency of the services created for pdData to urgency
+func setUrgency(urgency *pagerdutyv1alpha1.IncidentUrgency, pdData *pd.Data) {
+	pdData.Urgency = string(urgency.Urgency)
+	pdData.OutsideSupportHoursUrgency = string(urgency.OutsideSupportHoursUrgency)
+	if urgency.SupportHours != nil {
+		pdData.SupportHours = &pdApi.SupportHours{
+			Type:       "fixed_time_per_day",
+			Timezone:   urgency.SupportHours.TimeZone,
+			StartTime:  urgency.SupportHours.StartTime,
+			EndTime:    urgency.SupportHours.EndTime,
+			DaysOfWeek: urgency.SupportHours.DaysOfWeek,
+		}
+	} else {
+		pdData.SupportHours = nil
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reconcileKeyRotation replaces the integration of the cluster's service,
+// and with it the integration key, when the RotateIntegrationKeyAnnotation of
+// the clusterdeployment or of the pagerdutyintegration asks for the keys
+// issued before a time the current key predates. A key is issued when the
+// cluster's ConfigMap is created, or when it is rotated, as recorded in the
+// ConfigMap. Clusters set up after that time keep their key, and a time in
+// the future rotates the keys once it is reached. It returns true if the key
+// was rotated, so the Secret is synced anew.
+func (r *ReconcilePagerDutyIntegration) reconcileKeyRotation(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, configMapName string, pdData *pd.Data) (bool, error) {
+	requested, ok := r.keyRotationRequest(pdi, cd)
+	if !ok {
+		return false, nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: configMapName, Namespace: cd.Namespace}, cm)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// the service isn't created yet
+			return false, nil
+		}
+		return false, err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+
+	now := r.now()
+	issued := cm.CreationTimestamp.Time
+	if rotated, ok := keyRotationTime(cm); ok {
+		issued = rotated.Time
+	}
+	if !issued.Before(requested) || requested.After(now) {
+		return false, nil
+	}
+
+	r.reqLogger.Info("Rotating PD integration key", "IntegrationID", pdData.IntegrationID, "RequestedBefore", requested)
+	err = pdclient.RotateIntegrationKey(pdData)
+	if err != nil {
+		return false, err
+	}
+	// a failed update rotates the key again, the PagerDutyService is
+	// updated first so it never holds the deleted integration
+	err = r.savePagerDutyService(pdi, cd, configMapName, pdData.ServiceID, pdData.IntegrationID)
+	if err != nil {
+		return false, err
+	}
+	cm.Data["INTEGRATION_ID"] = pdData.IntegrationID
+	cm.Data[config.IntegrationKeyRotatedAtKey] = now.UTC().Format(time.RFC3339)
+	err = r.client.Update(context.TODO(), cm)
+	if err != nil {
+		return false, err
+	}
+	r.recordClusterEvent(pdi, cd, corev1.EventTypeNormal, eventIntegrationKeyRotated,
+		"Rotated the integration key of PD service %s, the previous key no longer works", pdData.ServiceID)
+	return true, nil
+}
+
+// keyRotationRequest returns the latest of the times the
+// RotateIntegrationKeyAnnotation of the clusterdeployment and of the
+// pagerdutyintegration are set to, false if neither is set. Annotations that
+// are not an RFC 3339 time are logged and ignored.
+func (r *ReconcilePagerDutyIntegration) keyRotationRequest(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) (time.Time, bool) {
+	var requested time.Time
+	found := false
+	for _, obj := range []metav1.Object{pdi, cd} {
+		value, ok := obj.GetAnnotations()[config.RotateIntegrationKeyAnnotation]
+		if !ok {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			r.reqLogger.Error(err, "Ignoring invalid annotation, expected an RFC 3339 time", "Annotation", config.RotateIntegrationKeyAnnotation, "Object", obj.GetName())
+			continue
+		}
+		if !found || t.After(requested) {
+			requested = t
+			found = true
+		}
+	}
+	return requested, found
+}
+
+// keyRotationTime returns when the integration key of the cluster of cm was
+// last rotated, false if it never was
+func keyRotationTime(cm *corev1.ConfigMap) (*metav1.Time, bool) {
+	rotated, err := time.Parse(time.RFC3339, cm.Data[config.IntegrationKeyRotatedAtKey])
+	if err != nil {
+		return nil, false
+	}
+	return &metav1.Time{Time: rotated}, true
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	mockpd "github.com/openshift/pagerduty-operator/pkg/pagerduty/mock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileKeyRotation(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	created := now.Add(-24 * time.Hour)
+	at := func(t time.Time) string { return t.Format(time.RFC3339) }
+
+	tests := []struct {
+		name          string
+		cdAnnotation  string
+		pdiAnnotation string
+		rotatedAt     string
+		expectRotated bool
+	}{
+		{
+			name: "Test No Annotation",
+		},
+		{
+			name:          "Test Cluster Key Rotated",
+			cdAnnotation:  at(now.Add(-time.Hour)),
+			expectRotated: true,
+		},
+		{
+			name:          "Test Fleet Keys Rotated",
+			pdiAnnotation: at(now.Add(-time.Hour)),
+			expectRotated: true,
+		},
+		{
+			name:          "Test Latest Annotation Applies",
+			cdAnnotation:  at(now.Add(-time.Hour)),
+			pdiAnnotation: at(now.Add(-2 * time.Hour)),
+			rotatedAt:     at(now.Add(-90 * time.Minute)),
+			expectRotated: true,
+		},
+		{
+			name:         "Test Key Already Rotated",
+			cdAnnotation: at(now.Add(-time.Hour)),
+			rotatedAt:    at(now.Add(-30 * time.Minute)),
+		},
+		{
+			name:         "Test Key Issued After Request",
+			cdAnnotation: at(created.Add(-time.Hour)),
+		},
+		{
+			name:         "Test Request In The Future",
+			cdAnnotation: at(now.Add(time.Hour)),
+		},
+		{
+			name:         "Test Invalid Annotation Ignored",
+			cdAnnotation: "now",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			cd := testClusterDeployment(true, true, true, false)
+			pdi := testPagerDutyIntegration()
+			if test.cdAnnotation != "" {
+				cd.Annotations = map[string]string{config.RotateIntegrationKeyAnnotation: test.cdAnnotation}
+			}
+			if test.pdiAnnotation != "" {
+				pdi.Annotations = map[string]string{config.RotateIntegrationKeyAnnotation: test.pdiAnnotation}
+			}
+			cm := testCDConfigMap()
+			cm.CreationTimestamp = metav1.Time{Time: created}
+			if test.rotatedAt != "" {
+				cm.Data[config.IntegrationKeyRotatedAtKey] = test.rotatedAt
+			}
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockPDClient := mockpd.NewMockClient(mockCtrl)
+			times := 0
+			if test.expectRotated {
+				times = 1
+			}
+			mockPDClient.EXPECT().RotateIntegrationKey(gomock.Any()).DoAndReturn(func(data *pd.Data) error {
+				data.IntegrationID = "ROTATED"
+				return nil
+			}).Times(times)
+
+			recorder := record.NewFakeRecorder(10)
+			r := &ReconcilePagerDutyIntegration{
+				client:    fakekubeclient.NewFakeClientWithScheme(scheme.Scheme, cd, cm),
+				scheme:    scheme.Scheme,
+				reqLogger: log,
+				recorder:  recorder,
+				clock:     func() time.Time { return now },
+			}
+			pdData := &pd.Data{ServiceID: testServiceID, IntegrationID: testIntegrationID}
+
+			// Act
+			rotated, err := r.reconcileKeyRotation(mockPDClient, pdi, cd, cm.Name, pdData)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectRotated, rotated)
+			updated := &corev1.ConfigMap{}
+			err = r.client.Get(context.TODO(), types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, updated)
+			assert.NoError(t, err)
+			events := eventsWithReason(recorder, eventIntegrationKeyRotated)
+			if test.expectRotated {
+				assert.Equal(t, "ROTATED", updated.Data["INTEGRATION_ID"])
+				assert.Equal(t, at(now), updated.Data[config.IntegrationKeyRotatedAtKey])
+				pdService := &pagerdutyv1alpha1.PagerDutyService{}
+				err = r.client.Get(context.TODO(), types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, pdService)
+				assert.NoError(t, err)
+				assert.Equal(t, "ROTATED", pdService.Spec.IntegrationID)
+				// on the ClusterDeployment and the PDI
+				assert.Len(t, events, 2)
+			} else {
+				assert.Equal(t, testIntegrationID, updated.Data["INTEGRATION_ID"])
+				assert.Equal(t, test.rotatedAt, updated.Data[config.IntegrationKeyRotatedAtKey])
+				assert.Empty(t, events)
+			}
+		})
+	}
+}
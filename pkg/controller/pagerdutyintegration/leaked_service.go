@@ -0,0 +1,100 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"fmt"
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/localmetrics"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// findServiceOfLostConfigMap looks up by its name the PagerDuty service of
+// a cluster torn down without the ConfigMap recording its ID, so it is not
+// left in PagerDuty. It returns true with the service set in pdData if the
+// service was found, false if there is none. When the lookup fails the
+// service may be left behind, which the LeakedService condition of the PDI,
+// an event and a metric report.
+func (r *ReconcilePagerDutyIntegration) findServiceOfLostConfigMap(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, pdData *pd.Data) bool {
+	pdData.NormalizeName = pdi.Spec.NormalizeServiceNames
+	setServiceName(pdi, cd, pdData)
+	name := pd.ServiceName(pdData)
+
+	service, err := pdclient.FindServiceByName(pdData)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed looking up PD service of ClusterDeployment without ConfigMap, it may be left in PagerDuty", "Name", name)
+		r.recordClusterEvent(pdi, cd, corev1.EventTypeWarning, eventPDServiceLeaked,
+			"PD service %s may be left in PagerDuty, its ConfigMap is missing and looking it up failed: %v", name, err)
+		setLeakedService(pdi, true, "ServiceLookupFailed",
+			fmt.Sprintf("PD service %s of ClusterDeployment %s/%s may be left in PagerDuty: %v", name, cd.Namespace, cd.Name, err))
+		localmetrics.IncMetricPagerDutyIntegrationLeakedServices(pdi.Name)
+		return false
+	}
+	if service == nil {
+		// never created, or already deleted
+		r.reqLogger.Info("No PD service found for ClusterDeployment without ConfigMap", "Name", name)
+		return false
+	}
+
+	r.reqLogger.Info("Found PD service of ClusterDeployment without ConfigMap", "Name", name, "ServiceID", service.ID)
+	pdData.ServiceID = service.ID
+	pdData.IntegrationID = pd.IntegrationID(service)
+	return true
+}
+
+// leakedService returns the LeakedService condition of the PDI while it is
+// true, nil otherwise
+func leakedService(pdi *pagerdutyv1alpha1.PagerDutyIntegration) *pagerdutyv1alpha1.PagerDutyIntegrationCondition {
+	for i := range pdi.Status.Conditions {
+		condition := &pdi.Status.Conditions[i]
+		if condition.Type == pagerdutyv1alpha1.PagerDutyIntegrationConditionLeakedService && condition.Status == corev1.ConditionTrue {
+			return condition
+		}
+	}
+	return nil
+}
+
+// setLeakedService sets the LeakedService condition of the PDI. The status
+// is persisted at the end of Reconcile.
+func setLeakedService(pdi *pagerdutyv1alpha1.PagerDutyIntegration, leaked bool, reason, message string) {
+	condition := pagerdutyv1alpha1.PagerDutyIntegrationCondition{
+		Type:    pagerdutyv1alpha1.PagerDutyIntegrationConditionLeakedService,
+		Status:  corev1.ConditionFalse,
+		Reason:  reason,
+		Message: logging.Redact(message),
+	}
+	if leaked {
+		condition.Status = corev1.ConditionTrue
+	}
+	setPagerDutyIntegrationCondition(&pdi.Status.Conditions, condition)
+}
+
+// clearLeakedServiceAfterSweep clears the LeakedService condition of the PDI
+// once an orphaned service sweep ran after it was set, as the sweep reports
+// or deletes the services whose cluster no longer exists
+func clearLeakedServiceAfterSweep(pdi *pagerdutyv1alpha1.PagerDutyIntegration, lastSweep *metav1.Time) {
+	condition := leakedService(pdi)
+	if condition == nil || lastSweep == nil || !lastSweep.After(condition.LastTransitionTime.Time) {
+		return
+	}
+	setLeakedService(pdi, false, "OrphanedServicesSwept",
+		fmt.Sprintf("Orphaned services swept at %s, which reports or deletes leaked services", lastSweep.UTC().Format(time.RFC3339)))
+}
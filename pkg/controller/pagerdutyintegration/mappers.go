@@ -19,9 +19,9 @@ import (
 	"strings"
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
 	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -40,12 +40,9 @@ func (m clusterDeploymentToPagerDutyIntegrationsMapper) Map(mo handler.MapObject
 	}
 
 	requests := []reconcile.Request{}
-	for _, pdi := range pdiList.Items {
-		selector, err := metav1.LabelSelectorAsSelector(&pdi.Spec.ClusterDeploymentSelector)
-		if err != nil {
-			continue
-		}
-		if selector.Matches(labels.Set(mo.Meta.GetLabels())) {
+	for i := range pdiList.Items {
+		pdi := &pdiList.Items[i]
+		if selectsClusterDeployment(pdi, mo.Meta) {
 			requests = append(requests, reconcile.Request{
 				NamespacedName: types.NamespacedName{
 					Name:      pdi.Name,
@@ -83,23 +80,147 @@ func (m ownedByClusterDeploymentToPagerDutyIntegrationsMapper) Map(mo handler.Ma
 		return []reconcile.Request{}
 	}
 
+	// objects labeled with the PDI that manages them only concern that PDI,
+	// there is no need to reconcile every PDI selecting the cluster
+	owner, labeled := mo.Meta.GetLabels()[config.PagerDutyIntegrationLabel]
+
 	requests := []reconcile.Request{}
-	for _, pdi := range pdiList.Items {
-		selector, err := metav1.LabelSelectorAsSelector(&pdi.Spec.ClusterDeploymentSelector)
-		if err != nil {
+	for i := range pdiList.Items {
+		pdi := &pdiList.Items[i]
+		if labeled && !isOwner(pdi, owner) {
 			continue
 		}
 
 		for _, cd := range relevantClusterDeployments {
-			if selector.Matches(labels.Set(cd.ObjectMeta.GetLabels())) {
+			if selectsClusterDeployment(pdi, cd) {
 				requests = append(requests, reconcile.Request{
 					NamespacedName: types.NamespacedName{
 						Name:      pdi.Name,
 						Namespace: pdi.Namespace,
 					}},
 				)
+				// one request per PDI is enough, however many of its clusters own the object
+				break
 			}
 		}
 	}
 	return requests
 }
+
+type clusterSyncToPagerDutyIntegrationsMapper struct {
+	Client client.Client
+}
+
+func (m clusterSyncToPagerDutyIntegrationsMapper) Map(mo handler.MapObject) []reconcile.Request {
+	// a ClusterSync has the same name and namespace as its ClusterDeployment
+	cd := &hivev1.ClusterDeployment{}
+	err := m.Client.Get(context.TODO(), client.ObjectKey{Name: mo.Meta.GetName(), Namespace: mo.Meta.GetNamespace()}, cd)
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	return clusterDeploymentToPagerDutyIntegrationsMapper{Client: m.Client}.Map(handler.MapObject{Meta: cd, Object: cd})
+}
+
+type silenceToPagerDutyIntegrationsMapper struct {
+	Client client.Client
+}
+
+func (m silenceToPagerDutyIntegrationsMapper) Map(mo handler.MapObject) []reconcile.Request {
+	silence, ok := mo.Object.(*pagerdutyv1alpha1.PagerDutySilence)
+	if !ok {
+		return []reconcile.Request{}
+	}
+
+	cd := &hivev1.ClusterDeployment{}
+	err := m.Client.Get(context.TODO(), client.ObjectKey{Name: silence.Spec.ClusterDeploymentRef.Name, Namespace: silence.Namespace}, cd)
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	return clusterDeploymentToPagerDutyIntegrationsMapper{Client: m.Client}.Map(handler.MapObject{Meta: cd, Object: cd})
+}
+
+type clusterConfigToPagerDutyIntegrationsMapper struct {
+	Client client.Client
+}
+
+func (m clusterConfigToPagerDutyIntegrationsMapper) Map(mo handler.MapObject) []reconcile.Request {
+	cfg, ok := mo.Object.(*pagerdutyv1alpha1.PagerDutyClusterConfig)
+	if !ok {
+		return []reconcile.Request{}
+	}
+
+	cd := &hivev1.ClusterDeployment{}
+	err := m.Client.Get(context.TODO(), client.ObjectKey{Name: cfg.Spec.ClusterDeploymentRef.Name, Namespace: cfg.Namespace}, cd)
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := clusterDeploymentToPagerDutyIntegrationsMapper{Client: m.Client}.Map(handler.MapObject{Meta: cd, Object: cd})
+	if cfg.Spec.PagerDutyIntegration == "" {
+		return requests
+	}
+	// only the PDI the config overrides is concerned
+	for _, request := range requests {
+		if request.Name == cfg.Spec.PagerDutyIntegration {
+			return []reconcile.Request{request}
+		}
+	}
+	return []reconcile.Request{}
+}
+
+type templateToPagerDutyIntegrationsMapper struct {
+	Client client.Client
+}
+
+func (m templateToPagerDutyIntegrationsMapper) Map(mo handler.MapObject) []reconcile.Request {
+	pdiList := &pagerdutyv1alpha1.PagerDutyIntegrationList{}
+	err := m.Client.List(context.TODO(), pdiList, client.InNamespace(mo.Meta.GetNamespace()))
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := []reconcile.Request{}
+	for _, pdi := range pdiList.Items {
+		if pdi.Spec.TemplateRef != nil && pdi.Spec.TemplateRef.Name == mo.Meta.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      pdi.Name,
+					Namespace: pdi.Namespace,
+				}},
+			)
+		}
+	}
+	return requests
+}
+
+type apiKeySecretToPagerDutyIntegrationsMapper struct {
+	Client client.Client
+}
+
+func (m apiKeySecretToPagerDutyIntegrationsMapper) Map(mo handler.MapObject) []reconcile.Request {
+	pdiList := &pagerdutyv1alpha1.PagerDutyIntegrationList{}
+	err := m.Client.List(context.TODO(), pdiList, &client.ListOptions{})
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	secret := corev1.SecretReference{Name: mo.Meta.GetName(), Namespace: mo.Meta.GetNamespace()}
+	requests := []reconcile.Request{}
+	for _, pdi := range pdiList.Items {
+		refersTo := pdi.Spec.PagerdutyApiKeySecretRef == secret
+		if migration := pdi.Spec.AccountMigration; migration != nil && migration.PagerdutyApiKeySecretRef == secret {
+			refersTo = true
+		}
+		if refersTo {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      pdi.Name,
+					Namespace: pdi.Namespace,
+				}},
+			)
+		}
+	}
+	return requests
+}
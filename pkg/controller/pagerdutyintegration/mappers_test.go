@@ -19,6 +19,7 @@ import (
 
 	hiveapis "github.com/openshift/hive/pkg/apis"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
 	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
 	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
 	"github.com/stretchr/testify/assert"
@@ -79,6 +80,49 @@ func TestClusterDeploymentToPagerDutyIntegrationsMapper(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "clusterDeploymentToPagerDutyIntegrations: matching an additional service",
+			mapper: clusterDeploymentToPagerDutyIntegrations,
+			objects: []runtime.Object{
+				withAdditionalService(pagerDutyIntegration("test1", map[string]string{"notmatching": "test"}), "customer", map[string]string{"test": "test"}),
+				pagerDutyIntegration("test2", map[string]string{"notmatching": "test"}),
+			},
+			mapObject: handler.MapObject{
+				Meta: &metav1.ObjectMeta{
+					Labels: map[string]string{"test": "test"},
+				},
+			},
+			expectedRequests: []reconcile.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      "test1",
+						Namespace: "test",
+					},
+				},
+			},
+		},
+		{
+			name:   "clusterDeploymentToPagerDutyIntegrations: opted in with the annotation",
+			mapper: clusterDeploymentToPagerDutyIntegrations,
+			objects: []runtime.Object{
+				withSelfServiceOnboarding(pagerDutyIntegration("test1", map[string]string{"notmatching": "test"})),
+				pagerDutyIntegration("test2", map[string]string{"notmatching": "test"}),
+			},
+			mapObject: handler.MapObject{
+				Meta: &metav1.ObjectMeta{
+					Labels:      map[string]string{"test": "test"},
+					Annotations: map[string]string{config.ClusterDeploymentIntegrationAnnotation: "test1"},
+				},
+			},
+			expectedRequests: []reconcile.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      "test1",
+						Namespace: "test",
+					},
+				},
+			},
+		},
 
 		{
 			name:    "ownedByClusterDeploymentToPagerDutyIntegrations: empty",
@@ -96,6 +140,253 @@ func TestClusterDeploymentToPagerDutyIntegrationsMapper(t *testing.T) {
 			},
 			expectedRequests: []reconcile.Request{},
 		},
+		{
+			name:   "ownedByClusterDeploymentToPagerDutyIntegrations: owned by two matching ClusterDeployments",
+			mapper: ownedByClusterDeploymentToPagerDutyIntegrations,
+			objects: []runtime.Object{
+				pagerDutyIntegration("test1", map[string]string{"test": "test"}),
+				clusterDeployment("cd1", map[string]string{"test": "test"}),
+				clusterDeployment("cd2", map[string]string{"test": "test"}),
+			},
+			mapObject: handler.MapObject{
+				Meta: &metav1.ObjectMeta{
+					Namespace:       "test",
+					OwnerReferences: []metav1.OwnerReference{clusterDeploymentOwner("cd1"), clusterDeploymentOwner("cd2")},
+				},
+			},
+			expectedRequests: []reconcile.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      "test1",
+						Namespace: "test",
+					},
+				},
+			},
+		},
+		{
+			name:   "ownedByClusterDeploymentToPagerDutyIntegrations: labeled object only maps to its PagerDutyIntegration",
+			mapper: ownedByClusterDeploymentToPagerDutyIntegrations,
+			objects: []runtime.Object{
+				pagerDutyIntegration("test1", map[string]string{"test": "test"}),
+				pagerDutyIntegration("test2", map[string]string{"test": "test"}),
+				clusterDeployment("cd1", map[string]string{"test": "test"}),
+			},
+			mapObject: handler.MapObject{
+				Meta: &metav1.ObjectMeta{
+					Namespace:       "test",
+					Labels:          map[string]string{config.PagerDutyIntegrationLabel: "test2"},
+					OwnerReferences: []metav1.OwnerReference{clusterDeploymentOwner("cd1")},
+				},
+			},
+			expectedRequests: []reconcile.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      "test2",
+						Namespace: "test",
+					},
+				},
+			},
+		},
+		{
+			name:   "ownedByClusterDeploymentToPagerDutyIntegrations: object of an additional service maps to its PagerDutyIntegration",
+			mapper: ownedByClusterDeploymentToPagerDutyIntegrations,
+			objects: []runtime.Object{
+				withAdditionalService(pagerDutyIntegration("test1", map[string]string{"notmatching": "test"}), "customer", map[string]string{"test": "test"}),
+				pagerDutyIntegration("test2", map[string]string{"test": "test"}),
+				clusterDeployment("cd1", map[string]string{"test": "test"}),
+			},
+			mapObject: handler.MapObject{
+				Meta: &metav1.ObjectMeta{
+					Namespace:       "test",
+					Labels:          map[string]string{config.PagerDutyIntegrationLabel: "test1.customer"},
+					OwnerReferences: []metav1.OwnerReference{clusterDeploymentOwner("cd1")},
+				},
+			},
+			expectedRequests: []reconcile.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      "test1",
+						Namespace: "test",
+					},
+				},
+			},
+		},
+
+		{
+			name:   "clusterSyncToPagerDutyIntegrations: ClusterDeployment matching one PagerDutyIntegration",
+			mapper: clusterSyncToPagerDutyIntegrations,
+			objects: []runtime.Object{
+				pagerDutyIntegration("test1", map[string]string{"test": "test"}),
+				pagerDutyIntegration("test2", map[string]string{"notmatching": "test"}),
+				&hivev1.ClusterDeployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cd",
+						Namespace: "test",
+						Labels:    map[string]string{"test": "test"},
+					},
+				},
+			},
+			mapObject: handler.MapObject{
+				Meta: &metav1.ObjectMeta{
+					Name:      "cd",
+					Namespace: "test",
+				},
+			},
+			expectedRequests: []reconcile.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      "test1",
+						Namespace: "test",
+					},
+				},
+			},
+		},
+		{
+			name:   "silenceToPagerDutyIntegrations: silenced ClusterDeployment matching one PagerDutyIntegration",
+			mapper: silenceToPagerDutyIntegrations,
+			objects: []runtime.Object{
+				pagerDutyIntegration("test1", map[string]string{"test": "test"}),
+				pagerDutyIntegration("test2", map[string]string{"notmatching": "test"}),
+				&hivev1.ClusterDeployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cd",
+						Namespace: "test",
+						Labels:    map[string]string{"test": "test"},
+					},
+				},
+			},
+			mapObject: silenceMapObject("cd"),
+			expectedRequests: []reconcile.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      "test1",
+						Namespace: "test",
+					},
+				},
+			},
+		},
+		{
+			name:             "silenceToPagerDutyIntegrations: missing ClusterDeployment",
+			mapper:           silenceToPagerDutyIntegrations,
+			objects:          []runtime.Object{pagerDutyIntegration("test1", map[string]string{"test": "test"})},
+			mapObject:        silenceMapObject("cd"),
+			expectedRequests: []reconcile.Request{},
+		},
+		{
+			name:   "clusterConfigToPagerDutyIntegrations: config of every PagerDutyIntegration",
+			mapper: clusterConfigToPagerDutyIntegrations,
+			objects: []runtime.Object{
+				pagerDutyIntegration("test1", map[string]string{"test": "test"}),
+				pagerDutyIntegration("test2", map[string]string{"test": "test"}),
+				pagerDutyIntegration("test3", map[string]string{"notmatching": "test"}),
+				clusterDeployment("cd", map[string]string{"test": "test"}),
+			},
+			mapObject: clusterConfigMapObject("cd", ""),
+			expectedRequests: []reconcile.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      "test1",
+						Namespace: "test",
+					},
+				},
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      "test2",
+						Namespace: "test",
+					},
+				},
+			},
+		},
+		{
+			name:   "clusterConfigToPagerDutyIntegrations: config of one PagerDutyIntegration",
+			mapper: clusterConfigToPagerDutyIntegrations,
+			objects: []runtime.Object{
+				pagerDutyIntegration("test1", map[string]string{"test": "test"}),
+				pagerDutyIntegration("test2", map[string]string{"test": "test"}),
+				clusterDeployment("cd", map[string]string{"test": "test"}),
+			},
+			mapObject: clusterConfigMapObject("cd", "test2"),
+			expectedRequests: []reconcile.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      "test2",
+						Namespace: "test",
+					},
+				},
+			},
+		},
+		{
+			name:   "clusterConfigToPagerDutyIntegrations: config of a PagerDutyIntegration not selecting the ClusterDeployment",
+			mapper: clusterConfigToPagerDutyIntegrations,
+			objects: []runtime.Object{
+				pagerDutyIntegration("test1", map[string]string{"test": "test"}),
+				pagerDutyIntegration("test2", map[string]string{"notmatching": "test"}),
+				clusterDeployment("cd", map[string]string{"test": "test"}),
+			},
+			mapObject:        clusterConfigMapObject("cd", "test2"),
+			expectedRequests: []reconcile.Request{},
+		},
+		{
+			name:   "templateToPagerDutyIntegrations: one referring PagerDutyIntegration",
+			mapper: templateToPagerDutyIntegrations,
+			objects: []runtime.Object{
+				withTemplate(pagerDutyIntegration("test1", map[string]string{"test": "test"}), "common"),
+				withTemplate(pagerDutyIntegration("test2", map[string]string{"test": "test"}), "other"),
+				pagerDutyIntegration("test3", map[string]string{"test": "test"}),
+			},
+			mapObject: templateMapObject("common"),
+			expectedRequests: []reconcile.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      "test1",
+						Namespace: "test",
+					},
+				},
+			},
+		},
+		{
+			name:   "apiKeySecretToPagerDutyIntegrations: PagerDutyIntegrations referring to the secret",
+			mapper: apiKeySecretToPagerDutyIntegrations,
+			objects: []runtime.Object{
+				pagerDutyIntegration("test1", map[string]string{"test": "test"}),
+				withAPIKeySecret(pagerDutyIntegration("test2", map[string]string{"test": "test"}), "other"),
+				withMigrationAPIKeySecret(withAPIKeySecret(pagerDutyIntegration("test3", map[string]string{"test": "test"}), "other"), "test"),
+			},
+			mapObject: handler.MapObject{
+				Meta: &metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "test",
+				},
+			},
+			expectedRequests: []reconcile.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      "test1",
+						Namespace: "test",
+					},
+				},
+				{
+					NamespacedName: types.NamespacedName{
+						Name:      "test3",
+						Namespace: "test",
+					},
+				},
+			},
+		},
+		{
+			name:   "apiKeySecretToPagerDutyIntegrations: same name in another namespace",
+			mapper: apiKeySecretToPagerDutyIntegrations,
+			objects: []runtime.Object{
+				pagerDutyIntegration("test1", map[string]string{"test": "test"}),
+			},
+			mapObject: handler.MapObject{
+				Meta: &metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "other",
+				},
+			},
+			expectedRequests: []reconcile.Request{},
+		},
 	}
 
 	for _, test := range tests {
@@ -118,6 +409,82 @@ func ownedByClusterDeploymentToPagerDutyIntegrations(client client.Client) handl
 	return ownedByClusterDeploymentToPagerDutyIntegrationsMapper{Client: client}
 }
 
+func clusterSyncToPagerDutyIntegrations(client client.Client) handler.Mapper {
+	return clusterSyncToPagerDutyIntegrationsMapper{Client: client}
+}
+
+func silenceToPagerDutyIntegrations(client client.Client) handler.Mapper {
+	return silenceToPagerDutyIntegrationsMapper{Client: client}
+}
+
+func clusterConfigToPagerDutyIntegrations(client client.Client) handler.Mapper {
+	return clusterConfigToPagerDutyIntegrationsMapper{Client: client}
+}
+
+func templateToPagerDutyIntegrations(client client.Client) handler.Mapper {
+	return templateToPagerDutyIntegrationsMapper{Client: client}
+}
+
+func apiKeySecretToPagerDutyIntegrations(client client.Client) handler.Mapper {
+	return apiKeySecretToPagerDutyIntegrationsMapper{Client: client}
+}
+
+func templateMapObject(name string) handler.MapObject {
+	template := &pagerdutyv1alpha1.PagerDutyIntegrationTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+		},
+	}
+	return handler.MapObject{Meta: template, Object: template}
+}
+
+func silenceMapObject(clusterDeploymentName string) handler.MapObject {
+	silence := &pagerdutyv1alpha1.PagerDutySilence{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "silence",
+			Namespace: "test",
+		},
+		Spec: pagerdutyv1alpha1.PagerDutySilenceSpec{
+			ClusterDeploymentRef: v1.LocalObjectReference{Name: clusterDeploymentName},
+		},
+	}
+	return handler.MapObject{Meta: silence, Object: silence}
+}
+
+func clusterConfigMapObject(clusterDeploymentName, pdiName string) handler.MapObject {
+	cfg := &pagerdutyv1alpha1.PagerDutyClusterConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "config",
+			Namespace: "test",
+		},
+		Spec: pagerdutyv1alpha1.PagerDutyClusterConfigSpec{
+			ClusterDeploymentRef: v1.LocalObjectReference{Name: clusterDeploymentName},
+			PagerDutyIntegration: pdiName,
+		},
+	}
+	return handler.MapObject{Meta: cfg, Object: cfg}
+}
+
+func clusterDeployment(name string, labels map[string]string) *hivev1.ClusterDeployment {
+	return &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+			Labels:    labels,
+		},
+	}
+}
+
+func clusterDeploymentOwner(name string) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: hivev1.SchemeGroupVersion.String(),
+		Kind:       "ClusterDeployment",
+		Name:       name,
+		UID:        types.UID(name),
+	}
+}
+
 func pagerDutyIntegration(name string, labels map[string]string) *pagerdutyv1alpha1.PagerDutyIntegration {
 	return &pagerdutyv1alpha1.PagerDutyIntegration{
 		ObjectMeta: metav1.ObjectMeta{
@@ -141,3 +508,44 @@ func pagerDutyIntegration(name string, labels map[string]string) *pagerdutyv1alp
 		},
 	}
 }
+
+func withAdditionalService(pdi *pagerdutyv1alpha1.PagerDutyIntegration, servicePrefix string, labels map[string]string) *pagerdutyv1alpha1.PagerDutyIntegration {
+	pdi.Spec.AdditionalServices = append(pdi.Spec.AdditionalServices, pagerdutyv1alpha1.AdditionalService{
+		ServicePrefix:    servicePrefix,
+		EscalationPolicy: "DEF456",
+		ClusterDeploymentSelector: metav1.LabelSelector{
+			MatchLabels: labels,
+		},
+		TargetSecretRef: v1.SecretReference{
+			Name:      servicePrefix,
+			Namespace: "test",
+		},
+	})
+	return pdi
+}
+
+func withSelfServiceOnboarding(pdi *pagerdutyv1alpha1.PagerDutyIntegration) *pagerdutyv1alpha1.PagerDutyIntegration {
+	pdi.Spec.SelfServiceOnboarding = &pagerdutyv1alpha1.SelfServiceOnboarding{}
+	return pdi
+}
+
+func withTemplate(pdi *pagerdutyv1alpha1.PagerDutyIntegration, templateName string) *pagerdutyv1alpha1.PagerDutyIntegration {
+	pdi.Spec.TemplateRef = &v1.LocalObjectReference{Name: templateName}
+	return pdi
+}
+
+func withAPIKeySecret(pdi *pagerdutyv1alpha1.PagerDutyIntegration, secretName string) *pagerdutyv1alpha1.PagerDutyIntegration {
+	pdi.Spec.PagerdutyApiKeySecretRef.Name = secretName
+	return pdi
+}
+
+func withMigrationAPIKeySecret(pdi *pagerdutyv1alpha1.PagerDutyIntegration, secretName string) *pagerdutyv1alpha1.PagerDutyIntegration {
+	pdi.Spec.AccountMigration = &pagerdutyv1alpha1.AccountMigration{
+		PagerdutyApiKeySecretRef: v1.SecretReference{
+			Name:      secretName,
+			Namespace: "test",
+		},
+		EscalationPolicy: "DEF456",
+	}
+	return pdi
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// recreateMissingIntegration creates the integration of the cluster's
+// service anew if it was deleted in PagerDuty, and records it in the
+// PagerDutyService and the ConfigMap. The key of the deleted integration no
+// longer works, so it returns true if it did for the new key to be synced.
+func (r *ReconcilePagerDutyIntegration) recreateMissingIntegration(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, configMapName string, pdData *pd.Data) (bool, error) {
+	deleted := pdData.IntegrationID
+	recreated, err := pdclient.RecreateMissingIntegration(pdData)
+	if err != nil || !recreated {
+		return false, err
+	}
+	r.reqLogger.Info("Recreated PD integration deleted in PagerDuty", "ServiceID", pdData.ServiceID, "DeletedIntegrationID", deleted, "IntegrationID", pdData.IntegrationID)
+
+	// a failed update recreates the integration again, the PagerDutyService
+	// is updated first so it never holds the deleted integration
+	err = r.savePagerDutyService(pdi, cd, configMapName, pdData.ServiceID, pdData.IntegrationID)
+	if err != nil {
+		return false, err
+	}
+	cm := &corev1.ConfigMap{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: configMapName, Namespace: cd.Namespace}, cm)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	if err == nil {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data["INTEGRATION_ID"] = pdData.IntegrationID
+		err = r.client.Update(context.TODO(), cm)
+		if err != nil {
+			return false, err
+		}
+	}
+	r.recordClusterEvent(pdi, cd, corev1.EventTypeWarning, eventIntegrationRecreated,
+		"Recreated the integration of PD service %s, deleted in PagerDuty, the previous key no longer works", pdData.ServiceID)
+	return true, nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	mockpd "github.com/openshift/pagerduty-operator/pkg/pagerduty/mock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRecreateMissingIntegration(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name            string
+		recreated       bool
+		recreateErr     error
+		expectRecreated bool
+		expectErr       bool
+	}{
+		{
+			name: "Test Integration In Place",
+		},
+		{
+			name:            "Test Integration Deleted",
+			recreated:       true,
+			expectRecreated: true,
+		},
+		{
+			name:        "Test Lookup Failed",
+			recreateErr: errors.New("Failed call API endpoint. HTTP response code: 500. Error: &{}"),
+			expectErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			cd := testClusterDeployment(true, true, true, false)
+			pdi := testPagerDutyIntegration()
+			cm := testCDConfigMap()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockPDClient := mockpd.NewMockClient(mockCtrl)
+			mockPDClient.EXPECT().RecreateMissingIntegration(gomock.Any()).DoAndReturn(func(data *pd.Data) (bool, error) {
+				if test.recreated {
+					data.IntegrationID = "RECREATED"
+				}
+				return test.recreated, test.recreateErr
+			}).Times(1)
+
+			recorder := record.NewFakeRecorder(10)
+			r := &ReconcilePagerDutyIntegration{
+				client:    fakekubeclient.NewFakeClientWithScheme(scheme.Scheme, cd, cm),
+				scheme:    scheme.Scheme,
+				reqLogger: log,
+				recorder:  recorder,
+			}
+			pdData := &pd.Data{ServiceID: testServiceID, IntegrationID: testIntegrationID}
+
+			// Act
+			recreated, err := r.recreateMissingIntegration(mockPDClient, pdi, cd, cm.Name, pdData)
+
+			// Assert
+			assert.Equal(t, test.expectErr, err != nil)
+			assert.Equal(t, test.expectRecreated, recreated)
+			updated := &corev1.ConfigMap{}
+			err = r.client.Get(context.TODO(), types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, updated)
+			assert.NoError(t, err)
+			events := eventsWithReason(recorder, eventIntegrationRecreated)
+			if test.expectRecreated {
+				assert.Equal(t, "RECREATED", updated.Data["INTEGRATION_ID"])
+				pdService := &pagerdutyv1alpha1.PagerDutyService{}
+				err = r.client.Get(context.TODO(), types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, pdService)
+				assert.NoError(t, err)
+				assert.Equal(t, "RECREATED", pdService.Spec.IntegrationID)
+				// on the ClusterDeployment and the PDI
+				assert.Len(t, events, 2)
+			} else {
+				assert.Equal(t, testIntegrationID, updated.Data["INTEGRATION_ID"])
+				assert.Empty(t, events)
+			}
+		})
+	}
+}
+
+func TestServiceVerificationRecreatesMissingIntegration(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	// Arrange
+	cd := testClusterDeployment(true, true, true, false)
+	pdi := testPagerDutyIntegration()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPDClient := mockpd.NewMockClient(mockCtrl)
+	mockPDClient.EXPECT().GetService(gomock.Any()).Return(testPDService(), nil).Times(1)
+	mockPDClient.EXPECT().RecreateMissingIntegration(gomock.Any()).DoAndReturn(func(data *pd.Data) (bool, error) {
+		data.IntegrationID = "RECREATED"
+		return true, nil
+	}).Times(1)
+
+	r := &ReconcilePagerDutyIntegration{
+		client:    fakekubeclient.NewFakeClientWithScheme(scheme.Scheme, cd, pdi, testCDConfigMap(), testCDSecret()),
+		scheme:    scheme.Scheme,
+		reqLogger: log,
+	}
+
+	// Act
+	condition, err := r.serviceVerificationCondition(mockPDClient, pdi, cd)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	// the Secret holding the dead key is synced anew by handleCreate
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: naming.SecretName(testServicePrefix, testClusterName), Namespace: testNamespace}, &corev1.Secret{})
+	assert.True(t, kerrors.IsNotFound(err))
+}
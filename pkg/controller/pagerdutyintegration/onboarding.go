@@ -0,0 +1,120 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"sync"
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// onboardingClusters records the ClusterDeployments whose managed label
+// turned true, keyed by namespace/name, with the time it did. The watch
+// noticing the change and the reconciles onboarding them share it.
+type onboardingClusters struct {
+	mu       sync.Mutex
+	clusters map[string]time.Time
+}
+
+func newOnboardingClusters() *onboardingClusters {
+	return &onboardingClusters{clusters: map[string]time.Time{}}
+}
+
+// add records that the ClusterDeployment namespace/name became managed at t
+func (o *onboardingClusters) add(namespace, name string, t time.Time) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.clusters[namespace+"/"+name] = t
+}
+
+// has returns true if the ClusterDeployment namespace/name became managed
+// less than config.OnboardingFastPathWindow before now. Older records are
+// dropped, the regular pass over the fleet has onboarded them by then.
+func (o *onboardingClusters) has(namespace, name string, now time.Time) bool {
+	if o == nil {
+		return false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for key, t := range o.clusters {
+		if now.Sub(t) > config.OnboardingFastPathWindow {
+			delete(o.clusters, key)
+		}
+	}
+	_, ok := o.clusters[namespace+"/"+name]
+	return ok
+}
+
+// managedLabelTurnedTrue passes the updates of ClusterDeployments whose
+// managed label changes to true
+var managedLabelTurnedTrue = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if e.MetaOld == nil || e.MetaNew == nil {
+			return false
+		}
+		return e.MetaOld.GetLabels()[config.ClusterDeploymentManagedLabel] != "true" &&
+			e.MetaNew.GetLabels()[config.ClusterDeploymentManagedLabel] == "true"
+	},
+}
+
+// onboardingClusterDeploymentToPagerDutyIntegrationsMapper records a
+// ClusterDeployment that became managed for the fast path of the reconciles
+// of the PagerDutyIntegrations selecting it, which it queues
+type onboardingClusterDeploymentToPagerDutyIntegrationsMapper struct {
+	clusterDeploymentToPagerDutyIntegrationsMapper
+	onboarding *onboardingClusters
+}
+
+func (m onboardingClusterDeploymentToPagerDutyIntegrationsMapper) Map(mo handler.MapObject) []reconcile.Request {
+	log.Info("ClusterDeployment became managed, onboarding it", "Namespace", mo.Meta.GetNamespace(), "Name", mo.Meta.GetName())
+	m.onboarding.add(mo.Meta.GetNamespace(), mo.Meta.GetName(), time.Now())
+	return m.clusterDeploymentToPagerDutyIntegrationsMapper.Map(mo)
+}
+
+// onboardClusters sets up, ahead of the rest of the reconcile, the selected
+// clusters that recently became managed and were not set up for the PDI
+// yet, so they page within minutes instead of waiting behind the teardown
+// and setup of the whole fleet. The regular pass handles them again, so
+// failures are only recorded as retry reasons.
+func (r *ReconcilePagerDutyIntegration) onboardClusters(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, finalizer string, matching []hivev1.ClusterDeployment, referencesValid bool) {
+	now := r.now()
+	onboarding := []hivev1.ClusterDeployment{}
+	for _, cd := range matching {
+		if !utils.HasFinalizer(&cd, finalizer) && r.onboarding.has(cd.Namespace, cd.Name, now) {
+			onboarding = append(onboarding, cd)
+		}
+	}
+	if len(onboarding) == 0 {
+		return
+	}
+
+	r.reqLogger.Info("Onboarding clusters that became managed", "Count", len(onboarding))
+	err := r.createClusters(pdclient, pdi, onboarding, referencesValid)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed onboarding clusters that became managed, the regular pass retries them")
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"testing"
+	"time"
+
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+func TestManagedLabelTurnedTrue(t *testing.T) {
+	meta := func(managed string) *metav1.ObjectMeta {
+		labels := map[string]string{}
+		if managed != "" {
+			labels[config.ClusterDeploymentManagedLabel] = managed
+		}
+		return &metav1.ObjectMeta{Labels: labels}
+	}
+
+	tests := []struct {
+		name   string
+		old    string
+		new    string
+		expect bool
+	}{
+		{name: "False To True", old: "false", new: "true", expect: true},
+		{name: "Unset To True", old: "", new: "true", expect: true},
+		{name: "True To True", old: "true", new: "true"},
+		{name: "True To False", old: "true", new: "false"},
+		{name: "False To False", old: "false", new: "false"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := event.UpdateEvent{MetaOld: meta(test.old), MetaNew: meta(test.new)}
+			assert.Equal(t, test.expect, managedLabelTurnedTrue.Update(e))
+		})
+	}
+
+	assert.False(t, managedLabelTurnedTrue.Create(event.CreateEvent{Meta: meta("true")}))
+}
+
+func TestOnboardingMapperRecordsCluster(t *testing.T) {
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+
+	onboarding := newOnboardingClusters()
+	m := onboardingClusterDeploymentToPagerDutyIntegrationsMapper{
+		clusterDeploymentToPagerDutyIntegrationsMapper: clusterDeploymentToPagerDutyIntegrationsMapper{
+			Client: fake.NewFakeClient([]runtime.Object{pagerDutyIntegration("test1", map[string]string{"test": "test"})}...),
+		},
+		onboarding: onboarding,
+	}
+
+	requests := m.Map(handler.MapObject{
+		Meta: &metav1.ObjectMeta{Name: "cd", Namespace: "ns", Labels: map[string]string{"test": "test"}},
+	})
+
+	assert.Len(t, requests, 1)
+	assert.True(t, onboarding.has("ns", "cd", time.Now()))
+	assert.False(t, onboarding.has("ns", "cd", time.Now().Add(config.OnboardingFastPathWindow+time.Minute)))
+	// expired records are dropped
+	assert.False(t, onboarding.has("ns", "cd", time.Now()))
+}
+
+func TestOnboardClusters(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	finalizer := config.PagerDutyFinalizerPrefix + testPagerDutyIntegrationName
+
+	onboarded := fleetClusterDeployment("cluster-onboarded", false)
+	newlyManaged := fleetClusterDeployment("cluster-newly-managed", false)
+	newlyManaged.Finalizers = nil
+	unchanged := fleetClusterDeployment("cluster-unchanged", false)
+	unchanged.Finalizers = nil
+	matching := []hivev1.ClusterDeployment{onboarded, newlyManaged, unchanged}
+
+	onboarding := newOnboardingClusters()
+	onboarding.add(testNamespace, "cluster-onboarded", now)
+	onboarding.add(testNamespace, "cluster-newly-managed", now)
+
+	handler := &fakeClusterHandler{}
+	r := &ReconcilePagerDutyIntegration{
+		reqLogger:  log,
+		clusters:   handler,
+		clock:      func() time.Time { return now.Add(time.Minute) },
+		onboarding: onboarding,
+	}
+
+	r.onboardClusters(nil, testPagerDutyIntegration(), finalizer, matching, true)
+
+	// only the recorded cluster not set up for the PDI yet
+	assert.Equal(t, []string{"cluster-newly-managed"}, handler.created)
+
+	// nothing is fast-pathed without the record
+	handler = &fakeClusterHandler{}
+	r = &ReconcilePagerDutyIntegration{reqLogger: log, clusters: handler}
+	r.onboardClusters(nil, testPagerDutyIntegration(), finalizer, matching, true)
+	assert.Empty(t, handler.created)
+}
@@ -0,0 +1,192 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// serviceNameSuffix ends the name of every service the operator creates,
+// unless the name was truncated
+const serviceNameSuffix = "-hive-cluster"
+
+// sweepOrphanedServices lists the PagerDuty services named after the PDI's
+// servicePrefix when spec.orphanedServiceSweep.interval has passed since the
+// last sweep, and reports or deletes those whose cluster is none of the
+// given ClusterDeployments. A service is left alone when any ConfigMap or
+// status.retainedServices records its ID, when another PagerDutyIntegration
+// or additional service has a longer prefix matching its name, or when its
+// name was truncated. Services are only reported, never deleted, while
+// spec.serviceDeletionPolicy keeps those of deleted clusters. It returns the
+// orphaned services, the time of the last sweep and how long until the next
+// one, 0 when the sweep is disabled.
+func (r *ReconcilePagerDutyIntegration) sweepOrphanedServices(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cds []hivev1.ClusterDeployment) ([]pagerdutyv1alpha1.OrphanedService, *metav1.Time, time.Duration, error) {
+	sweep := pdi.Spec.OrphanedServiceSweep
+	if sweep == nil {
+		return nil, nil, 0, nil
+	}
+
+	interval := config.OrphanedServiceSweepDefaultInterval
+	if sweep.Interval != nil {
+		interval = sweep.Interval.Duration
+	}
+	if interval < config.OrphanedServiceSweepMinInterval {
+		interval = config.OrphanedServiceSweepMinInterval
+	}
+
+	now := r.now()
+	if last := pdi.Status.LastOrphanedServiceSweepTime; last != nil {
+		if wait := last.Add(interval).Sub(now); wait > 0 {
+			return pdi.Status.OrphanedServices, last, wait, nil
+		}
+	}
+
+	names := map[string]bool{}
+	for i := range cds {
+		pdData := &pd.Data{
+			ClusterID:     cds[i].Spec.ClusterName,
+			BaseDomain:    cds[i].Spec.BaseDomain,
+			ServicePrefix: pdi.Spec.ServicePrefix,
+		}
+		names[strings.ToLower(pd.ServiceName(pdData))] = true
+		pdData.NormalizeName = true
+		names[pd.ServiceName(pdData)] = true
+		if pdi.Spec.ServiceNameTemplate != "" {
+			setServiceName(pdi, &cds[i], pdData)
+			names[strings.ToLower(pd.ServiceName(pdData))] = true
+			pdData.NormalizeName = false
+			names[strings.ToLower(pd.ServiceName(pdData))] = true
+		}
+	}
+
+	ids, err := r.recordedServiceIDs(pdi)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	otherPrefixes, err := r.otherServicePrefixes(pdi)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	services, err := pdclient.ListServicesByPrefix(pdi.Spec.ServicePrefix)
+	if err != nil {
+		// sweeping again on the next reconcile is good enough
+		r.reqLogger.Error(err, "Failed to list PagerDuty services")
+		return pdi.Status.OrphanedServices, pdi.Status.LastOrphanedServiceSweepTime, interval, nil
+	}
+
+	orphans := []pagerdutyv1alpha1.OrphanedService{}
+	for i := range services {
+		service := &services[i]
+		name := strings.ToLower(service.Name)
+		if names[name] || ids[service.ID] || !strings.HasSuffix(name, serviceNameSuffix) || hasAnyPrefix(name, otherPrefixes) {
+			continue
+		}
+
+		if sweep.Action == pagerdutyv1alpha1.OrphanedServiceActionDelete && !keepsServices(pdi) {
+			r.reqLogger.Info("Deleting orphaned PD service", "ServiceID", service.ID, "Name", service.Name)
+			err = pdclient.DeleteService(&pd.Data{ServiceID: service.ID, IntegrationID: pd.IntegrationID(service)})
+			if err == nil || pd.IsNotFound(err) {
+				r.recorder.Eventf(pdi, corev1.EventTypeNormal, "OrphanedServiceDeleted",
+					"Deleted PagerDuty service %s (%s) whose cluster no longer exists", service.ID, service.Name)
+				continue
+			}
+			// deleting it again on the next sweep is good enough
+			r.reqLogger.Error(err, "Failed deleting orphaned PD service", "ServiceID", service.ID)
+		}
+
+		r.recorder.Eventf(pdi, corev1.EventTypeWarning, "OrphanedServiceFound",
+			"PagerDuty service %s (%s) has no cluster", service.ID, service.Name)
+		orphans = append(orphans, pagerdutyv1alpha1.OrphanedService{
+			ServiceID: service.ID,
+			Name:      service.Name,
+		})
+	}
+
+	return orphans, &metav1.Time{Time: now}, interval, nil
+}
+
+// recordedServiceIDs returns the IDs of the services recorded in the
+// ConfigMaps of all PagerDutyIntegrations, whatever their prefix, and of
+// those retained for a reinstall
+func (r *ReconcilePagerDutyIntegration) recordedServiceIDs(pdi *pagerdutyv1alpha1.PagerDutyIntegration) (map[string]bool, error) {
+	// ConfigMaps created before the owner label was set don't have it, so
+	// all are looked at
+	cms := &corev1.ConfigMapList{}
+	err := r.client.List(context.TODO(), cms, &client.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[string]bool{}
+	for _, cm := range cms.Items {
+		if id := cm.Data["SERVICE_ID"]; id != "" {
+			ids[id] = true
+		}
+	}
+	for _, retained := range pdi.Status.RetainedServices {
+		ids[retained.ServiceID] = true
+	}
+	return ids, nil
+}
+
+// otherServicePrefixes returns, in lower case and followed by '-', the
+// service prefixes of the PagerDutyIntegrations and additional services
+// that are longer than the PDI's and start with it, so their services also
+// start with the PDI's prefix
+func (r *ReconcilePagerDutyIntegration) otherServicePrefixes(pdi *pagerdutyv1alpha1.PagerDutyIntegration) ([]string, error) {
+	pdiList := &pagerdutyv1alpha1.PagerDutyIntegrationList{}
+	err := r.client.List(context.TODO(), pdiList, &client.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	own := strings.ToLower(pdi.Spec.ServicePrefix) + "-"
+	prefixes := []string{}
+	add := func(prefix string) {
+		prefix = strings.ToLower(prefix) + "-"
+		if len(prefix) > len(own) && strings.HasPrefix(prefix, own) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	for _, other := range pdiList.Items {
+		add(other.Spec.ServicePrefix)
+		for _, additional := range other.Spec.AdditionalServices {
+			add(additional.ServicePrefix)
+		}
+	}
+	return prefixes, nil
+}
+
+// hasAnyPrefix returns true if s starts with one of prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"fmt"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/kube"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// errManagedByOther is returned when a secondary resource of a
+// ClusterDeployment belongs to another PagerDutyIntegration, which happens
+// when two PagerDutyIntegrations selecting the same cluster share a
+// ServicePrefix.
+type errManagedByOther struct {
+	kind  string
+	name  string
+	owner string
+}
+
+func (e *errManagedByOther) Error() string {
+	return fmt.Sprintf("%s %s is managed by PagerDutyIntegration %s", e.kind, e.name, e.owner)
+}
+
+// isManagedByOther returns true if err is an errManagedByOther
+func isManagedByOther(err error) bool {
+	_, ok := err.(*errManagedByOther)
+	return ok
+}
+
+// setOwnerLabel marks obj as managed by the given PagerDutyIntegration.
+func setOwnerLabel(obj metav1.Object, pdi *pagerdutyv1alpha1.PagerDutyIntegration) {
+	kube.SetOwnerLabel(obj, pdi)
+}
+
+// claimSecondaryResources makes sure the ConfigMap, Secret and SyncSets of a
+// ClusterDeployment that already exist belong to the given
+// PagerDutyIntegration. Resources created before they were labeled are
+// claimed. An errManagedByOther is returned if any belongs to another
+// PagerDutyIntegration.
+func (r *ReconcilePagerDutyIntegration) claimSecondaryResources(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) error {
+	resources := []struct {
+		kind string
+		name string
+		obj  runtime.Object
+	}{
+		{"ConfigMap", naming.ConfigMapName(pdi.Spec.ServicePrefix, cd.Name), &corev1.ConfigMap{}},
+		{"Secret", naming.SecretName(pdi.Spec.ServicePrefix, cd.Name), &corev1.Secret{}},
+		{"SyncSet", naming.SyncSetName(pdi.Spec.ServicePrefix, cd.Name), &hivev1.SyncSet{}},
+		{"SyncSet", naming.ProbeSyncSetName(pdi.Spec.ServicePrefix, cd.Name), &hivev1.SyncSet{}},
+		{"SyncSet", naming.AlertmanagerSyncSetName(pdi.Spec.ServicePrefix, cd.Name), &hivev1.SyncSet{}},
+	}
+
+	for _, res := range resources {
+		err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: res.name}, res.obj)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		meta := res.obj.(metav1.Object)
+		owner, labeled := meta.GetLabels()[config.PagerDutyIntegrationLabel]
+		if labeled && owner != pdi.Name {
+			return &errManagedByOther{kind: res.kind, name: cd.Namespace + "/" + res.name, owner: owner}
+		}
+		if !labeled {
+			r.reqLogger.Info("Claiming unlabeled resource", "Kind", res.kind, "Namespace", cd.Namespace, "Name", res.name)
+			baseToPatch := client.MergeFrom(res.obj.DeepCopyObject())
+			setOwnerLabel(meta, pdi)
+			err = r.client.Patch(context.TODO(), res.obj, baseToPatch)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
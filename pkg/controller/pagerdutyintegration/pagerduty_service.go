@@ -0,0 +1,88 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/kube"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// savePagerDutyService records the IDs of the PagerDuty service of cd in its
+// PagerDutyService, named like its ConfigMap. Clusters set up before
+// PagerDutyServices existed get theirs created from the IDs of their
+// ConfigMap, which migrates them.
+func (r *ReconcilePagerDutyIntegration) savePagerDutyService(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, name, serviceID, integrationID string) error {
+	pdService, err := r.pagerDutyService(cd.Namespace, name)
+	if err != nil {
+		return err
+	}
+
+	if pdService == nil {
+		r.reqLogger.Info("Creating PagerDutyService", "Namespace", cd.Namespace, "Name", name)
+		pdService = kube.GeneratePagerDutyService(cd.Namespace, name, pdi.Name, cd.Name, serviceID, integrationID)
+		setOwnerLabel(pdService, pdi)
+		if err = controllerutil.SetControllerReference(cd, pdService, r.scheme); err != nil {
+			return err
+		}
+		return r.client.Create(context.TODO(), pdService)
+	}
+
+	if pdService.Spec.ServiceID == serviceID && pdService.Spec.IntegrationID == integrationID {
+		return nil
+	}
+	r.reqLogger.Info("Updating PagerDutyService", "Namespace", cd.Namespace, "Name", name)
+	pdService.Spec.ServiceID = serviceID
+	pdService.Spec.IntegrationID = integrationID
+	return r.client.Update(context.TODO(), pdService)
+}
+
+// pagerDutyService returns the PagerDutyService of the given name, nil if
+// there is none
+func (r *ReconcilePagerDutyIntegration) pagerDutyService(namespace, name string) (*pagerdutyv1alpha1.PagerDutyService, error) {
+	pdService := &pagerdutyv1alpha1.PagerDutyService{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, pdService)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return pdService, nil
+}
+
+// updatePagerDutyServiceStatus copies the status of the cluster into its
+// PagerDutyService, so it can be watched without reading the status of the
+// whole PagerDutyIntegration
+func (r *ReconcilePagerDutyIntegration) updatePagerDutyServiceStatus(pdService *pagerdutyv1alpha1.PagerDutyService, status *pagerdutyv1alpha1.ClusterStatus) error {
+	expected := pagerdutyv1alpha1.PagerDutyServiceStatus{
+		State:            status.State,
+		LastError:        status.LastError,
+		RetryReason:      status.RetryReason,
+		Conditions:       status.Conditions,
+		LastVerifiedTime: status.LastVerifiedTime,
+	}
+	if equality.Semantic.DeepEqual(pdService.Status, expected) {
+		return nil
+	}
+	pdService.Status = expected
+	return r.client.Status().Update(context.TODO(), pdService)
+}
@@ -16,25 +16,32 @@ package pagerdutyintegration
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hiveintv1alpha1 "github.com/openshift/hive/pkg/apis/hiveinternal/v1alpha1"
 	"github.com/openshift/pagerduty-operator/config"
 	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/dryrun"
 	"github.com/openshift/pagerduty-operator/pkg/localmetrics"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
 	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/pause"
 	"github.com/openshift/pagerduty-operator/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
@@ -52,20 +59,36 @@ var log = logf.Log.WithName("controller_pagerdutyintegration")
 // Add creates a new PagerDutyIntegration Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	r := newReconciler(mgr)
+	return add(mgr, pause.Wrap(r, mgr.GetAPIReader(), config.GetOperatorNamespace()), r.onboarding, r.offboarding)
+}
+
+// newPDClient makes a PagerDuty client logging its API calls with the
+// controller's logger
+func newPDClient(APIKey string, controllerName string, opts ...pd.ClientOption) pd.Client {
+	return pd.NewClient(APIKey, controllerName, append([]pd.ClientOption{pd.WithLogger(log)}, opts...)...)
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+func newReconciler(mgr manager.Manager) *ReconcilePagerDutyIntegration {
 	return &ReconcilePagerDutyIntegration{
-		client:   utils.NewClientWithMetricsOrDie(log, mgr, controllerName),
-		scheme:   mgr.GetScheme(),
-		pdclient: pd.NewClient,
+		client:       utils.NewClientWithMetricsOrDie(log, mgr, controllerName),
+		scheme:       mgr.GetScheme(),
+		pdclient:     newPDClient,
+		remoteClient: newRemoteClient,
+		recorder:     logging.NewRedactingRecorder(mgr.GetEventRecorderFor(controllerName)),
+		onboarding:   newOnboardingClusters(),
+		offboarding:  newOnboardingClusters(),
+		reader:       mgr.GetAPIReader(),
+
+		maxConcurrentClusterSyncs: maxConcurrentClusterSyncs,
+		resyncPeriod:              resyncPeriod,
+		errorRequeueDelay:         errorRequeueDelay,
 	}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r reconcile.Reconciler, onboarding, offboarding *onboardingClusters) error {
 	// Create a new controller
 	c, err := controller.New("pagerdutyintegration-controller", mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -79,13 +102,124 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}
 
 	// Watch for changes to ClusterDeployments, and queue a request for all
-	// PagerDutyIntegration CR that selects it.
+	// PagerDutyIntegration CR that selects it. Changes of labels are left
+	// to the watch below.
 	err = c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}},
 		&handler.EnqueueRequestsFromMapFunc{
 			ToRequests: clusterDeploymentToPagerDutyIntegrationsMapper{
 				Client: mgr.GetClient(),
 			},
 		},
+		labelsUnchanged,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the labels of ClusterDeployments, and queue a
+	// request for the PagerDutyIntegration CRs the change concerns only,
+	// recording the clusters they stopped selecting to tear them down first
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}},
+		relabeledClusterDeploymentHandler{
+			Client:      mgr.GetClient(),
+			offboarding: offboarding,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch for ClusterDeployments whose managed label turns true, and
+	// record them so the PagerDutyIntegrations selecting them set them up
+	// ahead of the rest of the fleet
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: onboardingClusterDeploymentToPagerDutyIntegrationsMapper{
+				clusterDeploymentToPagerDutyIntegrationsMapper: clusterDeploymentToPagerDutyIntegrationsMapper{
+					Client: mgr.GetClient(),
+				},
+				onboarding: onboarding,
+			},
+		},
+		managedLabelTurnedTrue,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to PagerDutySilences, and queue a request for all
+	// PagerDutyIntegration CR that select the silenced ClusterDeployment.
+	err = c.Watch(&source.Kind{Type: &pagerdutyv1alpha1.PagerDutySilence{}},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: silenceToPagerDutyIntegrationsMapper{
+				Client: mgr.GetClient(),
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to PagerDutyClusterConfigs, and queue a request for
+	// the PagerDutyIntegration CR whose settings they override.
+	err = c.Watch(&source.Kind{Type: &pagerdutyv1alpha1.PagerDutyClusterConfig{}},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: clusterConfigToPagerDutyIntegrationsMapper{
+				Client: mgr.GetClient(),
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the tenant label of Namespaces, and queue a
+	// request for the PagerDutyIntegration CRs of the tenants it names.
+	err = c.Watch(&source.Kind{Type: &corev1.Namespace{}},
+		tenantNamespaceHandler{
+			Client: mgr.GetClient(),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to PagerDutyIntegrationTemplates, and queue a
+	// request for all PagerDutyIntegration CR that refer to it.
+	err = c.Watch(&source.Kind{Type: &pagerdutyv1alpha1.PagerDutyIntegrationTemplate{}},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: templateToPagerDutyIntegrationsMapper{
+				Client: mgr.GetClient(),
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to Secrets holding a PagerDuty API key, and queue a
+	// request for all PagerDutyIntegration CR that refer to it, so a
+	// rotated key is used right away.
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: apiKeySecretToPagerDutyIntegrationsMapper{
+				Client: mgr.GetClient(),
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to ClusterSyncs, where Hive reports the result of
+	// applying SyncSets, and queue a request for all PagerDutyIntegration CR
+	// that select the ClusterDeployment of the same name.
+	err = c.Watch(&source.Kind{Type: &hiveintv1alpha1.ClusterSync{}},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: clusterSyncToPagerDutyIntegrationsMapper{
+				Client: mgr.GetClient(),
+			},
+		},
 	)
 	if err != nil {
 		return err
@@ -146,7 +280,61 @@ type ReconcilePagerDutyIntegration struct {
 	client    client.Client
 	scheme    *runtime.Scheme
 	reqLogger logr.Logger
-	pdclient  func(APIKey string, controllerName string) pd.Client
+	pdclient  func(APIKey string, controllerName string, opts ...pd.ClientOption) pd.Client
+	recorder  record.EventRecorder
+
+	// remoteClient builds a client for a target cluster from its kubeconfig
+	remoteClient func(kubeconfig []byte) (client.Client, error)
+	// clusters sets up, tears down and verifies each cluster, the
+	// operator's defaultClusterHandler if nil
+	clusters clusterHandler
+	// clock returns the current time, time.Now if nil
+	clock func() time.Time
+	// reader reads the heartbeat Leases and the Namespaces, uncached so
+	// those of the hub aren't all watched, client if nil
+	reader client.Reader
+	// onboarding records the clusters that recently became managed, set up
+	// ahead of the rest of the fleet, none if nil
+	onboarding *onboardingClusters
+	// offboarding records the clusters that recently stopped being selected
+	// by a PDI, torn down ahead of the rest of the fleet, none if nil
+	offboarding *onboardingClusters
+	// maxConcurrentClusterSyncs is how many clusters are set up at once,
+	// one at a time if 1 or less
+	maxConcurrentClusterSyncs int
+	// resyncPeriod is how often the PagerDuty service of each cluster is
+	// verified unless the PagerDutyIntegration says otherwise,
+	// config.ResyncPeriod if 0
+	resyncPeriod time.Duration
+	// errorRequeueDelay is how long to wait after a transient PagerDuty
+	// error unless the PagerDutyIntegration says otherwise, the backoff of
+	// the controller if 0
+	errorRequeueDelay time.Duration
+	// mu guards the PagerDutyIntegration and the state of the current
+	// reconcile while workers set clusters up, nil when there are none
+	mu *sync.Mutex
+	// escrowKeys collects the integration keys to escrow, by escrowKey,
+	// while workers set clusters up, so the escrow secret is written once
+	// they are done. nil when there are no workers
+	escrowKeys map[string]string
+
+	// retryReasons records, for the current reconcile, why the setup of
+	// clusters was skipped or will be retried, keyed by namespace/name
+	retryReasons map[string]pagerdutyv1alpha1.RetryReason
+	// retryErrors holds the error behind each of retryReasons, if any
+	retryErrors map[string]string
+	// operations counts the per-cluster operations of the current
+	// reconcile, for the error budget
+	operations operationCounts
+	// template holds, for the current reconcile, the settings the
+	// PagerDutyIntegration inherits from its template, if any
+	template *pagerdutyv1alpha1.PagerDutyIntegrationTemplateSpec
+	// dryRun is set while the current reconcile only logs its changes,
+	// see startDryRun
+	dryRun bool
+	// requeueDelay is how long the current reconcile waits after a
+	// transient PagerDuty error, see errorRequeueDelayOf
+	requeueDelay time.Duration
 }
 
 // Reconcile reads that state of the cluster for a PagerDutyIntegration object and makes changes based on the state read
@@ -157,8 +345,12 @@ type ReconcilePagerDutyIntegration struct {
 func (r *ReconcilePagerDutyIntegration) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	start := time.Now()
 
-	r.reqLogger = log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	r.reqLogger = logging.ForIntegration(log, request.NamespacedName)
 	r.reqLogger.Info("Reconciling PagerDutyIntegration")
+	r.retryReasons = map[string]pagerdutyv1alpha1.RetryReason{}
+	r.retryErrors = map[string]string{}
+	r.operations = operationCounts{}
+	r.requeueDelay = r.errorRequeueDelay
 
 	defer func() {
 		dur := time.Since(start)
@@ -180,6 +372,48 @@ func (r *ReconcilePagerDutyIntegration) Reconcile(request reconcile.Request) (re
 		return r.requeueOnErr(err)
 	}
 
+	r.requeueDelay = r.errorRequeueDelayOf(pdi)
+
+	// log the changes instead of making them
+	if dryrun.Enabled(pdi) {
+		defer r.startDryRun()()
+	}
+
+	// load the settings inherited from the template, a PDI being deleted is
+	// cleaned up without them if the template is gone
+	err = r.loadTemplate(pdi)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return r.requeueOnErr(err)
+		}
+		if pdi.DeletionTimestamp == nil {
+			r.reqLogger.Error(err, "Failed to load PagerDutyIntegrationTemplate listed in PagerDutyIntegration CR")
+			return r.requeueAfter(10 * time.Minute)
+		}
+	}
+
+	// a tenant reaching outside of its namespace is refused before any of its
+	// secrets is read, a change of the PDI or its template is watched
+	tenancyValid, err := r.validateTenancy(pdi)
+	if err != nil {
+		return r.requeueOnErr(err)
+	}
+	if !tenancyValid {
+		if pdi.DeletionTimestamp != nil && utils.HasFinalizer(pdi, config.PagerDutyIntegrationFinalizer) {
+			// nothing can be torn down with the secrets the PDI is refused,
+			// which must not keep it from being deleted. Its metrics are
+			// left alone, they may be those of the PDI whose name it took
+			r.reqLogger.Info("Removing the finalizer of a PagerDutyIntegration refused for its tenancy, nothing is torn down")
+			utils.DeleteFinalizer(pdi, config.PagerDutyIntegrationFinalizer)
+			err = r.client.Update(context.TODO(), pdi)
+			if err != nil {
+				return r.requeueOnErr(err)
+			}
+			return r.doNotRequeue()
+		}
+		return r.requeueAfter(10 * time.Minute)
+	}
+
 	// fetch all CDs so we can inspect if they're dropped out of the matching CD list
 	allClusterDeployments, err := r.getAllClusterDeployments()
 	if err != nil {
@@ -205,29 +439,44 @@ func (r *ReconcilePagerDutyIntegration) Reconcile(request reconcile.Request) (re
 	if err != nil {
 		r.reqLogger.Error(err, "Failed to load PagerDuty API key from Secret listed in PagerDutyIntegration CR")
 		localmetrics.UpdateMetricPagerDutyIntegrationSecretLoaded(0, pdi.Name)
+		r.saveAPIKeyInvalid(pdi, "SecretNotLoaded", err.Error())
+		// a change of the secret is watched, this only covers missed events
 		return r.requeueAfter(10 * time.Minute)
 	}
 	localmetrics.UpdateMetricPagerDutyIntegrationSecretLoaded(1, pdi.Name)
-	pdClient := r.pdclient(pdApiKey, controllerName)
+	pdClient := r.pdclient(pdApiKey, controllerName, pd.WithAPIEndpoint(pdi.Spec.APIEndpoint))
 
 	// check if PDI is being deleted, if so we cleanup all CD w/ matching finalizers
 	if pdi.DeletionTimestamp != nil {
 		if utils.HasFinalizer(pdi, config.PagerDutyIntegrationFinalizer) {
 			// review _all_ CD, cleanup anything w/ this PDI finalizer
 
-			// do the CD cleanup
-			for _, clusterdeployment := range allClusterDeployments.Items {
-				if utils.HasFinalizer(&clusterdeployment, clusterDeploymentFinalizerName) {
-					err = r.handleDelete(pdClient, pdi, &clusterdeployment)
-					if err != nil {
-						return reconcile.Result{}, err
-					}
-				}
+			// the cleanup uses the inherited settings, which must not be
+			// written back when the finalizer is removed below
+			inherited := pdi.DeepCopy()
+			r.inheritTemplate(inherited)
+
+			// tear down everything the PDI set up, across all CDs
+			err = r.cleanupIntegration(pdClient, inherited, allClusterDeployments.Items)
+			if err != nil {
+				return r.requeueOnErr(err)
 			}
 
 			localmetrics.DeleteMetricPagerDutyIntegrationSecretLoaded(pdi.Name)
-
-			// do the PDI cleanup
+			localmetrics.DeleteMetricPagerDutyIntegrationAccountQuotaExceeded(pdi.Name)
+			deleteRetryReasonMetrics(pdi)
+			localmetrics.DeleteMetricPagerDutyIntegrationAnomalousClusters(pdi.Name)
+			localmetrics.DeleteMetricPagerDutyIntegrationServices(pdi.Name)
+			localmetrics.DeleteMetricPagerDutyIntegrationFailedClusters(pdi.Name)
+			localmetrics.DeleteMetricPagerDutyIntegrationSecretSyncFailedClusters(pdi.Name)
+			localmetrics.DeleteMetricPagerDutyIntegrationOrphanedServices(pdi.Name)
+			localmetrics.DeleteMetricPagerDutyIntegrationLeakedServices(pdi.Name)
+			localmetrics.DeleteMetricPagerDutyIntegrationErrorBudget(pdi.Name)
+			localmetrics.DeleteMetricPagerDutyClusterServiceInfo("", pdi.Name)
+
+			// do the PDI cleanup, the status may have been updated through
+			// the copy
+			pdi.ResourceVersion = inherited.ResourceVersion
 			utils.DeleteFinalizer(pdi, config.PagerDutyIntegrationFinalizer)
 			err = r.client.Update(context.TODO(), pdi)
 			if err != nil {
@@ -246,48 +495,231 @@ func (r *ReconcilePagerDutyIntegration) Reconcile(request reconcile.Request) (re
 		}
 	}
 
-	// review all CD and see if PD service needs added or removed
-	for _, cd := range allClusterDeployments.Items {
-		if utils.HasFinalizer(&cd, clusterDeploymentFinalizerName) {
-			if cd.DeletionTimestamp != nil {
-				// it has a finalizer and is being deleted.  clean up PD things!
-				err := r.handleDelete(pdClient, pdi, &cd)
-				if err != nil {
-					return r.requeueOnErr(err)
-				}
-			} else {
-				// it has a finalizer and is NOT being deleted.
-				// check if it should have PD setup or not (did it drop out of the PDI?)
-				cdIsMatching := false
-				for _, mcd := range matchingClusterDeployments.Items {
-					if cd.Namespace == mcd.Namespace && cd.Name == mcd.Name {
-						cdIsMatching = true
-						break
-					}
-				}
-
-				if !cdIsMatching {
-					// the CD has a finalizer but is NOT matching the PDI. clean it up.
-					err := r.handleDelete(pdClient, pdi, &cd)
-					if err != nil {
-						return r.requeueOnErr(err)
-					}
-				}
-			}
+	// conditions set while handling clusters are persisted at the end
+	previousConditions := pdi.Status.DeepCopy().Conditions
+
+	// the account's service limit was raised, resume creating services. The
+	// annotation is removed before the template is inherited, which the
+	// update would write into the spec otherwise
+	if _, ok := pdi.Annotations[config.PagerDutyIntegrationClearQuotaAnnotation]; ok {
+		delete(pdi.Annotations, config.PagerDutyIntegrationClearQuotaAnnotation)
+		err := r.client.Update(context.TODO(), pdi)
+		if err != nil {
+			return r.requeueOnErr(err)
+		}
+		if accountQuotaExceeded(pdi) {
+			r.reqLogger.Info("Clearing AccountQuotaExceeded condition on request")
+			setAccountQuotaExceeded(pdi, false, "ManuallyCleared", "Cleared with the "+config.PagerDutyIntegrationClearQuotaAnnotation+" annotation")
 		}
 	}
 
-	// and finally, any Matching CD not being deleted goes through handleCreate, which will do the needful
-	for _, cd := range matchingClusterDeployments.Items {
-		if cd.DeletionTimestamp == nil {
-			err := r.handleCreate(pdClient, pdi, &cd)
-			if err != nil {
-				return r.requeueOnErr(err)
-			}
+	// the leaked service was deleted by hand, the annotation is likewise
+	// removed before the template is inherited
+	if _, ok := pdi.Annotations[config.PagerDutyIntegrationClearLeakedServiceAnnotation]; ok {
+		delete(pdi.Annotations, config.PagerDutyIntegrationClearLeakedServiceAnnotation)
+		err := r.client.Update(context.TODO(), pdi)
+		if err != nil {
+			return r.requeueOnErr(err)
+		}
+		if leakedService(pdi) != nil {
+			r.reqLogger.Info("Clearing LeakedService condition on request")
+			setLeakedService(pdi, false, "ManuallyCleared", "Cleared with the "+config.PagerDutyIntegrationClearLeakedServiceAnnotation+" annotation")
+		}
+	}
+
+	// from here on the PDI is only written through its status, which leaves
+	// the inherited settings out of the spec
+	r.inheritTemplate(pdi)
+
+	// a changed servicePrefix renames what the clusters were set up with
+	// before anything is looked up under the new one
+	servicePrefix, renamePending, err := r.renameServicePrefix(pdClient, pdi, allClusterDeployments.Items)
+	if err != nil {
+		return r.requeueOnErr(err)
+	}
+
+	// make sure everything the PDI refers to exists before setting up any cluster
+	referencesValid := r.validateReferences(pdClient, pdi)
+
+	// a shared integration key is delivered to the whole fleet at once, so
+	// no cluster is set up on its own and those that were are torn down
+	err = r.reconcileSelectorSyncSet(pdi)
+	if err != nil {
+		return r.requeueOnErr(err)
+	}
+	if pdi.Spec.SharedIntegrationKey != nil {
+		matchingClusterDeployments.Items = nil
+	}
+
+	// clusters that just became managed are paged for before anything else
+	r.onboardClusters(pdClient, pdi, clusterDeploymentFinalizerName, matchingClusterDeployments.Items, referencesValid)
+
+	// and clusters that just stopped being selected stop paging as early
+	r.offboardClusters(pdClient, pdi, clusterDeploymentFinalizerName, allClusterDeployments.Items, matchingClusterDeployments.Items)
+
+	// re-enable alerting for clusters muted for too long, then report
+	// which of the selected clusters are still intentionally muted
+	staleSilences, nextStaleCheck, err := r.liftStaleSilences(pdi, matchingClusterDeployments.Items)
+	if err != nil {
+		return r.requeueOnErr(err)
+	}
+	silences, err := r.activeSilences(pdi, matchingClusterDeployments.Items)
+	if err != nil {
+		return r.requeueOnErr(err)
+	}
+	if !equality.Semantic.DeepEqual(pdi.Status.ActiveSilences, silences) ||
+		!equality.Semantic.DeepEqual(pdi.Status.StaleSilences, staleSilences) {
+		pdi.Status.ActiveSilences = silences
+		pdi.Status.StaleSilences = staleSilences
+		err = r.updateStatus(pdi)
+		if err != nil {
+			return r.requeueOnErr(err)
+		}
+	}
+
+	// review all CD and see if PD service needs removed
+	err = r.deleteClusters(pdClient, pdi, clusterDeploymentFinalizerName, allClusterDeployments.Items, matchingClusterDeployments.Items)
+	if err != nil {
+		return r.requeueOnOperationErr(pdi, err)
+	}
+
+	// delete the services of deleted clusters that were not reinstalled in time
+	nextRetainedExpiry, err := r.expireRetainedServices(pdClient, pdi)
+	if err != nil {
+		return r.requeueOnErr(err)
+	}
+
+	// and finally, any Matching CD not being deleted goes through handleCreate, which will do the needful.
+	createErr := r.createClusters(pdClient, pdi, matchingClusterDeployments.Items, referencesValid)
+
+	// the additional services are set up and torn down next to the PDI's own
+	err = r.reconcileAdditionalServices(pdClient, pdi, allClusterDeployments.Items, referencesValid)
+	if err != nil && createErr == nil {
+		createErr = err
+	}
+
+	// take the decommission of the account being migrated from one batch further
+	migrationStatus, nextDecommissionBatch, err := r.decommissionServices(pdClient, pdi, matchingClusterDeployments.Items)
+	if err != nil {
+		return r.requeueOnErr(err)
+	}
+
+	// report the state of each selected cluster, verifying those whose slot has come
+	clusters, next, err := r.clusterStatuses(pdClient, pdi, matchingClusterDeployments.Items)
+	if err != nil {
+		return r.requeueOnOperationErr(pdi, err)
+	}
+
+	// report changes made to the services outside of the operator
+	lastAuditPoll, nextAuditPoll, err := r.auditServices(pdClient, pdi, matchingClusterDeployments.Items)
+	if err != nil {
+		return r.requeueOnErr(err)
+	}
+
+	// flag the clusters getting far more incidents than the fleet
+	lastAlertVolume, nextAlertVolume, err := r.flagAlertVolumeAnomalies(pdClient, pdi, clusters)
+	if err != nil {
+		return r.requeueOnErr(err)
+	}
+
+	// suggest how to tune the services from their incidents
+	lastServiceTuning, nextServiceTuning, err := r.suggestServiceTuning(pdClient, pdi, clusters)
+	if err != nil {
+		return r.requeueOnErr(err)
+	}
+
+	// report or delete the services whose cluster no longer exists
+	orphans, lastOrphanSweep, nextOrphanSweep, err := r.sweepOrphanedServices(pdClient, pdi, allClusterDeployments.Items)
+	if err != nil {
+		return r.requeueOnErr(err)
+	}
+	clearLeakedServiceAfterSweep(pdi, lastOrphanSweep)
+
+	errorBudget := r.errorBudgetStatus(pdi)
+
+	// report which clusters are paged for, muted or failing
+	lastCoverageReport, nextCoverageReport, err := r.reportCoverage(pdClient, pdi, clusters)
+	if err != nil {
+		return r.requeueOnErr(err)
+	}
+
+	if !equality.Semantic.DeepEqual(pdi.Status.Clusters, clusters) ||
+		!equality.Semantic.DeepEqual(pdi.Status.Conditions, previousConditions) ||
+		!equality.Semantic.DeepEqual(pdi.Status.LastAuditPollTime, lastAuditPoll) ||
+		!equality.Semantic.DeepEqual(pdi.Status.LastAlertVolumeTime, lastAlertVolume) ||
+		!equality.Semantic.DeepEqual(pdi.Status.LastServiceTuningTime, lastServiceTuning) ||
+		!equality.Semantic.DeepEqual(pdi.Status.AccountMigration, migrationStatus) ||
+		!equality.Semantic.DeepEqual(pdi.Status.OrphanedServices, orphans) ||
+		!equality.Semantic.DeepEqual(pdi.Status.LastOrphanedServiceSweepTime, lastOrphanSweep) ||
+		!equality.Semantic.DeepEqual(pdi.Status.ErrorBudget, errorBudget) ||
+		!equality.Semantic.DeepEqual(pdi.Status.LastCoverageReportTime, lastCoverageReport) ||
+		pdi.Status.ServicePrefix != servicePrefix {
+		pdi.Status.Clusters = clusters
+		pdi.Status.ReadyClusters, pdi.Status.PendingClusters, pdi.Status.FailedClusters = countClusterStates(clusters)
+		pdi.Status.AnomalousClusters = countAnomalousClusters(clusters)
+		pdi.Status.SecretSyncFailedClusters = countSecretSyncFailures(clusters)
+		pdi.Status.LastAuditPollTime = lastAuditPoll
+		pdi.Status.LastAlertVolumeTime = lastAlertVolume
+		pdi.Status.LastServiceTuningTime = lastServiceTuning
+		pdi.Status.AccountMigration = migrationStatus
+		pdi.Status.OrphanedServices = orphans
+		pdi.Status.LastOrphanedServiceSweepTime = lastOrphanSweep
+		pdi.Status.ErrorBudget = errorBudget
+		pdi.Status.LastCoverageReportTime = lastCoverageReport
+		pdi.Status.ServicePrefix = servicePrefix
+		err = r.updateStatus(pdi)
+		if err != nil {
+			return r.requeueOnErr(err)
 		}
 	}
 
-	return r.doNotRequeue()
+	r.updateRetryReasonMetrics(pdi, clusters)
+	_, _, failed := countClusterStates(clusters)
+	localmetrics.UpdateMetricPagerDutyIntegrationFailedClusters(failed, pdi.Name)
+	localmetrics.UpdateMetricPagerDutyIntegrationSecretSyncFailedClusters(countSecretSyncFailures(clusters), pdi.Name)
+	localmetrics.UpdateMetricPagerDutyIntegrationServices(countServices(clusters), pdi.Name)
+	if pdi.Spec.AlertVolumeAnomaly != nil {
+		localmetrics.UpdateMetricPagerDutyIntegrationAnomalousClusters(countAnomalousClusters(clusters), pdi.Name)
+	} else {
+		localmetrics.DeleteMetricPagerDutyIntegrationAnomalousClusters(pdi.Name)
+	}
+	if pdi.Spec.OrphanedServiceSweep != nil {
+		localmetrics.UpdateMetricPagerDutyIntegrationOrphanedServices(len(orphans), pdi.Name)
+	} else {
+		localmetrics.DeleteMetricPagerDutyIntegrationOrphanedServices(pdi.Name)
+	}
+	if createErr != nil {
+		return r.requeueOnErr(createErr)
+	}
+
+	if nextStaleCheck > 0 && nextStaleCheck < next {
+		next = nextStaleCheck
+	}
+	if nextAuditPoll > 0 && nextAuditPoll < next {
+		next = nextAuditPoll
+	}
+	if nextRetainedExpiry > 0 && nextRetainedExpiry < next {
+		next = nextRetainedExpiry
+	}
+	if nextDecommissionBatch > 0 && nextDecommissionBatch < next {
+		next = nextDecommissionBatch
+	}
+	if nextAlertVolume > 0 && nextAlertVolume < next {
+		next = nextAlertVolume
+	}
+	if nextServiceTuning > 0 && nextServiceTuning < next {
+		next = nextServiceTuning
+	}
+	if nextOrphanSweep > 0 && nextOrphanSweep < next {
+		next = nextOrphanSweep
+	}
+	if nextCoverageReport > 0 && nextCoverageReport < next {
+		next = nextCoverageReport
+	}
+	if renamePending && servicePrefixRenameRetryInterval < next {
+		next = servicePrefixRenameRetryInterval
+	}
+	return r.requeueAfter(next)
 }
 
 func (r *ReconcilePagerDutyIntegration) getAllClusterDeployments() (*hivev1.ClusterDeploymentList, error) {
@@ -304,13 +736,48 @@ func (r *ReconcilePagerDutyIntegration) getMatchingClusterDeployments(pdi *pager
 	matchingClusterDeployments := &hivev1.ClusterDeploymentList{}
 	listOpts := &client.ListOptions{LabelSelector: selector}
 	err = r.client.List(context.TODO(), matchingClusterDeployments, listOpts)
+	if err != nil {
+		return matchingClusterDeployments, err
+	}
+
+	// and those that opted in with their annotation
+	if pdi.Spec.SelfServiceOnboarding != nil {
+		optedIn, err := r.optedInClusterDeployments(pdi, matchingClusterDeployments.Items)
+		matchingClusterDeployments.Items = append(matchingClusterDeployments.Items, optedIn...)
+		if err != nil {
+			return matchingClusterDeployments, err
+		}
+	}
+
+	// a tenant only sets up those of the namespaces it owns
+	matchingClusterDeployments.Items, err = r.tenantClusterDeployments(pdi, matchingClusterDeployments.Items)
 	return matchingClusterDeployments, err
 }
+
+// scopeLogger makes logger the request logger until the returned function
+// restores the previous one
+func (r *ReconcilePagerDutyIntegration) scopeLogger(logger logr.Logger) func() {
+	previous := r.reqLogger
+	r.reqLogger = logger
+	return func() {
+		r.reqLogger = previous
+	}
+}
+
 func (r *ReconcilePagerDutyIntegration) doNotRequeue() (reconcile.Result, error) {
 	return reconcile.Result{}, nil
 }
 
 func (r *ReconcilePagerDutyIntegration) requeueOnErr(err error) (reconcile.Result, error) {
+	if pd.IsRateLimited(err) {
+		// requeueing right away would only be rate limited again
+		r.reqLogger.Error(err, "PagerDuty API rate limited, requeueing later")
+		return r.requeueAfter(pd.RateLimitRetryAfter(err))
+	}
+	if r.requeueDelay > 0 && pd.IsTransient(err) {
+		r.reqLogger.Error(err, "PagerDuty API call failed, requeueing later", "RequeueAfter", r.requeueDelay)
+		return r.requeueAfter(r.requeueDelay)
+	}
 	return reconcile.Result{}, err
 }
 
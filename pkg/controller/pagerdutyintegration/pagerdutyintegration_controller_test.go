@@ -16,26 +16,38 @@ package pagerdutyintegration
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	pdApi "github.com/PagerDuty/go-pagerduty"
 	"github.com/golang/mock/gomock"
 	hiveapis "github.com/openshift/hive/pkg/apis"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hiveintv1alpha1 "github.com/openshift/hive/pkg/apis/hiveinternal/v1alpha1"
 	"github.com/openshift/pagerduty-operator/config"
 	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
 	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/heartbeat"
 	"github.com/openshift/pagerduty-operator/pkg/kube"
+	"github.com/openshift/pagerduty-operator/pkg/localmetrics"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
 	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
 	mockpd "github.com/openshift/pagerduty-operator/pkg/pagerduty/mock"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -84,10 +96,30 @@ func setupDefaultMocks(t *testing.T, localObjects []runtime.Object) *mocks {
 	}
 
 	mocks.mockPDClient = mockpd.NewMockClient(mocks.mockCtrl)
+	// the referenced PagerDuty resources exist unless a test says otherwise
+	mocks.mockPDClient.EXPECT().ValidateReferences(gomock.Any()).Return(nil, nil).AnyTimes()
+	// and no cluster has a service yet
+	mocks.mockPDClient.EXPECT().FindServiceByName(gomock.Any()).Return(nil, nil).AnyTimes()
+	// and the integrations of existing services are in place
+	mocks.mockPDClient.EXPECT().RecreateMissingIntegration(gomock.Any()).Return(false, nil).AnyTimes()
 
 	return mocks
 }
 
+// eventsWithReason drains the events recorded so far and returns those of
+// the given reason, so a test isn't thrown off by the lifecycle events of
+// the clusters
+func eventsWithReason(recorder *record.FakeRecorder, reason string) []string {
+	var events []string
+	for len(recorder.Events) > 0 {
+		// the FakeRecorder formats events "<type> <reason> <message>"
+		if event := <-recorder.Events; strings.Fields(event)[1] == reason {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
 // testPDISecret creates a fake secret containing pagerduty config details to use for testing.
 func testPDISecret() *corev1.Secret {
 	s := &corev1.Secret{
@@ -107,7 +139,7 @@ func testCDConfigMap() *corev1.ConfigMap {
 	cm := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: testNamespace,
-			Name:      config.Name(testServicePrefix, testClusterName, config.ConfigMapSuffix),
+			Name:      naming.ConfigMapName(testServicePrefix, testClusterName),
 		},
 		Data: map[string]string{
 			"INTEGRATION_ID": testIntegrationID,
@@ -117,6 +149,31 @@ func testCDConfigMap() *corev1.ConfigMap {
 	return cm
 }
 
+// testPDService returns the PagerDuty service of a deployed cluster, with the
+// settings of testPagerDutyIntegration, to use in testing.
+func testPDService() *pdApi.Service {
+	resolveTimeout := uint(testResolveTimeout)
+	acknowledgeTimeout := uint(testAcknowledgeTimeout)
+	return &pdApi.Service{
+		APIObject: pdApi.APIObject{
+			ID: testServiceID,
+		},
+		Description: testClusterName + " - A managed hive created cluster",
+		EscalationPolicy: pdApi.EscalationPolicy{
+			APIObject: pdApi.APIObject{
+				ID: testEscalationPolicy,
+			},
+		},
+		AutoResolveTimeout:     &resolveTimeout,
+		AcknowledgementTimeout: &acknowledgeTimeout,
+		AlertCreation:          "create_alerts_and_incidents",
+		IncidentUrgencyRule: &pdApi.IncidentUrgencyRule{
+			Type:    "constant",
+			Urgency: "severity_based",
+		},
+	}
+}
+
 // testCDSecret returns a Secret that will go in the SyncSet for a deployed cluster to use in testing.
 func testCDSecret() *corev1.Secret {
 	s := &corev1.Secret{
@@ -133,10 +190,10 @@ func testCDSecret() *corev1.Secret {
 
 // testCDSyncSet returns a SyncSet for an existing testClusterDeployment to use in testing.
 func testCDSyncSet() *hivev1.SyncSet {
-	secretName := config.Name(testServicePrefix, testClusterName, config.SecretSuffix)
-	secret := kube.GeneratePdSecret(testNamespace, secretName, testIntegrationID)
+	secretName := naming.SecretName(testServicePrefix, testClusterName)
 	pdi := testPagerDutyIntegration()
-	ss := kube.GenerateSyncSet(testNamespace, testClusterName, secret, pdi)
+	secret := kube.GeneratePdSecret(testNamespace, secretName, testIntegrationID, pdi)
+	ss := kube.GenerateSyncSet(testNamespace, naming.SyncSetName(testServicePrefix, testClusterName), testClusterName, secret, pdi)
 	return ss
 }
 
@@ -159,7 +216,7 @@ func testPagerDutyIntegration() *pagerdutyv1alpha1.PagerDutyIntegration {
 				Namespace: config.OperatorNamespace,
 			},
 			TargetSecretRef: corev1.SecretReference{
-				Name:      config.Name(testServicePrefix, testClusterName, config.SecretSuffix),
+				Name:      naming.SecretName(testServicePrefix, testClusterName),
 				Namespace: testNamespace,
 			},
 		},
@@ -199,7 +256,7 @@ func TestReconcilePagerDutyIntegration(t *testing.T) {
 
 	// expectedSyncSet is used by tests that _expect_ a SS
 	expectedSyncSet := &SyncSetEntry{
-		name:                     config.Name(testServicePrefix, testClusterName, config.SecretSuffix),
+		name:                     naming.SecretName(testServicePrefix, testClusterName),
 		clusterDeploymentRefName: testClusterName,
 		targetSecret: hivev1.SecretReference{
 			Name:      testPagerDutyIntegration().Spec.TargetSecretRef.Name,
@@ -209,7 +266,7 @@ func TestReconcilePagerDutyIntegration(t *testing.T) {
 
 	// expectedSecret is used by test that _expect_ a Secret
 	expectedSecret := &SecretEntry{
-		name:         config.Name(testServicePrefix, testClusterName, config.SecretSuffix),
+		name:         naming.SecretName(testServicePrefix, testClusterName),
 		pagerdutyKey: testIntegrationID,
 	}
 
@@ -225,6 +282,8 @@ func TestReconcilePagerDutyIntegration(t *testing.T) {
 		expectPDSetup           bool
 		verifyClusterDeployment func(client.Client, *ClusterDeploymentEntry) bool
 		setupPDMock             func(*mockpd.MockClientMockRecorder)
+		// on the ClusterDeployment and the PDI
+		expectServiceDeletedEvents int
 	}{
 		{
 			name: "Test Not Installed",
@@ -364,6 +423,7 @@ func TestReconcilePagerDutyIntegration(t *testing.T) {
 				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(0)
 				r.DeleteService(gomock.Any()).Return(nil).Times(1)
 			},
+			expectServiceDeletedEvents: 2,
 		},
 		{
 			name: "Test Managed, No Finalizer, Deleting, PD Not Setup",
@@ -423,6 +483,7 @@ func TestReconcilePagerDutyIntegration(t *testing.T) {
 				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(0)
 				r.DeleteService(gomock.Any()).Return(nil).Times(1)
 			},
+			expectServiceDeletedEvents: 2,
 		},
 		{
 			name: "Test Not Managed, Finalizer, Not Deleting, PD Setup",
@@ -440,6 +501,7 @@ func TestReconcilePagerDutyIntegration(t *testing.T) {
 				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(0)
 				r.DeleteService(gomock.Any()).Return(nil).Times(1)
 			},
+			expectServiceDeletedEvents: 2,
 		},
 		{
 			name: "Test Not Managed, Finalizer, Deleting, PD Not Setup",
@@ -471,6 +533,7 @@ func TestReconcilePagerDutyIntegration(t *testing.T) {
 				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(0)
 				r.DeleteService(gomock.Any()).Return(nil).Times(1)
 			},
+			expectServiceDeletedEvents: 2,
 		},
 		{
 			name: "Test Not Managed, No Finalizer, Deleting, PD Not Setup",
@@ -496,10 +559,12 @@ func TestReconcilePagerDutyIntegration(t *testing.T) {
 
 			defer mocks.mockCtrl.Finish()
 
+			recorder := record.NewFakeRecorder(10)
 			rpdi := &ReconcilePagerDutyIntegration{
 				client:   mocks.fakeKubeClient,
 				scheme:   scheme.Scheme,
-				pdclient: func(s1 string, s2 string) pd.Client { return mocks.mockPDClient },
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+				recorder: recorder,
 			}
 
 			// 1st run sets finalizer
@@ -543,193 +608,4596 @@ func TestReconcilePagerDutyIntegration(t *testing.T) {
 				assert.True(t, verifyNoFinalizer(mocks.fakeKubeClient, expectedClusterDeployment), "verifyNoFinalizer: "+test.name)
 				assert.True(t, verifyNoConfigMapExists(mocks.fakeKubeClient), "verifyNoConfigMapExists: "+test.name)
 			}
+			assert.Len(t, eventsWithReason(recorder, eventPDServiceDeleted), test.expectServiceDeletedEvents)
 		})
 	}
 }
 
-// verifySyncSetExists verifies that a SyncSet exists that matches the supplied expected SyncSetEntry.
-func verifySyncSetExists(c client.Client, expected *SyncSetEntry) bool {
-	ss := hivev1.SyncSet{}
-	err := c.Get(context.TODO(),
-		types.NamespacedName{Name: expected.name, Namespace: testNamespace},
-		&ss)
-	if err != nil {
-		return false
-	}
+func TestReconcilePagerDutyIntegrationSecretDeliveryMode(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
 
-	if expected.name != ss.Name {
-		return false
+	tests := []struct {
+		name         string
+		localObjects []runtime.Object
+		setupPDMock  func(*mockpd.MockClientMockRecorder)
+	}{
+		{
+			name:         "Test Patch Mode, PD Not Setup",
+			localObjects: []runtime.Object{},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+		},
+		{
+			name: "Test Patch Mode, PD Setup In Secret Mode",
+			localObjects: []runtime.Object{
+				testCDConfigMap(),
+				testCDSyncSet(),
+				testCDSecret(),
+			},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Times(0)
+				r.GetIntegrationKey(gomock.Any()).Times(0)
+			},
+		},
 	}
 
-	if expected.clusterDeploymentRefName != ss.Spec.ClusterDeploymentRefs[0].Name {
-		return false
-	}
-	secretReferences := ss.Spec.SyncSetCommonSpec.Secrets[0].SourceRef.Name
-	if secretReferences == "" {
-		return false
-	}
-	return string(secretReferences) == expected.name
-}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.SecretDeliveryMode = pagerdutyv1alpha1.SecretDeliveryModePatch
+			localObjects := append(test.localObjects, testClusterDeployment(true, true, true, false), testPDISecret(), pdi)
 
-// verifyNoSyncSetExists verifies that there is no SyncSet present that matches the supplied expected SyncSetEntry.
-func verifyNoSyncSetExists(c client.Client) bool {
-	ssList := &hivev1.SyncSetList{}
-	opts := client.ListOptions{Namespace: testNamespace}
-	err := c.List(context.TODO(), ssList, &opts)
+			mocks := setupDefaultMocks(t, localObjects)
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
 
-	if err != nil {
-		if errors.IsNotFound(err) {
-			// no syncsets are defined, this is OK
-			return true
-		}
-	}
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
 
-	for _, ss := range ssList.Items {
-		if ss.Name != testClusterName+testOtherSyncSetPostfix {
-			// too bad, found a syncset associated with this operator
-			return false
-		}
-	}
+			// Act
+			_, err := rpdi.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			})
 
-	// if we got here, it's good.  list was empty or everything passed
-	return true
-}
+			// Assert
+			assert.NoError(t, err)
 
-// verifyFinalizer verifies that there is no PD finalizer on the CD.
-func verifyFinalizer(c client.Client, expected *ClusterDeploymentEntry) bool {
-	cd := hivev1.ClusterDeployment{}
-	err := c.Get(context.TODO(),
-		types.NamespacedName{Name: expected.name, Namespace: testNamespace}, &cd)
-	if err != nil {
-		return false
+			ss := &hivev1.SyncSet{}
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: naming.SyncSetName(testServicePrefix, testClusterName), Namespace: testNamespace}, ss)
+			assert.NoError(t, err)
+			assert.Empty(t, ss.Spec.Secrets)
+			assert.Len(t, ss.Spec.Patches, 1)
+			assert.Equal(t, "Secret", ss.Spec.Patches[0].Kind)
+			assert.Equal(t, pdi.Spec.TargetSecretRef.Name, ss.Spec.Patches[0].Name)
+			assert.Equal(t, pdi.Spec.TargetSecretRef.Namespace, ss.Spec.Patches[0].Namespace)
+			assert.Equal(t, "merge", ss.Spec.Patches[0].PatchType)
+			assert.Contains(t, ss.Spec.Patches[0].Patch, config.PagerDutySecretKey)
+		})
 	}
+}
 
-	if expected.name != cd.Name {
-		return false
+func TestReconcilePagerDutyIntegrationSecretOptions(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name            string
+		localObjects    []runtime.Object
+		secretType      corev1.SecretType
+		immutable       bool
+		setupPDMock     func(*mockpd.MockClientMockRecorder)
+		expectType      corev1.SecretType
+		expectRenamed   bool
+		expectImmutable bool
+	}{
+		{
+			name:         "Test Immutable, PD Not Setup",
+			localObjects: []runtime.Object{},
+			immutable:    true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectType:      corev1.SecretTypeOpaque,
+			expectRenamed:   true,
+			expectImmutable: true,
+		},
+		{
+			name: "Test Immutable, PD Setup With Mutable Secret",
+			localObjects: []runtime.Object{
+				testCDConfigMap(),
+				testCDSyncSet(),
+				testCDSecret(),
+			},
+			immutable: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Times(0)
+				r.GetIntegrationKey(gomock.Any()).Times(0)
+			},
+			expectType:      corev1.SecretTypeOpaque,
+			expectRenamed:   true,
+			expectImmutable: true,
+		},
+		{
+			name: "Test Secret Type, PD Setup",
+			localObjects: []runtime.Object{
+				testCDConfigMap(),
+				testCDSyncSet(),
+				testCDSecret(),
+			},
+			secretType: "pagerduty.openshift.io/integration-key",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Times(0)
+				r.GetIntegrationKey(gomock.Any()).Times(0)
+			},
+			expectType:      "pagerduty.openshift.io/integration-key",
+			expectRenamed:   false,
+			expectImmutable: false,
+		},
 	}
 
-	clusterDeploymentFinalizerName := config.PagerDutyFinalizerPrefix + testPagerDutyIntegrationName
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.SecretType = test.secretType
+			pdi.Spec.ImmutableSecret = test.immutable
+			localObjects := append(test.localObjects, testClusterDeployment(true, true, true, false), testPDISecret(), pdi)
 
-	for _, finalizer := range cd.GetObjectMeta().GetFinalizers() {
-		if finalizer == clusterDeploymentFinalizerName {
-			// we found a matching finalizer, this is a success
-			return true
-		}
-	}
+			mocks := setupDefaultMocks(t, localObjects)
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
 
-	// if we got here, it's bad.  no matching finalizers
-	return false
-}
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			}
 
-// verifyNoFinalizer verifies that there is no PD finalizer on the CD.
-func verifyNoFinalizer(c client.Client, expected *ClusterDeploymentEntry) bool {
-	cd := hivev1.ClusterDeployment{}
-	err := c.Get(context.TODO(),
-		types.NamespacedName{Name: expected.name, Namespace: testNamespace}, &cd)
-	if err != nil {
-		return false
-	}
+			// Act, twice to confirm the second run is a noop
+			_, err1 := rpdi.Reconcile(request)
+			_, err2 := rpdi.Reconcile(request)
 
-	if expected.name != cd.Name {
-		return false
-	}
+			// Assert
+			assert.NoError(t, err1)
+			assert.NoError(t, err2)
 
-	for _, finalizer := range cd.GetObjectMeta().GetFinalizers() {
-		if strings.HasPrefix(finalizer, config.PagerDutyFinalizerPrefix) {
-			// we found a matching finalizer, this is a failure
-			return false
-		}
-	}
+			secret := &corev1.Secret{}
+			err := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: naming.SecretName(testServicePrefix, testClusterName), Namespace: testNamespace}, secret)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectType, secret.Type)
+			assert.Equal(t, test.expectImmutable, secret.Immutable != nil && *secret.Immutable)
 
-	// if we got here, it's good.  no matching finalizers
-	return true
+			ss := &hivev1.SyncSet{}
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: naming.SyncSetName(testServicePrefix, testClusterName), Namespace: testNamespace}, ss)
+			assert.NoError(t, err)
+			assert.Len(t, ss.Spec.Secrets, 1)
+			target := ss.Spec.Secrets[0].TargetRef.Name
+			assert.Equal(t, test.expectRenamed, target != pdi.Spec.TargetSecretRef.Name)
+			assert.True(t, strings.HasPrefix(target, pdi.Spec.TargetSecretRef.Name))
+		})
+	}
 }
 
-// verifySecretExists verifies that the secret which referenced by the SyncSet exists in the test namespace
-func verifySecretExists(c client.Client, expected *SecretEntry) bool {
-	secret := corev1.Secret{}
-	err := c.Get(context.TODO(),
-		types.NamespacedName{Name: expected.name, Namespace: testNamespace},
-		&secret)
+func TestReconcilePagerDutyIntegrationClusterStatus(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
 
-	if err != nil {
-		return false
+	tests := []struct {
+		name          string
+		syncStatuses  []hiveintv1alpha1.SyncStatus
+		expectStatus  corev1.ConditionStatus
+		expectMessage string
+	}{
+		{
+			name: "Test SyncSet Failed",
+			syncStatuses: []hiveintv1alpha1.SyncStatus{
+				{
+					Name:           naming.SyncSetName(testServicePrefix, testClusterName),
+					Result:         hiveintv1alpha1.FailureSyncSetResult,
+					FailureMessage: "namespace openshift-monitoring not found",
+				},
+			},
+			expectStatus:  corev1.ConditionTrue,
+			expectMessage: "namespace openshift-monitoring not found",
+		},
+		{
+			name: "Test SyncSet Applied",
+			syncStatuses: []hiveintv1alpha1.SyncStatus{
+				{
+					Name:   "some-other-syncset",
+					Result: hiveintv1alpha1.FailureSyncSetResult,
+				},
+				{
+					Name:   naming.SyncSetName(testServicePrefix, testClusterName),
+					Result: hiveintv1alpha1.SuccessSyncSetResult,
+				},
+			},
+			expectStatus: corev1.ConditionFalse,
+		},
+		{
+			name:          "Test SyncSet Not Reported",
+			expectStatus:  corev1.ConditionUnknown,
+			expectMessage: "Hive has not reported applying the SyncSet yet",
+		},
 	}
 
-	if expected.name != secret.Name {
-		return false
-	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			clusterSync := &hiveintv1alpha1.ClusterSync{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testClusterName,
+					Namespace: testNamespace,
+				},
+				Status: hiveintv1alpha1.ClusterSyncStatus{
+					SyncSets: test.syncStatuses,
+				},
+			}
 
-	if expected.pagerdutyKey != string(secret.Data["PAGERDUTY_KEY"]) {
-		return false
-	}
+			mocks := setupDefaultMocks(t, []runtime.Object{
+				testClusterDeployment(true, true, true, false),
+				testPDISecret(),
+				testPagerDutyIntegration(),
+				testCDConfigMap(),
+				testCDSyncSet(),
+				testCDSecret(),
+				clusterSync,
+			})
+			defer mocks.mockCtrl.Finish()
 
-	return true
-}
+			recorder := record.NewFakeRecorder(20)
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				recorder: recorder,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			}
 
-// verifyNoSecretExists verifies that the secret which referred by SyncSet does not exist
-func verifyNoSecretExists(c client.Client) bool {
-	secretList := &corev1.SecretList{}
-	opts := client.ListOptions{Namespace: testNamespace}
-	err := c.List(context.TODO(), secretList, &opts)
+			// Act, twice to confirm the second run is a noop
+			_, err1 := rpdi.Reconcile(request)
+			_, err2 := rpdi.Reconcile(request)
 
-	if err != nil {
-		if errors.IsNotFound(err) {
-			return true
-		}
-	}
+			// Assert
+			assert.NoError(t, err1)
+			assert.NoError(t, err2)
 
-	for _, secret := range secretList.Items {
-		if secret.Name == testsecretReferencesName {
-			return false
-		}
-	}
+			pdi := &pagerdutyv1alpha1.PagerDutyIntegration{}
+			err := mocks.fakeKubeClient.Get(context.TODO(), request.NamespacedName, pdi)
+			assert.NoError(t, err)
+			assert.Len(t, pdi.Status.Clusters, 1)
+			assert.Equal(t, testClusterName, pdi.Status.Clusters[0].ClusterDeploymentName)
+			var condition *pagerdutyv1alpha1.ClusterCondition
+			for i := range pdi.Status.Clusters[0].Conditions {
+				if pdi.Status.Clusters[0].Conditions[i].Type == pagerdutyv1alpha1.ClusterConditionSyncSetFailed {
+					condition = &pdi.Status.Clusters[0].Conditions[i]
+				}
+			}
+			if !assert.NotNil(t, condition) {
+				return
+			}
+			assert.Equal(t, test.expectStatus, condition.Status)
+			assert.Equal(t, test.expectMessage, condition.Message)
 
-	return true
+			// a failure is counted, and reported once on the ClusterDeployment and the PDI
+			events := eventsWithReason(recorder, eventSecretSyncFailed)
+			if test.expectStatus == corev1.ConditionTrue {
+				assert.Equal(t, 1, pdi.Status.SecretSyncFailedClusters)
+				assert.Len(t, events, 2)
+			} else {
+				assert.Equal(t, 0, pdi.Status.SecretSyncFailedClusters)
+				assert.Empty(t, events)
+			}
+		})
+	}
 }
 
-func verifyConfigMapExists(c client.Client) bool {
-	cmList := &corev1.ConfigMapList{}
-	opts := client.ListOptions{Namespace: testNamespace}
-	err := c.List(context.TODO(), cmList, &opts)
+func TestReconcilePagerDutyIntegrationServiceVerification(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
 
-	if err != nil {
-		if errors.IsNotFound(err) {
+	tests := []struct {
+		name         string
+		lastVerified time.Time
+		getService   error
+		expectCalls  int
+		expectStatus corev1.ConditionStatus
+	}{
+		{
+			name:        "Test Verification Not Due",
+			expectCalls: 0,
+		},
+		{
+			name:         "Test Verification Due, Service Found",
+			lastVerified: time.Now().Add(-config.ResyncPeriod - time.Hour),
+			expectCalls:  1,
+			expectStatus: corev1.ConditionFalse,
+		},
+		{
+			name:         "Test Verification Due, Service Not Found",
+			lastVerified: time.Now().Add(-config.ResyncPeriod - time.Hour),
+			getService:   fmt.Errorf("Failed call API endpoint. HTTP response code: 404. Error: &{2100 Not Found []}"),
+			expectCalls:  1,
+			expectStatus: corev1.ConditionTrue,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			if !test.lastVerified.IsZero() {
+				pdi.Status.Clusters = []pagerdutyv1alpha1.ClusterStatus{
+					{
+						ClusterDeploymentNamespace: testNamespace,
+						ClusterDeploymentName:      testClusterName,
+						LastVerifiedTime:           &metav1.Time{Time: test.lastVerified},
+					},
+				}
+			}
+
+			mocks := setupDefaultMocks(t, []runtime.Object{
+				testClusterDeployment(true, true, true, false),
+				testPDISecret(),
+				pdi,
+				testCDConfigMap(),
+				testCDSyncSet(),
+				testCDSecret(),
+			})
+			mocks.mockPDClient.EXPECT().GetService(gomock.Any()).Return(testPDService(), test.getService).Times(test.expectCalls)
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			}
+
+			// Act, twice to confirm the second run doesn't verify again
+			result, err1 := rpdi.Reconcile(request)
+			_, err2 := rpdi.Reconcile(request)
+
+			// Assert
+			assert.NoError(t, err1)
+			assert.NoError(t, err2)
+			assert.True(t, result.RequeueAfter >= config.ResyncMinInterval)
+			assert.True(t, result.RequeueAfter <= config.ResyncPeriod)
+
+			err := mocks.fakeKubeClient.Get(context.TODO(), request.NamespacedName, pdi)
+			assert.NoError(t, err)
+			assert.Len(t, pdi.Status.Clusters, 1)
+			assert.NotNil(t, pdi.Status.Clusters[0].LastVerifiedTime)
+			assert.True(t, time.Since(pdi.Status.Clusters[0].LastVerifiedTime.Time) < time.Minute)
+
+			var verification *pagerdutyv1alpha1.ClusterCondition
+			for i, condition := range pdi.Status.Clusters[0].Conditions {
+				if condition.Type == pagerdutyv1alpha1.ClusterConditionServiceVerificationFailed {
+					verification = &pdi.Status.Clusters[0].Conditions[i]
+				}
+			}
+			if test.expectCalls == 0 {
+				assert.Nil(t, verification)
+				return
+			}
+			assert.NotNil(t, verification)
+			assert.Equal(t, test.expectStatus, verification.Status)
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationServiceTags(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tags := &pagerdutyv1alpha1.ServiceTags{CostCenter: "123", Owner: "sre"}
+	expectTags := map[string]string{
+		config.ServiceTagCostCenter:  "123",
+		config.ServiceTagOwner:       "sre",
+		config.ServiceTagEnvironment: "",
+	}
+
+	tests := []struct {
+		name         string
+		serviceTags  *pagerdutyv1alpha1.ServiceTags
+		lastVerified time.Time
+		localObjects []runtime.Object
+		setupPDMock  func(*mockpd.MockClientMockRecorder)
+	}{
+		{
+			name:        "Test Tags Set On Create",
+			serviceTags: tags,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.SetServiceTags(gomock.Any(), expectTags).Return(nil).Times(1)
+			},
+		},
+		{
+			name:         "Test Tags Set On Resync",
+			serviceTags:  tags,
+			lastVerified: time.Now().Add(-config.ResyncPeriod - time.Hour),
+			localObjects: []runtime.Object{testCDConfigMap(), testCDSyncSet(), testCDSecret()},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.GetService(gomock.Any()).Return(testPDService(), nil).Times(1)
+				r.SetServiceTags(gomock.Any(), expectTags).Return(nil).Times(1)
+			},
+		},
+		{
+			name:         "Test Tag Failure Does Not Fail Resync",
+			serviceTags:  tags,
+			lastVerified: time.Now().Add(-config.ResyncPeriod - time.Hour),
+			localObjects: []runtime.Object{testCDConfigMap(), testCDSyncSet(), testCDSecret()},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.GetService(gomock.Any()).Return(testPDService(), nil).Times(1)
+				r.SetServiceTags(gomock.Any(), gomock.Any()).Return(fmt.Errorf("Failed call API endpoint. HTTP response code: 500")).Times(1)
+			},
+		},
+		{
+			name:         "Test No Tags",
+			lastVerified: time.Now().Add(-config.ResyncPeriod - time.Hour),
+			localObjects: []runtime.Object{testCDConfigMap(), testCDSyncSet(), testCDSecret()},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.GetService(gomock.Any()).Return(testPDService(), nil).Times(1)
+				r.SetServiceTags(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.ServiceTags = test.serviceTags
+			if !test.lastVerified.IsZero() {
+				pdi.Status.Clusters = []pagerdutyv1alpha1.ClusterStatus{
+					{
+						ClusterDeploymentNamespace: testNamespace,
+						ClusterDeploymentName:      testClusterName,
+						LastVerifiedTime:           &metav1.Time{Time: test.lastVerified},
+					},
+				}
+			}
+
+			localObjects := append([]runtime.Object{testClusterDeployment(true, true, true, false), testPDISecret(), pdi}, test.localObjects...)
+			mocks := setupDefaultMocks(t, localObjects)
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act, twice to confirm tags are not set again before the next resync
+			for i := 0; i < 2; i++ {
+				_, err := rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testPagerDutyIntegrationName,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationServiceDependencies(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	dependencies := &pagerdutyv1alpha1.ServiceDependencies{HubServiceID: "hub", BusinessServiceID: "business"}
+
+	tests := []struct {
+		name         string
+		dependencies *pagerdutyv1alpha1.ServiceDependencies
+		lastVerified time.Time
+		localObjects []runtime.Object
+		setupPDMock  func(*mockpd.MockClientMockRecorder)
+	}{
+		{
+			name:         "Test Dependencies Set On Create",
+			dependencies: dependencies,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.SetServiceDependencies(gomock.Any(), "hub", "business").Return(nil).Times(1)
+			},
+		},
+		{
+			name:         "Test Dependencies Set On Resync",
+			dependencies: dependencies,
+			lastVerified: time.Now().Add(-config.ResyncPeriod - time.Hour),
+			localObjects: []runtime.Object{testCDConfigMap(), testCDSyncSet(), testCDSecret()},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.GetService(gomock.Any()).Return(testPDService(), nil).Times(1)
+				r.SetServiceDependencies(gomock.Any(), "hub", "business").Return(nil).Times(1)
+			},
+		},
+		{
+			name:         "Test Dependency Failure Does Not Fail Resync",
+			dependencies: dependencies,
+			lastVerified: time.Now().Add(-config.ResyncPeriod - time.Hour),
+			localObjects: []runtime.Object{testCDConfigMap(), testCDSyncSet(), testCDSecret()},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.GetService(gomock.Any()).Return(testPDService(), nil).Times(1)
+				r.SetServiceDependencies(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("Failed call API endpoint. HTTP response code: 500")).Times(1)
+			},
+		},
+		{
+			name:         "Test No Dependencies",
+			lastVerified: time.Now().Add(-config.ResyncPeriod - time.Hour),
+			localObjects: []runtime.Object{testCDConfigMap(), testCDSyncSet(), testCDSecret()},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.GetService(gomock.Any()).Return(testPDService(), nil).Times(1)
+				r.SetServiceDependencies(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.ServiceDependencies = test.dependencies
+			if !test.lastVerified.IsZero() {
+				pdi.Status.Clusters = []pagerdutyv1alpha1.ClusterStatus{
+					{
+						ClusterDeploymentNamespace: testNamespace,
+						ClusterDeploymentName:      testClusterName,
+						LastVerifiedTime:           &metav1.Time{Time: test.lastVerified},
+					},
+				}
+			}
+
+			localObjects := append([]runtime.Object{testClusterDeployment(true, true, true, false), testPDISecret(), pdi}, test.localObjects...)
+			mocks := setupDefaultMocks(t, localObjects)
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act, twice to confirm dependencies are not set again before the next resync
+			for i := 0; i < 2; i++ {
+				_, err := rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testPagerDutyIntegrationName,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationServiceDrift(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	drifted := testPDService()
+	drifted.EscalationPolicy.ID = "other-escalation-policy"
+
+	highUrgency := testPDService()
+	highUrgency.IncidentUrgencyRule.Urgency = "high"
+
+	hygieneSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: config.OperatorNamespace,
+			Name:      "fleet-hygiene",
+		},
+		Data: map[string][]byte{
+			config.PagerDutySecretKey: []byte("hygiene-key"),
+		},
+	}
+
+	tests := []struct {
+		name            string
+		service         *pdApi.Service
+		hygiene         bool
+		incidentUrgency *pagerdutyv1alpha1.IncidentUrgency
+		alertGrouping   *pagerdutyv1alpha1.AlertGrouping
+		annotations     map[string]string
+		setupPDMock     func(*mockpd.MockClientMockRecorder)
+	}{
+		{
+			name:    "Test No Drift",
+			service: testPDService(),
+			hygiene: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.RepairService(gomock.Any(), gomock.Any()).Times(0)
+				r.SendChangeEvent(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:    "Test Drift Repaired Without Report",
+			service: drifted,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.LastServiceChanger(gomock.Any()).Times(0)
+				r.RepairService(gomock.Any(), drifted).Return(nil).Times(1)
+				r.SendChangeEvent(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:    "Test Drift Repaired And Reported",
+			service: drifted,
+			hygiene: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				gomock.InOrder(
+					r.LastServiceChanger(gomock.Any()).Return("Jane Doe", nil).Times(1),
+					r.RepairService(gomock.Any(), drifted).Return(nil).Times(1),
+					r.SendChangeEvent("hygiene-key", gomock.Any(), gomock.Any()).DoAndReturn(
+						func(routingKey string, summary string, details map[string]string) error {
+							assert.Equal(t, "Jane Doe", details["likely_changed_by"])
+							assert.Contains(t, details["drift"], "other-escalation-policy")
+							return nil
+						}).Times(1),
+				)
+			},
+		},
+		{
+			name:    "Test Failed Repair Not Reported",
+			service: drifted,
+			hygiene: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.LastServiceChanger(gomock.Any()).Return("", nil).Times(1)
+				r.RepairService(gomock.Any(), drifted).Return(fmt.Errorf("Failed call API endpoint. HTTP response code: 500")).Times(1)
+				r.SendChangeEvent(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:    "Test Urgency Drift Repaired",
+			service: highUrgency,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.RepairService(gomock.Any(), highUrgency).DoAndReturn(func(data *pd.Data, service *pdApi.Service) error {
+					assert.Equal(t, []string{"incident urgency is high instead of severity_based"}, pd.ServiceDrift(data, service))
+					return nil
+				}).Times(1)
+			},
+		},
+		{
+			name:            "Test Urgency Of Spec Not Drift",
+			service:         highUrgency,
+			incidentUrgency: &pagerdutyv1alpha1.IncidentUrgency{Urgency: pagerdutyv1alpha1.UrgencyHigh},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.RepairService(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:          "Test Alert Grouping Drift Repaired",
+			service:       testPDService(),
+			alertGrouping: &pagerdutyv1alpha1.AlertGrouping{Type: pagerdutyv1alpha1.AlertGroupingTime, Timeout: 10},
+			hygiene:       true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.AlertGroupingDrift(gomock.Any()).Return([]string{"alert grouping is unset instead of time (timeout 10)"}, nil).Times(1)
+				r.LastServiceChanger(gomock.Any()).Return("", nil).Times(1)
+				r.RepairService(gomock.Any(), gomock.Any()).Times(0)
+				r.SetAlertGrouping(gomock.Any()).DoAndReturn(func(data *pd.Data) error {
+					assert.Equal(t, "time", data.AlertGrouping.Type)
+					assert.Equal(t, uint(10), *data.AlertGrouping.Config.Timeout)
+					return nil
+				}).Times(1)
+				r.SendChangeEvent("hygiene-key", gomock.Any(), gomock.Any()).DoAndReturn(
+					func(routingKey string, summary string, details map[string]string) error {
+						assert.Equal(t, "alert grouping is unset instead of time (timeout 10)", details["drift"])
+						return nil
+					}).Times(1)
+			},
+		},
+		{
+			name:          "Test Alert Grouping Of Spec Not Drift",
+			service:       testPDService(),
+			alertGrouping: &pagerdutyv1alpha1.AlertGrouping{Type: pagerdutyv1alpha1.AlertGroupingIntelligent},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.AlertGroupingDrift(gomock.Any()).Return([]string{}, nil).Times(1)
+				r.SetAlertGrouping(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:    "Test Timeout Annotations Applied",
+			service: testPDService(),
+			annotations: map[string]string{
+				config.ClusterDeploymentResolveTimeoutAnnotation:     "0",
+				config.ClusterDeploymentAcknowledgeTimeoutAnnotation: "3600",
+			},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.RepairService(gomock.Any(), gomock.Any()).DoAndReturn(func(data *pd.Data, service *pdApi.Service) error {
+					assert.Equal(t, uint(0), data.AutoResolveTimeout)
+					assert.Equal(t, uint(3600), data.AcknowledgeTimeOut)
+					return nil
+				}).Times(1)
+			},
+		},
+		{
+			name:    "Test Invalid Timeout Annotation Ignored",
+			service: testPDService(),
+			annotations: map[string]string{
+				config.ClusterDeploymentResolveTimeoutAnnotation: "1h",
+			},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.RepairService(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.IncidentUrgency = test.incidentUrgency
+			pdi.Spec.AlertGrouping = test.alertGrouping
+			if test.hygiene {
+				pdi.Spec.FleetHygieneService = &pagerdutyv1alpha1.FleetHygieneService{
+					IntegrationKeySecretRef: corev1.SecretReference{Name: hygieneSecret.Name, Namespace: hygieneSecret.Namespace},
+				}
+			}
+			pdi.Status.Clusters = []pagerdutyv1alpha1.ClusterStatus{
+				{
+					ClusterDeploymentNamespace: testNamespace,
+					ClusterDeploymentName:      testClusterName,
+					LastVerifiedTime:           &metav1.Time{Time: time.Now().Add(-config.ResyncPeriod - time.Hour)},
+				},
+			}
+
+			cd := testClusterDeployment(true, true, true, false)
+			cd.Annotations = test.annotations
+
+			mocks := setupDefaultMocks(t, []runtime.Object{
+				cd,
+				testPDISecret(),
+				hygieneSecret,
+				pdi,
+				testCDConfigMap(),
+				testCDSyncSet(),
+				testCDSecret(),
+			})
+			mocks.mockPDClient.EXPECT().GetService(gomock.Any()).Return(test.service, nil).Times(1)
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act, twice to confirm drift is only checked in the verification slot
+			for i := 0; i < 2; i++ {
+				_, err := rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testPagerDutyIntegrationName,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationAuditPoll(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	changes := []pd.AuditRecord{
+		{
+			Action:        "update",
+			ExecutionTime: "2020-01-01T00:00:00Z",
+			Actors:        []pdApi.APIObject{{Type: "user_reference", Summary: "Jane Doe"}},
+			RootResource:  pdApi.APIObject{ID: testServiceID, Type: "service_reference"},
+		},
+		{
+			Action:       "update",
+			Actors:       []pdApi.APIObject{{Type: "user_reference", Summary: "John Doe"}},
+			RootResource: pdApi.APIObject{ID: "unmanaged-service", Type: "service_reference"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		interval     *metav1.Duration
+		lastPoll     time.Time
+		expectPolls  int
+		expectEvents int
+	}{
+		{
+			name:        "Test Poller Disabled",
+			expectPolls: 0,
+		},
+		{
+			name:         "Test First Poll",
+			interval:     &metav1.Duration{Duration: time.Hour},
+			expectPolls:  1,
+			expectEvents: 1,
+		},
+		{
+			name:        "Test Poll Not Due",
+			interval:    &metav1.Duration{Duration: time.Hour},
+			lastPoll:    time.Now().Add(-30 * time.Minute),
+			expectPolls: 0,
+		},
+		{
+			name:         "Test Poll Due",
+			interval:     &metav1.Duration{Duration: time.Hour},
+			lastPoll:     time.Now().Add(-2 * time.Hour),
+			expectPolls:  1,
+			expectEvents: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.AuditPollInterval = test.interval
+			if !test.lastPoll.IsZero() {
+				pdi.Status.LastAuditPollTime = &metav1.Time{Time: test.lastPoll}
+			}
+
+			mocks := setupDefaultMocks(t, []runtime.Object{
+				testClusterDeployment(true, true, true, false),
+				testPDISecret(),
+				pdi,
+				testCDConfigMap(),
+				testCDSyncSet(),
+				testCDSecret(),
+			})
+			mocks.mockPDClient.EXPECT().ListServiceChanges(gomock.Any()).Return(changes, nil).Times(test.expectPolls)
+			defer mocks.mockCtrl.Finish()
+
+			recorder := record.NewFakeRecorder(10)
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+				recorder: recorder,
+			}
+
+			// Act, twice to confirm the second run doesn't poll again
+			var result reconcile.Result
+			for i := 0; i < 2; i++ {
+				var err error
+				result, err = rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testPagerDutyIntegrationName,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+				assert.NoError(t, err)
+			}
+
+			// Assert
+			events := eventsWithReason(recorder, "ServiceModifiedOutOfBand")
+			assert.Len(t, events, test.expectEvents)
+			if test.expectEvents > 0 {
+				assert.Contains(t, events[0], "Jane Doe")
+			}
+
+			pdi = &pagerdutyv1alpha1.PagerDutyIntegration{}
+			err := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testPagerDutyIntegrationName, Namespace: config.OperatorNamespace}, pdi)
+			assert.NoError(t, err)
+			if test.interval == nil {
+				assert.Nil(t, pdi.Status.LastAuditPollTime)
+				return
+			}
+			assert.NotNil(t, pdi.Status.LastAuditPollTime)
+			assert.True(t, result.RequeueAfter <= test.interval.Duration)
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationActiveSilences(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	noalertsClusterDeployment := testClusterDeployment(false, true, false, false)
+	noalertsClusterDeployment.Labels[config.ClusterDeploymentNoalertsLabel] = "true"
+	noalertsClusterDeployment.Annotations = map[string]string{config.ClusterDeploymentSilencedAnnotation: "true"}
+
+	expiredSilence := testSilence("expired", time.Now().Add(-2*time.Hour))
+
+	mocks := setupDefaultMocks(t, []runtime.Object{
+		noalertsClusterDeployment,
+		testSilence("active", time.Now()),
+		expiredSilence,
+		testPDISecret(),
+		testPagerDutyIntegration(),
+	})
+	defer mocks.mockCtrl.Finish()
+
+	rpdi := &ReconcilePagerDutyIntegration{
+		client:   mocks.fakeKubeClient,
+		scheme:   scheme.Scheme,
+		pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+	}
+
+	_, err := rpdi.Reconcile(reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      testPagerDutyIntegrationName,
+			Namespace: config.OperatorNamespace,
+		},
+	})
+	assert.NoError(t, err)
+
+	pdi := &pagerdutyv1alpha1.PagerDutyIntegration{}
+	err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testPagerDutyIntegrationName, Namespace: config.OperatorNamespace}, pdi)
+	assert.NoError(t, err)
+
+	// the expired silence must not be reported
+	assert.Len(t, pdi.Status.ActiveSilences, 3)
+	assert.Equal(t, pagerdutyv1alpha1.SilenceSourceNoalertsLabel, pdi.Status.ActiveSilences[0].Source)
+	assert.Nil(t, pdi.Status.ActiveSilences[0].ExpiresAt)
+	assert.Equal(t, pagerdutyv1alpha1.SilenceSourceSilencedAnnotation, pdi.Status.ActiveSilences[1].Source)
+	assert.Equal(t, pagerdutyv1alpha1.SilenceSourcePagerDutySilence, pdi.Status.ActiveSilences[2].Source)
+	assert.Equal(t, "active", pdi.Status.ActiveSilences[2].SilenceName)
+	assert.NotNil(t, pdi.Status.ActiveSilences[2].ExpiresAt)
+}
+
+func TestReconcilePagerDutyIntegrationStaleSilences(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name              string
+		noalertsSince     string
+		silenceCreated    time.Time
+		expectStale       int
+		expectActive      int
+		expectEvents      int
+		expectRequeue     bool
+		expectNoalerts    bool
+		expectSilenceGone bool
+	}{
+		{
+			name:              "Test Stale Silences Lifted",
+			noalertsSince:     time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339),
+			silenceCreated:    time.Now().Add(-90 * time.Minute),
+			expectStale:       2,
+			expectActive:      0,
+			expectEvents:      2,
+			expectRequeue:     false,
+			expectNoalerts:    false,
+			expectSilenceGone: true,
+		},
+		{
+			name:              "Test Recent Silences Kept",
+			silenceCreated:    time.Now().Add(-10 * time.Minute),
+			expectStale:       0,
+			expectActive:      2,
+			expectEvents:      0,
+			expectRequeue:     true,
+			expectNoalerts:    true,
+			expectSilenceGone: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			cd := testClusterDeployment(false, true, false, false)
+			cd.Labels[config.ClusterDeploymentNoalertsLabel] = "true"
+			if test.noalertsSince != "" {
+				cd.Annotations = map[string]string{config.ClusterDeploymentNoalertsSinceAnnotation: test.noalertsSince}
+			}
+
+			silence := testSilence("silence", test.silenceCreated)
+			silence.Spec.Duration = metav1.Duration{Duration: 4 * time.Hour}
+
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.MaxSilenceDuration = &metav1.Duration{Duration: time.Hour}
+
+			mocks := setupDefaultMocks(t, []runtime.Object{cd, silence, testPDISecret(), pdi})
+			defer mocks.mockCtrl.Finish()
+
+			recorder := record.NewFakeRecorder(10)
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+				recorder: recorder,
+			}
+
+			// Act
+			result, err := rpdi.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			// a silence still in effect is checked again before the next resync
+			assert.Equal(t, test.expectRequeue, result.RequeueAfter < config.ResyncPeriod)
+			assert.Len(t, recorder.Events, test.expectEvents)
+
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testPagerDutyIntegrationName, Namespace: config.OperatorNamespace}, pdi)
+			assert.NoError(t, err)
+			assert.Len(t, pdi.Status.StaleSilences, test.expectStale)
+			assert.Len(t, pdi.Status.ActiveSilences, test.expectActive)
+
+			cd = &hivev1.ClusterDeployment{}
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testClusterName, Namespace: testNamespace}, cd)
+			assert.NoError(t, err)
+			_, hasNoalerts := cd.Labels[config.ClusterDeploymentNoalertsLabel]
+			_, hasSince := cd.Annotations[config.ClusterDeploymentNoalertsSinceAnnotation]
+			assert.Equal(t, test.expectNoalerts, hasNoalerts)
+			assert.Equal(t, test.expectNoalerts, hasSince)
+
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: "silence", Namespace: testNamespace}, silence)
+			assert.Equal(t, test.expectSilenceGone, errors.IsNotFound(err))
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationDeliveryProbe(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name          string
+		remoteObjects []runtime.Object
+		expectStatus  corev1.ConditionStatus
+		expectReason  string
+	}{
+		{
+			name:          "Test Probe Succeeded",
+			remoteObjects: []runtime.Object{testProbeCronJob(kube.ProbeResultSuccess)},
+			expectStatus:  corev1.ConditionFalse,
+			expectReason:  "ProbeSucceeded",
+		},
+		{
+			name:          "Test Probe Reported Unreachable",
+			remoteObjects: []runtime.Object{testProbeCronJob(kube.ProbeResultUnreachable)},
+			expectStatus:  corev1.ConditionTrue,
+			expectReason:  kube.ProbeResultUnreachable,
+		},
+		{
+			name:          "Test Probe Not Run",
+			remoteObjects: []runtime.Object{testProbeCronJob("")},
+			expectStatus:  corev1.ConditionUnknown,
+			expectReason:  "ProbeNotReported",
+		},
+		{
+			name:          "Test Probe Not Delivered",
+			remoteObjects: []runtime.Object{},
+			expectStatus:  corev1.ConditionUnknown,
+			expectReason:  "ProbeNotFound",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.DeliveryProbe = &pagerdutyv1alpha1.DeliveryProbe{Image: "quay.io/example/probe:latest"}
+			pdi.Status.Clusters = []pagerdutyv1alpha1.ClusterStatus{
+				{
+					ClusterDeploymentNamespace: testNamespace,
+					ClusterDeploymentName:      testClusterName,
+					LastVerifiedTime:           &metav1.Time{Time: time.Now().Add(-config.ResyncPeriod - time.Hour)},
+				},
+			}
+
+			cd := testClusterDeployment(true, true, true, false)
+			cd.Spec.ClusterMetadata = &hivev1.ClusterMetadata{
+				AdminKubeconfigSecretRef: corev1.LocalObjectReference{Name: "admin-kubeconfig"},
+			}
+			kubeconfig := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "admin-kubeconfig", Namespace: testNamespace},
+				Data:       map[string][]byte{"kubeconfig": []byte("test")},
+			}
+
+			mocks := setupDefaultMocks(t, []runtime.Object{cd, kubeconfig, testPDISecret(), pdi, testCDConfigMap(), testCDSyncSet(), testCDSecret()})
+			mocks.mockPDClient.EXPECT().GetService(gomock.Any()).Return(testPDService(), nil).Times(1)
+			defer mocks.mockCtrl.Finish()
+
+			remote := fakekubeclient.NewFakeClient(test.remoteObjects...)
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+				remoteClient: func(kubeconfig []byte) (client.Client, error) {
+					assert.Equal(t, "test", string(kubeconfig))
+					return remote, nil
+				},
+			}
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			}
+
+			// Act, twice to confirm the second run doesn't probe again
+			_, err1 := rpdi.Reconcile(request)
+			_, err2 := rpdi.Reconcile(request)
+
+			// Assert
+			assert.NoError(t, err1)
+			assert.NoError(t, err2)
+
+			ss := &hivev1.SyncSet{}
+			err := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: naming.ProbeSyncSetName(testServicePrefix, testClusterName), Namespace: testNamespace}, ss)
+			assert.NoError(t, err)
+			assert.Len(t, ss.Spec.Resources, 4)
+
+			err = mocks.fakeKubeClient.Get(context.TODO(), request.NamespacedName, pdi)
+			assert.NoError(t, err)
+			assert.Len(t, pdi.Status.Clusters, 1)
+
+			var probe *pagerdutyv1alpha1.ClusterCondition
+			for i, condition := range pdi.Status.Clusters[0].Conditions {
+				if condition.Type == pagerdutyv1alpha1.ClusterConditionDeliveryVerificationFailed {
+					probe = &pdi.Status.Clusters[0].Conditions[i]
+				}
+			}
+			assert.NotNil(t, probe)
+			assert.Equal(t, test.expectStatus, probe.Status)
+			assert.Equal(t, test.expectReason, probe.Reason)
+		})
+	}
+}
+
+// testProbeCronJob returns the delivery probe CronJob of the target cluster, labeled with the given result unless empty.
+func testProbeCronJob(result string) *batchv1beta1.CronJob {
+	pdi := testPagerDutyIntegration()
+	cronJob := &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kube.ProbeName(pdi),
+			Namespace: pdi.Spec.TargetSecretRef.Namespace,
+		},
+	}
+	if result != "" {
+		cronJob.Labels = map[string]string{config.DeliveryProbeResultLabel: result}
+	}
+	return cronJob
+}
+
+func TestReconcilePagerDutyIntegrationAccountQuota(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name            string
+		quotaExceeded   bool
+		clearAnnotation bool
+		setupPDMock     func(*mockpd.MockClientMockRecorder)
+		expectStatus    corev1.ConditionStatus
+		expectReason    string
+	}{
+		{
+			name: "Test Quota Exceeded On Create",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return("", &pd.AccountQuotaExceededError{Err: fmt.Errorf("HTTP response code: 402")}).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Times(0)
+			},
+			expectStatus: corev1.ConditionTrue,
+			expectReason: "ServiceLimitReached",
+		},
+		{
+			name:          "Test Quota Exceeded Stops Create",
+			quotaExceeded: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Times(0)
+				r.GetIntegrationKey(gomock.Any()).Times(0)
+			},
+			expectStatus: corev1.ConditionTrue,
+			expectReason: "ServiceLimitReached",
+		},
+		{
+			name:            "Test Quota Exceeded Cleared By Annotation",
+			quotaExceeded:   true,
+			clearAnnotation: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectStatus: corev1.ConditionFalse,
+			expectReason: "ManuallyCleared",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			if test.quotaExceeded {
+				pdi.Status.Conditions = []pagerdutyv1alpha1.PagerDutyIntegrationCondition{{
+					Type:   pagerdutyv1alpha1.PagerDutyIntegrationConditionAccountQuotaExceeded,
+					Status: corev1.ConditionTrue,
+					Reason: "ServiceLimitReached",
+				}}
+			}
+			if test.clearAnnotation {
+				pdi.Annotations = map[string]string{config.PagerDutyIntegrationClearQuotaAnnotation: ""}
+			}
+
+			mocks := setupDefaultMocks(t, []runtime.Object{testClusterDeployment(true, true, true, false), testPDISecret(), pdi})
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act, twice to confirm the quota stops further attempts
+			for i := 0; i < 2; i++ {
+				_, err := rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testPagerDutyIntegrationName,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+				assert.NoError(t, err)
+			}
+
+			// Assert
+			pdi = &pagerdutyv1alpha1.PagerDutyIntegration{}
+			err := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testPagerDutyIntegrationName, Namespace: config.OperatorNamespace}, pdi)
+			assert.NoError(t, err)
+			assert.NotContains(t, pdi.Annotations, config.PagerDutyIntegrationClearQuotaAnnotation)
+			var quota *pagerdutyv1alpha1.PagerDutyIntegrationCondition
+			for i, condition := range pdi.Status.Conditions {
+				if condition.Type == pagerdutyv1alpha1.PagerDutyIntegrationConditionAccountQuotaExceeded {
+					quota = &pdi.Status.Conditions[i]
+				}
+			}
+			if assert.NotNil(t, quota) {
+				assert.Equal(t, test.expectStatus, quota.Status)
+				assert.Equal(t, test.expectReason, quota.Reason)
+			}
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationDeprovisioningEventRule(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name        string
+		rule        *pagerdutyv1alpha1.DeprovisioningEventRule
+		setupPDMock func(*mockpd.MockClientMockRecorder)
+	}{
+		{
+			name: "Test No Rule",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateSuppressionRule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				r.DeleteService(gomock.Any()).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "Test Rule Defaults",
+			rule: &pagerdutyv1alpha1.DeprovisioningEventRule{RulesetID: "RULESET"},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateSuppressionRule("RULESET", config.DeprovisioningEventRuleDefaultDetail, testClusterName, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(rulesetID, detail, value string, start, end time.Time) (string, error) {
+						assert.Equal(t, config.DeprovisioningEventRuleDefaultDuration, end.Sub(start))
+						return "RULE", nil
+					}).Times(1)
+				r.DeleteService(gomock.Any()).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "Test Rule Custom",
+			rule: &pagerdutyv1alpha1.DeprovisioningEventRule{
+				RulesetID:       "RULESET",
+				ClusterIDDetail: "cluster",
+				Duration:        &metav1.Duration{Duration: time.Hour},
+			},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateSuppressionRule("RULESET", "cluster", testClusterName, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(rulesetID, detail, value string, start, end time.Time) (string, error) {
+						assert.Equal(t, time.Hour, end.Sub(start))
+						return "RULE", nil
+					}).Times(1)
+				r.DeleteService(gomock.Any()).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "Test Rule Failure Does Not Block Deletion",
+			rule: &pagerdutyv1alpha1.DeprovisioningEventRule{RulesetID: "RULESET"},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateSuppressionRule(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return("", fmt.Errorf("ruleset not found")).Times(1)
+				r.DeleteService(gomock.Any()).Return(nil).Times(1)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.DeprovisioningEventRule = test.rule
+
+			mocks := setupDefaultMocks(t, []runtime.Object{
+				testClusterDeployment(true, true, true, true),
+				testPDISecret(),
+				pdi,
+				testCDConfigMap(),
+				testCDSyncSet(),
+				testCDSecret(),
+			})
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act, twice to confirm the rule is only added while the finalizer is held
+			for i := 0; i < 2; i++ {
+				_, err := rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testPagerDutyIntegrationName,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+				assert.NoError(t, err)
+			}
+
+			// Assert
+			cd := &hivev1.ClusterDeployment{}
+			err := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testClusterName, Namespace: testNamespace}, cd)
+			assert.NoError(t, err)
+			assert.False(t, utils.HasFinalizer(cd, config.PagerDutyFinalizerPrefix+testPagerDutyIntegrationName))
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationRetryReasons(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	applied := &hiveintv1alpha1.ClusterSync{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testClusterName,
+			Namespace: testNamespace,
+		},
+		Status: hiveintv1alpha1.ClusterSyncStatus{
+			SyncSets: []hiveintv1alpha1.SyncStatus{
+				{
+					Name:   naming.SyncSetName(testServicePrefix, testClusterName),
+					Result: hiveintv1alpha1.SuccessSyncSetResult,
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		installed     bool
+		servicePrefix string
+		normalize     bool
+		nameTemplate  string
+		requeueDelay  *metav1.Duration
+		localObjects  []runtime.Object
+		setupPDMock   func(*mockpd.MockClientMockRecorder)
+		expectErr     bool
+		expectRequeue time.Duration
+		expectReason  pagerdutyv1alpha1.RetryReason
+	}{
+		{
+			name:         "Test Not Installed",
+			installed:    false,
+			setupPDMock:  func(r *mockpd.MockClientMockRecorder) {},
+			expectReason: pagerdutyv1alpha1.RetryReasonNotInstalled,
+		},
+		{
+			name:      "Test PD Error",
+			installed: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return("", fmt.Errorf("Failed call API endpoint. HTTP response code: 500. Error: &{}")).Times(1)
+			},
+			expectErr:    true,
+			expectReason: pagerdutyv1alpha1.RetryReasonPDError,
+		},
+		{
+			name:         "Test PD Error Requeue Delay",
+			installed:    true,
+			requeueDelay: &metav1.Duration{Duration: 2 * time.Minute},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return("", fmt.Errorf("Failed call API endpoint. HTTP response code: 500. Error: &{}")).Times(1)
+			},
+			expectRequeue: 2 * time.Minute,
+			expectReason:  pagerdutyv1alpha1.RetryReasonPDError,
+		},
+		{
+			name:      "Test PD Rate Limited",
+			installed: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return("", fmt.Errorf("Failed call API endpoint. HTTP response code: 429. Error: &{}")).Times(1)
+			},
+			expectRequeue: pd.RateLimitDelay,
+			expectReason:  pagerdutyv1alpha1.RetryReasonPDRateLimited,
+		},
+		{
+			name:      "Test PD Rate Limited After Retries",
+			installed: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return("", &pd.RateLimitError{RetryAfter: 42 * time.Second}).Times(1)
+			},
+			expectRequeue: 42 * time.Second,
+			expectReason:  pagerdutyv1alpha1.RetryReasonPDRateLimited,
+		},
+		{
+			name:      "Test Secret Sync Pending",
+			installed: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectReason: pagerdutyv1alpha1.RetryReasonSecretSyncPending,
+		},
+		{
+			name:          "Test Service Name Too Long",
+			installed:     true,
+			servicePrefix: strings.Repeat("x", pd.MaxServiceNameLength),
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Times(0)
+			},
+			expectReason: pagerdutyv1alpha1.RetryReasonServiceNameTooLong,
+		},
+		{
+			name:          "Test Service Name Normalized",
+			installed:     true,
+			servicePrefix: strings.Repeat("x", pd.MaxServiceNameLength),
+			normalize:     true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).DoAndReturn(func(data *pd.Data) (string, error) {
+					assert.Len(t, pd.ServiceName(data), pd.MaxServiceNameLength)
+					return testIntegrationID, nil
+				}).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectReason: pagerdutyv1alpha1.RetryReasonSecretSyncPending,
+		},
+		{
+			name:         "Test Service Name Template Failed",
+			installed:    true,
+			nameTemplate: "{{.Prefix}}-{{.Labels.region}}",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Times(0)
+			},
+			expectReason: pagerdutyv1alpha1.RetryReasonServiceNameTemplateFailed,
+		},
+		{
+			name:         "Test Service Name Templated",
+			installed:    true,
+			nameTemplate: "{{.Prefix}}-{{.ClusterID}}",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).DoAndReturn(func(data *pd.Data) (string, error) {
+					assert.Equal(t, testServicePrefix+"-"+testClusterName, pd.ServiceName(data))
+					return testIntegrationID, nil
+				}).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectReason: pagerdutyv1alpha1.RetryReasonSecretSyncPending,
+		},
+		{
+			name:         "Test Set Up",
+			installed:    true,
+			localObjects: []runtime.Object{applied},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			if test.servicePrefix != "" {
+				pdi.Spec.ServicePrefix = test.servicePrefix
+			}
+			pdi.Spec.NormalizeServiceNames = test.normalize
+			pdi.Spec.ServiceNameTemplate = test.nameTemplate
+			pdi.Spec.ErrorRequeueDelay = test.requeueDelay
+			localObjects := append(test.localObjects, testClusterDeployment(test.installed, true, true, false), testPDISecret(), pdi)
+			mocks := setupDefaultMocks(t, localObjects)
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act
+			result, err := rpdi.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			})
+
+			// Assert
+			assert.Equal(t, test.expectErr, err != nil)
+			if test.expectRequeue > 0 {
+				assert.Equal(t, test.expectRequeue, result.RequeueAfter)
+			}
+			for _, reason := range retryReasons {
+				expected := 0.0
+				if reason == test.expectReason {
+					expected = 1
+				}
+				metric := localmetrics.MetricPagerDutyIntegrationRetryReason.WithLabelValues(testPagerDutyIntegrationName, string(reason))
+				assert.Equal(t, expected, testutil.ToFloat64(metric), string(reason))
+			}
+
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testPagerDutyIntegrationName, Namespace: config.OperatorNamespace}, pdi)
+			assert.NoError(t, err)
+			if !test.installed {
+				assert.Len(t, pdi.Status.Clusters, 0)
+				return
+			}
+			assert.Len(t, pdi.Status.Clusters, 1)
+			assert.Equal(t, test.expectReason, pdi.Status.Clusters[0].RetryReason)
+
+			failed := localmetrics.MetricPagerDutyIntegrationFailedClusters.WithLabelValues(testPagerDutyIntegrationName)
+			assert.Equal(t, float64(pdi.Status.FailedClusters), testutil.ToFloat64(failed))
+			services := localmetrics.MetricPagerDutyIntegrationServices.WithLabelValues(testPagerDutyIntegrationName)
+			assert.Equal(t, float64(countServices(pdi.Status.Clusters)), testutil.ToFloat64(services))
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationMultipleOwners(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	const otherPagerDutyIntegrationName = "otherPagerDutyIntegration"
+
+	tests := []struct {
+		name              string
+		otherPrefix       string
+		otherMatching     bool
+		localObjects      []runtime.Object
+		setupPDMock       func(*mockpd.MockClientMockRecorder)
+		expectOtherObject bool
+		expectEvents      int
+	}{
+		{
+			name:          "Test Different Prefixes Each Get Their Own Objects",
+			otherPrefix:   "other-service-prefix",
+			otherMatching: true,
+			localObjects:  []runtime.Object{},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(2)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(2)
+			},
+			expectOtherObject: true,
+			expectEvents:      0,
+		},
+		{
+			name:          "Test Shared Prefix Is Not Taken Over",
+			otherPrefix:   testServicePrefix,
+			otherMatching: true,
+			localObjects:  []runtime.Object{},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectOtherObject: false,
+			expectEvents:      2,
+		},
+		{
+			name:          "Test Shared Prefix Is Not Cleaned Up By Other",
+			otherPrefix:   testServicePrefix,
+			otherMatching: false,
+			localObjects:  []runtime.Object{testCDConfigMap(), testCDSecret(), testCDSyncSet()},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Times(0)
+				r.GetIntegrationKey(gomock.Any()).Times(0)
+				r.DeleteService(gomock.Any()).Times(0)
+			},
+			expectOtherObject: false,
+			expectEvents:      0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			cd := testClusterDeployment(true, true, true, false)
+			cd.Finalizers = append(cd.Finalizers, config.PagerDutyFinalizerPrefix+otherPagerDutyIntegrationName)
+
+			other := testPagerDutyIntegration()
+			other.Name = otherPagerDutyIntegrationName
+			other.Spec.ServicePrefix = test.otherPrefix
+			if !test.otherMatching {
+				other.Spec.ClusterDeploymentSelector.MatchLabels = map[string]string{"notmatching": "true"}
+			}
+
+			localObjects := append(test.localObjects, cd, testPDISecret(), testPagerDutyIntegration(), other)
+			mocks := setupDefaultMocks(t, localObjects)
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			recorder := record.NewFakeRecorder(10)
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+				recorder: recorder,
+			}
+
+			// Act, twice for each PDI to confirm the second run is a noop
+			for _, name := range []string{testPagerDutyIntegrationName, otherPagerDutyIntegrationName, testPagerDutyIntegrationName, otherPagerDutyIntegrationName} {
+				_, err := rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      name,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+				assert.NoError(t, err)
+			}
+
+			// Assert
+			assert.Len(t, eventsWithReason(recorder, string(pagerdutyv1alpha1.RetryReasonConflict)), test.expectEvents)
+
+			// the first PDI always keeps its objects
+			for _, obj := range []struct {
+				name string
+				obj  runtime.Object
+			}{
+				{naming.ConfigMapName(testServicePrefix, testClusterName), &corev1.ConfigMap{}},
+				{naming.SecretName(testServicePrefix, testClusterName), &corev1.Secret{}},
+				{naming.SyncSetName(testServicePrefix, testClusterName), &hivev1.SyncSet{}},
+			} {
+				err := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: obj.name, Namespace: testNamespace}, obj.obj)
+				assert.NoError(t, err)
+				assert.Equal(t, testPagerDutyIntegrationName, obj.obj.(metav1.Object).GetLabels()[config.PagerDutyIntegrationLabel])
+			}
+
+			cm := &corev1.ConfigMap{}
+			err := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: naming.ConfigMapName("other-service-prefix", testClusterName), Namespace: testNamespace}, cm)
+			assert.Equal(t, test.expectOtherObject, err == nil)
+			if test.expectOtherObject {
+				assert.Equal(t, otherPagerDutyIntegrationName, cm.Labels[config.PagerDutyIntegrationLabel])
+			}
+
+			cd = &hivev1.ClusterDeployment{}
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testClusterName, Namespace: testNamespace}, cd)
+			assert.NoError(t, err)
+			assert.Contains(t, cd.Finalizers, config.PagerDutyFinalizerPrefix+testPagerDutyIntegrationName)
+			assert.Equal(t, test.otherMatching, utils.HasFinalizer(cd, config.PagerDutyFinalizerPrefix+otherPagerDutyIntegrationName))
+		})
+	}
+}
+
+// testSilence returns a PagerDutySilence for the test ClusterDeployment created at the given time and lasting one hour.
+func testSilence(name string, created time.Time) *pagerdutyv1alpha1.PagerDutySilence {
+	return &pagerdutyv1alpha1.PagerDutySilence{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         testNamespace,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: pagerdutyv1alpha1.PagerDutySilenceSpec{
+			ClusterDeploymentRef: corev1.LocalObjectReference{Name: testClusterName},
+			Duration:             metav1.Duration{Duration: time.Hour},
+			Reason:               "planned upgrade",
+			Requester:            "someone@example.com",
+		},
+	}
+}
+
+// verifySyncSetExists verifies that a SyncSet exists that matches the supplied expected SyncSetEntry.
+func verifySyncSetExists(c client.Client, expected *SyncSetEntry) bool {
+	ss := hivev1.SyncSet{}
+	err := c.Get(context.TODO(),
+		types.NamespacedName{Name: expected.name, Namespace: testNamespace},
+		&ss)
+	if err != nil {
+		return false
+	}
+
+	if expected.name != ss.Name {
+		return false
+	}
+
+	if expected.clusterDeploymentRefName != ss.Spec.ClusterDeploymentRefs[0].Name {
+		return false
+	}
+	secretReferences := ss.Spec.SyncSetCommonSpec.Secrets[0].SourceRef.Name
+	if secretReferences == "" {
+		return false
+	}
+	return string(secretReferences) == expected.name
+}
+
+// verifyNoSyncSetExists verifies that there is no SyncSet present that matches the supplied expected SyncSetEntry.
+func verifyNoSyncSetExists(c client.Client) bool {
+	ssList := &hivev1.SyncSetList{}
+	opts := client.ListOptions{Namespace: testNamespace}
+	err := c.List(context.TODO(), ssList, &opts)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// no syncsets are defined, this is OK
+			return true
+		}
+	}
+
+	for _, ss := range ssList.Items {
+		if ss.Name != testClusterName+testOtherSyncSetPostfix {
+			// too bad, found a syncset associated with this operator
+			return false
+		}
+	}
+
+	// if we got here, it's good.  list was empty or everything passed
+	return true
+}
+
+// verifyFinalizer verifies that there is no PD finalizer on the CD.
+func verifyFinalizer(c client.Client, expected *ClusterDeploymentEntry) bool {
+	cd := hivev1.ClusterDeployment{}
+	err := c.Get(context.TODO(),
+		types.NamespacedName{Name: expected.name, Namespace: testNamespace}, &cd)
+	if err != nil {
+		return false
+	}
+
+	if expected.name != cd.Name {
+		return false
+	}
+
+	clusterDeploymentFinalizerName := config.PagerDutyFinalizerPrefix + testPagerDutyIntegrationName
+
+	for _, finalizer := range cd.GetObjectMeta().GetFinalizers() {
+		if finalizer == clusterDeploymentFinalizerName {
+			// we found a matching finalizer, this is a success
+			return true
+		}
+	}
+
+	// if we got here, it's bad.  no matching finalizers
+	return false
+}
+
+// verifyNoFinalizer verifies that there is no PD finalizer on the CD.
+func verifyNoFinalizer(c client.Client, expected *ClusterDeploymentEntry) bool {
+	cd := hivev1.ClusterDeployment{}
+	err := c.Get(context.TODO(),
+		types.NamespacedName{Name: expected.name, Namespace: testNamespace}, &cd)
+	if err != nil {
+		return false
+	}
+
+	if expected.name != cd.Name {
+		return false
+	}
+
+	for _, finalizer := range cd.GetObjectMeta().GetFinalizers() {
+		if strings.HasPrefix(finalizer, config.PagerDutyFinalizerPrefix) {
+			// we found a matching finalizer, this is a failure
+			return false
+		}
+	}
+
+	// if we got here, it's good.  no matching finalizers
+	return true
+}
+
+// verifySecretExists verifies that the secret which referenced by the SyncSet exists in the test namespace
+func verifySecretExists(c client.Client, expected *SecretEntry) bool {
+	secret := corev1.Secret{}
+	err := c.Get(context.TODO(),
+		types.NamespacedName{Name: expected.name, Namespace: testNamespace},
+		&secret)
+
+	if err != nil {
+		return false
+	}
+
+	if expected.name != secret.Name {
+		return false
+	}
+
+	if expected.pagerdutyKey != string(secret.Data["PAGERDUTY_KEY"]) {
+		return false
+	}
+
+	return true
+}
+
+// verifyNoSecretExists verifies that the secret which referred by SyncSet does not exist
+func verifyNoSecretExists(c client.Client) bool {
+	secretList := &corev1.SecretList{}
+	opts := client.ListOptions{Namespace: testNamespace}
+	err := c.List(context.TODO(), secretList, &opts)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return true
+		}
+	}
+
+	for _, secret := range secretList.Items {
+		if secret.Name == testsecretReferencesName {
+			return false
+		}
+	}
+
+	return true
+}
+
+func verifyConfigMapExists(c client.Client) bool {
+	cmList := &corev1.ConfigMapList{}
+	opts := client.ListOptions{Namespace: testNamespace}
+	err := c.List(context.TODO(), cmList, &opts)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
 			// no configmaps are defined, this is a failure
 			return false
 		}
 	}
 
-	for _, cm := range cmList.Items {
-		if strings.HasSuffix(cm.Name, config.ConfigMapSuffix) {
-			// found a configmap associated with this operator!
-			return true
-		}
+	for _, cm := range cmList.Items {
+		if strings.HasSuffix(cm.Name, naming.ConfigMapSuffix) {
+			// found a configmap associated with this operator!
+			return true
+		}
+	}
+
+	// if we got here, it's bad.
+	return false
+}
+
+func verifyNoConfigMapExists(c client.Client) bool {
+	cmList := &corev1.ConfigMapList{}
+	opts := client.ListOptions{Namespace: testNamespace}
+	err := c.List(context.TODO(), cmList, &opts)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// no configmaps are defined, this is OK
+			return true
+		}
+	}
+
+	for _, cm := range cmList.Items {
+		if strings.HasSuffix(cm.Name, naming.ConfigMapSuffix) {
+			// too bad, found a configmap associated with this operator
+			return false
+		}
+	}
+
+	// if we got here, it's good.  list was empty or everything passed
+	return true
+}
+
+func TestReconcilePagerDutyIntegrationReferencesValid(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name          string
+		setupPDMock   func(*mockpd.MockClientMockRecorder)
+		expectStatus  corev1.ConditionStatus
+		expectReason  string
+		expectMessage string
+	}{
+		{
+			name: "Test References Found",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.ValidateReferences(pd.References{EscalationPolicyID: testEscalationPolicy}).Return([]string{}, nil).Times(2)
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectStatus: corev1.ConditionTrue,
+			expectReason: "ReferencesFound",
+		},
+		{
+			name: "Test Reference Not Found Stops Create",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.ValidateReferences(gomock.Any()).Return([]string{"escalation policy " + testEscalationPolicy + " not found"}, nil).Times(2)
+				r.CreateService(gomock.Any()).Times(0)
+				r.GetIntegrationKey(gomock.Any()).Times(0)
+			},
+			expectStatus:  corev1.ConditionFalse,
+			expectReason:  "ReferenceNotFound",
+			expectMessage: "escalation policy " + testEscalationPolicy + " not found",
+		},
+		{
+			name: "Test Validation Failure Does Not Stop Create",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.ValidateReferences(gomock.Any()).Return(nil, fmt.Errorf("HTTP response code: 500")).Times(2)
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectStatus:  corev1.ConditionUnknown,
+			expectReason:  "ValidationFailed",
+			expectMessage: "HTTP response code: 500",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			mocks := &mocks{
+				fakeKubeClient: fakekubeclient.NewFakeClient(testClusterDeployment(true, true, true, false), testPDISecret(), testPagerDutyIntegration()),
+				mockCtrl:       gomock.NewController(t),
+			}
+			mocks.mockPDClient = mockpd.NewMockClient(mocks.mockCtrl)
+			mocks.mockPDClient.EXPECT().FindServiceByName(gomock.Any()).Return(nil, nil).AnyTimes()
+			mocks.mockPDClient.EXPECT().RecreateMissingIntegration(gomock.Any()).Return(false, nil).AnyTimes()
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act
+			for i := 0; i < 2; i++ {
+				_, err := rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testPagerDutyIntegrationName,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+				assert.NoError(t, err)
+			}
+
+			// Assert
+			pdi := &pagerdutyv1alpha1.PagerDutyIntegration{}
+			err := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testPagerDutyIntegrationName, Namespace: config.OperatorNamespace}, pdi)
+			assert.NoError(t, err)
+			if assert.Len(t, pdi.Status.Conditions, 1) {
+				condition := pdi.Status.Conditions[0]
+				assert.Equal(t, pagerdutyv1alpha1.PagerDutyIntegrationConditionReferencesValid, condition.Type)
+				assert.Equal(t, test.expectStatus, condition.Status)
+				assert.Equal(t, test.expectReason, condition.Reason)
+				assert.Equal(t, test.expectMessage, condition.Message)
+			}
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationTestAlert(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	interval := 2 * time.Hour
+	tests := []struct {
+		name           string
+		lastTest       time.Time
+		result         pd.TestAlertResult
+		sendErr        error
+		expectCalls    int
+		expectAccepted bool
+		expectMessage  string
+	}{
+		{
+			name:           "Test Alert Due, Accepted",
+			result:         pd.TestAlertResult{DedupKey: "test-dedup-key", Accepted: true, Latency: 300 * time.Millisecond},
+			expectCalls:    1,
+			expectAccepted: true,
+		},
+		{
+			name:           "Test Alert Not Due",
+			lastTest:       time.Now().Add(-30 * time.Minute),
+			expectCalls:    0,
+			expectAccepted: true,
+		},
+		{
+			name:          "Test Alert Due, Not Accepted",
+			lastTest:      time.Now().Add(-interval - time.Minute),
+			result:        pd.TestAlertResult{DedupKey: "test-dedup-key", Latency: 300 * time.Millisecond},
+			sendErr:       fmt.Errorf("HTTP response code: 400"),
+			expectCalls:   1,
+			expectMessage: "HTTP response code: 400",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.TestAlertInterval = &metav1.Duration{Duration: interval}
+			if !test.lastTest.IsZero() {
+				pdi.Status.Clusters = []pagerdutyv1alpha1.ClusterStatus{
+					{
+						ClusterDeploymentNamespace: testNamespace,
+						ClusterDeploymentName:      testClusterName,
+						LastVerifiedTime:           &metav1.Time{Time: time.Now()},
+						TestAlert: &pagerdutyv1alpha1.TestAlertStatus{
+							LastTestTime: metav1.Time{Time: test.lastTest},
+							DedupKey:     "previous-dedup-key",
+							Accepted:     true,
+						},
+					},
+				}
+			}
+
+			mocks := setupDefaultMocks(t, []runtime.Object{
+				testClusterDeployment(true, true, true, false),
+				testPDISecret(),
+				pdi,
+				testCDConfigMap(),
+				testCDSyncSet(),
+				testCDSecret(),
+			})
+			mocks.mockPDClient.EXPECT().SendTestAlert(testIntegrationID, testClusterName).Return(test.result, test.sendErr).Times(test.expectCalls)
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			}
+
+			// Act, twice to confirm the second run doesn't test again
+			_, err1 := rpdi.Reconcile(request)
+			result, err2 := rpdi.Reconcile(request)
+
+			// Assert
+			assert.NoError(t, err1)
+			assert.NoError(t, err2)
+			assert.True(t, result.RequeueAfter <= interval)
+
+			err := mocks.fakeKubeClient.Get(context.TODO(), request.NamespacedName, pdi)
+			assert.NoError(t, err)
+			assert.Len(t, pdi.Status.Clusters, 1)
+			testAlert := pdi.Status.Clusters[0].TestAlert
+			if !assert.NotNil(t, testAlert) {
+				return
+			}
+			assert.Equal(t, test.expectAccepted, testAlert.Accepted)
+			assert.Equal(t, test.expectMessage, testAlert.Message)
+			if test.expectCalls == 0 {
+				assert.Equal(t, "previous-dedup-key", testAlert.DedupKey)
+				return
+			}
+			assert.Equal(t, test.result.DedupKey, testAlert.DedupKey)
+			assert.Equal(t, test.result.Latency, testAlert.Latency.Duration)
+			assert.True(t, time.Since(testAlert.LastTestTime.Time) < time.Minute)
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationReinstallServiceRetention(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	retained := func(retainedAt time.Time) pagerdutyv1alpha1.RetainedService {
+		return pagerdutyv1alpha1.RetainedService{
+			ClusterDeploymentNamespace: testNamespace,
+			ClusterDeploymentName:      testClusterName,
+			ClusterID:                  testClusterName,
+			ServiceID:                  testServiceID,
+			IntegrationID:              testIntegrationID,
+			RetainedAt:                 metav1.Time{Time: retainedAt},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		localObjects    []runtime.Object
+		retained        []pagerdutyv1alpha1.RetainedService
+		setupPDMock     func(*mockpd.MockClientMockRecorder)
+		expectRetained  int
+		expectConfigMap bool
+	}{
+		{
+			name: "Test Deleted Cluster Retains Service",
+			localObjects: []runtime.Object{
+				testClusterDeployment(true, true, true, true),
+				testCDConfigMap(),
+				testCDSecret(),
+				testCDSyncSet(),
+			},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DeleteService(gomock.Any()).Times(0)
+			},
+			expectRetained: 1,
+		},
+		{
+			name: "Test Reinstalled Cluster Reuses Service",
+			localObjects: []runtime.Object{
+				testClusterDeployment(true, true, true, false),
+			},
+			retained: []pagerdutyv1alpha1.RetainedService{retained(time.Now())},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Times(0)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectRetained:  0,
+			expectConfigMap: true,
+		},
+		{
+			name:     "Test Retained Service Expires",
+			retained: []pagerdutyv1alpha1.RetainedService{retained(time.Now().Add(-2 * time.Hour))},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DeleteService(&pd.Data{ServiceID: testServiceID, IntegrationID: testIntegrationID}).Return(nil).Times(1)
+			},
+			expectRetained: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.ReinstallServiceRetention = &metav1.Duration{Duration: time.Hour}
+			pdi.Status.RetainedServices = test.retained
+
+			mocks := setupDefaultMocks(t, append(test.localObjects, testPDISecret(), pdi))
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			}
+
+			// Act
+			for i := 0; i < 2; i++ {
+				_, err := rpdi.Reconcile(request)
+				assert.NoError(t, err)
+			}
+
+			// Assert
+			pdi = &pagerdutyv1alpha1.PagerDutyIntegration{}
+			err := mocks.fakeKubeClient.Get(context.TODO(), request.NamespacedName, pdi)
+			assert.NoError(t, err)
+			assert.Len(t, pdi.Status.RetainedServices, test.expectRetained)
+			if test.expectRetained > 0 {
+				assert.Equal(t, testServiceID, pdi.Status.RetainedServices[0].ServiceID)
+				assert.Equal(t, testIntegrationID, pdi.Status.RetainedServices[0].IntegrationID)
+			}
+
+			if test.expectConfigMap {
+				cm := &corev1.ConfigMap{}
+				err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: naming.ConfigMapName(testServicePrefix, testClusterName)}, cm)
+				assert.NoError(t, err)
+				assert.Equal(t, testServiceID, cm.Data["SERVICE_ID"])
+				assert.Equal(t, testIntegrationID, cm.Data["INTEGRATION_ID"])
+			}
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationDeprovisionGracePeriod(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	retained := func(retainedAt time.Time) pagerdutyv1alpha1.RetainedService {
+		return pagerdutyv1alpha1.RetainedService{
+			ClusterDeploymentNamespace: testNamespace,
+			ClusterDeploymentName:      testClusterName,
+			ClusterID:                  testClusterName,
+			ServiceID:                  testServiceID,
+			IntegrationID:              testIntegrationID,
+			RetainedAt:                 metav1.Time{Time: retainedAt},
+			Disabled:                   true,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		localObjects   []runtime.Object
+		retained       []pagerdutyv1alpha1.RetainedService
+		setupPDMock    func(*mockpd.MockClientMockRecorder)
+		expectRetained int
+		expectDisabled bool
+	}{
+		{
+			name: "Test Deleted Cluster Disables Service",
+			localObjects: []runtime.Object{
+				testClusterDeployment(true, true, true, true),
+				testCDConfigMap(),
+				testCDSecret(),
+				testCDSyncSet(),
+			},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DisableService(gomock.Any()).Return(nil).Times(1)
+				r.DeleteService(gomock.Any()).Times(0)
+			},
+			expectRetained: 1,
+			expectDisabled: true,
+		},
+		{
+			name: "Test Failing To Disable Still Retains Service",
+			localObjects: []runtime.Object{
+				testClusterDeployment(true, true, true, true),
+				testCDConfigMap(),
+				testCDSecret(),
+				testCDSyncSet(),
+			},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DisableService(gomock.Any()).Return(fmt.Errorf("Failed call API endpoint. HTTP response code: 500")).Times(1)
+				r.DeleteService(gomock.Any()).Times(0)
+			},
+			expectRetained: 1,
+		},
+		{
+			name: "Test Reinstalled Cluster Enables Service",
+			localObjects: []runtime.Object{
+				testClusterDeployment(true, true, true, false),
+			},
+			retained: []pagerdutyv1alpha1.RetainedService{retained(time.Now())},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.EnableService(&pd.Data{ServiceID: testServiceID, IntegrationID: testIntegrationID}).Return(nil).Times(1)
+				r.CreateService(gomock.Any()).Times(0)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectRetained: 0,
+		},
+		{
+			name:     "Test Service Deleted Once Grace Period Is Over",
+			retained: []pagerdutyv1alpha1.RetainedService{retained(time.Now().Add(-2 * time.Hour))},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DeleteService(&pd.Data{ServiceID: testServiceID, IntegrationID: testIntegrationID}).Return(nil).Times(1)
+			},
+			expectRetained: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.DeprovisionGracePeriod = &metav1.Duration{Duration: time.Hour}
+			pdi.Status.RetainedServices = test.retained
+
+			mocks := setupDefaultMocks(t, append(test.localObjects, testPDISecret(), pdi))
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			}
+
+			// Act
+			for i := 0; i < 2; i++ {
+				_, err := rpdi.Reconcile(request)
+				assert.NoError(t, err)
+			}
+
+			// Assert
+			pdi = &pagerdutyv1alpha1.PagerDutyIntegration{}
+			err := mocks.fakeKubeClient.Get(context.TODO(), request.NamespacedName, pdi)
+			assert.NoError(t, err)
+			assert.Len(t, pdi.Status.RetainedServices, test.expectRetained)
+			if test.expectRetained > 0 {
+				assert.Equal(t, testServiceID, pdi.Status.RetainedServices[0].ServiceID)
+				assert.Equal(t, test.expectDisabled, pdi.Status.RetainedServices[0].Disabled)
+			}
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationServiceDeletionPolicy(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name        string
+		policy      pagerdutyv1alpha1.ServiceDeletionPolicy
+		setupPDMock func(*mockpd.MockClientMockRecorder)
+	}{
+		{
+			name:   "Test Retain Policy Leaves Service",
+			policy: pagerdutyv1alpha1.ServiceDeletionPolicyRetain,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DisableService(gomock.Any()).Times(0)
+				r.DeleteService(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:   "Test Disable Policy Disables Service",
+			policy: pagerdutyv1alpha1.ServiceDeletionPolicyDisable,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DisableService(gomock.Any()).Return(nil).Times(1)
+				r.DeleteService(gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.ServiceDeletionPolicy = test.policy
+
+			mocks := setupDefaultMocks(t, []runtime.Object{
+				testClusterDeployment(true, true, true, true),
+				testCDConfigMap(),
+				testCDSecret(),
+				testCDSyncSet(),
+				testPDISecret(),
+				pdi,
+			})
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			}
+
+			// Act
+			_, err := rpdi.Reconcile(request)
+			assert.NoError(t, err)
+
+			// Assert
+			pdi = &pagerdutyv1alpha1.PagerDutyIntegration{}
+			err = mocks.fakeKubeClient.Get(context.TODO(), request.NamespacedName, pdi)
+			assert.NoError(t, err)
+			assert.Empty(t, pdi.Status.RetainedServices)
+
+			cm := &corev1.ConfigMap{}
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: naming.ConfigMapName(testServicePrefix, testClusterName)}, cm)
+			assert.True(t, errors.IsNotFound(err))
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationLostConfigMap(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name         string
+		setupPDMock  func(*mockpd.MockClientMockRecorder)
+		expectLeaked bool
+		expectEvents []string
+	}{
+		{
+			name: "Service Found By Name Is Deleted",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.FindServiceByName(gomock.Any()).Return(testPDService(), nil).Times(1)
+				r.DeleteService(gomock.Any()).DoAndReturn(func(data *pd.Data) error {
+					assert.Equal(t, testServiceID, data.ServiceID)
+					return nil
+				}).Times(1)
+			},
+			expectEvents: []string{eventPDServiceDeleted, eventPDServiceDeleted},
+		},
+		{
+			name: "No Service Found",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.FindServiceByName(gomock.Any()).Return(nil, nil).Times(1)
+				r.DeleteService(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Lookup Failed",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.FindServiceByName(gomock.Any()).Return(nil, fmt.Errorf("HTTP response code: 500")).Times(1)
+				r.DeleteService(gomock.Any()).Times(0)
+			},
+			expectLeaked: true,
+			expectEvents: []string{eventPDServiceLeaked, eventPDServiceLeaked},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			mocks := &mocks{
+				fakeKubeClient: fakekubeclient.NewFakeClient(testClusterDeployment(true, true, true, true), testPDISecret(), testPagerDutyIntegration()),
+				mockCtrl:       gomock.NewController(t),
+			}
+			mocks.mockPDClient = mockpd.NewMockClient(mocks.mockCtrl)
+			mocks.mockPDClient.EXPECT().ValidateReferences(gomock.Any()).Return(nil, nil).AnyTimes()
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			recorder := record.NewFakeRecorder(10)
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+				recorder: recorder,
+			}
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			}
+
+			// Act
+			_, err := rpdi.Reconcile(request)
+
+			// Assert
+			assert.NoError(t, err)
+			pdi := &pagerdutyv1alpha1.PagerDutyIntegration{}
+			err = mocks.fakeKubeClient.Get(context.TODO(), request.NamespacedName, pdi)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectLeaked, leakedService(pdi) != nil)
+
+			// the cluster is torn down either way
+			cd := &hivev1.ClusterDeployment{}
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testClusterName, Namespace: testNamespace}, cd)
+			assert.NoError(t, err)
+			assert.False(t, utils.HasFinalizer(cd, config.PagerDutyFinalizerPrefix+testPagerDutyIntegrationName))
+
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, strings.Fields(<-recorder.Events)[1])
+			}
+			assert.Equal(t, test.expectEvents, events)
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationAccountMigration(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	const (
+		migrationAPIKeySecretName = "pagerduty-api-key-new"
+		migrationEscalationPolicy = "new-escalation-policy"
+		migrationServiceID        = "GHI789"
+		migrationIntegrationKey   = "new-integration-key"
+	)
+
+	migrationAPIKeySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: config.OperatorNamespace,
+			Name:      migrationAPIKeySecretName,
+		},
+		Data: map[string][]byte{
+			config.PagerDutyAPISecretKey: []byte("new-pd-api-key"),
+		},
+	}
+	migrationConfigMap := kube.GenerateConfigMap(testNamespace, naming.MigrationConfigMapName(testServicePrefix, testClusterName), migrationServiceID, testIntegrationID)
+
+	tests := []struct {
+		name            string
+		localObjects    []runtime.Object
+		setupPDMock     func(*mockpd.MockClientMockRecorder)
+		expectSecret    bool
+		expectConfigMap bool
+	}{
+		{
+			name: "Test Services Created In Both Accounts",
+			localObjects: []runtime.Object{
+				testClusterDeployment(true, true, true, false),
+			},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(2)
+				r.GetIntegrationKey(gomock.Any()).DoAndReturn(func(data *pd.Data) (string, error) {
+					if data.EscalationPolicyID == migrationEscalationPolicy {
+						return migrationIntegrationKey, nil
+					}
+					return testIntegrationID, nil
+				}).Times(2)
+			},
+			expectSecret:    true,
+			expectConfigMap: true,
+		},
+		{
+			name: "Test Services Deleted In Both Accounts",
+			localObjects: []runtime.Object{
+				testClusterDeployment(true, true, true, true),
+				testCDConfigMap(),
+				migrationConfigMap,
+				testCDSecret(),
+				testCDSyncSet(),
+			},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DeleteService(gomock.Any()).Return(nil).Times(2)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.AccountMigration = &pagerdutyv1alpha1.AccountMigration{
+				PagerdutyApiKeySecretRef: corev1.SecretReference{Namespace: config.OperatorNamespace, Name: migrationAPIKeySecretName},
+				EscalationPolicy:         migrationEscalationPolicy,
+			}
+
+			mocks := setupDefaultMocks(t, append(test.localObjects, testPDISecret(), migrationAPIKeySecret, pdi))
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act
+			for i := 0; i < 2; i++ {
+				_, err := rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testPagerDutyIntegrationName,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+				assert.NoError(t, err)
+			}
+
+			// Assert
+			secret := &corev1.Secret{}
+			err := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: naming.SecretName(testServicePrefix, testClusterName)}, secret)
+			if test.expectSecret {
+				assert.NoError(t, err)
+				assert.Equal(t, testIntegrationID, string(secret.Data[config.PagerDutySecretKey]))
+				assert.Equal(t, migrationIntegrationKey, string(secret.Data[config.PagerDutyMigrationSecretKey]))
+			} else {
+				assert.True(t, errors.IsNotFound(err))
+			}
+
+			cm := &corev1.ConfigMap{}
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: naming.MigrationConfigMapName(testServicePrefix, testClusterName)}, cm)
+			if test.expectConfigMap {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, cm.Data["SERVICE_ID"])
+			} else {
+				assert.True(t, errors.IsNotFound(err))
+			}
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationAccountMigrationDecommission(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	const (
+		migrationAPIKeySecretName = "pagerduty-api-key-new"
+		migrationServiceID        = "GHI789"
+		migrationIntegrationID    = "JKL012"
+		migrationIntegrationKey   = "new-integration-key"
+	)
+
+	// Arrange
+	migrationAPIKeySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: config.OperatorNamespace,
+			Name:      migrationAPIKeySecretName,
+		},
+		Data: map[string][]byte{
+			config.PagerDutyAPISecretKey: []byte("new-pd-api-key"),
+		},
+	}
+	cdSecret := testCDSecret()
+	cdSecret.Data[config.PagerDutyMigrationSecretKey] = []byte(migrationIntegrationKey)
+
+	pdi := testPagerDutyIntegration()
+	pdi.Spec.AccountMigration = &pagerdutyv1alpha1.AccountMigration{
+		PagerdutyApiKeySecretRef: corev1.SecretReference{Namespace: config.OperatorNamespace, Name: migrationAPIKeySecretName},
+		EscalationPolicy:         "new-escalation-policy",
+		Phase:                    pagerdutyv1alpha1.MigrationPhaseDecommission,
+		DecommissionBatchSize:    1,
+	}
+
+	mocks := setupDefaultMocks(t, []runtime.Object{
+		testClusterDeployment(true, true, true, false),
+		testPDISecret(),
+		migrationAPIKeySecret,
+		pdi,
+		testCDConfigMap(),
+		kube.GenerateConfigMap(testNamespace, naming.MigrationConfigMapName(testServicePrefix, testClusterName), migrationServiceID, migrationIntegrationID),
+		cdSecret,
+		testCDSyncSet(),
+	})
+	gomock.InOrder(
+		mocks.mockPDClient.EXPECT().DisableService(&pd.Data{ServiceID: testServiceID, IntegrationID: testIntegrationID}).Return(nil).Times(1),
+		mocks.mockPDClient.EXPECT().DeleteService(&pd.Data{ServiceID: testServiceID, IntegrationID: testIntegrationID}).Return(nil).Times(1),
+	)
+	defer mocks.mockCtrl.Finish()
+
+	rpdi := &ReconcilePagerDutyIntegration{
+		client:   mocks.fakeKubeClient,
+		scheme:   scheme.Scheme,
+		pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+	}
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      testPagerDutyIntegrationName,
+			Namespace: config.OperatorNamespace,
+		},
+	}
+
+	// Act, one batch disables the service and the next one deletes it
+	for i := 0; i < 3; i++ {
+		_, err := rpdi.Reconcile(request)
+		assert.NoError(t, err)
+	}
+
+	// Assert
+	pdi = &pagerdutyv1alpha1.PagerDutyIntegration{}
+	err := mocks.fakeKubeClient.Get(context.TODO(), request.NamespacedName, pdi)
+	assert.NoError(t, err)
+	assert.Equal(t, &pagerdutyv1alpha1.AccountMigrationStatus{Clusters: 1, ServicesDeleted: 1}, pdi.Status.AccountMigration)
+
+	cm := &corev1.ConfigMap{}
+	err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: naming.ConfigMapName(testServicePrefix, testClusterName)}, cm)
+	assert.NoError(t, err)
+	assert.Equal(t, "deleted", cm.Annotations[config.DecommissionAnnotation])
+
+	// Act, completing the migration promotes the service of the new account
+	pdi.Spec.AccountMigration = nil
+	err = mocks.fakeKubeClient.Update(context.TODO(), pdi)
+	assert.NoError(t, err)
+	mocks.mockPDClient.EXPECT().GetIntegrationKey(&pd.Data{
+		ClusterID:          testClusterName,
+		EscalationPolicyID: testEscalationPolicy,
+		AutoResolveTimeout: testResolveTimeout,
+		AcknowledgeTimeOut: testAcknowledgeTimeout,
+		ServicePrefix:      testServicePrefix,
+		APIKey:             testAPIKey,
+		Metadata:           &pd.ClusterMetadata{},
+		ServiceID:          migrationServiceID,
+		IntegrationID:      migrationIntegrationID,
+	}).Return(migrationIntegrationKey, nil).Times(1)
+	for i := 0; i < 2; i++ {
+		_, err = rpdi.Reconcile(request)
+		assert.NoError(t, err)
+	}
+
+	// Assert
+	cm = &corev1.ConfigMap{}
+	err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: naming.ConfigMapName(testServicePrefix, testClusterName)}, cm)
+	assert.NoError(t, err)
+	assert.Equal(t, migrationServiceID, cm.Data["SERVICE_ID"])
+	assert.NotContains(t, cm.Annotations, config.DecommissionAnnotation)
+
+	err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: naming.MigrationConfigMapName(testServicePrefix, testClusterName)}, &corev1.ConfigMap{})
+	assert.True(t, errors.IsNotFound(err))
+
+	secret := &corev1.Secret{}
+	err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: naming.SecretName(testServicePrefix, testClusterName)}, secret)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{config.PagerDutySecretKey: []byte(migrationIntegrationKey)}, secret.Data)
+
+	pdi = &pagerdutyv1alpha1.PagerDutyIntegration{}
+	err = mocks.fakeKubeClient.Get(context.TODO(), request.NamespacedName, pdi)
+	assert.NoError(t, err)
+	assert.Nil(t, pdi.Status.AccountMigration)
+}
+
+func TestReconcilePagerDutyIntegrationEscrowSecret(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	const escrowSecretName = "pagerduty-escrow"
+	escrowKey := testNamespace + "." + testClusterName
+	otherEscrowKey := "other-namespace.other-cluster"
+
+	tests := []struct {
+		name         string
+		localObjects []runtime.Object
+		setupPDMock  func(*mockpd.MockClientMockRecorder)
+		expectData   map[string][]byte
+	}{
+		{
+			name: "Test Key Escrowed In New Secret",
+			localObjects: []runtime.Object{
+				testClusterDeployment(true, true, true, false),
+			},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectData: map[string][]byte{escrowKey: []byte(testIntegrationID)},
+		},
+		{
+			name: "Test Key Escrowed Next To Others",
+			localObjects: []runtime.Object{
+				testClusterDeployment(true, true, true, false),
+				testCDConfigMap(),
+				testCDSecret(),
+				testCDSyncSet(),
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: config.OperatorNamespace, Name: escrowSecretName},
+					Data:       map[string][]byte{otherEscrowKey: []byte("other-key")},
+				},
+			},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {},
+			expectData:  map[string][]byte{escrowKey: []byte(testIntegrationID), otherEscrowKey: []byte("other-key")},
+		},
+		{
+			name: "Test Key Removed With Cluster",
+			localObjects: []runtime.Object{
+				testClusterDeployment(true, true, true, true),
+				testCDConfigMap(),
+				testCDSecret(),
+				testCDSyncSet(),
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: config.OperatorNamespace, Name: escrowSecretName},
+					Data:       map[string][]byte{escrowKey: []byte(testIntegrationID), otherEscrowKey: []byte("other-key")},
+				},
+			},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DeleteService(gomock.Any()).Return(nil).Times(1)
+			},
+			expectData: map[string][]byte{otherEscrowKey: []byte("other-key")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.EscrowSecretRef = &corev1.SecretReference{Namespace: config.OperatorNamespace, Name: escrowSecretName}
+
+			mocks := setupDefaultMocks(t, append(test.localObjects, testPDISecret(), pdi))
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act
+			for i := 0; i < 2; i++ {
+				_, err := rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testPagerDutyIntegrationName,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+				assert.NoError(t, err)
+			}
+
+			// Assert
+			escrow := &corev1.Secret{}
+			err := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: config.OperatorNamespace, Name: escrowSecretName}, escrow)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectData, escrow.Data)
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationClusterListing(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	applied := &hiveintv1alpha1.ClusterSync{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testClusterName,
+			Namespace: testNamespace,
+		},
+		Status: hiveintv1alpha1.ClusterSyncStatus{
+			SyncSets: []hiveintv1alpha1.SyncStatus{
+				{
+					Name:   naming.SyncSetName(testServicePrefix, testClusterName),
+					Result: hiveintv1alpha1.SuccessSyncSetResult,
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		localObjects    []runtime.Object
+		setupPDMock     func(*mockpd.MockClientMockRecorder)
+		expectServiceID string
+		expectState     pagerdutyv1alpha1.ClusterState
+		expectLastError string
+		expectCounts    [3]int
+	}{
+		{
+			name: "Test Cluster Ready",
+			localObjects: []runtime.Object{
+				testClusterDeployment(true, true, true, false),
+				applied,
+			},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectServiceID: "XYZ123",
+			expectState:     pagerdutyv1alpha1.ClusterStateReady,
+			expectCounts:    [3]int{1, 0, 0},
+		},
+		{
+			name: "Test Cluster Pending Secret Sync",
+			localObjects: []runtime.Object{
+				testClusterDeployment(true, true, true, false),
+			},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectServiceID: "XYZ123",
+			expectState:     pagerdutyv1alpha1.ClusterStatePending,
+			expectCounts:    [3]int{0, 1, 0},
+		},
+		{
+			name: "Test Cluster Failed",
+			localObjects: []runtime.Object{
+				testClusterDeployment(true, true, true, false),
+			},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return("", fmt.Errorf("HTTP response code: 500")).Times(2)
+			},
+			expectState:     pagerdutyv1alpha1.ClusterStateFailed,
+			expectLastError: "HTTP response code: 500",
+			expectCounts:    [3]int{0, 0, 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			mocks := setupDefaultMocks(t, append(test.localObjects, testPDISecret(), testPagerDutyIntegration()))
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act
+			for i := 0; i < 2; i++ {
+				_, _ = rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testPagerDutyIntegrationName,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+			}
+
+			// Assert
+			pdi := &pagerdutyv1alpha1.PagerDutyIntegration{}
+			err := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testPagerDutyIntegrationName, Namespace: config.OperatorNamespace}, pdi)
+			assert.NoError(t, err)
+			if assert.Len(t, pdi.Status.Clusters, 1) {
+				cluster := pdi.Status.Clusters[0]
+				assert.Equal(t, test.expectServiceID, cluster.ServiceID)
+				assert.Equal(t, test.expectState, cluster.State)
+				assert.Contains(t, cluster.LastError, test.expectLastError)
+			}
+			assert.Equal(t, test.expectCounts, [3]int{pdi.Status.ReadyClusters, pdi.Status.PendingClusters, pdi.Status.FailedClusters})
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationAdditionalServices(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	const (
+		additionalPrefix           = "customer"
+		additionalEscalationPolicy = "customer-escalation-policy"
+		additionalServiceID        = "GHI789"
+	)
+	additionalService := pagerdutyv1alpha1.AdditionalService{
+		ServicePrefix:    additionalPrefix,
+		EscalationPolicy: additionalEscalationPolicy,
+		ClusterDeploymentSelector: metav1.LabelSelector{
+			MatchLabels: map[string]string{config.ClusterDeploymentManagedLabel: "true"},
+		},
+		TargetSecretRef: corev1.SecretReference{Name: "customer-pagerduty", Namespace: "openshift-monitoring"},
+	}
+	additionalFinalizer := config.PagerDutyFinalizerPrefix + testPagerDutyIntegrationName + "." + additionalPrefix
+
+	// the resources of an additional service already set up
+	additionalResources := func() []runtime.Object {
+		pdi := additionalServiceIntegration(testPagerDutyIntegration(), additionalService)
+		cm := kube.GenerateConfigMap(testNamespace, naming.ConfigMapName(additionalPrefix, testClusterName), additionalServiceID, testIntegrationID)
+		secret := kube.GeneratePdSecret(testNamespace, naming.SecretName(additionalPrefix, testClusterName), testIntegrationID, pdi)
+		ss := kube.GenerateSyncSet(testNamespace, naming.SyncSetName(additionalPrefix, testClusterName), testClusterName, secret, pdi)
+		return []runtime.Object{cm, secret, ss}
+	}
+	clusterDeployment := func(isDeleting bool) *hivev1.ClusterDeployment {
+		cd := testClusterDeployment(true, true, true, isDeleting)
+		cd.Finalizers = append(cd.Finalizers, additionalFinalizer)
+		return cd
+	}
+
+	tests := []struct {
+		name               string
+		localObjects       []runtime.Object
+		additionalServices []pagerdutyv1alpha1.AdditionalService
+		setupPDMock        func(*mockpd.MockClientMockRecorder)
+		expectAdditional   bool
+		expectFinalizer    bool
+	}{
+		{
+			name: "Test Additional Service Created",
+			localObjects: []runtime.Object{
+				testClusterDeployment(true, true, true, false),
+			},
+			additionalServices: []pagerdutyv1alpha1.AdditionalService{additionalService},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).DoAndReturn(func(data *pd.Data) (string, error) {
+					if data.ServicePrefix == additionalPrefix {
+						assert.Equal(t, additionalEscalationPolicy, data.EscalationPolicyID)
+					} else {
+						assert.Equal(t, testEscalationPolicy, data.EscalationPolicyID)
+					}
+					return testIntegrationID, nil
+				}).Times(2)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(2)
+			},
+			expectAdditional: true,
+			expectFinalizer:  true,
+		},
+		{
+			name: "Test Additional Service Deleted With Cluster",
+			localObjects: append(additionalResources(),
+				clusterDeployment(true), testCDConfigMap(), testCDSecret(), testCDSyncSet()),
+			additionalServices: []pagerdutyv1alpha1.AdditionalService{additionalService},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DeleteService(gomock.Any()).Return(nil).Times(2)
+			},
+		},
+		{
+			name: "Test Additional Service Removed From Spec",
+			localObjects: append(additionalResources(),
+				clusterDeployment(false), testCDConfigMap(), testCDSecret(), testCDSyncSet()),
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DeleteService(gomock.Any()).DoAndReturn(func(data *pd.Data) error {
+					assert.Equal(t, additionalServiceID, data.ServiceID)
+					return nil
+				}).Times(1)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.AdditionalServices = test.additionalServices
+
+			mocks := setupDefaultMocks(t, append(test.localObjects, testPDISecret(), pdi))
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act
+			for i := 0; i < 2; i++ {
+				_, err := rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testPagerDutyIntegrationName,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+				assert.NoError(t, err)
+			}
+
+			// Assert
+			cm := &corev1.ConfigMap{}
+			err := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: naming.ConfigMapName(additionalPrefix, testClusterName)}, cm)
+			secret := &corev1.Secret{}
+			secretErr := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: naming.SecretName(additionalPrefix, testClusterName)}, secret)
+			ss := &hivev1.SyncSet{}
+			ssErr := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: naming.SyncSetName(additionalPrefix, testClusterName)}, ss)
+			if test.expectAdditional {
+				assert.NoError(t, err)
+				assert.Equal(t, testPagerDutyIntegrationName+"."+additionalPrefix, cm.Labels[config.PagerDutyIntegrationLabel])
+				assert.NoError(t, secretErr)
+				assert.NoError(t, ssErr)
+				if assert.Len(t, ss.Spec.Secrets, 1) {
+					assert.Equal(t, additionalService.TargetSecretRef.Name, ss.Spec.Secrets[0].TargetRef.Name)
+				}
+			} else {
+				assert.True(t, errors.IsNotFound(err))
+				assert.True(t, errors.IsNotFound(secretErr))
+				assert.True(t, errors.IsNotFound(ssErr))
+			}
+
+			cd := &hivev1.ClusterDeployment{}
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testClusterName}, cd)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectFinalizer, utils.HasFinalizer(cd, additionalFinalizer))
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationClusterConditions(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	applied := &hiveintv1alpha1.ClusterSync{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testClusterName,
+			Namespace: testNamespace,
+		},
+		Status: hiveintv1alpha1.ClusterSyncStatus{
+			SyncSets: []hiveintv1alpha1.SyncStatus{
+				{
+					Name:   naming.SyncSetName(testServicePrefix, testClusterName),
+					Result: hiveintv1alpha1.SuccessSyncSetResult,
+				},
+			},
+		},
+	}
+	deprovisioning := testClusterDeployment(true, true, true, true)
+	deprovisioning.Finalizers = append(deprovisioning.Finalizers, "hive.openshift.io/deprovision")
+
+	tests := []struct {
+		name              string
+		clusterDeployment *hivev1.ClusterDeployment
+		lastVerified      time.Time
+		setupPDMock       func(*mockpd.MockClientMockRecorder)
+		expectState       pagerdutyv1alpha1.ClusterState
+		expectReady       corev1.ConditionStatus
+		expectReason      string
+		expectDegraded    corev1.ConditionStatus
+		expectDeleting    corev1.ConditionStatus
+	}{
+		{
+			name:              "Test Ready",
+			clusterDeployment: testClusterDeployment(true, true, true, false),
+			setupPDMock:       func(r *mockpd.MockClientMockRecorder) {},
+			expectState:       pagerdutyv1alpha1.ClusterStateReady,
+			expectReady:       corev1.ConditionTrue,
+			expectReason:      "IntegrationReady",
+			expectDegraded:    corev1.ConditionFalse,
+		},
+		{
+			name:              "Test Degraded",
+			clusterDeployment: testClusterDeployment(true, true, true, false),
+			lastVerified:      time.Now().Add(-config.ResyncPeriod - time.Hour),
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.GetService(gomock.Any()).Return(nil, fmt.Errorf("Failed call API endpoint. HTTP response code: 404. Error: &{2100 Not Found []}")).Times(1)
+			},
+			expectState:    pagerdutyv1alpha1.ClusterStateFailed,
+			expectReady:    corev1.ConditionFalse,
+			expectReason:   string(pagerdutyv1alpha1.ClusterConditionServiceVerificationFailed),
+			expectDegraded: corev1.ConditionTrue,
+		},
+		{
+			name:              "Test Deleting",
+			clusterDeployment: deprovisioning,
+			lastVerified:      time.Now(),
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DeleteService(gomock.Any()).Return(nil).Times(1)
+			},
+			expectState:    pagerdutyv1alpha1.ClusterStateDeleting,
+			expectReady:    corev1.ConditionFalse,
+			expectReason:   "Deleting",
+			expectDeleting: corev1.ConditionTrue,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			if !test.lastVerified.IsZero() {
+				pdi.Status.Clusters = []pagerdutyv1alpha1.ClusterStatus{
+					{
+						ClusterDeploymentNamespace: testNamespace,
+						ClusterDeploymentName:      testClusterName,
+						LastVerifiedTime:           &metav1.Time{Time: test.lastVerified},
+					},
+				}
+			}
+
+			mocks := setupDefaultMocks(t, []runtime.Object{
+				test.clusterDeployment,
+				testPDISecret(),
+				pdi,
+				applied,
+				testCDConfigMap(),
+				testCDSyncSet(),
+				testCDSecret(),
+			})
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act
+			for i := 0; i < 2; i++ {
+				_, err := rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testPagerDutyIntegrationName,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+				assert.NoError(t, err)
+			}
+
+			// Assert
+			err := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testPagerDutyIntegrationName, Namespace: config.OperatorNamespace}, pdi)
+			assert.NoError(t, err)
+			if !assert.Len(t, pdi.Status.Clusters, 1) {
+				return
+			}
+			assert.Equal(t, test.expectState, pdi.Status.Clusters[0].State)
+
+			conditions := map[pagerdutyv1alpha1.ClusterConditionType]pagerdutyv1alpha1.ClusterCondition{}
+			for _, condition := range pdi.Status.Clusters[0].Conditions {
+				conditions[condition.Type] = condition
+			}
+			assert.Equal(t, test.expectReady, conditions[pagerdutyv1alpha1.ClusterConditionReady].Status)
+			assert.Equal(t, test.expectReason, conditions[pagerdutyv1alpha1.ClusterConditionReady].Reason)
+			assert.Equal(t, test.expectDegraded, conditions[pagerdutyv1alpha1.ClusterConditionDegraded].Status)
+			assert.Equal(t, test.expectDeleting, conditions[pagerdutyv1alpha1.ClusterConditionDeleting].Status)
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationAlertVolumeAnomaly(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	clusterNames := []string{"cluster-a", "cluster-b", "cluster-c"}
+	serviceIDs := []string{"SVCA", "SVCB", "SVCC"}
+
+	// installed clusters whose services are already set up
+	clusterObjects := func(pdi *pagerdutyv1alpha1.PagerDutyIntegration) []runtime.Object {
+		objects := []runtime.Object{}
+		for i, name := range clusterNames {
+			cd := testClusterDeployment(true, true, true, false)
+			cd.Name = name
+			cd.Spec.ClusterName = name
+			secret := kube.GeneratePdSecret(testNamespace, naming.SecretName(testServicePrefix, name), testIntegrationID, pdi)
+			objects = append(objects,
+				cd,
+				kube.GenerateConfigMap(testNamespace, naming.ConfigMapName(testServicePrefix, name), serviceIDs[i], testIntegrationID),
+				secret,
+				kube.GenerateSyncSet(testNamespace, naming.SyncSetName(testServicePrefix, name), name, secret, pdi),
+			)
+		}
+		return objects
+	}
+
+	tests := []struct {
+		name            string
+		lastCount       *metav1.Time
+		previous        []pagerdutyv1alpha1.ClusterStatus
+		setupPDMock     func(*mockpd.MockClientMockRecorder)
+		expectIncidents []int
+		expectAnomalous []bool
+	}{
+		{
+			name: "Test Noisy Cluster Flagged",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CountServiceIncidents(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ids []string, since, until time.Time) (map[string]int, error) {
+					assert.ElementsMatch(t, serviceIDs, ids)
+					assert.Equal(t, config.AlertVolumeDefaultWindow, until.Sub(since))
+					return map[string]int{"SVCA": 1, "SVCB": 2, "SVCC": 40}, nil
+				}).Times(1)
+			},
+			expectIncidents: []int{1, 2, 40},
+			expectAnomalous: []bool{false, false, true},
+		},
+		{
+			name: "Test Quiet Fleet",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CountServiceIncidents(gomock.Any(), gomock.Any(), gomock.Any()).Return(map[string]int{"SVCA": 0, "SVCB": 0, "SVCC": 3}, nil).Times(1)
+			},
+			expectIncidents: []int{0, 0, 3},
+			expectAnomalous: []bool{false, false, false},
+		},
+		{
+			name:      "Test Count Not Due",
+			lastCount: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+			previous: []pagerdutyv1alpha1.ClusterStatus{
+				{ClusterDeploymentNamespace: testNamespace, ClusterDeploymentName: "cluster-a", AlertVolume: &pagerdutyv1alpha1.AlertVolumeStatus{Incidents: 1}},
+				{ClusterDeploymentNamespace: testNamespace, ClusterDeploymentName: "cluster-b", AlertVolume: &pagerdutyv1alpha1.AlertVolumeStatus{Incidents: 2}},
+				{ClusterDeploymentNamespace: testNamespace, ClusterDeploymentName: "cluster-c", AlertVolume: &pagerdutyv1alpha1.AlertVolumeStatus{Incidents: 40, Anomalous: true, Note: "40 incidents in the last 24h0m0s, the fleet median is 2"}},
+			},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CountServiceIncidents(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectIncidents: []int{1, 2, 40},
+			expectAnomalous: []bool{false, false, true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.AlertVolumeAnomaly = &pagerdutyv1alpha1.AlertVolumeAnomaly{}
+			pdi.Status.LastAlertVolumeTime = test.lastCount
+			pdi.Status.Clusters = test.previous
+
+			mocks := setupDefaultMocks(t, append(clusterObjects(pdi), testPDISecret(), pdi))
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act
+			for i := 0; i < 2; i++ {
+				_, err := rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testPagerDutyIntegrationName,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+				assert.NoError(t, err)
+			}
+
+			// Assert
+			err := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testPagerDutyIntegrationName, Namespace: config.OperatorNamespace}, pdi)
+			assert.NoError(t, err)
+			assert.NotNil(t, pdi.Status.LastAlertVolumeTime)
+			if !assert.Len(t, pdi.Status.Clusters, len(clusterNames)) {
+				return
+			}
+
+			anomalous := 0
+			for i, name := range clusterNames {
+				cluster := findClusterStatus(pdi.Status.Clusters, testNamespace, name)
+				if !assert.NotNil(t, cluster) || !assert.NotNil(t, cluster.AlertVolume) {
+					continue
+				}
+				assert.Equal(t, test.expectIncidents[i], cluster.AlertVolume.Incidents)
+				assert.Equal(t, test.expectAnomalous[i], cluster.AlertVolume.Anomalous)
+				assert.Equal(t, test.expectAnomalous[i], cluster.AlertVolume.Note != "")
+				if test.expectAnomalous[i] {
+					anomalous++
+				}
+			}
+			assert.Equal(t, anomalous, pdi.Status.AnomalousClusters)
+			assert.Equal(t, float64(anomalous), testutil.ToFloat64(localmetrics.MetricPagerDutyIntegrationAnomalousClusters.WithLabelValues(testPagerDutyIntegrationName)))
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationIncidentUrgency(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name               string
+		incidentUrgency    *pagerdutyv1alpha1.IncidentUrgency
+		expectUrgency      string
+		expectOutside      string
+		expectSupportHours *pdApi.SupportHours
+	}{
+		{
+			name: "Test Default Urgency",
+		},
+		{
+			name:            "Test Constant Urgency",
+			incidentUrgency: &pagerdutyv1alpha1.IncidentUrgency{Urgency: pagerdutyv1alpha1.UrgencyHigh},
+			expectUrgency:   "high",
+		},
+		{
+			name: "Test Support Hours Urgency",
+			incidentUrgency: &pagerdutyv1alpha1.IncidentUrgency{
+				Urgency:                    pagerdutyv1alpha1.UrgencyHigh,
+				OutsideSupportHoursUrgency: pagerdutyv1alpha1.UrgencyLow,
+				SupportHours: &pagerdutyv1alpha1.SupportHours{
+					TimeZone:   "Europe/Berlin",
+					StartTime:  "08:00:00",
+					EndTime:    "18:00:00",
+					DaysOfWeek: []uint{1, 2, 3, 4, 5},
+				},
+			},
+			expectUrgency: "high",
+			expectOutside: "low",
+			expectSupportHours: &pdApi.SupportHours{
+				Type:       "fixed_time_per_day",
+				Timezone:   "Europe/Berlin",
+				StartTime:  "08:00:00",
+				EndTime:    "18:00:00",
+				DaysOfWeek: []uint{1, 2, 3, 4, 5},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.IncidentUrgency = test.incidentUrgency
+
+			mocks := setupDefaultMocks(t, []runtime.Object{testClusterDeployment(true, true, true, false), testPDISecret(), pdi})
+			mocks.mockPDClient.EXPECT().CreateService(gomock.Any()).DoAndReturn(func(data *pd.Data) (string, error) {
+				assert.Equal(t, test.expectUrgency, data.Urgency)
+				assert.Equal(t, test.expectOutside, data.OutsideSupportHoursUrgency)
+				assert.Equal(t, test.expectSupportHours, data.SupportHours)
+				return testIntegrationID, nil
+			}).Times(1)
+			mocks.mockPDClient.EXPECT().GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act
+			for i := 0; i < 2; i++ {
+				_, err := rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testPagerDutyIntegrationName,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationIntegrationType(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	for _, integrationType := range []pagerdutyv1alpha1.IntegrationType{"", pagerdutyv1alpha1.IntegrationTypePrometheus} {
+		t.Run(string(integrationType), func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.IntegrationType = integrationType
+
+			mocks := setupDefaultMocks(t, []runtime.Object{testClusterDeployment(true, true, true, false), testPDISecret(), pdi})
+			mocks.mockPDClient.EXPECT().CreateService(gomock.Any()).DoAndReturn(func(data *pd.Data) (string, error) {
+				assert.Equal(t, string(integrationType), data.IntegrationType)
+				return testIntegrationID, nil
+			}).Times(1)
+			mocks.mockPDClient.EXPECT().GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act
+			for i := 0; i < 2; i++ {
+				_, err := rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testPagerDutyIntegrationName,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationEscalationPolicyMapping(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name         string
+		tier         string
+		expectPolicy string
+	}{
+		{
+			name:         "Test Mapped Value",
+			tier:         "gold",
+			expectPolicy: "gold-escalation-policy",
+		},
+		{
+			name:         "Test Unmapped Value",
+			tier:         "bronze",
+			expectPolicy: testEscalationPolicy,
+		},
+		{
+			name:         "Test No Label",
+			expectPolicy: testEscalationPolicy,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.EscalationPolicyMapping = &pagerdutyv1alpha1.EscalationPolicyMapping{
+				LabelKey: "tier",
+				Policies: map[string]string{
+					"gold":   "gold-escalation-policy",
+					"silver": "silver-escalation-policy",
+				},
+			}
+			cd := testClusterDeployment(true, true, true, false)
+			if test.tier != "" {
+				cd.Labels["tier"] = test.tier
+			}
+
+			mocks := setupDefaultMocks(t, []runtime.Object{cd, testPDISecret(), pdi})
+			mocks.mockPDClient.EXPECT().CreateService(gomock.Any()).DoAndReturn(func(data *pd.Data) (string, error) {
+				assert.Equal(t, test.expectPolicy, data.EscalationPolicyID)
+				return testIntegrationID, nil
+			}).Times(1)
+			mocks.mockPDClient.EXPECT().GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act
+			_, err := rpdi.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			})
+
+			// Assert
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationTimeoutAnnotations(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name              string
+		annotations       map[string]string
+		expectResolve     uint
+		expectAcknowledge uint
+	}{
+		{
+			name:              "Test Timeouts Of Spec",
+			expectResolve:     testResolveTimeout,
+			expectAcknowledge: testAcknowledgeTimeout,
+		},
+		{
+			name: "Test Timeouts Overridden",
+			annotations: map[string]string{
+				config.ClusterDeploymentResolveTimeoutAnnotation:     "0",
+				config.ClusterDeploymentAcknowledgeTimeoutAnnotation: "7200",
+			},
+			expectResolve:     0,
+			expectAcknowledge: 7200,
+		},
+		{
+			name: "Test Invalid Override Ignored",
+			annotations: map[string]string{
+				config.ClusterDeploymentResolveTimeoutAnnotation: "-1",
+			},
+			expectResolve:     testResolveTimeout,
+			expectAcknowledge: testAcknowledgeTimeout,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			cd := testClusterDeployment(true, true, true, false)
+			cd.Annotations = test.annotations
+
+			mocks := setupDefaultMocks(t, []runtime.Object{cd, testPDISecret(), testPagerDutyIntegration()})
+			mocks.mockPDClient.EXPECT().CreateService(gomock.Any()).DoAndReturn(func(data *pd.Data) (string, error) {
+				assert.Equal(t, test.expectResolve, data.AutoResolveTimeout)
+				assert.Equal(t, test.expectAcknowledge, data.AcknowledgeTimeOut)
+				return testIntegrationID, nil
+			}).Times(1)
+			mocks.mockPDClient.EXPECT().GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act
+			_, err := rpdi.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			})
+
+			// Assert
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationHeartbeat(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	// Arrange
+	pdi := testPagerDutyIntegration()
+	pdi.Spec.Heartbeat = &pagerdutyv1alpha1.Heartbeat{
+		URL:       "https://heartbeat.example.com",
+		Interval:  metav1.Duration{Duration: time.Hour},
+		SecretKey: "HEARTBEAT",
+	}
+
+	mocks := setupDefaultMocks(t, []runtime.Object{
+		testClusterDeployment(true, true, true, false),
+		testPDISecret(),
+		pdi,
+		testCDConfigMap(),
+		testCDSyncSet(),
+		testCDSecret(),
+	})
+	mocks.mockPDClient.EXPECT().CreateHeartbeatIntegration(gomock.Any()).Return("HB123", nil).Times(1)
+	defer mocks.mockCtrl.Finish()
+
+	rpdi := &ReconcilePagerDutyIntegration{
+		client:   mocks.fakeKubeClient,
+		scheme:   scheme.Scheme,
+		pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+	}
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      testPagerDutyIntegrationName,
+			Namespace: config.OperatorNamespace,
+		},
+	}
+
+	// Act, twice to confirm the second run is a noop
+	_, err1 := rpdi.Reconcile(request)
+	result, err2 := rpdi.Reconcile(request)
+
+	// Assert
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.True(t, result.RequeueAfter <= 2*time.Hour)
+
+	// the cluster gets the URL it checks in at
+	lease := &coordinationv1.Lease{}
+	err := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: naming.HeartbeatLeaseName(testServicePrefix, testClusterName), Namespace: testNamespace}, lease)
+	assert.NoError(t, err)
+	secret := &corev1.Secret{}
+	err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: naming.SecretName(testServicePrefix, testClusterName), Namespace: testNamespace}, secret)
+	assert.NoError(t, err)
+	assert.Equal(t, heartbeat.URL(pdi.Spec.Heartbeat, lease), string(secret.Data["HEARTBEAT"]))
+	assert.Equal(t, testIntegrationID, string(secret.Data[config.PagerDutySecretKey]))
+
+	err = mocks.fakeKubeClient.Get(context.TODO(), request.NamespacedName, pdi)
+	assert.NoError(t, err)
+	assert.Len(t, pdi.Status.Clusters, 1)
+	var missed *pagerdutyv1alpha1.ClusterCondition
+	for i, condition := range pdi.Status.Clusters[0].Conditions {
+		if condition.Type == pagerdutyv1alpha1.ClusterConditionHeartbeatMissed {
+			missed = &pdi.Status.Clusters[0].Conditions[i]
+		}
+	}
+	if assert.NotNil(t, missed) {
+		assert.Equal(t, corev1.ConditionFalse, missed.Status)
+	}
+}
+
+func TestReconcilePagerDutyIntegrationDryRun(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	// Arrange
+	pdi := testPagerDutyIntegration()
+	pdi.Annotations = map[string]string{config.DryRunAnnotation: "true"}
+
+	mocks := setupDefaultMocks(t, []runtime.Object{
+		testClusterDeployment(true, true, false, false),
+		testPDISecret(),
+		pdi,
+	})
+	// nothing reaches PagerDuty but reads
+	defer mocks.mockCtrl.Finish()
+
+	rpdi := &ReconcilePagerDutyIntegration{
+		client:   mocks.fakeKubeClient,
+		scheme:   scheme.Scheme,
+		pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+	}
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      testPagerDutyIntegrationName,
+			Namespace: config.OperatorNamespace,
+		},
+	}
+
+	// Act
+	_, err := rpdi.Reconcile(request)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, rpdi.dryRun)
+	assert.Equal(t, mocks.fakeKubeClient, rpdi.client)
+
+	// nothing was written to the hub
+	cd := &hivev1.ClusterDeployment{}
+	err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testClusterName, Namespace: testNamespace}, cd)
+	assert.NoError(t, err)
+	assert.Empty(t, cd.Finalizers)
+
+	err = mocks.fakeKubeClient.Get(context.TODO(), request.NamespacedName, pdi)
+	assert.NoError(t, err)
+	assert.Empty(t, pdi.Finalizers)
+	assert.Empty(t, pdi.Status.Clusters)
+
+	for _, obj := range []runtime.Object{&corev1.ConfigMap{}, &corev1.Secret{}, &hivev1.SyncSet{}} {
+		name := naming.ConfigMapName(testServicePrefix, testClusterName)
+		switch obj.(type) {
+		case *corev1.Secret:
+			name = naming.SecretName(testServicePrefix, testClusterName)
+		case *hivev1.SyncSet:
+			name = naming.SyncSetName(testServicePrefix, testClusterName)
+		}
+		err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: testNamespace}, obj)
+		assert.True(t, errors.IsNotFound(err), "%T %s", obj, name)
+	}
+}
+
+func TestReconcilePagerDutyIntegrationSelfServiceOnboarding(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	allowed := &pagerdutyv1alpha1.SelfServiceOnboarding{
+		NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"paging": "allowed"}},
+	}
+
+	tests := []struct {
+		name            string
+		policy          *pagerdutyv1alpha1.SelfServiceOnboarding
+		namespaceLabels map[string]string
+		annotated       bool
+		setUp           bool
+		expectEvents    int
+		setupPDMock     func(*mockpd.MockClientMockRecorder)
+	}{
+		{
+			name:            "Test Opted In",
+			policy:          allowed,
+			namespaceLabels: map[string]string{"paging": "allowed"},
+			annotated:       true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+		},
+		{
+			name:      "Test Self-Service Not Enabled",
+			annotated: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:         "Test Namespace Not Allowed",
+			policy:       allowed,
+			annotated:    true,
+			expectEvents: 2,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:            "Test Annotation Removed",
+			policy:          allowed,
+			namespaceLabels: map[string]string{"paging": "allowed"},
+			setUp:           true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Times(0)
+				r.DeleteService(gomock.Any()).Return(nil).Times(1)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.SelfServiceOnboarding = test.policy
+
+			// the cluster isn't selected by its labels
+			cd := testClusterDeployment(true, false, test.setUp, false)
+			if test.annotated {
+				cd.Annotations = map[string]string{config.ClusterDeploymentIntegrationAnnotation: testPagerDutyIntegrationName}
+			}
+
+			objects := []runtime.Object{
+				cd,
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace, Labels: test.namespaceLabels}},
+				testPDISecret(),
+				pdi,
+			}
+			if test.setUp {
+				objects = append(objects, testCDConfigMap(), testCDSyncSet(), testCDSecret())
+			}
+			mocks := setupDefaultMocks(t, objects)
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			recorder := record.NewFakeRecorder(10)
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+				recorder: recorder,
+			}
+
+			// Act, twice as the first reconcile only adds the finalizer
+			for i := 0; i < 2; i++ {
+				_, err := rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testPagerDutyIntegrationName,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+				assert.NoError(t, err)
+			}
+
+			// Assert
+			assert.Len(t, eventsWithReason(recorder, "SelfServiceOnboardingDenied"), test.expectEvents)
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationTemplate(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	template := &pagerdutyv1alpha1.PagerDutyIntegrationTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "common",
+			Namespace: config.OperatorNamespace,
+		},
+		Spec: pagerdutyv1alpha1.PagerDutyIntegrationTemplateSpec{
+			AcknowledgeTimeout: 600,
+			ResolveTimeout:     3600,
+			IncidentUrgency:    &pagerdutyv1alpha1.IncidentUrgency{Urgency: pagerdutyv1alpha1.UrgencyHigh},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		localObjects    []runtime.Object
+		resolveTimeout  uint
+		setupPDMock     func(*mockpd.MockClientMockRecorder)
+		expectFinalizer bool
+	}{
+		{
+			name:         "Test Settings Inherited",
+			localObjects: []runtime.Object{template},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).DoAndReturn(func(data *pd.Data) (string, error) {
+					assert.Equal(t, uint(600), data.AcknowledgeTimeOut)
+					assert.Equal(t, uint(3600), data.AutoResolveTimeout)
+					assert.Equal(t, "high", data.Urgency)
+					return testIntegrationID, nil
+				}).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectFinalizer: true,
+		},
+		{
+			name:           "Test Settings Overridden",
+			localObjects:   []runtime.Object{template},
+			resolveTimeout: 300,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).DoAndReturn(func(data *pd.Data) (string, error) {
+					assert.Equal(t, uint(600), data.AcknowledgeTimeOut)
+					assert.Equal(t, uint(300), data.AutoResolveTimeout)
+					return testIntegrationID, nil
+				}).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectFinalizer: true,
+		},
+		{
+			name: "Test Template Missing",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateService(gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.AcknowledgeTimeout = 0
+			pdi.Spec.ResolveTimeout = test.resolveTimeout
+			pdi.Spec.TemplateRef = &corev1.LocalObjectReference{Name: "common"}
+
+			mocks := setupDefaultMocks(t, append(test.localObjects, testClusterDeployment(true, true, false, false), testPDISecret(), pdi))
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+
+			// Act
+			for i := 0; i < 2; i++ {
+				_, err := rpdi.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testPagerDutyIntegrationName,
+						Namespace: config.OperatorNamespace,
+					},
+				})
+				assert.NoError(t, err)
+			}
+
+			// Assert
+			cd := &hivev1.ClusterDeployment{}
+			err := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testClusterName}, cd)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectFinalizer, utils.HasFinalizer(cd, config.PagerDutyFinalizerPrefix+testPagerDutyIntegrationName))
+		})
+	}
+}
+
+// TestReconcilePagerDutyIntegrationTemplateAnnotationCleared removes the
+// clear annotations without writing the inherited settings into the spec, so
+// a later change of the template still reaches the PDI
+func TestReconcilePagerDutyIntegrationTemplateAnnotationCleared(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	for _, annotation := range []string{
+		config.PagerDutyIntegrationClearQuotaAnnotation,
+		config.PagerDutyIntegrationClearLeakedServiceAnnotation,
+	} {
+		t.Run(annotation, func(t *testing.T) {
+			// Arrange
+			template := &pagerdutyv1alpha1.PagerDutyIntegrationTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "common",
+					Namespace: config.OperatorNamespace,
+				},
+				Spec: pagerdutyv1alpha1.PagerDutyIntegrationTemplateSpec{AcknowledgeTimeout: 600},
+			}
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.AcknowledgeTimeout = 0
+			pdi.Spec.TemplateRef = &corev1.LocalObjectReference{Name: "common"}
+			pdi.Annotations = map[string]string{annotation: ""}
+
+			// the cluster is only set up once the template changed
+			mocks := setupDefaultMocks(t, []runtime.Object{template, testClusterDeployment(false, true, false, false), testPDISecret(), pdi})
+			mocks.mockPDClient.EXPECT().CreateService(gomock.Any()).DoAndReturn(func(data *pd.Data) (string, error) {
+				assert.Equal(t, uint(900), data.AcknowledgeTimeOut)
+				return testIntegrationID, nil
+			}).Times(1)
+			mocks.mockPDClient.EXPECT().GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+			}
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			}
+
+			// Act
+			_, err := rpdi.Reconcile(request)
+			assert.NoError(t, err)
+
+			// Assert
+			pdi = &pagerdutyv1alpha1.PagerDutyIntegration{}
+			err = mocks.fakeKubeClient.Get(context.TODO(), request.NamespacedName, pdi)
+			assert.NoError(t, err)
+			assert.NotContains(t, pdi.Annotations, annotation)
+			assert.Equal(t, uint(0), pdi.Spec.AcknowledgeTimeout)
+
+			// Act, with the template changed and the cluster installed
+			assert.NoError(t, mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: config.OperatorNamespace, Name: "common"}, template))
+			template.Spec.AcknowledgeTimeout = 900
+			assert.NoError(t, mocks.fakeKubeClient.Update(context.TODO(), template))
+			cd := &hivev1.ClusterDeployment{}
+			assert.NoError(t, mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testClusterName}, cd))
+			cd.Spec.Installed = true
+			assert.NoError(t, mocks.fakeKubeClient.Update(context.TODO(), cd))
+			for i := 0; i < 2; i++ {
+				_, err = rpdi.Reconcile(request)
+				assert.NoError(t, err)
+			}
+
+			// Assert
+			err = mocks.fakeKubeClient.Get(context.TODO(), request.NamespacedName, pdi)
+			assert.NoError(t, err)
+			assert.Equal(t, uint(0), pdi.Spec.AcknowledgeTimeout)
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationAdoptService(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name        string
+		setupPDMock func(*mockpd.MockClientMockRecorder)
+		expectErr   bool
+		expectCM    bool
+		// the reasons of the events recorded, those of the PDI after
+		// those of the ClusterDeployment
+		expectEvents []string
+		// the mock's CreateService sets its own IDs
+		expectServiceID string
+	}{
+		{
+			name: "Existing Service Adopted",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.FindServiceByName(gomock.Any()).Return(testPDService(), nil).Times(1)
+				r.AdoptService(gomock.Any(), testPDService()).DoAndReturn(func(data *pd.Data, service *pdApi.Service) error {
+					data.ServiceID = service.ID
+					data.IntegrationID = testIntegrationID
+					return nil
+				}).Times(1)
+				r.CreateService(gomock.Any()).Times(0)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectCM:        true,
+			expectEvents:    []string{"ServiceAdopted", eventIntegrationKeySynced, eventIntegrationKeySynced},
+			expectServiceID: testServiceID,
+		},
+		{
+			name: "No Existing Service",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.FindServiceByName(gomock.Any()).Return(nil, nil).Times(1)
+				r.AdoptService(gomock.Any(), gomock.Any()).Times(0)
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectCM:        true,
+			expectEvents:    []string{eventPDServiceCreated, eventPDServiceCreated, eventIntegrationKeySynced, eventIntegrationKeySynced},
+			expectServiceID: "XYZ123",
+		},
+		{
+			name: "Lookup Failed",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.FindServiceByName(gomock.Any()).Return(nil, fmt.Errorf("HTTP response code: 500")).Times(1)
+				r.AdoptService(gomock.Any(), gomock.Any()).Times(0)
+				r.CreateService(gomock.Any()).Times(0)
+			},
+			expectErr:    true,
+			expectEvents: []string{eventPDAPIError, eventPDAPIError},
+		},
 	}
 
-	// if we got here, it's bad.
-	return false
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			mocks := &mocks{
+				fakeKubeClient: fakekubeclient.NewFakeClient(testClusterDeployment(true, true, true, false), testPDISecret(), testPagerDutyIntegration()),
+				mockCtrl:       gomock.NewController(t),
+			}
+			mocks.mockPDClient = mockpd.NewMockClient(mocks.mockCtrl)
+			mocks.mockPDClient.EXPECT().ValidateReferences(gomock.Any()).Return(nil, nil).AnyTimes()
+			mocks.mockPDClient.EXPECT().RecreateMissingIntegration(gomock.Any()).Return(false, nil).AnyTimes()
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			recorder := record.NewFakeRecorder(10)
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+				recorder: recorder,
+			}
+
+			// Act
+			_, err := rpdi.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			})
+
+			// Assert
+			assert.Equal(t, test.expectErr, err != nil, "Reconcile error: %v", err)
+			cm := &corev1.ConfigMap{}
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: naming.ConfigMapName(testServicePrefix, testClusterName), Namespace: testNamespace}, cm)
+			assert.Equal(t, test.expectCM, err == nil)
+			if test.expectCM {
+				assert.Equal(t, test.expectServiceID, cm.Data["SERVICE_ID"])
+				assert.Equal(t, testPagerDutyIntegrationName, cm.Labels[config.PagerDutyIntegrationLabel])
+			}
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, strings.Fields(<-recorder.Events)[1])
+			}
+			assert.Equal(t, test.expectEvents, events)
+		})
+	}
 }
 
-func verifyNoConfigMapExists(c client.Client) bool {
-	cmList := &corev1.ConfigMapList{}
-	opts := client.ListOptions{Namespace: testNamespace}
-	err := c.List(context.TODO(), cmList, &opts)
+func TestReconcilePagerDutyIntegrationOrphanedServices(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
 
-	if err != nil {
-		if errors.IsNotFound(err) {
-			// no configmaps are defined, this is OK
-			return true
-		}
+	otherPDI := testPagerDutyIntegration()
+	otherPDI.Name = "other"
+	otherPDI.Spec.ServicePrefix = testServicePrefix + "-other"
+
+	orphan := pdApi.Service{
+		APIObject: pdApi.APIObject{ID: "SVCX"},
+		Name:      testServicePrefix + "-gone.example.com-hive-cluster",
+		Integrations: []pdApi.Integration{
+			{APIObject: pdApi.APIObject{ID: "INTX"}, Name: "V4 Alertmanager", Type: "events_api_v2_inbound_integration"},
+		},
 	}
+	services := []pdApi.Service{
+		// the service of the selected cluster
+		{APIObject: pdApi.APIObject{ID: testServiceID}, Name: pd.ServiceName(&pd.Data{ClusterID: testClusterName, ServicePrefix: testServicePrefix})},
+		// retained for a reinstall
+		{APIObject: pdApi.APIObject{ID: "SVCR"}, Name: testServicePrefix + "-retained.example.com-hive-cluster"},
+		// of the PDI with the longer prefix
+		{APIObject: pdApi.APIObject{ID: "SVCO"}, Name: testServicePrefix + "-other-gone.example.com-hive-cluster"},
+		// not created by the operator
+		{APIObject: pdApi.APIObject{ID: "SVCM"}, Name: testServicePrefix + "-dashboard"},
+		orphan,
+	}
+	reported := []pagerdutyv1alpha1.OrphanedService{{ServiceID: "SVCX", Name: orphan.Name}}
 
-	for _, cm := range cmList.Items {
-		if strings.HasSuffix(cm.Name, config.ConfigMapSuffix) {
-			// too bad, found a configmap associated with this operator
-			return false
-		}
+	tests := []struct {
+		name          string
+		action        pagerdutyv1alpha1.OrphanedServiceAction
+		lastSweep     *metav1.Time
+		previous      []pagerdutyv1alpha1.OrphanedService
+		setupPDMock   func(*mockpd.MockClientMockRecorder)
+		expectOrphans []pagerdutyv1alpha1.OrphanedService
+		expectEvent   string
+	}{
+		{
+			name: "Test Orphan Reported",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.ListServicesByPrefix(testServicePrefix).Return(services, nil).Times(1)
+				r.DeleteService(gomock.Any()).Times(0)
+			},
+			expectOrphans: reported,
+			expectEvent:   "OrphanedServiceFound",
+		},
+		{
+			name:   "Test Orphan Deleted",
+			action: pagerdutyv1alpha1.OrphanedServiceActionDelete,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.ListServicesByPrefix(testServicePrefix).Return(services, nil).Times(1)
+				r.DeleteService(&pd.Data{ServiceID: "SVCX", IntegrationID: "INTX"}).Return(nil).Times(1)
+			},
+			expectEvent: "OrphanedServiceDeleted",
+		},
+		{
+			name:   "Test Orphan Deletion Failed",
+			action: pagerdutyv1alpha1.OrphanedServiceActionDelete,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.ListServicesByPrefix(testServicePrefix).Return(services, nil).Times(1)
+				r.DeleteService(gomock.Any()).Return(fmt.Errorf("HTTP response code: 500")).Times(1)
+			},
+			expectOrphans: reported,
+			expectEvent:   "OrphanedServiceFound",
+		},
+		{
+			name:      "Test Sweep Not Due",
+			lastSweep: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+			previous:  reported,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.ListServicesByPrefix(gomock.Any()).Times(0)
+			},
+			expectOrphans: reported,
+		},
 	}
 
-	// if we got here, it's good.  list was empty or everything passed
-	return true
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.OrphanedServiceSweep = &pagerdutyv1alpha1.OrphanedServiceSweep{Action: test.action}
+			pdi.Status.LastOrphanedServiceSweepTime = test.lastSweep
+			pdi.Status.OrphanedServices = test.previous
+			pdi.Spec.ReinstallServiceRetention = &metav1.Duration{Duration: 24 * time.Hour}
+			pdi.Status.RetainedServices = []pagerdutyv1alpha1.RetainedService{{ServiceID: "SVCR", RetainedAt: metav1.Now()}}
+			secret := kube.GeneratePdSecret(testNamespace, naming.SecretName(testServicePrefix, testClusterName), testIntegrationID, pdi)
+
+			mocks := setupDefaultMocks(t, []runtime.Object{
+				testClusterDeployment(true, true, true, false),
+				kube.GenerateConfigMap(testNamespace, naming.ConfigMapName(testServicePrefix, testClusterName), testServiceID, testIntegrationID),
+				secret,
+				kube.GenerateSyncSet(testNamespace, naming.SyncSetName(testServicePrefix, testClusterName), testClusterName, secret, pdi),
+				testPDISecret(),
+				pdi,
+				otherPDI,
+			})
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			recorder := record.NewFakeRecorder(10)
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+				recorder: recorder,
+			}
+
+			// Act
+			_, err := rpdi.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testPagerDutyIntegrationName, Namespace: config.OperatorNamespace}, pdi)
+			assert.NoError(t, err)
+			assert.Len(t, pdi.Status.OrphanedServices, len(test.expectOrphans))
+			for i := range test.expectOrphans {
+				assert.Equal(t, test.expectOrphans[i], pdi.Status.OrphanedServices[i])
+			}
+			if assert.NotNil(t, pdi.Status.LastOrphanedServiceSweepTime) && test.lastSweep != nil {
+				assert.True(t, test.lastSweep.Equal(pdi.Status.LastOrphanedServiceSweepTime))
+			}
+			if test.expectEvent != "" {
+				assert.Len(t, recorder.Events, 1)
+				assert.Contains(t, <-recorder.Events, test.expectEvent)
+			} else {
+				assert.Len(t, recorder.Events, 0)
+			}
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationAPIKeyValid(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name         string
+		noSecret     bool
+		keyInvalid   bool
+		setupPDMock  func(*mockpd.MockClientMockRecorder)
+		expectStatus corev1.ConditionStatus
+		expectReason string
+		expectEvent  bool
+	}{
+		{
+			name:     "Test Secret Missing",
+			noSecret: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.ValidateReferences(gomock.Any()).Times(0)
+			},
+			expectStatus: corev1.ConditionFalse,
+			expectReason: "SecretNotLoaded",
+			expectEvent:  true,
+		},
+		{
+			name: "Test Key Refused Stops Create",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.ValidateReferences(gomock.Any()).Return(nil, fmt.Errorf("HTTP response code: 401")).Times(1)
+				r.CreateService(gomock.Any()).Times(0)
+			},
+			expectStatus: corev1.ConditionFalse,
+			expectReason: "Unauthorized",
+			expectEvent:  true,
+		},
+		{
+			name:       "Test Key Still Refused",
+			keyInvalid: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.ValidateReferences(gomock.Any()).Return(nil, fmt.Errorf("HTTP response code: 401")).Times(1)
+				r.CreateService(gomock.Any()).Times(0)
+			},
+			expectStatus: corev1.ConditionFalse,
+			expectReason: "Unauthorized",
+		},
+		{
+			name:       "Test Rotated Key Accepted",
+			keyInvalid: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.ValidateReferences(gomock.Any()).Return([]string{}, nil).Times(1)
+				r.CreateService(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+				r.GetIntegrationKey(gomock.Any()).Return(testIntegrationID, nil).Times(1)
+			},
+			expectStatus: corev1.ConditionTrue,
+			expectReason: "KeyAccepted",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			if test.keyInvalid {
+				pdi.Status.Conditions = []pagerdutyv1alpha1.PagerDutyIntegrationCondition{
+					{Type: pagerdutyv1alpha1.PagerDutyIntegrationConditionAPIKeyValid, Status: corev1.ConditionFalse, Reason: "Unauthorized"},
+				}
+			}
+			objects := []runtime.Object{testClusterDeployment(true, true, true, false), pdi}
+			if !test.noSecret {
+				objects = append(objects, testPDISecret())
+			}
+			mocks := &mocks{
+				fakeKubeClient: fakekubeclient.NewFakeClient(objects...),
+				mockCtrl:       gomock.NewController(t),
+			}
+			mocks.mockPDClient = mockpd.NewMockClient(mocks.mockCtrl)
+			mocks.mockPDClient.EXPECT().FindServiceByName(gomock.Any()).Return(nil, nil).AnyTimes()
+			mocks.mockPDClient.EXPECT().RecreateMissingIntegration(gomock.Any()).Return(false, nil).AnyTimes()
+			test.setupPDMock(mocks.mockPDClient.EXPECT())
+			defer mocks.mockCtrl.Finish()
+
+			recorder := record.NewFakeRecorder(10)
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+				recorder: recorder,
+			}
+
+			// Act
+			_, err := rpdi.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testPagerDutyIntegrationName, Namespace: config.OperatorNamespace}, pdi)
+			assert.NoError(t, err)
+			condition := findPagerDutyIntegrationCondition(pdi.Status.Conditions, pagerdutyv1alpha1.PagerDutyIntegrationConditionAPIKeyValid)
+			if assert.NotNil(t, condition) {
+				assert.Equal(t, test.expectStatus, condition.Status)
+				assert.Equal(t, test.expectReason, condition.Reason)
+			}
+			events := eventsWithReason(recorder, "APIKeyInvalid")
+			if test.expectEvent {
+				assert.Len(t, events, 1)
+			} else {
+				assert.Len(t, events, 0)
+			}
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationSharedIntegrationKey(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	shared := &pagerdutyv1alpha1.SharedIntegrationKey{
+		IntegrationKeySecretRef: corev1.SecretReference{Name: "shared-key", Namespace: config.OperatorNamespace},
+	}
+
+	tests := []struct {
+		name          string
+		shared        *pagerdutyv1alpha1.SharedIntegrationKey
+		existingSSS   bool
+		deleteService int
+		expectSSS     bool
+	}{
+		{
+			name:          "Test Shared Key Replaces Cluster Setup",
+			shared:        shared,
+			deleteService: 1,
+			expectSSS:     true,
+		},
+		{
+			name:          "Test Shared Key Kept",
+			shared:        shared,
+			existingSSS:   true,
+			deleteService: 1,
+			expectSSS:     true,
+		},
+		{
+			name:        "Test Shared Key Removed",
+			existingSSS: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.SharedIntegrationKey = test.shared
+			localObjects := []runtime.Object{testPDISecret(), pdi}
+			if test.shared != nil {
+				// a cluster set up on its own before the key was shared
+				localObjects = append(localObjects, testClusterDeployment(true, true, true, false), testCDConfigMap(), testCDSyncSet(), testCDSecret())
+			}
+			if test.existingSSS {
+				sharedPDI := pdi.DeepCopy()
+				sharedPDI.Spec.SharedIntegrationKey = shared
+				localObjects = append(localObjects, kube.GenerateSelectorSyncSet(naming.SelectorSyncSetName(testPagerDutyIntegrationName), sharedPDI))
+			}
+
+			mocks := setupDefaultMocks(t, localObjects)
+			mocks.mockPDClient.EXPECT().CreateService(gomock.Any()).Times(0)
+			mocks.mockPDClient.EXPECT().DeleteService(gomock.Any()).Return(nil).Times(test.deleteService)
+			defer mocks.mockCtrl.Finish()
+
+			rpdi := &ReconcilePagerDutyIntegration{
+				client:   mocks.fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+				recorder: record.NewFakeRecorder(10),
+			}
+
+			// Act
+			_, err := rpdi.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testPagerDutyIntegrationName,
+					Namespace: config.OperatorNamespace,
+				},
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			sss := &hivev1.SelectorSyncSet{}
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: naming.SelectorSyncSetName(testPagerDutyIntegrationName)}, sss)
+			if !test.expectSSS {
+				assert.True(t, errors.IsNotFound(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "shared-key", sss.Spec.Secrets[0].SourceRef.Name)
+
+			// the cluster no longer has anything of its own
+			cd := &hivev1.ClusterDeployment{}
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: testClusterName, Namespace: testNamespace}, cd)
+			if err == nil {
+				assert.False(t, utils.HasFinalizer(cd, config.PagerDutyFinalizerPrefix+testPagerDutyIntegrationName))
+			}
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: naming.SyncSetName(testServicePrefix, testClusterName), Namespace: testNamespace}, &hivev1.SyncSet{})
+			assert.True(t, errors.IsNotFound(err))
+		})
+	}
+}
+
+func TestReconcilePagerDutyIntegrationKeyRotation(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	// Arrange
+	cd := testClusterDeployment(true, true, true, false)
+	cd.Annotations = map[string]string{config.RotateIntegrationKeyAnnotation: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)}
+	cm := testCDConfigMap()
+	cm.CreationTimestamp = metav1.Time{Time: time.Now().Add(-time.Hour)}
+
+	mocks := setupDefaultMocks(t, []runtime.Object{
+		cd,
+		testPDISecret(),
+		testPagerDutyIntegration(),
+		cm,
+		testCDSyncSet(),
+		testCDSecret(),
+	})
+	mocks.mockPDClient.EXPECT().RotateIntegrationKey(gomock.Any()).DoAndReturn(func(data *pd.Data) error {
+		data.IntegrationID = "ROTATED"
+		return nil
+	}).Times(1)
+	mocks.mockPDClient.EXPECT().GetIntegrationKey(gomock.Any()).Return("rotated-key", nil).Times(1)
+	defer mocks.mockCtrl.Finish()
+
+	recorder := record.NewFakeRecorder(100)
+	rpdi := &ReconcilePagerDutyIntegration{
+		client:   mocks.fakeKubeClient,
+		scheme:   scheme.Scheme,
+		recorder: recorder,
+		pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+	}
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      testPagerDutyIntegrationName,
+			Namespace: config.OperatorNamespace,
+		},
+	}
+
+	// Act, twice to confirm the key is rotated once
+	_, err1 := rpdi.Reconcile(request)
+	_, err2 := rpdi.Reconcile(request)
+
+	// Assert
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+
+	// the cluster gets the new key
+	secret := &corev1.Secret{}
+	err := mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Name: naming.SecretName(testServicePrefix, testClusterName), Namespace: testNamespace}, secret)
+	assert.NoError(t, err)
+	assert.Equal(t, "rotated-key", string(secret.Data[config.PagerDutySecretKey]))
+	assert.Len(t, eventsWithReason(recorder, eventIntegrationKeyRotated), 2)
+
+	pdi := &pagerdutyv1alpha1.PagerDutyIntegration{}
+	err = mocks.fakeKubeClient.Get(context.TODO(), request.NamespacedName, pdi)
+	assert.NoError(t, err)
+	assert.Len(t, pdi.Status.Clusters, 1)
+	assert.NotNil(t, pdi.Status.Clusters[0].LastKeyRotationTime)
+}
+
+func TestReconcilePagerDutyIntegrationPagerDutyService(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	serviceName := types.NamespacedName{Name: naming.ConfigMapName(testServicePrefix, testClusterName), Namespace: testNamespace}
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      testPagerDutyIntegrationName,
+			Namespace: config.OperatorNamespace,
+		},
+	}
+
+	t.Run("Test Migrated From ConfigMap", func(t *testing.T) {
+		// Arrange
+		mocks := setupDefaultMocks(t, []runtime.Object{
+			testClusterDeployment(true, true, true, false),
+			testPDISecret(),
+			testPagerDutyIntegration(),
+			testCDConfigMap(),
+			testCDSyncSet(),
+			testCDSecret(),
+		})
+		defer mocks.mockCtrl.Finish()
+		rpdi := &ReconcilePagerDutyIntegration{
+			client:   mocks.fakeKubeClient,
+			scheme:   scheme.Scheme,
+			pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+		}
+
+		// Act
+		_, err := rpdi.Reconcile(request)
+
+		// Assert
+		assert.NoError(t, err)
+		pdService := &pagerdutyv1alpha1.PagerDutyService{}
+		err = mocks.fakeKubeClient.Get(context.TODO(), serviceName, pdService)
+		assert.NoError(t, err)
+		assert.Equal(t, testClusterName, pdService.Spec.ClusterDeploymentRef.Name)
+		assert.Equal(t, testPagerDutyIntegrationName, pdService.Spec.PagerDutyIntegration)
+		assert.Equal(t, testServiceID, pdService.Spec.ServiceID)
+		assert.Equal(t, testIntegrationID, pdService.Spec.IntegrationID)
+		assert.Equal(t, testPagerDutyIntegrationName, pdService.Labels[config.PagerDutyIntegrationLabel])
+		// with the status of the cluster
+		assert.NotEmpty(t, pdService.Status.State)
+		assert.NotEmpty(t, pdService.Status.Conditions)
+	})
+
+	t.Run("Test PagerDutyService Preferred", func(t *testing.T) {
+		// Arrange
+		cm := testCDConfigMap()
+		cm.Data["INTEGRATION_ID"] = "STALE"
+		existing := kube.GeneratePagerDutyService(testNamespace, serviceName.Name, testPagerDutyIntegrationName, testClusterName, testServiceID, testIntegrationID)
+		mocks := setupDefaultMocks(t, []runtime.Object{
+			testClusterDeployment(true, true, true, false),
+			testPDISecret(),
+			testPagerDutyIntegration(),
+			cm,
+			existing,
+		})
+		mocks.mockPDClient.EXPECT().GetIntegrationKey(gomock.Any()).DoAndReturn(func(data *pd.Data) (string, error) {
+			assert.Equal(t, testIntegrationID, data.IntegrationID)
+			return testIntegrationID, nil
+		}).Times(1)
+		defer mocks.mockCtrl.Finish()
+		rpdi := &ReconcilePagerDutyIntegration{
+			client:   mocks.fakeKubeClient,
+			scheme:   scheme.Scheme,
+			pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+		}
+
+		// Act
+		_, err := rpdi.Reconcile(request)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("Test Deleted With The Service", func(t *testing.T) {
+		// Arrange
+		mocks := setupDefaultMocks(t, []runtime.Object{
+			testClusterDeployment(true, true, true, true),
+			testPDISecret(),
+			testPagerDutyIntegration(),
+			testCDConfigMap(),
+			testCDSyncSet(),
+			testCDSecret(),
+			kube.GeneratePagerDutyService(testNamespace, serviceName.Name, testPagerDutyIntegrationName, testClusterName, testServiceID, testIntegrationID),
+		})
+		mocks.mockPDClient.EXPECT().DeleteService(gomock.Any()).Return(nil).Times(1)
+		defer mocks.mockCtrl.Finish()
+		rpdi := &ReconcilePagerDutyIntegration{
+			client:   mocks.fakeKubeClient,
+			scheme:   scheme.Scheme,
+			pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mocks.mockPDClient },
+		}
+
+		// Act
+		_, err := rpdi.Reconcile(request)
+
+		// Assert
+		assert.NoError(t, err)
+		err = mocks.fakeKubeClient.Get(context.TODO(), serviceName, &pagerdutyv1alpha1.PagerDutyService{})
+		assert.True(t, errors.IsNotFound(err))
+	})
 }
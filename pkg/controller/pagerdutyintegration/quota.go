@@ -0,0 +1,76 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/localmetrics"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// accountQuotaExceeded returns true while the PagerDuty account of the PDI
+// is known to refuse new services. Creation is not retried until the
+// condition is cleared, by an operator deleting a service or by hand.
+func accountQuotaExceeded(pdi *pagerdutyv1alpha1.PagerDutyIntegration) bool {
+	for _, condition := range pdi.Status.Conditions {
+		if condition.Type == pagerdutyv1alpha1.PagerDutyIntegrationConditionAccountQuotaExceeded {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// setAccountQuotaExceeded sets the AccountQuotaExceeded condition of the PDI
+// and the matching metric. The status is persisted at the end of Reconcile.
+func setAccountQuotaExceeded(pdi *pagerdutyv1alpha1.PagerDutyIntegration, exceeded bool, reason, message string) {
+	condition := pagerdutyv1alpha1.PagerDutyIntegrationCondition{
+		Type:    pagerdutyv1alpha1.PagerDutyIntegrationConditionAccountQuotaExceeded,
+		Status:  corev1.ConditionFalse,
+		Reason:  reason,
+		Message: logging.Redact(message),
+	}
+	metric := 0
+	if exceeded {
+		condition.Status = corev1.ConditionTrue
+		metric = 1
+	}
+
+	setPagerDutyIntegrationCondition(&pdi.Status.Conditions, condition)
+	localmetrics.UpdateMetricPagerDutyIntegrationAccountQuotaExceeded(metric, pdi.Name)
+}
+
+// setPagerDutyIntegrationCondition adds or replaces the condition of the
+// same type. The previous transition time is kept when the status did not
+// change.
+func setPagerDutyIntegrationCondition(conditions *[]pagerdutyv1alpha1.PagerDutyIntegrationCondition, condition pagerdutyv1alpha1.PagerDutyIntegrationCondition) {
+	for i := range *conditions {
+		existing := &(*conditions)[i]
+		if existing.Type != condition.Type {
+			continue
+		}
+		if existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		} else {
+			condition.LastTransitionTime = metav1.Now()
+		}
+		*existing = condition
+		return
+	}
+
+	condition.LastTransitionTime = metav1.Now()
+	*conditions = append(*conditions, condition)
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"strings"
+
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// validateReferences looks up all PagerDuty resources the PDI refers to in
+// one batch, before any cluster is set up, and records the outcome in the
+// ReferencesValid condition. It returns false only when a resource is known
+// to be missing or PagerDuty refused the API key, which is recorded in the
+// APIKeyValid condition; when PagerDuty could not be asked otherwise the
+// clusters are set up as before, each failing on its own if a resource is
+// indeed missing. The status is persisted at the end of Reconcile.
+func (r *ReconcilePagerDutyIntegration) validateReferences(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration) bool {
+	refs := pd.References{EscalationPolicyID: pdi.Spec.EscalationPolicy, Team: pdi.Spec.Team}
+	refs.EscalationPolicyIDs = mappedEscalationPolicies(pdi)
+	if pdi.Spec.DeprovisioningEventRule != nil {
+		refs.RulesetIDs = append(refs.RulesetIDs, pdi.Spec.DeprovisioningEventRule.RulesetID)
+	}
+	if pdi.Spec.ServiceDependencies != nil && pdi.Spec.ServiceDependencies.HubServiceID != "" {
+		refs.ServiceIDs = append(refs.ServiceIDs, pdi.Spec.ServiceDependencies.HubServiceID)
+	}
+
+	condition := pagerdutyv1alpha1.PagerDutyIntegrationCondition{
+		Type:   pagerdutyv1alpha1.PagerDutyIntegrationConditionReferencesValid,
+		Status: corev1.ConditionTrue,
+		Reason: "ReferencesFound",
+	}
+
+	missing, err := pdclient.ValidateReferences(refs)
+	switch {
+	case err != nil:
+		r.reqLogger.Error(err, "Failed to validate PagerDuty references")
+		condition.Status = corev1.ConditionUnknown
+		condition.Reason = "ValidationFailed"
+		condition.Message = logging.Redact(err.Error())
+	case len(missing) > 0:
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "ReferenceNotFound"
+		condition.Message = strings.Join(missing, ", ")
+	}
+
+	setPagerDutyIntegrationCondition(&pdi.Status.Conditions, condition)
+
+	switch {
+	case err == nil:
+		r.setAPIKeyValid(pdi, true, "KeyAccepted", "")
+	case pd.IsUnauthorized(err):
+		r.setAPIKeyValid(pdi, false, "Unauthorized", err.Error())
+		// nothing can be set up with a key PagerDuty refuses
+		return false
+	}
+	return condition.Status != corev1.ConditionFalse
+}
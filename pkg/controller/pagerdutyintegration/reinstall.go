@@ -0,0 +1,192 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/kube"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// retainsServices returns true if the PagerDuty service of a deleted
+// cluster is kept for a reinstall to reuse or for its grace period.
+func retainsServices(pdi *pagerdutyv1alpha1.PagerDutyIntegration) bool {
+	return (pdi.Spec.ReinstallServiceRetention != nil || pdi.Spec.DeprovisionGracePeriod != nil) && pdi.DeletionTimestamp == nil
+}
+
+// serviceRetention returns how long the PagerDuty service of a deleted
+// cluster is kept, the longest of reinstallServiceRetention and
+// deprovisionGracePeriod.
+func serviceRetention(pdi *pagerdutyv1alpha1.PagerDutyIntegration) time.Duration {
+	var retention time.Duration
+	if pdi.Spec.ReinstallServiceRetention != nil {
+		retention = pdi.Spec.ReinstallServiceRetention.Duration
+	}
+	if pdi.Spec.DeprovisionGracePeriod != nil && pdi.Spec.DeprovisionGracePeriod.Duration > retention {
+		retention = pdi.Spec.DeprovisionGracePeriod.Duration
+	}
+	return retention
+}
+
+// retainService records the PagerDuty service of a deleted cluster instead
+// of deleting it, disabling it first when deprovisionGracePeriod is set so
+// it pages nobody meanwhile. The record is persisted right away, the
+// ClusterDeployment and the ConfigMap holding the service ID are about to
+// go. A service that failed to be disabled is still retained.
+func (r *ReconcilePagerDutyIntegration) retainService(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, pdData *pd.Data) error {
+	disabled := false
+	if pdi.Spec.DeprovisionGracePeriod != nil {
+		r.reqLogger.Info("Disabling PD service for the deprovision grace period", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name, "ServiceID", pdData.ServiceID)
+		err := pdclient.DisableService(pdData)
+		if err != nil {
+			r.reqLogger.Error(err, "Failed disabling PD service", "ServiceID", pdData.ServiceID)
+			r.recordClusterEvent(pdi, cd, corev1.EventTypeWarning, eventPDAPIError,
+				"Disabling PD service %s failed: %v", pdData.ServiceID, err)
+		} else {
+			disabled = true
+		}
+	}
+
+	r.reqLogger.Info("Retaining PD service of the deleted cluster", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name, "ServiceID", pdData.ServiceID)
+	removeRetainedService(&pdi.Status.RetainedServices, cd.Namespace, cd.Name)
+	pdi.Status.RetainedServices = append(pdi.Status.RetainedServices, pagerdutyv1alpha1.RetainedService{
+		ClusterDeploymentNamespace: cd.Namespace,
+		ClusterDeploymentName:      cd.Name,
+		ClusterID:                  cd.Spec.ClusterName,
+		ServiceID:                  pdData.ServiceID,
+		IntegrationID:              pdData.IntegrationID,
+		RetainedAt:                 metav1.NewTime(r.now()),
+		Disabled:                   disabled,
+	})
+	return r.updateStatus(pdi)
+}
+
+// restoreRetainedService recreates the ConfigMap of a reinstalled cluster
+// from the service retained when it was deleted, so handleCreate reuses the
+// service and its integration key instead of creating new ones. A service
+// disabled for the deprovision grace period is enabled again.
+func (r *ReconcilePagerDutyIntegration) restoreRetainedService(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, configMapName string) error {
+	retained := findRetainedService(pdi, cd)
+	if retained == nil {
+		return nil
+	}
+
+	cm := kube.GenerateConfigMap(cd.Namespace, configMapName, retained.ServiceID, retained.IntegrationID)
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: configMapName}, cm)
+	if err == nil {
+		// not expected, but the cluster already has a service
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return err
+	}
+
+	r.reqLogger.Info("Reusing PD service retained for a reinstall", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name, "ServiceID", retained.ServiceID)
+	if retained.Disabled {
+		err = pdclient.EnableService(&pd.Data{ServiceID: retained.ServiceID, IntegrationID: retained.IntegrationID})
+		if err != nil {
+			return err
+		}
+	}
+	err = r.savePagerDutyService(pdi, cd, configMapName, retained.ServiceID, retained.IntegrationID)
+	if err != nil {
+		return err
+	}
+	setOwnerLabel(cm, pdi)
+	if err = controllerutil.SetControllerReference(cd, cm, r.scheme); err != nil {
+		return err
+	}
+	if err = r.client.Create(context.TODO(), cm); err != nil {
+		return err
+	}
+
+	removeRetainedService(&pdi.Status.RetainedServices, cd.Namespace, cd.Name)
+	return r.updateStatus(pdi)
+}
+
+// findRetainedService returns the service retained for a previous install
+// of the cluster, or nil if there is none
+func findRetainedService(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) *pagerdutyv1alpha1.RetainedService {
+	for i := range pdi.Status.RetainedServices {
+		service := &pdi.Status.RetainedServices[i]
+		if service.ClusterDeploymentNamespace == cd.Namespace && service.ClusterDeploymentName == cd.Name && service.ClusterID == cd.Spec.ClusterName {
+			return service
+		}
+	}
+	return nil
+}
+
+// expireRetainedServices deletes the services that were not reused within
+// reinstallServiceRetention and whose deprovisionGracePeriod is over, or all
+// of them once both are unset or the PDI is being deleted. It returns how
+// long until the next one expires.
+func (r *ReconcilePagerDutyIntegration) expireRetainedServices(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration) (time.Duration, error) {
+	var retention time.Duration
+	if retainsServices(pdi) {
+		retention = serviceRetention(pdi)
+	}
+
+	now := r.now()
+	var next time.Duration
+	kept := []pagerdutyv1alpha1.RetainedService{}
+	for _, service := range pdi.Status.RetainedServices {
+		if wait := service.RetainedAt.Add(retention).Sub(now); wait > 0 {
+			if next == 0 || wait < next {
+				next = wait
+			}
+			kept = append(kept, service)
+			continue
+		}
+
+		r.reqLogger.Info("Deleting retained PD service", "ClusterDeployment.Namespace", service.ClusterDeploymentNamespace, "ClusterDeployment.Name", service.ClusterDeploymentName, "ServiceID", service.ServiceID)
+		err := pdclient.DeleteService(&pd.Data{ServiceID: service.ServiceID, IntegrationID: service.IntegrationID})
+		if err != nil && !pd.IsNotFound(err) {
+			r.reqLogger.Error(err, "Failed deleting retained PD service", "ServiceID", service.ServiceID)
+			kept = append(kept, service)
+			continue
+		}
+		if accountQuotaExceeded(pdi) {
+			// the deleted service freed room for a new one
+			setAccountQuotaExceeded(pdi, false, "ServiceDeleted", "A PagerDuty service was deleted, creation is retried")
+		}
+	}
+
+	if len(kept) == len(pdi.Status.RetainedServices) {
+		return next, nil
+	}
+	pdi.Status.RetainedServices = kept
+	return next, r.updateStatus(pdi)
+}
+
+// removeRetainedService removes the service retained for the given
+// ClusterDeployment, if any.
+func removeRetainedService(services *[]pagerdutyv1alpha1.RetainedService, namespace, name string) {
+	kept := []pagerdutyv1alpha1.RetainedService{}
+	for _, service := range *services {
+		if service.ClusterDeploymentNamespace != namespace || service.ClusterDeploymentName != name {
+			kept = append(kept, service)
+		}
+	}
+	*services = kept
+}
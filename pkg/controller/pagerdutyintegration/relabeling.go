@@ -0,0 +1,162 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// labelsChanged returns true if the labels of a ClusterDeployment differ
+// between the two sides of an update
+func labelsChanged(e event.UpdateEvent) bool {
+	if e.MetaOld == nil || e.MetaNew == nil {
+		return false
+	}
+	return !labels.Equals(labels.Set(e.MetaOld.GetLabels()), labels.Set(e.MetaNew.GetLabels()))
+}
+
+// labelsUnchanged passes the events of ClusterDeployments but the updates
+// changing their labels, which relabeledClusterDeploymentHandler queues
+var labelsUnchanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool { return !labelsChanged(e) },
+}
+
+// relabeledClusterDeploymentHandler queues, when the labels of a
+// ClusterDeployment change, only the PagerDutyIntegrations the change
+// concerns. It records the clusters a PagerDutyIntegration stopped
+// selecting, such as when their managed label turns false, so their
+// services are torn down ahead of the rest of the fleet.
+type relabeledClusterDeploymentHandler struct {
+	Client      client.Client
+	offboarding *onboardingClusters
+}
+
+func (h relabeledClusterDeploymentHandler) Create(event.CreateEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h relabeledClusterDeploymentHandler) Update(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	if !labelsChanged(e) {
+		return
+	}
+	for _, request := range h.requests(e.MetaOld, e.MetaNew) {
+		q.Add(request)
+	}
+}
+
+func (h relabeledClusterDeploymentHandler) Delete(event.DeleteEvent, workqueue.RateLimitingInterface) {
+}
+
+func (h relabeledClusterDeploymentHandler) Generic(event.GenericEvent, workqueue.RateLimitingInterface) {
+}
+
+// requests returns a request for each PagerDutyIntegration concerned by the
+// labels of a ClusterDeployment changing from those of old to those of new
+func (h relabeledClusterDeploymentHandler) requests(old, new metav1.Object) []reconcile.Request {
+	pdiList := &pagerdutyv1alpha1.PagerDutyIntegrationList{}
+	err := h.Client.List(context.TODO(), pdiList, &client.ListOptions{})
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := []reconcile.Request{}
+	for i := range pdiList.Items {
+		pdi := &pdiList.Items[i]
+		if !relabelingConcerns(pdi, old, new) {
+			continue
+		}
+		if selectsClusterDeployment(pdi, old) && !selectsClusterDeployment(pdi, new) {
+			log.Info("ClusterDeployment no longer selected, tearing it down", "Namespace", new.GetNamespace(), "Name", new.GetName(), "PagerDutyIntegration", pdi.Name)
+			h.offboarding.add(new.GetNamespace(), new.GetName(), time.Now())
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      pdi.Name,
+				Namespace: pdi.Namespace,
+			}},
+		)
+	}
+	return requests
+}
+
+// relabelingConcerns returns true if changing the labels of a
+// ClusterDeployment from those of old to those of new changes whether the
+// PDI or one of its additional services selects it, or, while selected, the
+// escalation policy, silencing or name of its service
+func relabelingConcerns(pdi *pagerdutyv1alpha1.PagerDutyIntegration, old, new metav1.Object) bool {
+	selectedBefore, selected := selectsClusterDeployment(pdi, old), selectsClusterDeployment(pdi, new)
+	if selectedBefore != selected {
+		return true
+	}
+	if !selected {
+		return false
+	}
+
+	oldLabels, newLabels := old.GetLabels(), new.GetLabels()
+	for _, svc := range pdi.Spec.AdditionalServices {
+		if selects(svc.ClusterDeploymentSelector, oldLabels) != selects(svc.ClusterDeploymentSelector, newLabels) {
+			return true
+		}
+	}
+	if mapping := pdi.Spec.EscalationPolicyMapping; mapping != nil && oldLabels[mapping.LabelKey] != newLabels[mapping.LabelKey] {
+		return true
+	}
+	if oldLabels[config.ClusterDeploymentNoalertsLabel] != newLabels[config.ClusterDeploymentNoalertsLabel] {
+		return true
+	}
+	// the template may refer to any label
+	return pdi.Spec.ServiceNameTemplate != ""
+}
+
+// offboardClusters tears down, ahead of the rest of the reconcile, the
+// clusters that recently stopped being selected by the PDI and still carry
+// its finalizer, so they stop paging within minutes instead of waiting
+// behind the rest of the fleet. The regular pass handles them again, so
+// failures are only logged.
+func (r *ReconcilePagerDutyIntegration) offboardClusters(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, finalizer string, all []hivev1.ClusterDeployment, matching []hivev1.ClusterDeployment) {
+	now := r.now()
+	offboarding := []hivev1.ClusterDeployment{}
+	for _, cd := range all {
+		if cd.DeletionTimestamp != nil || !utils.HasFinalizer(&cd, finalizer) {
+			continue
+		}
+		if findClusterDeployment(matching, cd.Namespace, cd.Name) == nil && r.offboarding.has(cd.Namespace, cd.Name, now) {
+			offboarding = append(offboarding, cd)
+		}
+	}
+	if len(offboarding) == 0 {
+		return
+	}
+
+	r.reqLogger.Info("Offboarding clusters no longer selected", "Count", len(offboarding))
+	err := r.deleteClusters(pdclient, pdi, finalizer, offboarding, matching)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed offboarding clusters no longer selected, the regular pass retries them")
+	}
+}
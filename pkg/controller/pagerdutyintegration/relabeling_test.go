@@ -0,0 +1,166 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"testing"
+	"time"
+
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestLabelsUnchanged(t *testing.T) {
+	meta := func(labels map[string]string) *metav1.ObjectMeta {
+		return &metav1.ObjectMeta{Labels: labels}
+	}
+
+	assert.True(t, labelsUnchanged.Update(event.UpdateEvent{MetaOld: meta(nil), MetaNew: meta(map[string]string{})}))
+	assert.True(t, labelsUnchanged.Update(event.UpdateEvent{MetaOld: meta(map[string]string{"a": "b"}), MetaNew: meta(map[string]string{"a": "b"})}))
+	assert.False(t, labelsUnchanged.Update(event.UpdateEvent{MetaOld: meta(map[string]string{"a": "b"}), MetaNew: meta(map[string]string{"a": "c"})}))
+	assert.True(t, labelsUnchanged.Create(event.CreateEvent{Meta: meta(nil)}))
+}
+
+func TestRelabelingConcerns(t *testing.T) {
+	tiered := pagerDutyIntegration("tiered", map[string]string{config.ClusterDeploymentManagedLabel: "true"})
+	tiered.Spec.EscalationPolicyMapping = &pagerdutyv1alpha1.EscalationPolicyMapping{
+		LabelKey: "tier",
+		Policies: map[string]string{"gold": "GOLD123"},
+	}
+
+	tests := []struct {
+		name   string
+		pdi    *pagerdutyv1alpha1.PagerDutyIntegration
+		old    map[string]string
+		new    map[string]string
+		expect bool
+	}{
+		{
+			name:   "Becomes Unmanaged",
+			pdi:    tiered,
+			old:    map[string]string{config.ClusterDeploymentManagedLabel: "true"},
+			new:    map[string]string{config.ClusterDeploymentManagedLabel: "false"},
+			expect: true,
+		},
+		{
+			name:   "Becomes Managed",
+			pdi:    tiered,
+			old:    map[string]string{},
+			new:    map[string]string{config.ClusterDeploymentManagedLabel: "true"},
+			expect: true,
+		},
+		{
+			name:   "Tier Changes",
+			pdi:    tiered,
+			old:    map[string]string{config.ClusterDeploymentManagedLabel: "true", "tier": "bronze"},
+			new:    map[string]string{config.ClusterDeploymentManagedLabel: "true", "tier": "gold"},
+			expect: true,
+		},
+		{
+			name:   "Unrelated Label Changes",
+			pdi:    tiered,
+			old:    map[string]string{config.ClusterDeploymentManagedLabel: "true", "owner": "a"},
+			new:    map[string]string{config.ClusterDeploymentManagedLabel: "true", "owner": "b"},
+			expect: false,
+		},
+		{
+			name:   "Never Selected",
+			pdi:    tiered,
+			old:    map[string]string{"tier": "bronze"},
+			new:    map[string]string{"tier": "gold"},
+			expect: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			old := &metav1.ObjectMeta{Name: "cd", Namespace: "ns", Labels: test.old}
+			new := &metav1.ObjectMeta{Name: "cd", Namespace: "ns", Labels: test.new}
+			assert.Equal(t, test.expect, relabelingConcerns(test.pdi, old, new))
+		})
+	}
+}
+
+func TestRelabeledClusterDeploymentHandler(t *testing.T) {
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+
+	offboarding := newOnboardingClusters()
+	h := relabeledClusterDeploymentHandler{
+		Client: fake.NewFakeClient([]runtime.Object{
+			pagerDutyIntegration("managed", map[string]string{config.ClusterDeploymentManagedLabel: "true"}),
+			pagerDutyIntegration("other", map[string]string{"other": "true"}),
+		}...),
+		offboarding: offboarding,
+	}
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+
+	h.Update(event.UpdateEvent{
+		MetaOld: &metav1.ObjectMeta{Name: "cd", Namespace: "ns", Labels: map[string]string{config.ClusterDeploymentManagedLabel: "true"}},
+		MetaNew: &metav1.ObjectMeta{Name: "cd", Namespace: "ns", Labels: map[string]string{config.ClusterDeploymentManagedLabel: "false"}},
+	}, q)
+
+	// only the PDI that stopped selecting the cluster
+	assert.Equal(t, 1, q.Len())
+	item, _ := q.Get()
+	assert.Equal(t, "managed", item.(reconcile.Request).Name)
+	assert.True(t, offboarding.has("ns", "cd", time.Now()))
+}
+
+func TestOffboardClusters(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	finalizer := config.PagerDutyFinalizerPrefix + testPagerDutyIntegrationName
+
+	selected := fleetClusterDeployment("cluster-selected", false)
+	unmanaged := fleetClusterDeployment("cluster-unmanaged", false)
+	dropped := fleetClusterDeployment("cluster-dropped", false)
+	all := []hivev1.ClusterDeployment{selected, unmanaged, dropped}
+	matching := []hivev1.ClusterDeployment{selected}
+
+	offboarding := newOnboardingClusters()
+	offboarding.add(testNamespace, "cluster-selected", now)
+	offboarding.add(testNamespace, "cluster-unmanaged", now)
+
+	handler := &fakeClusterHandler{}
+	r := &ReconcilePagerDutyIntegration{
+		reqLogger:   log,
+		clusters:    handler,
+		clock:       func() time.Time { return now.Add(time.Minute) },
+		offboarding: offboarding,
+	}
+
+	r.offboardClusters(nil, testPagerDutyIntegration(), finalizer, all, matching)
+
+	// only the recorded cluster no longer selected, the other one waits for the regular pass
+	assert.Equal(t, []string{"cluster-unmanaged"}, handler.deleted)
+
+	// nothing is fast-pathed without the record
+	handler = &fakeClusterHandler{}
+	r = &ReconcilePagerDutyIntegration{reqLogger: log, clusters: handler}
+	r.offboardClusters(nil, testPagerDutyIntegration(), finalizer, all, matching)
+	assert.Empty(t, handler.deleted)
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"time"
+
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+)
+
+var (
+	// resyncPeriod is set by --resync-period
+	resyncPeriod = config.ResyncPeriod
+	// errorRequeueDelay is set by --pagerduty-error-requeue-delay
+	errorRequeueDelay time.Duration
+)
+
+// resyncPeriodOf returns how often the PagerDuty service of each cluster of
+// pdi is verified, spec.resyncPeriod if set, else the one of the operator,
+// no shorter than config.ResyncMinInterval
+func (r *ReconcilePagerDutyIntegration) resyncPeriodOf(pdi *pagerdutyv1alpha1.PagerDutyIntegration) time.Duration {
+	period := r.resyncPeriod
+	if pdi.Spec.ResyncPeriod != nil {
+		period = pdi.Spec.ResyncPeriod.Duration
+	} else if period <= 0 {
+		period = config.ResyncPeriod
+	}
+	if period < config.ResyncMinInterval {
+		period = config.ResyncMinInterval
+	}
+	return period
+}
+
+// errorRequeueDelayOf returns how long to wait before reconciling pdi again
+// after a transient PagerDuty error, spec.errorRequeueDelay if set, else the
+// one of the operator. 0 leaves it to the backoff of the controller.
+func (r *ReconcilePagerDutyIntegration) errorRequeueDelayOf(pdi *pagerdutyv1alpha1.PagerDutyIntegration) time.Duration {
+	if pdi.Spec.ErrorRequeueDelay != nil {
+		return pdi.Spec.ErrorRequeueDelay.Duration
+	}
+	return r.errorRequeueDelay
+}
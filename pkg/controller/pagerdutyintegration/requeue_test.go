@@ -0,0 +1,98 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/pagerduty-operator/config"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResyncPeriodOf(t *testing.T) {
+	tests := []struct {
+		name         string
+		operator     time.Duration
+		spec         *metav1.Duration
+		expectPeriod time.Duration
+	}{
+		{
+			name:         "Test Default",
+			expectPeriod: config.ResyncPeriod,
+		},
+		{
+			name:         "Test Operator Flag",
+			operator:     time.Hour,
+			expectPeriod: time.Hour,
+		},
+		{
+			name:         "Test Spec Overrides Flag",
+			operator:     time.Hour,
+			spec:         &metav1.Duration{Duration: 24 * time.Hour},
+			expectPeriod: 24 * time.Hour,
+		},
+		{
+			name:         "Test Raised To Minimum",
+			spec:         &metav1.Duration{Duration: time.Second},
+			expectPeriod: config.ResyncMinInterval,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.ResyncPeriod = test.spec
+			r := &ReconcilePagerDutyIntegration{resyncPeriod: test.operator}
+
+			assert.Equal(t, test.expectPeriod, r.resyncPeriodOf(pdi))
+		})
+	}
+}
+
+func TestErrorRequeueDelayOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		operator    time.Duration
+		spec        *metav1.Duration
+		expectDelay time.Duration
+	}{
+		{
+			name: "Test Default Backs Off",
+		},
+		{
+			name:        "Test Operator Flag",
+			operator:    time.Minute,
+			expectDelay: time.Minute,
+		},
+		{
+			name:        "Test Spec Overrides Flag",
+			operator:    time.Minute,
+			spec:        &metav1.Duration{Duration: 5 * time.Minute},
+			expectDelay: 5 * time.Minute,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.ErrorRequeueDelay = test.spec
+			r := &ReconcilePagerDutyIntegration{errorRequeueDelay: test.operator}
+
+			assert.Equal(t, test.expectDelay, r.errorRequeueDelayOf(pdi))
+		})
+	}
+}
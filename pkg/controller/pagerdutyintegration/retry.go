@@ -0,0 +1,105 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/localmetrics"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+)
+
+// retryReasons lists every RetryReason, so the metric of each is reported
+// even when no cluster has it
+var retryReasons = []pagerdutyv1alpha1.RetryReason{
+	pagerdutyv1alpha1.RetryReasonNotInstalled,
+	pagerdutyv1alpha1.RetryReasonUnmanaged,
+	pagerdutyv1alpha1.RetryReasonSecretSyncPending,
+	pagerdutyv1alpha1.RetryReasonPDRateLimited,
+	pagerdutyv1alpha1.RetryReasonPDError,
+	pagerdutyv1alpha1.RetryReasonConflict,
+	pagerdutyv1alpha1.RetryReasonServiceNameTooLong,
+	pagerdutyv1alpha1.RetryReasonServiceNameTemplateFailed,
+}
+
+// retryReasonFor returns the RetryReason of an error setting up a cluster.
+func retryReasonFor(err error) pagerdutyv1alpha1.RetryReason {
+	switch {
+	case isManagedByOther(err):
+		return pagerdutyv1alpha1.RetryReasonConflict
+	case pd.IsRateLimited(err):
+		return pagerdutyv1alpha1.RetryReasonPDRateLimited
+	default:
+		return pagerdutyv1alpha1.RetryReasonPDError
+	}
+}
+
+// setRetryReason records why setting up the cluster was skipped or will be
+// retried, for its status and the metrics, and logs it along with err if any.
+func (r *ReconcilePagerDutyIntegration) setRetryReason(cd *hivev1.ClusterDeployment, reason pagerdutyv1alpha1.RetryReason, err error) {
+	if r.retryReasons == nil {
+		r.retryReasons = map[string]pagerdutyv1alpha1.RetryReason{}
+	}
+	r.retryReasons[cd.Namespace+"/"+cd.Name] = reason
+
+	if err != nil {
+		if r.retryErrors == nil {
+			r.retryErrors = map[string]string{}
+		}
+		r.retryErrors[cd.Namespace+"/"+cd.Name] = logging.Redact(err.Error())
+		r.reqLogger.Error(err, "Cluster not set up", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name, "RetryReason", reason)
+		return
+	}
+	r.reqLogger.Info("Cluster not set up", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name, "RetryReason", reason)
+}
+
+// retryReason returns the reason recorded for the cluster in this reconcile,
+// or "" if none.
+func (r *ReconcilePagerDutyIntegration) retryReason(cd *hivev1.ClusterDeployment) pagerdutyv1alpha1.RetryReason {
+	return r.retryReasons[cd.Namespace+"/"+cd.Name]
+}
+
+// retryError returns the error behind the reason recorded for the cluster
+// in this reconcile, or "" if none.
+func (r *ReconcilePagerDutyIntegration) retryError(cd *hivev1.ClusterDeployment) string {
+	return r.retryErrors[cd.Namespace+"/"+cd.Name]
+}
+
+// updateRetryReasonMetrics reports how many clusters have each RetryReason.
+// Reasons only make it to the status of installed clusters, NotInstalled and
+// Unmanaged are counted from those recorded in this reconcile.
+func (r *ReconcilePagerDutyIntegration) updateRetryReasonMetrics(pdi *pagerdutyv1alpha1.PagerDutyIntegration, clusters []pagerdutyv1alpha1.ClusterStatus) {
+	counts := map[pagerdutyv1alpha1.RetryReason]int{}
+	for _, reason := range r.retryReasons {
+		counts[reason]++
+	}
+	for _, cluster := range clusters {
+		// the SyncSet state is only known from the status
+		if cluster.RetryReason == pagerdutyv1alpha1.RetryReasonSecretSyncPending {
+			counts[cluster.RetryReason]++
+		}
+	}
+	for _, reason := range retryReasons {
+		localmetrics.UpdateMetricPagerDutyIntegrationRetryReason(counts[reason], pdi.Name, string(reason))
+	}
+}
+
+// deleteRetryReasonMetrics deletes the metrics of the PagerDutyIntegration.
+func deleteRetryReasonMetrics(pdi *pagerdutyv1alpha1.PagerDutyIntegration) {
+	for _, reason := range retryReasons {
+		localmetrics.DeleteMetricPagerDutyIntegrationRetryReason(pdi.Name, string(reason))
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/selfservice"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// optedInClusterDeployments returns the ClusterDeployments that opted into
+// the PagerDutyIntegration with their annotation, leaving out those already
+// selected. The annotations the policy of the PagerDutyIntegration doesn't
+// allow, as when the webhook was bypassed or the policy changed since, are
+// ignored and reported on their ClusterDeployment.
+func (r *ReconcilePagerDutyIntegration) optedInClusterDeployments(pdi *pagerdutyv1alpha1.PagerDutyIntegration, selected []hivev1.ClusterDeployment) ([]hivev1.ClusterDeployment, error) {
+	allClusterDeployments := &hivev1.ClusterDeploymentList{}
+	err := r.client.List(context.TODO(), allClusterDeployments, &client.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	isSelected := map[string]bool{}
+	for _, cd := range selected {
+		isSelected[cd.Namespace+"/"+cd.Name] = true
+	}
+
+	// the policy only depends on the namespace
+	denials := map[string]string{}
+	optedIn := []hivev1.ClusterDeployment{}
+	for i := range allClusterDeployments.Items {
+		cd := &allClusterDeployments.Items[i]
+		if !selfservice.Requests(cd, pdi) || isSelected[cd.Namespace+"/"+cd.Name] {
+			continue
+		}
+
+		denied, checked := denials[cd.Namespace]
+		if !checked {
+			denied, err = selfservice.Denied(r.uncachedReader(), pdi, cd.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			denials[cd.Namespace] = denied
+		}
+		if denied != "" {
+			r.reqLogger.Info("Ignoring self-service onboarding request", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name, "Reason", denied)
+			r.recorder.Eventf(cd, corev1.EventTypeWarning, "SelfServiceOnboardingDenied",
+				"Annotation %s ignored: %s", config.ClusterDeploymentIntegrationAnnotation, denied)
+			continue
+		}
+		optedIn = append(optedIn, *cd)
+	}
+	return optedIn, nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+)
+
+// reconcileServiceDependencies registers the dependencies of
+// spec.serviceDependencies for the cluster's PagerDuty service. The service
+// graph is only informative, so failing to register them is logged and
+// retried on the next resync.
+func (r *ReconcilePagerDutyIntegration) reconcileServiceDependencies(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, pdData *pd.Data) {
+	if pdi.Spec.ServiceDependencies == nil {
+		return
+	}
+
+	err := pdclient.SetServiceDependencies(pdData, pdi.Spec.ServiceDependencies.HubServiceID, pdi.Spec.ServiceDependencies.BusinessServiceID)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to register PagerDuty service dependencies", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name, "ServiceID", pdData.ServiceID)
+	}
+}
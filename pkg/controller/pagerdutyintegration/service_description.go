@@ -0,0 +1,55 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+)
+
+// setClusterMetadata sets the metadata of cd the description of its PagerDuty
+// service lists, so responders see more than the name of the service
+func setClusterMetadata(cd *hivev1.ClusterDeployment, pdData *pd.Data) {
+	metadata := &pd.ClusterMetadata{
+		ConsoleURL: cd.Status.WebConsoleURL,
+	}
+	if cd.Spec.ClusterMetadata != nil {
+		metadata.ClusterUUID = cd.Spec.ClusterMetadata.ClusterID
+	}
+	metadata.CloudProvider, metadata.Region = cloudPlatform(cd.Spec.Platform)
+	pdData.Metadata = metadata
+}
+
+// cloudPlatform returns the name of the platform of a cluster and its
+// region, empty for platforms without regions
+func cloudPlatform(platform hivev1.Platform) (string, string) {
+	switch {
+	case platform.AWS != nil:
+		return "aws", platform.AWS.Region
+	case platform.Azure != nil:
+		return "azure", platform.Azure.Region
+	case platform.GCP != nil:
+		return "gcp", platform.GCP.Region
+	case platform.BareMetal != nil:
+		return "baremetal", ""
+	case platform.OpenStack != nil:
+		return "openstack", ""
+	case platform.VSphere != nil:
+		return "vsphere", ""
+	case platform.Ovirt != nil:
+		return "ovirt", ""
+	}
+	return "", ""
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"testing"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/apis/hive/v1/aws"
+	"github.com/openshift/hive/pkg/apis/hive/v1/baremetal"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetClusterMetadata(t *testing.T) {
+	tests := []struct {
+		name           string
+		platform       hivev1.Platform
+		metadata       *hivev1.ClusterMetadata
+		consoleURL     string
+		expectMetadata *pd.ClusterMetadata
+	}{
+		{
+			name:           "Test Not Installed",
+			expectMetadata: &pd.ClusterMetadata{},
+		},
+		{
+			name:       "Test AWS Cluster",
+			platform:   hivev1.Platform{AWS: &aws.Platform{Region: "us-east-1"}},
+			metadata:   &hivev1.ClusterMetadata{ClusterID: "test-cluster-uuid"},
+			consoleURL: "https://console.example.com",
+			expectMetadata: &pd.ClusterMetadata{
+				ClusterUUID:   "test-cluster-uuid",
+				CloudProvider: "aws",
+				Region:        "us-east-1",
+				ConsoleURL:    "https://console.example.com",
+			},
+		},
+		{
+			name:     "Test Platform Without Region",
+			platform: hivev1.Platform{BareMetal: &baremetal.Platform{}},
+			expectMetadata: &pd.ClusterMetadata{
+				CloudProvider: "baremetal",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment(true, true, true, false)
+			cd.Spec.Platform = test.platform
+			cd.Spec.ClusterMetadata = test.metadata
+			cd.Status.WebConsoleURL = test.consoleURL
+			pdData := &pd.Data{}
+
+			setClusterMetadata(cd, pdData)
+
+			assert.Equal(t, test.expectMetadata, pdData.Metadata)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+)
+
+// setServiceName passes spec.serviceNameTemplate on to the PagerDuty service
+// of cd, along with the labels it may refer to. Without it the service is
+// named after the servicePrefix, cluster name and base domain.
+func setServiceName(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, pdData *pd.Data) {
+	if pdi.Spec.ServiceNameTemplate == "" {
+		return
+	}
+	pdData.ServiceNameTemplate = pdi.Spec.ServiceNameTemplate
+	pdData.Labels = cd.Labels
+}
@@ -0,0 +1,140 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// servicePrefixRenameRetryInterval is how often a rename of the
+// servicePrefix waiting for Hive to apply the renamed SyncSets is checked
+// again, in case a change of a ClusterSync was missed
+const servicePrefixRenameRetryInterval = 5 * time.Minute
+
+// renameServicePrefix carries a change of spec.servicePrefix over to the
+// clusters set up under the previous one, recorded in status.servicePrefix,
+// instead of setting them up again under the new one. Their ConfigMaps,
+// Secrets, SyncSets, Lease and PagerDutyService are renamed in place the way naming.Migrate
+// renames those of a previous naming scheme, and their PagerDuty services
+// are renamed, so neither are duplicated. It returns the servicePrefix to
+// record in the status, which only switches to the new one once nothing is
+// named after the previous one anymore, and whether the rename is still
+// pending. Additional services are not renamed.
+func (r *ReconcilePagerDutyIntegration) renameServicePrefix(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cds []hivev1.ClusterDeployment) (string, bool, error) {
+	oldPrefix, newPrefix := pdi.Status.ServicePrefix, pdi.Spec.ServicePrefix
+	if oldPrefix == "" || oldPrefix == newPrefix {
+		return newPrefix, false, nil
+	}
+
+	r.reqLogger.Info("Renaming clusters to the new servicePrefix", "OldServicePrefix", oldPrefix, "ServicePrefix", newPrefix)
+	finalizer := config.PagerDutyFinalizerPrefix + pdi.Name
+	pending := false
+	renamed := 0
+	for i := range cds {
+		cd := &cds[i]
+		if !utils.HasFinalizer(cd, finalizer) {
+			continue
+		}
+
+		err := naming.MigratePrefix(r.client, r.reqLogger, cd.Namespace, oldPrefix, newPrefix, cd.Name)
+		if err != nil {
+			return oldPrefix, true, err
+		}
+
+		err = r.renamePagerDutyService(pdi, cd, oldPrefix, newPrefix)
+		if err != nil {
+			return oldPrefix, true, err
+		}
+
+		err = r.renameService(pdclient, pdi, cd)
+		if err != nil {
+			return oldPrefix, true, err
+		}
+
+		left, err := naming.PrefixPending(r.client, cd.Namespace, oldPrefix, newPrefix, cd.Name)
+		if err != nil {
+			return oldPrefix, true, err
+		}
+		pending = pending || left
+		renamed++
+	}
+
+	if pending {
+		return oldPrefix, true, nil
+	}
+	r.reqLogger.Info("Renamed clusters to the new servicePrefix", "OldServicePrefix", oldPrefix, "ServicePrefix", newPrefix, "Clusters", renamed)
+	if r.recorder != nil {
+		r.recorder.Eventf(pdi, corev1.EventTypeNormal, "ServicePrefixRenamed",
+			"Renamed the objects and PagerDuty services of %d clusters from servicePrefix %s to %s", renamed, oldPrefix, newPrefix)
+	}
+	return newPrefix, false, nil
+}
+
+// renamePagerDutyService moves the PagerDutyService of cd, named like its
+// ConfigMap, to the name it has under newPrefix
+func (r *ReconcilePagerDutyIntegration) renamePagerDutyService(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, oldPrefix, newPrefix string) error {
+	pdService, err := r.pagerDutyService(cd.Namespace, naming.ConfigMapName(oldPrefix, cd.Name))
+	if err != nil || pdService == nil {
+		return err
+	}
+
+	// the status is copied into the new one on the next verification
+	err = r.savePagerDutyService(pdi, cd, naming.ConfigMapName(newPrefix, cd.Name), pdService.Spec.ServiceID, pdService.Spec.IntegrationID)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	r.reqLogger.Info("Deleting PagerDutyService named after the previous servicePrefix", "Namespace", cd.Namespace, "Name", pdService.Name)
+	err = r.client.Delete(context.TODO(), pdService)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// renameService gives the PagerDuty service recorded in the renamed
+// ConfigMap of cd the name it has under the new servicePrefix
+func (r *ReconcilePagerDutyIntegration) renameService(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment) error {
+	pdData := &pd.Data{
+		ClusterID:     cd.Spec.ClusterName,
+		BaseDomain:    cd.Spec.BaseDomain,
+		ServicePrefix: pdi.Spec.ServicePrefix,
+		NormalizeName: pdi.Spec.NormalizeServiceNames,
+	}
+	setServiceName(pdi, cd, pdData)
+	err := pdData.ParseClusterConfig(r.client, cd.Namespace, naming.ConfigMapName(pdi.Spec.ServicePrefix, cd.Name))
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// no service was set up
+			return nil
+		}
+		return err
+	}
+
+	err = pdclient.RenameService(pdData)
+	if err != nil && !pd.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
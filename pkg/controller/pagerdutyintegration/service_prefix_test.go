@@ -0,0 +1,140 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/kube"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	mockpd "github.com/openshift/pagerduty-operator/pkg/pagerduty/mock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRenameServicePrefix(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	const newPrefix = "renamed"
+	pdService := func() runtime.Object {
+		return kube.GeneratePagerDutyService(testNamespace, naming.ConfigMapName(testServicePrefix, testClusterName), testPagerDutyIntegrationName, testClusterName, testServiceID, testIntegrationID)
+	}
+
+	tests := []struct {
+		name          string
+		statusPrefix  string
+		objects       []runtime.Object
+		expectRename  bool
+		expectPrefix  string
+		expectPending bool
+	}{
+		{
+			name:         "Test Prefix Not Recorded Yet",
+			objects:      []runtime.Object{testCDConfigMap()},
+			expectPrefix: newPrefix,
+		},
+		{
+			name:         "Test Prefix Unchanged",
+			statusPrefix: newPrefix,
+			objects:      []runtime.Object{testCDConfigMap()},
+			expectPrefix: newPrefix,
+		},
+		{
+			name:         "Test Prefix Changed",
+			statusPrefix: testServicePrefix,
+			objects:      []runtime.Object{testCDConfigMap(), pdService()},
+			expectRename: true,
+			expectPrefix: newPrefix,
+		},
+		{
+			name:          "Test Prefix Changed Waiting For SyncSet",
+			statusPrefix:  testServicePrefix,
+			objects:       []runtime.Object{testCDConfigMap(), testCDSecret(), testCDSyncSet()},
+			expectRename:  true,
+			expectPrefix:  testServicePrefix,
+			expectPending: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			cd := testClusterDeployment(true, true, true, false)
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.ServicePrefix = newPrefix
+			pdi.Status.ServicePrefix = test.statusPrefix
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockPDClient := mockpd.NewMockClient(mockCtrl)
+			if test.expectRename {
+				mockPDClient.EXPECT().RenameService(gomock.Any()).DoAndReturn(func(data *pd.Data) error {
+					assert.Equal(t, newPrefix, data.ServicePrefix)
+					assert.Equal(t, testServiceID, data.ServiceID)
+					return nil
+				}).Times(1)
+			}
+
+			r := &ReconcilePagerDutyIntegration{
+				client:    fakekubeclient.NewFakeClientWithScheme(scheme.Scheme, append(test.objects, cd, pdi)...),
+				scheme:    scheme.Scheme,
+				reqLogger: log,
+			}
+
+			// Act
+			prefix, pending, err := r.renameServicePrefix(mockPDClient, pdi, []hivev1.ClusterDeployment{*cd})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectPrefix, prefix)
+			assert.Equal(t, test.expectPending, pending)
+
+			cm := &corev1.ConfigMap{}
+			err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: naming.ConfigMapName(newPrefix, testClusterName)}, cm)
+			if !test.expectRename {
+				assert.True(t, kerrors.IsNotFound(err), "ConfigMap renamed without a change of servicePrefix")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testServiceID, cm.Data["SERVICE_ID"])
+			err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: naming.ConfigMapName(testServicePrefix, testClusterName)}, &corev1.ConfigMap{})
+			assert.True(t, kerrors.IsNotFound(err), "ConfigMap kept under the previous servicePrefix")
+
+			if test.expectPending {
+				return
+			}
+			renamed := &pagerdutyv1alpha1.PagerDutyService{}
+			err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: naming.ConfigMapName(newPrefix, testClusterName)}, renamed)
+			assert.NoError(t, err)
+			assert.Equal(t, testServiceID, renamed.Spec.ServiceID)
+			assert.Equal(t, testIntegrationID, renamed.Spec.IntegrationID)
+			err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: naming.ConfigMapName(testServicePrefix, testClusterName)}, &pagerdutyv1alpha1.PagerDutyService{})
+			assert.True(t, kerrors.IsNotFound(err), "PagerDutyService kept under the previous servicePrefix")
+		})
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+)
+
+// reconcileServiceTags sets the ownership tags of spec.serviceTags on the
+// cluster's PagerDuty service. Tags are only used for reporting, so failing
+// to set them is logged and retried on the next resync.
+func (r *ReconcilePagerDutyIntegration) reconcileServiceTags(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, pdData *pd.Data) {
+	if pdi.Spec.ServiceTags == nil {
+		return
+	}
+
+	tags := map[string]string{
+		config.ServiceTagCostCenter:  pdi.Spec.ServiceTags.CostCenter,
+		config.ServiceTagOwner:       pdi.Spec.ServiceTags.Owner,
+		config.ServiceTagEnvironment: pdi.Spec.ServiceTags.Environment,
+	}
+	err := pdclient.SetServiceTags(pdData, tags)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to set PagerDuty service tags", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name, "ServiceID", pdData.ServiceID)
+	}
+}
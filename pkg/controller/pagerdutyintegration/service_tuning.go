@@ -0,0 +1,132 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// suggestServiceTuning lists the incidents of the services of the given
+// clusters once the window of spec.serviceTuning has passed since the last
+// listing, and suggests how to tune the services whose incidents call for
+// it. New suggestions are also reported as events on the PDI. In between,
+// clusters keep their previous suggestions. It returns the time of the last
+// listing and how long until the next, 0 when the suggestions are disabled.
+func (r *ReconcilePagerDutyIntegration) suggestServiceTuning(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, clusters []pagerdutyv1alpha1.ClusterStatus) (*metav1.Time, time.Duration, error) {
+	if pdi.Spec.ServiceTuning == nil {
+		for i := range clusters {
+			clusters[i].Suggestions = nil
+		}
+		return nil, 0, nil
+	}
+
+	window := config.ServiceTuningDefaultWindow
+	if pdi.Spec.ServiceTuning.Window != nil {
+		window = pdi.Spec.ServiceTuning.Window.Duration
+	}
+	if window < config.ServiceTuningMinWindow {
+		window = config.ServiceTuningMinWindow
+	}
+	minIncidents := pdi.Spec.ServiceTuning.MinIncidents
+	if minIncidents == 0 {
+		minIncidents = config.ServiceTuningDefaultMinIncidents
+	}
+
+	now := r.now()
+	if last := pdi.Status.LastServiceTuningTime; last != nil {
+		if wait := last.Add(window).Sub(now); wait > 0 {
+			return last, wait, nil
+		}
+	}
+
+	serviceIDs := []string{}
+	for _, cluster := range clusters {
+		if cluster.ServiceID != "" && cluster.State != pagerdutyv1alpha1.ClusterStateDeleting {
+			serviceIDs = append(serviceIDs, cluster.ServiceID)
+		}
+	}
+
+	stats, err := pdclient.ServiceIncidentStats(serviceIDs, now.Add(-window), now)
+	if err != nil {
+		// suggesting again in a window is good enough
+		r.reqLogger.Error(err, "Failed to list the incidents of the PagerDuty services")
+		return pdi.Status.LastServiceTuningTime, window, nil
+	}
+
+	for i := range clusters {
+		cluster := &clusters[i]
+		if cluster.State == pagerdutyv1alpha1.ClusterStateDeleting {
+			continue
+		}
+		s, ok := stats[cluster.ServiceID]
+		if !ok {
+			cluster.Suggestions = nil
+			continue
+		}
+
+		suggestions := serviceSuggestions(s, minIncidents, window)
+		for _, suggestion := range suggestions {
+			if !hasSuggestion(cluster.Suggestions, suggestion.Type) {
+				r.reqLogger.Info("Suggesting to tune the PagerDuty service of cluster", "ClusterDeployment.Namespace", cluster.ClusterDeploymentNamespace, "ClusterDeployment.Name", cluster.ClusterDeploymentName, "Suggestion", suggestion.Type)
+				r.recorder.Eventf(pdi, corev1.EventTypeNormal, "ServiceTuningSuggested",
+					"%s suggested for service %s of cluster %s/%s: %s", suggestion.Type, cluster.ServiceID, cluster.ClusterDeploymentNamespace, cluster.ClusterDeploymentName, suggestion.Message)
+			}
+		}
+		cluster.Suggestions = suggestions
+	}
+
+	return &metav1.Time{Time: now}, window, nil
+}
+
+// serviceSuggestions returns the suggestions the incident stats of a
+// service over window call for, none when the service got fewer than
+// minIncidents incidents.
+func serviceSuggestions(s pd.IncidentStats, minIncidents int, window time.Duration) []pagerdutyv1alpha1.ServiceSuggestion {
+	if s.Incidents < minIncidents {
+		return nil
+	}
+
+	var suggestions []pagerdutyv1alpha1.ServiceSuggestion
+	if float64(s.DuplicateIncidents) >= config.ServiceTuningDuplicateRatio*float64(s.Incidents) {
+		suggestions = append(suggestions, pagerdutyv1alpha1.ServiceSuggestion{
+			Type:    pagerdutyv1alpha1.ServiceSuggestionEnableAlertGrouping,
+			Message: fmt.Sprintf("%d of %d incidents in the last %s repeat an earlier one", s.DuplicateIncidents, s.Incidents, window),
+		})
+	}
+	if float64(s.AutoResolvedIncidents) >= config.ServiceTuningAutoResolvedRatio*float64(s.Incidents) {
+		suggestions = append(suggestions, pagerdutyv1alpha1.ServiceSuggestion{
+			Type:    pagerdutyv1alpha1.ServiceSuggestionPauseTransientAlerts,
+			Message: fmt.Sprintf("%d of %d incidents in the last %s resolved on their own", s.AutoResolvedIncidents, s.Incidents, window),
+		})
+	}
+	return suggestions
+}
+
+// hasSuggestion returns true if suggestions has one of type suggestionType
+func hasSuggestion(suggestions []pagerdutyv1alpha1.ServiceSuggestion, suggestionType pagerdutyv1alpha1.ServiceSuggestionType) bool {
+	for _, suggestion := range suggestions {
+		if suggestion.Type == suggestionType {
+			return true
+		}
+	}
+	return false
+}
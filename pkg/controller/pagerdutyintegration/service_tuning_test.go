@@ -0,0 +1,136 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	mockpd "github.com/openshift/pagerduty-operator/pkg/pagerduty/mock"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestSuggestServiceTuning(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	grouping := pagerdutyv1alpha1.ServiceSuggestion{Type: pagerdutyv1alpha1.ServiceSuggestionEnableAlertGrouping, Message: "8 of 10 incidents in the last 168h0m0s repeat an earlier one"}
+	pausing := pagerdutyv1alpha1.ServiceSuggestion{Type: pagerdutyv1alpha1.ServiceSuggestionPauseTransientAlerts, Message: "9 of 10 incidents in the last 168h0m0s resolved on their own"}
+
+	tests := []struct {
+		name              string
+		tuning            *pagerdutyv1alpha1.ServiceTuning
+		lastTuning        *metav1.Time
+		previous          []pagerdutyv1alpha1.ServiceSuggestion
+		setupPDMock       func(*mockpd.MockClientMockRecorder)
+		expectSuggestions []pagerdutyv1alpha1.ServiceSuggestion
+		expectEvents      int
+	}{
+		{
+			name:   "Test Noisy Service Suggested",
+			tuning: &pagerdutyv1alpha1.ServiceTuning{},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.ServiceIncidentStats([]string{testServiceID}, now.Add(-config.ServiceTuningDefaultWindow), now).
+					Return(map[string]pd.IncidentStats{testServiceID: {Incidents: 10, DuplicateIncidents: 8, AutoResolvedIncidents: 9}}, nil).Times(1)
+			},
+			expectSuggestions: []pagerdutyv1alpha1.ServiceSuggestion{grouping, pausing},
+			expectEvents:      2,
+		},
+		{
+			name:     "Test Only New Suggestions Reported",
+			tuning:   &pagerdutyv1alpha1.ServiceTuning{},
+			previous: []pagerdutyv1alpha1.ServiceSuggestion{grouping},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.ServiceIncidentStats(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(map[string]pd.IncidentStats{testServiceID: {Incidents: 10, DuplicateIncidents: 8, AutoResolvedIncidents: 9}}, nil).Times(1)
+			},
+			expectSuggestions: []pagerdutyv1alpha1.ServiceSuggestion{grouping, pausing},
+			expectEvents:      1,
+		},
+		{
+			name:     "Test Too Few Incidents",
+			tuning:   &pagerdutyv1alpha1.ServiceTuning{MinIncidents: 20},
+			previous: []pagerdutyv1alpha1.ServiceSuggestion{grouping},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.ServiceIncidentStats(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(map[string]pd.IncidentStats{testServiceID: {Incidents: 10, DuplicateIncidents: 8, AutoResolvedIncidents: 9}}, nil).Times(1)
+			},
+		},
+		{
+			name:       "Test Listing Not Due",
+			tuning:     &pagerdutyv1alpha1.ServiceTuning{},
+			lastTuning: &metav1.Time{Time: now.Add(-time.Hour)},
+			previous:   []pagerdutyv1alpha1.ServiceSuggestion{grouping},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.ServiceIncidentStats(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectSuggestions: []pagerdutyv1alpha1.ServiceSuggestion{grouping},
+		},
+		{
+			name:     "Test Disabled",
+			previous: []pagerdutyv1alpha1.ServiceSuggestion{grouping},
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.ServiceIncidentStats(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			pdi := testPagerDutyIntegration()
+			pdi.Spec.ServiceTuning = test.tuning
+			pdi.Status.LastServiceTuningTime = test.lastTuning
+			clusters := []pagerdutyv1alpha1.ClusterStatus{
+				{
+					ClusterDeploymentNamespace: testNamespace,
+					ClusterDeploymentName:      testClusterName,
+					ServiceID:                  testServiceID,
+					State:                      pagerdutyv1alpha1.ClusterStateReady,
+					Suggestions:                test.previous,
+				},
+			}
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockPDClient := mockpd.NewMockClient(mockCtrl)
+			test.setupPDMock(mockPDClient.EXPECT())
+
+			recorder := record.NewFakeRecorder(10)
+			r := &ReconcilePagerDutyIntegration{
+				reqLogger: log,
+				recorder:  recorder,
+				clock:     func() time.Time { return now },
+			}
+
+			// Act
+			_, next, err := r.suggestServiceTuning(mockPDClient, pdi, clusters)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectSuggestions, clusters[0].Suggestions)
+			assert.Len(t, recorder.Events, test.expectEvents)
+			if test.tuning == nil {
+				assert.Zero(t, next)
+			} else {
+				assert.NotZero(t, next)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/kube"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reconcileSelectorSyncSet makes the SelectorSyncSet delivering the shared
+// integration key to the fleet match spec.sharedIntegrationKey, deleting it
+// when the key is not shared. The SelectorSyncSet is cluster scoped, so the
+// PDI can't own it and it is deleted with the PDI instead.
+func (r *ReconcilePagerDutyIntegration) reconcileSelectorSyncSet(pdi *pagerdutyv1alpha1.PagerDutyIntegration) error {
+	if pdi.Spec.SharedIntegrationKey == nil {
+		return r.deleteSelectorSyncSet(pdi)
+	}
+
+	name := naming.SelectorSyncSetName(pdi.Name)
+	expected := kube.GenerateSelectorSyncSet(name, pdi)
+
+	sss := &hivev1.SelectorSyncSet{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: name}, sss)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		r.reqLogger.Info("Creating shared integration key selectorsyncset", "Name", name)
+		setOwnerLabel(expected, pdi)
+		return r.client.Create(context.TODO(), expected)
+	}
+
+	if !equality.Semantic.DeepEqual(sss.Spec, expected.Spec) {
+		r.reqLogger.Info("Updating shared integration key selectorsyncset", "Name", name)
+		sss.Spec = expected.Spec
+		return r.client.Update(context.TODO(), sss)
+	}
+
+	return nil
+}
+
+// deleteSelectorSyncSet deletes the SelectorSyncSet delivering the shared
+// integration key of the PDI, if there is one
+func (r *ReconcilePagerDutyIntegration) deleteSelectorSyncSet(pdi *pagerdutyv1alpha1.PagerDutyIntegration) error {
+	name := naming.SelectorSyncSetName(pdi.Name)
+
+	sss := &hivev1.SelectorSyncSet{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: name}, sss)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	r.reqLogger.Info("Deleting shared integration key selectorsyncset", "Name", name)
+	err = r.client.Delete(context.TODO(), sss)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reconcileSilencedAnnotation disables the service of a cluster while its
+// ClusterDeployment has the silenced annotation or its
+// PagerDutyClusterConfig disables paging, and enables it again once neither
+// does. The service being disabled by the operator is recorded in the
+// cluster's ConfigMap, so services disabled by hand are not enabled.
+func (r *ReconcilePagerDutyIntegration) reconcileSilencedAnnotation(pdclient pd.Client, cd *hivev1.ClusterDeployment, clusterConfig *pagerdutyv1alpha1.PagerDutyClusterConfig, configMapName string, pdData *pd.Data) error {
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: configMapName, Namespace: cd.Namespace}, cm)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// the service isn't created yet
+			return nil
+		}
+		return err
+	}
+
+	annotated := cd.Annotations[config.ClusterDeploymentSilencedAnnotation] == "true"
+	silenced := annotated || pagingDisabled(clusterConfig)
+	disabled := cm.Data[config.ServiceDisabledKey] == "true"
+
+	if silenced == disabled {
+		return nil
+	}
+
+	if silenced {
+		r.reqLogger.Info("Disabling PD service of silenced cluster")
+		err = pdclient.DisableService(pdData)
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[config.ServiceDisabledKey] = "true"
+		if annotated {
+			r.recorder.Eventf(cd, corev1.EventTypeNormal, "ServiceDisabled",
+				"Disabled PagerDuty service %s while annotation %s is set", pdData.ServiceID, config.ClusterDeploymentSilencedAnnotation)
+		} else {
+			r.recorder.Eventf(cd, corev1.EventTypeNormal, "ServiceDisabled",
+				"Disabled PagerDuty service %s while PagerDutyClusterConfig %s disables paging", pdData.ServiceID, clusterConfig.Name)
+		}
+		return r.client.Update(context.TODO(), cm)
+	}
+
+	r.reqLogger.Info("Enabling PD service of cluster no longer silenced")
+	err = pdclient.EnableService(pdData)
+	if err != nil && !pd.IsNotFound(err) {
+		return err
+	}
+	delete(cm.Data, config.ServiceDisabledKey)
+	r.recorder.Eventf(cd, corev1.EventTypeNormal, "ServiceEnabled",
+		"Enabled PagerDuty service %s, neither annotation %s nor a PagerDutyClusterConfig disables paging any longer", pdData.ServiceID, config.ClusterDeploymentSilencedAnnotation)
+	return r.client.Update(context.TODO(), cm)
+}
@@ -0,0 +1,144 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	mockpd "github.com/openshift/pagerduty-operator/pkg/pagerduty/mock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileSilencedAnnotation(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name           string
+		silenced       string
+		disabled       bool
+		disablePaging  bool
+		setupPDMock    func(*mockpd.MockClientMockRecorder)
+		expectDisabled bool
+		expectEvents   int
+	}{
+		{
+			name:     "Test Silenced Cluster Disabled",
+			silenced: "true",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DisableService(gomock.Any()).Return(nil).Times(1)
+			},
+			expectDisabled: true,
+			expectEvents:   1,
+		},
+		{
+			name:     "Test Disabled Cluster Left Alone",
+			silenced: "true",
+			disabled: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DisableService(gomock.Any()).Times(0)
+			},
+			expectDisabled: true,
+		},
+		{
+			name:     "Test Annotation Removed",
+			disabled: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.EnableService(gomock.Any()).Return(nil).Times(1)
+			},
+			expectEvents: 1,
+		},
+		{
+			name:          "Test Paging Disabled By Cluster Config",
+			disablePaging: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DisableService(gomock.Any()).Return(nil).Times(1)
+			},
+			expectDisabled: true,
+			expectEvents:   1,
+		},
+		{
+			name:          "Test Annotation Removed While Paging Disabled",
+			disabled:      true,
+			disablePaging: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.EnableService(gomock.Any()).Times(0)
+			},
+			expectDisabled: true,
+		},
+		{
+			name:     "Test Annotation Not True",
+			silenced: "false",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DisableService(gomock.Any()).Times(0)
+				r.EnableService(gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			cd := testClusterDeployment(true, true, true, false)
+			if test.silenced != "" {
+				cd.Annotations = map[string]string{config.ClusterDeploymentSilencedAnnotation: test.silenced}
+			}
+			cm := testCDConfigMap()
+			if test.disabled {
+				cm.Data[config.ServiceDisabledKey] = "true"
+			}
+			clusterConfig := &pagerdutyv1alpha1.PagerDutyClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: cd.Namespace},
+				Spec:       pagerdutyv1alpha1.PagerDutyClusterConfigSpec{DisablePaging: test.disablePaging},
+			}
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockPDClient := mockpd.NewMockClient(mockCtrl)
+			test.setupPDMock(mockPDClient.EXPECT())
+
+			recorder := record.NewFakeRecorder(10)
+			r := &ReconcilePagerDutyIntegration{
+				client:    fakekubeclient.NewFakeClient(cd, cm),
+				reqLogger: log,
+				recorder:  recorder,
+			}
+
+			// Act
+			err := r.reconcileSilencedAnnotation(mockPDClient, cd, clusterConfig, cm.Name, &pd.Data{ServiceID: testServiceID})
+
+			// Assert
+			assert.NoError(t, err)
+			updated := &corev1.ConfigMap{}
+			err = r.client.Get(context.TODO(), types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, updated)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectDisabled, updated.Data[config.ServiceDisabledKey] == "true")
+			assert.Len(t, recorder.Events, test.expectEvents)
+		})
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// activeSilences lists the silences currently muting any of the given
+// ClusterDeployments of the PDI, whether from a PagerDutySilence, the
+// noalerts label, the silenced annotation or a PagerDutyClusterConfig
+// disabling paging.
+func (r *ReconcilePagerDutyIntegration) activeSilences(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cds []hivev1.ClusterDeployment) ([]pagerdutyv1alpha1.ActiveSilence, error) {
+	silenceList := &pagerdutyv1alpha1.PagerDutySilenceList{}
+	err := r.client.List(context.TODO(), silenceList, &client.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	configList := &pagerdutyv1alpha1.PagerDutyClusterConfigList{}
+	err = r.client.List(context.TODO(), configList, &client.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	now := r.now()
+	silences := []pagerdutyv1alpha1.ActiveSilence{}
+	for _, cd := range cds {
+		if cd.Labels[config.ClusterDeploymentNoalertsLabel] == "true" {
+			silences = append(silences, pagerdutyv1alpha1.ActiveSilence{
+				ClusterDeploymentNamespace: cd.Namespace,
+				ClusterDeploymentName:      cd.Name,
+				Source:                     pagerdutyv1alpha1.SilenceSourceNoalertsLabel,
+			})
+		}
+		if cd.Annotations[config.ClusterDeploymentSilencedAnnotation] == "true" {
+			silences = append(silences, pagerdutyv1alpha1.ActiveSilence{
+				ClusterDeploymentNamespace: cd.Namespace,
+				ClusterDeploymentName:      cd.Name,
+				Source:                     pagerdutyv1alpha1.SilenceSourceSilencedAnnotation,
+			})
+		}
+		if cfg := selectClusterConfig(configList.Items, pdi, &cd); pagingDisabled(cfg) {
+			silences = append(silences, pagerdutyv1alpha1.ActiveSilence{
+				ClusterDeploymentNamespace: cd.Namespace,
+				ClusterDeploymentName:      cd.Name,
+				Source:                     pagerdutyv1alpha1.SilenceSourcePagerDutyClusterConfig,
+				Reason:                     "Paging disabled by PagerDutyClusterConfig " + cfg.Name,
+			})
+		}
+
+		for _, silence := range silenceList.Items {
+			if silence.Namespace != cd.Namespace || silence.Spec.ClusterDeploymentRef.Name != cd.Name {
+				continue
+			}
+			if silence.DeletionTimestamp != nil {
+				continue
+			}
+			expiresAt := silence.CreationTimestamp.Add(silence.Spec.Duration.Duration)
+			if !now.Before(expiresAt) {
+				continue
+			}
+
+			silences = append(silences, pagerdutyv1alpha1.ActiveSilence{
+				ClusterDeploymentNamespace: cd.Namespace,
+				ClusterDeploymentName:      cd.Name,
+				Source:                     pagerdutyv1alpha1.SilenceSourcePagerDutySilence,
+				SilenceName:                silence.Name,
+				Reason:                     silence.Spec.Reason,
+				Requester:                  silence.Spec.Requester,
+				ExpiresAt:                  &metav1.Time{Time: expiresAt},
+			})
+		}
+	}
+
+	return silences, nil
+}
+
+// liftStaleSilences re-enables alerting for any of the given ClusterDeployments
+// that has been muted for longer than the PagerDutyIntegration's
+// maxSilenceDuration. It returns the stale silences to report in status and
+// how long until the next silence would become stale, or 0 if none.
+func (r *ReconcilePagerDutyIntegration) liftStaleSilences(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cds []hivev1.ClusterDeployment) ([]pagerdutyv1alpha1.StaleSilence, time.Duration, error) {
+	// keep reporting silences lifted earlier while their cluster is still selected
+	stale := []pagerdutyv1alpha1.StaleSilence{}
+	for _, s := range pdi.Status.StaleSilences {
+		for _, cd := range cds {
+			if s.ClusterDeploymentNamespace == cd.Namespace && s.ClusterDeploymentName == cd.Name {
+				stale = append(stale, s)
+				break
+			}
+		}
+	}
+
+	if pdi.Spec.MaxSilenceDuration == nil || pdi.Spec.MaxSilenceDuration.Duration <= 0 {
+		return stale, 0, nil
+	}
+	maxDuration := pdi.Spec.MaxSilenceDuration.Duration
+
+	silenceList := &pagerdutyv1alpha1.PagerDutySilenceList{}
+	err := r.client.List(context.TODO(), silenceList, &client.ListOptions{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := r.now()
+	var next time.Duration
+	// remember the earliest time at which a silence still in effect goes stale
+	scheduleCheck := func(staleAt time.Time) {
+		wait := staleAt.Sub(now)
+		if next == 0 || wait < next {
+			next = wait
+		}
+	}
+
+	for i := range cds {
+		// update in place so the caller keeps working with the latest resourceVersion
+		cd := &cds[i]
+		if cd.DeletionTimestamp != nil {
+			continue
+		}
+
+		since, err := r.noalertsSince(cd, now)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !since.IsZero() {
+			if now.Sub(since) < maxDuration {
+				scheduleCheck(since.Add(maxDuration))
+			} else {
+				delete(cd.Labels, config.ClusterDeploymentNoalertsLabel)
+				delete(cd.Annotations, config.ClusterDeploymentNoalertsSinceAnnotation)
+				err = r.client.Update(context.TODO(), cd)
+				if err != nil {
+					return nil, 0, err
+				}
+				r.reqLogger.Info("Lifted stale noalerts label", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name)
+				r.recorder.Eventf(cd, corev1.EventTypeWarning, "StaleSilenceLifted",
+					"Removed label %s after the cluster was muted for more than %s", config.ClusterDeploymentNoalertsLabel, maxDuration)
+				stale = append(stale, pagerdutyv1alpha1.StaleSilence{
+					ClusterDeploymentNamespace: cd.Namespace,
+					ClusterDeploymentName:      cd.Name,
+					Source:                     pagerdutyv1alpha1.SilenceSourceNoalertsLabel,
+					LiftedAt:                   metav1.NewTime(now),
+				})
+			}
+		}
+
+		for j := range silenceList.Items {
+			silence := &silenceList.Items[j]
+			if silence.Namespace != cd.Namespace || silence.Spec.ClusterDeploymentRef.Name != cd.Name {
+				continue
+			}
+			if silence.DeletionTimestamp != nil {
+				continue
+			}
+			if !now.Before(silence.CreationTimestamp.Add(silence.Spec.Duration.Duration)) {
+				// already expired, nothing to lift
+				continue
+			}
+			staleAt := silence.CreationTimestamp.Add(maxDuration)
+			if now.Before(staleAt) {
+				scheduleCheck(staleAt)
+				continue
+			}
+
+			// deleting the silence makes the PagerDutySilence controller end its maintenance windows
+			err = r.client.Delete(context.TODO(), silence)
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, 0, err
+			}
+			r.reqLogger.Info("Lifted stale PagerDutySilence", "PagerDutySilence.Namespace", silence.Namespace, "PagerDutySilence.Name", silence.Name)
+			r.recorder.Eventf(cd, corev1.EventTypeWarning, "StaleSilenceLifted",
+				"Deleted PagerDutySilence %s requested by %s after the cluster was muted for more than %s", silence.Name, silence.Spec.Requester, maxDuration)
+			stale = append(stale, pagerdutyv1alpha1.StaleSilence{
+				ClusterDeploymentNamespace: cd.Namespace,
+				ClusterDeploymentName:      cd.Name,
+				Source:                     pagerdutyv1alpha1.SilenceSourcePagerDutySilence,
+				SilenceName:                silence.Name,
+				Requester:                  silence.Spec.Requester,
+				LiftedAt:                   metav1.NewTime(now),
+			})
+		}
+	}
+
+	return stale, next, nil
+}
+
+// noalertsSince returns when the noalerts label was first seen on the
+// ClusterDeployment, or the zero time if it isn't set. The time is kept in
+// an annotation, which is added or removed to track the label.
+func (r *ReconcilePagerDutyIntegration) noalertsSince(cd *hivev1.ClusterDeployment, now time.Time) (time.Time, error) {
+	annotation, hasAnnotation := cd.Annotations[config.ClusterDeploymentNoalertsSinceAnnotation]
+
+	if cd.Labels[config.ClusterDeploymentNoalertsLabel] != "true" {
+		if hasAnnotation {
+			delete(cd.Annotations, config.ClusterDeploymentNoalertsSinceAnnotation)
+			return time.Time{}, r.client.Update(context.TODO(), cd)
+		}
+		return time.Time{}, nil
+	}
+
+	if hasAnnotation {
+		since, err := time.Parse(time.RFC3339, annotation)
+		if err == nil {
+			return since, nil
+		}
+		r.reqLogger.Info("Resetting invalid annotation", "Annotation", config.ClusterDeploymentNoalertsSinceAnnotation, "Value", annotation)
+	}
+
+	if cd.Annotations == nil {
+		cd.Annotations = map[string]string{}
+	}
+	cd.Annotations[config.ClusterDeploymentNoalertsSinceAnnotation] = now.UTC().Format(time.RFC3339)
+	return now, r.client.Update(context.TODO(), cd)
+}
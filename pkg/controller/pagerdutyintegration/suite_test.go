@@ -0,0 +1,223 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/pagerduty/fake"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// envtestTimeout bounds the wait for the controller to act on a change
+	envtestTimeout = 30 * time.Second
+	// envtestInterval is how often the API server is polled meanwhile
+	envtestInterval = 250 * time.Millisecond
+)
+
+// testEnvConfig reaches the API server of the envtest environment, nil when
+// KUBEBUILDER_ASSETS doesn't point to the etcd and kube-apiserver binaries
+var testEnvConfig *rest.Config
+
+// TestMain runs the tests against a real kube-apiserver and etcd when their
+// binaries are available, for the watches, finalizers and status updates
+// the fake client doesn't validate. The unit tests run either way.
+func TestMain(m *testing.M) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		os.Exit(m.Run())
+	}
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "..", "deploy", "crds")},
+		CRDs:                  hiveCRDs(),
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		log.Error(err, "Failed to start the envtest environment")
+		os.Exit(1)
+	}
+	testEnvConfig = cfg
+
+	code := m.Run()
+
+	if err := testEnv.Stop(); err != nil {
+		log.Error(err, "Failed to stop the envtest environment")
+	}
+	os.Exit(code)
+}
+
+// startTestManager runs the controller in a manager against the envtest
+// environment, calling PagerDuty through the real client pointed at an
+// in-memory fake.Server, and returns a client reading straight from the API
+// server along with the fake. setup, if not nil, customizes the reconciler
+// before it starts. The test is skipped without an environment, and the
+// manager stopped when it ends.
+func startTestManager(t *testing.T, setup func(*ReconcilePagerDutyIntegration)) (client.Client, *fake.Server) {
+	if testEnvConfig == nil {
+		t.Skip("KUBEBUILDER_ASSETS not set, skipping envtest")
+	}
+	if err := pagerdutyapis.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := hiveapis.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	// each test has its own API key, the clients of a key share the pages
+	// of services they list
+	server := fake.NewServer("envtest-" + t.Name())
+	t.Cleanup(server.Close)
+	server.AddEscalationPolicy(testEscalationPolicy, "test policy")
+	server.AddVendor("PVENDOR", "Prometheus")
+
+	mgr, err := manager.New(testEnvConfig, manager.Options{
+		Scheme:             scheme.Scheme,
+		MetricsBindAddress: "0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := newReconciler(mgr)
+	r.pdclient = func(APIKey string, controllerName string, opts ...pd.ClientOption) pd.Client {
+		return pd.NewClient(APIKey, controllerName, append(opts, pd.WithAPIEndpoint(server.URL))...)
+	}
+	if setup != nil {
+		setup(r)
+	}
+	if err := add(mgr, r, r.onboarding, r.offboarding); err != nil {
+		t.Fatal(err)
+	}
+
+	stop := make(chan struct{})
+	go func() {
+		if err := mgr.Start(stop); err != nil {
+			t.Error(err)
+		}
+	}()
+	t.Cleanup(func() { close(stop) })
+
+	c, err := client.New(testEnvConfig, client.Options{Scheme: scheme.Scheme})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, server
+}
+
+// createTestObjects creates objs in order, and deletes them in reverse
+// order when the test ends, waiting for each to be gone, so the controller
+// still running tears down what they set up and the next test starts
+// afresh. Namespaces are created if missing and kept, envtest doesn't run
+// the controller deleting them.
+func createTestObjects(t *testing.T, c client.Client, objs ...runtime.Object) {
+	for _, obj := range objs {
+		err := c.Create(context.TODO(), obj)
+		if _, ok := obj.(*corev1.Namespace); ok {
+			if err != nil && !errors.IsAlreadyExists(err) {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		obj := obj
+		t.Cleanup(func() {
+			if err := c.Delete(context.TODO(), obj); client.IgnoreNotFound(err) != nil {
+				t.Error(err)
+				return
+			}
+			key, err := client.ObjectKeyFromObject(obj)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			err = wait.PollImmediate(envtestInterval, envtestTimeout, func() (bool, error) {
+				err := c.Get(context.TODO(), key, obj.DeepCopyObject())
+				return errors.IsNotFound(err), client.IgnoreNotFound(err)
+			})
+			if err != nil {
+				t.Errorf("%s not deleted: %v", key, err)
+			}
+		})
+	}
+}
+
+// testEnvAPIKeySecret returns the API key secret of testPagerDutyIntegration
+// holding the key server accepts
+func testEnvAPIKeySecret(server *fake.Server) *corev1.Secret {
+	secret := testPDISecret()
+	secret.Data[config.PagerDutyAPISecretKey] = []byte(server.APIKey)
+	return secret
+}
+
+// hiveCRDs returns CRDs for the Hive resources the controller watches.
+// Their schema isn't validated, Hive's own CRDs aren't vendored.
+func hiveCRDs() []runtime.Object {
+	crds := []runtime.Object{}
+	for _, kind := range []struct {
+		group, kind, plural string
+	}{
+		{"hive.openshift.io", "ClusterDeployment", "clusterdeployments"},
+		{"hive.openshift.io", "SyncSet", "syncsets"},
+		{"hive.openshift.io", "SelectorSyncSet", "selectorsyncsets"},
+		{"hiveinternal.openshift.io", "ClusterSync", "clustersyncs"},
+	} {
+		scope := apiextensionsv1beta1.NamespaceScoped
+		if kind.kind == "SelectorSyncSet" {
+			scope = apiextensionsv1beta1.ClusterScoped
+		}
+		version := "v1"
+		if kind.group == "hiveinternal.openshift.io" {
+			version = "v1alpha1"
+		}
+		preserveUnknownFields := true
+		crds = append(crds, &apiextensionsv1beta1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: kind.plural + "." + kind.group},
+			Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{
+				Group:   kind.group,
+				Version: version,
+				Scope:   scope,
+				Names: apiextensionsv1beta1.CustomResourceDefinitionNames{
+					Kind:   kind.kind,
+					Plural: kind.plural,
+				},
+				Subresources:          &apiextensionsv1beta1.CustomResourceSubresources{Status: &apiextensionsv1beta1.CustomResourceSubresourceStatus{}},
+				PreserveUnknownFields: &preserveUnknownFields,
+			},
+		})
+	}
+	return crds
+}
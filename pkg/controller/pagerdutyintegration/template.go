@@ -0,0 +1,84 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// loadTemplate loads the PagerDutyIntegrationTemplate of spec.templateRef
+// into r.template, nil when the PagerDutyIntegration has none.
+func (r *ReconcilePagerDutyIntegration) loadTemplate(pdi *pagerdutyv1alpha1.PagerDutyIntegration) error {
+	r.template = nil
+	if pdi.Spec.TemplateRef == nil {
+		return nil
+	}
+
+	template := &pagerdutyv1alpha1.PagerDutyIntegrationTemplate{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: pdi.Namespace, Name: pdi.Spec.TemplateRef.Name}, template)
+	if err != nil {
+		return err
+	}
+	r.template = &template.Spec
+	return nil
+}
+
+// inheritTemplate fills the settings the PagerDutyIntegration leaves unset
+// from r.template. The inherited settings are only used for the current
+// reconcile and never written back, so changes to the template reach every
+// PagerDutyIntegration referring to it.
+func (r *ReconcilePagerDutyIntegration) inheritTemplate(pdi *pagerdutyv1alpha1.PagerDutyIntegration) {
+	template := r.template
+	if template == nil {
+		return
+	}
+
+	if pdi.Spec.AcknowledgeTimeout == 0 {
+		pdi.Spec.AcknowledgeTimeout = template.AcknowledgeTimeout
+	}
+	if pdi.Spec.ResolveTimeout == 0 {
+		pdi.Spec.ResolveTimeout = template.ResolveTimeout
+	}
+	if pdi.Spec.IncidentUrgency == nil {
+		pdi.Spec.IncidentUrgency = template.IncidentUrgency.DeepCopy()
+	}
+	if pdi.Spec.MaxSilenceDuration == nil {
+		pdi.Spec.MaxSilenceDuration = template.MaxSilenceDuration.DeepCopy()
+	}
+	if pdi.Spec.DeprovisioningEventRule == nil {
+		pdi.Spec.DeprovisioningEventRule = template.DeprovisioningEventRule.DeepCopy()
+	}
+	if pdi.Spec.ServiceTags == nil {
+		pdi.Spec.ServiceTags = template.ServiceTags.DeepCopy()
+	}
+	if pdi.Spec.FleetHygieneService == nil {
+		pdi.Spec.FleetHygieneService = template.FleetHygieneService.DeepCopy()
+	}
+}
+
+// updateStatus persists the status of the PagerDutyIntegration. The API
+// server returns the spec as stored, so the settings inherited from the
+// template are filled in again.
+func (r *ReconcilePagerDutyIntegration) updateStatus(pdi *pagerdutyv1alpha1.PagerDutyIntegration) error {
+	err := r.client.Status().Update(context.TODO(), pdi)
+	if err != nil {
+		return err
+	}
+	r.inheritTemplate(pdi)
+	return nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"fmt"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/validation"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// tenant returns true if the PDI is outside of the operator namespace. A
+// tenant only uses the secrets of its own namespace and only sets up the
+// ClusterDeployments of the namespaces it owns, see tenantNamespaces.
+func tenant(pdi *pagerdutyv1alpha1.PagerDutyIntegration) bool {
+	return pdi.Namespace != config.GetOperatorNamespace()
+}
+
+// validateTenancy checks that a tenant PDI, with the settings inherited from
+// its template, stays within its namespace, and records the outcome in the
+// TenancyValid condition. Since the finalizers and labels of the clusters
+// are named after the PDI alone, a tenant is also refused the name of a PDI
+// of the operator namespace or of an older tenant. The status is persisted
+// right away when the PDI is refused, as the reconcile stops short of its
+// status update.
+func (r *ReconcilePagerDutyIntegration) validateTenancy(pdi *pagerdutyv1alpha1.PagerDutyIntegration) (bool, error) {
+	if !tenant(pdi) {
+		return true, nil
+	}
+
+	inherited := pdi.DeepCopy()
+	r.inheritTemplate(inherited)
+	reason, message := "", ""
+	if err := validation.TenancyViolation(inherited, config.GetOperatorNamespace()); err != nil {
+		reason, message = "SecretOutsideNamespace", err.Error()
+	}
+
+	if reason == "" {
+		pdiList := &pagerdutyv1alpha1.PagerDutyIntegrationList{}
+		err := r.client.List(context.TODO(), pdiList, &client.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+		for i := range pdiList.Items {
+			other := &pdiList.Items[i]
+			if other.Name == pdi.Name && other.Namespace != pdi.Namespace && takesPrecedence(other, pdi) {
+				reason = "NameTaken"
+				message = fmt.Sprintf("PagerDutyIntegration %s/%s has the same name, rename this one", other.Namespace, other.Name)
+				break
+			}
+		}
+	}
+
+	if reason == "" {
+		r.setTenancyValid(pdi, true, "WithinNamespace", "")
+		return true, nil
+	}
+
+	r.reqLogger.Info("PagerDutyIntegration reaches outside of its namespace, nothing is set up", "Reason", reason, "Message", message)
+	previousConditions := pdi.Status.DeepCopy().Conditions
+	r.setTenancyValid(pdi, false, reason, message)
+	if !equality.Semantic.DeepEqual(pdi.Status.Conditions, previousConditions) {
+		err := r.updateStatus(pdi)
+		if err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// takesPrecedence returns true if the PDI other keeps its name over the
+// tenant PDI of the same name in another namespace: when it is in the
+// operator namespace or, among tenants, when it is older
+func takesPrecedence(other, pdi *pagerdutyv1alpha1.PagerDutyIntegration) bool {
+	if !tenant(other) {
+		return true
+	}
+	if !other.CreationTimestamp.Equal(&pdi.CreationTimestamp) {
+		return other.CreationTimestamp.Before(&pdi.CreationTimestamp)
+	}
+	return other.Namespace < pdi.Namespace
+}
+
+// setTenancyValid sets the TenancyValid condition of the PDI, and records a
+// Warning event when the PDI is refused. Staying within its namespace only
+// updates a condition already there, so PDIs never refused don't carry it.
+func (r *ReconcilePagerDutyIntegration) setTenancyValid(pdi *pagerdutyv1alpha1.PagerDutyIntegration, valid bool, reason, message string) {
+	previous := findPagerDutyIntegrationCondition(pdi.Status.Conditions, pagerdutyv1alpha1.PagerDutyIntegrationConditionTenancyValid)
+	if valid && previous == nil {
+		return
+	}
+
+	condition := pagerdutyv1alpha1.PagerDutyIntegrationCondition{
+		Type:    pagerdutyv1alpha1.PagerDutyIntegrationConditionTenancyValid,
+		Status:  corev1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	}
+	if !valid {
+		condition.Status = corev1.ConditionFalse
+		if r.recorder != nil && (previous == nil || previous.Status != corev1.ConditionFalse) {
+			r.recorder.Eventf(pdi, corev1.EventTypeWarning, "TenancyViolation", "Nothing is set up: %s", message)
+		}
+	}
+	setPagerDutyIntegrationCondition(&pdi.Status.Conditions, condition)
+}
+
+// tenantNamespaces returns the namespaces whose ClusterDeployments the PDI
+// may set up, nil if it may set up those of any namespace. A tenant owns its
+// own namespace and those whose TenantNamespaceLabel names it.
+func (r *ReconcilePagerDutyIntegration) tenantNamespaces(pdi *pagerdutyv1alpha1.PagerDutyIntegration) (map[string]bool, error) {
+	if !tenant(pdi) {
+		return nil, nil
+	}
+
+	nsList := &corev1.NamespaceList{}
+	err := r.client.List(context.TODO(), nsList, client.MatchingLabels{config.TenantNamespaceLabel: pdi.Namespace})
+	if err != nil {
+		return nil, err
+	}
+	namespaces := map[string]bool{pdi.Namespace: true}
+	for _, ns := range nsList.Items {
+		namespaces[ns.Name] = true
+	}
+	return namespaces, nil
+}
+
+// ownsNamespace returns true if namespace is among those returned by
+// tenantNamespaces
+func ownsNamespace(namespaces map[string]bool, namespace string) bool {
+	return namespaces == nil || namespaces[namespace]
+}
+
+// tenantClusterDeployments returns the ClusterDeployments of cds the PDI may
+// set up. Those a tenant stops owning are then torn down like those it
+// stops selecting.
+func (r *ReconcilePagerDutyIntegration) tenantClusterDeployments(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cds []hivev1.ClusterDeployment) ([]hivev1.ClusterDeployment, error) {
+	namespaces, err := r.tenantNamespaces(pdi)
+	if err != nil || namespaces == nil {
+		return cds, err
+	}
+
+	owned := []hivev1.ClusterDeployment{}
+	for _, cd := range cds {
+		if ownsNamespace(namespaces, cd.Namespace) {
+			owned = append(owned, cd)
+		}
+	}
+	return owned, nil
+}
+
+// tenantNamespaceHandler queues, when the TenantNamespaceLabel of a
+// namespace is set, changed or removed, the PagerDutyIntegrations of the
+// tenants it names, so they set up or tear down its ClusterDeployments
+type tenantNamespaceHandler struct {
+	Client client.Client
+}
+
+func (h tenantNamespaceHandler) Create(e event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.queue(q, e.Meta.GetLabels()[config.TenantNamespaceLabel])
+}
+
+func (h tenantNamespaceHandler) Update(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	if e.MetaOld == nil || e.MetaNew == nil {
+		return
+	}
+	oldTenant, newTenant := e.MetaOld.GetLabels()[config.TenantNamespaceLabel], e.MetaNew.GetLabels()[config.TenantNamespaceLabel]
+	if oldTenant == newTenant {
+		return
+	}
+	h.queue(q, oldTenant)
+	h.queue(q, newTenant)
+}
+
+func (h tenantNamespaceHandler) Delete(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.queue(q, e.Meta.GetLabels()[config.TenantNamespaceLabel])
+}
+
+func (h tenantNamespaceHandler) Generic(event.GenericEvent, workqueue.RateLimitingInterface) {
+}
+
+// queue adds a request for each PagerDutyIntegration of the tenant
+// namespace, if any
+func (h tenantNamespaceHandler) queue(q workqueue.RateLimitingInterface, namespace string) {
+	if namespace == "" || namespace == config.GetOperatorNamespace() {
+		return
+	}
+	pdiList := &pagerdutyv1alpha1.PagerDutyIntegrationList{}
+	err := h.Client.List(context.TODO(), pdiList, client.InNamespace(namespace))
+	if err != nil {
+		return
+	}
+	for _, pdi := range pdiList.Items {
+		q.Add(reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      pdi.Name,
+				Namespace: pdi.Namespace,
+			}},
+		)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const testTenantNamespace = "team-a"
+
+// testTenantPagerDutyIntegration returns testPagerDutyIntegration in the
+// namespace of a tenant, referring to the API key secret of that namespace
+func testTenantPagerDutyIntegration() *pagerdutyv1alpha1.PagerDutyIntegration {
+	pdi := testPagerDutyIntegration()
+	pdi.Namespace = testTenantNamespace
+	pdi.CreationTimestamp = metav1.NewTime(time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC))
+	pdi.Spec.PagerdutyApiKeySecretRef.Namespace = testTenantNamespace
+	return pdi
+}
+
+func TestValidateTenancy(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	olderTenant := testTenantPagerDutyIntegration()
+	olderTenant.Namespace = "team-b"
+	olderTenant.CreationTimestamp = metav1.NewTime(olderTenant.CreationTimestamp.Add(-time.Hour))
+	newerTenant := testTenantPagerDutyIntegration()
+	newerTenant.Namespace = "team-b"
+	newerTenant.CreationTimestamp = metav1.NewTime(newerTenant.CreationTimestamp.Add(time.Hour))
+
+	tests := []struct {
+		name         string
+		pdi          func() *pagerdutyv1alpha1.PagerDutyIntegration
+		objects      []runtime.Object
+		expectValid  bool
+		expectReason string
+	}{
+		{
+			name:        "Test Operator Namespace",
+			pdi:         testPagerDutyIntegration,
+			expectValid: true,
+		},
+		{
+			name:        "Test Tenant Within Its Namespace",
+			pdi:         testTenantPagerDutyIntegration,
+			objects:     []runtime.Object{newerTenant},
+			expectValid: true,
+		},
+		{
+			name: "Test Tenant Using Operator API Key",
+			pdi: func() *pagerdutyv1alpha1.PagerDutyIntegration {
+				pdi := testTenantPagerDutyIntegration()
+				pdi.Spec.PagerdutyApiKeySecretRef.Namespace = config.OperatorNamespace
+				return pdi
+			},
+			expectReason: "SecretOutsideNamespace",
+		},
+		{
+			name:         "Test Tenant Named Like Operator Namespace PDI",
+			pdi:          testTenantPagerDutyIntegration,
+			objects:      []runtime.Object{testPagerDutyIntegration()},
+			expectReason: "NameTaken",
+		},
+		{
+			name:         "Test Tenant Named Like Older Tenant",
+			pdi:          testTenantPagerDutyIntegration,
+			objects:      []runtime.Object{olderTenant},
+			expectReason: "NameTaken",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pdi := test.pdi()
+			r := &ReconcilePagerDutyIntegration{
+				client:    fakekubeclient.NewFakeClientWithScheme(scheme.Scheme, append(test.objects, pdi)...),
+				reqLogger: log,
+			}
+
+			valid, err := r.validateTenancy(pdi)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectValid, valid)
+			condition := findPagerDutyIntegrationCondition(pdi.Status.Conditions, pagerdutyv1alpha1.PagerDutyIntegrationConditionTenancyValid)
+			if test.expectValid {
+				assert.Nil(t, condition, "condition set on a PDI never refused")
+				return
+			}
+			assert.NotNil(t, condition)
+			assert.Equal(t, corev1.ConditionFalse, condition.Status)
+			assert.Equal(t, test.expectReason, condition.Reason)
+		})
+	}
+}
+
+func TestTenantClusterDeployments(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	namespace := func(name, tenant string) *corev1.Namespace {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if tenant != "" {
+			ns.Labels = map[string]string{config.TenantNamespaceLabel: tenant}
+		}
+		return ns
+	}
+	clusterDeployment := func(namespace string) hivev1.ClusterDeployment {
+		cd := testClusterDeployment(true, true, false, false)
+		cd.Namespace = namespace
+		return *cd
+	}
+	cds := []hivev1.ClusterDeployment{
+		clusterDeployment(testTenantNamespace),
+		clusterDeployment("labeled"),
+		clusterDeployment("other-tenant"),
+		clusterDeployment("unlabeled"),
+	}
+
+	r := &ReconcilePagerDutyIntegration{
+		client: fakekubeclient.NewFakeClientWithScheme(scheme.Scheme,
+			namespace(testTenantNamespace, ""),
+			namespace("labeled", testTenantNamespace),
+			namespace("other-tenant", "team-b"),
+			namespace("unlabeled", ""),
+		),
+		reqLogger: log,
+	}
+
+	// the operator namespace sets up those of every namespace
+	owned, err := r.tenantClusterDeployments(testPagerDutyIntegration(), cds)
+	assert.NoError(t, err)
+	assert.Equal(t, cds, owned)
+
+	// a tenant only those of its namespace and those labeled for it
+	owned, err = r.tenantClusterDeployments(testTenantPagerDutyIntegration(), cds)
+	assert.NoError(t, err)
+	namespaces := []string{}
+	for _, cd := range owned {
+		namespaces = append(namespaces, cd.Namespace)
+	}
+	assert.Equal(t, []string{testTenantNamespace, "labeled"}, namespaces)
+}
+
+func TestReconcileRefusedTenantDeleted(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	// Arrange
+	pdi := testTenantPagerDutyIntegration()
+	pdi.Spec.PagerdutyApiKeySecretRef.Namespace = config.OperatorNamespace
+	pdi.Finalizers = []string{config.PagerDutyIntegrationFinalizer}
+	now := metav1.Now()
+	pdi.DeletionTimestamp = &now
+
+	r := &ReconcilePagerDutyIntegration{
+		client: fakekubeclient.NewFakeClientWithScheme(scheme.Scheme, pdi, testPDISecret()),
+		scheme: scheme.Scheme,
+		pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client {
+			t.Error("PagerDuty client created for a refused PagerDutyIntegration")
+			return nil
+		},
+	}
+
+	// Act
+	result, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: pdi.Namespace, Name: pdi.Name}})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, result)
+	pdi = &pagerdutyv1alpha1.PagerDutyIntegration{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testTenantNamespace, Name: testPagerDutyIntegrationName}, pdi)
+	if err == nil {
+		assert.NotContains(t, pdi.Finalizers, config.PagerDutyIntegrationFinalizer)
+	} else {
+		assert.True(t, errors.IsNotFound(err))
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"context"
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// testAlertStatus sends a synthetic test alert through the cluster's
+// integration once testAlertInterval has passed since the previous one, and
+// returns its result along with how long until the next one is due. The
+// previous result is returned while the next test alert is not due, or while
+// the cluster has no integration key yet.
+func (r *ReconcilePagerDutyIntegration) testAlertStatus(pdclient pd.Client, pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, previous *pagerdutyv1alpha1.TestAlertStatus, now time.Time) (*pagerdutyv1alpha1.TestAlertStatus, time.Duration, error) {
+	if pdi.Spec.TestAlertInterval == nil {
+		return nil, 0, nil
+	}
+
+	interval := pdi.Spec.TestAlertInterval.Duration
+	if interval < config.TestAlertMinInterval {
+		interval = config.TestAlertMinInterval
+	}
+	if previous != nil {
+		if due := previous.LastTestTime.Add(interval); due.After(now) {
+			return previous, due.Sub(now), nil
+		}
+	}
+
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: naming.SecretName(pdi.Spec.ServicePrefix, cd.Name)}, secret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// handleCreate will create it
+			return previous, 0, nil
+		}
+		return nil, 0, err
+	}
+
+	result, err := pdclient.SendTestAlert(string(secret.Data[config.PagerDutySecretKey]), cd.Spec.ClusterName)
+	status := &pagerdutyv1alpha1.TestAlertStatus{
+		LastTestTime: metav1.Time{Time: now},
+		DedupKey:     result.DedupKey,
+		Accepted:     result.Accepted,
+		Latency:      &metav1.Duration{Duration: result.Latency.Round(time.Millisecond)},
+	}
+	if err != nil {
+		r.reqLogger.Error(err, "Test alert failed", "ClusterDeployment.Namespace", cd.Namespace, "ClusterDeployment.Name", cd.Name, "DedupKey", result.DedupKey)
+		status.Message = logging.Redact(err.Error())
+	}
+	return status, interval, nil
+}
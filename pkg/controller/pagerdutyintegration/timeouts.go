@@ -0,0 +1,48 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyintegration
+
+import (
+	"strconv"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+)
+
+// setTimeouts passes the timeouts of the PagerDutyIntegration on to the
+// PagerDuty services created for pdData, unless the cluster's
+// ClusterDeployment overrides them with an annotation. An annotation that
+// isn't a number of seconds is logged and ignored.
+func (r *ReconcilePagerDutyIntegration) setTimeouts(pdi *pagerdutyv1alpha1.PagerDutyIntegration, cd *hivev1.ClusterDeployment, pdData *pd.Data) {
+	pdData.AutoResolveTimeout = r.timeoutOverride(cd, config.ClusterDeploymentResolveTimeoutAnnotation, pdi.Spec.ResolveTimeout)
+	pdData.AcknowledgeTimeOut = r.timeoutOverride(cd, config.ClusterDeploymentAcknowledgeTimeoutAnnotation, pdi.Spec.AcknowledgeTimeout)
+}
+
+// timeoutOverride returns the timeout set by annotation on cd, or timeout
+// if it sets none
+func (r *ReconcilePagerDutyIntegration) timeoutOverride(cd *hivev1.ClusterDeployment, annotation string, timeout uint) uint {
+	value, ok := cd.Annotations[annotation]
+	if !ok {
+		return timeout
+	}
+	seconds, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		r.reqLogger.Error(err, "Ignoring invalid timeout annotation", "Annotation", annotation, "Value", value)
+		return timeout
+	}
+	return uint(seconds)
+}
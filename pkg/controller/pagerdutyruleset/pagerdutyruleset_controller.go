@@ -0,0 +1,543 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyruleset
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/dryrun"
+	"github.com/openshift/pagerduty-operator/pkg/localmetrics"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/pause"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	"github.com/openshift/pagerduty-operator/pkg/validation"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "pagerdutyruleset"
+
+	// pendingRetryInterval is how often a ruleset whose PagerDutyIntegration
+	// is missing is retried
+	pendingRetryInterval = 5 * time.Minute
+)
+
+var log = logf.Log.WithName("controller_pagerdutyruleset")
+
+// Add creates a new PagerDutyRuleset Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, pause.Wrap(newReconciler(mgr), mgr.GetAPIReader(), config.GetOperatorNamespace()))
+}
+
+// newPDClient makes a PagerDuty client logging its API calls with the
+// controller's logger
+func newPDClient(APIKey string, controllerName string, opts ...pd.ClientOption) pd.Client {
+	return pd.NewClient(APIKey, controllerName, append([]pd.ClientOption{pd.WithLogger(log)}, opts...)...)
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcilePagerDutyRuleset{
+		client:   utils.NewClientWithMetricsOrDie(log, mgr, controllerName),
+		scheme:   mgr.GetScheme(),
+		pdclient: newPDClient,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("pagerdutyruleset-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource PagerDutyRuleset
+	err = c.Watch(&source.Kind{Type: &pagerdutyv1alpha1.PagerDutyRuleset{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to PagerDutyIntegrations, whose status lists the
+	// services of the clusters, and queue a request for all PagerDutyRuleset
+	// CR that refer to it.
+	return c.Watch(&source.Kind{Type: &pagerdutyv1alpha1.PagerDutyIntegration{}},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: pagerDutyIntegrationToRulesetsMapper{
+				Client: mgr.GetClient(),
+			},
+		},
+	)
+}
+
+type pagerDutyIntegrationToRulesetsMapper struct {
+	Client client.Client
+}
+
+func (m pagerDutyIntegrationToRulesetsMapper) Map(mo handler.MapObject) []reconcile.Request {
+	rulesetList := &pagerdutyv1alpha1.PagerDutyRulesetList{}
+	err := m.Client.List(context.TODO(), rulesetList, client.InNamespace(mo.Meta.GetNamespace()))
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := []reconcile.Request{}
+	for _, ruleset := range rulesetList.Items {
+		if ruleset.Spec.PagerDutyIntegrationRef.Name == mo.Meta.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      ruleset.Name,
+					Namespace: ruleset.Namespace,
+				}},
+			)
+		}
+	}
+	return requests
+}
+
+// blank assignment to verify that ReconcilePagerDutyRuleset implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcilePagerDutyRuleset{}
+
+// ReconcilePagerDutyRuleset reconciles a PagerDutyRuleset object
+type ReconcilePagerDutyRuleset struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client    client.Client
+	scheme    *runtime.Scheme
+	reqLogger logr.Logger
+	pdclient  func(APIKey string, controllerName string, opts ...pd.ClientOption) pd.Client
+}
+
+// desiredRule is a rule of a PagerDutyRuleset for one cluster
+type desiredRule struct {
+	managed pagerdutyv1alpha1.ManagedEventRule
+	rule    pd.EventRule
+}
+
+// Reconcile adds, for each cluster of the referenced PagerDutyIntegration
+// that has a PagerDuty service, a rule to the PagerDuty global ruleset for
+// each rule of the PagerDutyRuleset, updates those that changed and deletes
+// those no longer wanted.
+func (r *ReconcilePagerDutyRuleset) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+
+	r.reqLogger = logging.WithReconcileID(log).WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	r.reqLogger.Info("Reconciling PagerDutyRuleset")
+
+	defer func() {
+		dur := time.Since(start)
+		localmetrics.SetReconcileDuration(controllerName, dur.Seconds())
+		r.reqLogger.WithValues("Duration", dur).Info("Reconcile complete")
+	}()
+
+	ruleset := &pagerdutyv1alpha1.PagerDutyRuleset{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, ruleset)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return r.doNotRequeue()
+		}
+		return r.requeueOnErr(err)
+	}
+
+	// log the changes instead of making them
+	if dryrun.Enabled(ruleset) {
+		defer r.startDryRun()()
+	}
+
+	pdi := &pagerdutyv1alpha1.PagerDutyIntegration{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: ruleset.Namespace, Name: ruleset.Spec.PagerDutyIntegrationRef.Name}, pdi)
+	if err != nil && !errors.IsNotFound(err) {
+		return r.requeueOnErr(err)
+	}
+	pdiFound := err == nil
+
+	// the API key of a PDI refused for its tenancy is not used here either,
+	// the validating webhook may have let it through
+	var tenancyErr error
+	if pdiFound {
+		tenancyErr = validation.TenancyViolation(pdi, config.GetOperatorNamespace())
+	}
+
+	if ruleset.DeletionTimestamp != nil {
+		if utils.HasFinalizer(ruleset, config.PagerDutyRulesetFinalizer) {
+			switch {
+			case !pdiFound:
+				// without the API key the rules cannot be deleted, they
+				// only matched the events of the deleted services anyway
+				r.reqLogger.Info("PagerDutyIntegration not found, leaving PD ruleset rules behind", "PagerDutyIntegration", ruleset.Spec.PagerDutyIntegrationRef.Name)
+			case tenancyErr != nil:
+				r.reqLogger.Info("PagerDutyIntegration refused for its tenancy, leaving PD ruleset rules behind", "PagerDutyIntegration", pdi.Name, "Reason", tenancyErr.Error())
+			default:
+				err = r.syncRules(pdi, ruleset, nil)
+				if err != nil {
+					return r.requeueOnErr(err)
+				}
+			}
+
+			utils.DeleteFinalizer(ruleset, config.PagerDutyRulesetFinalizer)
+			err = r.client.Update(context.TODO(), ruleset)
+			if err != nil {
+				return r.requeueOnErr(err)
+			}
+		}
+		return r.doNotRequeue()
+	}
+
+	if !utils.HasFinalizer(ruleset, config.PagerDutyRulesetFinalizer) {
+		utils.AddFinalizer(ruleset, config.PagerDutyRulesetFinalizer)
+		err = r.client.Update(context.TODO(), ruleset)
+		if err != nil {
+			return r.requeueOnErr(err)
+		}
+	}
+
+	if !pdiFound {
+		ruleset.Status.Message = fmt.Sprintf("PagerDutyIntegration %s not found", ruleset.Spec.PagerDutyIntegrationRef.Name)
+		err = r.client.Status().Update(context.TODO(), ruleset)
+		if err != nil {
+			return r.requeueOnErr(err)
+		}
+		return r.requeueAfter(pendingRetryInterval)
+	}
+
+	if tenancyErr != nil {
+		ruleset.Status.Message = fmt.Sprintf("PagerDutyIntegration %s is refused: %s", pdi.Name, tenancyErr)
+		err = r.client.Status().Update(context.TODO(), ruleset)
+		if err != nil {
+			return r.requeueOnErr(err)
+		}
+		return r.requeueAfter(pendingRetryInterval)
+	}
+
+	if name := duplicateRuleName(ruleset); name != "" {
+		// rules are told apart by name, they cannot be managed
+		ruleset.Status.Message = fmt.Sprintf("Rule name %s is used more than once", name)
+		err = r.client.Status().Update(context.TODO(), ruleset)
+		if err != nil {
+			return r.requeueOnErr(err)
+		}
+		return r.doNotRequeue()
+	}
+
+	if ruleset.Annotations[config.PagerDutyRulesetRollbackAnnotation] == "true" {
+		rolledBack, err := r.rollback(ruleset)
+		if err != nil {
+			return r.requeueOnErr(err)
+		}
+		if !rolledBack {
+			ruleset.Status.Message = "No previous rules to roll back to"
+			err = r.client.Status().Update(context.TODO(), ruleset)
+			if err != nil {
+				return r.requeueOnErr(err)
+			}
+			return r.doNotRequeue()
+		}
+	}
+
+	desired, err := r.desiredRules(pdi, ruleset)
+	if err != nil {
+		return r.requeueOnErr(err)
+	}
+
+	syncErr := r.syncRules(pdi, ruleset, desired)
+	if syncErr != nil {
+		r.reqLogger.Error(syncErr, "Failed to sync PD ruleset rules")
+		ruleset.Status.Message = logging.Redact(syncErr.Error())
+	} else {
+		ruleset.Status.Message = fmt.Sprintf("%d PD ruleset rule(s) managed", len(ruleset.Status.Rules))
+		err = recordAppliedRules(ruleset)
+		if err != nil {
+			return r.requeueOnErr(err)
+		}
+	}
+
+	// rules created before a failure are recorded so they are not created twice
+	err = r.client.Status().Update(context.TODO(), ruleset)
+	if err != nil {
+		return r.requeueOnErr(err)
+	}
+	if syncErr != nil {
+		return r.requeueOnErr(syncErr)
+	}
+	return r.doNotRequeue()
+}
+
+// desiredRules returns the rules of the ruleset for each cluster of the
+// PagerDutyIntegration that has a PagerDuty service and is not being deleted
+func (r *ReconcilePagerDutyRuleset) desiredRules(pdi *pagerdutyv1alpha1.PagerDutyIntegration, ruleset *pagerdutyv1alpha1.PagerDutyRuleset) ([]desiredRule, error) {
+	detail := ruleset.Spec.ClusterIDDetail
+	if detail == "" {
+		detail = config.EventRuleDefaultClusterIDDetail
+	}
+
+	desired := []desiredRule{}
+	for _, cluster := range pdi.Status.Clusters {
+		if cluster.ServiceID == "" {
+			continue
+		}
+
+		cd := &hivev1.ClusterDeployment{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: cluster.ClusterDeploymentNamespace, Name: cluster.ClusterDeploymentName}, cd)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if cd.DeletionTimestamp != nil {
+			continue
+		}
+
+		for _, spec := range ruleset.Spec.Rules {
+			rule := pd.EventRule{
+				ClusterIDDetail: detail,
+				ClusterID:       cd.Spec.ClusterName,
+				ServiceID:       cluster.ServiceID,
+				Route:           spec.Route,
+				Suppress:        spec.Suppress,
+				Severity:        spec.Severity,
+			}
+			for _, condition := range spec.Conditions {
+				rule.Conditions = append(rule.Conditions, pd.EventRuleCondition{
+					Detail:   condition.Label,
+					Operator: condition.Operator,
+					Value:    condition.Value,
+				})
+			}
+
+			checksum, err := ruleChecksum(rule)
+			if err != nil {
+				return nil, err
+			}
+			desired = append(desired, desiredRule{
+				managed: pagerdutyv1alpha1.ManagedEventRule{
+					Name:                       spec.Name,
+					ClusterDeploymentName:      cd.Name,
+					ClusterDeploymentNamespace: cd.Namespace,
+					RulesetID:                  ruleset.Spec.RulesetID,
+					Checksum:                   checksum,
+				},
+				rule: rule,
+			})
+		}
+	}
+	return desired, nil
+}
+
+// syncRules creates the desired rules that are missing, updates those that
+// changed and deletes the managed rules no longer desired, recording the
+// result in the ruleset status. It goes on past failures, so one bad rule
+// does not hold up the others, and returns the first.
+func (r *ReconcilePagerDutyRuleset) syncRules(pdi *pagerdutyv1alpha1.PagerDutyIntegration, ruleset *pagerdutyv1alpha1.PagerDutyRuleset, desired []desiredRule) error {
+	pdApiKey, err := utils.LoadSecretData(
+		r.client,
+		pdi.Spec.PagerdutyApiKeySecretRef.Name,
+		pdi.Spec.PagerdutyApiKeySecretRef.Namespace,
+		config.PagerDutyAPISecretKey,
+	)
+	if err != nil {
+		return err
+	}
+	pdclient := r.pdclient(pdApiKey, controllerName, pd.WithAPIEndpoint(pdi.Spec.APIEndpoint))
+
+	var firstErr error
+	managed := []pagerdutyv1alpha1.ManagedEventRule{}
+	wanted := map[string]bool{}
+	for _, d := range desired {
+		wanted[ruleKey(d.managed)] = true
+		current := findManagedRule(ruleset.Status.Rules, d.managed)
+		switch {
+		case current == nil:
+			r.reqLogger.Info("Creating PD ruleset rule", "Rule", d.managed.Name, "ClusterDeployment.Name", d.managed.ClusterDeploymentName, "RulesetID", d.managed.RulesetID)
+			d.managed.RuleID, err = pdclient.CreateEventRule(d.managed.RulesetID, d.rule)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+		case current.Checksum != d.managed.Checksum:
+			r.reqLogger.Info("Updating PD ruleset rule", "Rule", d.managed.Name, "ClusterDeployment.Name", d.managed.ClusterDeploymentName, "RuleID", current.RuleID)
+			err = pdclient.UpdateEventRule(current.RulesetID, current.RuleID, d.rule)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				managed = append(managed, *current)
+				continue
+			}
+			d.managed.RuleID = current.RuleID
+		default:
+			d.managed.RuleID = current.RuleID
+		}
+		managed = append(managed, d.managed)
+	}
+
+	for _, current := range ruleset.Status.Rules {
+		if wanted[ruleKey(current)] {
+			continue
+		}
+		r.reqLogger.Info("Deleting PD ruleset rule", "Rule", current.Name, "ClusterDeployment.Name", current.ClusterDeploymentName, "RuleID", current.RuleID)
+		err = pdclient.DeleteEventRule(current.RulesetID, current.RuleID)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			managed = append(managed, current)
+		}
+	}
+
+	ruleset.Status.Rules = managed
+	return firstErr
+}
+
+// rollback restores in the spec the rules applied before the last change,
+// and removes the rollback annotation. The restored rules are then applied
+// to all the clusters like any change. It returns false when there are no
+// previous rules.
+func (r *ReconcilePagerDutyRuleset) rollback(ruleset *pagerdutyv1alpha1.PagerDutyRuleset) (bool, error) {
+	previous := ruleset.Status.PreviousRules
+	status := ruleset.Status.DeepCopy()
+
+	delete(ruleset.Annotations, config.PagerDutyRulesetRollbackAnnotation)
+	if previous != nil {
+		r.reqLogger.Info("Rolling back PD ruleset rules", "Checksum", previous.Checksum)
+		ruleset.Spec.Rules = previous.Rules
+	}
+	err := r.client.Update(context.TODO(), ruleset)
+	if err != nil {
+		return false, err
+	}
+	// the status is only written through the status subresource
+	ruleset.Status = *status
+	return previous != nil, nil
+}
+
+// recordAppliedRules records the rules of the spec, which were just applied
+// to all the clusters, as the applied ones, keeping the rules they replace
+// to roll back to
+func recordAppliedRules(ruleset *pagerdutyv1alpha1.PagerDutyRuleset) error {
+	checksum, err := rulesChecksum(ruleset.Spec.Rules)
+	if err != nil {
+		return err
+	}
+	applied := ruleset.Status.AppliedRules
+	if applied != nil && applied.Checksum == checksum {
+		return nil
+	}
+
+	ruleset.Status.PreviousRules = applied
+	ruleset.Status.AppliedRules = &pagerdutyv1alpha1.AppliedEventRules{
+		Checksum: checksum,
+		Rules:    append([]pagerdutyv1alpha1.EventRule(nil), ruleset.Spec.Rules...),
+	}
+	return nil
+}
+
+// ruleKey identifies the PD rule made from a rule of the PagerDutyRuleset
+// for a cluster
+func ruleKey(rule pagerdutyv1alpha1.ManagedEventRule) string {
+	return rule.RulesetID + "/" + rule.ClusterDeploymentNamespace + "/" + rule.ClusterDeploymentName + "/" + rule.Name
+}
+
+func findManagedRule(rules []pagerdutyv1alpha1.ManagedEventRule, rule pagerdutyv1alpha1.ManagedEventRule) *pagerdutyv1alpha1.ManagedEventRule {
+	for i := range rules {
+		if ruleKey(rules[i]) == ruleKey(rule) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// ruleChecksum tells whether a rule changed since it was written to PD
+func ruleChecksum(rule pd.EventRule) (string, error) {
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// rulesChecksum tells whether the rules of the spec changed since they were
+// last applied
+func rulesChecksum(rules []pagerdutyv1alpha1.EventRule) (string, error) {
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// duplicateRuleName returns a rule name used more than once, or ""
+func duplicateRuleName(ruleset *pagerdutyv1alpha1.PagerDutyRuleset) string {
+	seen := map[string]bool{}
+	for _, rule := range ruleset.Spec.Rules {
+		if seen[rule.Name] {
+			return rule.Name
+		}
+		seen[rule.Name] = true
+	}
+	return ""
+}
+
+// startDryRun makes the current reconcile log the changes it would make
+// instead of making them, and returns the func ending the dry run
+func (r *ReconcilePagerDutyRuleset) startDryRun() func() {
+	c, pdclient := r.client, r.pdclient
+
+	r.reqLogger.Info("Dry run, no change is made")
+	r.client = dryrun.NewClient(c, r.scheme, &r.reqLogger)
+	r.pdclient = func(APIKey string, controllerName string, opts ...pd.ClientOption) pd.Client {
+		return dryrun.NewPDClient(pdclient(APIKey, controllerName, opts...), &r.reqLogger)
+	}
+
+	return func() {
+		r.client, r.pdclient = c, pdclient
+	}
+}
+
+func (r *ReconcilePagerDutyRuleset) doNotRequeue() (reconcile.Result, error) {
+	return reconcile.Result{}, nil
+}
+
+func (r *ReconcilePagerDutyRuleset) requeueOnErr(err error) (reconcile.Result, error) {
+	return reconcile.Result{}, err
+}
+
+func (r *ReconcilePagerDutyRuleset) requeueAfter(t time.Duration) (reconcile.Result, error) {
+	return reconcile.Result{RequeueAfter: t}, nil
+}
@@ -0,0 +1,447 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutyruleset
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	mockpd "github.com/openshift/pagerduty-operator/pkg/pagerduty/mock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	testPagerDutyIntegrationName = "testPagerDutyIntegration"
+	testRulesetName              = "testRuleset"
+	testClusterName              = "testCluster"
+	testClusterID                = "test-cluster-id"
+	testNamespace                = "testNamespace"
+	testServiceID                = "DEF456"
+	testRulesetID                = "RULESET1"
+	testRuleID                   = "RULE1"
+	testAPIKey                   = "test-pd-api-key"
+)
+
+func testPDISecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: config.OperatorNamespace,
+			Name:      config.PagerDutyAPISecretName,
+		},
+		Data: map[string][]byte{
+			config.PagerDutyAPISecretKey: []byte(testAPIKey),
+		},
+	}
+}
+
+func testPagerDutyIntegration(serviceID string) *pagerdutyv1alpha1.PagerDutyIntegration {
+	return &pagerdutyv1alpha1.PagerDutyIntegration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testPagerDutyIntegrationName,
+			Namespace: config.OperatorNamespace,
+		},
+		Spec: pagerdutyv1alpha1.PagerDutyIntegrationSpec{
+			PagerdutyApiKeySecretRef: corev1.SecretReference{
+				Name:      config.PagerDutyAPISecretName,
+				Namespace: config.OperatorNamespace,
+			},
+		},
+		Status: pagerdutyv1alpha1.PagerDutyIntegrationStatus{
+			Clusters: []pagerdutyv1alpha1.ClusterStatus{
+				{
+					ClusterDeploymentNamespace: testNamespace,
+					ClusterDeploymentName:      testClusterName,
+					ServiceID:                  serviceID,
+				},
+			},
+		},
+	}
+}
+
+func testClusterDeployment() *hivev1.ClusterDeployment {
+	return &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testClusterName,
+			Namespace: testNamespace,
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			ClusterName: testClusterID,
+			Installed:   true,
+		},
+	}
+}
+
+func testRules() []pagerdutyv1alpha1.EventRule {
+	return []pagerdutyv1alpha1.EventRule{
+		{
+			Name:     "critical-alerts",
+			Route:    true,
+			Severity: "critical",
+			Conditions: []pagerdutyv1alpha1.EventRuleCondition{
+				{Label: "severity", Operator: "equals", Value: "critical"},
+			},
+		},
+		{
+			Name:     "watchdog",
+			Suppress: true,
+			Conditions: []pagerdutyv1alpha1.EventRuleCondition{
+				{Label: "alertname", Operator: "equals", Value: "Watchdog"},
+			},
+		},
+	}
+}
+
+func testRuleset(isDeleting bool, rules []pagerdutyv1alpha1.EventRule, managed []pagerdutyv1alpha1.ManagedEventRule) *pagerdutyv1alpha1.PagerDutyRuleset {
+	ruleset := &pagerdutyv1alpha1.PagerDutyRuleset{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testRulesetName,
+			Namespace: config.OperatorNamespace,
+		},
+		Spec: pagerdutyv1alpha1.PagerDutyRulesetSpec{
+			PagerDutyIntegrationRef: corev1.LocalObjectReference{Name: testPagerDutyIntegrationName},
+			RulesetID:               testRulesetID,
+			Rules:                   rules,
+		},
+		Status: pagerdutyv1alpha1.PagerDutyRulesetStatus{
+			Rules: managed,
+		},
+	}
+
+	if isDeleting {
+		now := metav1.Now()
+		ruleset.DeletionTimestamp = &now
+		ruleset.Finalizers = []string{config.PagerDutyRulesetFinalizer}
+	}
+
+	return ruleset
+}
+
+func testManagedRule(name string, checksum string) pagerdutyv1alpha1.ManagedEventRule {
+	return pagerdutyv1alpha1.ManagedEventRule{
+		Name:                       name,
+		ClusterDeploymentName:      testClusterName,
+		ClusterDeploymentNamespace: testNamespace,
+		RulesetID:                  testRulesetID,
+		RuleID:                     testRuleID,
+		Checksum:                   checksum,
+	}
+}
+
+func TestReconcilePagerDutyRuleset(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name          string
+		localObjects  []runtime.Object
+		expectRules   int
+		expectMessage string
+		expectDeleted bool
+		setupPDMock   func(*mockpd.MockClientMockRecorder)
+	}{
+		{
+			name: "Test Rules Created",
+			localObjects: []runtime.Object{
+				testRuleset(false, testRules(), nil),
+				testClusterDeployment(),
+				testPDISecret(),
+				testPagerDutyIntegration(testServiceID),
+			},
+			expectRules:   2,
+			expectMessage: "2 PD ruleset rule(s) managed",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateEventRule(testRulesetID, pd.EventRule{
+					ClusterIDDetail: config.EventRuleDefaultClusterIDDetail,
+					ClusterID:       testClusterID,
+					ServiceID:       testServiceID,
+					Conditions:      []pd.EventRuleCondition{{Detail: "severity", Operator: "equals", Value: "critical"}},
+					Route:           true,
+					Severity:        "critical",
+				}).Return("RULE2", nil).Times(1)
+				r.CreateEventRule(testRulesetID, gomock.Any()).Return("RULE3", nil).Times(1)
+			},
+		},
+		{
+			name: "Test Cluster Without Service",
+			localObjects: []runtime.Object{
+				testRuleset(false, testRules(), nil),
+				testClusterDeployment(),
+				testPDISecret(),
+				testPagerDutyIntegration(""),
+			},
+			expectRules:   0,
+			expectMessage: "0 PD ruleset rule(s) managed",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateEventRule(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Test Changed Rule Updated",
+			localObjects: []runtime.Object{
+				testRuleset(false, testRules()[:1], []pagerdutyv1alpha1.ManagedEventRule{testManagedRule("critical-alerts", "outdated")}),
+				testClusterDeployment(),
+				testPDISecret(),
+				testPagerDutyIntegration(testServiceID),
+			},
+			expectRules:   1,
+			expectMessage: "1 PD ruleset rule(s) managed",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateEventRule(gomock.Any(), gomock.Any()).Times(0)
+				r.UpdateEventRule(testRulesetID, testRuleID, gomock.Any()).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "Test Removed Rule Deleted",
+			localObjects: []runtime.Object{
+				testRuleset(false, nil, []pagerdutyv1alpha1.ManagedEventRule{testManagedRule("removed", "checksum")}),
+				testClusterDeployment(),
+				testPDISecret(),
+				testPagerDutyIntegration(testServiceID),
+			},
+			expectRules:   0,
+			expectMessage: "0 PD ruleset rule(s) managed",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.DeleteEventRule(testRulesetID, testRuleID).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "Test Duplicate Rule Names",
+			localObjects: []runtime.Object{
+				testRuleset(false, append(testRules(), testRules()[0]), nil),
+				testClusterDeployment(),
+				testPDISecret(),
+				testPagerDutyIntegration(testServiceID),
+			},
+			expectRules:   0,
+			expectMessage: "Rule name critical-alerts is used more than once",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateEventRule(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Test PagerDutyIntegration Not Found",
+			localObjects: []runtime.Object{
+				testRuleset(false, testRules(), nil),
+				testClusterDeployment(),
+				testPDISecret(),
+			},
+			expectRules:   0,
+			expectMessage: "PagerDutyIntegration " + testPagerDutyIntegrationName + " not found",
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateEventRule(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Test Deleting Ruleset",
+			localObjects: []runtime.Object{
+				testRuleset(true, testRules(), []pagerdutyv1alpha1.ManagedEventRule{testManagedRule("critical-alerts", "checksum")}),
+				testClusterDeployment(),
+				testPDISecret(),
+				testPagerDutyIntegration(testServiceID),
+			},
+			expectDeleted: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateEventRule(gomock.Any(), gomock.Any()).Times(0)
+				r.DeleteEventRule(testRulesetID, testRuleID).Return(nil).Times(1)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockPDClient := mockpd.NewMockClient(mockCtrl)
+			test.setupPDMock(mockPDClient.EXPECT())
+
+			fakeKubeClient := fakekubeclient.NewFakeClient(test.localObjects...)
+			rpdrs := &ReconcilePagerDutyRuleset{
+				client:   fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mockPDClient },
+			}
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: testRulesetName, Namespace: config.OperatorNamespace},
+			}
+
+			// Act, twice to confirm the second run is a noop
+			_, err1 := rpdrs.Reconcile(request)
+			_, err2 := rpdrs.Reconcile(request)
+
+			// Assert
+			assert.NoError(t, err1, "Unexpected Error with Reconcile (1 of 2)")
+			assert.NoError(t, err2, "Unexpected Error with Reconcile (2 of 2)")
+
+			ruleset := &pagerdutyv1alpha1.PagerDutyRuleset{}
+			err := fakeKubeClient.Get(context.TODO(), request.NamespacedName, ruleset)
+			assert.NoError(t, err)
+			if test.expectDeleted {
+				assert.NotContains(t, ruleset.Finalizers, config.PagerDutyRulesetFinalizer)
+				return
+			}
+			assert.Len(t, ruleset.Status.Rules, test.expectRules)
+			assert.Equal(t, test.expectMessage, ruleset.Status.Message)
+			assert.Contains(t, ruleset.Finalizers, config.PagerDutyRulesetFinalizer)
+		})
+	}
+}
+
+// TestReconcilePagerDutyRulesetTenancyViolation never uses the API key of
+// the operator namespace for a tenant PDI referring to it
+func TestReconcilePagerDutyRulesetTenancyViolation(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	const tenantNamespace = "team-a"
+
+	for _, isDeleting := range []bool{false, true} {
+		t.Run(fmt.Sprintf("Deleting %t", isDeleting), func(t *testing.T) {
+			// Arrange
+			ruleset := testRuleset(isDeleting, testRules(), []pagerdutyv1alpha1.ManagedEventRule{testManagedRule("critical-alerts", "checksum")})
+			ruleset.Namespace = tenantNamespace
+			pdi := testPagerDutyIntegration(testServiceID)
+			pdi.Namespace = tenantNamespace
+
+			fakeKubeClient := fakekubeclient.NewFakeClient(ruleset, pdi, testClusterDeployment(), testPDISecret())
+			rpdrs := &ReconcilePagerDutyRuleset{
+				client: fakeKubeClient,
+				scheme: scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client {
+					t.Error("PagerDuty client created with the API key of the operator namespace")
+					return nil
+				},
+			}
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: testRulesetName, Namespace: tenantNamespace},
+			}
+
+			// Act
+			_, err := rpdrs.Reconcile(request)
+
+			// Assert
+			assert.NoError(t, err)
+			ruleset = &pagerdutyv1alpha1.PagerDutyRuleset{}
+			assert.NoError(t, fakeKubeClient.Get(context.TODO(), request.NamespacedName, ruleset))
+			if isDeleting {
+				assert.NotContains(t, ruleset.Finalizers, config.PagerDutyRulesetFinalizer)
+				return
+			}
+			assert.Contains(t, ruleset.Status.Message, "spec.pagerdutyApiKeySecretRef")
+			assert.Len(t, ruleset.Status.Rules, 1)
+		})
+	}
+}
+
+func TestReconcilePagerDutyRulesetRollback(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	good := testRules()[:1]
+	bad := testRules()
+	bad[0].Severity = "info"
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPDClient := mockpd.NewMockClient(mockCtrl)
+	mockPDClient.EXPECT().CreateEventRule(testRulesetID, gomock.Any()).Return(testRuleID, nil).Times(1)
+	mockPDClient.EXPECT().CreateEventRule(testRulesetID, gomock.Any()).Return("RULE2", nil).Times(1)
+	// the bad push, then the rollback
+	mockPDClient.EXPECT().UpdateEventRule(testRulesetID, testRuleID, gomock.Any()).Return(nil).Times(2)
+	mockPDClient.EXPECT().DeleteEventRule(testRulesetID, "RULE2").Return(nil).Times(1)
+
+	fakeKubeClient := fakekubeclient.NewFakeClient(
+		testRuleset(false, good, nil),
+		testClusterDeployment(),
+		testPDISecret(),
+		testPagerDutyIntegration(testServiceID),
+	)
+	rpdrs := &ReconcilePagerDutyRuleset{
+		client:   fakeKubeClient,
+		scheme:   scheme.Scheme,
+		pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mockPDClient },
+	}
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: testRulesetName, Namespace: config.OperatorNamespace},
+	}
+	var ruleset *pagerdutyv1alpha1.PagerDutyRuleset
+	reconcileAndGet := func() {
+		_, err := rpdrs.Reconcile(request)
+		assert.NoError(t, err)
+		ruleset = &pagerdutyv1alpha1.PagerDutyRuleset{}
+		err = fakeKubeClient.Get(context.TODO(), request.NamespacedName, ruleset)
+		assert.NoError(t, err)
+	}
+
+	// nothing to roll back to before a change
+	reconcileAndGet()
+	assert.NotNil(t, ruleset.Status.AppliedRules)
+	assert.Nil(t, ruleset.Status.PreviousRules)
+	ruleset.Annotations = map[string]string{config.PagerDutyRulesetRollbackAnnotation: "true"}
+	assert.NoError(t, fakeKubeClient.Update(context.TODO(), ruleset))
+	reconcileAndGet()
+	assert.Equal(t, "No previous rules to roll back to", ruleset.Status.Message)
+	assert.NotContains(t, ruleset.Annotations, config.PagerDutyRulesetRollbackAnnotation)
+
+	// push the bad rules
+	goodChecksum := ruleset.Status.AppliedRules.Checksum
+	ruleset.Spec.Rules = bad
+	assert.NoError(t, fakeKubeClient.Update(context.TODO(), ruleset))
+	reconcileAndGet()
+	assert.Len(t, ruleset.Status.Rules, 2)
+	assert.Equal(t, goodChecksum, ruleset.Status.PreviousRules.Checksum)
+
+	// roll them back
+	ruleset.Annotations = map[string]string{config.PagerDutyRulesetRollbackAnnotation: "true"}
+	assert.NoError(t, fakeKubeClient.Update(context.TODO(), ruleset))
+	reconcileAndGet()
+	assert.Equal(t, good, ruleset.Spec.Rules)
+	assert.Len(t, ruleset.Status.Rules, 1)
+	assert.Equal(t, goodChecksum, ruleset.Status.AppliedRules.Checksum)
+	assert.Equal(t, bad, ruleset.Status.PreviousRules.Rules)
+	assert.NotContains(t, ruleset.Annotations, config.PagerDutyRulesetRollbackAnnotation)
+}
+
+func TestPagerDutyIntegrationToRulesetsMapper(t *testing.T) {
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	other := testRuleset(false, nil, nil)
+	other.Name = "otherRuleset"
+	other.Spec.PagerDutyIntegrationRef.Name = "otherPagerDutyIntegration"
+	fakeKubeClient := fakekubeclient.NewFakeClient(testRuleset(false, nil, nil), other)
+
+	pdi := testPagerDutyIntegration(testServiceID)
+	requests := pagerDutyIntegrationToRulesetsMapper{Client: fakeKubeClient}.Map(handler.MapObject{Meta: pdi, Object: pdi})
+
+	assert.Equal(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: testRulesetName, Namespace: config.OperatorNamespace}},
+	}, requests)
+}
@@ -0,0 +1,370 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutysilence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/dryrun"
+	"github.com/openshift/pagerduty-operator/pkg/localmetrics"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/pause"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	"github.com/openshift/pagerduty-operator/pkg/validation"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "pagerdutysilence"
+
+	// pendingRetryInterval is how often a silence whose cluster has no
+	// PagerDuty service yet is retried
+	pendingRetryInterval = 5 * time.Minute
+)
+
+var log = logf.Log.WithName("controller_pagerdutysilence")
+
+// Add creates a new PagerDutySilence Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, pause.Wrap(newReconciler(mgr), mgr.GetAPIReader(), config.GetOperatorNamespace()))
+}
+
+// newPDClient makes a PagerDuty client logging its API calls with the
+// controller's logger
+func newPDClient(APIKey string, controllerName string, opts ...pd.ClientOption) pd.Client {
+	return pd.NewClient(APIKey, controllerName, append([]pd.ClientOption{pd.WithLogger(log)}, opts...)...)
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcilePagerDutySilence{
+		client:   utils.NewClientWithMetricsOrDie(log, mgr, controllerName),
+		scheme:   mgr.GetScheme(),
+		pdclient: newPDClient,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("pagerdutysilence-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource PagerDutySilence
+	return c.Watch(&source.Kind{Type: &pagerdutyv1alpha1.PagerDutySilence{}}, &handler.EnqueueRequestForObject{})
+}
+
+// blank assignment to verify that ReconcilePagerDutySilence implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcilePagerDutySilence{}
+
+// ReconcilePagerDutySilence reconciles a PagerDutySilence object
+type ReconcilePagerDutySilence struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client    client.Client
+	scheme    *runtime.Scheme
+	reqLogger logr.Logger
+	pdclient  func(APIKey string, controllerName string, opts ...pd.ClientOption) pd.Client
+}
+
+// Reconcile puts every PagerDuty service of the silenced ClusterDeployment
+// into a maintenance window lasting until the silence expires. Expired
+// silences are kept so that past silences stay auditable.
+func (r *ReconcilePagerDutySilence) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+
+	r.reqLogger = logging.WithReconcileID(log).WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	r.reqLogger.Info("Reconciling PagerDutySilence")
+
+	defer func() {
+		dur := time.Since(start)
+		localmetrics.SetReconcileDuration(controllerName, dur.Seconds())
+		r.reqLogger.WithValues("Duration", dur).Info("Reconcile complete")
+	}()
+
+	silence := &pagerdutyv1alpha1.PagerDutySilence{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, silence)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return r.doNotRequeue()
+		}
+		return r.requeueOnErr(err)
+	}
+
+	// log the changes instead of making them
+	if dryrun.Enabled(silence) {
+		defer r.startDryRun()()
+	}
+
+	if silence.DeletionTimestamp != nil {
+		if utils.HasFinalizer(silence, config.PagerDutySilenceFinalizer) {
+			// a silence removed before it expired gives paging back right away
+			if silence.Status.Phase != pagerdutyv1alpha1.SilencePhaseExpired {
+				r.endMaintenanceWindows(silence)
+			}
+
+			utils.DeleteFinalizer(silence, config.PagerDutySilenceFinalizer)
+			err = r.client.Update(context.TODO(), silence)
+			if err != nil {
+				return r.requeueOnErr(err)
+			}
+		}
+		return r.doNotRequeue()
+	}
+
+	if !utils.HasFinalizer(silence, config.PagerDutySilenceFinalizer) {
+		utils.AddFinalizer(silence, config.PagerDutySilenceFinalizer)
+		err = r.client.Update(context.TODO(), silence)
+		if err != nil {
+			return r.requeueOnErr(err)
+		}
+	}
+
+	expiresAt := silence.CreationTimestamp.Add(silence.Spec.Duration.Duration)
+	silence.Status.ExpiresAt = &metav1.Time{Time: expiresAt}
+
+	if !time.Now().Before(expiresAt) {
+		// PagerDuty ends the maintenance windows on its own at their end time
+		silence.Status.Phase = pagerdutyv1alpha1.SilencePhaseExpired
+		silence.Status.Message = "Silence expired, paging is restored"
+		err = r.client.Status().Update(context.TODO(), silence)
+		if err != nil {
+			return r.requeueOnErr(err)
+		}
+		return r.doNotRequeue()
+	}
+
+	err = r.ensureMaintenanceWindows(silence, expiresAt)
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to put PagerDuty services in maintenance")
+		silence.Status.Message = logging.Redact(err.Error())
+		if statusErr := r.client.Status().Update(context.TODO(), silence); statusErr != nil {
+			r.reqLogger.Error(statusErr, "Failed to update PagerDutySilence status")
+		}
+		return r.requeueOnErr(err)
+	}
+
+	requeueAfter := time.Until(expiresAt)
+	if len(silence.Status.MaintenanceWindows) == 0 {
+		silence.Status.Phase = pagerdutyv1alpha1.SilencePhasePending
+		silence.Status.Message = "No PagerDuty service found for the ClusterDeployment"
+		if requeueAfter > pendingRetryInterval {
+			requeueAfter = pendingRetryInterval
+		}
+	} else {
+		silence.Status.Phase = pagerdutyv1alpha1.SilencePhaseActive
+		silence.Status.Message = fmt.Sprintf("%d PagerDuty service(s) in maintenance", len(silence.Status.MaintenanceWindows))
+	}
+
+	err = r.client.Status().Update(context.TODO(), silence)
+	if err != nil {
+		return r.requeueOnErr(err)
+	}
+
+	return r.requeueAfter(requeueAfter)
+}
+
+// ensureMaintenanceWindows creates a maintenance window for each PagerDuty
+// service of the silenced ClusterDeployment that doesn't have one yet, and
+// records it in the silence status.
+func (r *ReconcilePagerDutySilence) ensureMaintenanceWindows(silence *pagerdutyv1alpha1.PagerDutySilence, expiresAt time.Time) error {
+	cd := &hivev1.ClusterDeployment{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: silence.Namespace, Name: silence.Spec.ClusterDeploymentRef.Name}, cd)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	pdiList := &pagerdutyv1alpha1.PagerDutyIntegrationList{}
+	err = r.client.List(context.TODO(), pdiList, &client.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, pdi := range pdiList.Items {
+		// only PagerDutyIntegrations that manage a service for the
+		// cluster leave their finalizer on it
+		if !utils.HasFinalizer(cd, config.PagerDutyFinalizerPrefix+pdi.Name) {
+			continue
+		}
+		if hasMaintenanceWindow(silence, pdi.Name) {
+			continue
+		}
+		// the API key of a PDI refused for its tenancy is not used here
+		// either, the validating webhook may have let it through
+		if err := validation.TenancyViolation(&pdi, config.GetOperatorNamespace()); err != nil {
+			r.reqLogger.Info("Skipping PagerDutyIntegration refused for its tenancy", "PagerDutyIntegration", pdi.Name, "Reason", err.Error())
+			continue
+		}
+
+		pdData := &pd.Data{}
+		err = pdData.ParseClusterConfig(r.client, cd.Namespace, naming.ConfigMapName(pdi.Spec.ServicePrefix, cd.Name))
+		if err != nil {
+			if errors.IsNotFound(err) {
+				// the service isn't created yet
+				continue
+			}
+			return err
+		}
+
+		pdApiKey, err := utils.LoadSecretData(
+			r.client,
+			pdi.Spec.PagerdutyApiKeySecretRef.Name,
+			pdi.Spec.PagerdutyApiKeySecretRef.Namespace,
+			config.PagerDutyAPISecretKey,
+		)
+		if err != nil {
+			return err
+		}
+
+		description := fmt.Sprintf("Silenced by %s: %s", silence.Spec.Requester, silence.Spec.Reason)
+		r.reqLogger.Info("Creating PD maintenance window", "ServiceID", pdData.ServiceID, "PagerDutyIntegration", pdi.Name)
+		pdclient := r.pdclient(pdApiKey, controllerName, pd.WithAPIEndpoint(pdi.Spec.APIEndpoint))
+		windowID, err := pdclient.CreateMaintenanceWindow(pdData, description, time.Now(), expiresAt)
+		if err != nil {
+			return err
+		}
+
+		err = r.saveMaintenanceWindow(silence, pagerdutyv1alpha1.SilenceMaintenanceWindow{
+			PagerDutyIntegration: pdi.Name,
+			ServiceID:            pdData.ServiceID,
+			MaintenanceWindowID:  windowID,
+		})
+		if err != nil {
+			// the next reconcile opens a new window, don't leave this one
+			// open next to it
+			r.reqLogger.Info("Ending PD maintenance window whose ID could not be saved", "MaintenanceWindowID", windowID)
+			if endErr := pdclient.DeleteMaintenanceWindow(windowID); endErr != nil {
+				r.reqLogger.Error(endErr, "Failed to end PD maintenance window", "MaintenanceWindowID", windowID)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveMaintenanceWindow records window in the silence status and persists it
+// right away, so its ID isn't lost when the status update ending the
+// reconcile fails and the next reconcile doesn't open a second window. The
+// window is left out of the status if it can't be persisted.
+func (r *ReconcilePagerDutySilence) saveMaintenanceWindow(silence *pagerdutyv1alpha1.PagerDutySilence, window pagerdutyv1alpha1.SilenceMaintenanceWindow) error {
+	silence.Status.MaintenanceWindows = append(silence.Status.MaintenanceWindows, window)
+	err := r.client.Status().Update(context.TODO(), silence)
+	if err != nil {
+		silence.Status.MaintenanceWindows = silence.Status.MaintenanceWindows[:len(silence.Status.MaintenanceWindows)-1]
+	}
+	return err
+}
+
+// endMaintenanceWindows ends the maintenance windows of a silence. Failures
+// are logged and skipped, as the windows end on their own at expiry anyway.
+func (r *ReconcilePagerDutySilence) endMaintenanceWindows(silence *pagerdutyv1alpha1.PagerDutySilence) {
+	pdiList := &pagerdutyv1alpha1.PagerDutyIntegrationList{}
+	err := r.client.List(context.TODO(), pdiList, &client.ListOptions{})
+	if err != nil {
+		r.reqLogger.Error(err, "Failed to list PagerDutyIntegrations, maintenance windows will end at expiry")
+		return
+	}
+
+	for _, window := range silence.Status.MaintenanceWindows {
+		for _, pdi := range pdiList.Items {
+			if pdi.Name != window.PagerDutyIntegration {
+				continue
+			}
+			if err := validation.TenancyViolation(&pdi, config.GetOperatorNamespace()); err != nil {
+				r.reqLogger.Info("Skipping PagerDutyIntegration refused for its tenancy, maintenance window will end at expiry", "PagerDutyIntegration", pdi.Name, "Reason", err.Error())
+				continue
+			}
+
+			pdApiKey, err := utils.LoadSecretData(
+				r.client,
+				pdi.Spec.PagerdutyApiKeySecretRef.Name,
+				pdi.Spec.PagerdutyApiKeySecretRef.Namespace,
+				config.PagerDutyAPISecretKey,
+			)
+			if err != nil {
+				r.reqLogger.Error(err, "Failed to load PagerDuty API key", "PagerDutyIntegration", pdi.Name)
+				continue
+			}
+
+			r.reqLogger.Info("Ending PD maintenance window", "ServiceID", window.ServiceID, "MaintenanceWindowID", window.MaintenanceWindowID)
+			err = r.pdclient(pdApiKey, controllerName, pd.WithAPIEndpoint(pdi.Spec.APIEndpoint)).DeleteMaintenanceWindow(window.MaintenanceWindowID)
+			if err != nil {
+				r.reqLogger.Error(err, "Failed to end PD maintenance window", "MaintenanceWindowID", window.MaintenanceWindowID)
+			}
+		}
+	}
+}
+
+func hasMaintenanceWindow(silence *pagerdutyv1alpha1.PagerDutySilence, pdiName string) bool {
+	for _, window := range silence.Status.MaintenanceWindows {
+		if window.PagerDutyIntegration == pdiName {
+			return true
+		}
+	}
+	return false
+}
+
+// startDryRun makes the current reconcile log the changes it would make
+// instead of making them, and returns the func ending the dry run
+func (r *ReconcilePagerDutySilence) startDryRun() func() {
+	c, pdclient := r.client, r.pdclient
+
+	r.reqLogger.Info("Dry run, no change is made")
+	r.client = dryrun.NewClient(c, r.scheme, &r.reqLogger)
+	r.pdclient = func(APIKey string, controllerName string, opts ...pd.ClientOption) pd.Client {
+		return dryrun.NewPDClient(pdclient(APIKey, controllerName, opts...), &r.reqLogger)
+	}
+
+	return func() {
+		r.client, r.pdclient = c, pdclient
+	}
+}
+
+func (r *ReconcilePagerDutySilence) doNotRequeue() (reconcile.Result, error) {
+	return reconcile.Result{}, nil
+}
+
+func (r *ReconcilePagerDutySilence) requeueOnErr(err error) (reconcile.Result, error) {
+	return reconcile.Result{}, err
+}
+
+func (r *ReconcilePagerDutySilence) requeueAfter(t time.Duration) (reconcile.Result, error) {
+	return reconcile.Result{RequeueAfter: t}, nil
+}
@@ -0,0 +1,409 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerdutysilence
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	mockpd "github.com/openshift/pagerduty-operator/pkg/pagerduty/mock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	testPagerDutyIntegrationName = "testPagerDutyIntegration"
+	testSilenceName              = "testSilence"
+	testClusterName              = "testCluster"
+	testNamespace                = "testNamespace"
+	testIntegrationID            = "ABC123"
+	testServiceID                = "DEF456"
+	testMaintenanceWindowID      = "GHI789"
+	testAPIKey                   = "test-pd-api-key"
+	testServicePrefix            = "test-service-prefix"
+)
+
+func testPDISecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: config.OperatorNamespace,
+			Name:      config.PagerDutyAPISecretName,
+		},
+		Data: map[string][]byte{
+			config.PagerDutyAPISecretKey: []byte(testAPIKey),
+		},
+	}
+}
+
+func testPagerDutyIntegration() *pagerdutyv1alpha1.PagerDutyIntegration {
+	return &pagerdutyv1alpha1.PagerDutyIntegration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testPagerDutyIntegrationName,
+			Namespace: config.OperatorNamespace,
+		},
+		Spec: pagerdutyv1alpha1.PagerDutyIntegrationSpec{
+			ServicePrefix: testServicePrefix,
+			PagerdutyApiKeySecretRef: corev1.SecretReference{
+				Name:      config.PagerDutyAPISecretName,
+				Namespace: config.OperatorNamespace,
+			},
+		},
+	}
+}
+
+// testRefusedTenantPagerDutyIntegration returns testPagerDutyIntegration in
+// the namespace of a tenant, still referring to the API key secret of the
+// operator namespace
+func testRefusedTenantPagerDutyIntegration() *pagerdutyv1alpha1.PagerDutyIntegration {
+	pdi := testPagerDutyIntegration()
+	pdi.Namespace = "team-a"
+	return pdi
+}
+
+func testCDConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      naming.ConfigMapName(testServicePrefix, testClusterName),
+		},
+		Data: map[string]string{
+			"INTEGRATION_ID": testIntegrationID,
+			"SERVICE_ID":     testServiceID,
+		},
+	}
+}
+
+func testClusterDeployment() *hivev1.ClusterDeployment {
+	return &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testClusterName,
+			Namespace:  testNamespace,
+			Finalizers: []string{config.PagerDutyFinalizerPrefix + testPagerDutyIntegrationName},
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			ClusterName: testClusterName,
+			Installed:   true,
+		},
+	}
+}
+
+// testSilence returns a PagerDutySilence created at the given time and lasting one hour.
+func testSilence(created time.Time, isDeleting bool, windows []pagerdutyv1alpha1.SilenceMaintenanceWindow) *pagerdutyv1alpha1.PagerDutySilence {
+	silence := &pagerdutyv1alpha1.PagerDutySilence{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              testSilenceName,
+			Namespace:         testNamespace,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: pagerdutyv1alpha1.PagerDutySilenceSpec{
+			ClusterDeploymentRef: corev1.LocalObjectReference{Name: testClusterName},
+			Duration:             metav1.Duration{Duration: time.Hour},
+			Reason:               "planned upgrade",
+			Requester:            "someone@example.com",
+		},
+		Status: pagerdutyv1alpha1.PagerDutySilenceStatus{
+			MaintenanceWindows: windows,
+		},
+	}
+
+	if isDeleting {
+		now := metav1.Now()
+		silence.DeletionTimestamp = &now
+		silence.Finalizers = []string{config.PagerDutySilenceFinalizer}
+	}
+
+	return silence
+}
+
+func testWindows() []pagerdutyv1alpha1.SilenceMaintenanceWindow {
+	return []pagerdutyv1alpha1.SilenceMaintenanceWindow{
+		{
+			PagerDutyIntegration: testPagerDutyIntegrationName,
+			ServiceID:            testServiceID,
+			MaintenanceWindowID:  testMaintenanceWindowID,
+		},
+	}
+}
+
+func TestReconcilePagerDutySilence(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name          string
+		localObjects  []runtime.Object
+		expectPhase   pagerdutyv1alpha1.PagerDutySilencePhase
+		expectWindows int
+		expectDeleted bool
+		setupPDMock   func(*mockpd.MockClientMockRecorder)
+	}{
+		{
+			name: "Test Active Silence",
+			localObjects: []runtime.Object{
+				testSilence(time.Now(), false, nil),
+				testClusterDeployment(),
+				testCDConfigMap(),
+				testPDISecret(),
+				testPagerDutyIntegration(),
+			},
+			expectPhase:   pagerdutyv1alpha1.SilencePhaseActive,
+			expectWindows: 1,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateMaintenanceWindow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(testMaintenanceWindowID, nil).Times(1)
+				r.DeleteMaintenanceWindow(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Test Silence, PD Not Setup",
+			localObjects: []runtime.Object{
+				testSilence(time.Now(), false, nil),
+				testClusterDeployment(),
+				testPDISecret(),
+				testPagerDutyIntegration(),
+			},
+			expectPhase:   pagerdutyv1alpha1.SilencePhasePending,
+			expectWindows: 0,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateMaintenanceWindow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				r.DeleteMaintenanceWindow(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Test Expired Silence",
+			localObjects: []runtime.Object{
+				testSilence(time.Now().Add(-2*time.Hour), false, testWindows()),
+				testClusterDeployment(),
+				testCDConfigMap(),
+				testPDISecret(),
+				testPagerDutyIntegration(),
+			},
+			expectPhase:   pagerdutyv1alpha1.SilencePhaseExpired,
+			expectWindows: 1,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateMaintenanceWindow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				r.DeleteMaintenanceWindow(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Test Deleting Active Silence",
+			localObjects: []runtime.Object{
+				testSilence(time.Now(), true, testWindows()),
+				testClusterDeployment(),
+				testCDConfigMap(),
+				testPDISecret(),
+				testPagerDutyIntegration(),
+			},
+			expectDeleted: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateMaintenanceWindow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				r.DeleteMaintenanceWindow(testMaintenanceWindowID).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "Test Tenant Using Operator API Key",
+			localObjects: []runtime.Object{
+				testSilence(time.Now(), false, nil),
+				testClusterDeployment(),
+				testCDConfigMap(),
+				testPDISecret(),
+				testRefusedTenantPagerDutyIntegration(),
+			},
+			expectPhase:   pagerdutyv1alpha1.SilencePhasePending,
+			expectWindows: 0,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateMaintenanceWindow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				r.DeleteMaintenanceWindow(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Test Deleting Silence Of Tenant Using Operator API Key",
+			localObjects: []runtime.Object{
+				testSilence(time.Now(), true, testWindows()),
+				testClusterDeployment(),
+				testCDConfigMap(),
+				testPDISecret(),
+				testRefusedTenantPagerDutyIntegration(),
+			},
+			expectDeleted: true,
+			setupPDMock: func(r *mockpd.MockClientMockRecorder) {
+				r.CreateMaintenanceWindow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				r.DeleteMaintenanceWindow(gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Arrange
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockPDClient := mockpd.NewMockClient(mockCtrl)
+			test.setupPDMock(mockPDClient.EXPECT())
+
+			fakeKubeClient := fakekubeclient.NewFakeClient(test.localObjects...)
+			rpds := &ReconcilePagerDutySilence{
+				client:   fakeKubeClient,
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mockPDClient },
+			}
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: testSilenceName, Namespace: testNamespace},
+			}
+
+			// Act, twice to confirm the second run is a noop
+			_, err1 := rpds.Reconcile(request)
+			_, err2 := rpds.Reconcile(request)
+
+			// Assert
+			assert.NoError(t, err1, "Unexpected Error with Reconcile (1 of 2)")
+			assert.NoError(t, err2, "Unexpected Error with Reconcile (2 of 2)")
+
+			silence := &pagerdutyv1alpha1.PagerDutySilence{}
+			err := fakeKubeClient.Get(context.TODO(), request.NamespacedName, silence)
+			assert.NoError(t, err)
+			if test.expectDeleted {
+				assert.NotContains(t, silence.Finalizers, config.PagerDutySilenceFinalizer)
+				return
+			}
+			assert.Equal(t, test.expectPhase, silence.Status.Phase)
+			assert.Len(t, silence.Status.MaintenanceWindows, test.expectWindows)
+			assert.NotNil(t, silence.Status.ExpiresAt)
+			assert.True(t, verifyFinalizer(fakeKubeClient, request.NamespacedName))
+		})
+	}
+}
+
+// conflictingStatusClient fails the status update of the given number,
+// counting from 1, with a conflict
+type conflictingStatusClient struct {
+	client.Client
+	updates    *int
+	conflictAt int
+}
+
+func (c conflictingStatusClient) Status() client.StatusWriter {
+	return conflictingStatusWriter{StatusWriter: c.Client.Status(), client: c}
+}
+
+type conflictingStatusWriter struct {
+	client.StatusWriter
+	client conflictingStatusClient
+}
+
+func (w conflictingStatusWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	*w.client.updates++
+	if *w.client.updates == w.client.conflictAt {
+		return errors.NewConflict(pagerdutyv1alpha1.SchemeGroupVersion.WithResource("pagerdutysilences").GroupResource(), testSilenceName, fmt.Errorf("the object has been modified"))
+	}
+	return w.StatusWriter.Update(ctx, obj, opts...)
+}
+
+func TestReconcilePagerDutySilenceStatusConflict(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name       string
+		conflictAt int
+		expectEnd  bool
+	}{
+		{
+			// the window is saved, the status update ending the reconcile
+			// conflicts
+			name:       "Test Final Status Update Conflicts",
+			conflictAt: 2,
+		},
+		{
+			name:       "Test Saving The Window Conflicts",
+			conflictAt: 1,
+			expectEnd:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockPDClient := mockpd.NewMockClient(mockCtrl)
+			creates := 1
+			if test.expectEnd {
+				creates = 2
+				mockPDClient.EXPECT().DeleteMaintenanceWindow(testMaintenanceWindowID).Return(nil).Times(1)
+			}
+			mockPDClient.EXPECT().CreateMaintenanceWindow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(testMaintenanceWindowID, nil).Times(creates)
+
+			fakeKubeClient := fakekubeclient.NewFakeClient(
+				testSilence(time.Now(), false, nil),
+				testClusterDeployment(),
+				testCDConfigMap(),
+				testPDISecret(),
+				testPagerDutyIntegration(),
+			)
+			rpds := &ReconcilePagerDutySilence{
+				client:   conflictingStatusClient{Client: fakeKubeClient, updates: new(int), conflictAt: test.conflictAt},
+				scheme:   scheme.Scheme,
+				pdclient: func(s1 string, s2 string, opts ...pd.ClientOption) pd.Client { return mockPDClient },
+			}
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: testSilenceName, Namespace: testNamespace},
+			}
+
+			_, err1 := rpds.Reconcile(request)
+			_, err2 := rpds.Reconcile(request)
+
+			assert.Error(t, err1)
+			assert.NoError(t, err2)
+			silence := &pagerdutyv1alpha1.PagerDutySilence{}
+			err := fakeKubeClient.Get(context.TODO(), request.NamespacedName, silence)
+			assert.NoError(t, err)
+			assert.Equal(t, pagerdutyv1alpha1.SilencePhaseActive, silence.Status.Phase)
+			assert.Len(t, silence.Status.MaintenanceWindows, 1)
+		})
+	}
+}
+
+// verifyFinalizer verifies that the silence finalizer is set on the PagerDutySilence.
+func verifyFinalizer(c client.Client, name types.NamespacedName) bool {
+	silence := &pagerdutyv1alpha1.PagerDutySilence{}
+	err := c.Get(context.TODO(), name, silence)
+	if err != nil {
+		return false
+	}
+
+	for _, finalizer := range silence.Finalizers {
+		if finalizer == config.PagerDutySilenceFinalizer {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package debug serves the vars of the operator, in the format of expvar,
+// and, optionally, its pprof profiles, to investigate its performance.
+// Besides the memstats and cmdline, /debug/vars holds the stats of the
+// PagerDuty clients, under "pagerduty", and the cluster counts of each
+// PagerDutyIntegration, under "pagerdutyintegrations". The endpoints are not
+// authenticated and are meant to be reached through a port-forward.
+//
+// The expvar and net/http/pprof packages are not imported: they register
+// their endpoints on http.DefaultServeMux, which the metrics server serves.
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("debug")
+
+// ClusterCounts are the counts of the clusters of a PagerDutyIntegration,
+// from its status
+type ClusterCounts struct {
+	Clusters         int `json:"clusters"`
+	Ready            int `json:"ready"`
+	Pending          int `json:"pending"`
+	Failed           int `json:"failed"`
+	Anomalous        int `json:"anomalous"`
+	SecretSyncFailed int `json:"secretSyncFailed"`
+}
+
+// PagerDutyIntegrationCounts returns the ClusterCounts of each
+// PagerDutyIntegration, by namespace/name
+func PagerDutyIntegrationCounts(reader client.Reader) (map[string]ClusterCounts, error) {
+	pdiList := &pagerdutyv1alpha1.PagerDutyIntegrationList{}
+	err := reader.List(context.TODO(), pdiList, &client.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]ClusterCounts{}
+	for _, pdi := range pdiList.Items {
+		counts[pdi.Namespace+"/"+pdi.Name] = ClusterCounts{
+			Clusters:         len(pdi.Status.Clusters),
+			Ready:            pdi.Status.ReadyClusters,
+			Pending:          pdi.Status.PendingClusters,
+			Failed:           pdi.Status.FailedClusters,
+			Anomalous:        pdi.Status.AnomalousClusters,
+			SecretSyncFailed: pdi.Status.SecretSyncFailedClusters,
+		}
+	}
+	return counts, nil
+}
+
+// Server serves the debug endpoints on every replica of the operator, each
+// replica has its own clients and caches
+type Server struct {
+	// Addr is the address the server binds to
+	Addr string
+	// Pprof serves the pprof profiles under /debug/pprof/ too
+	Pprof bool
+	// Reader lists the PagerDutyIntegrations
+	Reader client.Reader
+}
+
+// Handler returns the handler of the debug endpoints, the pprof profiles
+// only if s.Pprof is set
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/vars", s.serveVars)
+	if s.Pprof {
+		mux.HandleFunc("/debug/pprof/", serveProfile)
+		mux.HandleFunc("/debug/pprof/cmdline", serveCmdline)
+		mux.HandleFunc("/debug/pprof/profile", serveCPUProfile)
+		mux.HandleFunc("/debug/pprof/trace", serveTrace)
+	}
+	return mux
+}
+
+// serveVars serves the vars as expvar.Handler would
+func (s *Server) serveVars(w http.ResponseWriter, req *http.Request) {
+	memstats := &runtime.MemStats{}
+	runtime.ReadMemStats(memstats)
+	vars := map[string]interface{}{
+		"cmdline":   os.Args,
+		"memstats":  memstats,
+		"pagerduty": pd.CurrentStats(),
+	}
+	counts, err := PagerDutyIntegrationCounts(s.Reader)
+	if err != nil {
+		vars["pagerdutyintegrations"] = map[string]string{"error": err.Error()}
+	} else {
+		vars["pagerdutyintegrations"] = counts
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(vars); err != nil {
+		log.Error(err, "Failed to write the debug vars")
+	}
+}
+
+// Start serves the debug endpoints until stop is closed
+func (s *Server) Start(stop <-chan struct{}) error {
+	srv := &http.Server{Addr: s.Addr, Handler: s.Handler()}
+
+	errs := make(chan error, 1)
+	go func() {
+		log.Info("Serving debug endpoints", "Addr", s.Addr, "Pprof", s.Pprof)
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("debug server stopped: %v", err)
+	case <-stop:
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return srv.Shutdown(ctx)
+	}
+}
+
+// NeedLeaderElection returns false, every replica serves the debug endpoints
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,125 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testServer(pprof bool) *Server {
+	pdi := &pagerdutyv1alpha1.PagerDutyIntegration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: config.OperatorNamespace, Name: "osd"},
+		Status: pagerdutyv1alpha1.PagerDutyIntegrationStatus{
+			Clusters:      []pagerdutyv1alpha1.ClusterStatus{{}, {}},
+			ReadyClusters: 1,
+		},
+	}
+	return &Server{
+		Pprof:  pprof,
+		Reader: fakekubeclient.NewFakeClient(pdi),
+	}
+}
+
+func get(handler http.Handler, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+// TestDefaultServeMux makes sure nothing registers the debug endpoints on
+// http.DefaultServeMux, which the metrics server serves without
+// authentication whatever --enable-pprof says
+func TestDefaultServeMux(t *testing.T) {
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+	_ = testServer(false).Handler()
+
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/heap", "/debug/vars"} {
+		_, pattern := http.DefaultServeMux.Handler(httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Empty(t, pattern, path)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	tests := []struct {
+		name       string
+		pprof      bool
+		path       string
+		expectCode int
+	}{
+		{
+			name:       "Test Vars",
+			path:       "/debug/vars",
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "Test Pprof Disabled",
+			path:       "/debug/pprof/heap",
+			expectCode: http.StatusNotFound,
+		},
+		{
+			name:       "Test Pprof Index",
+			pprof:      true,
+			path:       "/debug/pprof/",
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "Test Pprof Heap",
+			pprof:      true,
+			path:       "/debug/pprof/heap?debug=1",
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "Test Pprof Unknown Profile",
+			pprof:      true,
+			path:       "/debug/pprof/unknown",
+			expectCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := get(testServer(test.pprof).Handler(), test.path)
+			assert.Equal(t, test.expectCode, rec.Code)
+		})
+	}
+}
+
+func TestVars(t *testing.T) {
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	rec := get(testServer(false).Handler(), "/debug/vars")
+
+	vars := map[string]json.RawMessage{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &vars))
+	for _, name := range []string{"cmdline", "memstats", "pagerduty", "pagerdutyintegrations"} {
+		assert.Contains(t, vars, name)
+	}
+	counts := map[string]ClusterCounts{}
+	assert.NoError(t, json.Unmarshal(vars["pagerdutyintegrations"], &counts))
+	assert.Equal(t, ClusterCounts{Clusters: 2, Ready: 1}, counts[config.OperatorNamespace+"/osd"])
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// serveProfile serves the profile named after /debug/pprof/, and the index
+// of the profiles on /debug/pprof/ itself. The profiles are served the way
+// net/http/pprof serves them, so `go tool pprof` reads them the same.
+func serveProfile(w http.ResponseWriter, req *http.Request) {
+	name := strings.TrimPrefix(req.URL.Path, "/debug/pprof/")
+	if name == "" {
+		serveIndex(w)
+		return
+	}
+
+	profile := pprof.Lookup(name)
+	if profile == nil {
+		http.Error(w, fmt.Sprintf("Unknown profile %s", name), http.StatusNotFound)
+		return
+	}
+	if name == "heap" && req.FormValue("gc") != "" {
+		runtime.GC()
+	}
+	debug, _ := strconv.Atoi(req.FormValue("debug"))
+	if debug != 0 {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, name))
+	}
+	if err := profile.WriteTo(w, debug); err != nil {
+		log.Error(err, "Failed to write the profile", "Profile", name)
+	}
+}
+
+// serveIndex lists the profiles
+func serveIndex(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintln(w, "<html><body><ul>")
+	for _, profile := range pprof.Profiles() {
+		name := html.EscapeString(profile.Name())
+		fmt.Fprintf(w, "<li><a href=\"%s?debug=1\">%s</a> (%d)</li>\n", name, name, profile.Count())
+	}
+	fmt.Fprintln(w, `<li><a href="profile?seconds=30">profile</a></li>`)
+	fmt.Fprintln(w, `<li><a href="trace?seconds=5">trace</a></li>`)
+	fmt.Fprintln(w, "</ul></body></html>")
+}
+
+// serveCmdline serves the command line, its arguments separated by NUL bytes
+func serveCmdline(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, strings.Join(os.Args, "\x00"))
+}
+
+// serveCPUProfile serves the CPU profile of the ?seconds=, 30 by default
+func serveCPUProfile(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="profile"`)
+	if err := pprof.StartCPUProfile(w); err != nil {
+		// another profile is running
+		http.Error(w, fmt.Sprintf("Could not enable CPU profiling: %s", err), http.StatusInternalServerError)
+		return
+	}
+	sleep(req, durationOf(req, 30*time.Second))
+	pprof.StopCPUProfile()
+}
+
+// serveTrace serves the execution trace of the ?seconds=, 1 by default
+func serveTrace(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="trace"`)
+	if err := trace.Start(w); err != nil {
+		// another trace is running
+		http.Error(w, fmt.Sprintf("Could not enable tracing: %s", err), http.StatusInternalServerError)
+		return
+	}
+	sleep(req, durationOf(req, time.Second))
+	trace.Stop()
+}
+
+// durationOf returns the ?seconds= of the request, def if it has none
+func durationOf(req *http.Request, def time.Duration) time.Duration {
+	seconds, err := strconv.ParseFloat(req.FormValue("seconds"), 64)
+	if err != nil || seconds <= 0 {
+		return def
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// sleep waits for d, or until the client went away
+func sleep(req *http.Request, d time.Duration) {
+	select {
+	case <-time.After(d):
+	case <-req.Context().Done():
+	}
+}
@@ -0,0 +1,312 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dryrun makes reconciles log the changes they would make instead
+// of making them, so a change such as a new ClusterDeployment selector can
+// be validated against a production hub before it is rolled out. Reconciles
+// are dry runs while the operator runs with --dry-run, or for the custom
+// resources with the config.DryRunAnnotation annotation set to "true".
+// Their clients are swapped for ones passing reads through and logging
+// writes: Kubernetes objects created, updated, patched or deleted, PagerDuty
+// services, integrations, maintenance windows and rules, and events.
+package dryrun
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/go-logr/logr"
+	"github.com/openshift/pagerduty-operator/config"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// PlaceholderID stands for the ID of the PagerDuty objects a dry run
+// would have created, so the reconcile carries on as if they were
+const PlaceholderID = "DRYRUN"
+
+// enabled is set by --dry-run
+var enabled bool
+
+// FlagSet returns the flag making all reconciles dry runs, --dry-run
+func FlagSet() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("dryrun", pflag.ExitOnError)
+	fs.BoolVar(&enabled, "dry-run", false,
+		"Log the changes reconciles would make to PagerDuty and the hub instead of making them")
+	return fs
+}
+
+// Enabled returns true if the reconcile of obj is a dry run
+func Enabled(obj metav1.Object) bool {
+	return enabled || obj.GetAnnotations()[config.DryRunAnnotation] == "true"
+}
+
+// NewClient returns c logging its writes to logger instead of making them.
+// logger is read on each write, so it follows the scope of the reconcile.
+func NewClient(c client.Client, scheme *runtime.Scheme, logger *logr.Logger) client.Client {
+	return &dryRunClient{Reader: c, scheme: scheme, logger: logger}
+}
+
+type dryRunClient struct {
+	client.Reader
+	scheme *runtime.Scheme
+	logger *logr.Logger
+}
+
+func (c *dryRunClient) log(action string, obj runtime.Object) {
+	kind := fmt.Sprintf("%T", obj)
+	if gvk, err := apiutil.GVKForObject(obj, c.scheme); err == nil {
+		kind = gvk.Kind
+	}
+	namespace, name := "", ""
+	if accessor, err := meta.Accessor(obj); err == nil {
+		namespace, name = accessor.GetNamespace(), accessor.GetName()
+	}
+	(*c.logger).Info("Dry run, would "+action, "Kind", kind, "Namespace", namespace, "Name", name)
+}
+
+func (c *dryRunClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	c.log("create", obj)
+	return nil
+}
+
+func (c *dryRunClient) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	c.log("update", obj)
+	return nil
+}
+
+func (c *dryRunClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.log("patch", obj)
+	return nil
+}
+
+func (c *dryRunClient) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+	c.log("delete", obj)
+	return nil
+}
+
+func (c *dryRunClient) DeleteAllOf(ctx context.Context, obj runtime.Object, opts ...client.DeleteAllOfOption) error {
+	c.log("delete all of", obj)
+	return nil
+}
+
+func (c *dryRunClient) Status() client.StatusWriter {
+	return dryRunStatusWriter{c}
+}
+
+type dryRunStatusWriter struct {
+	c *dryRunClient
+}
+
+func (w dryRunStatusWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	w.c.log("update the status of", obj)
+	return nil
+}
+
+func (w dryRunStatusWriter) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	w.c.log("patch the status of", obj)
+	return nil
+}
+
+// NewPDClient returns c logging its changes to PagerDuty to logger instead
+// of making them. Objects it would have created get PlaceholderID as ID.
+func NewPDClient(c pd.Client, logger *logr.Logger) pd.Client {
+	return &dryRunPDClient{Client: c, logger: logger}
+}
+
+type dryRunPDClient struct {
+	pd.Client
+	logger *logr.Logger
+}
+
+func (c *dryRunPDClient) log(action string, keysAndValues ...interface{}) {
+	(*c.logger).Info("Dry run, would "+action, keysAndValues...)
+}
+
+func (c *dryRunPDClient) GetService(data *pd.Data) (*pdApi.Service, error) {
+	if data.ServiceID == PlaceholderID {
+		return &pdApi.Service{APIObject: pdApi.APIObject{ID: PlaceholderID}, Name: pd.ServiceName(data)}, nil
+	}
+	return c.Client.GetService(data)
+}
+
+func (c *dryRunPDClient) GetIntegrationKey(data *pd.Data) (string, error) {
+	if data.ServiceID == PlaceholderID || data.IntegrationID == PlaceholderID {
+		return PlaceholderID, nil
+	}
+	return c.Client.GetIntegrationKey(data)
+}
+
+func (c *dryRunPDClient) CreateService(data *pd.Data) (string, error) {
+	c.log("create PD service", "ServiceName", pd.ServiceName(data), "EscalationPolicyID", data.EscalationPolicyID)
+	data.ServiceID = PlaceholderID
+	data.IntegrationID = PlaceholderID
+	return data.IntegrationID, nil
+}
+
+func (c *dryRunPDClient) AdoptService(data *pd.Data, service *pdApi.Service) error {
+	c.log("adopt PD service", "ServiceID", service.ID)
+	data.ServiceID = service.ID
+	data.IntegrationID = pd.IntegrationID(service)
+	if data.IntegrationID == "" {
+		data.IntegrationID = PlaceholderID
+	}
+	return nil
+}
+
+func (c *dryRunPDClient) DeleteService(data *pd.Data) error {
+	c.log("delete PD service", "ServiceID", data.ServiceID)
+	return nil
+}
+
+func (c *dryRunPDClient) CreateMaintenanceWindow(data *pd.Data, description string, start time.Time, end time.Time) (string, error) {
+	c.log("create PD maintenance window", "ServiceID", data.ServiceID, "Description", description, "Start", start, "End", end)
+	return PlaceholderID, nil
+}
+
+func (c *dryRunPDClient) DeleteMaintenanceWindow(id string) error {
+	c.log("delete PD maintenance window", "MaintenanceWindowID", id)
+	return nil
+}
+
+func (c *dryRunPDClient) CreateSuppressionRule(rulesetID string, detail string, value string, start time.Time, end time.Time) (string, error) {
+	c.log("create PD suppression rule", "RulesetID", rulesetID, "Detail", detail, "Value", value, "Start", start, "End", end)
+	return PlaceholderID, nil
+}
+
+func (c *dryRunPDClient) SetServiceTags(data *pd.Data, tags map[string]string) error {
+	c.log("set PD service tags", "ServiceID", data.ServiceID, "Tags", tags)
+	return nil
+}
+
+func (c *dryRunPDClient) RepairService(data *pd.Data, service *pdApi.Service) error {
+	c.log("repair PD service", "ServiceID", data.ServiceID)
+	return nil
+}
+
+func (c *dryRunPDClient) AlertGroupingDrift(data *pd.Data) ([]string, error) {
+	if data.ServiceID == PlaceholderID {
+		return []string{}, nil
+	}
+	return c.Client.AlertGroupingDrift(data)
+}
+
+func (c *dryRunPDClient) SetAlertGrouping(data *pd.Data) error {
+	c.log("set PD alert grouping", "ServiceID", data.ServiceID, "Type", data.AlertGrouping.Type)
+	return nil
+}
+
+func (c *dryRunPDClient) SetServiceDependencies(data *pd.Data, hubServiceID string, businessServiceID string) error {
+	c.log("set PD service dependencies", "ServiceID", data.ServiceID, "HubServiceID", hubServiceID, "BusinessServiceID", businessServiceID)
+	return nil
+}
+
+func (c *dryRunPDClient) SendChangeEvent(routingKey string, summary string, details map[string]string) error {
+	c.log("send PD change event", "Summary", summary)
+	return nil
+}
+
+func (c *dryRunPDClient) SendTestAlert(integrationKey string, clusterID string) (pd.TestAlertResult, error) {
+	c.log("send PD test alert", "ClusterID", clusterID)
+	return pd.TestAlertResult{Accepted: true}, nil
+}
+
+func (c *dryRunPDClient) CreateHeartbeatIntegration(data *pd.Data) (string, error) {
+	c.log("create PD heartbeat integration", "ServiceID", data.ServiceID)
+	return PlaceholderID, nil
+}
+
+func (c *dryRunPDClient) RotateIntegrationKey(data *pd.Data) error {
+	c.log("rotate PD integration key", "ServiceID", data.ServiceID, "IntegrationID", data.IntegrationID)
+	data.IntegrationID = PlaceholderID
+	return nil
+}
+
+func (c *dryRunPDClient) RecreateMissingIntegration(data *pd.Data) (bool, error) {
+	if data.ServiceID == PlaceholderID || data.IntegrationID == PlaceholderID {
+		return false, nil
+	}
+	_, err := c.Client.GetIntegrationKey(data)
+	if err == nil || !pd.IsNotFound(err) {
+		return false, err
+	}
+	c.log("recreate missing PD integration", "ServiceID", data.ServiceID, "IntegrationID", data.IntegrationID)
+	data.IntegrationID = PlaceholderID
+	return true, nil
+}
+
+func (c *dryRunPDClient) SendHeartbeatEvent(integrationKey string, clusterID string, missed bool) error {
+	c.log("send PD heartbeat event", "ClusterID", clusterID, "Missed", missed)
+	return nil
+}
+
+func (c *dryRunPDClient) DisableService(data *pd.Data) error {
+	c.log("disable PD service", "ServiceID", data.ServiceID)
+	return nil
+}
+
+func (c *dryRunPDClient) EnableService(data *pd.Data) error {
+	c.log("enable PD service", "ServiceID", data.ServiceID)
+	return nil
+}
+
+func (c *dryRunPDClient) RenameService(data *pd.Data) error {
+	c.log("rename PD service", "ServiceID", data.ServiceID, "Name", pd.ServiceName(data))
+	return nil
+}
+
+func (c *dryRunPDClient) CreateEventRule(rulesetID string, rule pd.EventRule) (string, error) {
+	c.log("create PD event rule", "RulesetID", rulesetID)
+	return PlaceholderID, nil
+}
+
+func (c *dryRunPDClient) UpdateEventRule(rulesetID string, ruleID string, rule pd.EventRule) error {
+	c.log("update PD event rule", "RulesetID", rulesetID, "RuleID", ruleID)
+	return nil
+}
+
+func (c *dryRunPDClient) DeleteEventRule(rulesetID string, ruleID string) error {
+	c.log("delete PD event rule", "RulesetID", rulesetID, "RuleID", ruleID)
+	return nil
+}
+
+// NewRecorder returns a recorder logging the events to logger instead of
+// recording them
+func NewRecorder(logger *logr.Logger) record.EventRecorder {
+	return &dryRunRecorder{logger: logger}
+}
+
+type dryRunRecorder struct {
+	logger *logr.Logger
+}
+
+func (r *dryRunRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	(*r.logger).Info("Dry run, would record event", "Type", eventtype, "Reason", reason, "Message", message)
+}
+
+func (r *dryRunRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *dryRunRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventtype, reason, messageFmt, args...)
+}
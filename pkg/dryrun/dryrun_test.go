@@ -0,0 +1,136 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dryrun
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/openshift/pagerduty-operator/config"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	mockpd "github.com/openshift/pagerduty-operator/pkg/pagerduty/mock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// recordingLogger records the messages logged at Info
+type recordingLogger struct {
+	logr.Logger
+	messages *[]string
+}
+
+func newRecordingLogger() (logr.Logger, *[]string) {
+	messages := &[]string{}
+	return recordingLogger{messages: messages}, messages
+}
+
+func (l recordingLogger) Info(msg string, keysAndValues ...interface{}) {
+	*l.messages = append(*l.messages, msg)
+}
+
+func testConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-configmap", Namespace: "test-namespace"},
+		Data:       map[string]string{"SERVICE_ID": "ABC123"},
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	defer func() { enabled = false }()
+
+	cm := testConfigMap()
+	assert.False(t, Enabled(cm))
+
+	cm.Annotations = map[string]string{config.DryRunAnnotation: "true"}
+	assert.True(t, Enabled(cm))
+
+	cm.Annotations[config.DryRunAnnotation] = "false"
+	assert.False(t, Enabled(cm))
+
+	assert.NoError(t, FlagSet().Parse([]string{"--dry-run"}))
+	assert.True(t, Enabled(cm))
+}
+
+func TestClient(t *testing.T) {
+	fakeClient := fakekubeclient.NewFakeClient(testConfigMap())
+	logger, messages := newRecordingLogger()
+	c := NewClient(fakeClient, scheme.Scheme, &logger)
+
+	// reads pass through
+	cm := &corev1.ConfigMap{}
+	key := types.NamespacedName{Name: "test-configmap", Namespace: "test-namespace"}
+	assert.NoError(t, c.Get(context.TODO(), key, cm))
+
+	// writes are only logged
+	cm.Data["SERVICE_ID"] = "DEF456"
+	assert.NoError(t, c.Update(context.TODO(), cm))
+	assert.NoError(t, c.Status().Update(context.TODO(), cm))
+	assert.NoError(t, c.Delete(context.TODO(), cm))
+	created := testConfigMap()
+	created.Name = "created-configmap"
+	assert.NoError(t, c.Create(context.TODO(), created))
+
+	assert.Equal(t, []string{
+		"Dry run, would update",
+		"Dry run, would update the status of",
+		"Dry run, would delete",
+		"Dry run, would create",
+	}, *messages)
+
+	found := &corev1.ConfigMap{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), key, found))
+	assert.Equal(t, "ABC123", found.Data["SERVICE_ID"])
+	err := fakeClient.Get(context.TODO(), types.NamespacedName{Name: "created-configmap", Namespace: "test-namespace"}, found)
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestPDClient(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPDClient := mockpd.NewMockClient(mockCtrl)
+	// only reads of existing objects reach PagerDuty
+	mockPDClient.EXPECT().GetIntegrationKey(&pd.Data{ServiceID: "ABC123"}).Return("existing-key", nil).Times(1)
+
+	logger, messages := newRecordingLogger()
+	c := NewPDClient(mockPDClient, &logger)
+
+	data := &pd.Data{ServicePrefix: "test", ClusterID: "test-cluster"}
+	_, err := c.CreateService(data)
+	assert.NoError(t, err)
+	assert.Equal(t, PlaceholderID, data.ServiceID)
+	assert.Equal(t, PlaceholderID, data.IntegrationID)
+
+	key, err := c.GetIntegrationKey(data)
+	assert.NoError(t, err)
+	assert.Equal(t, PlaceholderID, key)
+
+	key, err = c.GetIntegrationKey(&pd.Data{ServiceID: "ABC123"})
+	assert.NoError(t, err)
+	assert.Equal(t, "existing-key", key)
+
+	assert.NoError(t, c.DeleteService(&pd.Data{ServiceID: "ABC123"}))
+
+	assert.Equal(t, []string{
+		"Dry run, would create PD service",
+		"Dry run, would delete PD service",
+	}, *messages)
+}
@@ -0,0 +1,311 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fleetaudit compares the ClusterDeployments managed by the
+// PagerDutyIntegrations with the services in PagerDuty, for fleet
+// reporting: for every managed cluster it reports the service it should
+// have, and whether that service and its integration exist in PagerDuty and
+// match. It only reads, from the hub and from PagerDuty.
+package fleetaudit
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Result is how the PagerDuty service of a cluster compares to PagerDuty
+type Result string
+
+const (
+	// ResultMatch means the service and its integration exist and the
+	// service has the expected name
+	ResultMatch Result = "Match"
+	// ResultNotSetUp means the cluster has no service recorded on the hub
+	ResultNotSetUp Result = "NotSetUp"
+	// ResultServiceMissing means the recorded service is not in PagerDuty
+	ResultServiceMissing Result = "ServiceMissing"
+	// ResultIntegrationMissing means the recorded integration is not on the
+	// service
+	ResultIntegrationMissing Result = "IntegrationMissing"
+	// ResultNameMismatch means the service exists under another name than
+	// the expected one
+	ResultNameMismatch Result = "NameMismatch"
+	// ResultError means the cluster couldn't be checked
+	ResultError Result = "Error"
+)
+
+// Entry is the audit of the PagerDuty service of one cluster
+type Entry struct {
+	// PagerDutyIntegration is the namespace/name of the PagerDutyIntegration,
+	// with the .<servicePrefix> of an additional service
+	PagerDutyIntegration string `json:"pagerDutyIntegration"`
+	// ClusterDeployment is the namespace/name of the ClusterDeployment
+	ClusterDeployment   string `json:"clusterDeployment"`
+	ExpectedServiceName string `json:"expectedServiceName"`
+	// ServiceID and IntegrationID are those recorded on the hub
+	ServiceID     string `json:"serviceID,omitempty"`
+	IntegrationID string `json:"integrationID,omitempty"`
+	// ServiceName is the name of the service in PagerDuty
+	ServiceName       string `json:"serviceName,omitempty"`
+	ServiceExists     bool   `json:"serviceExists"`
+	IntegrationExists bool   `json:"integrationExists"`
+	Result            Result `json:"result"`
+	Error             string `json:"error,omitempty"`
+}
+
+// Options configures Run
+type Options struct {
+	// Client reads the PagerDutyIntegrations, ClusterDeployments and the
+	// records of their services on the hub
+	Client client.Client
+	// PDClient builds a PagerDuty client for an API key
+	PDClient func(apiKey string, opts ...pd.ClientOption) pd.Client
+}
+
+// service is a PagerDuty service each cluster of a PagerDutyIntegration
+// gets, its own or one of its additional services
+type service struct {
+	owner    string
+	prefix   string
+	selector metav1.LabelSelector
+	// finalizer is on the ClusterDeployments the service was set up for
+	finalizer string
+	normalize bool
+	template  string
+}
+
+// Run audits every cluster managed by a PagerDutyIntegration, sorted by
+// PagerDutyIntegration and ClusterDeployment. A PagerDutyIntegration whose
+// API key can't be loaded gets an Error entry for each of its clusters.
+func Run(opts Options) ([]Entry, error) {
+	pdiList := &pagerdutyv1alpha1.PagerDutyIntegrationList{}
+	err := opts.Client.List(context.TODO(), pdiList)
+	if err != nil {
+		return nil, err
+	}
+	cdList := &hivev1.ClusterDeploymentList{}
+	err = opts.Client.List(context.TODO(), cdList)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []Entry{}
+	for i := range pdiList.Items {
+		pdi := &pdiList.Items[i]
+		if pdi.DeletionTimestamp != nil || pdi.Spec.SharedIntegrationKey != nil {
+			// clusters paging a shared service have none of their own
+			continue
+		}
+
+		var pdclient pd.Client
+		apiKey, keyErr := utils.LoadSecretData(
+			opts.Client,
+			pdi.Spec.PagerdutyApiKeySecretRef.Name,
+			pdi.Spec.PagerdutyApiKeySecretRef.Namespace,
+			config.PagerDutyAPISecretKey,
+		)
+		if keyErr == nil {
+			pdclient = opts.PDClient(apiKey, pd.WithAPIEndpoint(pdi.Spec.APIEndpoint))
+		}
+
+		for _, svc := range services(pdi) {
+			selector, err := metav1.LabelSelectorAsSelector(&svc.selector)
+			if err != nil {
+				return nil, err
+			}
+			for j := range cdList.Items {
+				cd := &cdList.Items[j]
+				if !managed(cd, selector, svc.finalizer) {
+					continue
+				}
+				entry := Entry{
+					PagerDutyIntegration: pdi.Namespace + "/" + svc.owner,
+					ClusterDeployment:    cd.Namespace + "/" + cd.Name,
+				}
+				if keyErr != nil {
+					entry.Result = ResultError
+					entry.Error = fmt.Sprintf("failed to load the API key: %v", keyErr)
+					entries = append(entries, entry)
+					continue
+				}
+				entries = append(entries, audit(opts.Client, pdclient, svc, cd, entry))
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].PagerDutyIntegration != entries[j].PagerDutyIntegration {
+			return entries[i].PagerDutyIntegration < entries[j].PagerDutyIntegration
+		}
+		return entries[i].ClusterDeployment < entries[j].ClusterDeployment
+	})
+	return entries, nil
+}
+
+// services returns the services of pdi, its own first. The service name
+// template only applies to its own.
+func services(pdi *pagerdutyv1alpha1.PagerDutyIntegration) []service {
+	list := []service{{
+		owner:     pdi.Name,
+		prefix:    pdi.Spec.ServicePrefix,
+		selector:  pdi.Spec.ClusterDeploymentSelector,
+		finalizer: config.PagerDutyFinalizerPrefix + pdi.Name,
+		normalize: pdi.Spec.NormalizeServiceNames,
+		template:  pdi.Spec.ServiceNameTemplate,
+	}}
+	for _, as := range pdi.Spec.AdditionalServices {
+		owner := pdi.Name + "." + as.ServicePrefix
+		list = append(list, service{
+			owner:     owner,
+			prefix:    as.ServicePrefix,
+			selector:  as.ClusterDeploymentSelector,
+			finalizer: config.PagerDutyFinalizerPrefix + owner,
+			normalize: pdi.Spec.NormalizeServiceNames,
+		})
+	}
+	return list
+}
+
+// managed returns true if the installed cluster is selected for the service,
+// or was set up for it, such as a cluster that opted in
+func managed(cd *hivev1.ClusterDeployment, selector labels.Selector, finalizer string) bool {
+	if !cd.Spec.Installed || cd.DeletionTimestamp != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(cd.Labels)) || utils.HasFinalizer(cd, finalizer)
+}
+
+// audit fills in entry for the service of cd
+func audit(c client.Client, pdclient pd.Client, svc service, cd *hivev1.ClusterDeployment, entry Entry) Entry {
+	pdData := &pd.Data{
+		ClusterID:           cd.Spec.ClusterName,
+		BaseDomain:          cd.Spec.BaseDomain,
+		ServicePrefix:       svc.prefix,
+		NormalizeName:       svc.normalize,
+		ServiceNameTemplate: svc.template,
+		Labels:              cd.Labels,
+	}
+	entry.ExpectedServiceName = pd.ServiceName(pdData)
+
+	err := pdData.ParseClusterConfig(c, cd.Namespace, naming.ConfigMapName(svc.prefix, cd.Name))
+	if err != nil {
+		if errors.IsNotFound(err) {
+			entry.Result = ResultNotSetUp
+			return entry
+		}
+		entry.Result = ResultError
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.ServiceID = pdData.ServiceID
+	entry.IntegrationID = pdData.IntegrationID
+
+	remote, err := pdclient.GetService(pdData)
+	if err != nil {
+		if pd.IsNotFound(err) {
+			entry.Result = ResultServiceMissing
+			return entry
+		}
+		entry.Result = ResultError
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.ServiceExists = true
+	entry.ServiceName = remote.Name
+
+	_, err = pdclient.GetIntegrationKey(pdData)
+	if err != nil {
+		if pd.IsNotFound(err) {
+			entry.Result = ResultIntegrationMissing
+			return entry
+		}
+		entry.Result = ResultError
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.IntegrationExists = true
+
+	entry.Result = ResultMatch
+	if remote.Name != entry.ExpectedServiceName {
+		entry.Result = ResultNameMismatch
+	}
+	return entry
+}
+
+// WriteJSON writes the entries to w as an indented JSON array
+func WriteJSON(w io.Writer, entries []Entry) error {
+	out, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(out))
+	return err
+}
+
+// csvHeader names the columns of WriteCSV, after the JSON fields
+var csvHeader = []string{
+	"pagerDutyIntegration",
+	"clusterDeployment",
+	"expectedServiceName",
+	"serviceID",
+	"integrationID",
+	"serviceName",
+	"serviceExists",
+	"integrationExists",
+	"result",
+	"error",
+}
+
+// WriteCSV writes the entries to w as CSV, with a header line
+func WriteCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		record := []string{
+			e.PagerDutyIntegration,
+			e.ClusterDeployment,
+			e.ExpectedServiceName,
+			e.ServiceID,
+			e.IntegrationID,
+			e.ServiceName,
+			strconv.FormatBool(e.ServiceExists),
+			strconv.FormatBool(e.IntegrationExists),
+			string(e.Result),
+			e.Error,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
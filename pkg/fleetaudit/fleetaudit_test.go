@@ -0,0 +1,198 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fleetaudit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/golang/mock/gomock"
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/kube"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	mockpd "github.com/openshift/pagerduty-operator/pkg/pagerduty/mock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNamespace  = "uhc-production-1234"
+	testPrefix     = "osd"
+	testBaseDomain = "example.com"
+)
+
+func testPagerDutyIntegration() *pagerdutyv1alpha1.PagerDutyIntegration {
+	return &pagerdutyv1alpha1.PagerDutyIntegration{
+		ObjectMeta: metav1.ObjectMeta{Name: "osd", Namespace: config.OperatorNamespace},
+		Spec: pagerdutyv1alpha1.PagerDutyIntegrationSpec{
+			ServicePrefix: testPrefix,
+			ClusterDeploymentSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{config.ClusterDeploymentManagedLabel: "true"},
+			},
+			PagerdutyApiKeySecretRef: corev1.SecretReference{
+				Name:      config.PagerDutyAPISecretName,
+				Namespace: config.OperatorNamespace,
+			},
+		},
+	}
+}
+
+func testSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: config.PagerDutyAPISecretName, Namespace: config.OperatorNamespace},
+		Data:       map[string][]byte{config.PagerDutyAPISecretKey: []byte("test-api-key")},
+	}
+}
+
+func testClusterDeployment(name string, managed bool) *hivev1.ClusterDeployment {
+	cd := &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, Labels: map[string]string{}},
+		Spec: hivev1.ClusterDeploymentSpec{
+			ClusterName: name,
+			BaseDomain:  testBaseDomain,
+			Installed:   true,
+		},
+	}
+	if managed {
+		cd.Labels[config.ClusterDeploymentManagedLabel] = "true"
+	}
+	return cd
+}
+
+func testPagerDutyService(cdName, serviceID, integrationID string) *pagerdutyv1alpha1.PagerDutyService {
+	return kube.GeneratePagerDutyService(testNamespace, naming.ConfigMapName(testPrefix, cdName), "osd", cdName, serviceID, integrationID)
+}
+
+var errNotFound = errors.New("Failed call API endpoint. HTTP response code: 404. Error: &{}")
+
+func TestRun(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	objects := []runtime.Object{
+		testPagerDutyIntegration(),
+		testSecret(),
+		testClusterDeployment("match", true),
+		testPagerDutyService("match", "SVC-MATCH", "INT-MATCH"),
+		testClusterDeployment("not-set-up", true),
+		testClusterDeployment("service-missing", true),
+		testPagerDutyService("service-missing", "SVC-GONE", "INT-GONE"),
+		testClusterDeployment("integration-missing", true),
+		testPagerDutyService("integration-missing", "SVC-NOINT", "INT-GONE"),
+		testClusterDeployment("renamed", true),
+		testPagerDutyService("renamed", "SVC-RENAMED", "INT-RENAMED"),
+		testClusterDeployment("unmanaged", false),
+	}
+	c := fakekubeclient.NewFakeClientWithScheme(scheme.Scheme, objects...)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	pdclient := mockpd.NewMockClient(mockCtrl)
+	pdclient.EXPECT().GetService(gomock.Any()).DoAndReturn(func(data *pd.Data) (*pdApi.Service, error) {
+		switch data.ServiceID {
+		case "SVC-GONE":
+			return nil, errNotFound
+		case "SVC-RENAMED":
+			return &pdApi.Service{Name: "renamed by hand"}, nil
+		}
+		return &pdApi.Service{Name: pd.ServiceName(data)}, nil
+	}).AnyTimes()
+	pdclient.EXPECT().GetIntegrationKey(gomock.Any()).DoAndReturn(func(data *pd.Data) (string, error) {
+		if data.IntegrationID == "INT-GONE" {
+			return "", errNotFound
+		}
+		return "key", nil
+	}).AnyTimes()
+
+	entries, err := Run(Options{
+		Client:   c,
+		PDClient: func(apiKey string, opts ...pd.ClientOption) pd.Client { return pdclient },
+	})
+
+	assert.NoError(t, err)
+	results := map[string]Result{}
+	for _, e := range entries {
+		assert.Equal(t, config.OperatorNamespace+"/osd", e.PagerDutyIntegration)
+		results[e.ClusterDeployment] = e.Result
+	}
+	assert.Equal(t, map[string]Result{
+		testNamespace + "/match":               ResultMatch,
+		testNamespace + "/not-set-up":          ResultNotSetUp,
+		testNamespace + "/service-missing":     ResultServiceMissing,
+		testNamespace + "/integration-missing": ResultIntegrationMissing,
+		testNamespace + "/renamed":             ResultNameMismatch,
+	}, results)
+	assert.Equal(t, testNamespace+"/integration-missing", entries[0].ClusterDeployment, "entries are not sorted")
+	assert.Equal(t, "osd-match.example.com-hive-cluster", entries[1].ExpectedServiceName)
+}
+
+func TestRunAPIKeyMissing(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	c := fakekubeclient.NewFakeClientWithScheme(scheme.Scheme,
+		testPagerDutyIntegration(),
+		testClusterDeployment("match", true),
+	)
+
+	entries, err := Run(Options{
+		Client: c,
+		PDClient: func(apiKey string, opts ...pd.ClientOption) pd.Client {
+			t.Fatal("no API key to build a client with")
+			return nil
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, ResultError, entries[0].Result)
+	assert.Contains(t, entries[0].Error, "failed to load the API key")
+}
+
+func TestWrite(t *testing.T) {
+	entries := []Entry{
+		{PagerDutyIntegration: "ns/osd", ClusterDeployment: "ns/a", ExpectedServiceName: "osd-a", ServiceID: "S1", IntegrationID: "I1", ServiceName: "osd-a", ServiceExists: true, IntegrationExists: true, Result: ResultMatch},
+		{PagerDutyIntegration: "ns/osd", ClusterDeployment: "ns/b", ExpectedServiceName: "osd-b", Result: ResultError, Error: "boom, again"},
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, WriteJSON(&out, entries))
+	decoded := []Entry{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, entries, decoded)
+
+	out.Reset()
+	assert.NoError(t, WriteCSV(&out, entries))
+	records, err := csv.NewReader(&out).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		csvHeader,
+		{"ns/osd", "ns/a", "osd-a", "S1", "I1", "osd-a", "true", "true", "Match", ""},
+		{"ns/osd", "ns/b", "osd-b", "", "", "", "false", "false", "Error", "boom, again"},
+	}, records)
+}
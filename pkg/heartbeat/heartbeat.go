@@ -0,0 +1,204 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package heartbeat records the check-ins of the clusters of a
+// PagerDutyIntegration with spec.heartbeat set. Each cluster checks in by
+// POSTing to its own URL, served by the operator, at least once per
+// interval; the check-ins are recorded as the renew time of a Lease in the
+// namespace of the ClusterDeployment. A cluster that didn't check in for
+// twice its interval has missed its heartbeat, and the controller pages on
+// it through the heartbeat integration of its service.
+package heartbeat
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("heartbeat")
+
+// pathPrefix prefixes the path of every check-in URL
+const pathPrefix = "/heartbeat/"
+
+// missedIntervals is how many intervals a cluster may go without checking
+// in before it has missed its heartbeat, so a single late check-in isn't
+// paged on
+const missedIntervals = 2
+
+// Interval returns how often clusters are expected to check in, no less
+// than config.HeartbeatMinInterval
+func Interval(hb *pagerdutyv1alpha1.Heartbeat) time.Duration {
+	if hb.Interval.Duration < config.HeartbeatMinInterval {
+		return config.HeartbeatMinInterval
+	}
+	return hb.Interval.Duration
+}
+
+// URL returns the URL the cluster whose check-ins lease records checks in at
+func URL(hb *pagerdutyv1alpha1.Heartbeat, lease *coordinationv1.Lease) string {
+	return strings.TrimSuffix(hb.URL, "/") + pathPrefix + lease.Namespace + "/" + lease.Name + "/" + lease.Annotations[config.HeartbeatTokenAnnotation]
+}
+
+// NewLease returns the Lease recording the check-ins of a cluster, with a
+// new random token. The cluster is considered to have checked in at now,
+// so it has two intervals to start checking in.
+func NewLease(namespace, name string, now time.Time) (*coordinationv1.Lease, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	renewTime := metav1.NewMicroTime(now)
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: map[string]string{config.HeartbeatTokenAnnotation: hex.EncodeToString(token)},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			RenewTime: &renewTime,
+		},
+	}, nil
+}
+
+// LastCheckIn returns the time the cluster whose check-ins lease records
+// last checked in, the creation of lease if it never did
+func LastCheckIn(lease *coordinationv1.Lease) time.Time {
+	if lease.Spec.RenewTime != nil {
+		return lease.Spec.RenewTime.Time
+	}
+	return lease.CreationTimestamp.Time
+}
+
+// Deadline returns the time the cluster whose check-ins lease records
+// misses its heartbeat unless it checks in before
+func Deadline(hb *pagerdutyv1alpha1.Heartbeat, lease *coordinationv1.Lease) time.Time {
+	return LastCheckIn(lease).Add(missedIntervals * Interval(hb))
+}
+
+// Missed returns true if the cluster whose check-ins lease records missed
+// its heartbeat as of now
+func Missed(hb *pagerdutyv1alpha1.Heartbeat, lease *coordinationv1.Lease, now time.Time) bool {
+	return now.After(Deadline(hb, lease))
+}
+
+// Handler records the check-ins POSTed to the URLs of the clusters
+type Handler struct {
+	// reader reads the Leases, uncached so the Leases of the hub aren't
+	// all watched
+	reader client.Reader
+	// writer renews the Leases
+	writer client.Writer
+	// clock returns the current time, time.Now if nil
+	clock func() time.Time
+}
+
+// NewHandler returns a Handler reading the Leases with reader and renewing
+// them with writer
+func NewHandler(reader client.Reader, writer client.Writer) *Handler {
+	return &Handler{reader: reader, writer: writer}
+}
+
+// ServeHTTP renews the Lease the path of the request names if the request
+// carries its token. Unknown Leases, Leases the operator didn't create and
+// wrong tokens all get a 404, so neither can be probed for.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(req.URL.Path, pathPrefix), "/")
+	if !strings.HasPrefix(req.URL.Path, pathPrefix) || len(parts) != 3 {
+		http.NotFound(w, req)
+		return
+	}
+	namespace, name, token := parts[0], parts[1], parts[2]
+
+	lease := &coordinationv1.Lease{}
+	err := h.reader.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, lease)
+	if err != nil && !errors.IsNotFound(err) {
+		log.Error(err, "Failed to get the heartbeat lease", "Namespace", namespace, "Name", name)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	expected := lease.Annotations[config.HeartbeatTokenAnnotation]
+	if err != nil || expected == "" || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		http.NotFound(w, req)
+		return
+	}
+
+	now := time.Now()
+	if h.clock != nil {
+		now = h.clock()
+	}
+	renewTime := metav1.NewMicroTime(now)
+	lease.Spec.RenewTime = &renewTime
+	err = h.writer.Update(context.TODO(), lease)
+	if err != nil {
+		log.Error(err, "Failed to renew the heartbeat lease", "Namespace", namespace, "Name", name)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// Server serves the check-ins on every replica of the operator, so clusters
+// keep checking in while the leader changes
+type Server struct {
+	// Addr is the address the server binds to
+	Addr string
+	// Handler records the check-ins
+	Handler http.Handler
+}
+
+// Start serves the check-ins until stop is closed
+func (s *Server) Start(stop <-chan struct{}) error {
+	mux := http.NewServeMux()
+	mux.Handle(pathPrefix, s.Handler)
+	srv := &http.Server{Addr: s.Addr, Handler: mux}
+
+	errs := make(chan error, 1)
+	go func() {
+		log.Info("Serving heartbeat check-ins", "Addr", s.Addr)
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("heartbeat server stopped: %v", err)
+	case <-stop:
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return srv.Shutdown(ctx)
+	}
+}
+
+// NeedLeaderElection returns false, every replica serves the check-ins
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
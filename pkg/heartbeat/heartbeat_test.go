@@ -0,0 +1,172 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heartbeat
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNamespace = "testNamespace"
+	testLeaseName = "test-service-prefix-testCluster-pd-heartbeat"
+)
+
+func testHeartbeat(interval time.Duration) *pagerdutyv1alpha1.Heartbeat {
+	return &pagerdutyv1alpha1.Heartbeat{
+		URL:      "https://heartbeat.example.com/",
+		Interval: metav1.Duration{Duration: interval},
+	}
+}
+
+func TestURL(t *testing.T) {
+	lease, err := NewLease(testNamespace, testLeaseName, time.Now())
+	assert.NoError(t, err)
+	token := lease.Annotations[config.HeartbeatTokenAnnotation]
+	assert.Len(t, token, 64)
+
+	assert.Equal(t, "https://heartbeat.example.com/heartbeat/testNamespace/"+testLeaseName+"/"+token, URL(testHeartbeat(time.Hour), lease))
+
+	other, err := NewLease(testNamespace, testLeaseName, time.Now())
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, other.Annotations[config.HeartbeatTokenAnnotation])
+}
+
+func TestMissed(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		interval     time.Duration
+		lastCheckIn  time.Time
+		expectMissed bool
+	}{
+		{
+			name:         "Test Checked In",
+			interval:     time.Hour,
+			lastCheckIn:  now.Add(-time.Hour),
+			expectMissed: false,
+		},
+		{
+			name:         "Test Late Check-In Not Missed",
+			interval:     time.Hour,
+			lastCheckIn:  now.Add(-90 * time.Minute),
+			expectMissed: false,
+		},
+		{
+			name:         "Test Missed",
+			interval:     time.Hour,
+			lastCheckIn:  now.Add(-3 * time.Hour),
+			expectMissed: true,
+		},
+		{
+			name:         "Test Short Interval Raised",
+			interval:     time.Minute,
+			lastCheckIn:  now.Add(-5 * time.Minute),
+			expectMissed: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lease, err := NewLease(testNamespace, testLeaseName, test.lastCheckIn)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectMissed, Missed(testHeartbeat(test.interval), lease, now))
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	created := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := created.Add(time.Hour)
+
+	lease, err := NewLease(testNamespace, testLeaseName, created)
+	assert.NoError(t, err)
+	token := lease.Annotations[config.HeartbeatTokenAnnotation]
+	foreign := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: "foreign", Namespace: testNamespace}}
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		expectStatus  int
+		expectRenewed bool
+	}{
+		{
+			name:          "Test Check-In",
+			method:        http.MethodPost,
+			path:          "/heartbeat/testNamespace/" + testLeaseName + "/" + token,
+			expectStatus:  http.StatusOK,
+			expectRenewed: true,
+		},
+		{
+			name:         "Test Wrong Token",
+			method:       http.MethodPost,
+			path:         "/heartbeat/testNamespace/" + testLeaseName + "/wrong",
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			name:         "Test Unknown Lease",
+			method:       http.MethodPost,
+			path:         "/heartbeat/testNamespace/unknown/" + token,
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			name:         "Test Lease Without Token",
+			method:       http.MethodPost,
+			path:         "/heartbeat/testNamespace/foreign/",
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			name:         "Test Malformed Path",
+			method:       http.MethodPost,
+			path:         "/heartbeat/testNamespace/" + testLeaseName,
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			name:         "Test Get Rejected",
+			method:       http.MethodGet,
+			path:         "/heartbeat/testNamespace/" + testLeaseName + "/" + token,
+			expectStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := fakekubeclient.NewFakeClient(lease.DeepCopy(), foreign.DeepCopy())
+			h := NewHandler(c, c)
+			h.clock = func() time.Time { return now }
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(test.method, test.path, nil))
+			assert.Equal(t, test.expectStatus, rec.Code)
+
+			renewed := &coordinationv1.Lease{}
+			assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testLeaseName}, renewed))
+			assert.Equal(t, test.expectRenewed, renewed.Spec.RenewTime.Time.Equal(now))
+		})
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"encoding/json"
+	"fmt"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/heartbeat"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// alertmanagerConfig is the part of the Alertmanager configuration the
+// operator generates. Structs rather than maps keep the keys in a fixed
+// order.
+type alertmanagerConfig struct {
+	Route     alertmanagerRoute      `json:"route"`
+	Receivers []alertmanagerReceiver `json:"receivers"`
+}
+
+type alertmanagerRoute struct {
+	Receiver       string              `json:"receiver"`
+	Match          map[string]string   `json:"match,omitempty"`
+	GroupWait      string              `json:"group_wait,omitempty"`
+	GroupInterval  string              `json:"group_interval,omitempty"`
+	RepeatInterval string              `json:"repeat_interval,omitempty"`
+	Routes         []alertmanagerRoute `json:"routes,omitempty"`
+}
+
+type alertmanagerReceiver struct {
+	Name             string                        `json:"name"`
+	PagerdutyConfigs []alertmanagerPagerdutyConfig `json:"pagerduty_configs,omitempty"`
+	WebhookConfigs   []alertmanagerWebhookConfig   `json:"webhook_configs,omitempty"`
+}
+
+type alertmanagerPagerdutyConfig struct {
+	RoutingKey   string `json:"routing_key"`
+	SendResolved bool   `json:"send_resolved"`
+}
+
+type alertmanagerWebhookConfig struct {
+	URL          string `json:"url"`
+	SendResolved bool   `json:"send_resolved"`
+}
+
+// GenerateAlertmanagerConfig returns an Alertmanager configuration routing
+// all alerts to a receiver sending them, and their resolution, to the
+// PagerDuty service whose integration key is pdIntegrationKey. The
+// configuration is JSON, which Alertmanager reads as YAML.
+func GenerateAlertmanagerConfig(pdi *pagerdutyv1alpha1.PagerDutyIntegration, pdIntegrationKey string) ([]byte, error) {
+	return GenerateAlertmanagerConfigWithHeartbeat(pdi, pdIntegrationKey, "")
+}
+
+// GenerateAlertmanagerConfigWithHeartbeat returns the configuration of
+// GenerateAlertmanagerConfig, in which the notifications of the always
+// firing Watchdog alert also check in at heartbeatURL, once per half
+// spec.heartbeat.interval. An empty heartbeatURL, or no spec.heartbeat,
+// leaves the check-ins out.
+func GenerateAlertmanagerConfigWithHeartbeat(pdi *pagerdutyv1alpha1.PagerDutyIntegration, pdIntegrationKey string, heartbeatURL string) ([]byte, error) {
+	receiver := config.AlertmanagerDefaultReceiver
+	if pdi.Spec.AlertmanagerConfig != nil && pdi.Spec.AlertmanagerConfig.Receiver != "" {
+		receiver = pdi.Spec.AlertmanagerConfig.Receiver
+	}
+
+	amConfig := alertmanagerConfig{
+		Route: alertmanagerRoute{Receiver: receiver},
+		Receivers: []alertmanagerReceiver{
+			{
+				Name: receiver,
+				PagerdutyConfigs: []alertmanagerPagerdutyConfig{
+					{
+						RoutingKey:   pdIntegrationKey,
+						SendResolved: true,
+					},
+				},
+			},
+		},
+	}
+
+	if pdi.Spec.Heartbeat != nil && heartbeatURL != "" {
+		checkIn := fmt.Sprintf("%ds", int64(heartbeat.Interval(pdi.Spec.Heartbeat).Seconds()/2))
+		amConfig.Route.Routes = []alertmanagerRoute{
+			{
+				Receiver:       config.AlertmanagerHeartbeatReceiver,
+				Match:          map[string]string{"alertname": config.AlertmanagerHeartbeatAlert},
+				GroupWait:      "0s",
+				GroupInterval:  checkIn,
+				RepeatInterval: checkIn,
+			},
+		}
+		amConfig.Receivers = append(amConfig.Receivers, alertmanagerReceiver{
+			Name: config.AlertmanagerHeartbeatReceiver,
+			WebhookConfigs: []alertmanagerWebhookConfig{
+				{
+					URL:          heartbeatURL,
+					SendResolved: false,
+				},
+			},
+		})
+	}
+
+	return json.MarshalIndent(amConfig, "", "  ")
+}
+
+// GenerateAlertmanagerSyncSet returns a syncset delivering the Alertmanager
+// configuration of GenerateAlertmanagerConfig to the Secret or ConfigMap
+// spec.alertmanagerConfig names in the target cluster. The configuration is
+// embedded in the syncset, integration key included.
+func GenerateAlertmanagerSyncSet(namespace string, name string, clusterDeploymentName string, pdIntegrationKey string, pdi *pagerdutyv1alpha1.PagerDutyIntegration) (*hivev1.SyncSet, error) {
+	return GenerateAlertmanagerSyncSetWithHeartbeat(namespace, name, clusterDeploymentName, pdIntegrationKey, "", pdi)
+}
+
+// GenerateAlertmanagerSyncSetWithHeartbeat returns the syncset of
+// GenerateAlertmanagerSyncSet delivering the configuration of
+// GenerateAlertmanagerConfigWithHeartbeat, checking in at heartbeatURL.
+func GenerateAlertmanagerSyncSetWithHeartbeat(namespace string, name string, clusterDeploymentName string, pdIntegrationKey string, heartbeatURL string, pdi *pagerdutyv1alpha1.PagerDutyIntegration) (*hivev1.SyncSet, error) {
+	alertmanager := pdi.Spec.AlertmanagerConfig
+
+	data, err := GenerateAlertmanagerConfigWithHeartbeat(pdi, pdIntegrationKey, heartbeatURL)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := metav1.ObjectMeta{
+		Name:      alertmanager.Name,
+		Namespace: alertmanager.Namespace,
+	}
+
+	var obj runtime.Object
+	if alertmanager.Kind == pagerdutyv1alpha1.AlertmanagerConfigKindConfigMap {
+		obj = &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: meta,
+			Data: map[string]string{
+				config.AlertmanagerConfigKey: string(data),
+			},
+		}
+	} else {
+		obj = &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: meta,
+			Type:       corev1.SecretTypeOpaque,
+			Data: map[string][]byte{
+				config.AlertmanagerConfigKey: data,
+			},
+		}
+	}
+
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hivev1.SyncSet{
+		TypeMeta: syncSetTypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: hivev1.SyncSetSpec{
+			ClusterDeploymentRefs: []corev1.LocalObjectReference{
+				{
+					Name: clusterDeploymentName,
+				},
+			},
+			SyncSetCommonSpec: hivev1.SyncSetCommonSpec{
+				ResourceApplyMode: "Sync",
+				Resources:         []runtime.RawExtension{{Raw: raw}},
+			},
+		},
+	}, nil
+}
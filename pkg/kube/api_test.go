@@ -0,0 +1,237 @@
+package kube_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/kube"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// The stable API of the package, see doc.go. A change breaking any of these
+// breaks the operators embedding the package and fails to compile here.
+var (
+	_ func(string, string, string, string) *corev1.ConfigMap                                                              = kube.GenerateConfigMap
+	_ func(string, string, string, *pagerdutyv1alpha1.PagerDutyIntegration) *corev1.Secret                                = kube.GeneratePdSecret
+	_ func(string, string, string, *corev1.Secret, *pagerdutyv1alpha1.PagerDutyIntegration) *hivev1.SyncSet               = kube.GenerateSyncSet
+	_ func(string, string, string, string, *pagerdutyv1alpha1.PagerDutyIntegration) (*hivev1.SyncSet, error)              = kube.GenerateProbeSyncSet
+	_ func(*pagerdutyv1alpha1.PagerDutyIntegration, ...string) string                                                     = kube.TargetSecretName
+	_ func(*pagerdutyv1alpha1.PagerDutyIntegration) []corev1.SecretReference                                              = kube.TargetSecretRefs
+	_ func(*pagerdutyv1alpha1.PagerDutyIntegration, *corev1.Secret) []string                                              = kube.IntegrationKeys
+	_ func(*pagerdutyv1alpha1.PagerDutyIntegration) string                                                                = kube.ProbeName
+	_ func(*pagerdutyv1alpha1.PagerDutyIntegration, string) ([]byte, error)                                               = kube.GenerateAlertmanagerConfig
+	_ func(string, string, string, string, *pagerdutyv1alpha1.PagerDutyIntegration) (*hivev1.SyncSet, error)              = kube.GenerateAlertmanagerSyncSet
+	_ func(*pagerdutyv1alpha1.PagerDutyIntegration, string, string) ([]byte, error)                                       = kube.GenerateAlertmanagerConfigWithHeartbeat
+	_ func(string, string, string, string, string, *pagerdutyv1alpha1.PagerDutyIntegration) (*hivev1.SyncSet, error)      = kube.GenerateAlertmanagerSyncSetWithHeartbeat
+	_ func(string, *pagerdutyv1alpha1.PagerDutyIntegration) *hivev1.SelectorSyncSet                                       = kube.GenerateSelectorSyncSet
+	_ func(a, b []runtime.RawExtension) bool                                                                              = kube.ProbeResourcesEqual
+	_ func(*pagerdutyv1alpha1.PagerDutyIntegration, string, string, string, string, string) (*kube.ClusterObjects, error) = kube.GenerateClusterObjects
+	_ func(runtime.Object) ([]byte, error)                                                                                = kube.Marshal
+	_ func(metav1.Object, *pagerdutyv1alpha1.PagerDutyIntegration)                                                        = kube.SetOwnerLabel
+)
+
+func TestGenerateConfigMap(t *testing.T) {
+	cm := kube.GenerateConfigMap("ns", "name-pd-config", "SVC1", "INT1")
+
+	// the keys other operators read
+	if cm.Data["SERVICE_ID"] != "SVC1" || cm.Data["INTEGRATION_ID"] != "INT1" {
+		t.Errorf("unexpected ConfigMap data %v", cm.Data)
+	}
+}
+
+func TestGenerateAlertmanagerSyncSet(t *testing.T) {
+	pdi := &pagerdutyv1alpha1.PagerDutyIntegration{
+		Spec: pagerdutyv1alpha1.PagerDutyIntegrationSpec{
+			AlertmanagerConfig: &pagerdutyv1alpha1.AlertmanagerConfig{
+				Name:      "alertmanager-pd",
+				Namespace: "openshift-monitoring",
+				Kind:      pagerdutyv1alpha1.AlertmanagerConfigKindConfigMap,
+				Receiver:  "pd",
+			},
+		},
+	}
+
+	ss, err := kube.GenerateAlertmanagerSyncSet("ns", "name-pd-alertmanager", "name", "INTKEY", pdi)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(ss.Spec.Resources) != 1 {
+		t.Fatalf("expected 1 resource, got %d", len(ss.Spec.Resources))
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := json.Unmarshal(ss.Spec.Resources[0].Raw, cm); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if cm.Kind != "ConfigMap" || cm.Name != "alertmanager-pd" || cm.Namespace != "openshift-monitoring" {
+		t.Errorf("unexpected object %v", cm.ObjectMeta)
+	}
+
+	// the receiver the route sends to must exist and hold the key
+	var amConfig struct {
+		Route struct {
+			Receiver string `json:"receiver"`
+		} `json:"route"`
+		Receivers []struct {
+			Name             string `json:"name"`
+			PagerdutyConfigs []struct {
+				RoutingKey string `json:"routing_key"`
+			} `json:"pagerduty_configs"`
+		} `json:"receivers"`
+	}
+	if err := json.Unmarshal([]byte(cm.Data["alertmanager.yaml"]), &amConfig); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if amConfig.Route.Receiver != "pd" || len(amConfig.Receivers) != 1 || amConfig.Receivers[0].Name != "pd" ||
+		len(amConfig.Receivers[0].PagerdutyConfigs) != 1 || amConfig.Receivers[0].PagerdutyConfigs[0].RoutingKey != "INTKEY" {
+		t.Errorf("unexpected Alertmanager config %s", cm.Data["alertmanager.yaml"])
+	}
+}
+
+func TestGenerateAlertmanagerConfigWithHeartbeat(t *testing.T) {
+	pdi := &pagerdutyv1alpha1.PagerDutyIntegration{
+		Spec: pagerdutyv1alpha1.PagerDutyIntegrationSpec{
+			AlertmanagerConfig: &pagerdutyv1alpha1.AlertmanagerConfig{
+				Name:      "alertmanager-pd",
+				Namespace: "openshift-monitoring",
+			},
+			Heartbeat: &pagerdutyv1alpha1.Heartbeat{
+				URL:      "https://heartbeat.example.com",
+				Interval: metav1.Duration{Duration: 10 * time.Minute},
+			},
+		},
+	}
+
+	data, err := kube.GenerateAlertmanagerConfigWithHeartbeat(pdi, "INTKEY", "https://heartbeat.example.com/heartbeat/ns/name/TOKEN")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// the Watchdog alert checks in twice per interval, everything else pages
+	var amConfig struct {
+		Route struct {
+			Receiver string `json:"receiver"`
+			Routes   []struct {
+				Receiver       string            `json:"receiver"`
+				Match          map[string]string `json:"match"`
+				RepeatInterval string            `json:"repeat_interval"`
+			} `json:"routes"`
+		} `json:"route"`
+		Receivers []struct {
+			Name           string `json:"name"`
+			WebhookConfigs []struct {
+				URL string `json:"url"`
+			} `json:"webhook_configs"`
+		} `json:"receivers"`
+	}
+	if err := json.Unmarshal(data, &amConfig); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if amConfig.Route.Receiver != "pagerduty" || len(amConfig.Route.Routes) != 1 ||
+		amConfig.Route.Routes[0].Receiver != "pagerduty-operator-heartbeat" ||
+		amConfig.Route.Routes[0].Match["alertname"] != "Watchdog" || amConfig.Route.Routes[0].RepeatInterval != "300s" {
+		t.Errorf("unexpected Alertmanager route %s", data)
+	}
+	if len(amConfig.Receivers) != 2 || amConfig.Receivers[1].Name != "pagerduty-operator-heartbeat" ||
+		len(amConfig.Receivers[1].WebhookConfigs) != 1 || amConfig.Receivers[1].WebhookConfigs[0].URL != "https://heartbeat.example.com/heartbeat/ns/name/TOKEN" {
+		t.Errorf("unexpected Alertmanager receivers %s", data)
+	}
+
+	// without a URL the configuration is the one without heartbeat
+	withoutURL, err := kube.GenerateAlertmanagerConfigWithHeartbeat(pdi, "INTKEY", "")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected, err := kube.GenerateAlertmanagerConfig(pdi, "INTKEY")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if string(withoutURL) != string(expected) {
+		t.Errorf("unexpected Alertmanager config %s", withoutURL)
+	}
+}
+
+func TestGenerateSelectorSyncSet(t *testing.T) {
+	pdi := &pagerdutyv1alpha1.PagerDutyIntegration{
+		Spec: pagerdutyv1alpha1.PagerDutyIntegrationSpec{
+			ClusterDeploymentSelector: metav1.LabelSelector{MatchLabels: map[string]string{"api.openshift.com/managed": "true"}},
+			TargetSecretRef:           corev1.SecretReference{Name: "pd-secret", Namespace: "openshift-monitoring"},
+			SharedIntegrationKey: &pagerdutyv1alpha1.SharedIntegrationKey{
+				IntegrationKeySecretRef: corev1.SecretReference{Name: "shared-key", Namespace: "pagerduty-operator"},
+			},
+		},
+	}
+
+	sss := kube.GenerateSelectorSyncSet("osd-pd-secret", pdi)
+
+	if sss.Spec.ClusterDeploymentSelector.MatchLabels["api.openshift.com/managed"] != "true" {
+		t.Errorf("unexpected selector %v", sss.Spec.ClusterDeploymentSelector)
+	}
+	if len(sss.Spec.Secrets) != 1 ||
+		sss.Spec.Secrets[0].SourceRef != (hivev1.SecretReference{Name: "shared-key", Namespace: "pagerduty-operator"}) ||
+		sss.Spec.Secrets[0].TargetRef != (hivev1.SecretReference{Name: "pd-secret", Namespace: "openshift-monitoring"}) {
+		t.Errorf("unexpected secrets %v", sss.Spec.Secrets)
+	}
+}
+
+func TestGenerateSyncSetAdditionalTargets(t *testing.T) {
+	pdi := &pagerdutyv1alpha1.PagerDutyIntegration{
+		Spec: pagerdutyv1alpha1.PagerDutyIntegrationSpec{
+			TargetSecretRef: corev1.SecretReference{Name: "pd-secret", Namespace: "openshift-monitoring"},
+			AdditionalTargetSecretRefs: []corev1.SecretReference{
+				{Name: "pd-secret", Namespace: "customer"},
+				// listed twice, synced once
+				{Name: "pd-secret", Namespace: "openshift-monitoring"},
+				{Name: "uwm-pd-secret", Namespace: "openshift-user-workload-monitoring"},
+			},
+			ImmutableSecret: true,
+		},
+	}
+	secret := kube.GeneratePdSecret("cluster-namespace", "osd-cluster-pd-secret", "integration-key", pdi)
+
+	ss := kube.GenerateSyncSet("cluster-namespace", "osd-cluster-pd-sync", "cluster", secret, pdi)
+
+	// every target is named after the key, like TargetSecretRef
+	suffix := strings.TrimPrefix(kube.TargetSecretName(pdi, "integration-key"), "pd-secret")
+	expected := []hivev1.SecretReference{
+		{Name: "pd-secret" + suffix, Namespace: "openshift-monitoring"},
+		{Name: "pd-secret" + suffix, Namespace: "customer"},
+		{Name: "uwm-pd-secret" + suffix, Namespace: "openshift-user-workload-monitoring"},
+	}
+	if len(ss.Spec.Secrets) != len(expected) {
+		t.Fatalf("unexpected secrets %v", ss.Spec.Secrets)
+	}
+	for i, mapping := range ss.Spec.Secrets {
+		if mapping.SourceRef != (hivev1.SecretReference{Name: "osd-cluster-pd-secret", Namespace: "cluster-namespace"}) ||
+			mapping.TargetRef != expected[i] {
+			t.Errorf("unexpected secret %d %v", i, mapping)
+		}
+	}
+
+	pdi.Spec.SecretDeliveryMode = pagerdutyv1alpha1.SecretDeliveryModePatch
+	ss = kube.GenerateSyncSet("cluster-namespace", "osd-cluster-pd-sync", "cluster", secret, pdi)
+	if len(ss.Spec.Patches) != len(expected) {
+		t.Fatalf("unexpected patches %v", ss.Spec.Patches)
+	}
+	for i, patch := range ss.Spec.Patches {
+		// patched secrets keep their name
+		if patch.Name != strings.TrimSuffix(expected[i].Name, suffix) || patch.Namespace != expected[i].Namespace {
+			t.Errorf("unexpected patch %d %v", i, patch)
+		}
+	}
+
+	pdi.Spec.SharedIntegrationKey = &pagerdutyv1alpha1.SharedIntegrationKey{
+		IntegrationKeySecretRef: corev1.SecretReference{Name: "shared-key", Namespace: "pagerduty-operator"},
+	}
+	sss := kube.GenerateSelectorSyncSet("osd-pd-secret", pdi)
+	if len(sss.Spec.Secrets) != len(expected) ||
+		sss.Spec.Secrets[2].TargetRef != (hivev1.SecretReference{Name: "uwm-pd-secret", Namespace: "openshift-user-workload-monitoring"}) {
+		t.Errorf("unexpected selectorsyncset secrets %v", sss.Spec.Secrets)
+	}
+}
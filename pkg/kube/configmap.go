@@ -22,6 +22,10 @@ import (
 // GenerateConfigMap returns a configmap that can be created with the oc client
 func GenerateConfigMap(namespace string, cmName string, pdServiceID string, pdIntegrationID string) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cmName,
 			Namespace: namespace,
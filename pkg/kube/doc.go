@@ -0,0 +1,30 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kube generates the Kubernetes and Hive objects that deliver the
+// PagerDuty integration key of a cluster: the ConfigMap recording the
+// service, the Secret holding the key, and the SyncSets copying them to the
+// cluster, along with the optional delivery probe and Alertmanager
+// configuration. Other operators embed it, so its exported functions are a
+// library API: within a major version of the operator they are not
+// removed, renamed or given new parameters, and the objects they generate
+// keep their names and keys. New behavior is driven by new fields of the
+// PagerDutyIntegration passed in. api_test.go guards against accidental
+// breaking changes.
+//
+// GenerateClusterObjects returns all the objects the operator creates for a
+// cluster, and Marshal renders them byte for byte the same on each call, so
+// tooling can preview what the operator would create, for example to diff a
+// change against a live hub. The golden files in testdata show the output.
+package kube
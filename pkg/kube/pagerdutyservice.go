@@ -0,0 +1,42 @@
+// Copyright 2019 RedHat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GeneratePagerDutyService returns the PagerDutyService recording the
+// PagerDuty service the given PagerDutyIntegration created for a cluster
+func GeneratePagerDutyService(namespace string, name string, pdiName string, clusterDeploymentName string, pdServiceID string, pdIntegrationID string) *pagerdutyv1alpha1.PagerDutyService {
+	return &pagerdutyv1alpha1.PagerDutyService{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PagerDutyService",
+			APIVersion: pagerdutyv1alpha1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: pagerdutyv1alpha1.PagerDutyServiceSpec{
+			ClusterDeploymentRef: corev1.LocalObjectReference{Name: clusterDeploymentName},
+			PagerDutyIntegration: pdiName,
+			ServiceID:            pdServiceID,
+			IntegrationID:        pdIntegrationID,
+		},
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Results the delivery probe sets in config.DeliveryProbeResultLabel
+const (
+	ProbeResultSuccess       = "Success"
+	ProbeResultSecretMissing = "SecretMissing"
+	ProbeResultUnreachable   = "Unreachable"
+)
+
+// probeScript checks the integration key was delivered and PagerDuty can be
+// reached, then labels the probe's own CronJob with the result for the hub
+// to read.
+const probeScript = `if [ -z "$%[1]s" ]; then
+  result=%[2]s
+elif curl -s -o /dev/null --max-time 30 https://events.pagerduty.com/; then
+  result=%[3]s
+else
+  result=%[4]s
+fi
+oc label cronjob %[5]s %[6]s=$result --overwrite
+`
+
+// ProbeName returns the name of the delivery probe's CronJob and of the
+// ServiceAccount, Role and RoleBinding it runs with in the target cluster.
+func ProbeName(pdi *pagerdutyv1alpha1.PagerDutyIntegration) string {
+	return pdi.Spec.TargetSecretRef.Name + "-probe"
+}
+
+// GenerateProbeSyncSet returns a syncset delivering the delivery probe to
+// the namespace of TargetSecretRef in the target cluster, checking the
+// secret named targetSecretName
+func GenerateProbeSyncSet(namespace string, name string, clusterDeploymentName string, targetSecretName string, pdi *pagerdutyv1alpha1.PagerDutyIntegration) (*hivev1.SyncSet, error) {
+	probeName := ProbeName(pdi)
+	probeNamespace := pdi.Spec.TargetSecretRef.Namespace
+
+	schedule := pdi.Spec.DeliveryProbe.Schedule
+	if schedule == "" {
+		schedule = config.DeliveryProbeDefaultSchedule
+	}
+
+	meta := metav1.ObjectMeta{
+		Name:      probeName,
+		Namespace: probeNamespace,
+	}
+
+	serviceAccount := &corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+		ObjectMeta: meta,
+	}
+
+	role := &rbacv1.Role{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"},
+		ObjectMeta: meta,
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{"batch"},
+				Resources:     []string{"cronjobs"},
+				ResourceNames: []string{probeName},
+				Verbs:         []string{"get", "patch"},
+			},
+		},
+	}
+
+	roleBinding := &rbacv1.RoleBinding{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "RoleBinding"},
+		ObjectMeta: meta,
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     probeName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      probeName,
+				Namespace: probeNamespace,
+			},
+		},
+	}
+
+	optional := true
+	cronJob := &batchv1beta1.CronJob{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1beta1", Kind: "CronJob"},
+		ObjectMeta: meta,
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:          schedule,
+			ConcurrencyPolicy: batchv1beta1.ForbidConcurrent,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							ServiceAccountName: probeName,
+							RestartPolicy:      corev1.RestartPolicyNever,
+							Containers: []corev1.Container{
+								{
+									Name:    "probe",
+									Image:   pdi.Spec.DeliveryProbe.Image,
+									Command: []string{"/bin/sh", "-c", fmt.Sprintf(probeScript, config.PagerDutySecretKey, ProbeResultSecretMissing, ProbeResultSuccess, ProbeResultUnreachable, probeName, config.DeliveryProbeResultLabel)},
+									Env: []corev1.EnvVar{
+										{
+											Name: config.PagerDutySecretKey,
+											ValueFrom: &corev1.EnvVarSource{
+												SecretKeyRef: &corev1.SecretKeySelector{
+													LocalObjectReference: corev1.LocalObjectReference{Name: targetSecretName},
+													Key:                  config.PagerDutySecretKey,
+													Optional:             &optional,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	resources := []runtime.RawExtension{}
+	for _, obj := range []runtime.Object{serviceAccount, role, roleBinding, cronJob} {
+		raw, err := json.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, runtime.RawExtension{Raw: raw})
+	}
+
+	return &hivev1.SyncSet{
+		TypeMeta: syncSetTypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: hivev1.SyncSetSpec{
+			ClusterDeploymentRefs: []corev1.LocalObjectReference{
+				{
+					Name: clusterDeploymentName,
+				},
+			},
+			SyncSetCommonSpec: hivev1.SyncSetCommonSpec{
+				ResourceApplyMode: "Sync",
+				Resources:         resources,
+			},
+		},
+	}, nil
+}
+
+// ProbeResourcesEqual returns true if the resources of two syncsets, such as
+// the probe syncsets, describe the same objects. The API server may reformat
+// the raw JSON, so the resources are compared decoded.
+func ProbeResourcesEqual(a, b []runtime.RawExtension) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		var objA, objB interface{}
+		if json.Unmarshal(a[i].Raw, &objA) != nil || json.Unmarshal(b[i].Raw, &objB) != nil {
+			return false
+		}
+		if !reflect.DeepEqual(objA, objB) {
+			return false
+		}
+	}
+	return true
+}
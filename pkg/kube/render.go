@@ -0,0 +1,106 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"encoding/json"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/naming"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ClusterObjects are the objects the operator creates on the hub for the
+// PagerDuty service of one cluster
+type ClusterObjects struct {
+	ConfigMap        *corev1.ConfigMap
+	PagerDutyService *pagerdutyv1alpha1.PagerDutyService
+	Secret           *corev1.Secret
+	SyncSet          *hivev1.SyncSet
+	// ProbeSyncSet is nil unless spec.deliveryProbe is set
+	ProbeSyncSet *hivev1.SyncSet
+	// AlertmanagerSyncSet is nil unless spec.alertmanagerConfig is set
+	AlertmanagerSyncSet *hivev1.SyncSet
+}
+
+// GenerateClusterObjects returns the objects the operator creates for pdi
+// in namespace for the ClusterDeployment clusterDeploymentName, once its
+// PagerDuty service serviceID has the integration integrationID whose key is
+// pdIntegrationKey. Tooling can compare them to the live objects of a hub to
+// preview what a change of pdi would do. The owner reference to the
+// ClusterDeployment, which needs its UID, is left out, and so are the
+// check-ins of spec.heartbeat, whose URL holds a random token.
+func GenerateClusterObjects(pdi *pagerdutyv1alpha1.PagerDutyIntegration, namespace string, clusterDeploymentName string, serviceID string, integrationID string, pdIntegrationKey string) (*ClusterObjects, error) {
+	objects := &ClusterObjects{
+		ConfigMap:        GenerateConfigMap(namespace, naming.ConfigMapName(pdi.Spec.ServicePrefix, clusterDeploymentName), serviceID, integrationID),
+		PagerDutyService: GeneratePagerDutyService(namespace, naming.ConfigMapName(pdi.Spec.ServicePrefix, clusterDeploymentName), pdi.Name, clusterDeploymentName, serviceID, integrationID),
+		Secret:           GeneratePdSecret(namespace, naming.SecretName(pdi.Spec.ServicePrefix, clusterDeploymentName), pdIntegrationKey, pdi),
+	}
+	objects.SyncSet = GenerateSyncSet(namespace, naming.SyncSetName(pdi.Spec.ServicePrefix, clusterDeploymentName), clusterDeploymentName, objects.Secret, pdi)
+
+	if pdi.Spec.DeliveryProbe != nil {
+		targetSecretName := TargetSecretName(pdi, IntegrationKeys(pdi, objects.Secret)...)
+		probe, err := GenerateProbeSyncSet(namespace, naming.ProbeSyncSetName(pdi.Spec.ServicePrefix, clusterDeploymentName), clusterDeploymentName, targetSecretName, pdi)
+		if err != nil {
+			return nil, err
+		}
+		objects.ProbeSyncSet = probe
+	}
+
+	if pdi.Spec.AlertmanagerConfig != nil {
+		alertmanager, err := GenerateAlertmanagerSyncSet(namespace, naming.AlertmanagerSyncSetName(pdi.Spec.ServicePrefix, clusterDeploymentName), clusterDeploymentName, pdIntegrationKey, pdi)
+		if err != nil {
+			return nil, err
+		}
+		objects.AlertmanagerSyncSet = alertmanager
+	}
+
+	for _, obj := range objects.List() {
+		SetOwnerLabel(obj.(metav1.Object), pdi)
+	}
+	return objects, nil
+}
+
+// List returns the objects in the order the operator creates them
+func (o *ClusterObjects) List() []runtime.Object {
+	objects := []runtime.Object{o.ConfigMap, o.PagerDutyService, o.Secret}
+	if o.ProbeSyncSet != nil {
+		objects = append(objects, o.ProbeSyncSet)
+	}
+	if o.AlertmanagerSyncSet != nil {
+		objects = append(objects, o.AlertmanagerSyncSet)
+	}
+	return append(objects, o.SyncSet)
+}
+
+// Marshal returns the JSON of obj, indented. The same object always gives
+// the same bytes: fields come in a fixed order and map keys are sorted.
+func Marshal(obj runtime.Object) ([]byte, error) {
+	return json.MarshalIndent(obj, "", "  ")
+}
+
+// SetOwnerLabel marks obj as managed by the given PagerDutyIntegration
+func SetOwnerLabel(obj metav1.Object, pdi *pagerdutyv1alpha1.PagerDutyIntegration) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[config.PagerDutyIntegrationLabel] = pdi.Name
+	obj.SetLabels(labels)
+}
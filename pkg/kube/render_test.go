@@ -0,0 +1,129 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube_test
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/kube"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// update rewrites the golden files: go test ./pkg/kube -update
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func testPagerDutyIntegration() *pagerdutyv1alpha1.PagerDutyIntegration {
+	return &pagerdutyv1alpha1.PagerDutyIntegration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "osd",
+			Namespace: "pagerduty-operator",
+		},
+		Spec: pagerdutyv1alpha1.PagerDutyIntegrationSpec{
+			ServicePrefix: "osd",
+			TargetSecretRef: corev1.SecretReference{
+				Name:      "pd-secret",
+				Namespace: "openshift-monitoring",
+			},
+		},
+	}
+}
+
+func TestGenerateClusterObjectsGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		golden string
+		modify func(*pagerdutyv1alpha1.PagerDutyIntegration)
+	}{
+		{
+			name:   "Sync",
+			golden: "sync.json",
+			modify: func(*pagerdutyv1alpha1.PagerDutyIntegration) {},
+		},
+		{
+			name:   "Patch",
+			golden: "patch.json",
+			modify: func(pdi *pagerdutyv1alpha1.PagerDutyIntegration) {
+				pdi.Spec.SecretDeliveryMode = pagerdutyv1alpha1.SecretDeliveryModePatch
+			},
+		},
+		{
+			name:   "Immutable Secret With Delivery Probe",
+			golden: "immutable-probe.json",
+			modify: func(pdi *pagerdutyv1alpha1.PagerDutyIntegration) {
+				pdi.Spec.ImmutableSecret = true
+				pdi.Spec.DeliveryProbe = &pagerdutyv1alpha1.DeliveryProbe{Image: "quay.io/openshift/origin-cli:latest"}
+			},
+		},
+		{
+			name:   "Alertmanager Config",
+			golden: "alertmanager.json",
+			modify: func(pdi *pagerdutyv1alpha1.PagerDutyIntegration) {
+				pdi.Spec.AlertmanagerConfig = &pagerdutyv1alpha1.AlertmanagerConfig{
+					Name:      "alertmanager-main",
+					Namespace: "openshift-monitoring",
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pdi := testPagerDutyIntegration()
+			test.modify(pdi)
+
+			var rendered []byte
+			// the output must not vary from one call to the next
+			for i := 0; i < 2; i++ {
+				objects, err := kube.GenerateClusterObjects(pdi, "uhc-production-1234", "my-cluster", "PSVC123", "PINT456", "integration-key")
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				var out bytes.Buffer
+				for _, obj := range objects.List() {
+					raw, err := kube.Marshal(obj)
+					if err != nil {
+						t.Fatalf("unexpected error %v", err)
+					}
+					out.Write(raw)
+					out.WriteString("\n")
+				}
+				if rendered != nil && !bytes.Equal(rendered, out.Bytes()) {
+					t.Fatal("rendering the same objects twice gave different output")
+				}
+				rendered = out.Bytes()
+			}
+
+			golden := filepath.Join("testdata", test.golden)
+			if *update {
+				if err := ioutil.WriteFile(golden, rendered, 0644); err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+			}
+			expected, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !bytes.Equal(expected, rendered) {
+				t.Errorf("objects differ from %s, run go test ./pkg/kube -update if the change is intended:\n%s", golden, rendered)
+			}
+		})
+	}
+}
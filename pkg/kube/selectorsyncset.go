@@ -0,0 +1,63 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// selectorSyncSetTypeMeta is set on the generated selectorsyncsets so they
+// render complete
+var selectorSyncSetTypeMeta = metav1.TypeMeta{
+	Kind:       "SelectorSyncSet",
+	APIVersion: hivev1.SchemeGroupVersion.String(),
+}
+
+// GenerateSelectorSyncSet returns a selectorsyncset syncing the secret of
+// spec.sharedIntegrationKey to the TargetSecretRefs in every cluster the
+// PagerDutyIntegration selects
+func GenerateSelectorSyncSet(name string, pdi *pagerdutyv1alpha1.PagerDutyIntegration) *hivev1.SelectorSyncSet {
+	source := pdi.Spec.SharedIntegrationKey.IntegrationKeySecretRef
+
+	mappings := []hivev1.SecretMapping{}
+	for _, ref := range TargetSecretRefs(pdi) {
+		mappings = append(mappings, hivev1.SecretMapping{
+			SourceRef: hivev1.SecretReference{
+				Namespace: source.Namespace,
+				Name:      source.Name,
+			},
+			TargetRef: hivev1.SecretReference{
+				Namespace: ref.Namespace,
+				Name:      ref.Name,
+			},
+		})
+	}
+
+	return &hivev1.SelectorSyncSet{
+		TypeMeta: selectorSyncSetTypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: hivev1.SelectorSyncSetSpec{
+			SyncSetCommonSpec: hivev1.SyncSetCommonSpec{
+				ResourceApplyMode: "Sync",
+				Secrets:           mappings,
+			},
+			ClusterDeploymentSelector: pdi.Spec.ClusterDeploymentSelector,
+		},
+	}
+}
@@ -15,6 +15,13 @@
 package kube
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -23,11 +30,23 @@ import (
 	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
 )
 
-// GenerateSyncSet returns a syncset that can be created with the oc client
-func GenerateSyncSet(namespace string, clusterDeploymentName string, secret *corev1.Secret, pdi *pagerdutyv1alpha1.PagerDutyIntegration) *hivev1.SyncSet {
+// syncSetTypeMeta is set on the generated syncsets so they render complete
+var syncSetTypeMeta = metav1.TypeMeta{
+	Kind:       "SyncSet",
+	APIVersion: hivev1.SchemeGroupVersion.String(),
+}
+
+// GenerateSyncSet returns a syncset that can be created with the oc client.
+// The same arguments always give the same syncset, see Marshal.
+func GenerateSyncSet(namespace string, name string, clusterDeploymentName string, secret *corev1.Secret, pdi *pagerdutyv1alpha1.PagerDutyIntegration) *hivev1.SyncSet {
+	if pdi.Spec.SecretDeliveryMode == pagerdutyv1alpha1.SecretDeliveryModePatch {
+		return generatePatchSyncSet(namespace, name, clusterDeploymentName, secret, pdi)
+	}
+
 	return &hivev1.SyncSet{
+		TypeMeta: syncSetTypeMeta,
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      secret.Name,
+			Name:      name,
 			Namespace: namespace,
 		},
 		Spec: hivev1.SyncSetSpec{
@@ -38,27 +57,173 @@ func GenerateSyncSet(namespace string, clusterDeploymentName string, secret *cor
 			},
 			SyncSetCommonSpec: hivev1.SyncSetCommonSpec{
 				ResourceApplyMode: "Sync",
-				Secrets: []hivev1.SecretMapping{
-					{
-						SourceRef: hivev1.SecretReference{
-							Namespace: secret.Namespace,
-							Name:      secret.Name,
-						},
-						TargetRef: hivev1.SecretReference{
-							Namespace: pdi.Spec.TargetSecretRef.Namespace,
-							Name:      pdi.Spec.TargetSecretRef.Name,
-						},
-					},
+				Secrets:           secretMappings(secret, pdi),
+			},
+		},
+	}
+}
+
+// secretMappings returns the mappings syncing secret to each of the
+// TargetSecretRefs of pdi
+func secretMappings(secret *corev1.Secret, pdi *pagerdutyv1alpha1.PagerDutyIntegration) []hivev1.SecretMapping {
+	keys := IntegrationKeys(pdi, secret)
+	mappings := []hivev1.SecretMapping{}
+	for _, ref := range TargetSecretRefs(pdi) {
+		mappings = append(mappings, hivev1.SecretMapping{
+			SourceRef: hivev1.SecretReference{
+				Namespace: secret.Namespace,
+				Name:      secret.Name,
+			},
+			TargetRef: hivev1.SecretReference{
+				Namespace: ref.Namespace,
+				Name:      targetSecretName(pdi, ref.Name, keys),
+			},
+		})
+	}
+	return mappings
+}
+
+// generatePatchSyncSet returns a syncset that merges the integration key
+// into the existing secret in the target cluster instead of syncing secret
+func generatePatchSyncSet(namespace string, name string, clusterDeploymentName string, secret *corev1.Secret, pdi *pagerdutyv1alpha1.PagerDutyIntegration) *hivev1.SyncSet {
+	data := map[string]string{}
+	for key, value := range secret.Data {
+		data[key] = base64.StdEncoding.EncodeToString(value)
+	}
+	// marshalling a map of strings can't fail
+	raw, _ := json.Marshal(map[string]interface{}{"data": data})
+	patch := string(raw)
+
+	patches := []hivev1.SyncObjectPatch{}
+	for _, ref := range TargetSecretRefs(pdi) {
+		patches = append(patches, hivev1.SyncObjectPatch{
+			APIVersion: "v1",
+			Kind:       "Secret",
+			Name:       ref.Name,
+			Namespace:  ref.Namespace,
+			Patch:      patch,
+			PatchType:  "merge",
+		})
+	}
+
+	return &hivev1.SyncSet{
+		TypeMeta: syncSetTypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: hivev1.SyncSetSpec{
+			ClusterDeploymentRefs: []corev1.LocalObjectReference{
+				{
+					Name: clusterDeploymentName,
 				},
 			},
+			SyncSetCommonSpec: hivev1.SyncSetCommonSpec{
+				ResourceApplyMode: "Sync",
+				Patches:           patches,
+			},
 		},
 	}
 }
 
-// GeneratePdSecret returns a secret that can be created with the oc client
-func GeneratePdSecret(namespace string, name string, pdIntegrationKey string) *corev1.Secret {
+// TargetSecretRefs returns TargetSecretRef followed by the
+// AdditionalTargetSecretRefs of pdi, each of them once.
+func TargetSecretRefs(pdi *pagerdutyv1alpha1.PagerDutyIntegration) []corev1.SecretReference {
+	refs := []corev1.SecretReference{pdi.Spec.TargetSecretRef}
+	for _, ref := range pdi.Spec.AdditionalTargetSecretRefs {
+		duplicate := false
+		for _, existing := range refs {
+			if existing == ref {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// TargetSecretName returns the name of the secret holding the integration
+// keys in the target cluster at TargetSecretRef. Immutable secrets are named
+// after the keys, so a new key is delivered in a new secret instead of
+// updating the old one.
+func TargetSecretName(pdi *pagerdutyv1alpha1.PagerDutyIntegration, pdIntegrationKeys ...string) string {
+	return targetSecretName(pdi, pdi.Spec.TargetSecretRef.Name, pdIntegrationKeys)
+}
+
+// targetSecretName returns the name of the secret holding the integration
+// keys in the target cluster at one of the TargetSecretRefs named name
+func targetSecretName(pdi *pagerdutyv1alpha1.PagerDutyIntegration, name string, pdIntegrationKeys []string) string {
+	if !pdi.Spec.ImmutableSecret || pdi.Spec.SecretDeliveryMode == pagerdutyv1alpha1.SecretDeliveryModePatch {
+		return name
+	}
+	sum := sha256.Sum256([]byte(strings.Join(pdIntegrationKeys, "")))
+	return name + "-" + hex.EncodeToString(sum[:])[:8]
+}
+
+// MigrationSecretKey returns the key of the synced secret holding the
+// integration key of the account being migrated to.
+func MigrationSecretKey(pdi *pagerdutyv1alpha1.PagerDutyIntegration) string {
+	if pdi.Spec.AccountMigration != nil && pdi.Spec.AccountMigration.SecretKey != "" {
+		return pdi.Spec.AccountMigration.SecretKey
+	}
+	return config.PagerDutyMigrationSecretKey
+}
+
+// HeartbeatSecretKey returns the key of the synced secret holding the URL
+// the cluster checks in at.
+func HeartbeatSecretKey(pdi *pagerdutyv1alpha1.PagerDutyIntegration) string {
+	if pdi.Spec.Heartbeat != nil && pdi.Spec.Heartbeat.SecretKey != "" {
+		return pdi.Spec.Heartbeat.SecretKey
+	}
+	return config.PagerDutyHeartbeatSecretKey
+}
+
+// IntegrationKeys returns the integration keys held by secret, the one of
+// the account being migrated to last, followed by the URL the cluster
+// checks in at when spec.heartbeat is set, as an immutable secret is
+// replaced when it changes too.
+func IntegrationKeys(pdi *pagerdutyv1alpha1.PagerDutyIntegration, secret *corev1.Secret) []string {
+	keys := []string{string(secret.Data[config.PagerDutySecretKey])}
+	if pdi.Spec.AccountMigration != nil {
+		keys = append(keys, string(secret.Data[MigrationSecretKey(pdi)]))
+	}
+	if pdi.Spec.Heartbeat != nil {
+		keys = append(keys, string(secret.Data[HeartbeatSecretKey(pdi)]))
+	}
+	return keys
+}
+
+// ValidateSecretType returns an error if the synced secret can't be of
+// secretType. The built-in kubernetes.io/ types require keys the secret
+// doesn't hold, so the API server of every cluster would reject it; only
+// Opaque and custom types can hold the integration key alone.
+func ValidateSecretType(secretType corev1.SecretType) error {
+	if strings.HasPrefix(string(secretType), "kubernetes.io/") {
+		return fmt.Errorf("secret type %s requires keys the synced secret doesn't hold, use %s or a custom type", secretType, corev1.SecretTypeOpaque)
+	}
+	return nil
+}
+
+// GeneratePdSecret returns a secret that can be created with the oc client.
+// Hive copies its type and immutability to the target cluster.
+func GeneratePdSecret(namespace string, name string, pdIntegrationKey string, pdi *pagerdutyv1alpha1.PagerDutyIntegration) *corev1.Secret {
+	secretType := corev1.SecretTypeOpaque
+	if pdi.Spec.SecretType != "" {
+		secretType = pdi.Spec.SecretType
+	}
+
+	var immutable *bool
+	if pdi.Spec.ImmutableSecret {
+		immutableSecret := true
+		immutable = &immutableSecret
+	}
+
 	secret := &corev1.Secret{
-		Type: "Opaque",
+		Type:      secretType,
+		Immutable: immutable,
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Secret",
 			APIVersion: "v1",
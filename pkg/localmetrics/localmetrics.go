@@ -19,6 +19,7 @@ import (
 	"net/http"
 	neturl "net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -70,27 +71,128 @@ var (
 		Buckets: []float64{1},
 	}, []string{"controller", "method", "resource", "status"})
 
+	// ApiCallErrors counts the API requests answered with an error status
+	ApiCallErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:        "pagerduty_operator_api_request_errors_total",
+		Help:        "Number of API requests answered with an error status, by endpoint",
+		ConstLabels: prometheus.Labels{"name": operatorName},
+	}, []string{"controller", "method", "resource", "status"})
+
 	MetricPagerDutyIntegrationSecretLoaded = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Name:        "pagerdutyintegration_secret_loaded",
 		Help:        "Metric to track the ability to load the PagerDuty API key from the Secret specified in the PagerDutyIntegration",
 		ConstLabels: prometheus.Labels{"name": "pagerduty-operator"},
 	}, []string{"pagerdutyintegration_name"})
 
+	MetricPagerDutyIntegrationAccountQuotaExceeded = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:        "pagerdutyintegration_account_quota_exceeded",
+		Help:        "Metric set to 1 while the PagerDuty account of the PagerDutyIntegration refuses to create services because it reached its service limit",
+		ConstLabels: prometheus.Labels{"name": "pagerduty-operator"},
+	}, []string{"pagerdutyintegration_name"})
+
+	MetricPagerDutyIntegrationRetryReason = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:        "pagerdutyintegration_cluster_retry_reason",
+		Help:        "Metric to track the number of clusters of the PagerDutyIntegration whose setup was skipped or will be retried, by reason",
+		ConstLabels: prometheus.Labels{"name": "pagerduty-operator"},
+	}, []string{"pagerdutyintegration_name", "reason"})
+
+	MetricPagerDutyIntegrationAnomalousClusters = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:        "pagerdutyintegration_alert_volume_anomalous_clusters",
+		Help:        "Metric to track the number of clusters of the PagerDutyIntegration getting far more incidents than the fleet",
+		ConstLabels: prometheus.Labels{"name": "pagerduty-operator"},
+	}, []string{"pagerdutyintegration_name"})
+
+	MetricPagerDutyIntegrationServices = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:        "pagerdutyintegration_services",
+		Help:        "Metric to track the number of PagerDuty services managed for the clusters of the PagerDutyIntegration",
+		ConstLabels: prometheus.Labels{"name": "pagerduty-operator"},
+	}, []string{"pagerdutyintegration_name"})
+
+	MetricPagerDutyIntegrationOrphanedServices = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:        "pagerdutyintegration_orphaned_services",
+		Help:        "Metric to track the number of PagerDuty services of the PagerDutyIntegration whose cluster no longer exists",
+		ConstLabels: prometheus.Labels{"name": "pagerduty-operator"},
+	}, []string{"pagerdutyintegration_name"})
+
+	MetricPagerDutyIntegrationLeakedServices = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:        "pagerdutyintegration_leaked_services_total",
+		Help:        "Metric to count the PagerDuty services of the PagerDutyIntegration that may be left in PagerDuty, as their cluster was torn down without its ConfigMap and looking them up by name failed",
+		ConstLabels: prometheus.Labels{"name": "pagerduty-operator"},
+	}, []string{"pagerdutyintegration_name"})
+
+	MetricPagerDutyIntegrationOperationSuccessRatio = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:        "pagerdutyintegration_operation_success_ratio",
+		Help:        "Metric to track the share of the per-cluster operations of the PagerDutyIntegration that succeeded over the window of its error budget",
+		ConstLabels: prometheus.Labels{"name": "pagerduty-operator"},
+	}, []string{"pagerdutyintegration_name"})
+
+	MetricPagerDutyIntegrationErrorBudgetRemaining = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:        "pagerdutyintegration_error_budget_remaining",
+		Help:        "Metric to track the share of the error budget of the PagerDutyIntegration left, negative once exhausted",
+		ConstLabels: prometheus.Labels{"name": "pagerduty-operator"},
+	}, []string{"pagerdutyintegration_name"})
+
+	MetricPagerDutyIntegrationFailedClusters = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:        "pagerdutyintegration_failed_clusters",
+		Help:        "Metric to track the number of clusters of the PagerDutyIntegration failing to be set up",
+		ConstLabels: prometheus.Labels{"name": "pagerduty-operator"},
+	}, []string{"pagerdutyintegration_name"})
+
+	MetricPagerDutyIntegrationSecretSyncFailedClusters = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:        "pagerdutyintegration_secret_sync_failed_clusters",
+		Help:        "Metric to track the number of clusters of the PagerDutyIntegration Hive failed to apply the integration key to",
+		ConstLabels: prometheus.Labels{"name": "pagerduty-operator"},
+	}, []string{"pagerdutyintegration_name"})
+
+	MetricPagerDutyClusterServiceInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:        "pagerduty_cluster_service_info",
+		Help:        "Metric set to 1 mapping each cluster to its PagerDuty service and integration, to join alert data with PagerDuty",
+		ConstLabels: prometheus.Labels{"name": "pagerduty-operator"},
+	}, []string{"cluster", "service_id", "integration_id", "pdi"})
+
+	MetricReconcilePausedUntil = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:        "pagerduty_operator_reconcile_paused_until_seconds",
+		Help:        "Metric to track the Unix time reconciles are paused until, 0 when they are not paused",
+		ConstLabels: prometheus.Labels{"name": "pagerduty-operator"},
+	})
+
 	MetricsList = []prometheus.Collector{
 		MetricPagerDutyCreateFailure,
 		MetricPagerDutyDeleteFailure,
 		MetricPagerDutyHeartbeat,
 		ApiCallDuration,
+		ApiCallErrors,
 		ReconcileDuration,
 		MetricPagerDutyIntegrationSecretLoaded,
+		MetricPagerDutyIntegrationAccountQuotaExceeded,
+		MetricPagerDutyIntegrationRetryReason,
+		MetricPagerDutyIntegrationAnomalousClusters,
+		MetricPagerDutyIntegrationServices,
+		MetricPagerDutyIntegrationFailedClusters,
+		MetricPagerDutyIntegrationSecretSyncFailedClusters,
+		MetricPagerDutyIntegrationOrphanedServices,
+		MetricPagerDutyIntegrationLeakedServices,
+		MetricPagerDutyIntegrationOperationSuccessRatio,
+		MetricPagerDutyIntegrationErrorBudgetRemaining,
+		MetricPagerDutyClusterServiceInfo,
+		MetricReconcilePausedUntil,
 	}
 )
 
-// UpdateAPIMetrics updates all API endpoint metrics every 5 minutes
-func UpdateAPIMetrics(APIKey string, timer *prometheus.Timer) {
+// clusterServiceInfo holds the labels of the info metric of each cluster,
+// keyed by PagerDutyIntegration and cluster, so the series of a service the
+// cluster no longer uses can be deleted
+var clusterServiceInfo = struct {
+	sync.Mutex
+	labels map[string]prometheus.Labels
+}{labels: map[string]prometheus.Labels{}}
+
+// UpdateAPIMetrics updates all API endpoint metrics every 5 minutes. The
+// API key is fetched again each time, so a rotated key is picked up.
+func UpdateAPIMetrics(apiKey func() string, timer *prometheus.Timer) {
 	d := time.Tick(5 * time.Minute)
 	for range d {
-		UpdateMetricPagerDutyHeartbeat(APIKey, timer)
+		UpdateMetricPagerDutyHeartbeat(apiKey(), timer)
 	}
 
 }
@@ -114,6 +216,211 @@ func DeleteMetricPagerDutyIntegrationSecretLoaded(pdiName string) bool {
 	)
 }
 
+// UpdateMetricPagerDutyIntegrationAccountQuotaExceeded updates gauge to 1
+// when the PagerDuty account of the PagerDutyIntegration reached its service
+// limit, or to 0 once services can be created again
+func UpdateMetricPagerDutyIntegrationAccountQuotaExceeded(x int, pdiName string) {
+	MetricPagerDutyIntegrationAccountQuotaExceeded.With(
+		prometheus.Labels{"pagerdutyintegration_name": pdiName},
+	).Set(float64(x))
+}
+
+// DeleteMetricPagerDutyIntegrationAccountQuotaExceeded deletes the metric for
+// the PagerDutyIntegration name provided, when the PagerDutyIntegration is
+// being deleted.
+func DeleteMetricPagerDutyIntegrationAccountQuotaExceeded(pdiName string) bool {
+	return MetricPagerDutyIntegrationAccountQuotaExceeded.Delete(
+		prometheus.Labels{"pagerdutyintegration_name": pdiName},
+	)
+}
+
+// UpdateMetricPagerDutyIntegrationRetryReason updates gauge to the number of
+// clusters of the PagerDutyIntegration skipped or retried for reason
+func UpdateMetricPagerDutyIntegrationRetryReason(x int, pdiName string, reason string) {
+	MetricPagerDutyIntegrationRetryReason.With(prometheus.Labels{
+		"pagerdutyintegration_name": pdiName,
+		"reason":                    reason,
+	}).Set(float64(x))
+}
+
+// DeleteMetricPagerDutyIntegrationRetryReason deletes the metric for the
+// PagerDutyIntegration name and reason provided, when the
+// PagerDutyIntegration is being deleted.
+func DeleteMetricPagerDutyIntegrationRetryReason(pdiName string, reason string) bool {
+	return MetricPagerDutyIntegrationRetryReason.Delete(prometheus.Labels{
+		"pagerdutyintegration_name": pdiName,
+		"reason":                    reason,
+	})
+}
+
+// UpdateMetricPagerDutyIntegrationAnomalousClusters updates gauge to the
+// number of clusters of the PagerDutyIntegration whose alert volume is
+// anomalous
+func UpdateMetricPagerDutyIntegrationAnomalousClusters(x int, pdiName string) {
+	MetricPagerDutyIntegrationAnomalousClusters.With(
+		prometheus.Labels{"pagerdutyintegration_name": pdiName},
+	).Set(float64(x))
+}
+
+// DeleteMetricPagerDutyIntegrationAnomalousClusters deletes the metric for
+// the PagerDutyIntegration name provided, when the PagerDutyIntegration is
+// being deleted.
+func DeleteMetricPagerDutyIntegrationAnomalousClusters(pdiName string) bool {
+	return MetricPagerDutyIntegrationAnomalousClusters.Delete(
+		prometheus.Labels{"pagerdutyintegration_name": pdiName},
+	)
+}
+
+// UpdateMetricPagerDutyIntegrationServices updates gauge to the number of
+// PagerDuty services managed for the clusters of the PagerDutyIntegration
+func UpdateMetricPagerDutyIntegrationServices(x int, pdiName string) {
+	MetricPagerDutyIntegrationServices.With(
+		prometheus.Labels{"pagerdutyintegration_name": pdiName},
+	).Set(float64(x))
+}
+
+// DeleteMetricPagerDutyIntegrationServices deletes the metric for the
+// PagerDutyIntegration name provided, when the PagerDutyIntegration is being
+// deleted.
+func DeleteMetricPagerDutyIntegrationServices(pdiName string) bool {
+	return MetricPagerDutyIntegrationServices.Delete(
+		prometheus.Labels{"pagerdutyintegration_name": pdiName},
+	)
+}
+
+// UpdateMetricPagerDutyIntegrationFailedClusters updates gauge to the number
+// of clusters of the PagerDutyIntegration failing to be set up
+func UpdateMetricPagerDutyIntegrationFailedClusters(x int, pdiName string) {
+	MetricPagerDutyIntegrationFailedClusters.With(
+		prometheus.Labels{"pagerdutyintegration_name": pdiName},
+	).Set(float64(x))
+}
+
+// DeleteMetricPagerDutyIntegrationFailedClusters deletes the metric for the
+// PagerDutyIntegration name provided, when the PagerDutyIntegration is being
+// deleted.
+func DeleteMetricPagerDutyIntegrationFailedClusters(pdiName string) bool {
+	return MetricPagerDutyIntegrationFailedClusters.Delete(
+		prometheus.Labels{"pagerdutyintegration_name": pdiName},
+	)
+}
+
+// UpdateMetricPagerDutyIntegrationSecretSyncFailedClusters updates gauge to
+// the number of clusters of the PagerDutyIntegration Hive failed to apply
+// the integration key to
+func UpdateMetricPagerDutyIntegrationSecretSyncFailedClusters(x int, pdiName string) {
+	MetricPagerDutyIntegrationSecretSyncFailedClusters.With(
+		prometheus.Labels{"pagerdutyintegration_name": pdiName},
+	).Set(float64(x))
+}
+
+// DeleteMetricPagerDutyIntegrationSecretSyncFailedClusters deletes the
+// metric for the PagerDutyIntegration name provided, when the
+// PagerDutyIntegration is being deleted.
+func DeleteMetricPagerDutyIntegrationSecretSyncFailedClusters(pdiName string) bool {
+	return MetricPagerDutyIntegrationSecretSyncFailedClusters.Delete(
+		prometheus.Labels{"pagerdutyintegration_name": pdiName},
+	)
+}
+
+// UpdateMetricPagerDutyIntegrationOrphanedServices updates gauge to the
+// number of PagerDuty services of the PagerDutyIntegration whose cluster no
+// longer exists
+func UpdateMetricPagerDutyIntegrationOrphanedServices(x int, pdiName string) {
+	MetricPagerDutyIntegrationOrphanedServices.With(
+		prometheus.Labels{"pagerdutyintegration_name": pdiName},
+	).Set(float64(x))
+}
+
+// DeleteMetricPagerDutyIntegrationOrphanedServices deletes the metric for
+// the PagerDutyIntegration name provided, when the PagerDutyIntegration is
+// being deleted or no longer sweeps for orphaned services.
+func DeleteMetricPagerDutyIntegrationOrphanedServices(pdiName string) bool {
+	return MetricPagerDutyIntegrationOrphanedServices.Delete(
+		prometheus.Labels{"pagerdutyintegration_name": pdiName},
+	)
+}
+
+// IncMetricPagerDutyIntegrationLeakedServices counts a PagerDuty service of
+// the PagerDutyIntegration that may be left in PagerDuty
+func IncMetricPagerDutyIntegrationLeakedServices(pdiName string) {
+	MetricPagerDutyIntegrationLeakedServices.With(
+		prometheus.Labels{"pagerdutyintegration_name": pdiName},
+	).Inc()
+}
+
+// DeleteMetricPagerDutyIntegrationLeakedServices deletes the metric for the
+// PagerDutyIntegration name provided, when the PagerDutyIntegration is
+// being deleted.
+func DeleteMetricPagerDutyIntegrationLeakedServices(pdiName string) bool {
+	return MetricPagerDutyIntegrationLeakedServices.Delete(
+		prometheus.Labels{"pagerdutyintegration_name": pdiName},
+	)
+}
+
+// UpdateMetricPagerDutyIntegrationErrorBudget updates the gauges to the
+// share of the per-cluster operations of the PagerDutyIntegration that
+// succeeded and the share of its error budget left
+func UpdateMetricPagerDutyIntegrationErrorBudget(successRatio float64, remaining float64, pdiName string) {
+	labels := prometheus.Labels{"pagerdutyintegration_name": pdiName}
+	MetricPagerDutyIntegrationOperationSuccessRatio.With(labels).Set(successRatio)
+	MetricPagerDutyIntegrationErrorBudgetRemaining.With(labels).Set(remaining)
+}
+
+// DeleteMetricPagerDutyIntegrationErrorBudget deletes the metrics for the
+// PagerDutyIntegration name provided, when the PagerDutyIntegration is being
+// deleted or no longer accounts an error budget.
+func DeleteMetricPagerDutyIntegrationErrorBudget(pdiName string) {
+	labels := prometheus.Labels{"pagerdutyintegration_name": pdiName}
+	MetricPagerDutyIntegrationOperationSuccessRatio.Delete(labels)
+	MetricPagerDutyIntegrationErrorBudgetRemaining.Delete(labels)
+}
+
+// SetReconcilePausedUntil publishes the time reconciles are paused until,
+// the zero time when they are not paused
+func SetReconcilePausedUntil(until time.Time) {
+	if until.IsZero() {
+		MetricReconcilePausedUntil.Set(0)
+		return
+	}
+	MetricReconcilePausedUntil.Set(float64(until.Unix()))
+}
+
+// UpdateMetricPagerDutyClusterServiceInfo sets the info metric of the
+// cluster to its PagerDuty service and integration, replacing the series of
+// any service it was mapped to before
+func UpdateMetricPagerDutyClusterServiceInfo(cluster string, serviceID string, integrationID string, pdiName string) {
+	labels := prometheus.Labels{
+		"cluster":        cluster,
+		"service_id":     serviceID,
+		"integration_id": integrationID,
+		"pdi":            pdiName,
+	}
+
+	clusterServiceInfo.Lock()
+	defer clusterServiceInfo.Unlock()
+	key := pdiName + "/" + cluster
+	if previous, ok := clusterServiceInfo.labels[key]; ok {
+		MetricPagerDutyClusterServiceInfo.Delete(previous)
+	}
+	clusterServiceInfo.labels[key] = labels
+	MetricPagerDutyClusterServiceInfo.With(labels).Set(1)
+}
+
+// DeleteMetricPagerDutyClusterServiceInfo deletes the info metric of the
+// cluster, when its service is deleted. An empty cluster deletes the info
+// metrics of all the clusters of the PagerDutyIntegration.
+func DeleteMetricPagerDutyClusterServiceInfo(cluster string, pdiName string) {
+	clusterServiceInfo.Lock()
+	defer clusterServiceInfo.Unlock()
+	for key, labels := range clusterServiceInfo.labels {
+		if labels["pdi"] == pdiName && (cluster == "" || labels["cluster"] == cluster) {
+			MetricPagerDutyClusterServiceInfo.Delete(labels)
+			delete(clusterServiceInfo.labels, key)
+		}
+	}
+}
+
 // UpdateMetricPagerDutyCreateFailure updates gauge to 1 when creation fails
 func UpdateMetricPagerDutyCreateFailure(x int, cd string, pdiName string) {
 	MetricPagerDutyCreateFailure.With(prometheus.Labels{
@@ -168,12 +475,16 @@ func UpdateMetricPagerDutyHeartbeat(APIKey string, timer *prometheus.Timer) {
 // - param resp: The HTTP Response structure
 // - param duration: The number of seconds the call took.
 func AddAPICall(controller string, req *http.Request, resp *http.Response, duration float64) {
-	ApiCallDuration.With(prometheus.Labels{
+	labels := prometheus.Labels{
 		"controller": controller,
 		"method":     req.Method,
 		"resource":   resourceFrom(req.URL),
 		"status":     resp.Status,
-	}).Observe(duration)
+	}
+	ApiCallDuration.With(labels).Observe(duration)
+	if resp.StatusCode >= http.StatusBadRequest {
+		ApiCallErrors.With(labels).Inc()
+	}
 }
 
 // resourceFrom normalizes an API request URL, including removing individual namespace and
@@ -1,9 +1,12 @@
 package localmetrics
 
 import (
+	"net/http"
 	neturl "net/url"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -125,3 +128,40 @@ func TestPathParse(t *testing.T) {
 	}
 
 }
+
+func TestAddAPICallErrors(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://api.pagerduty.com/services/SVC1", nil)
+	assert.NoError(t, err)
+	labels := prometheus.Labels{
+		"controller": "test",
+		"method":     http.MethodGet,
+		"resource":   "api.pagerduty.com/services/{UID}",
+	}
+
+	AddAPICall("test", req, &http.Response{Status: "200 OK", StatusCode: http.StatusOK}, 0.1)
+	AddAPICall("test", req, &http.Response{Status: "429 Too Many Requests", StatusCode: http.StatusTooManyRequests}, 0.1)
+	AddAPICall("test", req, &http.Response{Status: "429 Too Many Requests", StatusCode: http.StatusTooManyRequests}, 0.1)
+
+	labels["status"] = "200 OK"
+	assert.Equal(t, 0.0, testutil.ToFloat64(ApiCallErrors.With(labels)))
+	labels["status"] = "429 Too Many Requests"
+	assert.Equal(t, 2.0, testutil.ToFloat64(ApiCallErrors.With(labels)))
+}
+
+func TestUpdateMetricPagerDutyClusterServiceInfo(t *testing.T) {
+	UpdateMetricPagerDutyClusterServiceInfo("cluster1", "SVC1", "INT1", "pdi1")
+	UpdateMetricPagerDutyClusterServiceInfo("cluster2", "SVC2", "INT2", "pdi1")
+	UpdateMetricPagerDutyClusterServiceInfo("cluster1", "SVC3", "INT3", "pdi2")
+	assert.Equal(t, 3, testutil.CollectAndCount(MetricPagerDutyClusterServiceInfo))
+
+	// a new service replaces the series of the old one
+	UpdateMetricPagerDutyClusterServiceInfo("cluster1", "SVC4", "INT4", "pdi1")
+	assert.Equal(t, 3, testutil.CollectAndCount(MetricPagerDutyClusterServiceInfo))
+	assert.Equal(t, 1.0, testutil.ToFloat64(MetricPagerDutyClusterServiceInfo.WithLabelValues("cluster1", "SVC4", "INT4", "pdi1")))
+
+	DeleteMetricPagerDutyClusterServiceInfo("cluster2", "pdi1")
+	assert.Equal(t, 2, testutil.CollectAndCount(MetricPagerDutyClusterServiceInfo))
+
+	DeleteMetricPagerDutyClusterServiceInfo("", "pdi1")
+	assert.Equal(t, 1, testutil.CollectAndCount(MetricPagerDutyClusterServiceInfo))
+}
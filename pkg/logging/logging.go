@@ -0,0 +1,113 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logging tags the operator's log lines with the fields needed to
+// follow a single PagerDutyIntegration, cluster or PagerDuty service through
+// the logs, and sets how verbose they are.
+package logging
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/operator-framework/operator-sdk/pkg/log/zap"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+)
+
+const (
+	// IntegrationKey is the namespace/name of the PagerDutyIntegration
+	IntegrationKey = "pagerdutyintegration"
+	// ClusterDeploymentKey is the namespace/name of the ClusterDeployment
+	ClusterDeploymentKey = "clusterdeployment"
+	// ServiceIDKey is the ID of the PagerDuty service of a cluster
+	ServiceIDKey = "pd_service_id"
+	// ReconcileIDKey is unique to each reconcile, so the lines of
+	// concurrent reconciles can be told apart
+	ReconcileIDKey = "reconcile_id"
+
+	// reconcileIDLength is the number of characters of a reconcile ID
+	reconcileIDLength = 8
+)
+
+// ForIntegration returns logger tagged for a reconcile of the
+// PagerDutyIntegration name, with a new reconcile ID
+func ForIntegration(logger logr.Logger, name types.NamespacedName) logr.Logger {
+	return WithReconcileID(logger).WithValues(IntegrationKey, name.String())
+}
+
+// WithReconcileID returns logger tagged with a new reconcile ID
+func WithReconcileID(logger logr.Logger) logr.Logger {
+	return logger.WithValues(ReconcileIDKey, utilrand.String(reconcileIDLength))
+}
+
+// ForCluster returns logger tagged with the ClusterDeployment cd
+func ForCluster(logger logr.Logger, cd *hivev1.ClusterDeployment) logr.Logger {
+	return logger.WithValues(ClusterDeploymentKey, cd.Namespace+"/"+cd.Name)
+}
+
+// ForService returns logger tagged with the PagerDuty service serviceID,
+// or logger itself while the service isn't known
+func ForService(logger logr.Logger, serviceID string) logr.Logger {
+	if serviceID == "" {
+		return logger
+	}
+	return logger.WithValues(ServiceIDKey, serviceID)
+}
+
+// FlagSet returns the flag setting the log level, --zap-log-level. It
+// takes the same values as the --zap-level flag of zap.FlagSet, which it
+// sets, so it must be parsed along with it.
+func FlagSet() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("logging", pflag.ExitOnError)
+	fs.Var(&levelValue{}, "zap-log-level",
+		"Log level (one of 'debug', 'info', 'error' or an integer > 0 for more verbose debug logs)")
+	return fs
+}
+
+// levelValue forwards the log level to the zap flag set
+type levelValue struct {
+	str string
+}
+
+func (v *levelValue) Set(l string) error {
+	if err := ValidateLevel(l); err != nil {
+		return err
+	}
+	v.str = l
+	return zap.FlagSet().Set("zap-level", l)
+}
+
+func (v levelValue) String() string {
+	return v.str
+}
+
+func (v levelValue) Type() string {
+	return "level"
+}
+
+// ValidateLevel returns an error if l isn't a log level
+func ValidateLevel(l string) error {
+	switch l {
+	case "debug", "info", "error":
+		return nil
+	}
+	if n, err := strconv.Atoi(l); err != nil || n <= 0 {
+		return fmt.Errorf("invalid log level %q, must be one of 'debug', 'info', 'error' or an integer > 0", l)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"testing"
+
+	"github.com/operator-framework/operator-sdk/pkg/log/zap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLevel(t *testing.T) {
+	for _, l := range []string{"debug", "info", "error", "1", "5"} {
+		assert.NoError(t, ValidateLevel(l), l)
+	}
+	for _, l := range []string{"", "warn", "0", "-1", "verbose"} {
+		assert.Error(t, ValidateLevel(l), l)
+	}
+}
+
+func TestFlagSet(t *testing.T) {
+	fs := FlagSet()
+
+	assert.NoError(t, fs.Parse([]string{"--zap-log-level", "debug"}))
+	assert.Equal(t, "debug", zap.FlagSet().Lookup("zap-level").Value.String())
+
+	assert.Error(t, fs.Set("zap-log-level", "loud"))
+}
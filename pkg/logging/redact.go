@@ -0,0 +1,163 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// Redacted replaces the key material scrubbed by Redact
+const Redacted = "[REDACTED]"
+
+// minSecretLength is the length under which a value isn't registered by
+// AddSecret, as it would scrub unrelated words
+const minSecretLength = 8
+
+// keyPatterns match key material wherever it comes from, secrets whose
+// value isn't registered included
+var keyPatterns = []struct {
+	pattern *regexp.Regexp
+	replace string
+}{
+	// PagerDuty API keys in an Authorization header
+	{regexp.MustCompile(`(?i)(token token=)[^\s"',;]+`), "${1}" + Redacted},
+	// integration keys in the payloads of the PagerDuty APIs
+	{regexp.MustCompile(`("(?:routing_key|integration_key|service_key)"\s*:\s*")[^"]*`), "${1}" + Redacted},
+	// integration keys and heartbeat tokens, 32 hex characters or more
+	{regexp.MustCompile(`\b[0-9a-f]{32,}\b`), Redacted},
+}
+
+// secrets holds the values registered with AddSecret
+var secrets = struct {
+	sync.RWMutex
+	values map[string]bool
+}{values: map[string]bool{}}
+
+// AddSecret registers value, read from a Secret, to be scrubbed by Redact
+func AddSecret(value string) {
+	if len(value) < minSecretLength {
+		return
+	}
+	secrets.Lock()
+	defer secrets.Unlock()
+	secrets.values[value] = true
+}
+
+// Redact returns s with the key material it holds replaced by Redacted
+func Redact(s string) string {
+	secrets.RLock()
+	for value := range secrets.values {
+		s = strings.ReplaceAll(s, value, Redacted)
+	}
+	secrets.RUnlock()
+
+	for _, p := range keyPatterns {
+		s = p.pattern.ReplaceAllString(s, p.replace)
+	}
+	return s
+}
+
+// RedactError returns err with its message redacted, nil if err is nil
+func RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return errors.New(Redact(err.Error()))
+}
+
+// redactValues redacts the values of keysAndValues, leaving the keys as
+// they are. Byte slices are Secret payloads and are dropped altogether.
+func redactValues(keysAndValues []interface{}) []interface{} {
+	redacted := make([]interface{}, len(keysAndValues))
+	for i, value := range keysAndValues {
+		if i%2 == 0 {
+			redacted[i] = value
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			redacted[i] = Redact(v)
+		case error:
+			redacted[i] = Redact(v.Error())
+		case []byte, map[string][]byte:
+			redacted[i] = Redacted
+		case fmt.Stringer:
+			redacted[i] = Redact(v.String())
+		default:
+			redacted[i] = value
+		}
+	}
+	return redacted
+}
+
+// NewRedactingLogger returns logger redacting the messages, errors and
+// values it logs
+func NewRedactingLogger(logger logr.Logger) logr.Logger {
+	return redactingLogger{Logger: logger}
+}
+
+type redactingLogger struct {
+	logr.Logger
+}
+
+func (l redactingLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.Logger.Info(Redact(msg), redactValues(keysAndValues)...)
+}
+
+func (l redactingLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.Logger.Error(RedactError(err), Redact(msg), redactValues(keysAndValues)...)
+}
+
+func (l redactingLogger) V(level int) logr.Logger {
+	return redactingLogger{Logger: l.Logger.V(level)}
+}
+
+func (l redactingLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	return redactingLogger{Logger: l.Logger.WithValues(redactValues(keysAndValues)...)}
+}
+
+func (l redactingLogger) WithName(name string) logr.Logger {
+	return redactingLogger{Logger: l.Logger.WithName(name)}
+}
+
+// NewRedactingRecorder returns recorder redacting the messages of the
+// events it records
+func NewRedactingRecorder(recorder record.EventRecorder) record.EventRecorder {
+	return redactingRecorder{recorder: recorder}
+}
+
+type redactingRecorder struct {
+	recorder record.EventRecorder
+}
+
+func (r redactingRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.recorder.Event(object, eventtype, reason, Redact(message))
+}
+
+func (r redactingRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.recorder.Event(object, eventtype, reason, Redact(fmt.Sprintf(messageFmt, args...)))
+}
+
+func (r redactingRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.recorder.AnnotatedEventf(object, annotations, eventtype, reason, "%s", Redact(fmt.Sprintf(messageFmt, args...)))
+}
@@ -0,0 +1,315 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package naming
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hiveintv1alpha1 "github.com/openshift/hive/pkg/apis/hiveinternal/v1alpha1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Migrate renames the secondary resources of a ClusterDeployment created
+// under any previous scheme to the current scheme. ConfigMaps are copied to
+// their new name before the old object is deleted, so the PagerDuty service
+// they point to is never lost. A SyncSet is replaced rather than renamed: the
+// controller creates the new one, and the old one is only deleted once Hive
+// reports applying its replacement to the cluster, so the cluster keeps its
+// integration key throughout. The old Secret, which the old SyncSet reads,
+// is copied right away but kept until then. Objects controlled by another
+// ClusterDeployment are left alone. It is safe to call on every reconcile.
+func Migrate(c client.Client, reqLogger logr.Logger, namespace, servicePrefix, clusterDeploymentName string) error {
+	return migrate(c, reqLogger, Previous(), Current(), namespace, servicePrefix, clusterDeploymentName)
+}
+
+// MigratePrefix renames the secondary resources of a ClusterDeployment
+// named after oldPrefix to newPrefix, the way Migrate renames those of a
+// previous scheme, when the servicePrefix of their PagerDutyIntegration
+// changes. It is safe to call on every reconcile.
+func MigratePrefix(c client.Client, reqLogger logr.Logger, namespace, oldPrefix, newPrefix, clusterDeploymentName string) error {
+	return rename(c, reqLogger, []Scheme{Current()}, oldPrefix, Current(), newPrefix, namespace, clusterDeploymentName)
+}
+
+func migrate(c client.Client, reqLogger logr.Logger, from []Scheme, to Scheme, namespace, servicePrefix, clusterDeploymentName string) error {
+	return rename(c, reqLogger, from, servicePrefix, to, servicePrefix, namespace, clusterDeploymentName)
+}
+
+// rename renames the secondary resources of a ClusterDeployment named after
+// fromPrefix under any of the from schemes to toPrefix under the to scheme
+func rename(c client.Client, reqLogger logr.Logger, from []Scheme, fromPrefix string, to Scheme, toPrefix string, namespace, clusterDeploymentName string) error {
+	for _, old := range from {
+		oldName := old.ConfigMapName(fromPrefix, clusterDeploymentName)
+		newName := to.ConfigMapName(toPrefix, clusterDeploymentName)
+		if oldName != newName {
+			err := migrateObject(c, reqLogger, namespace, clusterDeploymentName, oldName, newName, true, &corev1.ConfigMap{}, func(o runtime.Object) runtime.Object {
+				cm := o.(*corev1.ConfigMap)
+				return &corev1.ConfigMap{ObjectMeta: copyMeta(cm.ObjectMeta, newName), Data: cm.Data}
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		oldName = old.MigrationConfigMapName(fromPrefix, clusterDeploymentName)
+		newName = to.MigrationConfigMapName(toPrefix, clusterDeploymentName)
+		if oldName != newName {
+			err := migrateObject(c, reqLogger, namespace, clusterDeploymentName, oldName, newName, true, &corev1.ConfigMap{}, func(o runtime.Object) runtime.Object {
+				cm := o.(*corev1.ConfigMap)
+				return &corev1.ConfigMap{ObjectMeta: copyMeta(cm.ObjectMeta, newName), Data: cm.Data}
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		// the old Secret goes once nothing delivers it anymore
+		secretDelivered := false
+		oldName = old.SyncSetName(fromPrefix, clusterDeploymentName)
+		newName = to.SyncSetName(toPrefix, clusterDeploymentName)
+		if oldName != newName {
+			retired, err := retireSyncSet(c, reqLogger, namespace, clusterDeploymentName, oldName, newName, old.Version)
+			if err != nil {
+				return err
+			}
+			secretDelivered = !retired
+		}
+
+		oldName = old.SecretName(fromPrefix, clusterDeploymentName)
+		newName = to.SecretName(toPrefix, clusterDeploymentName)
+		if oldName != newName {
+			err := migrateObject(c, reqLogger, namespace, clusterDeploymentName, oldName, newName, !secretDelivered, &corev1.Secret{}, func(o runtime.Object) runtime.Object {
+				secret := o.(*corev1.Secret)
+				return &corev1.Secret{ObjectMeta: copyMeta(secret.ObjectMeta, newName), Type: secret.Type, Data: secret.Data}
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		oldName = old.ProbeSyncSetName(fromPrefix, clusterDeploymentName)
+		newName = to.ProbeSyncSetName(toPrefix, clusterDeploymentName)
+		if oldName != newName {
+			_, err := retireSyncSet(c, reqLogger, namespace, clusterDeploymentName, oldName, newName, old.Version)
+			if err != nil {
+				return err
+			}
+		}
+
+		oldName = old.AlertmanagerSyncSetName(fromPrefix, clusterDeploymentName)
+		newName = to.AlertmanagerSyncSetName(toPrefix, clusterDeploymentName)
+		if oldName != newName {
+			_, err := retireSyncSet(c, reqLogger, namespace, clusterDeploymentName, oldName, newName, old.Version)
+			if err != nil {
+				return err
+			}
+		}
+
+		// the check-in URL names the Lease, the controller delivers the
+		// new one before the cluster checks in again
+		oldName = old.HeartbeatLeaseName(fromPrefix, clusterDeploymentName)
+		newName = to.HeartbeatLeaseName(toPrefix, clusterDeploymentName)
+		if oldName != newName {
+			err := migrateObject(c, reqLogger, namespace, clusterDeploymentName, oldName, newName, true, &coordinationv1.Lease{}, func(o runtime.Object) runtime.Object {
+				lease := o.(*coordinationv1.Lease)
+				return &coordinationv1.Lease{ObjectMeta: copyMeta(lease.ObjectMeta, newName), Spec: lease.Spec}
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Pending returns true while an object of the ClusterDeployment named under
+// a previous scheme remains, as SyncSets and the Secret they deliver are
+// only retired once their replacement is applied and Migrate has to be
+// called again until then.
+func Pending(c client.Client, namespace, servicePrefix, clusterDeploymentName string) (bool, error) {
+	return pending(c, Previous(), Current(), namespace, servicePrefix, clusterDeploymentName)
+}
+
+// PrefixPending returns true while an object of the ClusterDeployment named
+// after oldPrefix remains, as MigratePrefix has to be called again until
+// its SyncSets and the Secret they deliver are retired.
+func PrefixPending(c client.Client, namespace, oldPrefix, newPrefix, clusterDeploymentName string) (bool, error) {
+	return renamePending(c, []Scheme{Current()}, oldPrefix, Current(), newPrefix, namespace, clusterDeploymentName)
+}
+
+func pending(c client.Client, from []Scheme, current Scheme, namespace, servicePrefix, clusterDeploymentName string) (bool, error) {
+	return renamePending(c, from, servicePrefix, current, servicePrefix, namespace, clusterDeploymentName)
+}
+
+// renamePending returns true while an object of the ClusterDeployment named
+// after fromPrefix under any of the from schemes remains
+func renamePending(c client.Client, from []Scheme, fromPrefix string, current Scheme, toPrefix string, namespace, clusterDeploymentName string) (bool, error) {
+	for _, old := range from {
+		objects := []struct {
+			oldName, newName string
+			obj              runtime.Object
+		}{
+			{old.ConfigMapName(fromPrefix, clusterDeploymentName), current.ConfigMapName(toPrefix, clusterDeploymentName), &corev1.ConfigMap{}},
+			{old.MigrationConfigMapName(fromPrefix, clusterDeploymentName), current.MigrationConfigMapName(toPrefix, clusterDeploymentName), &corev1.ConfigMap{}},
+			{old.SyncSetName(fromPrefix, clusterDeploymentName), current.SyncSetName(toPrefix, clusterDeploymentName), &hivev1.SyncSet{}},
+			{old.SecretName(fromPrefix, clusterDeploymentName), current.SecretName(toPrefix, clusterDeploymentName), &corev1.Secret{}},
+			{old.ProbeSyncSetName(fromPrefix, clusterDeploymentName), current.ProbeSyncSetName(toPrefix, clusterDeploymentName), &hivev1.SyncSet{}},
+			{old.AlertmanagerSyncSetName(fromPrefix, clusterDeploymentName), current.AlertmanagerSyncSetName(toPrefix, clusterDeploymentName), &hivev1.SyncSet{}},
+			{old.HeartbeatLeaseName(fromPrefix, clusterDeploymentName), current.HeartbeatLeaseName(toPrefix, clusterDeploymentName), &coordinationv1.Lease{}},
+		}
+		for _, o := range objects {
+			if o.oldName == o.newName {
+				continue
+			}
+			err := c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: o.oldName}, o.obj)
+			if err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return false, err
+			}
+			meta, err := apimeta.Accessor(o.obj)
+			if err != nil {
+				return false, err
+			}
+			if !controlledByOther(meta, clusterDeploymentName) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// retireSyncSet deletes the SyncSet named under a previous scheme once the
+// ClusterSync of the cluster reports its replacement, newName, applied.
+// Before that, a SyncSet in Sync mode is switched to Upsert, and deleted
+// only after Hive stops tracking its resources, as Hive would otherwise
+// delete from the cluster the objects the replacement applies too. It
+// returns true once the old SyncSet is gone.
+func retireSyncSet(c client.Client, reqLogger logr.Logger, namespace, clusterDeploymentName, name, newName string, version int) (bool, error) {
+	ss := &hivev1.SyncSet{}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, ss)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	if controlledByOther(ss, clusterDeploymentName) {
+		return true, nil
+	}
+
+	clusterSync := &hiveintv1alpha1.ClusterSync{}
+	err = c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: clusterDeploymentName}, clusterSync)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	replacement := findSyncStatus(clusterSync.Status.SyncSets, newName)
+	if replacement == nil || replacement.Result != hiveintv1alpha1.SuccessSyncSetResult {
+		reqLogger.Info("Keeping SyncSet named under a previous scheme until its replacement is applied", "Namespace", namespace, "Name", name, "NewName", newName, "Scheme", version)
+		return false, nil
+	}
+
+	if ss.Spec.ResourceApplyMode == hivev1.SyncResourceApplyMode {
+		reqLogger.Info("Switching SyncSet named under a previous scheme to Upsert before deleting it", "Namespace", namespace, "Name", name, "Scheme", version)
+		ss.Spec.ResourceApplyMode = hivev1.UpsertResourceApplyMode
+		return false, c.Update(context.TODO(), ss)
+	}
+	if status := findSyncStatus(clusterSync.Status.SyncSets, name); status != nil && len(status.ResourcesToDelete) > 0 {
+		// Hive hasn't applied the switch to Upsert yet
+		return false, nil
+	}
+
+	reqLogger.Info("Deleting SyncSet named under a previous scheme", "Namespace", namespace, "Name", name, "Scheme", version)
+	err = c.Delete(context.TODO(), ss)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	return true, nil
+}
+
+// findSyncStatus returns the status of the SyncSet name, or nil
+func findSyncStatus(statuses []hiveintv1alpha1.SyncStatus, name string) *hiveintv1alpha1.SyncStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+// migrateObject copies the object named oldName to newName, unless newName
+// already exists, then deletes the old object if deleteOld is set. obj is
+// the empty object to read into and rename builds the copy to create.
+func migrateObject(c client.Client, reqLogger logr.Logger, namespace, clusterDeploymentName, oldName, newName string, deleteOld bool, obj runtime.Object, rename func(runtime.Object) runtime.Object) error {
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: oldName}, obj)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	meta, err := apimeta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	if controlledByOther(meta, clusterDeploymentName) {
+		return nil
+	}
+
+	reqLogger.Info("Migrating object to its new name", "Namespace", namespace, "Name", oldName, "NewName", newName)
+	err = c.Create(context.TODO(), rename(obj))
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	if !deleteOld {
+		return nil
+	}
+	err = c.Delete(context.TODO(), obj)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// controlledByOther returns true if the object is controlled by a
+// ClusterDeployment other than clusterDeploymentName, as an old name of one
+// cluster can be the name of another's object.
+func controlledByOther(obj metav1.Object, clusterDeploymentName string) bool {
+	owner := metav1.GetControllerOf(obj)
+	return owner != nil && owner.Kind == "ClusterDeployment" && owner.Name != clusterDeploymentName
+}
+
+// copyMeta keeps the labels, annotations and owner references of an object
+// under a new name.
+func copyMeta(meta metav1.ObjectMeta, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:            name,
+		Namespace:       meta.Namespace,
+		Labels:          meta.Labels,
+		Annotations:     meta.Annotations,
+		OwnerReferences: meta.OwnerReferences,
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package naming is the single place that decides the names of the
+// secondary resources (Secrets, ConfigMaps, SyncSets) the operator creates
+// for a ClusterDeployment.
+//
+// Names are versioned: every convention ever used is kept as a Scheme so
+// that objects created under an older one can be found and migrated to the
+// current one instead of being orphaned. To change a name, append a new
+// Scheme to schemes; never edit an existing one.
+package naming
+
+const (
+	// SecretSuffix is the suffix of the Secret holding the integration key
+	SecretSuffix string = "-pd-secret"
+	// ConfigMapSuffix is the suffix of the ConfigMap holding SERVICE_ID and INTEGRATION_ID
+	ConfigMapSuffix string = "-pd-config"
+	// ProbeSyncSetSuffix is the suffix of the SyncSet delivering the delivery probe
+	ProbeSyncSetSuffix string = "-pd-probe"
+	// AlertmanagerSyncSetSuffix is the suffix of the SyncSet delivering the Alertmanager configuration
+	AlertmanagerSyncSetSuffix string = "-pd-alertmanager"
+	// LegacySyncSetSuffix is the suffix of the SyncSet delivering the
+	// integration key under the legacy scheme
+	LegacySyncSetSuffix string = "-pd-sync"
+	// MigrationConfigMapSuffix is the suffix of the ConfigMap holding SERVICE_ID
+	// and INTEGRATION_ID of the service in the account being migrated to
+	MigrationConfigMapSuffix string = "-pd-migration-config"
+	// HeartbeatLeaseSuffix is the suffix of the Lease recording the check-ins of the cluster
+	HeartbeatLeaseSuffix string = "-pd-heartbeat"
+	// CoverageReportSuffix is the suffix of the ConfigMap holding the
+	// coverage report of a PagerDutyIntegration
+	CoverageReportSuffix string = "-pd-coverage-report"
+)
+
+// Scheme is one version of the naming convention for the secondary resources
+// of a ClusterDeployment.
+type Scheme struct {
+	// Version identifies the scheme, newer schemes have higher versions
+	Version int
+
+	secretName    func(servicePrefix, clusterDeploymentName string) string
+	configMapName func(servicePrefix, clusterDeploymentName string) string
+	syncSetName   func(servicePrefix, clusterDeploymentName string) string
+
+	probeSyncSetName        func(servicePrefix, clusterDeploymentName string) string
+	alertmanagerSyncSetName func(servicePrefix, clusterDeploymentName string) string
+	migrationConfigMapName  func(servicePrefix, clusterDeploymentName string) string
+	heartbeatLeaseName      func(servicePrefix, clusterDeploymentName string) string
+}
+
+// SecretName returns the name of the Secret holding the integration key.
+func (s Scheme) SecretName(servicePrefix, clusterDeploymentName string) string {
+	return s.secretName(servicePrefix, clusterDeploymentName)
+}
+
+// ConfigMapName returns the name of the ConfigMap holding SERVICE_ID and INTEGRATION_ID.
+func (s Scheme) ConfigMapName(servicePrefix, clusterDeploymentName string) string {
+	return s.configMapName(servicePrefix, clusterDeploymentName)
+}
+
+// SyncSetName returns the name of the SyncSet that delivers the Secret to the cluster.
+func (s Scheme) SyncSetName(servicePrefix, clusterDeploymentName string) string {
+	return s.syncSetName(servicePrefix, clusterDeploymentName)
+}
+
+// ProbeSyncSetName returns the name of the SyncSet that delivers the delivery probe to the cluster.
+func (s Scheme) ProbeSyncSetName(servicePrefix, clusterDeploymentName string) string {
+	return s.probeSyncSetName(servicePrefix, clusterDeploymentName)
+}
+
+// AlertmanagerSyncSetName returns the name of the SyncSet that delivers the Alertmanager configuration to the cluster.
+func (s Scheme) AlertmanagerSyncSetName(servicePrefix, clusterDeploymentName string) string {
+	return s.alertmanagerSyncSetName(servicePrefix, clusterDeploymentName)
+}
+
+// MigrationConfigMapName returns the name of the ConfigMap holding SERVICE_ID and INTEGRATION_ID
+// of the service in the account being migrated to.
+func (s Scheme) MigrationConfigMapName(servicePrefix, clusterDeploymentName string) string {
+	return s.migrationConfigMapName(servicePrefix, clusterDeploymentName)
+}
+
+// HeartbeatLeaseName returns the name of the Lease recording the check-ins of the cluster.
+func (s Scheme) HeartbeatLeaseName(servicePrefix, clusterDeploymentName string) string {
+	return s.heartbeatLeaseName(servicePrefix, clusterDeploymentName)
+}
+
+// schemes lists every naming scheme, oldest first. The last one is current.
+var schemes = []Scheme{
+	{
+		// v0, legacy: <cd name><suffix> without the servicePrefix, SyncSet
+		// named <cd name>-pd-sync. The probe, Alertmanager, migration and
+		// heartbeat objects didn't exist yet and keep their current names.
+		Version:       0,
+		secretName:    func(p, cd string) string { return cd + SecretSuffix },
+		configMapName: func(p, cd string) string { return cd + ConfigMapSuffix },
+		syncSetName:   func(p, cd string) string { return cd + LegacySyncSetSuffix },
+
+		probeSyncSetName:        func(p, cd string) string { return join(p, cd, ProbeSyncSetSuffix) },
+		alertmanagerSyncSetName: func(p, cd string) string { return join(p, cd, AlertmanagerSyncSetSuffix) },
+		migrationConfigMapName:  func(p, cd string) string { return join(p, cd, MigrationConfigMapSuffix) },
+		heartbeatLeaseName:      func(p, cd string) string { return join(p, cd, HeartbeatLeaseSuffix) },
+	},
+	{
+		// v1: <servicePrefix>-<cd name><suffix>, SyncSet named after the Secret
+		Version:       1,
+		secretName:    func(p, cd string) string { return join(p, cd, SecretSuffix) },
+		configMapName: func(p, cd string) string { return join(p, cd, ConfigMapSuffix) },
+		syncSetName:   func(p, cd string) string { return join(p, cd, SecretSuffix) },
+
+		probeSyncSetName:        func(p, cd string) string { return join(p, cd, ProbeSyncSetSuffix) },
+		alertmanagerSyncSetName: func(p, cd string) string { return join(p, cd, AlertmanagerSyncSetSuffix) },
+		migrationConfigMapName:  func(p, cd string) string { return join(p, cd, MigrationConfigMapSuffix) },
+		heartbeatLeaseName:      func(p, cd string) string { return join(p, cd, HeartbeatLeaseSuffix) },
+	},
+}
+
+// Current returns the scheme used for all new objects.
+func Current() Scheme {
+	return schemes[len(schemes)-1]
+}
+
+// Previous returns the schemes that objects may still be named under, newest first.
+func Previous() []Scheme {
+	previous := []Scheme{}
+	for i := len(schemes) - 2; i >= 0; i-- {
+		previous = append(previous, schemes[i])
+	}
+	return previous
+}
+
+// SecretName returns the name of the Secret under the current scheme.
+func SecretName(servicePrefix, clusterDeploymentName string) string {
+	return Current().SecretName(servicePrefix, clusterDeploymentName)
+}
+
+// ConfigMapName returns the name of the ConfigMap under the current scheme.
+func ConfigMapName(servicePrefix, clusterDeploymentName string) string {
+	return Current().ConfigMapName(servicePrefix, clusterDeploymentName)
+}
+
+// SyncSetName returns the name of the SyncSet under the current scheme.
+func SyncSetName(servicePrefix, clusterDeploymentName string) string {
+	return Current().SyncSetName(servicePrefix, clusterDeploymentName)
+}
+
+// ProbeSyncSetName returns the name of the probe SyncSet under the current scheme.
+func ProbeSyncSetName(servicePrefix, clusterDeploymentName string) string {
+	return Current().ProbeSyncSetName(servicePrefix, clusterDeploymentName)
+}
+
+// AlertmanagerSyncSetName returns the name of the Alertmanager SyncSet under the current scheme.
+func AlertmanagerSyncSetName(servicePrefix, clusterDeploymentName string) string {
+	return Current().AlertmanagerSyncSetName(servicePrefix, clusterDeploymentName)
+}
+
+// MigrationConfigMapName returns the name of the migration ConfigMap under the current scheme.
+func MigrationConfigMapName(servicePrefix, clusterDeploymentName string) string {
+	return Current().MigrationConfigMapName(servicePrefix, clusterDeploymentName)
+}
+
+// HeartbeatLeaseName returns the name of the heartbeat Lease under the current scheme.
+func HeartbeatLeaseName(servicePrefix, clusterDeploymentName string) string {
+	return Current().HeartbeatLeaseName(servicePrefix, clusterDeploymentName)
+}
+
+// SelectorSyncSetName returns the name of the SelectorSyncSet delivering the
+// shared integration key of a PagerDutyIntegration to all its clusters. It
+// is not per cluster, so it has no scheme.
+func SelectorSyncSetName(pagerDutyIntegrationName string) string {
+	return pagerDutyIntegrationName + SecretSuffix
+}
+
+// CoverageReportConfigMapName returns the name of the ConfigMap holding the
+// coverage report of a PagerDutyIntegration. It is not per cluster, so it
+// has no scheme.
+func CoverageReportConfigMapName(pagerDutyIntegrationName string) string {
+	return pagerDutyIntegrationName + CoverageReportSuffix
+}
+
+func join(servicePrefix, clusterDeploymentName, suffix string) string {
+	return servicePrefix + "-" + clusterDeploymentName + suffix
+}
@@ -0,0 +1,373 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package naming
+
+import (
+	"context"
+	"testing"
+
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hiveintv1alpha1 "github.com/openshift/hive/pkg/apis/hiveinternal/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	testNamespace     = "testNamespace"
+	testServicePrefix = "test-service-prefix"
+	testClusterName   = "testCluster"
+)
+
+// testScheme is a naming scheme that only exists in tests, to have
+// something to migrate to.
+var testScheme = Scheme{
+	Version:       99,
+	secretName:    func(p, cd string) string { return "new-" + join(p, cd, SecretSuffix) },
+	configMapName: func(p, cd string) string { return "new-" + join(p, cd, ConfigMapSuffix) },
+	syncSetName:   func(p, cd string) string { return "new-" + join(p, cd, "-pd-syncset") },
+
+	probeSyncSetName:        func(p, cd string) string { return "new-" + join(p, cd, ProbeSyncSetSuffix) },
+	alertmanagerSyncSetName: func(p, cd string) string { return "new-" + join(p, cd, AlertmanagerSyncSetSuffix) },
+	migrationConfigMapName:  func(p, cd string) string { return "new-" + join(p, cd, MigrationConfigMapSuffix) },
+	heartbeatLeaseName:      func(p, cd string) string { return "new-" + join(p, cd, HeartbeatLeaseSuffix) },
+}
+
+func TestCurrentNames(t *testing.T) {
+	// these names are relied on by existing clusters, they must never change
+	assert.Equal(t, "test-service-prefix-testCluster-pd-secret", SecretName(testServicePrefix, testClusterName))
+	assert.Equal(t, "test-service-prefix-testCluster-pd-config", ConfigMapName(testServicePrefix, testClusterName))
+	assert.Equal(t, "test-service-prefix-testCluster-pd-secret", SyncSetName(testServicePrefix, testClusterName))
+	assert.Equal(t, "test-service-prefix-testCluster-pd-probe", ProbeSyncSetName(testServicePrefix, testClusterName))
+	assert.Equal(t, "test-service-prefix-testCluster-pd-alertmanager", AlertmanagerSyncSetName(testServicePrefix, testClusterName))
+	assert.Equal(t, "test-service-prefix-testCluster-pd-migration-config", MigrationConfigMapName(testServicePrefix, testClusterName))
+	assert.Equal(t, "test-service-prefix-testCluster-pd-heartbeat", HeartbeatLeaseName(testServicePrefix, testClusterName))
+	assert.Equal(t, "osd-pd-secret", SelectorSyncSetName("osd"))
+}
+
+func TestLegacyNames(t *testing.T) {
+	legacy := schemes[0]
+	assert.Equal(t, "testCluster-pd-secret", legacy.SecretName(testServicePrefix, testClusterName))
+	assert.Equal(t, "testCluster-pd-config", legacy.ConfigMapName(testServicePrefix, testClusterName))
+	assert.Equal(t, "testCluster-pd-sync", legacy.SyncSetName(testServicePrefix, testClusterName))
+	assert.Equal(t, legacy.Version, Previous()[len(Previous())-1].Version)
+}
+
+func TestSchemeVersions(t *testing.T) {
+	for i := 1; i < len(schemes); i++ {
+		assert.Greater(t, schemes[i].Version, schemes[i-1].Version, "schemes must be listed oldest first")
+	}
+	assert.Len(t, Previous(), len(schemes)-1)
+}
+
+func TestMigrate(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+
+	old := Current()
+	tests := []struct {
+		name            string
+		localObjects    []runtime.Object
+		expectServiceID string
+	}{
+		{
+			name: "Test Migrate Objects",
+			localObjects: []runtime.Object{
+				testConfigMap(old.ConfigMapName(testServicePrefix, testClusterName), "OLD"),
+				testSecret(old.SecretName(testServicePrefix, testClusterName)),
+				testSyncSet(old.SyncSetName(testServicePrefix, testClusterName)),
+				testSyncSet(old.ProbeSyncSetName(testServicePrefix, testClusterName)),
+				testSyncSet(old.AlertmanagerSyncSetName(testServicePrefix, testClusterName)),
+				testConfigMap(old.MigrationConfigMapName(testServicePrefix, testClusterName), "OLD"),
+				testLease(old.HeartbeatLeaseName(testServicePrefix, testClusterName)),
+				testClusterSync(testScheme, hiveintv1alpha1.SuccessSyncSetResult),
+			},
+			expectServiceID: "OLD",
+		},
+		{
+			name: "Test Migrate Keeps Existing Objects",
+			localObjects: []runtime.Object{
+				testConfigMap(old.ConfigMapName(testServicePrefix, testClusterName), "OLD"),
+				testConfigMap(testScheme.ConfigMapName(testServicePrefix, testClusterName), "NEW"),
+				testSecret(testScheme.SecretName(testServicePrefix, testClusterName)),
+				testClusterSync(testScheme, hiveintv1alpha1.SuccessSyncSetResult),
+			},
+			expectServiceID: "NEW",
+		},
+		{
+			name:         "Test Migrate Nothing To Do",
+			localObjects: []runtime.Object{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := fakekubeclient.NewFakeClient(test.localObjects...)
+
+			// run twice to confirm the second run is a noop
+			err1 := migrate(c, logf.Log, []Scheme{old}, testScheme, testNamespace, testServicePrefix, testClusterName)
+			err2 := migrate(c, logf.Log, []Scheme{old}, testScheme, testNamespace, testServicePrefix, testClusterName)
+			assert.NoError(t, err1)
+			assert.NoError(t, err2)
+
+			// nothing is left under the old names
+			err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: old.ConfigMapName(testServicePrefix, testClusterName)}, &corev1.ConfigMap{})
+			assert.True(t, errors.IsNotFound(err))
+			err = c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: old.SecretName(testServicePrefix, testClusterName)}, &corev1.Secret{})
+			assert.True(t, errors.IsNotFound(err))
+			err = c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: old.SyncSetName(testServicePrefix, testClusterName)}, &hivev1.SyncSet{})
+			assert.True(t, errors.IsNotFound(err))
+			err = c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: old.ProbeSyncSetName(testServicePrefix, testClusterName)}, &hivev1.SyncSet{})
+			assert.True(t, errors.IsNotFound(err))
+			err = c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: old.AlertmanagerSyncSetName(testServicePrefix, testClusterName)}, &hivev1.SyncSet{})
+			assert.True(t, errors.IsNotFound(err))
+			err = c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: old.MigrationConfigMapName(testServicePrefix, testClusterName)}, &corev1.ConfigMap{})
+			assert.True(t, errors.IsNotFound(err))
+			err = c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: old.HeartbeatLeaseName(testServicePrefix, testClusterName)}, &coordinationv1.Lease{})
+			assert.True(t, errors.IsNotFound(err))
+
+			if test.expectServiceID == "" {
+				return
+			}
+
+			// the configmap is under the new name, and an existing one is not overwritten
+			cm := &corev1.ConfigMap{}
+			err = c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testScheme.ConfigMapName(testServicePrefix, testClusterName)}, cm)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectServiceID, cm.Data["SERVICE_ID"])
+			assert.Equal(t, "test", cm.Labels["test"])
+
+			err = c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testScheme.SecretName(testServicePrefix, testClusterName)}, &corev1.Secret{})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestMigrateRetiresSyncSets(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+
+	old := Current()
+	oldSyncSet := types.NamespacedName{Namespace: testNamespace, Name: old.SyncSetName(testServicePrefix, testClusterName)}
+	oldSecret := types.NamespacedName{Namespace: testNamespace, Name: old.SecretName(testServicePrefix, testClusterName)}
+	newSecret := types.NamespacedName{Namespace: testNamespace, Name: testScheme.SecretName(testServicePrefix, testClusterName)}
+
+	syncSet := testSyncSet(oldSyncSet.Name)
+	syncSet.Spec.ResourceApplyMode = hivev1.SyncResourceApplyMode
+	clusterSync := testClusterSync(testScheme, hiveintv1alpha1.FailureSyncSetResult)
+	clusterSync.Status.SyncSets = append(clusterSync.Status.SyncSets, hiveintv1alpha1.SyncStatus{
+		Name:              oldSyncSet.Name,
+		Result:            hiveintv1alpha1.SuccessSyncSetResult,
+		ResourcesToDelete: []hiveintv1alpha1.SyncResourceReference{{Kind: "Secret", Name: "pd-secret"}},
+	})
+	c := fakekubeclient.NewFakeClient(syncSet, testSecret(oldSecret.Name), clusterSync)
+	migrate := func() {
+		assert.NoError(t, migrate(c, logf.Log, []Scheme{old}, testScheme, testNamespace, testServicePrefix, testClusterName))
+	}
+
+	// the replacement isn't applied yet, the old SyncSet and the Secret it reads are kept
+	migrate()
+	assert.NoError(t, c.Get(context.TODO(), oldSyncSet, &hivev1.SyncSet{}))
+	assert.NoError(t, c.Get(context.TODO(), oldSecret, &corev1.Secret{}))
+	assert.NoError(t, c.Get(context.TODO(), newSecret, &corev1.Secret{}))
+
+	// once it is, the old SyncSet stops deleting its resources from the cluster
+	clusterSync.Status.SyncSets[0].Result = hiveintv1alpha1.SuccessSyncSetResult
+	assert.NoError(t, c.Update(context.TODO(), clusterSync))
+	migrate()
+	ss := &hivev1.SyncSet{}
+	assert.NoError(t, c.Get(context.TODO(), oldSyncSet, ss))
+	assert.Equal(t, hivev1.UpsertResourceApplyMode, ss.Spec.ResourceApplyMode)
+
+	// and is deleted after Hive applied the switch, along with the old Secret
+	migrate()
+	assert.NoError(t, c.Get(context.TODO(), oldSyncSet, &hivev1.SyncSet{}))
+	findSyncStatus(clusterSync.Status.SyncSets, oldSyncSet.Name).ResourcesToDelete = nil
+	assert.NoError(t, c.Update(context.TODO(), clusterSync))
+	migrate()
+	assert.True(t, errors.IsNotFound(c.Get(context.TODO(), oldSyncSet, &hivev1.SyncSet{})))
+	assert.True(t, errors.IsNotFound(c.Get(context.TODO(), oldSecret, &corev1.Secret{})))
+	assert.NoError(t, c.Get(context.TODO(), newSecret, &corev1.Secret{}))
+}
+
+func TestMigrateSkipsObjectsOfOtherClusters(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+
+	old := Current()
+	cm := testConfigMap(old.ConfigMapName(testServicePrefix, testClusterName), "OTHER")
+	isController := true
+	cm.OwnerReferences = []metav1.OwnerReference{{Kind: "ClusterDeployment", Name: "otherCluster", Controller: &isController}}
+	c := fakekubeclient.NewFakeClient(cm)
+
+	assert.NoError(t, migrate(c, logf.Log, []Scheme{old}, testScheme, testNamespace, testServicePrefix, testClusterName))
+
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: cm.Name}, &corev1.ConfigMap{}))
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testScheme.ConfigMapName(testServicePrefix, testClusterName)}, &corev1.ConfigMap{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestMigratePrefix(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+
+	newPrefix := "renamed"
+	clusterSync := &hiveintv1alpha1.ClusterSync{ObjectMeta: metav1.ObjectMeta{Name: testClusterName, Namespace: testNamespace}}
+	c := fakekubeclient.NewFakeClient(
+		testConfigMap(ConfigMapName(testServicePrefix, testClusterName), "OLD"),
+		testSecret(SecretName(testServicePrefix, testClusterName)),
+		testSyncSet(SyncSetName(testServicePrefix, testClusterName)),
+		clusterSync,
+	)
+
+	// the ConfigMap moves right away, the Secret is copied but kept for the SyncSet delivering it
+	assert.NoError(t, MigratePrefix(c, logf.Log, testNamespace, testServicePrefix, newPrefix, testClusterName))
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: ConfigMapName(newPrefix, testClusterName)}, cm))
+	assert.Equal(t, "OLD", cm.Data["SERVICE_ID"])
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: ConfigMapName(testServicePrefix, testClusterName)}, &corev1.ConfigMap{})
+	assert.True(t, errors.IsNotFound(err))
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: SecretName(newPrefix, testClusterName)}, &corev1.Secret{}))
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: SecretName(testServicePrefix, testClusterName)}, &corev1.Secret{}))
+	pending, err := PrefixPending(c, testNamespace, testServicePrefix, newPrefix, testClusterName)
+	assert.NoError(t, err)
+	assert.True(t, pending)
+
+	// once the renamed SyncSet is applied the old one and its Secret go
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testClusterName}, clusterSync))
+	clusterSync.Status.SyncSets = []hiveintv1alpha1.SyncStatus{{Name: SyncSetName(newPrefix, testClusterName), Result: hiveintv1alpha1.SuccessSyncSetResult}}
+	assert.NoError(t, c.Update(context.TODO(), clusterSync))
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, MigratePrefix(c, logf.Log, testNamespace, testServicePrefix, newPrefix, testClusterName))
+	}
+	pending, err = PrefixPending(c, testNamespace, testServicePrefix, newPrefix, testClusterName)
+	assert.NoError(t, err)
+	assert.False(t, pending)
+}
+
+func TestPending(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+
+	old := Current()
+	otherCM := testConfigMap(old.ConfigMapName(testServicePrefix, testClusterName), "OTHER")
+	isController := true
+	otherCM.OwnerReferences = []metav1.OwnerReference{{Kind: "ClusterDeployment", Name: "otherCluster", Controller: &isController}}
+
+	tests := []struct {
+		name         string
+		localObjects []runtime.Object
+		expected     bool
+	}{
+		{
+			name:     "nothing left",
+			expected: false,
+		},
+		{
+			name:         "only current names",
+			localObjects: []runtime.Object{testConfigMap(testScheme.ConfigMapName(testServicePrefix, testClusterName), "ABC")},
+			expected:     false,
+		},
+		{
+			name:         "old SyncSet",
+			localObjects: []runtime.Object{testSyncSet(old.SyncSetName(testServicePrefix, testClusterName))},
+			expected:     true,
+		},
+		{
+			name:         "old Secret",
+			localObjects: []runtime.Object{testSecret(old.SecretName(testServicePrefix, testClusterName))},
+			expected:     true,
+		},
+		{
+			name:         "old ConfigMap of another cluster",
+			localObjects: []runtime.Object{otherCM},
+			expected:     false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := fakekubeclient.NewFakeClient(test.localObjects...)
+			pending, err := pending(c, []Scheme{old}, testScheme, testNamespace, testServicePrefix, testClusterName)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, pending)
+		})
+	}
+}
+
+func testClusterSync(s Scheme, result hiveintv1alpha1.SyncSetResult) *hiveintv1alpha1.ClusterSync {
+	clusterSync := &hiveintv1alpha1.ClusterSync{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testClusterName,
+			Namespace: testNamespace,
+		},
+	}
+	for _, name := range []string{
+		s.SyncSetName(testServicePrefix, testClusterName),
+		s.ProbeSyncSetName(testServicePrefix, testClusterName),
+		s.AlertmanagerSyncSetName(testServicePrefix, testClusterName),
+	} {
+		clusterSync.Status.SyncSets = append(clusterSync.Status.SyncSets, hiveintv1alpha1.SyncStatus{Name: name, Result: result})
+	}
+	return clusterSync
+}
+
+func testLease(name string) *coordinationv1.Lease {
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   testNamespace,
+			Annotations: map[string]string{"test": "test"},
+		},
+	}
+}
+
+func testConfigMap(name, serviceID string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels:    map[string]string{"test": "test"},
+		},
+		Data: map[string]string{
+			"SERVICE_ID":     serviceID,
+			"INTEGRATION_ID": "ABC123",
+		},
+	}
+}
+
+func testSecret(name string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+		Data: map[string][]byte{
+			"PAGERDUTY_KEY": []byte("ABC123"),
+		},
+	}
+}
+
+func testSyncSet(name string) *hivev1.SyncSet {
+	return &hivev1.SyncSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+	}
+}
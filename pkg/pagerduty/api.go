@@ -0,0 +1,318 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerduty
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
+)
+
+// apiEndpoint is the PagerDuty REST API, go-pagerduty does not export it
+const apiEndpoint = "https://api.pagerduty.com"
+
+// changeEventsEndpoint is where change events are sent
+const changeEventsEndpoint = "https://events.pagerduty.com/v2/change/enqueue"
+
+// Tag is a PagerDuty tag
+type Tag struct {
+	ID    string `json:"id,omitempty"`
+	Type  string `json:"type,omitempty"`
+	Label string `json:"label,omitempty"`
+}
+
+// AuditRecord is a PagerDuty audit record, a change made to a resource
+type AuditRecord struct {
+	ID            string             `json:"id,omitempty"`
+	ExecutionTime string             `json:"execution_time,omitempty"`
+	Action        string             `json:"action,omitempty"`
+	Actors        []pdApi.APIObject  `json:"actors,omitempty"`
+	Method        *AuditRecordMethod `json:"method,omitempty"`
+	RootResource  pdApi.APIObject    `json:"root_resource,omitempty"`
+}
+
+// AuditRecordMethod describes how the change of an AuditRecord was made
+type AuditRecordMethod struct {
+	Type           string `json:"type,omitempty"`
+	TruncatedToken string `json:"truncated_token,omitempty"`
+	Description    string `json:"description,omitempty"`
+}
+
+// Changer returns who made the change, or "" if unknown
+func (a AuditRecord) Changer() string {
+	for _, actor := range a.Actors {
+		if actor.Summary != "" {
+			return actor.Summary
+		}
+	}
+	if a.Method != nil {
+		return a.Method.Description
+	}
+	return ""
+}
+
+// ChangeEvent is a PagerDuty change event, informing responders of a change
+// without paging them
+type ChangeEvent struct {
+	RoutingKey string             `json:"routing_key"`
+	Payload    ChangeEventPayload `json:"payload"`
+}
+
+// ChangeEventPayload is the content of a ChangeEvent
+type ChangeEventPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source,omitempty"`
+	Timestamp     string            `json:"timestamp,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+// apiClient adds the API calls go-pagerduty lacks to pdApi.Client
+type apiClient struct {
+	*pdApi.Client
+	apiKey string
+	// endpoint is the PagerDuty REST API called
+	endpoint string
+}
+
+// ListServiceTags returns the tags of a service
+func (c *apiClient) ListServiceTags(serviceID string) ([]Tag, error) {
+	response := struct {
+		Tags []Tag `json:"tags"`
+	}{}
+	err := c.do(http.MethodGet, "/services/"+serviceID+"/tags?limit=100", nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Tags, nil
+}
+
+// ChangeServiceTags adds the tags with the given labels to a service, creating
+// them if needed, and removes the tags with the given IDs from it
+func (c *apiClient) ChangeServiceTags(serviceID string, add []string, remove []string) error {
+	request := struct {
+		Add    []Tag `json:"add,omitempty"`
+		Remove []Tag `json:"remove,omitempty"`
+	}{}
+	for _, label := range add {
+		request.Add = append(request.Add, Tag{Type: "tag", Label: label})
+	}
+	for _, id := range remove {
+		request.Remove = append(request.Remove, Tag{Type: "tag_reference", ID: id})
+	}
+	return c.do(http.MethodPost, "/services/"+serviceID+"/change_tags", request, nil)
+}
+
+// ListServiceAuditRecords returns the most recent audit records of a
+// service, newest first
+func (c *apiClient) ListServiceAuditRecords(serviceID string) ([]AuditRecord, error) {
+	response := struct {
+		Records []AuditRecord `json:"records"`
+	}{}
+	err := c.do(http.MethodGet, "/services/"+serviceID+"/audit/records?limit=25", nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Records, nil
+}
+
+// ListAuditRecords returns the audit records of all resources of the given
+// type changed since the given time, newest first
+func (c *apiClient) ListAuditRecords(rootResourceType string, since time.Time) ([]AuditRecord, error) {
+	query := url.Values{}
+	query.Set("root_resource_types[]", rootResourceType)
+	query.Set("since", since.UTC().Format(time.RFC3339))
+	query.Set("limit", "100")
+
+	records := []AuditRecord{}
+	for {
+		response := struct {
+			Records    []AuditRecord `json:"records"`
+			NextCursor string        `json:"next_cursor"`
+		}{}
+		err := c.do(http.MethodGet, "/audit/records?"+query.Encode(), nil, &response)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, response.Records...)
+		if response.NextCursor == "" {
+			return records, nil
+		}
+		query.Set("cursor", response.NextCursor)
+	}
+}
+
+// IncidentMetrics are the incident analytics of a service
+type IncidentMetrics struct {
+	ServiceID          string `json:"service_id"`
+	TotalIncidentCount int    `json:"total_incident_count"`
+}
+
+// ListServiceIncidentMetrics returns the incident analytics of the given
+// services for the incidents created between since and until. Services
+// without incidents in that period may be left out.
+func (c *apiClient) ListServiceIncidentMetrics(serviceIDs []string, since, until time.Time) ([]IncidentMetrics, error) {
+	request := struct {
+		Filters struct {
+			CreatedAtStart string   `json:"created_at_start"`
+			CreatedAtEnd   string   `json:"created_at_end"`
+			ServiceIDs     []string `json:"service_ids"`
+		} `json:"filters"`
+	}{}
+	request.Filters.CreatedAtStart = since.UTC().Format(time.RFC3339)
+	request.Filters.CreatedAtEnd = until.UTC().Format(time.RFC3339)
+	request.Filters.ServiceIDs = serviceIDs
+
+	response := struct {
+		Data []IncidentMetrics `json:"data"`
+	}{}
+	err := c.do(http.MethodPost, "/analytics/metrics/incidents/services", request, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// AlertGrouping is how PagerDuty groups the alerts of a service into
+// incidents, its alert_grouping_parameters
+type AlertGrouping struct {
+	Type   string               `json:"type"`
+	Config *AlertGroupingConfig `json:"config,omitempty"`
+}
+
+// AlertGroupingConfig are the settings of the type of an AlertGrouping
+type AlertGroupingConfig struct {
+	Timeout   *uint    `json:"timeout,omitempty"`
+	Aggregate string   `json:"aggregate,omitempty"`
+	Fields    []string `json:"fields,omitempty"`
+}
+
+// GetServiceAlertGrouping returns how the alerts of a service are grouped,
+// or nil if they aren't
+func (c *apiClient) GetServiceAlertGrouping(serviceID string) (*AlertGrouping, error) {
+	response := struct {
+		Service struct {
+			AlertGroupingParameters *AlertGrouping `json:"alert_grouping_parameters"`
+		} `json:"service"`
+	}{}
+	err := c.do(http.MethodGet, "/services/"+serviceID, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Service.AlertGroupingParameters, nil
+}
+
+// UpdateServiceAlertGrouping sets how the alerts of a service are grouped,
+// leaving the rest of the service alone
+func (c *apiClient) UpdateServiceAlertGrouping(serviceID string, grouping *AlertGrouping) error {
+	request := struct {
+		Service struct {
+			AlertGroupingParameters *AlertGrouping `json:"alert_grouping_parameters"`
+		} `json:"service"`
+	}{}
+	request.Service.AlertGroupingParameters = grouping
+	return c.do(http.MethodPut, "/services/"+serviceID, request, nil)
+}
+
+// ServiceDependency relates a PagerDuty service to a service it depends on
+type ServiceDependency struct {
+	ID                string          `json:"id,omitempty"`
+	SupportingService pdApi.APIObject `json:"supporting_service"`
+	DependentService  pdApi.APIObject `json:"dependent_service"`
+}
+
+// ListServiceDependencies returns the dependencies of a technical service,
+// both on the services it depends on and of those depending on it
+func (c *apiClient) ListServiceDependencies(serviceID string) ([]ServiceDependency, error) {
+	response := struct {
+		Relationships []ServiceDependency `json:"relationships"`
+	}{}
+	err := c.do(http.MethodGet, "/service_dependencies/technical_services/"+serviceID, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.Relationships, nil
+}
+
+// AssociateServiceDependencies registers dependencies between services
+func (c *apiClient) AssociateServiceDependencies(dependencies []ServiceDependency) error {
+	request := struct {
+		Relationships []ServiceDependency `json:"relationships"`
+	}{Relationships: dependencies}
+	return c.do(http.MethodPost, "/service_dependencies/associate", request, nil)
+}
+
+// sendChangeEvent sends a change event to the events API
+func sendChangeEvent(event ChangeEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(changeEventsEndpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		message, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("Failed to send change event. HTTP response code: %v. Error: %s", resp.StatusCode, logging.Redact(string(message)))
+	}
+	return nil
+}
+
+func (c *apiClient) do(method string, path string, body interface{}, v interface{}) error {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.endpoint+path, payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Token token="+c.apiKey)
+
+	resp, err := c.Client.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// same message as go-pagerduty, so errors are recognized alike
+		message, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("Failed call API endpoint. HTTP response code: %v. Error: %s", resp.StatusCode, logging.Redact(string(message)))
+	}
+
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,68 @@
+package pagerduty_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	s "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+)
+
+// The stable API of the package, see doc.go. A change breaking any of these
+// breaks the operators embedding the package and fails to compile here.
+var (
+	_ func(string, string, ...s.ClientOption) s.Client = s.NewClient
+	_ s.ClientOption                                   = s.WithLogger(nil)
+	_ func(s.APICallObserver) s.ClientOption           = s.WithMetrics
+	_ func(s.RateLimiter) s.ClientOption               = s.WithRateLimiter
+	_ func(pdApi.HTTPClient) s.ClientOption            = s.WithHTTPClient
+
+	_ func(error) bool          = s.IsRateLimited
+	_ func(error) bool          = s.IsNotFound
+	_ func(error) bool          = s.IsAccountQuotaExceeded
+	_ func(error) time.Duration = s.RateLimitRetryAfter
+	_ error                     = &s.RateLimitError{RetryAfter: time.Second}
+	_ error                     = &s.AccountQuotaExceededError{Err: nil}
+
+	_ func(*s.Data) string = s.ServiceName
+	_ func(string) string  = s.NormalizeServiceName
+	_ int                  = s.MaxServiceNameLength
+
+	_ func(time.Duration) func(*http.Request) error = s.ReadinessCheck
+
+	_ s.Client = &s.SvcClient{}
+)
+
+// stableClient lists the methods of Client other operators rely on
+type stableClient interface {
+	GetService(data *s.Data) (*pdApi.Service, error)
+	GetIntegrationKey(data *s.Data) (string, error)
+	CreateService(data *s.Data) (string, error)
+	DeleteService(data *s.Data) error
+	CreateMaintenanceWindow(data *s.Data, description string, start time.Time, end time.Time) (string, error)
+	DeleteMaintenanceWindow(id string) error
+	ValidateReferences(refs s.References) ([]string, error)
+	SendTestAlert(integrationKey string, clusterID string) (s.TestAlertResult, error)
+}
+
+func TestStableAPI(t *testing.T) {
+	var client s.Client = &s.SvcClient{}
+	if _, ok := client.(stableClient); !ok {
+		t.Error("Client lost methods of its stable API")
+	}
+
+	// fields other operators set
+	_ = s.Data{
+		EscalationPolicyID: "",
+		AutoResolveTimeout: 0,
+		AcknowledgeTimeOut: 0,
+		ServicePrefix:      "",
+		APIKey:             "",
+		ClusterID:          "",
+		BaseDomain:         "",
+		ServiceID:          "",
+		IntegrationID:      "",
+	}
+	_ = s.References{EscalationPolicyID: "", RulesetIDs: nil}
+}
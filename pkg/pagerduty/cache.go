@@ -0,0 +1,180 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerduty
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+)
+
+// listCacheTTL is how long a page of services listed from PagerDuty is
+// reused. Changes the operator makes to services drop the cached pages
+// right away, so this only bounds how late changes made outside of it show.
+const listCacheTTL = time.Minute
+
+// listCaches pools the caches of the service pages listed by the clients
+// made by NewClient. The controllers make a client for every reconcile,
+// sharing the cache of its API key spares PagerDuty the same listing for
+// every PagerDutyIntegration and cluster of a fleet-wide reconcile.
+var listCaches = &listCachePool{}
+
+type listCachePool struct {
+	mu     sync.Mutex
+	caches map[string]*listCache
+}
+
+// get returns the cache of apiKey, making it on the first call
+func (p *listCachePool) get(apiKey string) *listCache {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cache, ok := p.caches[apiKey]
+	if !ok {
+		if p.caches == nil {
+			p.caches = map[string]*listCache{}
+		}
+		cache = &listCache{ttl: listCacheTTL}
+		p.caches[apiKey] = cache
+	}
+	return cache
+}
+
+// listCache holds the pages of services listed from PagerDuty until they
+// expire
+type listCache struct {
+	ttl time.Duration
+	// now returns the current time, time.Now if nil
+	now func() time.Time
+
+	mu    sync.Mutex
+	pages map[string]listCacheEntry
+}
+
+type listCacheEntry struct {
+	page    *pdApi.ListServiceResponse
+	expires time.Time
+}
+
+func (c *listCache) time() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// get returns the cached page listed with key, or nil
+func (c *listCache) get(key string) *pdApi.ListServiceResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.pages[key]
+	if !ok || !c.time().Before(entry.expires) {
+		return nil
+	}
+	return entry.page
+}
+
+// put caches the page listed with key
+func (c *listCache) put(key string, page *pdApi.ListServiceResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.pages == nil {
+		c.pages = map[string]listCacheEntry{}
+	}
+	// expired pages are dropped as new ones come in, keeping the cache to
+	// the listings in use
+	now := c.time()
+	for k, entry := range c.pages {
+		if !now.Before(entry.expires) {
+			delete(c.pages, k)
+		}
+	}
+	c.pages[key] = listCacheEntry{page: page, expires: now.Add(c.ttl)}
+}
+
+// invalidate drops every cached page
+func (c *listCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pages = nil
+}
+
+// cachingPdClient reuses the pages of services listed through it, and drops
+// them whenever a service or integration is changed through it
+type cachingPdClient struct {
+	PdClient
+	cache *listCache
+}
+
+// ListServices returns the cached page listed with the same options, or
+// lists it
+func (c *cachingPdClient) ListServices(o pdApi.ListServiceOptions) (*pdApi.ListServiceResponse, error) {
+	key := fmt.Sprintf("%+v", o)
+	page := c.cache.get(key)
+	apiStats.cacheLookup(page != nil)
+	if page != nil {
+		return copyServicePage(page), nil
+	}
+
+	page, err := c.PdClient.ListServices(o)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.put(key, page)
+	return copyServicePage(page), nil
+}
+
+// copyServicePage copies page so callers can't change the cached one
+func copyServicePage(page *pdApi.ListServiceResponse) *pdApi.ListServiceResponse {
+	copied := *page
+	copied.Services = append([]pdApi.Service(nil), page.Services...)
+	return &copied
+}
+
+func (c *cachingPdClient) CreateService(service pdApi.Service) (*pdApi.Service, error) {
+	// even a failed create may mean the service exists, as when its name
+	// is already taken
+	defer c.cache.invalidate()
+	return c.PdClient.CreateService(service)
+}
+
+func (c *cachingPdClient) UpdateService(service pdApi.Service) (*pdApi.Service, error) {
+	defer c.cache.invalidate()
+	return c.PdClient.UpdateService(service)
+}
+
+func (c *cachingPdClient) DeleteService(id string) error {
+	defer c.cache.invalidate()
+	return c.PdClient.DeleteService(id)
+}
+
+func (c *cachingPdClient) CreateIntegration(serviceID string, integration pdApi.Integration) (*pdApi.Integration, error) {
+	defer c.cache.invalidate()
+	return c.PdClient.CreateIntegration(serviceID, integration)
+}
+
+func (c *cachingPdClient) DeleteIntegration(serviceID string, integrationID string) error {
+	defer c.cache.invalidate()
+	return c.PdClient.DeleteIntegration(serviceID, integrationID)
+}
+
+func (c *cachingPdClient) UpdateServiceAlertGrouping(serviceID string, grouping *AlertGrouping) error {
+	defer c.cache.invalidate()
+	return c.PdClient.UpdateServiceAlertGrouping(serviceID, grouping)
+}
@@ -0,0 +1,80 @@
+package pagerduty
+
+import (
+	"testing"
+	"time"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"gotest.tools/assert"
+)
+
+// countingPdClient lists one page of services per offset and counts the
+// pages listed
+type countingPdClient struct {
+	PdClient
+	listed int
+}
+
+func (c *countingPdClient) ListServices(o pdApi.ListServiceOptions) (*pdApi.ListServiceResponse, error) {
+	c.listed++
+	if o.Offset == 0 {
+		return &pdApi.ListServiceResponse{
+			APIListObject: pdApi.APIListObject{More: true},
+			Services: []pdApi.Service{{
+				APIObject:    pdApi.APIObject{ID: "first"},
+				Integrations: []pdApi.Integration{{APIObject: pdApi.APIObject{ID: "first-integration"}}},
+			}},
+		}, nil
+	}
+	return &pdApi.ListServiceResponse{Services: []pdApi.Service{{APIObject: pdApi.APIObject{ID: "second"}}}}, nil
+}
+
+func (c *countingPdClient) CreateService(service pdApi.Service) (*pdApi.Service, error) {
+	return &service, nil
+}
+
+func TestListServicesCached(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	counting := &countingPdClient{}
+	cache := &listCache{ttl: time.Minute, now: func() time.Time { return now }}
+	c := &SvcClient{PdClient: &cachingPdClient{PdClient: counting, cache: cache}}
+
+	// every page is listed once
+	services, err := c.ListServices("prefix")
+	assert.NilError(t, err)
+	assert.Equal(t, len(services), 2)
+	assert.Equal(t, counting.listed, 2)
+
+	integrations, err := c.ListIntegrations("prefix")
+	assert.NilError(t, err)
+	assert.Equal(t, counting.listed, 2)
+	assert.Equal(t, len(integrations), 1)
+	assert.Equal(t, integrations[0].Service.ID, "first")
+
+	// another query is listed on its own
+	_, err = c.ListServices("other")
+	assert.NilError(t, err)
+	assert.Equal(t, counting.listed, 4)
+
+	// creating a service drops the cached pages
+	_, err = c.PdClient.CreateService(pdApi.Service{})
+	assert.NilError(t, err)
+	_, err = c.ListServices("prefix")
+	assert.NilError(t, err)
+	assert.Equal(t, counting.listed, 6)
+
+	// as does their expiry
+	now = now.Add(time.Minute)
+	_, err = c.ListServices("prefix")
+	assert.NilError(t, err)
+	assert.Equal(t, counting.listed, 8)
+}
+
+func TestNewClientSharesListCache(t *testing.T) {
+	first := NewClient("test-key", "test-controller").(*SvcClient).PdClient.(*cachingPdClient).cache
+	second := NewClient("test-key", "other-controller").(*SvcClient).PdClient.(*cachingPdClient).cache
+	other := NewClient("other-key", "test-controller").(*SvcClient).PdClient.(*cachingPdClient).cache
+
+	assert.Assert(t, first == second)
+	assert.Assert(t, first != other)
+}
@@ -0,0 +1,37 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pagerduty manages the PagerDuty services of clusters through the
+// PagerDuty REST and events APIs. Other operators embed it, so it is a
+// library API with compatibility guarantees:
+//
+// The stable API is NewClient and its ClientOptions, the Client interface,
+// Data and References, the results returned by Client (TestAlertResult,
+// AuditRecord, Tag), the error types and predicates (RateLimitError,
+// AccountQuotaExceededError, IsRateLimited, IsNotFound,
+// IsAccountQuotaExceeded), the service naming functions (ServiceName,
+// NormalizeServiceName, MaxServiceNameLength) and ReadinessCheck.
+//
+// Within a major version of the operator none of these is removed, renamed
+// or changes signature; fields are only added to structs, and options are
+// added as new ClientOptions rather than new NewClient parameters. Methods
+// may be added to Client, so implementations outside of this package, such
+// as test doubles, should embed a Client to keep building. Breaking changes
+// are listed in the release notes and api_test.go guards against accidental
+// ones.
+//
+// SvcClient, PdClient and the mock and fake packages are the implementation
+// and are not covered; they change with the PagerDuty API calls the operator
+// needs.
+package pagerduty
@@ -0,0 +1,736 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fake is an in-memory PagerDuty REST API serving the subset of
+// endpoints the operator calls, so the e2e and envtest suites exercise the
+// real client, pagination, error handling and rate limiting included,
+// instead of a mock of the Client interface. Point a client at it with
+// pagerduty.WithAPIEndpoint(server.URL).
+//
+// It is a test double, not an emulation of PagerDuty: objects are stored as
+// the JSON they are created with, updates replace their top-level fields,
+// and only the query parameters the operator sends are honored.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// object is a PagerDuty resource as the JSON it is served as
+type object map[string]interface{}
+
+// failure is an error the server answers matching calls with
+type failure struct {
+	method     string
+	pathPrefix string
+	statusCode int
+	// times is how many more calls get the error, forever if negative
+	times int
+}
+
+// Server is an in-memory PagerDuty REST API. The zero value is not usable,
+// make one with NewServer and Close it once done.
+type Server struct {
+	*httptest.Server
+
+	// APIKey is the only API key accepted, any key if empty
+	APIKey string
+
+	mu                 sync.Mutex
+	nextID             int
+	services           map[string]object
+	escalationPolicies map[string]object
+	teams              map[string]object
+	vendors            map[string]object
+	incidents          map[string]object
+	maintenanceWindows map[string]object
+	rulesets           map[string]object
+	rules              map[string][]object
+	tags               map[string][]object
+	dependencies       []object
+	failures           []failure
+	rateLimited        int
+	retryAfter         int
+	calls              map[string]int
+}
+
+// NewServer starts a server accepting apiKey, any key if empty
+func NewServer(apiKey string) *Server {
+	s := &Server{
+		APIKey:             apiKey,
+		services:           map[string]object{},
+		escalationPolicies: map[string]object{},
+		teams:              map[string]object{},
+		vendors:            map[string]object{},
+		incidents:          map[string]object{},
+		maintenanceWindows: map[string]object{},
+		rulesets:           map[string]object{},
+		rules:              map[string][]object{},
+		tags:               map[string][]object{},
+		calls:              map[string]int{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// AddEscalationPolicy makes an escalation policy services can be created
+// with
+func (s *Server) AddEscalationPolicy(id, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.escalationPolicies[id] = object{"id": id, "type": "escalation_policy", "name": name, "summary": name}
+}
+
+// AddTeam makes a team services can be assigned to
+func (s *Server) AddTeam(id, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.teams[id] = object{"id": id, "type": "team", "name": name, "summary": name}
+}
+
+// AddVendor makes a vendor integrations can be created with, such as
+// Prometheus
+func (s *Server) AddVendor(id, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.vendors[id] = object{"id": id, "type": "vendor", "name": name, "summary": name}
+}
+
+// AddRuleset makes a global ruleset rules can be added to
+func (s *Server) AddRuleset(id, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rulesets[id] = object{"id": id, "type": "global_ruleset", "name": name}
+}
+
+// AddIncident opens an incident on the service serviceID and returns its ID.
+// Its alerts are not tracked, the incident lists none.
+func (s *Server) AddIncident(serviceID, title string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.newID("Q")
+	s.incidents[id] = object{
+		"id":           id,
+		"type":         "incident",
+		"title":        title,
+		"status":       "triggered",
+		"service":      object{"id": serviceID, "type": "service_reference"},
+		"alert_counts": object{"triggered": 0, "resolved": 0, "all": 0},
+	}
+	return id
+}
+
+// Service returns the service serviceID as it is served, with its
+// integrations, and whether it exists
+func (s *Server) Service(serviceID string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	service, ok := s.services[serviceID]
+	return copyObject(service), ok
+}
+
+// ServiceIDs returns the IDs of the services, sorted
+func (s *Server) ServiceIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := []string{}
+	for id := range s.services {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Fail answers the next times calls whose method is method and whose path
+// starts with pathPrefix with statusCode, all of them if times is negative.
+// An empty method matches any method.
+func (s *Server) Fail(method, pathPrefix string, statusCode int, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{method: method, pathPrefix: pathPrefix, statusCode: statusCode, times: times})
+}
+
+// RateLimit answers the next times calls with 429 Too Many Requests, telling
+// to retry after retryAfter seconds
+func (s *Server) RateLimit(times int, retryAfter int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimited = times
+	s.retryAfter = retryAfter
+}
+
+// Calls returns how many calls were made to method path, rate limited and
+// failed calls included. The path excludes the query.
+func (s *Server) Calls(method, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method+" "+path]
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[req.Method+" "+req.URL.Path]++
+
+	if s.APIKey != "" && req.Header.Get("Authorization") != "Token token="+s.APIKey {
+		writeError(w, http.StatusUnauthorized, 2006, "Unauthorized")
+		return
+	}
+	if s.rateLimited > 0 {
+		s.rateLimited--
+		w.Header().Set("Retry-After", strconv.Itoa(s.retryAfter))
+		writeError(w, http.StatusTooManyRequests, 2020, "Rate Limit Exceeded")
+		return
+	}
+	if statusCode := s.injectedFailure(req); statusCode != 0 {
+		writeError(w, statusCode, 0, http.StatusText(statusCode))
+		return
+	}
+
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	route := req.Method + " " + routePattern(segments)
+	switch route {
+	case "GET /services":
+		s.listServices(w, req)
+	case "POST /services":
+		s.createService(w, req)
+	case "GET /services/{id}":
+		s.get(w, s.services, segments[1], "service")
+	case "PUT /services/{id}":
+		s.updateService(w, req, segments[1])
+	case "DELETE /services/{id}":
+		s.deleteService(w, segments[1])
+	case "POST /services/{id}/integrations":
+		s.createIntegration(w, req, segments[1])
+	case "GET /services/{id}/integrations/{id}":
+		s.getIntegration(w, segments[1], segments[3])
+	case "DELETE /services/{id}/integrations/{id}":
+		s.deleteIntegration(w, segments[1], segments[3])
+	case "GET /services/{id}/tags":
+		s.listTags(w, segments[1])
+	case "POST /services/{id}/change_tags":
+		s.changeTags(w, req, segments[1])
+	case "GET /services/{id}/audit/records", "GET /audit/records":
+		writeJSON(w, http.StatusOK, object{"records": []object{}})
+	case "GET /escalation_policies/{id}":
+		s.get(w, s.escalationPolicies, segments[1], "escalation_policy")
+	case "GET /teams":
+		s.list(w, req, s.teams, "teams")
+	case "GET /teams/{id}":
+		s.get(w, s.teams, segments[1], "team")
+	case "GET /vendors":
+		s.list(w, req, s.vendors, "vendors")
+	case "GET /incidents":
+		s.listIncidents(w, req)
+	case "GET /incidents/{id}/alerts":
+		writeJSON(w, http.StatusOK, object{"alerts": []object{}})
+	case "POST /maintenance_windows":
+		s.createMaintenanceWindow(w, req)
+	case "DELETE /maintenance_windows/{id}":
+		s.delete(w, s.maintenanceWindows, segments[1])
+	case "GET /rulesets/{id}":
+		s.get(w, s.rulesets, segments[1], "ruleset")
+	case "GET /rulesets/{id}/rules":
+		s.listRules(w, segments[1])
+	case "POST /rulesets/{id}/rules":
+		s.createRule(w, req, segments[1])
+	case "PUT /rulesets/{id}/rules/{id}":
+		s.updateRule(w, req, segments[1], segments[3])
+	case "DELETE /rulesets/{id}/rules/{id}":
+		s.deleteRule(w, segments[1], segments[3])
+	case "GET /service_dependencies/technical_services/{id}":
+		s.listDependencies(w, segments[2])
+	case "POST /service_dependencies/associate":
+		s.associateDependencies(w, req)
+	case "POST /analytics/metrics/incidents/services":
+		writeJSON(w, http.StatusOK, object{"data": []object{}})
+	default:
+		writeError(w, http.StatusNotFound, 2100, "Not Found")
+	}
+}
+
+// injectedFailure returns the status code of the first failure set by Fail
+// matching req, 0 if none does
+func (s *Server) injectedFailure(req *http.Request) int {
+	for i := range s.failures {
+		f := &s.failures[i]
+		if f.times == 0 || (f.method != "" && f.method != req.Method) || !strings.HasPrefix(req.URL.Path, f.pathPrefix) {
+			continue
+		}
+		if f.times > 0 {
+			f.times--
+		}
+		return f.statusCode
+	}
+	return 0
+}
+
+// routePattern replaces the IDs of a path with {id}, the odd segments of
+// collection/{id}/collection/{id} paths
+func routePattern(segments []string) string {
+	pattern := make([]string, len(segments))
+	for i, segment := range segments {
+		pattern[i] = segment
+		if i%2 == 1 && !isCollection(segment) {
+			pattern[i] = "{id}"
+		}
+	}
+	// service_dependencies/technical_services/{id} nests two collections
+	if len(segments) == 3 && segments[0] == "service_dependencies" && segments[1] == "technical_services" {
+		pattern[2] = "{id}"
+	}
+	return "/" + strings.Join(pattern, "/")
+}
+
+// isCollection returns true for the path segments naming a collection or an
+// action where an ID could be expected
+func isCollection(segment string) bool {
+	switch segment {
+	case "records", "technical_services", "associate", "metrics", "services":
+		return true
+	}
+	return false
+}
+
+func (s *Server) listServices(w http.ResponseWriter, req *http.Request) {
+	query := strings.ToLower(req.URL.Query().Get("query"))
+	includeIntegrations := false
+	for _, include := range req.URL.Query()["include[]"] {
+		includeIntegrations = includeIntegrations || include == "integrations"
+	}
+
+	services := []object{}
+	for _, service := range s.services {
+		if !strings.Contains(strings.ToLower(service["name"].(string)), query) {
+			continue
+		}
+		service = copyObject(service)
+		if !includeIntegrations {
+			service["integrations"] = references(service["integrations"])
+		}
+		services = append(services, service)
+	}
+	writePage(w, req, "services", services)
+}
+
+func (s *Server) createService(w http.ResponseWriter, req *http.Request) {
+	service, ok := decode(w, req, "service")
+	if !ok {
+		return
+	}
+	name, _ := service["name"].(string)
+	if name == "" {
+		writeError(w, http.StatusBadRequest, 2001, "Invalid Input Provided", "Name can't be blank.")
+		return
+	}
+	for _, existing := range s.services {
+		if strings.EqualFold(existing["name"].(string), name) {
+			writeError(w, http.StatusBadRequest, 2001, "Invalid Input Provided", "Name has already been taken.")
+			return
+		}
+	}
+	policy, _ := service["escalation_policy"].(map[string]interface{})
+	if policy == nil || s.escalationPolicies[fmt.Sprint(policy["id"])] == nil {
+		writeError(w, http.StatusBadRequest, 2001, "Invalid Input Provided", "Escalation policy must be specified.")
+		return
+	}
+
+	id := s.newID("P")
+	service["id"] = id
+	service["type"] = "service"
+	service["summary"] = name
+	if _, ok := service["status"]; !ok {
+		service["status"] = "active"
+	}
+	service["integrations"] = []interface{}{}
+	s.services[id] = service
+	writeJSON(w, http.StatusCreated, object{"service": service})
+}
+
+func (s *Server) updateService(w http.ResponseWriter, req *http.Request, id string) {
+	service, ok := s.services[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, 2100, "Not Found")
+		return
+	}
+	update, ok := decode(w, req, "service")
+	if !ok {
+		return
+	}
+	for key, value := range update {
+		switch key {
+		case "id", "type", "integrations":
+			// not updatable
+		default:
+			service[key] = value
+		}
+	}
+	writeJSON(w, http.StatusOK, object{"service": service})
+}
+
+func (s *Server) deleteService(w http.ResponseWriter, id string) {
+	if _, ok := s.services[id]; !ok {
+		writeError(w, http.StatusNotFound, 2100, "Not Found")
+		return
+	}
+	delete(s.services, id)
+	delete(s.tags, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) createIntegration(w http.ResponseWriter, req *http.Request, serviceID string) {
+	service, ok := s.services[serviceID]
+	if !ok {
+		writeError(w, http.StatusNotFound, 2100, "Not Found")
+		return
+	}
+	integration, ok := decode(w, req, "integration")
+	if !ok {
+		return
+	}
+
+	id := s.newID("I")
+	integration["id"] = id
+	integration["summary"] = integration["name"]
+	integration["service"] = object{"id": serviceID, "type": "service_reference"}
+	integration["integration_key"] = fmt.Sprintf("%032x", s.nextID)
+	service["integrations"] = append(service["integrations"].([]interface{}), integration)
+	writeJSON(w, http.StatusCreated, object{"integration": integration})
+}
+
+func (s *Server) getIntegration(w http.ResponseWriter, serviceID, id string) {
+	if service, ok := s.services[serviceID]; ok {
+		for _, integration := range service["integrations"].([]interface{}) {
+			if integration.(object)["id"] == id {
+				writeJSON(w, http.StatusOK, object{"integration": integration})
+				return
+			}
+		}
+	}
+	writeError(w, http.StatusNotFound, 2100, "Not Found")
+}
+
+func (s *Server) deleteIntegration(w http.ResponseWriter, serviceID, id string) {
+	if service, ok := s.services[serviceID]; ok {
+		integrations := service["integrations"].([]interface{})
+		for i, integration := range integrations {
+			if integration.(object)["id"] == id {
+				service["integrations"] = append(integrations[:i:i], integrations[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+	}
+	writeError(w, http.StatusNotFound, 2100, "Not Found")
+}
+
+func (s *Server) listTags(w http.ResponseWriter, serviceID string) {
+	if _, ok := s.services[serviceID]; !ok {
+		writeError(w, http.StatusNotFound, 2100, "Not Found")
+		return
+	}
+	tags := s.tags[serviceID]
+	if tags == nil {
+		tags = []object{}
+	}
+	writeJSON(w, http.StatusOK, object{"tags": tags})
+}
+
+func (s *Server) changeTags(w http.ResponseWriter, req *http.Request, serviceID string) {
+	if _, ok := s.services[serviceID]; !ok {
+		writeError(w, http.StatusNotFound, 2100, "Not Found")
+		return
+	}
+	change := struct {
+		Add    []object `json:"add"`
+		Remove []object `json:"remove"`
+	}{}
+	if err := json.NewDecoder(req.Body).Decode(&change); err != nil {
+		writeError(w, http.StatusBadRequest, 2001, "Invalid Input Provided", err.Error())
+		return
+	}
+
+	tags := []object{}
+	for _, tag := range s.tags[serviceID] {
+		removed := false
+		for _, remove := range change.Remove {
+			removed = removed || remove["id"] == tag["id"]
+		}
+		if !removed {
+			tags = append(tags, tag)
+		}
+	}
+	for _, add := range change.Add {
+		tags = append(tags, object{"id": s.newID("T"), "type": "tag", "label": add["label"]})
+	}
+	s.tags[serviceID] = tags
+	writeJSON(w, http.StatusOK, object{})
+}
+
+func (s *Server) listIncidents(w http.ResponseWriter, req *http.Request) {
+	serviceIDs := req.URL.Query()["service_ids[]"]
+	statuses := req.URL.Query()["statuses[]"]
+
+	incidents := []object{}
+	for _, incident := range s.incidents {
+		serviceID := incident["service"].(object)["id"].(string)
+		if len(serviceIDs) > 0 && !contains(serviceIDs, serviceID) {
+			continue
+		}
+		if len(statuses) > 0 && !contains(statuses, incident["status"].(string)) {
+			continue
+		}
+		incidents = append(incidents, copyObject(incident))
+	}
+	writePage(w, req, "incidents", incidents)
+}
+
+func (s *Server) createMaintenanceWindow(w http.ResponseWriter, req *http.Request) {
+	window, ok := decode(w, req, "maintenance_window")
+	if !ok {
+		return
+	}
+	id := s.newID("M")
+	window["id"] = id
+	window["type"] = "maintenance_window"
+	s.maintenanceWindows[id] = window
+	writeJSON(w, http.StatusCreated, object{"maintenance_window": window})
+}
+
+func (s *Server) listRules(w http.ResponseWriter, rulesetID string) {
+	if _, ok := s.rulesets[rulesetID]; !ok {
+		writeError(w, http.StatusNotFound, 2100, "Not Found")
+		return
+	}
+	rules := s.rules[rulesetID]
+	if rules == nil {
+		rules = []object{}
+	}
+	writeJSON(w, http.StatusOK, object{"rules": rules, "limit": len(rules), "offset": 0, "more": false, "total": len(rules)})
+}
+
+func (s *Server) createRule(w http.ResponseWriter, req *http.Request, rulesetID string) {
+	if _, ok := s.rulesets[rulesetID]; !ok {
+		writeError(w, http.StatusNotFound, 2100, "Not Found")
+		return
+	}
+	rule, ok := decode(w, req, "rule")
+	if !ok {
+		return
+	}
+	rule["id"] = s.newID("R")
+	s.rules[rulesetID] = append(s.rules[rulesetID], rule)
+	writeJSON(w, http.StatusCreated, object{"rule": rule})
+}
+
+func (s *Server) updateRule(w http.ResponseWriter, req *http.Request, rulesetID, id string) {
+	update, ok := decode(w, req, "rule")
+	if !ok {
+		return
+	}
+	for _, rule := range s.rules[rulesetID] {
+		if rule["id"] == id {
+			for key, value := range update {
+				if key != "id" {
+					rule[key] = value
+				}
+			}
+			writeJSON(w, http.StatusOK, object{"rule": rule})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, 2100, "Not Found")
+}
+
+func (s *Server) deleteRule(w http.ResponseWriter, rulesetID, id string) {
+	rules := s.rules[rulesetID]
+	for i, rule := range rules {
+		if rule["id"] == id {
+			s.rules[rulesetID] = append(rules[:i:i], rules[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, 2100, "Not Found")
+}
+
+func (s *Server) listDependencies(w http.ResponseWriter, serviceID string) {
+	relationships := []object{}
+	for _, dependency := range s.dependencies {
+		supporting := dependency["supporting_service"].(map[string]interface{})
+		dependent := dependency["dependent_service"].(map[string]interface{})
+		if supporting["id"] == serviceID || dependent["id"] == serviceID {
+			relationships = append(relationships, dependency)
+		}
+	}
+	writeJSON(w, http.StatusOK, object{"relationships": relationships})
+}
+
+func (s *Server) associateDependencies(w http.ResponseWriter, req *http.Request) {
+	request := struct {
+		Relationships []object `json:"relationships"`
+	}{}
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, 2001, "Invalid Input Provided", err.Error())
+		return
+	}
+	for _, relationship := range request.Relationships {
+		relationship["id"] = s.newID("D")
+		s.dependencies = append(s.dependencies, relationship)
+	}
+	writeJSON(w, http.StatusOK, object{"relationships": request.Relationships})
+}
+
+// get writes the object id of objects under the key singular
+func (s *Server) get(w http.ResponseWriter, objects map[string]object, id, singular string) {
+	o, ok := objects[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, 2100, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, object{singular: o})
+}
+
+// list writes a page of the objects whose name contains the query under the
+// key plural
+func (s *Server) list(w http.ResponseWriter, req *http.Request, objects map[string]object, plural string) {
+	query := strings.ToLower(req.URL.Query().Get("query"))
+	listed := []object{}
+	for _, o := range objects {
+		if strings.Contains(strings.ToLower(fmt.Sprint(o["name"])), query) {
+			listed = append(listed, o)
+		}
+	}
+	writePage(w, req, plural, listed)
+}
+
+// delete deletes the object id of objects
+func (s *Server) delete(w http.ResponseWriter, objects map[string]object, id string) {
+	if _, ok := objects[id]; !ok {
+		writeError(w, http.StatusNotFound, 2100, "Not Found")
+		return
+	}
+	delete(objects, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// newID returns a new ID starting with prefix, PagerDuty IDs being
+// upper case
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s%06d", prefix, s.nextID)
+}
+
+// decode returns the object under key in the body of req, writing the error
+// if there is none
+func decode(w http.ResponseWriter, req *http.Request, key string) (object, bool) {
+	body := map[string]object{}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body[key] == nil {
+		writeError(w, http.StatusBadRequest, 2001, "Invalid Input Provided", fmt.Sprintf("%s is required", key))
+		return nil, false
+	}
+	return body[key], true
+}
+
+// writePage writes the page of objects, sorted by name then ID, the limit
+// and offset query parameters ask for, as PagerDuty paginates with limit,
+// offset and more
+func writePage(w http.ResponseWriter, req *http.Request, key string, objects []object) {
+	sort.Slice(objects, func(i, j int) bool {
+		ni, nj := fmt.Sprint(objects[i]["name"]), fmt.Sprint(objects[j]["name"])
+		if ni != nj {
+			return ni < nj
+		}
+		return fmt.Sprint(objects[i]["id"]) < fmt.Sprint(objects[j]["id"])
+	})
+
+	limit, err := strconv.Atoi(req.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 25
+	}
+	offset, err := strconv.Atoi(req.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	if offset > len(objects) {
+		offset = len(objects)
+	}
+	end := offset + limit
+	if end > len(objects) {
+		end = len(objects)
+	}
+
+	writeJSON(w, http.StatusOK, object{
+		key:      objects[offset:end],
+		"limit":  limit,
+		"offset": offset,
+		"more":   end < len(objects),
+		"total":  len(objects),
+	})
+}
+
+// writeError writes an error in the format of the PagerDuty API
+func writeError(w http.ResponseWriter, statusCode int, code int, message string, errors ...string) {
+	writeJSON(w, statusCode, object{"error": object{"code": code, "message": message, "errors": errors}})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// references returns the integrations as references, as PagerDuty lists
+// them without include[]=integrations
+func references(integrations interface{}) []interface{} {
+	refs := []interface{}{}
+	list, _ := integrations.([]interface{})
+	for _, integration := range list {
+		integration := integration.(object)
+		refs = append(refs, object{"id": integration["id"], "type": fmt.Sprint(integration["type"]) + "_reference"})
+	}
+	return refs
+}
+
+// copyObject returns a copy of o and of its integrations, so callers can't
+// change what the server holds
+func copyObject(o object) object {
+	if o == nil {
+		return nil
+	}
+	c := object{}
+	for key, value := range o {
+		c[key] = value
+	}
+	if integrations, ok := o["integrations"].([]interface{}); ok {
+		c["integrations"] = append([]interface{}{}, integrations...)
+	}
+	return c
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
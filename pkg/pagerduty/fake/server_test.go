@@ -0,0 +1,120 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"gotest.tools/assert"
+)
+
+const testEscalationPolicyID = "PEP0001"
+
+// newTestClient returns a server and a client calling it. Each test gets its
+// own API key, the clients made by NewClient share their listed pages per key.
+func newTestClient(t *testing.T) (*Server, pd.Client) {
+	apiKey := "fake-" + t.Name()
+	server := NewServer(apiKey)
+	t.Cleanup(server.Close)
+	server.AddEscalationPolicy(testEscalationPolicyID, "test policy")
+	return server, pd.NewClient(apiKey, "test-controller", pd.WithAPIEndpoint(server.URL))
+}
+
+func testData(clusterID string) *pd.Data {
+	return &pd.Data{
+		ClusterID:          clusterID,
+		BaseDomain:         "example.com",
+		ServicePrefix:      "test",
+		EscalationPolicyID: testEscalationPolicyID,
+	}
+}
+
+func TestServiceLifecycle(t *testing.T) {
+	server, client := newTestClient(t)
+	data := testData("cluster")
+
+	integrationID, err := client.CreateService(data)
+	assert.NilError(t, err)
+	assert.Equal(t, integrationID, data.IntegrationID)
+	assert.DeepEqual(t, server.ServiceIDs(), []string{data.ServiceID})
+
+	key, err := client.GetIntegrationKey(data)
+	assert.NilError(t, err)
+	assert.Assert(t, key != "")
+
+	services, err := client.ListServicesByPrefix("test")
+	assert.NilError(t, err)
+	assert.Equal(t, len(services), 1)
+	assert.Equal(t, pd.IntegrationID(&services[0]), integrationID)
+
+	err = client.DisableService(data)
+	assert.NilError(t, err)
+	service, _ := server.Service(data.ServiceID)
+	assert.Equal(t, service["status"], "disabled")
+
+	err = client.DeleteService(data)
+	assert.NilError(t, err)
+	_, err = client.GetService(data)
+	assert.Assert(t, pd.IsNotFound(err))
+}
+
+func TestCreateServiceAdoptsExisting(t *testing.T) {
+	server, client := newTestClient(t)
+
+	_, err := client.CreateService(testData("cluster"))
+	assert.NilError(t, err)
+
+	// the service exists but its ConfigMap was lost
+	data := testData("cluster")
+	_, err = client.CreateService(data)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, server.ServiceIDs(), []string{data.ServiceID})
+}
+
+func TestListServicesPaginates(t *testing.T) {
+	server, client := newTestClient(t)
+	for i := 0; i < 130; i++ {
+		_, err := client.CreateService(testData(fmt.Sprintf("cluster-%03d", i)))
+		assert.NilError(t, err)
+	}
+
+	services, err := client.ListServices("test")
+	assert.NilError(t, err)
+	assert.Equal(t, len(services), 130)
+	assert.Equal(t, server.Calls(http.MethodGet, "/services"), 2)
+}
+
+func TestRateLimitedCallIsRetried(t *testing.T) {
+	server, client := newTestClient(t)
+	data := testData("cluster")
+	_, err := client.CreateService(data)
+	assert.NilError(t, err)
+
+	server.RateLimit(1, 1)
+	_, err = client.GetService(data)
+	assert.NilError(t, err)
+	assert.Equal(t, server.Calls(http.MethodGet, "/services/"+data.ServiceID), 2)
+}
+
+func TestInjectedFailure(t *testing.T) {
+	server, client := newTestClient(t)
+	data := testData("cluster")
+	_, err := client.CreateService(data)
+	assert.NilError(t, err)
+
+	server.Fail(http.MethodGet, "/services/", http.StatusNotFound, 1)
+	_, err = client.GetService(data)
+	assert.Assert(t, pd.IsNotFound(err))
+
+	_, err = client.GetService(data)
+	assert.NilError(t, err)
+}
+
+func TestUnknownAPIKeyIsRefused(t *testing.T) {
+	server, _ := newTestClient(t)
+	client := pd.NewClient("other-key", "test-controller", pd.WithAPIEndpoint(server.URL))
+
+	_, err := client.GetService(testData("cluster"))
+	assert.ErrorContains(t, err, "HTTP response code: 401")
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerduty
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// apiHealth tracks the calls made by the clients of NewClient, for
+// ReadinessCheck
+var apiHealth = newAPIHealthTracker(time.Now)
+
+// refusedKeyTTL is how long an API key refused by the API is remembered
+// without further calls, so the key of a deleted PagerDutyIntegration stops
+// counting
+const refusedKeyTTL = time.Hour
+
+// refusal is when the API started and last refused an API key
+type refusal struct {
+	since time.Time
+	last  time.Time
+}
+
+// apiHealthTracker remembers since when the PagerDuty API has been
+// unreachable, and since when it has been refusing each API key. Keys are
+// only kept as hashes.
+type apiHealthTracker struct {
+	mu  sync.Mutex
+	now func() time.Time
+	// failingSince is when the calls started failing to reach the API,
+	// zero while the last call reached it
+	failingSince time.Time
+	lastErr      string
+	// refused are the API keys the API is refusing
+	refused map[[sha256.Size]byte]refusal
+}
+
+func newAPIHealthTracker(now func() time.Time) *apiHealthTracker {
+	return &apiHealthTracker{now: now, refused: map[[sha256.Size]byte]refusal{}}
+}
+
+// observe records the outcome of sending req. Server errors count as the
+// API being unreachable, rate limiting does not.
+func (t *apiHealthTracker) observe(req *http.Request, resp *http.Response, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		if t.failingSince.IsZero() {
+			t.failingSince = now
+		}
+		if err != nil {
+			t.lastErr = err.Error()
+		} else {
+			t.lastErr = fmt.Sprintf("HTTP response code: %d", resp.StatusCode)
+		}
+		return
+	}
+	t.failingSince = time.Time{}
+
+	key := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		r, ok := t.refused[key]
+		if !ok {
+			r.since = now
+		}
+		r.last = now
+		t.refused[key] = r
+		return
+	}
+	delete(t.refused, key)
+}
+
+// check returns an error once the API has been unreachable, or has been
+// refusing apiKey, for threshold. Other keys being refused doesn't fail the
+// check: they belong to PagerDutyIntegrations, whose own status reports it.
+func (t *apiHealthTracker) check(threshold time.Duration, apiKey string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if !t.failingSince.IsZero() && now.Sub(t.failingSince) >= threshold {
+		return fmt.Errorf("PagerDuty API unreachable since %s: %s", t.failingSince.UTC().Format(time.RFC3339), t.lastErr)
+	}
+	for key, r := range t.refused {
+		if now.Sub(r.last) > refusedKeyTTL {
+			delete(t.refused, key)
+		}
+	}
+	if apiKey == "" {
+		return nil
+	}
+	if r, ok := t.refused[sha256.Sum256([]byte("Token token="+apiKey))]; ok && now.Sub(r.since) >= threshold {
+		return fmt.Errorf("PagerDuty API refusing the operator API key since %s", r.since.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// ReadinessCheck returns a readiness check, such as a controller-runtime
+// healthz.Checker, failing once the calls made by the clients of NewClient
+// have not reached the PagerDuty API, or have been refused for the API key
+// apiKey returns, for threshold. Only the key of the operator is checked, so
+// the invalid key of a single PagerDutyIntegration, possibly of a tenant,
+// doesn't take every replica out of its Services. The check passes before
+// any call was made, and skips the key while apiKey returns "".
+func ReadinessCheck(threshold time.Duration, apiKey func() string) func(*http.Request) error {
+	return func(*http.Request) error {
+		return apiHealth.check(threshold, apiKey())
+	}
+}
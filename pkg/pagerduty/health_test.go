@@ -0,0 +1,62 @@
+package pagerduty
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestAPIHealthTracker(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	tracker := newAPIHealthTracker(func() time.Time { return now })
+
+	request := func(apiKey string) *http.Request {
+		req, _ := http.NewRequest(http.MethodGet, apiEndpoint+"/services", nil)
+		req.Header.Set("Authorization", "Token token="+apiKey)
+		return req
+	}
+	response := func(statusCode int) *http.Response {
+		return &http.Response{StatusCode: statusCode}
+	}
+
+	// no call made yet
+	assert.NilError(t, tracker.check(10*time.Minute, "good"))
+
+	// unreachable, not for long enough
+	tracker.observe(request("good"), nil, errors.New("dial tcp: i/o timeout"))
+	now = now.Add(5 * time.Minute)
+	tracker.observe(request("good"), response(http.StatusBadGateway), nil)
+	assert.NilError(t, tracker.check(10*time.Minute, "good"))
+
+	// unreachable for long enough
+	now = now.Add(5 * time.Minute)
+	assert.ErrorContains(t, tracker.check(10*time.Minute, "good"), "PagerDuty API unreachable")
+
+	// reached again, rate limited calls reach it
+	tracker.observe(request("good"), response(http.StatusTooManyRequests), nil)
+	assert.NilError(t, tracker.check(10*time.Minute, "good"))
+
+	// one key refused for long enough, only the operator key counts
+	tracker.observe(request("bad"), response(http.StatusUnauthorized), nil)
+	now = now.Add(10 * time.Minute)
+	tracker.observe(request("bad"), response(http.StatusUnauthorized), nil)
+	tracker.observe(request("good"), response(http.StatusOK), nil)
+	assert.NilError(t, tracker.check(10*time.Minute, "good"))
+	assert.ErrorContains(t, tracker.check(10*time.Minute, "bad"), "refusing the operator API key")
+
+	// the operator key not loaded
+	assert.NilError(t, tracker.check(10*time.Minute, ""))
+
+	// the refused key was replaced and is no longer used
+	now = now.Add(refusedKeyTTL + time.Minute)
+	assert.NilError(t, tracker.check(10*time.Minute, "bad"))
+
+	// a refused key accepted again
+	tracker.observe(request("rotated"), response(http.StatusForbidden), nil)
+	now = now.Add(10 * time.Minute)
+	tracker.observe(request("rotated"), response(http.StatusOK), nil)
+	assert.NilError(t, tracker.check(10*time.Minute, "rotated"))
+}
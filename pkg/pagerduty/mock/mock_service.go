@@ -8,7 +8,9 @@ import (
 	go_pagerduty "github.com/PagerDuty/go-pagerduty"
 	gomock "github.com/golang/mock/gomock"
 	pagerduty "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	http "net/http"
 	reflect "reflect"
+	time "time"
 )
 
 // MockClient is a mock of Client interface
@@ -81,6 +83,80 @@ func (mr *MockClientMockRecorder) CreateService(data interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateService", reflect.TypeOf((*MockClient)(nil).CreateService), data)
 }
 
+// FindServiceByName mocks base method
+func (m *MockClient) FindServiceByName(data *pagerduty.Data) (*go_pagerduty.Service, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindServiceByName", data)
+	ret0, _ := ret[0].(*go_pagerduty.Service)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindServiceByName indicates an expected call of FindServiceByName
+func (mr *MockClientMockRecorder) FindServiceByName(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindServiceByName", reflect.TypeOf((*MockClient)(nil).FindServiceByName), data)
+}
+
+// AdoptService mocks base method
+func (m *MockClient) AdoptService(data *pagerduty.Data, service *go_pagerduty.Service) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdoptService", data, service)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdoptService indicates an expected call of AdoptService
+func (mr *MockClientMockRecorder) AdoptService(data, service interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdoptService", reflect.TypeOf((*MockClient)(nil).AdoptService), data, service)
+}
+
+// ListServicesByPrefix mocks base method
+func (m *MockClient) ListServicesByPrefix(prefix string) ([]go_pagerduty.Service, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServicesByPrefix", prefix)
+	ret0, _ := ret[0].([]go_pagerduty.Service)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServicesByPrefix indicates an expected call of ListServicesByPrefix
+func (mr *MockClientMockRecorder) ListServicesByPrefix(prefix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServicesByPrefix", reflect.TypeOf((*MockClient)(nil).ListServicesByPrefix), prefix)
+}
+
+// ListServices mocks base method
+func (m *MockClient) ListServices(query string) ([]go_pagerduty.Service, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServices", query)
+	ret0, _ := ret[0].([]go_pagerduty.Service)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServices indicates an expected call of ListServices
+func (mr *MockClientMockRecorder) ListServices(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServices", reflect.TypeOf((*MockClient)(nil).ListServices), query)
+}
+
+// ListIntegrations mocks base method
+func (m *MockClient) ListIntegrations(query string) ([]go_pagerduty.Integration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIntegrations", query)
+	ret0, _ := ret[0].([]go_pagerduty.Integration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIntegrations indicates an expected call of ListIntegrations
+func (mr *MockClientMockRecorder) ListIntegrations(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIntegrations", reflect.TypeOf((*MockClient)(nil).ListIntegrations), query)
+}
+
 // DeleteService mocks base method
 func (m *MockClient) DeleteService(data *pagerduty.Data) error {
 	m.ctrl.T.Helper()
@@ -95,6 +171,368 @@ func (mr *MockClientMockRecorder) DeleteService(data interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteService", reflect.TypeOf((*MockClient)(nil).DeleteService), data)
 }
 
+// CreateMaintenanceWindow mocks base method
+func (m *MockClient) CreateMaintenanceWindow(data *pagerduty.Data, description string, start, end time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMaintenanceWindow", data, description, start, end)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMaintenanceWindow indicates an expected call of CreateMaintenanceWindow
+func (mr *MockClientMockRecorder) CreateMaintenanceWindow(data, description, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMaintenanceWindow", reflect.TypeOf((*MockClient)(nil).CreateMaintenanceWindow), data, description, start, end)
+}
+
+// DeleteMaintenanceWindow mocks base method
+func (m *MockClient) DeleteMaintenanceWindow(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMaintenanceWindow", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMaintenanceWindow indicates an expected call of DeleteMaintenanceWindow
+func (mr *MockClientMockRecorder) DeleteMaintenanceWindow(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMaintenanceWindow", reflect.TypeOf((*MockClient)(nil).DeleteMaintenanceWindow), id)
+}
+
+// CreateSuppressionRule mocks base method
+func (m *MockClient) CreateSuppressionRule(rulesetID, detail, value string, start, end time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSuppressionRule", rulesetID, detail, value, start, end)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSuppressionRule indicates an expected call of CreateSuppressionRule
+func (mr *MockClientMockRecorder) CreateSuppressionRule(rulesetID, detail, value, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSuppressionRule", reflect.TypeOf((*MockClient)(nil).CreateSuppressionRule), rulesetID, detail, value, start, end)
+}
+
+// SetServiceTags mocks base method
+func (m *MockClient) SetServiceTags(data *pagerduty.Data, tags map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetServiceTags", data, tags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetServiceTags indicates an expected call of SetServiceTags
+func (mr *MockClientMockRecorder) SetServiceTags(data, tags interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetServiceTags", reflect.TypeOf((*MockClient)(nil).SetServiceTags), data, tags)
+}
+
+// RepairService mocks base method
+func (m *MockClient) RepairService(data *pagerduty.Data, service *go_pagerduty.Service) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepairService", data, service)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RepairService indicates an expected call of RepairService
+func (mr *MockClientMockRecorder) RepairService(data, service interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairService", reflect.TypeOf((*MockClient)(nil).RepairService), data, service)
+}
+
+// LastServiceChanger mocks base method
+func (m *MockClient) LastServiceChanger(data *pagerduty.Data) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastServiceChanger", data)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastServiceChanger indicates an expected call of LastServiceChanger
+func (mr *MockClientMockRecorder) LastServiceChanger(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastServiceChanger", reflect.TypeOf((*MockClient)(nil).LastServiceChanger), data)
+}
+
+// SendChangeEvent mocks base method
+func (m *MockClient) SendChangeEvent(routingKey, summary string, details map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendChangeEvent", routingKey, summary, details)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendChangeEvent indicates an expected call of SendChangeEvent
+func (mr *MockClientMockRecorder) SendChangeEvent(routingKey, summary, details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendChangeEvent", reflect.TypeOf((*MockClient)(nil).SendChangeEvent), routingKey, summary, details)
+}
+
+// ListServiceChanges mocks base method
+func (m *MockClient) ListServiceChanges(since time.Time) ([]pagerduty.AuditRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServiceChanges", since)
+	ret0, _ := ret[0].([]pagerduty.AuditRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServiceChanges indicates an expected call of ListServiceChanges
+func (mr *MockClientMockRecorder) ListServiceChanges(since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServiceChanges", reflect.TypeOf((*MockClient)(nil).ListServiceChanges), since)
+}
+
+// ValidateReferences mocks base method
+func (m *MockClient) ValidateReferences(refs pagerduty.References) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateReferences", refs)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateReferences indicates an expected call of ValidateReferences
+func (mr *MockClientMockRecorder) ValidateReferences(refs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateReferences", reflect.TypeOf((*MockClient)(nil).ValidateReferences), refs)
+}
+
+// SendTestAlert mocks base method
+func (m *MockClient) SendTestAlert(integrationKey, clusterID string) (pagerduty.TestAlertResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendTestAlert", integrationKey, clusterID)
+	ret0, _ := ret[0].(pagerduty.TestAlertResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendTestAlert indicates an expected call of SendTestAlert
+func (mr *MockClientMockRecorder) SendTestAlert(integrationKey, clusterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTestAlert", reflect.TypeOf((*MockClient)(nil).SendTestAlert), integrationKey, clusterID)
+}
+
+// CreateHeartbeatIntegration mocks base method
+func (m *MockClient) CreateHeartbeatIntegration(data *pagerduty.Data) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHeartbeatIntegration", data)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHeartbeatIntegration indicates an expected call of CreateHeartbeatIntegration
+func (mr *MockClientMockRecorder) CreateHeartbeatIntegration(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHeartbeatIntegration", reflect.TypeOf((*MockClient)(nil).CreateHeartbeatIntegration), data)
+}
+
+// RotateIntegrationKey mocks base method
+func (m *MockClient) RotateIntegrationKey(data *pagerduty.Data) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateIntegrationKey", data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateIntegrationKey indicates an expected call of RotateIntegrationKey
+func (mr *MockClientMockRecorder) RotateIntegrationKey(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateIntegrationKey", reflect.TypeOf((*MockClient)(nil).RotateIntegrationKey), data)
+}
+
+// RecreateMissingIntegration mocks base method
+func (m *MockClient) RecreateMissingIntegration(data *pagerduty.Data) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecreateMissingIntegration", data)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecreateMissingIntegration indicates an expected call of RecreateMissingIntegration
+func (mr *MockClientMockRecorder) RecreateMissingIntegration(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecreateMissingIntegration", reflect.TypeOf((*MockClient)(nil).RecreateMissingIntegration), data)
+}
+
+// SendHeartbeatEvent mocks base method
+func (m *MockClient) SendHeartbeatEvent(integrationKey, clusterID string, missed bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeartbeatEvent", integrationKey, clusterID, missed)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeartbeatEvent indicates an expected call of SendHeartbeatEvent
+func (mr *MockClientMockRecorder) SendHeartbeatEvent(integrationKey, clusterID, missed interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeartbeatEvent", reflect.TypeOf((*MockClient)(nil).SendHeartbeatEvent), integrationKey, clusterID, missed)
+}
+
+// DisableService mocks base method
+func (m *MockClient) DisableService(data *pagerduty.Data) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableService", data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableService indicates an expected call of DisableService
+func (mr *MockClientMockRecorder) DisableService(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableService", reflect.TypeOf((*MockClient)(nil).DisableService), data)
+}
+
+// EnableService mocks base method
+func (m *MockClient) EnableService(data *pagerduty.Data) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableService", data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableService indicates an expected call of EnableService
+func (mr *MockClientMockRecorder) EnableService(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableService", reflect.TypeOf((*MockClient)(nil).EnableService), data)
+}
+
+// RenameService mocks base method
+func (m *MockClient) RenameService(data *pagerduty.Data) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameService", data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameService indicates an expected call of RenameService
+func (mr *MockClientMockRecorder) RenameService(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameService", reflect.TypeOf((*MockClient)(nil).RenameService), data)
+}
+
+// CountServiceIncidents mocks base method
+func (m *MockClient) CountServiceIncidents(serviceIDs []string, since, until time.Time) (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountServiceIncidents", serviceIDs, since, until)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountServiceIncidents indicates an expected call of CountServiceIncidents
+func (mr *MockClientMockRecorder) CountServiceIncidents(serviceIDs, since, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountServiceIncidents", reflect.TypeOf((*MockClient)(nil).CountServiceIncidents), serviceIDs, since, until)
+}
+
+// ServiceIncidentStats mocks base method
+func (m *MockClient) ServiceIncidentStats(serviceIDs []string, since, until time.Time) (map[string]pagerduty.IncidentStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServiceIncidentStats", serviceIDs, since, until)
+	ret0, _ := ret[0].(map[string]pagerduty.IncidentStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ServiceIncidentStats indicates an expected call of ServiceIncidentStats
+func (mr *MockClientMockRecorder) ServiceIncidentStats(serviceIDs, since, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServiceIncidentStats", reflect.TypeOf((*MockClient)(nil).ServiceIncidentStats), serviceIDs, since, until)
+}
+
+// CreateEventRule mocks base method
+func (m *MockClient) CreateEventRule(rulesetID string, rule pagerduty.EventRule) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEventRule", rulesetID, rule)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEventRule indicates an expected call of CreateEventRule
+func (mr *MockClientMockRecorder) CreateEventRule(rulesetID, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEventRule", reflect.TypeOf((*MockClient)(nil).CreateEventRule), rulesetID, rule)
+}
+
+// UpdateEventRule mocks base method
+func (m *MockClient) UpdateEventRule(rulesetID, ruleID string, rule pagerduty.EventRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEventRule", rulesetID, ruleID, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEventRule indicates an expected call of UpdateEventRule
+func (mr *MockClientMockRecorder) UpdateEventRule(rulesetID, ruleID, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEventRule", reflect.TypeOf((*MockClient)(nil).UpdateEventRule), rulesetID, ruleID, rule)
+}
+
+// DeleteEventRule mocks base method
+func (m *MockClient) DeleteEventRule(rulesetID, ruleID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEventRule", rulesetID, ruleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEventRule indicates an expected call of DeleteEventRule
+func (mr *MockClientMockRecorder) DeleteEventRule(rulesetID, ruleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventRule", reflect.TypeOf((*MockClient)(nil).DeleteEventRule), rulesetID, ruleID)
+}
+
+// AlertGroupingDrift mocks base method
+func (m *MockClient) AlertGroupingDrift(data *pagerduty.Data) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AlertGroupingDrift", data)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AlertGroupingDrift indicates an expected call of AlertGroupingDrift
+func (mr *MockClientMockRecorder) AlertGroupingDrift(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AlertGroupingDrift", reflect.TypeOf((*MockClient)(nil).AlertGroupingDrift), data)
+}
+
+// SetAlertGrouping mocks base method
+func (m *MockClient) SetAlertGrouping(data *pagerduty.Data) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAlertGrouping", data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAlertGrouping indicates an expected call of SetAlertGrouping
+func (mr *MockClientMockRecorder) SetAlertGrouping(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAlertGrouping", reflect.TypeOf((*MockClient)(nil).SetAlertGrouping), data)
+}
+
+// SetServiceDependencies mocks base method
+func (m *MockClient) SetServiceDependencies(data *pagerduty.Data, hubServiceID, businessServiceID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetServiceDependencies", data, hubServiceID, businessServiceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetServiceDependencies indicates an expected call of SetServiceDependencies
+func (mr *MockClientMockRecorder) SetServiceDependencies(data, hubServiceID, businessServiceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetServiceDependencies", reflect.TypeOf((*MockClient)(nil).SetServiceDependencies), data, hubServiceID, businessServiceID)
+}
+
 // MockPdClient is a mock of PdClient interface
 type MockPdClient struct {
 	ctrl     *gomock.Controller
@@ -178,6 +616,21 @@ func (mr *MockPdClientMockRecorder) CreateService(service interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateService", reflect.TypeOf((*MockPdClient)(nil).CreateService), service)
 }
 
+// UpdateService mocks base method
+func (m *MockPdClient) UpdateService(service go_pagerduty.Service) (*go_pagerduty.Service, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateService", service)
+	ret0, _ := ret[0].(*go_pagerduty.Service)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateService indicates an expected call of UpdateService
+func (mr *MockPdClientMockRecorder) UpdateService(service interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateService", reflect.TypeOf((*MockPdClient)(nil).UpdateService), service)
+}
+
 // DeleteService mocks base method
 func (m *MockPdClient) DeleteService(id string) error {
 	m.ctrl.T.Helper()
@@ -207,6 +660,20 @@ func (mr *MockPdClientMockRecorder) CreateIntegration(serviceID, integration int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIntegration", reflect.TypeOf((*MockPdClient)(nil).CreateIntegration), serviceID, integration)
 }
 
+// DeleteIntegration mocks base method
+func (m *MockPdClient) DeleteIntegration(serviceID, integrationID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIntegration", serviceID, integrationID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIntegration indicates an expected call of DeleteIntegration
+func (mr *MockPdClientMockRecorder) DeleteIntegration(serviceID, integrationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIntegration", reflect.TypeOf((*MockPdClient)(nil).DeleteIntegration), serviceID, integrationID)
+}
+
 // ListServices mocks base method
 func (m *MockPdClient) ListServices(arg0 go_pagerduty.ListServiceOptions) (*go_pagerduty.ListServiceResponse, error) {
 	m.ctrl.T.Helper()
@@ -222,6 +689,21 @@ func (mr *MockPdClientMockRecorder) ListServices(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServices", reflect.TypeOf((*MockPdClient)(nil).ListServices), arg0)
 }
 
+// ListVendors mocks base method
+func (m *MockPdClient) ListVendors(arg0 go_pagerduty.ListVendorOptions) (*go_pagerduty.ListVendorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVendors", arg0)
+	ret0, _ := ret[0].(*go_pagerduty.ListVendorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVendors indicates an expected call of ListVendors
+func (mr *MockPdClientMockRecorder) ListVendors(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVendors", reflect.TypeOf((*MockPdClient)(nil).ListVendors), arg0)
+}
+
 // ListIncidents mocks base method
 func (m *MockPdClient) ListIncidents(arg0 go_pagerduty.ListIncidentsOptions) (*go_pagerduty.ListIncidentsResponse, error) {
 	m.ctrl.T.Helper()
@@ -251,3 +733,271 @@ func (mr *MockPdClientMockRecorder) ListIncidentAlerts(incidentId interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncidentAlerts", reflect.TypeOf((*MockPdClient)(nil).ListIncidentAlerts), incidentId)
 }
+
+// CreateMaintenanceWindow mocks base method
+func (m *MockPdClient) CreateMaintenanceWindow(from string, o go_pagerduty.MaintenanceWindow) (*go_pagerduty.MaintenanceWindow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMaintenanceWindow", from, o)
+	ret0, _ := ret[0].(*go_pagerduty.MaintenanceWindow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMaintenanceWindow indicates an expected call of CreateMaintenanceWindow
+func (mr *MockPdClientMockRecorder) CreateMaintenanceWindow(from, o interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMaintenanceWindow", reflect.TypeOf((*MockPdClient)(nil).CreateMaintenanceWindow), from, o)
+}
+
+// DeleteMaintenanceWindow mocks base method
+func (m *MockPdClient) DeleteMaintenanceWindow(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMaintenanceWindow", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMaintenanceWindow indicates an expected call of DeleteMaintenanceWindow
+func (mr *MockPdClientMockRecorder) DeleteMaintenanceWindow(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMaintenanceWindow", reflect.TypeOf((*MockPdClient)(nil).DeleteMaintenanceWindow), id)
+}
+
+// ListRulesetRules mocks base method
+func (m *MockPdClient) ListRulesetRules(rulesetID string) (*go_pagerduty.ListRulesetRulesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRulesetRules", rulesetID)
+	ret0, _ := ret[0].(*go_pagerduty.ListRulesetRulesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRulesetRules indicates an expected call of ListRulesetRules
+func (mr *MockPdClientMockRecorder) ListRulesetRules(rulesetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRulesetRules", reflect.TypeOf((*MockPdClient)(nil).ListRulesetRules), rulesetID)
+}
+
+// CreateRulesetRule mocks base method
+func (m *MockPdClient) CreateRulesetRule(rulesetID string, rule *go_pagerduty.RulesetRule) (*go_pagerduty.RulesetRule, *http.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRulesetRule", rulesetID, rule)
+	ret0, _ := ret[0].(*go_pagerduty.RulesetRule)
+	ret1, _ := ret[1].(*http.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateRulesetRule indicates an expected call of CreateRulesetRule
+func (mr *MockPdClientMockRecorder) CreateRulesetRule(rulesetID, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRulesetRule", reflect.TypeOf((*MockPdClient)(nil).CreateRulesetRule), rulesetID, rule)
+}
+
+// UpdateRulesetRule mocks base method
+func (m *MockPdClient) UpdateRulesetRule(rulesetID, ruleID string, rule *go_pagerduty.RulesetRule) (*go_pagerduty.RulesetRule, *http.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRulesetRule", rulesetID, ruleID, rule)
+	ret0, _ := ret[0].(*go_pagerduty.RulesetRule)
+	ret1, _ := ret[1].(*http.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateRulesetRule indicates an expected call of UpdateRulesetRule
+func (mr *MockPdClientMockRecorder) UpdateRulesetRule(rulesetID, ruleID, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRulesetRule", reflect.TypeOf((*MockPdClient)(nil).UpdateRulesetRule), rulesetID, ruleID, rule)
+}
+
+// DeleteRulesetRule mocks base method
+func (m *MockPdClient) DeleteRulesetRule(rulesetID, ruleID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRulesetRule", rulesetID, ruleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRulesetRule indicates an expected call of DeleteRulesetRule
+func (mr *MockPdClientMockRecorder) DeleteRulesetRule(rulesetID, ruleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRulesetRule", reflect.TypeOf((*MockPdClient)(nil).DeleteRulesetRule), rulesetID, ruleID)
+}
+
+// GetRuleset mocks base method
+func (m *MockPdClient) GetRuleset(id string) (*go_pagerduty.Ruleset, *http.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRuleset", id)
+	ret0, _ := ret[0].(*go_pagerduty.Ruleset)
+	ret1, _ := ret[1].(*http.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetRuleset indicates an expected call of GetRuleset
+func (mr *MockPdClientMockRecorder) GetRuleset(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuleset", reflect.TypeOf((*MockPdClient)(nil).GetRuleset), id)
+}
+
+// ListServiceTags mocks base method
+func (m *MockPdClient) ListServiceTags(serviceID string) ([]pagerduty.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServiceTags", serviceID)
+	ret0, _ := ret[0].([]pagerduty.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServiceTags indicates an expected call of ListServiceTags
+func (mr *MockPdClientMockRecorder) ListServiceTags(serviceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServiceTags", reflect.TypeOf((*MockPdClient)(nil).ListServiceTags), serviceID)
+}
+
+// ChangeServiceTags mocks base method
+func (m *MockPdClient) ChangeServiceTags(serviceID string, add, remove []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeServiceTags", serviceID, add, remove)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeServiceTags indicates an expected call of ChangeServiceTags
+func (mr *MockPdClientMockRecorder) ChangeServiceTags(serviceID, add, remove interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeServiceTags", reflect.TypeOf((*MockPdClient)(nil).ChangeServiceTags), serviceID, add, remove)
+}
+
+// ListServiceAuditRecords mocks base method
+func (m *MockPdClient) ListServiceAuditRecords(serviceID string) ([]pagerduty.AuditRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServiceAuditRecords", serviceID)
+	ret0, _ := ret[0].([]pagerduty.AuditRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServiceAuditRecords indicates an expected call of ListServiceAuditRecords
+func (mr *MockPdClientMockRecorder) ListServiceAuditRecords(serviceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServiceAuditRecords", reflect.TypeOf((*MockPdClient)(nil).ListServiceAuditRecords), serviceID)
+}
+
+// ListAuditRecords mocks base method
+func (m *MockPdClient) ListAuditRecords(rootResourceType string, since time.Time) ([]pagerduty.AuditRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditRecords", rootResourceType, since)
+	ret0, _ := ret[0].([]pagerduty.AuditRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditRecords indicates an expected call of ListAuditRecords
+func (mr *MockPdClientMockRecorder) ListAuditRecords(rootResourceType, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditRecords", reflect.TypeOf((*MockPdClient)(nil).ListAuditRecords), rootResourceType, since)
+}
+
+// ListServiceIncidentMetrics mocks base method
+func (m *MockPdClient) ListServiceIncidentMetrics(serviceIDs []string, since, until time.Time) ([]pagerduty.IncidentMetrics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServiceIncidentMetrics", serviceIDs, since, until)
+	ret0, _ := ret[0].([]pagerduty.IncidentMetrics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServiceIncidentMetrics indicates an expected call of ListServiceIncidentMetrics
+func (mr *MockPdClientMockRecorder) ListServiceIncidentMetrics(serviceIDs, since, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServiceIncidentMetrics", reflect.TypeOf((*MockPdClient)(nil).ListServiceIncidentMetrics), serviceIDs, since, until)
+}
+
+// GetServiceAlertGrouping mocks base method
+func (m *MockPdClient) GetServiceAlertGrouping(serviceID string) (*pagerduty.AlertGrouping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceAlertGrouping", serviceID)
+	ret0, _ := ret[0].(*pagerduty.AlertGrouping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceAlertGrouping indicates an expected call of GetServiceAlertGrouping
+func (mr *MockPdClientMockRecorder) GetServiceAlertGrouping(serviceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceAlertGrouping", reflect.TypeOf((*MockPdClient)(nil).GetServiceAlertGrouping), serviceID)
+}
+
+// UpdateServiceAlertGrouping mocks base method
+func (m *MockPdClient) UpdateServiceAlertGrouping(serviceID string, grouping *pagerduty.AlertGrouping) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateServiceAlertGrouping", serviceID, grouping)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateServiceAlertGrouping indicates an expected call of UpdateServiceAlertGrouping
+func (mr *MockPdClientMockRecorder) UpdateServiceAlertGrouping(serviceID, grouping interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServiceAlertGrouping", reflect.TypeOf((*MockPdClient)(nil).UpdateServiceAlertGrouping), serviceID, grouping)
+}
+
+// ListServiceDependencies mocks base method
+func (m *MockPdClient) ListServiceDependencies(serviceID string) ([]pagerduty.ServiceDependency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServiceDependencies", serviceID)
+	ret0, _ := ret[0].([]pagerduty.ServiceDependency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServiceDependencies indicates an expected call of ListServiceDependencies
+func (mr *MockPdClientMockRecorder) ListServiceDependencies(serviceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServiceDependencies", reflect.TypeOf((*MockPdClient)(nil).ListServiceDependencies), serviceID)
+}
+
+// AssociateServiceDependencies mocks base method
+func (m *MockPdClient) AssociateServiceDependencies(dependencies []pagerduty.ServiceDependency) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssociateServiceDependencies", dependencies)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssociateServiceDependencies indicates an expected call of AssociateServiceDependencies
+func (mr *MockPdClientMockRecorder) AssociateServiceDependencies(dependencies interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssociateServiceDependencies", reflect.TypeOf((*MockPdClient)(nil).AssociateServiceDependencies), dependencies)
+}
+
+// GetTeam mocks base method
+func (m *MockPdClient) GetTeam(id string) (*go_pagerduty.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeam", id)
+	ret0, _ := ret[0].(*go_pagerduty.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeam indicates an expected call of GetTeam
+func (mr *MockPdClientMockRecorder) GetTeam(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeam", reflect.TypeOf((*MockPdClient)(nil).GetTeam), id)
+}
+
+// ListTeams mocks base method
+func (m *MockPdClient) ListTeams(o go_pagerduty.ListTeamOptions) (*go_pagerduty.ListTeamResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTeams", o)
+	ret0, _ := ret[0].(*go_pagerduty.ListTeamResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTeams indicates an expected call of ListTeams
+func (mr *MockPdClientMockRecorder) ListTeams(o interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTeams", reflect.TypeOf((*MockPdClient)(nil).ListTeams), o)
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerduty
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/go-logr/logr"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
+)
+
+// ClientOption customizes the client made by NewClient, so operators
+// embedding this package can plug in their own observability
+type ClientOption func(*customHTTPClient)
+
+// APICallObserver is told about every call answered by the PagerDuty API,
+// with the number of seconds it took. localmetrics.AddAPICall is the default.
+type APICallObserver func(controller string, req *http.Request, resp *http.Response, duration float64)
+
+// RateLimiter throttles the calls made to the PagerDuty API. Wait blocks
+// until the next call may be made; golang.org/x/time/rate.Limiter is one.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// WithLogger logs every call made to the PagerDuty API at V(1), and the
+// calls that failed to be sent as errors
+func WithLogger(logger logr.Logger) ClientOption {
+	return func(c *customHTTPClient) {
+		c.logger = logging.NewRedactingLogger(logger)
+	}
+}
+
+// WithMetrics reports the calls made to the PagerDuty API to observe
+// instead of the operator's metrics
+func WithMetrics(observe APICallObserver) ClientOption {
+	return func(c *customHTTPClient) {
+		c.observe = observe
+	}
+}
+
+// WithRateLimiter waits for limiter before every call made to the PagerDuty
+// API, retries included
+func WithRateLimiter(limiter RateLimiter) ClientOption {
+	return func(c *customHTTPClient) {
+		c.limiter = limiter
+	}
+}
+
+// WithAPIEndpoint sends the calls made to the PagerDuty API to endpoint,
+// such as https://api.eu.pagerduty.com for an account in the EU service
+// region or a staging API, instead of https://api.pagerduty.com. An empty
+// endpoint keeps the default.
+func WithAPIEndpoint(endpoint string) ClientOption {
+	return func(c *customHTTPClient) {
+		c.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// WithHTTPClient sends the calls made to the PagerDuty API through client
+// instead of the go-pagerduty default
+func WithHTTPClient(client pdApi.HTTPClient) ClientOption {
+	return func(c *customHTTPClient) {
+		c.HTTPClient = client
+	}
+}
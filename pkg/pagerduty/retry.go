@@ -0,0 +1,132 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerduty
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// apiRetries is how often a call the PagerDuty API rate limited or
+	// failed with a server error is retried before giving up
+	apiRetries = 4
+	// apiRetryBaseDelay is the backoff before the first retry, it doubles
+	// with each further retry
+	apiRetryBaseDelay = time.Second
+	// apiRetryMaxDelay caps the backoff between two retries
+	apiRetryMaxDelay = 30 * time.Second
+	// RateLimitDelay is how long to wait before calling the API again once
+	// it kept rate limiting a call, if the API did not tell
+	RateLimitDelay = time.Minute
+)
+
+// RateLimitError is returned when the PagerDuty API still rate limits a call
+// after it was retried. Calling again before RetryAfter passed would only be
+// rate limited again.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+// Error keeps the status code in the message, as go-pagerduty only passes on
+// the text of the error
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("PagerDuty API rate limit exceeded, HTTP response code: %d, retry after %s", http.StatusTooManyRequests, e.RetryAfter)
+}
+
+// RateLimitRetryAfter returns how long to wait before calling the API again
+// after the rate limited err
+func RateLimitRetryAfter(err error) time.Duration {
+	if e, ok := err.(*RateLimitError); ok && e.RetryAfter > 0 {
+		return e.RetryAfter
+	}
+	return RateLimitDelay
+}
+
+// retryable returns true for the responses worth calling the API again for.
+// A rate limited call was refused, so any is retried. A server error may
+// come after the call succeeded, so only idempotent calls are retried: a
+// POST sent again could create a second service or integration.
+func retryable(method string, statusCode int) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if statusCode < http.StatusInternalServerError {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryDelay returns the jittered exponential backoff before the retry
+// following attempt, or the delay the API asked for in Retry-After
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	delay := apiRetryBaseDelay << uint(attempt)
+	if delay <= 0 || delay > apiRetryMaxDelay {
+		delay = apiRetryMaxDelay
+	}
+	// spread out the retries of concurrent calls
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// doWithRetry sends req with do, retrying rate limited calls and the server
+// errors of idempotent calls with backoff. A call still rate limited after the last retry returns a
+// RateLimitError, other failures return the last response.
+func doWithRetry(do func(*http.Request) (*http.Response, error), sleep func(time.Duration), req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := do(req)
+		if err != nil || !retryable(req.Method, resp.StatusCode) {
+			return resp, err
+		}
+
+		delay := retryDelay(resp, attempt)
+		// the body can only be sent again if it can be rewound
+		if attempt == apiRetries || (req.Body != nil && req.GetBody == nil) {
+			if resp.StatusCode == http.StatusTooManyRequests {
+				drain(resp)
+				return nil, &RateLimitError{RetryAfter: delay}
+			}
+			return resp, nil
+		}
+		drain(resp)
+
+		sleep(delay)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// drain reads and closes the body of a response that is not returned, so
+// the connection can be reused
+func drain(resp *http.Response) {
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,187 @@
+package pagerduty
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestDoWithRetry(t *testing.T) {
+	tests := []struct {
+		name string
+		// PUT if empty
+		method        string
+		statusCodes   []int
+		retryAfter    string
+		expectCalls   int
+		expectStatus  int
+		expectLimited bool
+		expectDelay   time.Duration
+	}{
+		{
+			name:         "Success Not Retried",
+			statusCodes:  []int{http.StatusOK},
+			expectCalls:  1,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "Client Error Not Retried",
+			statusCodes:  []int{http.StatusNotFound},
+			expectCalls:  1,
+			expectStatus: http.StatusNotFound,
+		},
+		{
+			name:         "Rate Limit Retried",
+			statusCodes:  []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			expectCalls:  3,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "Server Error Retried",
+			statusCodes:  []int{http.StatusBadGateway, http.StatusOK},
+			expectCalls:  2,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "Server Error Of POST Not Retried",
+			method:       http.MethodPost,
+			statusCodes:  []int{http.StatusBadGateway, http.StatusOK},
+			expectCalls:  1,
+			expectStatus: http.StatusBadGateway,
+		},
+		{
+			name:         "Rate Limit Of POST Retried",
+			method:       http.MethodPost,
+			statusCodes:  []int{http.StatusTooManyRequests, http.StatusOK},
+			expectCalls:  2,
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "Server Error Returned After Last Retry",
+			statusCodes:  []int{http.StatusServiceUnavailable},
+			expectCalls:  apiRetries + 1,
+			expectStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:          "Rate Limit Error After Last Retry",
+			statusCodes:   []int{http.StatusTooManyRequests},
+			retryAfter:    "42",
+			expectCalls:   apiRetries + 1,
+			expectLimited: true,
+			expectDelay:   42 * time.Second,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var bodies []string
+			do := func(req *http.Request) (*http.Response, error) {
+				body, err := ioutil.ReadAll(req.Body)
+				assert.NilError(t, err)
+				bodies = append(bodies, string(body))
+
+				statusCode := test.statusCodes[len(test.statusCodes)-1]
+				if len(bodies) <= len(test.statusCodes) {
+					statusCode = test.statusCodes[len(bodies)-1]
+				}
+				resp := &http.Response{
+					StatusCode: statusCode,
+					Header:     http.Header{},
+					Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+				}
+				if test.retryAfter != "" {
+					resp.Header.Set("Retry-After", test.retryAfter)
+				}
+				return resp, nil
+			}
+			var delays []time.Duration
+			sleep := func(d time.Duration) { delays = append(delays, d) }
+
+			method := test.method
+			if method == "" {
+				method = http.MethodPut
+			}
+			req, err := http.NewRequest(method, apiEndpoint, bytes.NewReader([]byte("payload")))
+			assert.NilError(t, err)
+
+			resp, err := doWithRetry(do, sleep, req)
+
+			assert.Equal(t, len(bodies), test.expectCalls)
+			assert.Equal(t, len(delays), test.expectCalls-1)
+			for _, body := range bodies {
+				// the body is sent again with each retry
+				assert.Equal(t, body, "payload")
+			}
+			for _, delay := range delays {
+				if test.retryAfter != "" {
+					assert.Equal(t, delay, test.expectDelay)
+					continue
+				}
+				assert.Assert(t, delay > 0 && delay <= apiRetryMaxDelay)
+			}
+			if test.expectLimited {
+				assert.Assert(t, IsRateLimited(err))
+				assert.Equal(t, RateLimitRetryAfter(err), test.expectDelay)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, resp.StatusCode, test.expectStatus)
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	for attempt := 0; attempt < 10; attempt++ {
+		backoff := apiRetryBaseDelay << uint(attempt)
+		if backoff > apiRetryMaxDelay {
+			backoff = apiRetryMaxDelay
+		}
+		delay := retryDelay(resp, attempt)
+		assert.Assert(t, delay >= backoff/2 && delay <= backoff, "attempt %d: %s", attempt, delay)
+	}
+}
+
+func TestRateLimitRetryAfter(t *testing.T) {
+	assert.Equal(t, RateLimitRetryAfter(&RateLimitError{RetryAfter: time.Second}), time.Second)
+	assert.Equal(t, RateLimitRetryAfter(errors.New("Failed call API endpoint. HTTP response code: 429. Error: &{}")), RateLimitDelay)
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		expectTransient bool
+	}{
+		{
+			name:            "Server Error",
+			err:             errors.New("Failed call API endpoint. HTTP response code: 503. Error: &{}"),
+			expectTransient: true,
+		},
+		{
+			name:            "API Not Reached",
+			err:             &url.Error{Op: "Get", URL: "https://api.pagerduty.com/services", Err: errors.New("connection refused")},
+			expectTransient: true,
+		},
+		{
+			name: "Not Found",
+			err:  errors.New("Failed call API endpoint. HTTP response code: 404. Error: &{}"),
+		},
+		{
+			name: "Unauthorized",
+			err:  errors.New("Failed call API endpoint. HTTP response code: 401. Error: &{}"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, IsTransient(test.err), test.expectTransient)
+		})
+	}
+}
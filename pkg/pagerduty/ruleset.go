@@ -0,0 +1,106 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerduty
+
+import (
+	pdApi "github.com/PagerDuty/go-pagerduty"
+)
+
+// EventRule is a ruleset rule handling the events of one cluster
+type EventRule struct {
+	// ClusterIDDetail is the custom detail holding the cluster's ID
+	ClusterIDDetail string
+	ClusterID       string
+	// ServiceID is the cluster's service, the events are routed to it if
+	// Route is set
+	ServiceID  string
+	Conditions []EventRuleCondition
+	Route      bool
+	Suppress   bool
+	Severity   string
+}
+
+// EventRuleCondition matches a custom detail of the events
+type EventRuleCondition struct {
+	Detail   string
+	Operator string
+	Value    string
+}
+
+// CreateEventRule adds rule to the global ruleset and returns its ID
+func (c *SvcClient) CreateEventRule(rulesetID string, rule EventRule) (string, error) {
+	newRule, _, err := c.PdClient.CreateRulesetRule(rulesetID, rulesetRule(rule))
+	if err != nil {
+		return "", err
+	}
+	return newRule.ID, nil
+}
+
+// UpdateEventRule replaces the rule ruleID of the global ruleset with rule
+func (c *SvcClient) UpdateEventRule(rulesetID string, ruleID string, rule EventRule) error {
+	_, _, err := c.PdClient.UpdateRulesetRule(rulesetID, ruleID, rulesetRule(rule))
+	return err
+}
+
+// DeleteEventRule deletes the rule ruleID of the global ruleset, a rule that
+// is already gone is not an error
+func (c *SvcClient) DeleteEventRule(rulesetID string, ruleID string) error {
+	err := c.PdClient.DeleteRulesetRule(rulesetID, ruleID)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// rulesetRule returns the PagerDuty ruleset rule of rule
+func rulesetRule(rule EventRule) *pdApi.RulesetRule {
+	subconditions := []*pdApi.RuleSubcondition{
+		{
+			Operator: "equals",
+			Parameters: &pdApi.ConditionParameter{
+				Path:  "payload.custom_details." + rule.ClusterIDDetail,
+				Value: rule.ClusterID,
+			},
+		},
+	}
+	for _, condition := range rule.Conditions {
+		subconditions = append(subconditions, &pdApi.RuleSubcondition{
+			Operator: condition.Operator,
+			Parameters: &pdApi.ConditionParameter{
+				Path:  "payload.custom_details." + condition.Detail,
+				Value: condition.Value,
+			},
+		})
+	}
+
+	actions := &pdApi.RuleActions{}
+	if rule.Route {
+		actions.Route = &pdApi.RuleActionParameter{Value: rule.ServiceID}
+	}
+	if rule.Suppress {
+		actions.Suppress = &pdApi.RuleActionSuppress{Value: true}
+	}
+	if rule.Severity != "" {
+		actions.Severity = &pdApi.RuleActionParameter{Value: rule.Severity}
+	}
+
+	return &pdApi.RulesetRule{
+		Conditions: &pdApi.RuleConditions{
+			Operator:          "and",
+			RuleSubconditions: subconditions,
+		},
+		Actions: actions,
+	}
+}
@@ -16,18 +16,29 @@ package pagerduty
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
 	"strings"
+	"sync"
+	"text/template"
 
 	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
 	"github.com/openshift/pagerduty-operator/pkg/localmetrics"
+	"github.com/openshift/pagerduty-operator/pkg/logging"
 
 	"time"
 
 	pdApi "github.com/PagerDuty/go-pagerduty"
+	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -55,6 +66,7 @@ func GetSecretKey(data map[string][]byte, key string) (string, error) {
 		errorStr := fmt.Sprintf("%v is empty", key)
 		return "", errors.New(errorStr)
 	}
+	logging.AddSecret(retString)
 	return retString, nil
 }
 
@@ -63,7 +75,37 @@ type Client interface {
 	GetService(data *Data) (*pdApi.Service, error)
 	GetIntegrationKey(data *Data) (string, error)
 	CreateService(data *Data) (string, error)
+	FindServiceByName(data *Data) (*pdApi.Service, error)
+	AdoptService(data *Data, service *pdApi.Service) error
+	ListServicesByPrefix(prefix string) ([]pdApi.Service, error)
+	ListServices(query string) ([]pdApi.Service, error)
+	ListIntegrations(query string) ([]pdApi.Integration, error)
 	DeleteService(data *Data) error
+	CreateMaintenanceWindow(data *Data, description string, start time.Time, end time.Time) (string, error)
+	DeleteMaintenanceWindow(id string) error
+	CreateSuppressionRule(rulesetID string, detail string, value string, start time.Time, end time.Time) (string, error)
+	SetServiceTags(data *Data, tags map[string]string) error
+	RepairService(data *Data, service *pdApi.Service) error
+	LastServiceChanger(data *Data) (string, error)
+	SendChangeEvent(routingKey string, summary string, details map[string]string) error
+	ListServiceChanges(since time.Time) ([]AuditRecord, error)
+	ValidateReferences(refs References) ([]string, error)
+	SendTestAlert(integrationKey string, clusterID string) (TestAlertResult, error)
+	CreateHeartbeatIntegration(data *Data) (string, error)
+	RotateIntegrationKey(data *Data) error
+	RecreateMissingIntegration(data *Data) (bool, error)
+	SendHeartbeatEvent(integrationKey string, clusterID string, missed bool) error
+	DisableService(data *Data) error
+	EnableService(data *Data) error
+	RenameService(data *Data) error
+	CountServiceIncidents(serviceIDs []string, since time.Time, until time.Time) (map[string]int, error)
+	ServiceIncidentStats(serviceIDs []string, since time.Time, until time.Time) (map[string]IncidentStats, error)
+	CreateEventRule(rulesetID string, rule EventRule) (string, error)
+	UpdateEventRule(rulesetID string, ruleID string, rule EventRule) error
+	DeleteEventRule(rulesetID string, ruleID string) error
+	AlertGroupingDrift(data *Data) ([]string, error)
+	SetAlertGrouping(data *Data) error
+	SetServiceDependencies(data *Data, hubServiceID string, businessServiceID string) error
 }
 
 type PdClient interface {
@@ -71,62 +113,313 @@ type PdClient interface {
 	GetEscalationPolicy(string, *pdApi.GetEscalationPolicyOptions) (*pdApi.EscalationPolicy, error)
 	GetIntegration(string, string, pdApi.GetIntegrationOptions) (*pdApi.Integration, error)
 	CreateService(service pdApi.Service) (*pdApi.Service, error)
+	UpdateService(service pdApi.Service) (*pdApi.Service, error)
 	DeleteService(id string) error
 	CreateIntegration(serviceID string, integration pdApi.Integration) (*pdApi.Integration, error)
+	DeleteIntegration(serviceID string, integrationID string) error
 	ListServices(pdApi.ListServiceOptions) (*pdApi.ListServiceResponse, error)
+	ListVendors(pdApi.ListVendorOptions) (*pdApi.ListVendorResponse, error)
 	ListIncidents(pdApi.ListIncidentsOptions) (*pdApi.ListIncidentsResponse, error)
 	ListIncidentAlerts(incidentId string) (*pdApi.ListAlertsResponse, error)
+	CreateMaintenanceWindow(from string, o pdApi.MaintenanceWindow) (*pdApi.MaintenanceWindow, error)
+	DeleteMaintenanceWindow(id string) error
+	ListRulesetRules(rulesetID string) (*pdApi.ListRulesetRulesResponse, error)
+	CreateRulesetRule(rulesetID string, rule *pdApi.RulesetRule) (*pdApi.RulesetRule, *http.Response, error)
+	UpdateRulesetRule(rulesetID, ruleID string, rule *pdApi.RulesetRule) (*pdApi.RulesetRule, *http.Response, error)
+	DeleteRulesetRule(rulesetID, ruleID string) error
+	GetRuleset(id string) (*pdApi.Ruleset, *http.Response, error)
+	ListServiceTags(serviceID string) ([]Tag, error)
+	ChangeServiceTags(serviceID string, add []string, remove []string) error
+	ListServiceAuditRecords(serviceID string) ([]AuditRecord, error)
+	ListAuditRecords(rootResourceType string, since time.Time) ([]AuditRecord, error)
+	ListServiceIncidentMetrics(serviceIDs []string, since, until time.Time) ([]IncidentMetrics, error)
+	GetServiceAlertGrouping(serviceID string) (*AlertGrouping, error)
+	UpdateServiceAlertGrouping(serviceID string, grouping *AlertGrouping) error
+	ListServiceDependencies(serviceID string) ([]ServiceDependency, error)
+	AssociateServiceDependencies(dependencies []ServiceDependency) error
+	GetTeam(id string) (*pdApi.Team, error)
+	ListTeams(o pdApi.ListTeamOptions) (*pdApi.ListTeamResponse, error)
+}
+
+// References are the PagerDuty resources a PagerDutyIntegration refers to
+type References struct {
+	EscalationPolicyID string
+	// EscalationPolicyIDs are further escalation policies services are
+	// created with
+	EscalationPolicyIDs []string
+	RulesetIDs          []string
+	ServiceIDs          []string
+	// Team is the ID or name of a team, none if empty
+	Team string
+}
+
+// listServicesPageSize is how many services are requested per page when
+// listing services
+const listServicesPageSize = 100
+
+// incidentMetricsBatchSize is how many services the incident analytics are
+// requested for at once
+const incidentMetricsBatchSize = 100
+
+// listIncidentsPageSize is the page size used when listing incidents
+const listIncidentsPageSize = 100
+
+// MaxServiceNameLength is the longest service name PagerDuty accepts
+const MaxServiceNameLength = 255
+
+// integrationName and integrationType describe the integration the
+// operator creates on each service, whose key is synced to the cluster,
+// unless Data.IntegrationType asks for another type
+const (
+	integrationName = "V4 Alertmanager"
+	integrationType = "events_api_v2_inbound_integration"
+)
+
+// Values of Data.IntegrationType
+const (
+	IntegrationTypeEventsAPIV2      = "events_api_v2"
+	IntegrationTypePrometheus       = "prometheus"
+	IntegrationTypeGenericEventsAPI = "generic_events_api"
+)
+
+// genericIntegrationType is the type of the Events API v1 integrations,
+// vendor integrations such as Prometheus' included
+const genericIntegrationType = "generic_events_api_inbound_integration"
+
+// prometheusVendorName is the name of the PagerDuty vendor of Prometheus
+// integrations
+const prometheusVendorName = "Prometheus"
+
+// heartbeatIntegrationName is the name of the integration the operator
+// pages through when a cluster stops checking in
+const heartbeatIntegrationName = "Heartbeat"
+
+// alertCreation is how the services of the operator handle events, each
+// alert opening an incident
+const alertCreation = "create_alerts_and_incidents"
+
+// TestAlertResult is the outcome of a synthetic test alert
+type TestAlertResult struct {
+	DedupKey string
+	Accepted bool
+	Latency  time.Duration
+}
+
+// IncidentStats describe the incidents of a service over a period
+type IncidentStats struct {
+	// Incidents created in the period
+	Incidents int
+	// DuplicateIncidents repeat the title of an earlier incident of the
+	// period, so alert grouping would have merged them
+	DuplicateIncidents int
+	// AutoResolvedIncidents were resolved by the monitoring tool rather than
+	// by a responder
+	AutoResolvedIncidents int
+}
+
+// AccountQuotaExceededError is returned by CreateService when the PagerDuty
+// account has reached its limit of services. Retrying will keep failing
+// until services are deleted or the limit is raised.
+type AccountQuotaExceededError struct {
+	Err error
+}
+
+func (e *AccountQuotaExceededError) Error() string {
+	return "PagerDuty account service quota exceeded: " + e.Err.Error()
+}
+
+// IsAccountQuotaExceeded returns true if err is an AccountQuotaExceededError
+func IsAccountQuotaExceeded(err error) bool {
+	_, ok := err.(*AccountQuotaExceededError)
+	return ok
+}
+
+// isAccountQuotaExceeded recognizes the API responses for an account that
+// cannot have more services. go-pagerduty only returns the status code and
+// body as text, so this matches on the message.
+func isAccountQuotaExceeded(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "http response code: 402") ||
+		strings.Contains(msg, "service limit") ||
+		strings.Contains(msg, "limit reached")
+}
+
+// IsNotFound returns true if err is the PagerDuty API reporting that the
+// requested resource does not exist
+func IsNotFound(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "http response code: 404")
+}
+
+// IsAPIError returns true if err is the PagerDuty API failing a call, as
+// opposed to an error of the operator or of the hub
+func IsAPIError(err error) bool {
+	return IsRateLimited(err) || IsAccountQuotaExceeded(err) ||
+		strings.Contains(strings.ToLower(err.Error()), "http response code:")
+}
+
+// IsTransient returns true if err is the PagerDuty API failing a call with a
+// server error, or the API not being reached, which calling again later may
+// fix
+func IsTransient(err error) bool {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "http response code: 5")
+}
+
+// IsUnauthorized returns true if err is the PagerDuty API refusing the API
+// key, as when it was revoked
+func IsUnauthorized(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "http response code: 401")
+}
+
+// IsRateLimited returns true if err is the PagerDuty API refusing a call
+// because the rate limit of the API key was exceeded
+func IsRateLimited(err error) bool {
+	if _, ok := err.(*RateLimitError); ok {
+		return true
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "http response code: 429")
 }
 
 type ManageEventFunc func(pdApi.V2Event) (*pdApi.V2EventResponse, error)
 type DelayFunc func(time.Duration)
+type ChangeEventFunc func(ChangeEvent) error
 
 //SvcClient wraps pdApi.Client
 type SvcClient struct {
 	APIKey      string
 	PdClient    PdClient
 	ManageEvent ManageEventFunc
+	ChangeEvent ChangeEventFunc
 	Delay       DelayFunc
+
+	// escalationPolicies caches the escalation policies looked up by this
+	// client, so the services of many clusters don't each fetch them
+	escalationPolicies map[string]*pdApi.EscalationPolicy
+	// teams caches the teams looked up by this client by the ID or name
+	// they were looked up with
+	teams map[string]*pdApi.Team
+	// mu guards the caches, the clusters of a PagerDutyIntegration may be
+	// set up concurrently
+	mu sync.Mutex
 }
 
 type customHTTPClient struct {
 	pdApi.HTTPClient
 	controller string
+	// sleep waits between retries, time.Sleep if nil
+	sleep func(time.Duration)
+	// observe is told about each call, localmetrics.AddAPICall if nil
+	observe APICallObserver
+	// logger logs each call if set
+	logger logr.Logger
+	// limiter is waited for before each call if set
+	limiter RateLimiter
+	// endpoint is the PagerDuty REST API called, apiEndpoint if empty
+	endpoint string
 }
 
-// Do wrapping standard call to time it
+// Do wrapping standard call to time it, retrying it when rate limited or
+// failed by PagerDuty
 func (c customHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	sleep := c.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	return doWithRetry(c.timedDo, sleep, req)
+}
+
+// timedDo sends req once, timing it
+func (c customHTTPClient) timedDo(req *http.Request) (*http.Response, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
 	start := time.Now()
+	done := apiStats.start(req)
 
 	resp, err := c.HTTPClient.Do(req)
 
-	if err == nil {
-		localmetrics.AddAPICall(c.controller, req, resp, time.Since(start).Seconds())
+	duration := time.Since(start).Seconds()
+	apiHealth.observe(req, resp, err)
+	done(resp, err)
+	if err != nil {
+		if c.logger != nil {
+			c.logger.Error(err, "PagerDuty API call failed", "Method", req.Method, "Path", req.URL.Path)
+		}
+		return resp, err
+	}
+
+	observe := c.observe
+	if observe == nil {
+		observe = localmetrics.AddAPICall
+	}
+	observe(c.controller, req, resp, duration)
+	if c.logger != nil {
+		c.logger.V(1).Info("PagerDuty API call", "Method", req.Method, "Path", req.URL.Path, "Status", resp.StatusCode, "Duration", duration)
 	}
 
 	return resp, err
 }
 
 // WithCustomHTTPClient allows to wrapper to monitor API response time
-func WithCustomHTTPClient(controllerName string) pdApi.ClientOptions {
+func WithCustomHTTPClient(controllerName string, opts ...ClientOption) pdApi.ClientOptions {
 	return func(c *pdApi.Client) {
-		c.HTTPClient = customHTTPClient{
+		httpClient := customHTTPClient{
 			HTTPClient: c.HTTPClient,
 			controller: controllerName,
 		}
+		for _, opt := range opts {
+			opt(&httpClient)
+		}
+		c.HTTPClient = httpClient
 	}
 }
 
 //NewClient creates out client wrapper object for the actual pdApi.Client we use.
-func NewClient(APIKey string, controllerName string) Client {
+//The options customize how the PagerDuty API is called. Clients of the same
+//API endpoint share their connections to PagerDuty, unless given
+//WithHTTPClient, and clients of the same API key the pages of services they
+//list, see ListServices.
+func NewClient(APIKey string, controllerName string, opts ...ClientOption) Client {
+	logging.AddSecret(APIKey)
+	endpoint := clientEndpoint(opts)
+	pooled := func(c *pdApi.Client) {
+		c.HTTPClient = httpClients.get(endpoint)
+	}
 	return &SvcClient{
-		APIKey:      APIKey,
-		PdClient:    pdApi.NewClient(APIKey, WithCustomHTTPClient(controllerName)),
+		APIKey: APIKey,
+		PdClient: &cachingPdClient{
+			PdClient: &apiClient{
+				Client:   pdApi.NewClient(APIKey, pooled, WithCustomHTTPClient(controllerName, opts...), pdApi.WithAPIEndpoint(endpoint)),
+				apiKey:   APIKey,
+				endpoint: endpoint,
+			},
+			cache: listCaches.get(APIKey),
+		},
 		ManageEvent: pdApi.ManageEvent,
+		ChangeEvent: sendChangeEvent,
 		Delay:       time.Sleep,
 	}
 }
 
+// clientEndpoint returns the PagerDuty REST API the options make the client
+// call, apiEndpoint unless given WithAPIEndpoint
+func clientEndpoint(opts []ClientOption) string {
+	var c customHTTPClient
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.endpoint == "" {
+		return apiEndpoint
+	}
+	return c.endpoint
+}
+
 // Data describes the data that is needed for PagerDuty api calls
 type Data struct {
 	EscalationPolicyID string
@@ -136,15 +429,67 @@ type Data struct {
 	APIKey             string
 	ClusterID          string
 	BaseDomain         string
+	// NormalizeName makes the service name fit PagerDuty, see
+	// NormalizeServiceName
+	NormalizeName bool
+	// ServiceNameTemplate names the service instead of the default
+	// <prefix>-<cluster ID>.<base domain>-hive-cluster when set, see
+	// ExecuteServiceNameTemplate
+	ServiceNameTemplate string
+	// Labels of the ClusterDeployment, for ServiceNameTemplate
+	Labels map[string]string
+
+	// Urgency of new incidents, config.PagerDutyUrgencyRule when empty, or
+	// of those raised during SupportHours when set
+	Urgency string
+	// Urgency of the incidents raised outside SupportHours, low when empty
+	OutsideSupportHoursUrgency string
+	SupportHours               *pdApi.SupportHours
+
+	// How the alerts of the service are grouped into incidents, left alone
+	// when nil
+	AlertGrouping *AlertGrouping
+
+	// ID or name of the team the service is assigned to, left alone when
+	// empty
+	Team string
+
+	// Type of the integration whose key is synced to the cluster, one of
+	// the IntegrationType values, Events API v2 when empty
+	IntegrationType string
+
+	// Metadata of the cluster described to responders in the description
+	// of the service, which is left alone when nil
+	Metadata *ClusterMetadata
 
 	ServiceID     string
 	IntegrationID string
 }
 
-// ParseClusterConfig parses the cluster specific config map and stores the IDs in the data struct
+// ParseClusterConfig reads the IDs of the cluster's service from its
+// PagerDutyService, or from the ConfigMap of the same name when the cluster
+// wasn't migrated to a PagerDutyService yet, and stores them in the data
+// struct
 func (data *Data) ParseClusterConfig(osc client.Client, namespace string, cmName string) error {
+	pdService := &pagerdutyv1alpha1.PagerDutyService{}
+	err := osc.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: cmName}, pdService)
+	if err == nil {
+		if pdService.Spec.ServiceID == "" {
+			return errors.New("PagerDutyService has no serviceID")
+		}
+		if pdService.Spec.IntegrationID == "" {
+			return errors.New("PagerDutyService has no integrationID")
+		}
+		data.ServiceID = pdService.Spec.ServiceID
+		data.IntegrationID = pdService.Spec.IntegrationID
+		return nil
+	}
+	if !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return err
+	}
+
 	pdAPIConfigMap := &corev1.ConfigMap{}
-	err := osc.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: cmName}, pdAPIConfigMap)
+	err = osc.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: cmName}, pdAPIConfigMap)
 	if err != nil {
 		return err
 	}
@@ -184,59 +529,378 @@ func (c *SvcClient) GetIntegrationKey(data *Data) (string, error) {
 
 // CreateService creates a service in pagerduty for the specified clusterid and returns the service key
 func (c *SvcClient) CreateService(data *Data) (string, error) {
-	escalationPolicy, err := c.PdClient.GetEscalationPolicy(string(data.EscalationPolicyID), nil)
+	escalationPolicy, err := c.getEscalationPolicy(data.EscalationPolicyID)
 	if err != nil {
 		return "", errors.New("Escalation policy not found in PagerDuty")
 	}
 
 	clusterService := pdApi.Service{
-		Name:                   data.ServicePrefix + "-" + data.ClusterID + "." + data.BaseDomain + "-hive-cluster",
-		Description:            data.ClusterID + " - A managed hive created cluster",
+		Name:                   ServiceName(data),
+		Description:            ServiceDescription(data),
 		EscalationPolicy:       *escalationPolicy,
 		AutoResolveTimeout:     &data.AutoResolveTimeout,
 		AcknowledgementTimeout: &data.AcknowledgeTimeOut,
-		AlertCreation:          "create_alerts_and_incidents",
-		IncidentUrgencyRule: &pdApi.IncidentUrgencyRule{
-			Type:    "constant",
-			Urgency: config.PagerDutyUrgencyRule,
-		},
+		AlertCreation:          alertCreation,
+		IncidentUrgencyRule:    incidentUrgencyRule(data),
+		SupportHours:           data.SupportHours,
+	}
+	if data.Team != "" {
+		team, err := c.getTeam(data.Team)
+		if err != nil {
+			return "", err
+		}
+		clusterService.Teams = []pdApi.Team{teamReference(team)}
 	}
 
 	var newSvc *pdApi.Service
 	newSvc, err = c.PdClient.CreateService(clusterService)
 	if err != nil {
+		if isAccountQuotaExceeded(err) {
+			return "", &AccountQuotaExceededError{Err: err}
+		}
 		if !strings.Contains(err.Error(), "Name has already been taken") {
 			return "", err
 		}
-		lso := pdApi.ListServiceOptions{}
-		lso.Query = clusterService.Name
-		currentSvcs, newerr := c.PdClient.ListServices(lso)
-		if newerr != nil {
+		existing, newerr := c.findService(clusterService.Name)
+		if newerr != nil || existing == nil {
+			return "", err
+		}
+		err = c.AdoptService(data, existing)
+		if err != nil {
 			return "", err
 		}
+		return data.IntegrationID, nil
+	}
+	data.ServiceID = newSvc.ID
 
-		if len(currentSvcs.Services) > 0 {
-			for _, svc := range currentSvcs.Services {
-				if svc.Name == clusterService.Name {
-					newSvc = &svc
-					break
-				}
+	data.IntegrationID, err = c.createServiceIntegration(newSvc.ID, data)
+	if err != nil {
+		return "", err
+	}
+
+	// go-pagerduty can't create services with alert grouping parameters
+	if data.AlertGrouping != nil {
+		err = c.SetAlertGrouping(data)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return data.IntegrationID, err
+}
+
+// FindServiceByName returns the service bearing the name CreateService
+// gives the service of data, or the name it had before normalization, with
+// its integrations. It returns nil if there is none, as when the service of
+// data still has to be created.
+func (c *SvcClient) FindServiceByName(data *Data) (*pdApi.Service, error) {
+	names := []string{ServiceName(data)}
+	if legacy := legacyServiceName(data); legacy != names[0] {
+		names = append(names, legacy)
+	}
+
+	for _, name := range names {
+		service, err := c.findService(name)
+		if err != nil || service != nil {
+			return service, err
+		}
+	}
+	return nil, nil
+}
+
+// findService returns the service named name, or nil if there is none
+func (c *SvcClient) findService(name string) (*pdApi.Service, error) {
+	lso := pdApi.ListServiceOptions{
+		Query:    name,
+		Includes: []string{"integrations"},
+	}
+	services, err := c.PdClient.ListServices(lso)
+	if err != nil {
+		return nil, err
+	}
+
+	// the query also matches services whose name only contains name
+	for i := range services.Services {
+		if services.Services[i].Name == name {
+			return &services.Services[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// AdoptService makes service, found by FindServiceByName, the service of
+// data. The integration the operator creates is reused if the service has
+// one, so its integration key stays the same, otherwise it is created.
+func (c *SvcClient) AdoptService(data *Data, service *pdApi.Service) error {
+	data.ServiceID = service.ID
+	data.IntegrationID = IntegrationID(service)
+	if data.IntegrationID != "" {
+		return nil
+	}
+
+	var err error
+	data.IntegrationID, err = c.createServiceIntegration(service.ID, data)
+	return err
+}
+
+// IntegrationID returns the ID of the integration the operator creates on
+// service, of any IntegrationType, "" if service, listed with its
+// integrations, has none
+func IntegrationID(service *pdApi.Service) string {
+	for _, integration := range service.Integrations {
+		if integration.Name == integrationName && (integration.Type == integrationType || integration.Type == genericIntegrationType) {
+			return integration.ID
+		}
+	}
+	return ""
+}
+
+// ListServicesByPrefix returns the services whose name starts with prefix
+// followed by '-', as the services named after a servicePrefix, with their
+// integrations. The prefix is compared regardless of case, as normalized
+// names are in lower case.
+func (c *SvcClient) ListServicesByPrefix(prefix string) ([]pdApi.Service, error) {
+	listed, err := c.ListServices(prefix)
+	if err != nil {
+		return nil, err
+	}
+	start := strings.ToLower(prefix) + "-"
+
+	var services []pdApi.Service
+	// the query also matches services whose name only contains prefix
+	for _, service := range listed {
+		if strings.HasPrefix(strings.ToLower(service.Name), start) {
+			services = append(services, service)
+		}
+	}
+	return services, nil
+}
+
+// ListServices returns all the services whose name contains query, all of
+// them if it is empty, with their integrations. The services are listed a
+// page at a time, and clients made by NewClient reuse the pages listed with
+// the same API key for a minute.
+func (c *SvcClient) ListServices(query string) ([]pdApi.Service, error) {
+	lso := pdApi.ListServiceOptions{
+		APIListObject: pdApi.APIListObject{Limit: listServicesPageSize},
+		Query:         query,
+		Includes:      []string{"integrations"},
+	}
+
+	var services []pdApi.Service
+	for {
+		page, err := c.PdClient.ListServices(lso)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, page.Services...)
+		if !page.More || len(page.Services) == 0 {
+			return services, nil
+		}
+		lso.Offset += uint(len(page.Services))
+	}
+}
+
+// ListIntegrations returns the integrations of all the services whose name
+// contains query, listed like ListServices, each referring to its service
+func (c *SvcClient) ListIntegrations(query string) ([]pdApi.Integration, error) {
+	services, err := c.ListServices(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var integrations []pdApi.Integration
+	for _, service := range services {
+		for _, integration := range service.Integrations {
+			if integration.Service == nil {
+				integration.Service = &pdApi.APIObject{ID: service.ID, Type: "service_reference"}
 			}
+			integrations = append(integrations, integration)
 		}
+	}
+	return integrations, nil
+}
 
-		if newSvc == nil {
-			return "", err
+// ServiceName returns the name of the service created for data
+func ServiceName(data *Data) string {
+	name := legacyServiceName(data)
+	if data.ServiceNameTemplate != "" {
+		// the controller doesn't create services whose template fails, so
+		// this only falls back to the default name for lookups
+		if templated, err := ExecuteServiceNameTemplate(data); err == nil {
+			name = templated
 		}
 	}
-	data.ServiceID = newSvc.ID
+	if data.NormalizeName {
+		return NormalizeServiceName(name)
+	}
+	return name
+}
+
+// ClusterMetadata is what responders are told about the cluster of a
+// service, empty fields are left out
+type ClusterMetadata struct {
+	// ClusterUUID is the ID OpenShift gave the cluster
+	ClusterUUID   string
+	CloudProvider string
+	Region        string
+	ConsoleURL    string
+}
+
+// ServiceDescription returns the description of the service created for
+// data, listing its cluster metadata
+func ServiceDescription(data *Data) string {
+	description := data.ClusterID + " - A managed hive created cluster"
+	if data.Metadata == nil {
+		return description
+	}
+
+	lines := []string{description}
+	for _, field := range []struct{ name, value string }{
+		{"Cluster ID", data.Metadata.ClusterUUID},
+		{"Base domain", data.BaseDomain},
+		{"Cloud provider", data.Metadata.CloudProvider},
+		{"Region", data.Metadata.Region},
+		{"Console", data.Metadata.ConsoleURL},
+	} {
+		if field.value != "" {
+			lines = append(lines, field.name+": "+field.value)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ServiceNameData is what a service name template is executed with
+type ServiceNameData struct {
+	// Prefix is the servicePrefix of the PagerDutyIntegration
+	Prefix     string
+	ClusterID  string
+	BaseDomain string
+	// Labels of the ClusterDeployment, such as its region or environment
+	Labels map[string]string
+}
 
-	data.IntegrationID, err = c.createIntegration(newSvc.ID, "V4 Alertmanager", "events_api_v2_inbound_integration")
+// ExecuteServiceNameTemplate returns the name ServiceNameTemplate gives the
+// service of data. It fails when the template refers to a label the
+// ClusterDeployment doesn't have, or renders an empty name.
+func ExecuteServiceNameTemplate(data *Data) (string, error) {
+	tmpl, err := template.New("serviceName").Option("missingkey=error").Parse(data.ServiceNameTemplate)
 	if err != nil {
 		return "", err
 	}
 
-	return data.IntegrationID, err
+	var b strings.Builder
+	err = tmpl.Execute(&b, ServiceNameData{
+		Prefix:     data.ServicePrefix,
+		ClusterID:  data.ClusterID,
+		BaseDomain: data.BaseDomain,
+		Labels:     data.Labels,
+	})
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimSpace(b.String())
+	if name == "" {
+		return "", fmt.Errorf("service name template %q renders an empty name", data.ServiceNameTemplate)
+	}
+	return name, nil
+}
+
+// ValidateServiceNameTemplate returns why text can't be a service name
+// template, nil if it can. It is executed for a sample cluster, so a
+// template referring to fields ServiceNameData doesn't have is refused.
+// Labels aren't checked, as they differ between clusters.
+func ValidateServiceNameTemplate(text string) error {
+	tmpl, err := template.New("serviceName").Parse(text)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	err = tmpl.Execute(&b, ServiceNameData{
+		Prefix:     "prefix",
+		ClusterID:  "cluster",
+		BaseDomain: "example.com",
+		Labels:     map[string]string{},
+	})
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(b.String()) == "" {
+		return fmt.Errorf("service name template %q renders an empty name", text)
+	}
+	return nil
+}
+
+// legacyServiceName returns the name of the service created for data
+// without normalization, as services used to be named
+func legacyServiceName(data *Data) string {
+	return data.ServicePrefix + "-" + data.ClusterID + "." + data.BaseDomain + "-hive-cluster"
+}
+
+// NormalizeServiceName returns name in lower case, with each run of
+// characters other than ASCII letters, digits, '-' and '.' replaced with a
+// single '-'. Names longer than MaxServiceNameLength are truncated and end
+// with a hash of the whole name, so they stay distinct. The same name always
+// gives the same result.
+func NormalizeServiceName(name string) string {
+	var b strings.Builder
+	replaced := false
+	for _, c := range strings.ToLower(name) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '.' {
+			b.WriteRune(c)
+			replaced = false
+			continue
+		}
+		if !replaced {
+			b.WriteRune('-')
+			replaced = true
+		}
+	}
+	normalized := strings.Trim(b.String(), "-.")
+
+	if len(normalized) > MaxServiceNameLength {
+		sum := sha256.Sum256([]byte(name))
+		suffix := "-" + hex.EncodeToString(sum[:])[:8]
+		normalized = strings.TrimRight(normalized[:MaxServiceNameLength-len(suffix)], "-.") + suffix
+	}
+	return normalized
+}
+
+// incidentUrgencyRule returns the urgency rule of the services created for
+// data
+func incidentUrgencyRule(data *Data) *pdApi.IncidentUrgencyRule {
+	urgency := data.Urgency
+	if urgency == "" {
+		urgency = config.PagerDutyUrgencyRule
+	}
+	if data.SupportHours == nil {
+		return &pdApi.IncidentUrgencyRule{
+			Type:    "constant",
+			Urgency: urgency,
+		}
+	}
+
+	outsideUrgency := data.OutsideSupportHoursUrgency
+	if outsideUrgency == "" {
+		outsideUrgency = "low"
+	}
+	return &pdApi.IncidentUrgencyRule{
+		Type:                "use_support_hours",
+		DuringSupportHours:  incidentUrgencyType(urgency),
+		OutsideSupportHours: incidentUrgencyType(outsideUrgency),
+	}
+}
+
+// incidentUrgencyType returns the urgency rule of one side of the support
+// hours, where severity based urgency is a type of its own
+func incidentUrgencyType(urgency string) *pdApi.IncidentUrgencyType {
+	if urgency == "severity_based" {
+		return &pdApi.IncidentUrgencyType{Type: "severity_based"}
+	}
+	return &pdApi.IncidentUrgencyType{Type: "constant", Urgency: urgency}
 }
+
 func (c *SvcClient) createIntegration(serviceId, name, integrationType string) (string, error) {
 	newIntegration := pdApi.Integration{
 		Name: name,
@@ -250,6 +914,48 @@ func (c *SvcClient) createIntegration(serviceId, name, integrationType string) (
 	return newInt.ID, nil
 }
 
+// createServiceIntegration creates the integration whose key is synced to
+// the cluster on the service serviceID, of the type data.IntegrationType
+// asks for, and returns its ID
+func (c *SvcClient) createServiceIntegration(serviceID string, data *Data) (string, error) {
+	integration := pdApi.Integration{
+		Name: integrationName,
+		Type: integrationType,
+	}
+	switch data.IntegrationType {
+	case IntegrationTypeGenericEventsAPI:
+		integration.Type = genericIntegrationType
+	case IntegrationTypePrometheus:
+		vendorID, err := c.findVendor(prometheusVendorName)
+		if err != nil {
+			return "", err
+		}
+		integration.Type = genericIntegrationType
+		integration.Vendor = &pdApi.APIObject{ID: vendorID, Type: "vendor_reference"}
+	}
+
+	newInt, err := c.PdClient.CreateIntegration(serviceID, integration)
+	if err != nil {
+		return "", err
+	}
+	return newInt.ID, nil
+}
+
+// findVendor returns the ID of the PagerDuty vendor named name
+func (c *SvcClient) findVendor(name string) (string, error) {
+	vendors, err := c.PdClient.ListVendors(pdApi.ListVendorOptions{Query: name})
+	if err != nil {
+		return "", err
+	}
+	// the query also matches vendors whose name only contains name
+	for _, vendor := range vendors.Vendors {
+		if vendor.Name == name {
+			return vendor.ID, nil
+		}
+	}
+	return "", fmt.Errorf("PagerDuty vendor %s not found", name)
+}
+
 // DeleteService will get a service from the PD api and delete it
 func (c *SvcClient) DeleteService(data *Data) error {
 	err := c.resolvePendingIncidents(data)
@@ -336,3 +1042,811 @@ func (c *SvcClient) resolveIncident(serviceKey, incidentKey string) error {
 	_, err := c.ManageEvent(event)
 	return err
 }
+
+// CreateMaintenanceWindow puts the service described by data into maintenance
+// between start and end, and returns the ID of the new maintenance window
+func (c *SvcClient) CreateMaintenanceWindow(data *Data, description string, start time.Time, end time.Time) (string, error) {
+	window := pdApi.MaintenanceWindow{
+		StartTime:   start.UTC().Format(time.RFC3339),
+		EndTime:     end.UTC().Format(time.RFC3339),
+		Description: description,
+		Services: []pdApi.APIObject{
+			{
+				ID:   data.ServiceID,
+				Type: "service_reference",
+			},
+		},
+	}
+
+	newWindow, err := c.PdClient.CreateMaintenanceWindow("", window)
+	if err != nil {
+		return "", err
+	}
+
+	return newWindow.ID, nil
+}
+
+// DeleteMaintenanceWindow deletes a future maintenance window, or ends it if
+// it is currently in progress
+func (c *SvcClient) DeleteMaintenanceWindow(id string) error {
+	return c.PdClient.DeleteMaintenanceWindow(id)
+}
+
+// CreateSuppressionRule adds a rule to the global ruleset suppressing the
+// events whose custom detail equals value between start and end, and returns
+// its ID. An active rule for the same value is reused, and rules added this
+// way that expired before start are deleted so they do not pile up.
+func (c *SvcClient) CreateSuppressionRule(rulesetID string, detail string, value string, start time.Time, end time.Time) (string, error) {
+	path := "payload.custom_details." + detail
+
+	rules, err := c.PdClient.ListRulesetRules(rulesetID)
+	if err != nil {
+		return "", err
+	}
+
+	startMillis := int(start.UnixNano() / int64(time.Millisecond))
+	for _, rule := range rules.Rules {
+		ruleValue, ruleEnd, ok := suppressionRule(rule, path)
+		if !ok {
+			continue
+		}
+		if ruleEnd < startMillis {
+			if err := c.PdClient.DeleteRulesetRule(rulesetID, rule.ID); err != nil {
+				return "", err
+			}
+			continue
+		}
+		if ruleValue == value {
+			return rule.ID, nil
+		}
+	}
+
+	rule := &pdApi.RulesetRule{
+		Conditions: &pdApi.RuleConditions{
+			Operator: "and",
+			RuleSubconditions: []*pdApi.RuleSubcondition{
+				{
+					Operator: "equals",
+					Parameters: &pdApi.ConditionParameter{
+						Path:  path,
+						Value: value,
+					},
+				},
+			},
+		},
+		Actions: &pdApi.RuleActions{
+			Suppress: &pdApi.RuleActionSuppress{
+				Value: true,
+			},
+		},
+		TimeFrame: &pdApi.RuleTimeFrame{
+			ActiveBetween: &pdApi.ActiveBetween{
+				StartTime: startMillis,
+				EndTime:   int(end.UnixNano() / int64(time.Millisecond)),
+			},
+		},
+	}
+
+	newRule, _, err := c.PdClient.CreateRulesetRule(rulesetID, rule)
+	if err != nil {
+		return "", err
+	}
+
+	return newRule.ID, nil
+}
+
+// suppressionRule returns the value matched by a rule created by
+// CreateSuppressionRule on path and the time it expires at, in milliseconds.
+// ok is false for any other rule.
+func suppressionRule(rule *pdApi.RulesetRule, path string) (value string, end int, ok bool) {
+	if rule.Actions == nil || rule.Actions.Suppress == nil || !rule.Actions.Suppress.Value {
+		return "", 0, false
+	}
+	if rule.TimeFrame == nil || rule.TimeFrame.ActiveBetween == nil {
+		return "", 0, false
+	}
+	if rule.Conditions == nil || len(rule.Conditions.RuleSubconditions) != 1 {
+		return "", 0, false
+	}
+	subcondition := rule.Conditions.RuleSubconditions[0]
+	if subcondition.Operator != "equals" || subcondition.Parameters == nil || subcondition.Parameters.Path != path {
+		return "", 0, false
+	}
+	return subcondition.Parameters.Value, rule.TimeFrame.ActiveBetween.EndTime, true
+}
+
+// SetServiceTags makes the tags of the service described by data match tags.
+// Each key of tags is managed by the operator: the service is tagged
+// "<key>:<value>", and any other tag for that key is removed, as is the tag
+// of a key whose value is empty. Tags for other keys are left alone.
+func (c *SvcClient) SetServiceTags(data *Data, tags map[string]string) error {
+	current, err := c.PdClient.ListServiceTags(data.ServiceID)
+	if err != nil {
+		return err
+	}
+
+	present := map[string]bool{}
+	remove := []string{}
+	for _, tag := range current {
+		parts := strings.SplitN(tag.Label, ":", 2)
+		value, managed := tags[parts[0]]
+		if !managed || len(parts) != 2 {
+			continue
+		}
+		if parts[1] == value {
+			present[tag.Label] = true
+			continue
+		}
+		remove = append(remove, tag.ID)
+	}
+
+	add := []string{}
+	for key, value := range tags {
+		label := key + ":" + value
+		if value != "" && !present[label] {
+			add = append(add, label)
+		}
+	}
+
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+	sort.Strings(add)
+	return c.PdClient.ChangeServiceTags(data.ServiceID, add, remove)
+}
+
+// ServiceDrift returns how the settings of service differ from those the
+// operator creates services with for data, one description per setting.
+func ServiceDrift(data *Data, service *pdApi.Service) []string {
+	drift := []string{}
+	if service.EscalationPolicy.ID != data.EscalationPolicyID {
+		drift = append(drift, fmt.Sprintf("escalation policy is %s instead of %s", service.EscalationPolicy.ID, data.EscalationPolicyID))
+	}
+	if timeout := timeoutValue(service.AutoResolveTimeout); timeout != data.AutoResolveTimeout {
+		drift = append(drift, fmt.Sprintf("auto resolve timeout is %d instead of %d", timeout, data.AutoResolveTimeout))
+	}
+	if timeout := timeoutValue(service.AcknowledgementTimeout); timeout != data.AcknowledgeTimeOut {
+		drift = append(drift, fmt.Sprintf("acknowledgement timeout is %d instead of %d", timeout, data.AcknowledgeTimeOut))
+	}
+	if serviceRenamed(data, service) {
+		drift = append(drift, fmt.Sprintf("name is %s instead of %s", service.Name, ServiceName(data)))
+	}
+	if service.AlertCreation != alertCreation {
+		drift = append(drift, fmt.Sprintf("alert creation is %s instead of %s", service.AlertCreation, alertCreation))
+	}
+	if data.Metadata != nil && service.Description != ServiceDescription(data) {
+		drift = append(drift, "description does not list the current cluster metadata")
+	}
+	if rule, expected := urgencyRuleString(service.IncidentUrgencyRule), urgencyRuleString(incidentUrgencyRule(data)); rule != expected {
+		drift = append(drift, fmt.Sprintf("incident urgency is %s instead of %s", rule, expected))
+	}
+	// support hours only matter to services using them
+	if data.SupportHours != nil {
+		if hours, expected := supportHoursString(service.SupportHours), supportHoursString(data.SupportHours); hours != expected {
+			drift = append(drift, fmt.Sprintf("support hours are %s instead of %s", hours, expected))
+		}
+	}
+	if data.Team != "" && !serviceHasTeam(service, data.Team) {
+		drift = append(drift, fmt.Sprintf("team %s is not assigned", data.Team))
+	}
+	return drift
+}
+
+// serviceHasTeam returns true if the service is assigned to the team with
+// the given ID or name. Other teams of the service are left alone.
+func serviceHasTeam(service *pdApi.Service, team string) bool {
+	for _, t := range service.Teams {
+		if t.ID == team || t.Name == team || t.Summary == team {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceRenamed returns true if the service still has the name it was
+// created with before names were normalized or templated. Services renamed
+// by hand are left alone.
+func serviceRenamed(data *Data, service *pdApi.Service) bool {
+	return (data.NormalizeName || data.ServiceNameTemplate != "") && service.Name == legacyServiceName(data) && service.Name != ServiceName(data)
+}
+
+// urgencyRuleString describes an incident urgency rule, PagerDuty returns
+// more fields than the operator sets so rules are compared by description
+func urgencyRuleString(rule *pdApi.IncidentUrgencyRule) string {
+	if rule == nil {
+		return "unset"
+	}
+	if rule.Type == "use_support_hours" {
+		return fmt.Sprintf("%s during support hours and %s outside", urgencyTypeString(rule.DuringSupportHours), urgencyTypeString(rule.OutsideSupportHours))
+	}
+	return rule.Urgency
+}
+
+// urgencyTypeString describes the urgency of one side of the support hours
+func urgencyTypeString(urgency *pdApi.IncidentUrgencyType) string {
+	if urgency == nil {
+		return "unset"
+	}
+	if urgency.Type == "severity_based" {
+		return urgency.Type
+	}
+	return urgency.Urgency
+}
+
+// supportHoursString describes support hours
+func supportHoursString(hours *pdApi.SupportHours) string {
+	if hours == nil {
+		return "unset"
+	}
+	return fmt.Sprintf("%s to %s %s on days %v", hours.StartTime, hours.EndTime, hours.Timezone, hours.DaysOfWeek)
+}
+
+// timeoutValue returns the value of a service timeout, PagerDuty returns
+// null for a disabled one where the operator uses 0
+func timeoutValue(timeout *uint) uint {
+	if timeout == nil {
+		return 0
+	}
+	return *timeout
+}
+
+// RepairService sets the settings reported by ServiceDrift back to those of
+// data. Only these settings are sent, the rest of the service is left alone.
+func (c *SvcClient) RepairService(data *Data, service *pdApi.Service) error {
+	repaired := pdApi.Service{
+		APIObject: pdApi.APIObject{
+			ID: service.ID,
+		},
+		EscalationPolicy: pdApi.EscalationPolicy{
+			APIObject: pdApi.APIObject{
+				ID:   data.EscalationPolicyID,
+				Type: "escalation_policy_reference",
+			},
+		},
+		AutoResolveTimeout:     &data.AutoResolveTimeout,
+		AcknowledgementTimeout: &data.AcknowledgeTimeOut,
+		AlertCreation:          alertCreation,
+		IncidentUrgencyRule:    incidentUrgencyRule(data),
+		SupportHours:           data.SupportHours,
+	}
+	if serviceRenamed(data, service) {
+		repaired.Name = ServiceName(data)
+	}
+	if data.Metadata != nil {
+		repaired.Description = ServiceDescription(data)
+	}
+	if data.Team != "" && !serviceHasTeam(service, data.Team) {
+		team, err := c.getTeam(data.Team)
+		if err != nil {
+			return err
+		}
+		// the teams are replaced as a whole, so the others are sent along
+		for _, t := range service.Teams {
+			repaired.Teams = append(repaired.Teams, teamReference(&t))
+		}
+		repaired.Teams = append(repaired.Teams, teamReference(team))
+	}
+
+	_, err := c.PdClient.UpdateService(repaired)
+	return err
+}
+
+// SetServiceDependencies registers the dependency of the service described
+// by data on the service hubServiceID, and that of the business service
+// businessServiceID on it, unless they are registered already. An empty ID
+// registers nothing. Other dependencies of the service are left alone.
+func (c *SvcClient) SetServiceDependencies(data *Data, hubServiceID string, businessServiceID string) error {
+	wanted := []ServiceDependency{}
+	if hubServiceID != "" {
+		wanted = append(wanted, ServiceDependency{
+			SupportingService: pdApi.APIObject{ID: hubServiceID, Type: "service"},
+			DependentService:  pdApi.APIObject{ID: data.ServiceID, Type: "service"},
+		})
+	}
+	if businessServiceID != "" {
+		wanted = append(wanted, ServiceDependency{
+			SupportingService: pdApi.APIObject{ID: data.ServiceID, Type: "service"},
+			DependentService:  pdApi.APIObject{ID: businessServiceID, Type: "business_service"},
+		})
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	current, err := c.PdClient.ListServiceDependencies(data.ServiceID)
+	if err != nil {
+		return err
+	}
+
+	// PagerDuty returns references of other types than those registered,
+	// so dependencies are compared by the IDs of their services
+	missing := []ServiceDependency{}
+	for _, dependency := range wanted {
+		found := false
+		for _, existing := range current {
+			if existing.SupportingService.ID == dependency.SupportingService.ID && existing.DependentService.ID == dependency.DependentService.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, dependency)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return c.PdClient.AssociateServiceDependencies(missing)
+}
+
+// AlertGroupingDrift returns how the alert grouping of the service described
+// by data differs from data.AlertGrouping, which is none when it is nil.
+func (c *SvcClient) AlertGroupingDrift(data *Data) ([]string, error) {
+	if data.AlertGrouping == nil {
+		return []string{}, nil
+	}
+	grouping, err := c.PdClient.GetServiceAlertGrouping(data.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	if current, expected := alertGroupingString(grouping), alertGroupingString(data.AlertGrouping); current != expected {
+		return []string{fmt.Sprintf("alert grouping is %s instead of %s", current, expected)}, nil
+	}
+	return []string{}, nil
+}
+
+// SetAlertGrouping sets the alert grouping of the service described by data
+// to data.AlertGrouping
+func (c *SvcClient) SetAlertGrouping(data *Data) error {
+	return c.PdClient.UpdateServiceAlertGrouping(data.ServiceID, data.AlertGrouping)
+}
+
+// alertGroupingString describes an alert grouping, PagerDuty fills in the
+// defaults of the settings left out so groupings are compared by description
+func alertGroupingString(grouping *AlertGrouping) string {
+	if grouping == nil || grouping.Type == "" {
+		return "unset"
+	}
+	config := grouping.Config
+	if config == nil {
+		config = &AlertGroupingConfig{}
+	}
+	switch grouping.Type {
+	case "time":
+		return fmt.Sprintf("time (timeout %d)", timeoutValue(config.Timeout))
+	case "content_based":
+		aggregate := config.Aggregate
+		if aggregate == "" {
+			aggregate = "all"
+		}
+		fields := append([]string{}, config.Fields...)
+		sort.Strings(fields)
+		return fmt.Sprintf("content_based (%s of %s)", aggregate, strings.Join(fields, ","))
+	}
+	return grouping.Type
+}
+
+// DisableService disables the service described by data, so it stops
+// creating incidents but can still be looked at. The service is sent back
+// as read, as fields left empty would be cleared.
+func (c *SvcClient) DisableService(data *Data) error {
+	service, err := c.PdClient.GetService(data.ServiceID, nil)
+	if err != nil {
+		return err
+	}
+	if service.Status == "disabled" {
+		return nil
+	}
+
+	service.Status = "disabled"
+	_, err = c.PdClient.UpdateService(*service)
+	return err
+}
+
+// EnableService enables the service described by data again after
+// DisableService, so it creates incidents again
+func (c *SvcClient) EnableService(data *Data) error {
+	service, err := c.PdClient.GetService(data.ServiceID, nil)
+	if err != nil {
+		return err
+	}
+	if service.Status != "disabled" {
+		return nil
+	}
+
+	service.Status = "active"
+	_, err = c.PdClient.UpdateService(*service)
+	return err
+}
+
+// RenameService sets the name of the service described by data to the one
+// its data gives it, such as after its servicePrefix changed. The service
+// is sent back as read, as fields left empty would be cleared.
+func (c *SvcClient) RenameService(data *Data) error {
+	service, err := c.PdClient.GetService(data.ServiceID, nil)
+	if err != nil {
+		return err
+	}
+	name := ServiceName(data)
+	if service.Name == name {
+		return nil
+	}
+
+	service.Name = name
+	_, err = c.PdClient.UpdateService(*service)
+	return err
+}
+
+// LastServiceChanger returns who made the most recent change to the service
+// described by data according to its audit records, or "" if unknown.
+func (c *SvcClient) LastServiceChanger(data *Data) (string, error) {
+	records, err := c.PdClient.ListServiceAuditRecords(data.ServiceID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, record := range records {
+		if changer := record.Changer(); changer != "" {
+			return changer, nil
+		}
+	}
+	return "", nil
+}
+
+// SendChangeEvent sends a change event with the given summary and details to
+// the service of routingKey
+func (c *SvcClient) SendChangeEvent(routingKey string, summary string, details map[string]string) error {
+	return c.ChangeEvent(ChangeEvent{
+		RoutingKey: routingKey,
+		Payload: ChangeEventPayload{
+			Summary:       summary,
+			Source:        config.OperatorName,
+			Timestamp:     time.Now().UTC().Format(time.RFC3339),
+			CustomDetails: details,
+		},
+	})
+}
+
+// ListServiceChanges returns the audit records of the changes made to any
+// service of the account since the given time, newest first. Changes made
+// with the API key of the client, by the operator itself, are left out.
+func (c *SvcClient) ListServiceChanges(since time.Time) ([]AuditRecord, error) {
+	records, err := c.PdClient.ListAuditRecords("services", since)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []AuditRecord{}
+	for _, record := range records {
+		if record.Method != nil && record.Method.TruncatedToken != "" && strings.HasSuffix(c.APIKey, record.Method.TruncatedToken) {
+			continue
+		}
+		changes = append(changes, record)
+	}
+	return changes, nil
+}
+
+// getEscalationPolicy returns the escalation policy with the given ID,
+// fetching it only the first time.
+func (c *SvcClient) getEscalationPolicy(id string) (*pdApi.EscalationPolicy, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if policy, ok := c.escalationPolicies[id]; ok {
+		return policy, nil
+	}
+
+	policy, err := c.PdClient.GetEscalationPolicy(id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.escalationPolicies == nil {
+		c.escalationPolicies = map[string]*pdApi.EscalationPolicy{}
+	}
+	c.escalationPolicies[id] = policy
+	return policy, nil
+}
+
+// getTeam returns the team whose ID or, failing that, name is team, looked up
+// once per client. The error of looking the team up by ID is returned when
+// no team has that name either, so IsNotFound applies to it.
+func (c *SvcClient) getTeam(team string) (*pdApi.Team, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t, ok := c.teams[team]; ok {
+		return t, nil
+	}
+
+	t, err := c.PdClient.GetTeam(team)
+	if err != nil {
+		if !IsNotFound(err) {
+			return nil, err
+		}
+		teams, listErr := c.PdClient.ListTeams(pdApi.ListTeamOptions{Query: team})
+		if listErr != nil {
+			return nil, listErr
+		}
+		// the query also matches teams whose name only contains team
+		for i := range teams.Teams {
+			if teams.Teams[i].Name == team {
+				t = &teams.Teams[i]
+				break
+			}
+		}
+		if t == nil {
+			return nil, err
+		}
+	}
+
+	if c.teams == nil {
+		c.teams = map[string]*pdApi.Team{}
+	}
+	c.teams[team] = t
+	return t, nil
+}
+
+// teamReference returns the reference to team services are assigned with
+func teamReference(team *pdApi.Team) pdApi.Team {
+	return pdApi.Team{APIObject: pdApi.APIObject{ID: team.ID, Type: "team_reference"}}
+}
+
+// ValidateReferences looks up every resource of refs and returns a
+// description of each one that does not exist. The escalation policy and
+// team are cached for the services this client creates afterwards. An error
+// is only returned when a lookup failed for another reason.
+func (c *SvcClient) ValidateReferences(refs References) ([]string, error) {
+	missing := []string{}
+
+	_, err := c.getEscalationPolicy(refs.EscalationPolicyID)
+	if err != nil {
+		if !IsNotFound(err) {
+			return nil, err
+		}
+		missing = append(missing, fmt.Sprintf("escalation policy %s not found", refs.EscalationPolicyID))
+	}
+
+	for _, id := range refs.EscalationPolicyIDs {
+		_, err := c.getEscalationPolicy(id)
+		if err != nil {
+			if !IsNotFound(err) {
+				return nil, err
+			}
+			missing = append(missing, fmt.Sprintf("escalation policy %s not found", id))
+		}
+	}
+
+	for _, id := range refs.RulesetIDs {
+		_, _, err := c.PdClient.GetRuleset(id)
+		if err != nil {
+			if !IsNotFound(err) {
+				return nil, err
+			}
+			missing = append(missing, fmt.Sprintf("ruleset %s not found", id))
+		}
+	}
+
+	if refs.Team != "" {
+		_, err := c.getTeam(refs.Team)
+		if err != nil {
+			if !IsNotFound(err) {
+				return nil, err
+			}
+			missing = append(missing, fmt.Sprintf("team %s not found", refs.Team))
+		}
+	}
+
+	for _, id := range refs.ServiceIDs {
+		_, err := c.PdClient.GetService(id, nil)
+		if err != nil {
+			if !IsNotFound(err) {
+				return nil, err
+			}
+			missing = append(missing, fmt.Sprintf("service %s not found", id))
+		}
+	}
+
+	return missing, nil
+}
+
+// SendTestAlert triggers a synthetic test alert through the given
+// integration and resolves it right away. The result records whether
+// PagerDuty accepted the trigger and how long it took to.
+func (c *SvcClient) SendTestAlert(integrationKey string, clusterID string) (TestAlertResult, error) {
+	result := TestAlertResult{
+		DedupKey: fmt.Sprintf("pagerduty-operator-test-%s-%d", clusterID, time.Now().Unix()),
+	}
+
+	event := pdApi.V2Event{}
+	event.Payload = &pdApi.V2Payload{}
+	event.RoutingKey = integrationKey
+	event.Action = "trigger"
+	event.DedupKey = result.DedupKey
+	event.Payload.Summary = "Test alert from pagerduty-operator, resolved automatically"
+	event.Payload.Source = "pagerduty-operator"
+	event.Payload.Severity = "info"
+	event.Payload.Details = map[string]string{"cluster_id": clusterID}
+
+	start := time.Now()
+	resp, err := c.ManageEvent(event)
+	result.Latency = time.Since(start)
+	if err != nil {
+		return result, err
+	}
+	if resp == nil || resp.Status != "success" {
+		return result, fmt.Errorf("test alert not accepted: %+v", resp)
+	}
+	result.Accepted = true
+
+	return result, c.resolveTestAlert(integrationKey, result.DedupKey)
+}
+
+func (c *SvcClient) resolveTestAlert(integrationKey, dedupKey string) error {
+	event := pdApi.V2Event{}
+	event.Payload = &pdApi.V2Payload{}
+	event.RoutingKey = integrationKey
+	event.Action = "resolve"
+	event.DedupKey = dedupKey
+	event.Payload.Summary = "Test alert from pagerduty-operator, resolved automatically"
+	event.Payload.Source = "pagerduty-operator"
+	event.Payload.Severity = "info"
+	_, err := c.ManageEvent(event)
+	return err
+}
+
+// CreateHeartbeatIntegration creates the heartbeat integration on the
+// service of data and returns its ID
+func (c *SvcClient) CreateHeartbeatIntegration(data *Data) (string, error) {
+	return c.createIntegration(data.ServiceID, heartbeatIntegrationName, integrationType)
+}
+
+// RotateIntegrationKey replaces the integration of the service of data, so
+// its integration key changes, and sets data.IntegrationID to the new one.
+// The leaked key stops working first, an integration already deleted is
+// only recreated.
+func (c *SvcClient) RotateIntegrationKey(data *Data) error {
+	if data.IntegrationID != "" {
+		err := c.PdClient.DeleteIntegration(data.ServiceID, data.IntegrationID)
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+	}
+
+	var err error
+	data.IntegrationID, err = c.createServiceIntegration(data.ServiceID, data)
+	return err
+}
+
+// RecreateMissingIntegration creates the integration of the service of data
+// anew if it was deleted from a service that still exists, as when it was
+// removed in the PagerDuty UI, and sets data.IntegrationID to the new one.
+// GetIntegrationKey would fail for good otherwise. It returns true if it
+// created one.
+func (c *SvcClient) RecreateMissingIntegration(data *Data) (bool, error) {
+	if data.IntegrationID != "" {
+		_, err := c.PdClient.GetIntegration(data.ServiceID, data.IntegrationID, pdApi.GetIntegrationOptions{})
+		if err == nil || !IsNotFound(err) {
+			return false, err
+		}
+	}
+
+	// a deleted service has to be set up again instead
+	_, err := c.PdClient.GetService(data.ServiceID, nil)
+	if err != nil {
+		return false, err
+	}
+
+	integrationID, err := c.createServiceIntegration(data.ServiceID, data)
+	if err != nil {
+		return false, err
+	}
+	data.IntegrationID = integrationID
+	return true, nil
+}
+
+// SendHeartbeatEvent triggers the alert of a missed heartbeat through the
+// given heartbeat integration if missed, or resolves it otherwise. The alert
+// of a cluster is deduplicated, so it is triggered and resolved once.
+func (c *SvcClient) SendHeartbeatEvent(integrationKey string, clusterID string, missed bool) error {
+	event := pdApi.V2Event{}
+	event.Payload = &pdApi.V2Payload{}
+	event.RoutingKey = integrationKey
+	event.Action = "resolve"
+	if missed {
+		event.Action = "trigger"
+	}
+	event.DedupKey = "pagerduty-operator-heartbeat-" + clusterID
+	event.Payload.Summary = fmt.Sprintf("Cluster %s stopped checking in to its heartbeat", clusterID)
+	event.Payload.Source = "pagerduty-operator"
+	event.Payload.Severity = "critical"
+	event.Payload.Details = map[string]string{"cluster_id": clusterID}
+
+	resp, err := c.ManageEvent(event)
+	if err != nil {
+		return err
+	}
+	if resp == nil || resp.Status != "success" {
+		return fmt.Errorf("heartbeat event not accepted: %+v", resp)
+	}
+	return nil
+}
+
+// CountServiceIncidents returns how many incidents were created between since
+// and until on each of the given services, from the PagerDuty analytics.
+// Services without incidents are counted 0.
+func (c *SvcClient) CountServiceIncidents(serviceIDs []string, since time.Time, until time.Time) (map[string]int, error) {
+	counts := map[string]int{}
+	for _, id := range serviceIDs {
+		counts[id] = 0
+	}
+
+	for start := 0; start < len(serviceIDs); start += incidentMetricsBatchSize {
+		end := start + incidentMetricsBatchSize
+		if end > len(serviceIDs) {
+			end = len(serviceIDs)
+		}
+		metrics, err := c.PdClient.ListServiceIncidentMetrics(serviceIDs[start:end], since, until)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range metrics {
+			if _, ok := counts[m.ServiceID]; ok {
+				counts[m.ServiceID] = m.TotalIncidentCount
+			}
+		}
+	}
+	return counts, nil
+}
+
+// ServiceIncidentStats returns the stats of the incidents created between
+// since and until on each of the given services, listing the incidents
+// rather than using the analytics, which do not tell duplicates apart.
+// Services without incidents get empty stats.
+func (c *SvcClient) ServiceIncidentStats(serviceIDs []string, since time.Time, until time.Time) (map[string]IncidentStats, error) {
+	stats := map[string]IncidentStats{}
+	titles := map[string]map[string]bool{}
+	for _, id := range serviceIDs {
+		stats[id] = IncidentStats{}
+		titles[id] = map[string]bool{}
+	}
+
+	for start := 0; start < len(serviceIDs); start += incidentMetricsBatchSize {
+		end := start + incidentMetricsBatchSize
+		if end > len(serviceIDs) {
+			end = len(serviceIDs)
+		}
+		lio := pdApi.ListIncidentsOptions{
+			APIListObject: pdApi.APIListObject{Limit: listIncidentsPageSize},
+			Since:         since.UTC().Format(time.RFC3339),
+			Until:         until.UTC().Format(time.RFC3339),
+			ServiceIDs:    serviceIDs[start:end],
+			SortBy:        "created_at:asc",
+		}
+		for {
+			page, err := c.PdClient.ListIncidents(lio)
+			if err != nil {
+				return nil, err
+			}
+			for _, incident := range page.Incidents {
+				s, ok := stats[incident.Service.ID]
+				if !ok {
+					continue
+				}
+				s.Incidents++
+				if titles[incident.Service.ID][incident.Title] {
+					s.DuplicateIncidents++
+				}
+				titles[incident.Service.ID][incident.Title] = true
+				// incidents resolved by an event are last changed by the service
+				if incident.Status == "resolved" && incident.LastStatusChangeBy.Type == "service_reference" {
+					s.AutoResolvedIncidents++
+				}
+				stats[incident.Service.ID] = s
+			}
+			if !page.More || len(page.Incidents) == 0 {
+				break
+			}
+			lio.Offset += uint(len(page.Incidents))
+		}
+	}
+	return stats, nil
+}
@@ -1,6 +1,12 @@
 package pagerduty_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -124,3 +130,1142 @@ func TestDeleteServiceTwoPendingIncidentsResolveTimeout(t *testing.T) {
 	funcMock.AssertNumberOfCalls(t, "manageEvents", 2)
 	funcMock.AssertNumberOfCalls(t, "delay", 5)
 }
+
+func TestCreateServiceAccountQuotaExceeded(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	mockPdClient.EXPECT().GetEscalationPolicy(gomock.Any(), gomock.Any()).Return(&pdApi.EscalationPolicy{}, nil).Times(1)
+	mockPdClient.EXPECT().CreateService(gomock.Any()).Return(nil, errors.New("Failed call API endpoint. HTTP response code: 402. Error: &{2001 Payment Required []}")).Times(1)
+	mockPdClient.EXPECT().ListServices(gomock.Any()).Times(0)
+	_, err := c.CreateService(NewPdData())
+	assert.Assert(t, s.IsAccountQuotaExceeded(err), "Expected an account quota error, got %v", err)
+}
+
+func TestCreateServiceOtherError(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	mockPdClient.EXPECT().GetEscalationPolicy(gomock.Any(), gomock.Any()).Return(&pdApi.EscalationPolicy{}, nil).Times(1)
+	mockPdClient.EXPECT().CreateService(gomock.Any()).Return(nil, errors.New("Failed call API endpoint. HTTP response code: 500. Error: &{}")).Times(1)
+	_, err := c.CreateService(NewPdData())
+	assert.Assert(t, err != nil)
+	assert.Assert(t, !s.IsAccountQuotaExceeded(err))
+}
+
+func TestCreateServiceNameTakenAdopted(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	data := NewPdData()
+	mockPdClient.EXPECT().GetEscalationPolicy(gomock.Any(), gomock.Any()).Return(&pdApi.EscalationPolicy{}, nil).Times(1)
+	mockPdClient.EXPECT().CreateService(gomock.Any()).Return(nil, errors.New("Failed call API endpoint. HTTP response code: 400. Error: &{2001 Invalid Input Provided [Name has already been taken.]}")).Times(1)
+	mockPdClient.EXPECT().ListServices(gomock.Any()).Return(&pdApi.ListServiceResponse{
+		Services: []pdApi.Service{existingService(s.ServiceName(data), "V4 Alertmanager")},
+	}, nil).Times(1)
+	mockPdClient.EXPECT().CreateIntegration(gomock.Any(), gomock.Any()).Times(0)
+
+	integrationID, err := c.CreateService(data)
+	assert.NilError(t, err)
+	assert.Equal(t, "existing-integration-id", integrationID)
+	assert.Equal(t, "existing-service-id", data.ServiceID)
+}
+
+func TestFindServiceByName(t *testing.T) {
+	data := NewPdData()
+	data.ServicePrefix = "osd"
+	data.ClusterID = "My_Cluster"
+	data.NormalizeName = true
+
+	tests := []struct {
+		name     string
+		services map[string][]pdApi.Service
+		expectID string
+	}{
+		{
+			name: "Service Found",
+			services: map[string][]pdApi.Service{
+				s.ServiceName(data): {
+					otherService(s.ServiceName(data) + "-other"),
+					existingService(s.ServiceName(data), "V4 Alertmanager"),
+				},
+			},
+			expectID: "existing-service-id",
+		},
+		{
+			name: "Service Named Before Normalization Found",
+			services: map[string][]pdApi.Service{
+				"osd-My_Cluster.test.domain-hive-cluster": {existingService("osd-My_Cluster.test.domain-hive-cluster", "V4 Alertmanager")},
+			},
+			expectID: "existing-service-id",
+		},
+		{
+			name: "Only Longer Names",
+			services: map[string][]pdApi.Service{
+				s.ServiceName(data): {otherService(s.ServiceName(data) + "-other")},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, mockPdClient, _ := NewTestClient(t)
+			mockPdClient.EXPECT().ListServices(gomock.Any()).DoAndReturn(func(o pdApi.ListServiceOptions) (*pdApi.ListServiceResponse, error) {
+				return &pdApi.ListServiceResponse{Services: test.services[o.Query]}, nil
+			}).AnyTimes()
+
+			service, err := c.FindServiceByName(data)
+			assert.NilError(t, err)
+			if test.expectID == "" {
+				assert.Assert(t, service == nil, "Expected no service, got %v", service)
+				return
+			}
+			assert.Assert(t, service != nil)
+			assert.Equal(t, test.expectID, service.ID)
+		})
+	}
+}
+
+func TestAdoptServiceWithoutIntegration(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	data := NewPdData()
+	service := existingService(s.ServiceName(data), "Some Other Integration")
+	mockPdClient.EXPECT().CreateIntegration("existing-service-id", pdApi.Integration{Name: "V4 Alertmanager", Type: "events_api_v2_inbound_integration"}).Return(&pdApi.Integration{APIObject: pdApi.APIObject{ID: "new-integration-id"}}, nil).Times(1)
+
+	err := c.AdoptService(data, &service)
+	assert.NilError(t, err)
+	assert.Equal(t, "existing-service-id", data.ServiceID)
+	assert.Equal(t, "new-integration-id", data.IntegrationID)
+}
+
+func TestListServicesByPrefix(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	pages := []*pdApi.ListServiceResponse{
+		{
+			APIListObject: pdApi.APIListObject{More: true},
+			Services: []pdApi.Service{
+				otherService("osd-cluster-1.test.domain-hive-cluster"),
+				otherService("legacy-osd-cluster-2.test.domain-hive-cluster"),
+			},
+		},
+		{
+			Services: []pdApi.Service{
+				otherService("OSD-Cluster_3.test.domain-hive-cluster"),
+				otherService("osdx-cluster-4.test.domain-hive-cluster"),
+			},
+		},
+	}
+	mockPdClient.EXPECT().ListServices(gomock.Any()).DoAndReturn(func(o pdApi.ListServiceOptions) (*pdApi.ListServiceResponse, error) {
+		assert.Equal(t, "osd", o.Query)
+		return pages[o.Offset/2], nil
+	}).Times(2)
+
+	services, err := c.ListServicesByPrefix("osd")
+	assert.NilError(t, err)
+	names := []string{}
+	for _, service := range services {
+		names = append(names, service.Name)
+	}
+	assert.DeepEqual(t, []string{"osd-cluster-1.test.domain-hive-cluster", "OSD-Cluster_3.test.domain-hive-cluster"}, names)
+}
+
+func existingService(name string, integrationName string) pdApi.Service {
+	return pdApi.Service{
+		APIObject: pdApi.APIObject{ID: "existing-service-id"},
+		Name:      name,
+		Integrations: []pdApi.Integration{
+			{
+				APIObject: pdApi.APIObject{ID: "existing-integration-id"},
+				Name:      integrationName,
+				Type:      "events_api_v2_inbound_integration",
+			},
+		},
+	}
+}
+
+func otherService(name string) pdApi.Service {
+	return pdApi.Service{APIObject: pdApi.APIObject{ID: "other-service-id"}, Name: name}
+}
+
+func suppressionRule(id string, value string, end time.Time) *pdApi.RulesetRule {
+	return &pdApi.RulesetRule{
+		ID: id,
+		Conditions: &pdApi.RuleConditions{
+			Operator: "and",
+			RuleSubconditions: []*pdApi.RuleSubcondition{
+				{
+					Operator:   "equals",
+					Parameters: &pdApi.ConditionParameter{Path: "payload.custom_details.cluster_id", Value: value},
+				},
+			},
+		},
+		Actions: &pdApi.RuleActions{
+			Suppress: &pdApi.RuleActionSuppress{Value: true},
+		},
+		TimeFrame: &pdApi.RuleTimeFrame{
+			ActiveBetween: &pdApi.ActiveBetween{EndTime: int(end.UnixNano() / int64(time.Millisecond))},
+		},
+	}
+}
+
+func TestCreateSuppressionRule(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	start := time.Now()
+	rules := &pdApi.ListRulesetRulesResponse{
+		Rules: []*pdApi.RulesetRule{
+			suppressionRule("expired", "other-cluster", start.Add(-time.Minute)),
+			suppressionRule("active", "other-cluster", start.Add(time.Hour)),
+			{ID: "unrelated", Actions: &pdApi.RuleActions{Severity: &pdApi.RuleActionParameter{Value: "info"}}},
+		},
+	}
+	mockPdClient.EXPECT().ListRulesetRules("ruleset").Return(rules, nil).Times(1)
+	mockPdClient.EXPECT().DeleteRulesetRule("ruleset", "expired").Return(nil).Times(1)
+	mockPdClient.EXPECT().CreateRulesetRule("ruleset", gomock.Any()).DoAndReturn(
+		func(rulesetID string, rule *pdApi.RulesetRule) (*pdApi.RulesetRule, *http.Response, error) {
+			assert.Equal(t, rule.Conditions.RuleSubconditions[0].Parameters.Path, "payload.custom_details.cluster_id")
+			assert.Equal(t, rule.Conditions.RuleSubconditions[0].Parameters.Value, "test-cluster-id")
+			assert.Assert(t, rule.Actions.Suppress.Value)
+			assert.Equal(t, rule.TimeFrame.ActiveBetween.EndTime-rule.TimeFrame.ActiveBetween.StartTime, int(time.Hour/time.Millisecond))
+			return &pdApi.RulesetRule{ID: "new"}, nil, nil
+		}).Times(1)
+	id, err := c.CreateSuppressionRule("ruleset", "cluster_id", "test-cluster-id", start, start.Add(time.Hour))
+	assert.NilError(t, err)
+	assert.Equal(t, id, "new")
+}
+
+func TestCreateSuppressionRuleReusesActiveRule(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	start := time.Now()
+	rules := &pdApi.ListRulesetRulesResponse{
+		Rules: []*pdApi.RulesetRule{
+			suppressionRule("active", "test-cluster-id", start.Add(time.Hour)),
+		},
+	}
+	mockPdClient.EXPECT().ListRulesetRules("ruleset").Return(rules, nil).Times(1)
+	mockPdClient.EXPECT().CreateRulesetRule(gomock.Any(), gomock.Any()).Times(0)
+	id, err := c.CreateSuppressionRule("ruleset", "cluster_id", "test-cluster-id", start, start.Add(time.Hour))
+	assert.NilError(t, err)
+	assert.Equal(t, id, "active")
+}
+
+func TestCreateEventRule(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	mockPdClient.EXPECT().CreateRulesetRule("ruleset", gomock.Any()).DoAndReturn(
+		func(rulesetID string, rule *pdApi.RulesetRule) (*pdApi.RulesetRule, *http.Response, error) {
+			assert.Equal(t, rule.Conditions.Operator, "and")
+			assert.Equal(t, len(rule.Conditions.RuleSubconditions), 2)
+			assert.Equal(t, rule.Conditions.RuleSubconditions[0].Parameters.Path, "payload.custom_details.cluster_id")
+			assert.Equal(t, rule.Conditions.RuleSubconditions[0].Parameters.Value, "test-cluster-id")
+			assert.Equal(t, rule.Conditions.RuleSubconditions[1].Operator, "matches")
+			assert.Equal(t, rule.Conditions.RuleSubconditions[1].Parameters.Path, "payload.custom_details.alertname")
+			assert.Equal(t, rule.Conditions.RuleSubconditions[1].Parameters.Value, "^Kube.*")
+			assert.Equal(t, rule.Actions.Route.Value, "test-service-id")
+			assert.Equal(t, rule.Actions.Severity.Value, "warning")
+			assert.Assert(t, rule.Actions.Suppress == nil)
+			return &pdApi.RulesetRule{ID: "new"}, nil, nil
+		}).Times(1)
+	id, err := c.CreateEventRule("ruleset", s.EventRule{
+		ClusterIDDetail: "cluster_id",
+		ClusterID:       "test-cluster-id",
+		ServiceID:       "test-service-id",
+		Conditions:      []s.EventRuleCondition{{Detail: "alertname", Operator: "matches", Value: "^Kube.*"}},
+		Route:           true,
+		Severity:        "warning",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, id, "new")
+}
+
+func TestDeleteEventRuleAlreadyGone(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	mockPdClient.EXPECT().DeleteRulesetRule("ruleset", "gone").Return(errors.New("Failed call API endpoint. HTTP response code: 404. Error: &{}")).Times(1)
+	assert.NilError(t, c.DeleteEventRule("ruleset", "gone"))
+}
+
+func TestSetServiceTags(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	current := []s.Tag{
+		{ID: "1", Label: "cost-center:123"},
+		{ID: "2", Label: "owner:old-team"},
+		{ID: "3", Label: "environment:staging"},
+		{ID: "4", Label: "unmanaged"},
+	}
+	mockPdClient.EXPECT().ListServiceTags("test-service-id").Return(current, nil).Times(1)
+	mockPdClient.EXPECT().ChangeServiceTags("test-service-id", []string{"owner:sre"}, []string{"2", "3"}).Return(nil).Times(1)
+	err := c.SetServiceTags(NewPdData(), map[string]string{"cost-center": "123", "owner": "sre", "environment": ""})
+	assert.NilError(t, err)
+}
+
+func TestSetServiceTagsUpToDate(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	current := []s.Tag{
+		{ID: "1", Label: "cost-center:123"},
+	}
+	mockPdClient.EXPECT().ListServiceTags("test-service-id").Return(current, nil).Times(1)
+	mockPdClient.EXPECT().ChangeServiceTags(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	err := c.SetServiceTags(NewPdData(), map[string]string{"cost-center": "123", "owner": ""})
+	assert.NilError(t, err)
+}
+
+func TestSetServiceDependencies(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	current := []s.ServiceDependency{
+		{
+			ID:                "1",
+			SupportingService: pdApi.APIObject{ID: "hub", Type: "technical_service_reference"},
+			DependentService:  pdApi.APIObject{ID: "test-service-id", Type: "technical_service_reference"},
+		},
+	}
+	mockPdClient.EXPECT().ListServiceDependencies("test-service-id").Return(current, nil).Times(1)
+	mockPdClient.EXPECT().AssociateServiceDependencies([]s.ServiceDependency{
+		{
+			SupportingService: pdApi.APIObject{ID: "test-service-id", Type: "service"},
+			DependentService:  pdApi.APIObject{ID: "business", Type: "business_service"},
+		},
+	}).Return(nil).Times(1)
+	err := c.SetServiceDependencies(NewPdData(), "hub", "business")
+	assert.NilError(t, err)
+}
+
+func TestSetServiceDependenciesUpToDate(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	current := []s.ServiceDependency{
+		{
+			ID:                "1",
+			SupportingService: pdApi.APIObject{ID: "hub", Type: "technical_service_reference"},
+			DependentService:  pdApi.APIObject{ID: "test-service-id", Type: "technical_service_reference"},
+		},
+	}
+	mockPdClient.EXPECT().ListServiceDependencies("test-service-id").Return(current, nil).Times(1)
+	mockPdClient.EXPECT().AssociateServiceDependencies(gomock.Any()).Times(0)
+	err := c.SetServiceDependencies(NewPdData(), "hub", "")
+	assert.NilError(t, err)
+}
+
+func TestServiceDrift(t *testing.T) {
+	data := NewPdData()
+	data.EscalationPolicyID = "policy"
+	data.AutoResolveTimeout = 300
+	timeout := uint(300)
+	service := &pdApi.Service{
+		EscalationPolicy:    pdApi.EscalationPolicy{APIObject: pdApi.APIObject{ID: "policy"}},
+		AutoResolveTimeout:  &timeout,
+		AlertCreation:       "create_alerts_and_incidents",
+		IncidentUrgencyRule: &pdApi.IncidentUrgencyRule{Type: "constant", Urgency: "severity_based"},
+	}
+	assert.Equal(t, len(s.ServiceDrift(data, service)), 0)
+
+	service.EscalationPolicy.ID = "other"
+	service.AutoResolveTimeout = nil
+	service.AlertCreation = "create_incidents"
+	service.IncidentUrgencyRule.Urgency = "high"
+	assert.DeepEqual(t, s.ServiceDrift(data, service), []string{
+		"escalation policy is other instead of policy",
+		"auto resolve timeout is 0 instead of 300",
+		"alert creation is create_incidents instead of create_alerts_and_incidents",
+		"incident urgency is high instead of severity_based",
+	})
+}
+
+func TestServiceDescription(t *testing.T) {
+	data := NewPdData()
+	assert.Equal(t, s.ServiceDescription(data), "test-cluster-id - A managed hive created cluster")
+
+	data.Metadata = &s.ClusterMetadata{
+		ClusterUUID:   "test-cluster-uuid",
+		CloudProvider: "aws",
+		Region:        "us-east-1",
+	}
+	assert.Equal(t, s.ServiceDescription(data), `test-cluster-id - A managed hive created cluster
+Cluster ID: test-cluster-uuid
+Base domain: test.domain
+Cloud provider: aws
+Region: us-east-1`)
+}
+
+func TestServiceDriftDescription(t *testing.T) {
+	data := NewPdData()
+	service := &pdApi.Service{
+		Description:         "outdated",
+		AlertCreation:       "create_alerts_and_incidents",
+		IncidentUrgencyRule: &pdApi.IncidentUrgencyRule{Type: "constant", Urgency: "severity_based"},
+	}
+	// the description is left alone without metadata
+	assert.Equal(t, len(s.ServiceDrift(data, service)), 0)
+
+	data.Metadata = &s.ClusterMetadata{ConsoleURL: "https://console.test.domain"}
+	assert.DeepEqual(t, s.ServiceDrift(data, service), []string{"description does not list the current cluster metadata"})
+
+	service.Description = s.ServiceDescription(data)
+	assert.Equal(t, len(s.ServiceDrift(data, service)), 0)
+}
+
+func TestServiceDriftSupportHours(t *testing.T) {
+	data := NewPdData()
+	data.Urgency = "high"
+	data.SupportHours = &pdApi.SupportHours{Type: "fixed_time_per_day", Timezone: "UTC", StartTime: "09:00:00", EndTime: "17:00:00", DaysOfWeek: []uint{1, 2, 3, 4, 5}}
+	service := &pdApi.Service{
+		AlertCreation: "create_alerts_and_incidents",
+		IncidentUrgencyRule: &pdApi.IncidentUrgencyRule{
+			Type:                "use_support_hours",
+			DuringSupportHours:  &pdApi.IncidentUrgencyType{Type: "constant", Urgency: "high"},
+			OutsideSupportHours: &pdApi.IncidentUrgencyType{Type: "constant", Urgency: "low"},
+		},
+		SupportHours: &pdApi.SupportHours{Type: "fixed_time_per_day", Timezone: "UTC", StartTime: "09:00:00", EndTime: "17:00:00", DaysOfWeek: []uint{1, 2, 3, 4, 5}},
+	}
+	assert.Equal(t, len(s.ServiceDrift(data, service)), 0)
+
+	service.IncidentUrgencyRule.OutsideSupportHours.Urgency = "high"
+	service.SupportHours.EndTime = "18:00:00"
+	assert.DeepEqual(t, s.ServiceDrift(data, service), []string{
+		"incident urgency is high during support hours and high outside instead of high during support hours and low outside",
+		"support hours are 09:00:00 to 18:00:00 UTC on days [1 2 3 4 5] instead of 09:00:00 to 17:00:00 UTC on days [1 2 3 4 5]",
+	})
+}
+
+func TestRepairService(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	data := NewPdData()
+	data.EscalationPolicyID = "policy"
+	mockPdClient.EXPECT().UpdateService(gomock.Any()).DoAndReturn(func(service pdApi.Service) (*pdApi.Service, error) {
+		assert.Equal(t, service.ID, "test-service-id")
+		assert.Equal(t, service.EscalationPolicy.ID, "policy")
+		assert.Equal(t, service.AlertCreation, "create_alerts_and_incidents")
+		assert.Equal(t, service.IncidentUrgencyRule.Urgency, "severity_based")
+		assert.Equal(t, service.Name, "")
+		return &service, nil
+	}).Times(1)
+	err := c.RepairService(data, &pdApi.Service{APIObject: pdApi.APIObject{ID: "test-service-id"}, Name: "test-service"})
+	assert.NilError(t, err)
+}
+
+func TestLastServiceChanger(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	records := []s.AuditRecord{
+		{ID: "2", Actors: []pdApi.APIObject{{Type: "user_reference", Summary: "Jane Doe"}}},
+		{ID: "1", Actors: []pdApi.APIObject{{Type: "user_reference", Summary: "John Doe"}}},
+	}
+	mockPdClient.EXPECT().ListServiceAuditRecords("test-service-id").Return(records, nil).Times(1)
+	changer, err := c.LastServiceChanger(NewPdData())
+	assert.NilError(t, err)
+	assert.Equal(t, changer, "Jane Doe")
+}
+
+func TestListServiceChanges(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	since := time.Now().Add(-time.Hour)
+	records := []s.AuditRecord{
+		{ID: "1", Method: &s.AuditRecordMethod{Type: "api_token", TruncatedToken: "-key"}},
+		{ID: "2", Method: &s.AuditRecordMethod{Type: "browser"}},
+	}
+	mockPdClient.EXPECT().ListAuditRecords("services", since).Return(records, nil).Times(1)
+	changes, err := c.ListServiceChanges(since)
+	assert.NilError(t, err)
+	assert.Equal(t, len(changes), 1)
+	assert.Equal(t, changes[0].ID, "2")
+}
+
+func TestValidateReferences(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	mockPdClient.EXPECT().GetEscalationPolicy("policy", nil).Return(&pdApi.EscalationPolicy{}, nil).Times(1)
+	mockPdClient.EXPECT().GetRuleset("found").Return(&pdApi.Ruleset{}, nil, nil).Times(2)
+	mockPdClient.EXPECT().GetRuleset("missing").Return(nil, nil, errors.New("Failed call API endpoint. HTTP response code: 404. Error: &{2100 Not Found []}")).Times(2)
+	refs := s.References{EscalationPolicyID: "policy", RulesetIDs: []string{"found", "missing"}}
+
+	// the escalation policy is only fetched once
+	for i := 0; i < 2; i++ {
+		missing, err := c.ValidateReferences(refs)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, missing, []string{"ruleset missing not found"})
+	}
+}
+
+func TestValidateReferencesMappedEscalationPolicies(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	mockPdClient.EXPECT().GetEscalationPolicy("policy", nil).Return(&pdApi.EscalationPolicy{}, nil).Times(1)
+	mockPdClient.EXPECT().GetEscalationPolicy("gold", nil).Return(&pdApi.EscalationPolicy{}, nil).Times(1)
+	mockPdClient.EXPECT().GetEscalationPolicy("silver", nil).Return(nil, errors.New("Failed call API endpoint. HTTP response code: 404. Error: &{2100 Not Found []}")).Times(1)
+	missing, err := c.ValidateReferences(s.References{EscalationPolicyID: "policy", EscalationPolicyIDs: []string{"gold", "silver"}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, missing, []string{"escalation policy silver not found"})
+}
+
+func TestValidateReferencesError(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	mockPdClient.EXPECT().GetEscalationPolicy("policy", nil).Return(nil, errors.New("Failed call API endpoint. HTTP response code: 500. Error: ")).Times(1)
+	_, err := c.ValidateReferences(s.References{EscalationPolicyID: "policy"})
+	assert.ErrorContains(t, err, "500")
+}
+
+func TestSendTestAlert(t *testing.T) {
+	c, _, funcMock := NewTestClient(t)
+	funcMock.On("manageEvents").Return(&pdApi.V2EventResponse{Status: "success"}, nil).Times(2)
+	result, err := c.SendTestAlert("test-integration-key", "test-cluster-id")
+	assert.NilError(t, err)
+	assert.Assert(t, result.Accepted)
+	assert.Assert(t, strings.HasPrefix(result.DedupKey, "pagerduty-operator-test-test-cluster-id-"))
+	funcMock.AssertNumberOfCalls(t, "manageEvents", 2)
+}
+
+func TestSendTestAlertNotAccepted(t *testing.T) {
+	c, _, funcMock := NewTestClient(t)
+	funcMock.On("manageEvents").Return(&pdApi.V2EventResponse{Status: "invalid event"}, nil).Times(1)
+	result, err := c.SendTestAlert("test-integration-key", "test-cluster-id")
+	assert.ErrorContains(t, err, "not accepted")
+	assert.Assert(t, !result.Accepted)
+	funcMock.AssertNumberOfCalls(t, "manageEvents", 1)
+}
+
+func TestCreateHeartbeatIntegration(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	mockPdClient.EXPECT().CreateIntegration("test-service-id", pdApi.Integration{Name: "Heartbeat", Type: "events_api_v2_inbound_integration"}).Return(&pdApi.Integration{APIObject: pdApi.APIObject{ID: "heartbeat-integration-id"}}, nil).Times(1)
+	id, err := c.CreateHeartbeatIntegration(NewPdData())
+	assert.NilError(t, err)
+	assert.Equal(t, id, "heartbeat-integration-id")
+}
+
+func TestRotateIntegrationKey(t *testing.T) {
+	for _, deleteErr := range []error{nil, errors.New("Failed call API endpoint. HTTP response code: 404. Error: &{}")} {
+		c, mockPdClient, _ := NewTestClient(t)
+		gomock.InOrder(
+			mockPdClient.EXPECT().DeleteIntegration("test-service-id", "test-integration-id").Return(deleteErr).Times(1),
+			mockPdClient.EXPECT().CreateIntegration("test-service-id", pdApi.Integration{Name: "V4 Alertmanager", Type: "events_api_v2_inbound_integration"}).Return(&pdApi.Integration{APIObject: pdApi.APIObject{ID: "rotated-integration-id"}}, nil).Times(1),
+		)
+		data := NewPdData()
+		err := c.RotateIntegrationKey(data)
+		assert.NilError(t, err)
+		assert.Equal(t, data.IntegrationID, "rotated-integration-id")
+	}
+}
+
+func TestRotateIntegrationKeyDeleteFailed(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	mockPdClient.EXPECT().DeleteIntegration("test-service-id", "test-integration-id").Return(errors.New("Failed call API endpoint. HTTP response code: 500. Error: &{}")).Times(1)
+	data := NewPdData()
+	err := c.RotateIntegrationKey(data)
+	assert.Assert(t, err != nil)
+	// the old integration is kept until it is deleted
+	assert.Equal(t, data.IntegrationID, "test-integration-id")
+}
+
+func TestIntegrationType(t *testing.T) {
+	tests := []struct {
+		integrationType string
+		expected        pdApi.Integration
+	}{
+		{
+			integrationType: "",
+			expected:        pdApi.Integration{Name: "V4 Alertmanager", Type: "events_api_v2_inbound_integration"},
+		},
+		{
+			integrationType: s.IntegrationTypeEventsAPIV2,
+			expected:        pdApi.Integration{Name: "V4 Alertmanager", Type: "events_api_v2_inbound_integration"},
+		},
+		{
+			integrationType: s.IntegrationTypeGenericEventsAPI,
+			expected:        pdApi.Integration{Name: "V4 Alertmanager", Type: "generic_events_api_inbound_integration"},
+		},
+		{
+			integrationType: s.IntegrationTypePrometheus,
+			expected: pdApi.Integration{
+				Name:   "V4 Alertmanager",
+				Type:   "generic_events_api_inbound_integration",
+				Vendor: &pdApi.APIObject{ID: "prometheus-vendor-id", Type: "vendor_reference"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.integrationType, func(t *testing.T) {
+			c, mockPdClient, _ := NewTestClient(t)
+			if test.integrationType == s.IntegrationTypePrometheus {
+				vendors := &pdApi.ListVendorResponse{Vendors: []pdApi.Vendor{
+					{APIObject: pdApi.APIObject{ID: "other-vendor-id"}, Name: "Prometheus Alertmanager"},
+					{APIObject: pdApi.APIObject{ID: "prometheus-vendor-id"}, Name: "Prometheus"},
+				}}
+				mockPdClient.EXPECT().ListVendors(pdApi.ListVendorOptions{Query: "Prometheus"}).Return(vendors, nil).Times(1)
+			}
+			mockPdClient.EXPECT().DeleteIntegration("test-service-id", "test-integration-id").Return(nil).Times(1)
+			mockPdClient.EXPECT().CreateIntegration("test-service-id", test.expected).Return(&pdApi.Integration{APIObject: pdApi.APIObject{ID: "rotated-integration-id"}}, nil).Times(1)
+
+			data := NewPdData()
+			data.IntegrationType = test.integrationType
+			err := c.RotateIntegrationKey(data)
+			assert.NilError(t, err)
+			assert.Equal(t, data.IntegrationID, "rotated-integration-id")
+		})
+	}
+}
+
+func TestIntegrationTypePrometheusVendorNotFound(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	mockPdClient.EXPECT().ListVendors(gomock.Any()).Return(&pdApi.ListVendorResponse{}, nil).Times(1)
+	mockPdClient.EXPECT().DeleteIntegration("test-service-id", "test-integration-id").Return(nil).Times(1)
+	mockPdClient.EXPECT().CreateIntegration(gomock.Any(), gomock.Any()).Times(0)
+
+	data := NewPdData()
+	data.IntegrationType = s.IntegrationTypePrometheus
+	err := c.RotateIntegrationKey(data)
+	assert.Assert(t, err != nil)
+}
+
+func TestRecreateMissingIntegration(t *testing.T) {
+	notFound := errors.New("Failed call API endpoint. HTTP response code: 404. Error: &{}")
+	serverError := errors.New("Failed call API endpoint. HTTP response code: 500. Error: &{}")
+
+	tests := []struct {
+		name              string
+		getIntegrationErr error
+		getServiceErr     error
+		expectRecreated   bool
+		expectErr         bool
+	}{
+		{name: "integration exists"},
+		{name: "integration deleted", getIntegrationErr: notFound, expectRecreated: true},
+		{name: "service deleted", getIntegrationErr: notFound, getServiceErr: notFound, expectErr: true},
+		{name: "lookup failed", getIntegrationErr: serverError, expectErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, mockPdClient, _ := NewTestClient(t)
+			mockPdClient.EXPECT().GetIntegration("test-service-id", "test-integration-id", gomock.Any()).Return(&pdApi.Integration{}, test.getIntegrationErr).Times(1)
+			if test.getIntegrationErr == notFound {
+				mockPdClient.EXPECT().GetService("test-service-id", nil).Return(&pdApi.Service{}, test.getServiceErr).Times(1)
+			}
+			if test.expectRecreated {
+				mockPdClient.EXPECT().CreateIntegration("test-service-id", pdApi.Integration{Name: "V4 Alertmanager", Type: "events_api_v2_inbound_integration"}).Return(&pdApi.Integration{APIObject: pdApi.APIObject{ID: "recreated-integration-id"}}, nil).Times(1)
+			}
+
+			data := NewPdData()
+			recreated, err := c.RecreateMissingIntegration(data)
+			assert.Equal(t, err != nil, test.expectErr)
+			assert.Equal(t, recreated, test.expectRecreated)
+			if test.expectRecreated {
+				assert.Equal(t, data.IntegrationID, "recreated-integration-id")
+			} else {
+				assert.Equal(t, data.IntegrationID, "test-integration-id")
+			}
+		})
+	}
+}
+
+func TestSendHeartbeatEvent(t *testing.T) {
+	for _, missed := range []bool{true, false} {
+		c, _, funcMock := NewTestClient(t)
+		var sent pdApi.V2Event
+		funcMock.On("manageEvents").Return(&pdApi.V2EventResponse{Status: "success"}, nil).Times(1)
+		c.(*s.SvcClient).ManageEvent = func(ev pdApi.V2Event) (*pdApi.V2EventResponse, error) {
+			sent = ev
+			return funcMock.manageEvents(ev)
+		}
+		err := c.SendHeartbeatEvent("heartbeat-integration-key", "test-cluster-id", missed)
+		assert.NilError(t, err)
+		assert.Equal(t, sent.RoutingKey, "heartbeat-integration-key")
+		assert.Equal(t, sent.DedupKey, "pagerduty-operator-heartbeat-test-cluster-id")
+		if missed {
+			assert.Equal(t, sent.Action, "trigger")
+		} else {
+			assert.Equal(t, sent.Action, "resolve")
+		}
+		funcMock.AssertNumberOfCalls(t, "manageEvents", 1)
+	}
+}
+
+func TestSendHeartbeatEventNotAccepted(t *testing.T) {
+	c, _, funcMock := NewTestClient(t)
+	funcMock.On("manageEvents").Return(&pdApi.V2EventResponse{Status: "invalid event"}, nil).Times(1)
+	err := c.SendHeartbeatEvent("heartbeat-integration-key", "test-cluster-id", true)
+	assert.ErrorContains(t, err, "not accepted")
+}
+
+func TestDisableService(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	timeout := uint(300)
+	service := &pdApi.Service{APIObject: pdApi.APIObject{ID: "test-service-id"}, Status: "active", AutoResolveTimeout: &timeout}
+	mockPdClient.EXPECT().GetService("test-service-id", nil).Return(service, nil).Times(1)
+	mockPdClient.EXPECT().UpdateService(gomock.Any()).DoAndReturn(func(service pdApi.Service) (*pdApi.Service, error) {
+		assert.Equal(t, service.Status, "disabled")
+		assert.Equal(t, *service.AutoResolveTimeout, timeout)
+		return &service, nil
+	}).Times(1)
+	err := c.DisableService(NewPdData())
+	assert.NilError(t, err)
+}
+
+func TestEnableService(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	timeout := uint(300)
+	service := &pdApi.Service{APIObject: pdApi.APIObject{ID: "test-service-id"}, Status: "disabled", AutoResolveTimeout: &timeout}
+	mockPdClient.EXPECT().GetService("test-service-id", nil).Return(service, nil).Times(1)
+	mockPdClient.EXPECT().UpdateService(gomock.Any()).DoAndReturn(func(service pdApi.Service) (*pdApi.Service, error) {
+		assert.Equal(t, service.Status, "active")
+		assert.Equal(t, *service.AutoResolveTimeout, timeout)
+		return &service, nil
+	}).Times(1)
+	err := c.EnableService(NewPdData())
+	assert.NilError(t, err)
+
+	// services that are not disabled are left alone
+	mockPdClient.EXPECT().GetService("test-service-id", nil).Return(&pdApi.Service{Status: "warning"}, nil).Times(1)
+	err = c.EnableService(NewPdData())
+	assert.NilError(t, err)
+}
+
+func TestRenameService(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	timeout := uint(300)
+	data := NewPdData()
+	data.ServicePrefix = "renamed"
+	service := &pdApi.Service{APIObject: pdApi.APIObject{ID: "test-service-id"}, Name: "old-test-cluster-id.test.domain-hive-cluster", AutoResolveTimeout: &timeout}
+	mockPdClient.EXPECT().GetService("test-service-id", nil).Return(service, nil).Times(1)
+	mockPdClient.EXPECT().UpdateService(gomock.Any()).DoAndReturn(func(service pdApi.Service) (*pdApi.Service, error) {
+		assert.Equal(t, service.Name, "renamed-test-cluster-id.test.domain-hive-cluster")
+		assert.Equal(t, *service.AutoResolveTimeout, timeout)
+		return &service, nil
+	}).Times(1)
+	err := c.RenameService(data)
+	assert.NilError(t, err)
+
+	// services already named so are left alone
+	mockPdClient.EXPECT().GetService("test-service-id", nil).Return(&pdApi.Service{Name: "renamed-test-cluster-id.test.domain-hive-cluster"}, nil).Times(1)
+	err = c.RenameService(data)
+	assert.NilError(t, err)
+}
+
+func TestCountServiceIncidents(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	until := time.Now()
+	since := until.Add(-24 * time.Hour)
+
+	serviceIDs := []string{}
+	for i := 0; i < 150; i++ {
+		serviceIDs = append(serviceIDs, fmt.Sprintf("S%d", i))
+	}
+	// the services are requested in batches
+	mockPdClient.EXPECT().ListServiceIncidentMetrics(serviceIDs[:100], since, until).Return([]s.IncidentMetrics{{ServiceID: "S1", TotalIncidentCount: 3}}, nil).Times(1)
+	mockPdClient.EXPECT().ListServiceIncidentMetrics(serviceIDs[100:], since, until).Return([]s.IncidentMetrics{{ServiceID: "S120", TotalIncidentCount: 40}, {ServiceID: "other", TotalIncidentCount: 7}}, nil).Times(1)
+
+	counts, err := c.CountServiceIncidents(serviceIDs, since, until)
+	assert.NilError(t, err)
+	assert.Equal(t, len(counts), 150)
+	assert.Equal(t, counts["S0"], 0)
+	assert.Equal(t, counts["S1"], 3)
+	assert.Equal(t, counts["S120"], 40)
+}
+
+func TestServiceIncidentStats(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	until := time.Now()
+	since := until.Add(-24 * time.Hour)
+
+	incident := func(serviceID, title, status, changedBy string) pdApi.Incident {
+		return pdApi.Incident{
+			Title:              title,
+			Status:             status,
+			Service:            pdApi.APIObject{ID: serviceID},
+			LastStatusChangeBy: pdApi.APIObject{Type: changedBy},
+		}
+	}
+
+	// the incidents are listed page by page
+	mockPdClient.EXPECT().ListIncidents(gomock.Any()).DoAndReturn(func(o pdApi.ListIncidentsOptions) (*pdApi.ListIncidentsResponse, error) {
+		assert.DeepEqual(t, o.ServiceIDs, []string{"S1", "S2", "S3"})
+		assert.Equal(t, o.Offset, uint(0))
+		return &pdApi.ListIncidentsResponse{
+			APIListObject: pdApi.APIListObject{More: true},
+			Incidents: []pdApi.Incident{
+				incident("S1", "KubeNodeNotReady", "resolved", "service_reference"),
+				incident("S1", "KubeNodeNotReady", "resolved", "service_reference"),
+			},
+		}, nil
+	}).Times(1)
+	mockPdClient.EXPECT().ListIncidents(gomock.Any()).DoAndReturn(func(o pdApi.ListIncidentsOptions) (*pdApi.ListIncidentsResponse, error) {
+		assert.Equal(t, o.Offset, uint(2))
+		return &pdApi.ListIncidentsResponse{
+			Incidents: []pdApi.Incident{
+				incident("S1", "etcdMembersDown", "resolved", "user_reference"),
+				incident("S2", "KubeNodeNotReady", "triggered", "service_reference"),
+				incident("other", "KubeNodeNotReady", "resolved", "service_reference"),
+			},
+		}, nil
+	}).Times(1)
+
+	stats, err := c.ServiceIncidentStats([]string{"S1", "S2", "S3"}, since, until)
+	assert.NilError(t, err)
+	assert.Equal(t, len(stats), 3)
+	assert.Equal(t, stats["S1"], s.IncidentStats{Incidents: 3, DuplicateIncidents: 1, AutoResolvedIncidents: 2})
+	assert.Equal(t, stats["S2"], s.IncidentStats{Incidents: 1})
+	assert.Equal(t, stats["S3"], s.IncidentStats{})
+}
+
+func TestCreateServiceIncidentUrgency(t *testing.T) {
+	supportHours := &pdApi.SupportHours{
+		Type:       "fixed_time_per_day",
+		Timezone:   "America/New_York",
+		StartTime:  "09:00:00",
+		EndTime:    "17:00:00",
+		DaysOfWeek: []uint{1, 2, 3, 4, 5},
+	}
+
+	tests := []struct {
+		name         string
+		urgency      string
+		outside      string
+		supportHours *pdApi.SupportHours
+		expectRule   pdApi.IncidentUrgencyRule
+	}{
+		{
+			name:       "default",
+			expectRule: pdApi.IncidentUrgencyRule{Type: "constant", Urgency: "severity_based"},
+		},
+		{
+			name:       "constant",
+			urgency:    "high",
+			expectRule: pdApi.IncidentUrgencyRule{Type: "constant", Urgency: "high"},
+		},
+		{
+			name:         "support hours",
+			urgency:      "severity_based",
+			supportHours: supportHours,
+			expectRule: pdApi.IncidentUrgencyRule{
+				Type:                "use_support_hours",
+				DuringSupportHours:  &pdApi.IncidentUrgencyType{Type: "severity_based"},
+				OutsideSupportHours: &pdApi.IncidentUrgencyType{Type: "constant", Urgency: "low"},
+			},
+		},
+		{
+			name:         "support hours outside urgency",
+			urgency:      "high",
+			outside:      "severity_based",
+			supportHours: supportHours,
+			expectRule: pdApi.IncidentUrgencyRule{
+				Type:                "use_support_hours",
+				DuringSupportHours:  &pdApi.IncidentUrgencyType{Type: "constant", Urgency: "high"},
+				OutsideSupportHours: &pdApi.IncidentUrgencyType{Type: "severity_based"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, mockPdClient, _ := NewTestClient(t)
+			mockPdClient.EXPECT().GetEscalationPolicy(gomock.Any(), gomock.Any()).Return(&pdApi.EscalationPolicy{}, nil).Times(1)
+			mockPdClient.EXPECT().CreateService(gomock.Any()).DoAndReturn(func(service pdApi.Service) (*pdApi.Service, error) {
+				assert.DeepEqual(t, test.expectRule, *service.IncidentUrgencyRule)
+				assert.DeepEqual(t, test.supportHours, service.SupportHours)
+				return &pdApi.Service{APIObject: pdApi.APIObject{ID: "test-service-id"}}, nil
+			}).Times(1)
+			mockPdClient.EXPECT().CreateIntegration("test-service-id", gomock.Any()).Return(&pdApi.Integration{APIObject: pdApi.APIObject{ID: "test-integration-id"}}, nil).Times(1)
+
+			data := NewPdData()
+			data.Urgency = test.urgency
+			data.OutsideSupportHoursUrgency = test.outside
+			data.SupportHours = test.supportHours
+			_, err := c.CreateService(data)
+			assert.NilError(t, err)
+		})
+	}
+}
+
+func TestCreateServiceAlertGrouping(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	timeout := uint(10)
+	data := NewPdData()
+	data.AlertGrouping = &s.AlertGrouping{Type: "time", Config: &s.AlertGroupingConfig{Timeout: &timeout}}
+	mockPdClient.EXPECT().GetEscalationPolicy(gomock.Any(), gomock.Any()).Return(&pdApi.EscalationPolicy{}, nil).Times(1)
+	mockPdClient.EXPECT().CreateService(gomock.Any()).Return(&pdApi.Service{APIObject: pdApi.APIObject{ID: "new-service-id"}}, nil).Times(1)
+	mockPdClient.EXPECT().CreateIntegration("new-service-id", gomock.Any()).Return(&pdApi.Integration{APIObject: pdApi.APIObject{ID: "test-integration-id"}}, nil).Times(1)
+	mockPdClient.EXPECT().UpdateServiceAlertGrouping("new-service-id", data.AlertGrouping).Return(nil).Times(1)
+
+	_, err := c.CreateService(data)
+	assert.NilError(t, err)
+}
+
+func TestCreateServiceTeamByName(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	data := NewPdData()
+	data.Team = "SRE"
+	mockPdClient.EXPECT().GetEscalationPolicy(gomock.Any(), gomock.Any()).Return(&pdApi.EscalationPolicy{}, nil).Times(1)
+	mockPdClient.EXPECT().GetTeam("SRE").Return(nil, errors.New("Failed call API endpoint. HTTP response code: 404. Error: &{2100 Not Found []}")).Times(1)
+	mockPdClient.EXPECT().ListTeams(pdApi.ListTeamOptions{Query: "SRE"}).Return(&pdApi.ListTeamResponse{Teams: []pdApi.Team{
+		{APIObject: pdApi.APIObject{ID: "other-team-id"}, Name: "SRE Leads"},
+		{APIObject: pdApi.APIObject{ID: "test-team-id"}, Name: "SRE"},
+	}}, nil).Times(1)
+	mockPdClient.EXPECT().CreateService(gomock.Any()).DoAndReturn(func(service pdApi.Service) (*pdApi.Service, error) {
+		assert.DeepEqual(t, service.Teams, []pdApi.Team{{APIObject: pdApi.APIObject{ID: "test-team-id", Type: "team_reference"}}})
+		return &pdApi.Service{APIObject: pdApi.APIObject{ID: "new-service-id"}}, nil
+	}).Times(1)
+	mockPdClient.EXPECT().CreateIntegration("new-service-id", gomock.Any()).Return(&pdApi.Integration{APIObject: pdApi.APIObject{ID: "test-integration-id"}}, nil).Times(1)
+
+	_, err := c.CreateService(data)
+	assert.NilError(t, err)
+}
+
+func TestServiceDriftTeam(t *testing.T) {
+	data := NewPdData()
+	data.Team = "SRE"
+	service := &pdApi.Service{
+		AlertCreation:       "create_alerts_and_incidents",
+		IncidentUrgencyRule: &pdApi.IncidentUrgencyRule{Type: "constant", Urgency: "severity_based"},
+		Teams:               []pdApi.Team{{APIObject: pdApi.APIObject{ID: "test-team-id", Summary: "SRE"}}},
+	}
+	assert.Equal(t, len(s.ServiceDrift(data, service)), 0)
+
+	data.Team = "test-team-id"
+	assert.Equal(t, len(s.ServiceDrift(data, service)), 0)
+
+	service.Teams = nil
+	assert.DeepEqual(t, s.ServiceDrift(data, service), []string{"team test-team-id is not assigned"})
+}
+
+func TestRepairServiceTeam(t *testing.T) {
+	c, mockPdClient, _ := NewTestClient(t)
+	data := NewPdData()
+	data.Team = "test-team-id"
+	mockPdClient.EXPECT().GetTeam("test-team-id").Return(&pdApi.Team{APIObject: pdApi.APIObject{ID: "test-team-id"}, Name: "SRE"}, nil).Times(1)
+	mockPdClient.EXPECT().UpdateService(gomock.Any()).DoAndReturn(func(service pdApi.Service) (*pdApi.Service, error) {
+		// the other teams of the service are kept
+		assert.DeepEqual(t, service.Teams, []pdApi.Team{
+			{APIObject: pdApi.APIObject{ID: "other-team-id", Type: "team_reference"}},
+			{APIObject: pdApi.APIObject{ID: "test-team-id", Type: "team_reference"}},
+		})
+		return &service, nil
+	}).Times(1)
+	service := &pdApi.Service{
+		APIObject: pdApi.APIObject{ID: "test-service-id"},
+		Teams:     []pdApi.Team{{APIObject: pdApi.APIObject{ID: "other-team-id", Type: "team_reference"}}},
+	}
+	err := c.RepairService(data, service)
+	assert.NilError(t, err)
+}
+
+func TestAlertGroupingDrift(t *testing.T) {
+	timeout := uint(0)
+	tests := []struct {
+		name        string
+		expected    *s.AlertGrouping
+		current     *s.AlertGrouping
+		expectDrift []string
+	}{
+		{
+			name:        "Not Managed",
+			current:     &s.AlertGrouping{Type: "intelligent"},
+			expectDrift: []string{},
+		},
+		{
+			name:        "Defaults Filled In",
+			expected:    &s.AlertGrouping{Type: "content_based", Config: &s.AlertGroupingConfig{Fields: []string{"summary", "component"}}},
+			current:     &s.AlertGrouping{Type: "content_based", Config: &s.AlertGroupingConfig{Aggregate: "all", Fields: []string{"component", "summary"}}},
+			expectDrift: []string{},
+		},
+		{
+			name:        "Timeout Zero",
+			expected:    &s.AlertGrouping{Type: "time"},
+			current:     &s.AlertGrouping{Type: "time", Config: &s.AlertGroupingConfig{Timeout: &timeout}},
+			expectDrift: []string{},
+		},
+		{
+			name:        "Grouping Removed",
+			expected:    &s.AlertGrouping{Type: "intelligent"},
+			expectDrift: []string{"alert grouping is unset instead of intelligent"},
+		},
+		{
+			name:        "Fields Changed",
+			expected:    &s.AlertGrouping{Type: "content_based", Config: &s.AlertGroupingConfig{Aggregate: "any", Fields: []string{"summary"}}},
+			current:     &s.AlertGrouping{Type: "content_based", Config: &s.AlertGroupingConfig{Aggregate: "any", Fields: []string{"component"}}},
+			expectDrift: []string{"alert grouping is content_based (any of component) instead of content_based (any of summary)"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, mockPdClient, _ := NewTestClient(t)
+			if test.expected != nil {
+				mockPdClient.EXPECT().GetServiceAlertGrouping("test-service-id").Return(test.current, nil).Times(1)
+			}
+			data := NewPdData()
+			data.AlertGrouping = test.expected
+			drift, err := c.AlertGroupingDrift(data)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, drift, test.expectDrift)
+		})
+	}
+}
+
+func TestNormalizeServiceName(t *testing.T) {
+	long := "osd-" + strings.Repeat("a", 300) + ".example.com-hive-cluster"
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "osd-cluster.example.com-hive-cluster", expected: "osd-cluster.example.com-hive-cluster"},
+		{name: "OSD-Cluster.Example.com-hive-cluster", expected: "osd-cluster.example.com-hive-cluster"},
+		{name: "osd prod_a-cluster.example.com-hive-cluster", expected: "osd-prod-a-cluster.example.com-hive-cluster"},
+		{name: "équipe--ops-cluster.example.com-hive-cluster", expected: "quipe--ops-cluster.example.com-hive-cluster"},
+		{name: "ops @ #1-cluster.example.com-hive-cluster", expected: "ops-1-cluster.example.com-hive-cluster"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, s.NormalizeServiceName(test.name), test.expected)
+	}
+
+	normalized := s.NormalizeServiceName(long)
+	assert.Equal(t, len(normalized), s.MaxServiceNameLength)
+	assert.Equal(t, normalized, s.NormalizeServiceName(long), "normalization is not stable")
+	assert.Assert(t, normalized != s.NormalizeServiceName(long+"x"), "truncated names are not distinct")
+}
+
+func TestServiceDriftLegacyName(t *testing.T) {
+	data := NewPdData()
+	data.ServicePrefix = "OSD"
+	service := &pdApi.Service{
+		Name:                "OSD-test-cluster-id.test.domain-hive-cluster",
+		AlertCreation:       "create_alerts_and_incidents",
+		IncidentUrgencyRule: &pdApi.IncidentUrgencyRule{Type: "constant", Urgency: "severity_based"},
+	}
+	assert.Equal(t, len(s.ServiceDrift(data, service)), 0)
+
+	data.NormalizeName = true
+	assert.DeepEqual(t, s.ServiceDrift(data, service), []string{
+		"name is OSD-test-cluster-id.test.domain-hive-cluster instead of osd-test-cluster-id.test.domain-hive-cluster",
+	})
+
+	c, mockPdClient, _ := NewTestClient(t)
+	mockPdClient.EXPECT().UpdateService(gomock.Any()).DoAndReturn(func(repaired pdApi.Service) (*pdApi.Service, error) {
+		assert.Equal(t, repaired.Name, "osd-test-cluster-id.test.domain-hive-cluster")
+		return &repaired, nil
+	}).Times(1)
+	assert.NilError(t, c.RepairService(data, service))
+
+	// a service renamed by hand keeps its name
+	service.Name = "custom"
+	assert.Equal(t, len(s.ServiceDrift(data, service)), 0)
+}
+
+func TestServiceNameTemplate(t *testing.T) {
+	data := NewPdData()
+	data.ServicePrefix = "osd"
+	data.Labels = map[string]string{"region": "us-east-1"}
+
+	tests := []struct {
+		template  string
+		expected  string
+		expectErr bool
+	}{
+		{template: "{{.Prefix}}-{{.ClusterID}}-{{.BaseDomain}}", expected: "osd-test-cluster-id-test.domain"},
+		{template: "{{.Prefix}}-{{index .Labels \"region\"}}-{{.ClusterID}}", expected: "osd-us-east-1-test-cluster-id"},
+		{template: "{{.Prefix}}-{{.Labels.env}}", expectErr: true},
+		{template: "{{if false}}x{{end}}", expectErr: true},
+		{template: "{{.Prefix", expectErr: true},
+	}
+	for _, test := range tests {
+		data.ServiceNameTemplate = test.template
+		name, err := s.ExecuteServiceNameTemplate(data)
+		assert.Equal(t, err != nil, test.expectErr, test.template)
+		assert.Equal(t, name, test.expected, test.template)
+	}
+
+	// lookups fall back to the default name when the template fails
+	assert.Equal(t, s.ServiceName(data), "osd-test-cluster-id.test.domain-hive-cluster")
+
+	data.ServiceNameTemplate = "{{.Prefix}} {{.ClusterID}}"
+	assert.Equal(t, s.ServiceName(data), "osd test-cluster-id")
+	data.NormalizeName = true
+	assert.Equal(t, s.ServiceName(data), "osd-test-cluster-id")
+}
+
+func TestValidateServiceNameTemplate(t *testing.T) {
+	tests := []struct {
+		template  string
+		expectErr bool
+	}{
+		{template: "{{.Prefix}}-{{.ClusterID}}-{{.BaseDomain}}"},
+		{template: "{{.Prefix}}-{{.Labels.region}}-{{.ClusterID}}"},
+		{template: "{{.Prefix}}-{{.Region}}", expectErr: true},
+		{template: "{{.Prefix", expectErr: true},
+		{template: " ", expectErr: true},
+	}
+	for _, test := range tests {
+		err := s.ValidateServiceNameTemplate(test.template)
+		assert.Equal(t, err != nil, test.expectErr, test.template)
+	}
+}
+
+func TestServiceDriftTemplatedName(t *testing.T) {
+	data := NewPdData()
+	data.ServicePrefix = "osd"
+	data.ServiceNameTemplate = "{{.Prefix}}-{{.ClusterID}}"
+	service := &pdApi.Service{
+		Name:                "osd-test-cluster-id.test.domain-hive-cluster",
+		AlertCreation:       "create_alerts_and_incidents",
+		IncidentUrgencyRule: &pdApi.IncidentUrgencyRule{Type: "constant", Urgency: "severity_based"},
+	}
+	assert.DeepEqual(t, s.ServiceDrift(data, service), []string{
+		"name is osd-test-cluster-id.test.domain-hive-cluster instead of osd-test-cluster-id",
+	})
+
+	// a service renamed by hand keeps its name
+	service.Name = "custom"
+	assert.Equal(t, len(s.ServiceDrift(data, service)), 0)
+}
+
+type fakeHTTPClient struct {
+	paths []string
+}
+
+func (c *fakeHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.paths = append(c.paths, req.URL.Path)
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader(`{"service": {"id": "SVC1"}}`)),
+	}, nil
+}
+
+type fakeRateLimiter struct {
+	waits int
+}
+
+func (l *fakeRateLimiter) Wait(ctx context.Context) error {
+	l.waits++
+	return nil
+}
+
+func TestNewClientOptions(t *testing.T) {
+	httpClient := &fakeHTTPClient{}
+	limiter := &fakeRateLimiter{}
+	var observed []string
+	observe := func(controller string, req *http.Request, resp *http.Response, duration float64) {
+		observed = append(observed, controller+" "+req.Method+" "+resp.Status)
+	}
+
+	client := s.NewClient("test-key", "test-controller",
+		s.WithHTTPClient(httpClient),
+		s.WithMetrics(observe),
+		s.WithRateLimiter(limiter),
+	)
+	service, err := client.GetService(&s.Data{ServiceID: "SVC1"})
+
+	assert.NilError(t, err)
+	assert.Equal(t, service.ID, "SVC1")
+	assert.DeepEqual(t, httpClient.paths, []string{"/services/SVC1"})
+	assert.DeepEqual(t, observed, []string{"test-controller GET 200 OK"})
+	assert.Equal(t, limiter.waits, 1)
+}
+
+// failingHTTPClient answers every call with a bad request echoing an
+// integration key
+type failingHTTPClient struct{}
+
+func (c failingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		Status:     "400 Bad Request",
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader(`{"error": {"message": "Invalid routing key 0123456789abcdef0123456789abcdef"}}`)),
+	}, nil
+}
+
+func TestAPIErrorsRedacted(t *testing.T) {
+	client := s.NewClient("test-key", "test-controller", s.WithHTTPClient(failingHTTPClient{}))
+
+	_, err := client.ListServiceChanges(time.Now())
+
+	assert.ErrorContains(t, err, "HTTP response code: 400")
+	assert.ErrorContains(t, err, "Invalid routing key [REDACTED]")
+}
@@ -0,0 +1,183 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerduty
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// apiStats tracks the calls made by the clients of NewClient, for
+// CurrentStats
+var apiStats = newAPIStatsTracker(time.Now)
+
+// statsKeyTTL is how long the rate limit budget of an API key is kept
+// without further calls, so the key of a deleted PagerDutyIntegration stops
+// being reported
+const statsKeyTTL = time.Hour
+
+// Stats is a snapshot of the calls made by the clients of NewClient, to
+// investigate their performance
+type Stats struct {
+	// InFlightRequests is how many calls to the PagerDuty API are waiting
+	// for their response
+	InFlightRequests int64 `json:"inFlightRequests"`
+	// RateLimits is the rate limit budget of each API key called with
+	RateLimits []RateLimitBudget `json:"rateLimits"`
+	// ServiceListCache counts how often the pages of services listed were
+	// served from the cache
+	ServiceListCache CacheStats `json:"serviceListCache"`
+}
+
+// RateLimitBudget is what PagerDuty last reported of the rate limit of an
+// API key
+type RateLimitBudget struct {
+	// Key identifies the API key by the start of its SHA-256, the key itself
+	// is never exposed
+	Key string `json:"key"`
+	// Limit and Remaining are the calls allowed per window and those left,
+	// from the RateLimit-Limit and RateLimit-Remaining headers of the last
+	// response. Both are -1 if PagerDuty did not send them.
+	Limit     int `json:"limit"`
+	Remaining int `json:"remaining"`
+	// Reset is when the window ends, from the RateLimit-Reset header
+	Reset *time.Time `json:"reset,omitempty"`
+	// LastCall is when the last response came in
+	LastCall time.Time `json:"lastCall"`
+	// LastRateLimited is when a call was last rate limited
+	LastRateLimited *time.Time `json:"lastRateLimited,omitempty"`
+}
+
+// CacheStats counts the lookups of a cache
+type CacheStats struct {
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	HitRate float64 `json:"hitRate"`
+}
+
+// apiStatsTracker counts the calls in flight and the lookups of the cache
+// of service pages, and remembers the rate limit budget of each API key.
+// Keys are only kept as hashes.
+type apiStatsTracker struct {
+	now func() time.Time
+
+	// inFlight, cacheHits and cacheMisses are accessed atomically
+	inFlight    int64
+	cacheHits   uint64
+	cacheMisses uint64
+
+	mu      sync.Mutex
+	budgets map[[sha256.Size]byte]RateLimitBudget
+}
+
+func newAPIStatsTracker(now func() time.Time) *apiStatsTracker {
+	return &apiStatsTracker{now: now, budgets: map[[sha256.Size]byte]RateLimitBudget{}}
+}
+
+// start records a call being sent, and returns the func recording its
+// outcome
+func (t *apiStatsTracker) start(req *http.Request) func(resp *http.Response, err error) {
+	atomic.AddInt64(&t.inFlight, 1)
+	return func(resp *http.Response, err error) {
+		atomic.AddInt64(&t.inFlight, -1)
+		if err == nil {
+			t.observe(req, resp)
+		}
+	}
+}
+
+// observe records the rate limit budget PagerDuty reported in resp
+func (t *apiStatsTracker) observe(req *http.Request, resp *http.Response) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	key := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	budget := t.budgets[key]
+	budget.Key = hex.EncodeToString(key[:4])
+	budget.Limit = headerInt(resp.Header, "RateLimit-Limit")
+	budget.Remaining = headerInt(resp.Header, "RateLimit-Remaining")
+	budget.Reset = nil
+	if seconds := headerInt(resp.Header, "RateLimit-Reset"); seconds >= 0 {
+		reset := now.Add(time.Duration(seconds) * time.Second)
+		budget.Reset = &reset
+	}
+	budget.LastCall = now
+	if resp.StatusCode == http.StatusTooManyRequests {
+		budget.LastRateLimited = &now
+	}
+	t.budgets[key] = budget
+}
+
+// headerInt returns the integer value of the header, -1 if missing or
+// invalid
+func headerInt(header http.Header, name string) int {
+	value, err := strconv.Atoi(header.Get(name))
+	if err != nil {
+		return -1
+	}
+	return value
+}
+
+// cacheLookup records a lookup of the cache of service pages
+func (t *apiStatsTracker) cacheLookup(hit bool) {
+	if hit {
+		atomic.AddUint64(&t.cacheHits, 1)
+		return
+	}
+	atomic.AddUint64(&t.cacheMisses, 1)
+}
+
+// snapshot returns the stats, the budgets ordered by key
+func (t *apiStatsTracker) snapshot() Stats {
+	stats := Stats{
+		InFlightRequests: atomic.LoadInt64(&t.inFlight),
+		RateLimits:       []RateLimitBudget{},
+		ServiceListCache: CacheStats{
+			Hits:   atomic.LoadUint64(&t.cacheHits),
+			Misses: atomic.LoadUint64(&t.cacheMisses),
+		},
+	}
+	if lookups := stats.ServiceListCache.Hits + stats.ServiceListCache.Misses; lookups > 0 {
+		stats.ServiceListCache.HitRate = float64(stats.ServiceListCache.Hits) / float64(lookups)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	for key, budget := range t.budgets {
+		if now.Sub(budget.LastCall) > statsKeyTTL {
+			delete(t.budgets, key)
+			continue
+		}
+		stats.RateLimits = append(stats.RateLimits, budget)
+	}
+	sort.Slice(stats.RateLimits, func(i, j int) bool {
+		return stats.RateLimits[i].Key < stats.RateLimits[j].Key
+	})
+	return stats
+}
+
+// CurrentStats returns the stats of the calls made by the clients of
+// NewClient, such as for an expvar.Func
+func CurrentStats() Stats {
+	return apiStats.snapshot()
+}
@@ -0,0 +1,87 @@
+package pagerduty
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestAPIStatsTracker(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	tracker := newAPIStatsTracker(func() time.Time { return now })
+
+	request := func(apiKey string) *http.Request {
+		req, _ := http.NewRequest(http.MethodGet, apiEndpoint+"/services", nil)
+		req.Header.Set("Authorization", "Token token="+apiKey)
+		return req
+	}
+	response := func(statusCode int, limit, remaining, reset string) *http.Response {
+		header := http.Header{}
+		if limit != "" {
+			header.Set("RateLimit-Limit", limit)
+			header.Set("RateLimit-Remaining", remaining)
+			header.Set("RateLimit-Reset", reset)
+		}
+		return &http.Response{StatusCode: statusCode, Header: header}
+	}
+
+	// no call made yet
+	stats := tracker.snapshot()
+	assert.Equal(t, int64(0), stats.InFlightRequests)
+	assert.Equal(t, 0, len(stats.RateLimits))
+	assert.Equal(t, 0.0, stats.ServiceListCache.HitRate)
+
+	// calls in flight, one failing to be sent
+	first := tracker.start(request("first"))
+	failed := tracker.start(request("first"))
+	assert.Equal(t, int64(2), tracker.snapshot().InFlightRequests)
+	failed(nil, errors.New("dial tcp: i/o timeout"))
+	assert.Equal(t, int64(1), tracker.snapshot().InFlightRequests)
+	assert.Equal(t, 0, len(tracker.snapshot().RateLimits))
+
+	// the budget reported in the headers
+	first(response(http.StatusOK, "900", "899", "60"), nil)
+	stats = tracker.snapshot()
+	assert.Equal(t, int64(0), stats.InFlightRequests)
+	assert.Equal(t, 1, len(stats.RateLimits))
+	budget := stats.RateLimits[0]
+	assert.Equal(t, 8, len(budget.Key))
+	assert.Equal(t, 900, budget.Limit)
+	assert.Equal(t, 899, budget.Remaining)
+	assert.Equal(t, now.Add(time.Minute), *budget.Reset)
+	assert.Equal(t, now, budget.LastCall)
+	assert.Assert(t, budget.LastRateLimited == nil)
+
+	// rate limited without headers, another key called
+	now = now.Add(time.Minute)
+	tracker.start(request("first"))(response(http.StatusTooManyRequests, "", "", ""), nil)
+	tracker.start(request("second"))(response(http.StatusOK, "900", "10", "5"), nil)
+	stats = tracker.snapshot()
+	assert.Equal(t, 2, len(stats.RateLimits))
+	for _, budget := range stats.RateLimits {
+		if budget.Remaining == 10 {
+			continue
+		}
+		assert.Equal(t, -1, budget.Limit)
+		assert.Equal(t, -1, budget.Remaining)
+		assert.Assert(t, budget.Reset == nil)
+		assert.Equal(t, now, *budget.LastRateLimited)
+	}
+
+	// keys no longer called are dropped
+	now = now.Add(statsKeyTTL + time.Minute)
+	assert.Equal(t, 0, len(tracker.snapshot().RateLimits))
+
+	// hit rate of the cache
+	tracker.cacheLookup(false)
+	tracker.cacheLookup(true)
+	tracker.cacheLookup(true)
+	tracker.cacheLookup(true)
+	stats = tracker.snapshot()
+	assert.Equal(t, uint64(3), stats.ServiceListCache.Hits)
+	assert.Equal(t, uint64(1), stats.ServiceListCache.Misses)
+	assert.Equal(t, 0.75, stats.ServiceListCache.HitRate)
+}
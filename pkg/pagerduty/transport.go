@@ -0,0 +1,156 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagerduty
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/openshift/pagerduty-operator/config"
+	"github.com/spf13/pflag"
+)
+
+// maxIdleConnsPerHost is how many connections to PagerDuty are kept open
+// per API endpoint, enough for the controllers reconciling at once
+const maxIdleConnsPerHost = 10
+
+// httpClients pools the HTTP clients the clients made by NewClient call the
+// PagerDuty API through. The controllers make a client for every reconcile,
+// sharing the HTTP client of its API endpoint keeps the connections open
+// across reconciles instead of handshaking TLS anew for each of them.
+// The API key is sent with each request rather than tied to a connection,
+// so the clients aren't pooled per key: the pool would grow with every
+// rotated key and keep it in memory for the life of the operator.
+var httpClients = &httpClientPool{}
+
+// proxyURL and caBundle are set by --pagerduty-proxy and --pagerduty-ca-bundle
+var (
+	proxyURL string
+	caBundle string
+)
+
+// transport holds the proxy and TLS config of the pooled HTTP clients, set
+// from the flags by ConfigureTransport. The proxy defaults to the one of the
+// HTTPS_PROXY and NO_PROXY environment variables.
+var transport = struct {
+	proxy     func(*http.Request) (*url.URL, error)
+	tlsConfig *tls.Config
+}{proxy: http.ProxyFromEnvironment}
+
+// FlagSet returns the flags setting the proxy the PagerDuty API is called
+// through and the CAs trusted on top of the system ones, --pagerduty-proxy
+// and --pagerduty-ca-bundle
+func FlagSet() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("pagerduty", pflag.ExitOnError)
+	fs.StringVar(&proxyURL, "pagerduty-proxy", os.Getenv(config.PagerDutyProxyEnvVar),
+		"URL of the proxy the PagerDuty API is called through, overriding HTTPS_PROXY for PagerDuty only")
+	fs.StringVar(&caBundle, "pagerduty-ca-bundle", os.Getenv(config.PagerDutyCABundleEnvVar),
+		"Path of a PEM bundle of CAs trusted for the PagerDuty API on top of the system ones, e.g. the CA of a TLS intercepting proxy")
+	return fs
+}
+
+// ConfigureTransport applies --pagerduty-proxy and --pagerduty-ca-bundle to
+// the HTTP clients NewClient makes from then on. It must be called after the
+// flags are parsed and before the first client is made.
+func ConfigureTransport() error {
+	proxy := http.ProxyFromEnvironment
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid PagerDuty proxy URL: %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("invalid PagerDuty proxy URL %q: expected http(s)://host[:port]", proxyURL)
+		}
+		proxy = http.ProxyURL(u)
+	}
+
+	var tlsConfig *tls.Config
+	if caBundle != "" {
+		pem, err := ioutil.ReadFile(caBundle)
+		if err != nil {
+			return fmt.Errorf("failed to read PagerDuty CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in PagerDuty CA bundle %s", caBundle)
+		}
+		tlsConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	httpClients.mu.Lock()
+	defer httpClients.mu.Unlock()
+	transport.proxy = proxy
+	transport.tlsConfig = tlsConfig
+	// the clients pooled so far keep the config they were made with
+	httpClients.clients = nil
+	return nil
+}
+
+// httpClientPool holds the HTTP clients by API endpoint. Its clients all
+// share the proxy and TLS config of the transport, ConfigureTransport empties
+// the pool when they change.
+type httpClientPool struct {
+	mu      sync.Mutex
+	clients map[string]*http.Client
+}
+
+// get returns the HTTP client of endpoint, making it on the first call
+func (p *httpClientPool) get(endpoint string) *http.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	client, ok := p.clients[endpoint]
+	if !ok {
+		if p.clients == nil {
+			p.clients = map[string]*http.Client{}
+		}
+		client = newPooledHTTPClient()
+		p.clients[endpoint] = client
+	}
+	return client
+}
+
+// newPooledHTTPClient makes an HTTP client like the go-pagerduty default,
+// keeping more idle connections open and going through the proxy and
+// trusting the CAs set by ConfigureTransport
+func newPooledHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           transport.proxy,
+			TLSClientConfig: transport.tlsConfig,
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			MaxIdleConns:          maxIdleConnsPerHost,
+			MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+	}
+}
@@ -0,0 +1,151 @@
+package pagerduty
+
+import (
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pdApi "github.com/PagerDuty/go-pagerduty"
+	"gotest.tools/assert"
+)
+
+// pooledHTTPClient returns the HTTP client the client made by NewClient
+// calls the PagerDuty API through
+func pooledHTTPClient(c Client) pdApi.HTTPClient {
+	return c.(*SvcClient).PdClient.(*cachingPdClient).PdClient.(*apiClient).Client.HTTPClient.(customHTTPClient).HTTPClient
+}
+
+func TestNewClientSharesHTTPClient(t *testing.T) {
+	first := pooledHTTPClient(NewClient("test-key", "test-controller"))
+	second := pooledHTTPClient(NewClient("test-key", "other-controller"))
+	other := pooledHTTPClient(NewClient("other-key", "test-controller"))
+
+	assert.Assert(t, first == second)
+	assert.Assert(t, first == other)
+	assert.Assert(t, first == httpClients.get(apiEndpoint))
+}
+
+func TestNewClientRotatedKeys(t *testing.T) {
+	_ = NewClient("rotated-key-0", "test-controller")
+	httpClients.mu.Lock()
+	pooled := len(httpClients.clients)
+	httpClients.mu.Unlock()
+
+	// every rotation makes clients of a new key
+	for i := 1; i <= 5; i++ {
+		_ = NewClient(fmt.Sprintf("rotated-key-%d", i), "test-controller")
+	}
+
+	httpClients.mu.Lock()
+	defer httpClients.mu.Unlock()
+	assert.Equal(t, len(httpClients.clients), pooled)
+}
+
+func TestNewClientAPIEndpoint(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		paths = append(paths, req.URL.Path)
+		if strings.HasSuffix(req.URL.Path, "/tags") {
+			_, _ = w.Write([]byte(`{"tags": []}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"service": {"id": "P1"}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("endpoint-key", "test-controller", WithAPIEndpoint(server.URL+"/"))
+	pdClient := c.(*SvcClient).PdClient
+
+	_, err := pdClient.GetService("P1", nil)
+	assert.NilError(t, err)
+	_, err = pdClient.ListServiceTags("P1")
+	assert.NilError(t, err)
+
+	// both the go-pagerduty calls and those it lacks go to the endpoint
+	assert.DeepEqual(t, []string{"/services/P1", "/services/P1/tags"}, paths)
+	// the endpoint gets connections of its own
+	assert.Assert(t, pooledHTTPClient(c) == httpClients.get(server.URL))
+	assert.Assert(t, pooledHTTPClient(c) != pooledHTTPClient(NewClient("endpoint-key", "test-controller")))
+}
+
+// setTransportFlags sets --pagerduty-proxy and --pagerduty-ca-bundle and
+// configures the transport, restoring the defaults when the test is done
+func setTransportFlags(t *testing.T, proxy, ca string) error {
+	t.Cleanup(func() {
+		proxyURL, caBundle = "", ""
+		assert.NilError(t, ConfigureTransport())
+	})
+	proxyURL, caBundle = proxy, ca
+	return ConfigureTransport()
+}
+
+func TestConfigureTransportProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		proxied = append(proxied, req.URL.Host+req.URL.Path)
+		_, _ = w.Write([]byte(`{"service": {"id": "P1"}}`))
+	}))
+	defer proxy.Close()
+
+	assert.NilError(t, setTransportFlags(t, proxy.URL, ""))
+
+	c := NewClient("proxy-key", "test-controller", WithAPIEndpoint("http://pagerduty.test"))
+	_, err := c.(*SvcClient).PdClient.GetService("P1", nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"pagerduty.test/services/P1"}, proxied)
+}
+
+func TestConfigureTransportCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`{"service": {"id": "P1"}}`))
+	}))
+	defer server.Close()
+
+	// the certificate of the server isn't trusted without the bundle
+	c := NewClient("ca-key", "test-controller", WithAPIEndpoint(server.URL))
+	_, err := c.(*SvcClient).PdClient.GetService("P1", nil)
+	assert.ErrorContains(t, err, "certificate")
+
+	dir, err := ioutil.TempDir("", "pagerduty")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	bundle := filepath.Join(dir, "ca.crt")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.NilError(t, ioutil.WriteFile(bundle, cert, 0600))
+	assert.NilError(t, setTransportFlags(t, "", bundle))
+
+	c = NewClient("ca-key", "test-controller", WithAPIEndpoint(server.URL))
+	_, err = c.(*SvcClient).PdClient.GetService("P1", nil)
+	assert.NilError(t, err)
+}
+
+func TestConfigureTransportInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pagerduty")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	empty := filepath.Join(dir, "empty.crt")
+	assert.NilError(t, ioutil.WriteFile(empty, []byte("not a certificate"), 0600))
+
+	tests := []struct {
+		name          string
+		proxy         string
+		ca            string
+		expectedError string
+	}{
+		{name: "Proxy Without Scheme", proxy: "proxy.example.com:3128", expectedError: "invalid PagerDuty proxy URL"},
+		{name: "Proxy Unsupported Scheme", proxy: "ftp://proxy.example.com", expectedError: "invalid PagerDuty proxy URL"},
+		{name: "CA Bundle Missing", ca: filepath.Join(dir, "missing.crt"), expectedError: "failed to read PagerDuty CA bundle"},
+		{name: "CA Bundle Without Certificates", ca: empty, expectedError: "no certificates found"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ErrorContains(t, setTransportFlags(t, test.proxy, test.ca), test.expectedError)
+		})
+	}
+}
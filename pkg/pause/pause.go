@@ -0,0 +1,107 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pause lets hub admins pause the reconciles of every controller
+// during hub maintenance, such as a Hive upgrade, so they don't race with
+// it. Reconciles are paused while the operator Deployment has the
+// config.ReconcilePausedUntilAnnotation annotation set to a time in the
+// future: reconciles in flight finish, and the others are requeued for the
+// resume time, published in the pagerduty_operator_reconcile_paused_until_seconds
+// metric.
+package pause
+
+import (
+	"context"
+	"time"
+
+	"github.com/openshift/pagerduty-operator/config"
+	"github.com/openshift/pagerduty-operator/pkg/localmetrics"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var log = logf.Log.WithName("pause")
+
+// Reconciler runs the reconciles of the wrapped Reconciler unless they are
+// paused
+type Reconciler struct {
+	reconcile.Reconciler
+
+	// reader reads the operator Deployment, uncached so the Deployments of
+	// the hub aren't all watched
+	reader client.Reader
+	// namespace of the operator Deployment
+	namespace string
+	// clock returns the current time, time.Now if nil
+	clock func() time.Time
+}
+
+// Wrap returns r paused while the operator Deployment in namespace says so
+func Wrap(r reconcile.Reconciler, reader client.Reader, namespace string) *Reconciler {
+	return &Reconciler{Reconciler: r, reader: reader, namespace: namespace}
+}
+
+// Reconcile requeues request for the resume time while reconciles are
+// paused, and reconciles it otherwise
+func (p *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	now := time.Now()
+	if p.clock != nil {
+		now = p.clock()
+	}
+
+	until, err := PausedUntil(p.reader, p.namespace, now)
+	if err != nil {
+		// not being able to tell must not stop the operator
+		log.Error(err, "Failed to read whether reconciles are paused, reconciling")
+	}
+	if until == nil {
+		localmetrics.SetReconcilePausedUntil(time.Time{})
+		return p.Reconciler.Reconcile(request)
+	}
+
+	log.Info("Reconciles are paused, requeuing", "Request.Namespace", request.Namespace, "Request.Name", request.Name, "ResumeTime", until)
+	localmetrics.SetReconcilePausedUntil(*until)
+	return reconcile.Result{RequeueAfter: until.Sub(now)}, nil
+}
+
+// PausedUntil returns the time reconciles are paused until as of now, nil
+// when they aren't paused
+func PausedUntil(reader client.Reader, namespace string, now time.Time) (*time.Time, error) {
+	deployment := &appsv1.Deployment{}
+	err := reader.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: config.OperatorName}, deployment)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// not running from the Deployment, e.g. locally
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	value, ok := deployment.Annotations[config.ReconcilePausedUntilAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	if !until.After(now) {
+		return nil, nil
+	}
+	return &until, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pause
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/pagerduty-operator/config"
+	"github.com/openshift/pagerduty-operator/pkg/localmetrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const testNamespace = "pagerduty-operator"
+
+// countingReconciler counts its reconciles
+type countingReconciler struct {
+	reconciles int
+}
+
+func (r *countingReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	r.reconciles++
+	return reconcile.Result{}, nil
+}
+
+func testDeployment(annotations map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        config.OperatorName,
+			Namespace:   testNamespace,
+			Annotations: annotations,
+		},
+	}
+}
+
+func TestReconciler(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	until := now.Add(time.Hour)
+
+	tests := []struct {
+		name             string
+		localObjects     []runtime.Object
+		expectReconciles int
+		expectRequeue    time.Duration
+	}{
+		{
+			name:             "Test No Deployment",
+			expectReconciles: 1,
+		},
+		{
+			name:             "Test Not Annotated",
+			localObjects:     []runtime.Object{testDeployment(nil)},
+			expectReconciles: 1,
+		},
+		{
+			name:          "Test Paused",
+			localObjects:  []runtime.Object{testDeployment(map[string]string{config.ReconcilePausedUntilAnnotation: until.Format(time.RFC3339)})},
+			expectRequeue: time.Hour,
+		},
+		{
+			name:             "Test Resume Time Passed",
+			localObjects:     []runtime.Object{testDeployment(map[string]string{config.ReconcilePausedUntilAnnotation: now.Add(-time.Minute).Format(time.RFC3339)})},
+			expectReconciles: 1,
+		},
+		{
+			name:             "Test Invalid Resume Time",
+			localObjects:     []runtime.Object{testDeployment(map[string]string{config.ReconcilePausedUntilAnnotation: "tomorrow"})},
+			expectReconciles: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			inner := &countingReconciler{}
+			r := Wrap(inner, fakekubeclient.NewFakeClient(test.localObjects...), testNamespace)
+			r.clock = func() time.Time { return now }
+
+			result, err := r.Reconcile(reconcile.Request{})
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectReconciles, inner.reconciles)
+			assert.Equal(t, test.expectRequeue, result.RequeueAfter)
+			if test.expectRequeue > 0 {
+				assert.Equal(t, float64(until.Unix()), testutil.ToFloat64(localmetrics.MetricReconcilePausedUntil))
+			} else {
+				assert.Equal(t, float64(0), testutil.ToFloat64(localmetrics.MetricReconcilePausedUntil))
+			}
+		})
+	}
+}
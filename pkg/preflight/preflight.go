@@ -0,0 +1,279 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package preflight checks an install of the operator before it starts
+// mutating anything: the RBAC of its service account, the PagerDuty API keys
+// of the PagerDutyIntegrations, the serving certificate of the webhook and
+// the CRDs it relies on. Each check adds a pass/fail result to a Report.
+package preflight
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"github.com/openshift/pagerduty-operator/pkg/utils"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Result is the outcome of a single check
+type Result struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// Report collects the results of the checks, it passed when all of them did
+type Report struct {
+	Passed  bool     `json:"passed"`
+	Results []Result `json:"results"`
+}
+
+func (r *Report) add(name string, err error, message string) {
+	result := Result{Name: name, Passed: err == nil, Message: message}
+	if err != nil {
+		result.Message = err.Error()
+		r.Passed = false
+	}
+	r.Results = append(r.Results, result)
+}
+
+// Options configures the checks of Run
+type Options struct {
+	// Client reads the cluster and reviews the access of the operator, it
+	// has to act as the service account of the operator
+	Client client.Client
+	// PDClient builds a PagerDuty client for an API key
+	PDClient func(apiKey string, opts ...pd.ClientOption) pd.Client
+	// WebhookCertDir holds the tls.crt and tls.key of the webhook server,
+	// empty skips the webhook check
+	WebhookCertDir string
+	// WebhookHost is the name the API server calls the webhook on
+	WebhookHost string
+	// Now is the time certificates are checked at, defaults to time.Now
+	Now func() time.Time
+}
+
+// Run runs all the checks and returns their report
+func Run(opts Options) *Report {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	report := &Report{Passed: true, Results: []Result{}}
+	checkPermissions(report, opts.Client)
+	checkCRDs(report, opts.Client)
+	checkPagerDuty(report, opts.Client, opts.PDClient)
+	if opts.WebhookCertDir != "" {
+		err := checkWebhookCert(opts.WebhookCertDir, opts.WebhookHost, opts.Now())
+		report.add("webhook/certificate", err, "")
+	}
+	return report
+}
+
+// permission is a set of verbs the operator needs on a resource
+type permission struct {
+	group, resource, subresource string
+	verbs                        []string
+}
+
+// permissions are what the controllers can't reconcile without, the
+// ClusterRole of the operator grants more
+var permissions = []permission{
+	{group: "hive.openshift.io", resource: "clusterdeployments", verbs: []string{"get", "list", "watch", "update"}},
+	{group: "hive.openshift.io", resource: "syncsets", verbs: []string{"get", "list", "create", "update", "delete"}},
+	{group: "hiveinternal.openshift.io", resource: "clustersyncs", verbs: []string{"get", "list", "watch"}},
+	{group: "pagerduty.openshift.io", resource: "pagerdutyintegrations", verbs: []string{"get", "list", "watch", "update"}},
+	{group: "pagerduty.openshift.io", resource: "pagerdutyintegrations", subresource: "status", verbs: []string{"update"}},
+	{group: "pagerduty.openshift.io", resource: "pagerdutyintegrationtemplates", verbs: []string{"get", "list", "watch"}},
+	{group: "pagerduty.openshift.io", resource: "pagerdutyclusterconfigs", verbs: []string{"get", "list", "watch"}},
+	{group: "pagerduty.openshift.io", resource: "pagerdutyservices", verbs: []string{"get", "list", "watch", "create", "update", "delete"}},
+	{group: "pagerduty.openshift.io", resource: "pagerdutyservices", subresource: "status", verbs: []string{"update"}},
+	{group: "", resource: "secrets", verbs: []string{"get", "list", "watch", "create", "update", "delete"}},
+	{group: "", resource: "configmaps", verbs: []string{"get", "list", "watch", "create", "update", "delete"}},
+	{group: "", resource: "events", verbs: []string{"create"}},
+	{group: "", resource: "namespaces", verbs: []string{"get", "list", "watch"}},
+	{group: "coordination.k8s.io", resource: "leases", verbs: []string{"get", "create", "update", "delete"}},
+	{group: "apiextensions.k8s.io", resource: "customresourcedefinitions", verbs: []string{"get"}},
+}
+
+func (p permission) String() string {
+	name := p.resource
+	if p.group != "" {
+		name = p.group + "/" + name
+	}
+	if p.subresource != "" {
+		name += "/" + p.subresource
+	}
+	return name
+}
+
+// checkPermissions reviews, for every permission, whether the operator is
+// allowed all its verbs across namespaces
+func checkPermissions(report *Report, c client.Client) {
+	for _, p := range permissions {
+		denied := []string{}
+		var err error
+		for _, verb := range p.verbs {
+			review := &authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Verb:        verb,
+						Group:       p.group,
+						Resource:    p.resource,
+						Subresource: p.subresource,
+					},
+				},
+			}
+			err = c.Create(context.TODO(), review)
+			if err != nil {
+				break
+			}
+			if !review.Status.Allowed {
+				denied = append(denied, verb)
+			}
+		}
+		if err == nil && len(denied) > 0 {
+			err = fmt.Errorf("not allowed to %s", strings.Join(denied, ", "))
+		}
+		report.add("rbac/"+p.String(), err, "")
+	}
+}
+
+// crd is a CRD the operator relies on and the versions it needs served
+type crd struct {
+	name     string
+	versions []string
+	// conversion is set when the versions are converted by the webhook
+	// of the operator
+	conversion bool
+}
+
+var crds = []crd{
+	{name: "pagerdutyintegrations.pagerduty.openshift.io", versions: []string{"v1alpha1", "v1beta1"}, conversion: true},
+	{name: "pagerdutyintegrationtemplates.pagerduty.openshift.io", versions: []string{"v1alpha1"}},
+	{name: "pagerdutyclusterconfigs.pagerduty.openshift.io", versions: []string{"v1alpha1"}},
+	{name: "pagerdutysilences.pagerduty.openshift.io", versions: []string{"v1alpha1"}},
+	{name: "pagerdutyrulesets.pagerduty.openshift.io", versions: []string{"v1alpha1"}},
+	{name: "pagerdutyservices.pagerduty.openshift.io", versions: []string{"v1alpha1"}},
+	{name: "clusterdeployments.hive.openshift.io", versions: []string{"v1"}},
+	{name: "syncsets.hive.openshift.io", versions: []string{"v1"}},
+	{name: "clustersyncs.hiveinternal.openshift.io", versions: []string{"v1alpha1"}},
+}
+
+// checkCRDs checks that the CRDs are installed and serve the versions the
+// operator was built against
+func checkCRDs(report *Report, c client.Client) {
+	for _, expected := range crds {
+		found := &apiextensionsv1beta1.CustomResourceDefinition{}
+		err := c.Get(context.TODO(), types.NamespacedName{Name: expected.name}, found)
+		if err == nil {
+			err = checkCRD(expected, found)
+		}
+		report.add("crd/"+expected.name, err, "")
+	}
+}
+
+func checkCRD(expected crd, found *apiextensionsv1beta1.CustomResourceDefinition) error {
+	served := map[string]bool{}
+	if found.Spec.Version != "" {
+		served[found.Spec.Version] = true
+	}
+	for _, version := range found.Spec.Versions {
+		served[version.Name] = version.Served
+	}
+	for _, version := range expected.versions {
+		if !served[version] {
+			return fmt.Errorf("version %s is not served", version)
+		}
+	}
+	if expected.conversion {
+		if found.Spec.Conversion == nil || found.Spec.Conversion.Strategy != apiextensionsv1beta1.WebhookConverter {
+			return fmt.Errorf("versions are not converted by a webhook")
+		}
+	}
+	return nil
+}
+
+// checkPagerDuty checks that the API key of every PagerDutyIntegration
+// loads, reaches PagerDuty and reads its escalation policy. A read-only key
+// passes, PagerDuty doesn't tell the scope of a key short of a write.
+func checkPagerDuty(report *Report, c client.Client, pdclient func(apiKey string, opts ...pd.ClientOption) pd.Client) {
+	pdiList := &pagerdutyv1alpha1.PagerDutyIntegrationList{}
+	err := c.List(context.TODO(), pdiList)
+	if err != nil {
+		report.add("pagerduty", err, "")
+		return
+	}
+	if len(pdiList.Items) == 0 {
+		report.add("pagerduty", nil, "no PagerDutyIntegration to check")
+		return
+	}
+
+	for _, pdi := range pdiList.Items {
+		name := fmt.Sprintf("pagerduty/%s/%s", pdi.Namespace, pdi.Name)
+		apiKey, err := utils.LoadSecretData(
+			c,
+			pdi.Spec.PagerdutyApiKeySecretRef.Name,
+			pdi.Spec.PagerdutyApiKeySecretRef.Namespace,
+			config.PagerDutyAPISecretKey,
+		)
+		if err != nil {
+			report.add(name, fmt.Errorf("failed to load the API key: %w", err), "")
+			continue
+		}
+		missing, err := pdclient(apiKey, pd.WithAPIEndpoint(pdi.Spec.APIEndpoint)).ValidateReferences(pd.References{EscalationPolicyID: pdi.Spec.EscalationPolicy})
+		switch {
+		case err != nil && pd.IsUnauthorized(err):
+			err = fmt.Errorf("the API key was rejected: %w", err)
+		case err != nil:
+			err = fmt.Errorf("failed to reach PagerDuty: %w", err)
+		case len(missing) > 0:
+			err = fmt.Errorf("%s", strings.Join(missing, ", "))
+		}
+		report.add(name, err, "")
+	}
+}
+
+// checkWebhookCert checks that the serving certificate of the webhook is
+// valid at now and for the name the API server calls it on
+func checkWebhookCert(dir, host string, now time.Time) error {
+	pair, err := tls.LoadX509KeyPair(filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"))
+	if err != nil {
+		return err
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return err
+	}
+	if now.Before(cert.NotBefore) {
+		return fmt.Errorf("the certificate is not valid before %s", cert.NotBefore.Format(time.RFC3339))
+	}
+	if now.After(cert.NotAfter) {
+		return fmt.Errorf("the certificate expired at %s", cert.NotAfter.Format(time.RFC3339))
+	}
+	if host != "" {
+		return cert.VerifyHostname(host)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preflight
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	mockpd "github.com/openshift/pagerduty-operator/pkg/pagerduty/mock"
+	"github.com/stretchr/testify/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testWebhookHost = "pagerduty-operator-webhook.pagerduty-operator.svc"
+
+// reviewClient answers the SelfSubjectAccessReviews the fake client can't,
+// denying the verbs of denied
+type reviewClient struct {
+	client.Client
+	denied map[string]bool
+}
+
+func (c *reviewClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	if review, ok := obj.(*authorizationv1.SelfSubjectAccessReview); ok {
+		attrs := review.Spec.ResourceAttributes
+		review.Status.Allowed = !c.denied[attrs.Verb+" "+attrs.Resource]
+		return nil
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func testCRDs() []runtime.Object {
+	objects := []runtime.Object{}
+	for _, expected := range crds {
+		found := &apiextensionsv1beta1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: expected.name},
+		}
+		for _, version := range expected.versions {
+			found.Spec.Versions = append(found.Spec.Versions, apiextensionsv1beta1.CustomResourceDefinitionVersion{
+				Name:    version,
+				Served:  true,
+				Storage: version == expected.versions[0],
+			})
+		}
+		if expected.conversion {
+			found.Spec.Conversion = &apiextensionsv1beta1.CustomResourceConversion{Strategy: apiextensionsv1beta1.WebhookConverter}
+		}
+		objects = append(objects, found)
+	}
+	return objects
+}
+
+func testPagerDutyIntegration() *pagerdutyv1alpha1.PagerDutyIntegration {
+	return &pagerdutyv1alpha1.PagerDutyIntegration{
+		ObjectMeta: metav1.ObjectMeta{Name: "testIntegration", Namespace: config.OperatorNamespace},
+		Spec: pagerdutyv1alpha1.PagerDutyIntegrationSpec{
+			EscalationPolicy: "ABC123",
+			PagerdutyApiKeySecretRef: corev1.SecretReference{
+				Name:      config.PagerDutyAPISecretName,
+				Namespace: config.OperatorNamespace,
+			},
+		},
+	}
+}
+
+func testSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: config.PagerDutyAPISecretName, Namespace: config.OperatorNamespace},
+		Data:       map[string][]byte{config.PagerDutyAPISecretKey: []byte("test-api-key")},
+	}
+}
+
+func testClient(denied map[string]bool, objects ...runtime.Object) client.Client {
+	s := scheme.Scheme
+	s.AddKnownTypes(pagerdutyv1alpha1.SchemeGroupVersion, &pagerdutyv1alpha1.PagerDutyIntegration{}, &pagerdutyv1alpha1.PagerDutyIntegrationList{})
+	_ = apiextensionsv1beta1.AddToScheme(s)
+	return &reviewClient{
+		Client: fakekubeclient.NewFakeClientWithScheme(s, objects...),
+		denied: denied,
+	}
+}
+
+// writeTestCert writes a certificate for host valid from notBefore to
+// notAfter to dir
+func writeTestCert(t *testing.T, dir, host string, notBefore, notAfter time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "tls.crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "tls.key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+}
+
+func failed(report *Report) []string {
+	names := []string{}
+	for _, result := range report.Results {
+		if !result.Passed {
+			names = append(names, result.Name)
+		}
+	}
+	return names
+}
+
+func TestRun(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		denied       map[string]bool
+		objects      func() []runtime.Object
+		pdErr        error
+		pdMissing    []string
+		certHost     string
+		certNotAfter time.Time
+		expectFailed []string
+	}{
+		{
+			name: "Test Passed",
+		},
+		{
+			name:         "Test SyncSets Not Creatable",
+			denied:       map[string]bool{"create syncsets": true},
+			expectFailed: []string{"rbac/hive.openshift.io/syncsets"},
+		},
+		{
+			name:         "Test Status Not Updatable",
+			denied:       map[string]bool{"update pagerdutyintegrations": true},
+			expectFailed: []string{"rbac/pagerduty.openshift.io/pagerdutyintegrations", "rbac/pagerduty.openshift.io/pagerdutyintegrations/status"},
+		},
+		{
+			name: "Test CRD Missing",
+			objects: func() []runtime.Object {
+				return append(testCRDs()[1:], testPagerDutyIntegration(), testSecret())
+			},
+			expectFailed: []string{"crd/pagerdutyintegrations.pagerduty.openshift.io"},
+		},
+		{
+			name: "Test CRD Without Conversion",
+			objects: func() []runtime.Object {
+				objects := append(testCRDs(), testPagerDutyIntegration(), testSecret())
+				objects[0].(*apiextensionsv1beta1.CustomResourceDefinition).Spec.Conversion = nil
+				return objects
+			},
+			expectFailed: []string{"crd/pagerdutyintegrations.pagerduty.openshift.io"},
+		},
+		{
+			name: "Test API Key Secret Missing",
+			objects: func() []runtime.Object {
+				return append(testCRDs(), testPagerDutyIntegration())
+			},
+			expectFailed: []string{"pagerduty/" + config.OperatorNamespace + "/testIntegration"},
+		},
+		{
+			name:         "Test API Key Rejected",
+			pdErr:        errors.New("HTTP response code: 401"),
+			expectFailed: []string{"pagerduty/" + config.OperatorNamespace + "/testIntegration"},
+		},
+		{
+			name:         "Test Escalation Policy Missing",
+			pdMissing:    []string{"escalation policy ABC123 not found"},
+			expectFailed: []string{"pagerduty/" + config.OperatorNamespace + "/testIntegration"},
+		},
+		{
+			name:         "Test Webhook Certificate Expired",
+			certNotAfter: now.Add(-time.Hour),
+			expectFailed: []string{"webhook/certificate"},
+		},
+		{
+			name:         "Test Webhook Certificate Wrong Host",
+			certHost:     "other.example.com",
+			expectFailed: []string{"webhook/certificate"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockPDClient := mockpd.NewMockClient(mockCtrl)
+			mockPDClient.EXPECT().ValidateReferences(pd.References{EscalationPolicyID: "ABC123"}).Return(test.pdMissing, test.pdErr).AnyTimes()
+
+			objects := append(testCRDs(), testPagerDutyIntegration(), testSecret())
+			if test.objects != nil {
+				objects = test.objects()
+			}
+
+			dir, err := ioutil.TempDir("", "preflight")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dir)
+			certHost := testWebhookHost
+			if test.certHost != "" {
+				certHost = test.certHost
+			}
+			certNotAfter := now.Add(24 * time.Hour)
+			if !test.certNotAfter.IsZero() {
+				certNotAfter = test.certNotAfter
+			}
+			writeTestCert(t, dir, certHost, now.Add(-24*time.Hour), certNotAfter)
+
+			report := Run(Options{
+				Client:         testClient(test.denied, objects...),
+				PDClient:       func(string, ...pd.ClientOption) pd.Client { return mockPDClient },
+				WebhookCertDir: dir,
+				WebhookHost:    testWebhookHost,
+				Now:            func() time.Time { return now },
+			})
+
+			expectFailed := test.expectFailed
+			if expectFailed == nil {
+				expectFailed = []string{}
+			}
+			assert.Equal(t, expectFailed, failed(report))
+			assert.Equal(t, len(expectFailed) == 0, report.Passed)
+		})
+	}
+}
+
+func TestRunWithoutPagerDutyIntegrations(t *testing.T) {
+	report := Run(Options{
+		Client:   testClient(nil, testCRDs()...),
+		PDClient: func(string, ...pd.ClientOption) pd.Client { return nil },
+	})
+
+	assert.True(t, report.Passed)
+	last := report.Results[len(report.Results)-1]
+	assert.Equal(t, Result{Name: "pagerduty", Passed: true, Message: "no PagerDutyIntegration to check"}, last)
+}
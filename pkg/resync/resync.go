@@ -0,0 +1,59 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resync decides when periodic resyncs happen. Each cluster gets a
+// slot at a deterministic offset in a window proportional to the fleet size,
+// so a large fleet doesn't hit the PagerDuty API all at once.
+package resync
+
+import (
+	"hash/fnv"
+	"time"
+)
+
+// Window returns how far resyncs are spread for the given number of
+// clusters: spreadPerCluster for each cluster, capped at period.
+func Window(period time.Duration, spreadPerCluster time.Duration, clusterCount int) time.Duration {
+	if clusterCount <= 0 || spreadPerCluster <= 0 {
+		return 0
+	}
+	// compare in the divided form to avoid overflowing on huge fleets
+	if time.Duration(clusterCount) > period/spreadPerCluster {
+		return period
+	}
+	return time.Duration(clusterCount) * spreadPerCluster
+}
+
+// Offset returns where in the window the slot of key falls. The same key
+// always gets the same offset, so a cluster keeps its slot across restarts.
+func Offset(key string, window time.Duration) time.Duration {
+	if window <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	// hash.Hash never returns an error on Write
+	_, _ = h.Write([]byte(key))
+	return time.Duration(h.Sum64() % uint64(window))
+}
+
+// NextSlot returns the first slot of key strictly after the given time.
+// Slots repeat every period, at Offset(key, window) past each multiple of
+// period.
+func NextSlot(key string, period time.Duration, window time.Duration, after time.Time) time.Time {
+	slot := after.Truncate(period).Add(Offset(key, window))
+	if !slot.After(after) {
+		slot = slot.Add(period)
+	}
+	return slot
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resync
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		name         string
+		clusterCount int
+		expected     time.Duration
+	}{
+		{
+			name:         "no clusters",
+			clusterCount: 0,
+			expected:     0,
+		},
+		{
+			name:         "small fleet",
+			clusterCount: 3,
+			expected:     30 * time.Second,
+		},
+		{
+			name:         "fleet filling the period",
+			clusterCount: 360,
+			expected:     time.Hour,
+		},
+		{
+			name:         "fleet larger than the period",
+			clusterCount: 3000,
+			expected:     time.Hour,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Window(time.Hour, 10*time.Second, test.clusterCount))
+		})
+	}
+}
+
+func TestOffset(t *testing.T) {
+	assert.Equal(t, time.Duration(0), Offset("testNamespace/testCluster", 0))
+
+	offset := Offset("testNamespace/testCluster", time.Hour)
+	assert.Equal(t, offset, Offset("testNamespace/testCluster", time.Hour), "offsets must be deterministic")
+	assert.True(t, offset >= 0)
+	assert.True(t, offset < time.Hour)
+}
+
+func TestNextSlot(t *testing.T) {
+	key := "testNamespace/testCluster"
+	now := time.Now()
+
+	slot := NextSlot(key, 10*time.Hour, time.Hour, now)
+	assert.True(t, slot.After(now))
+	assert.True(t, slot.Sub(now) <= 10*time.Hour)
+
+	// the slot after a slot is one period later
+	assert.Equal(t, slot.Add(10*time.Hour), NextSlot(key, 10*time.Hour, time.Hour, slot))
+}
+
+func TestOffsetSpread(t *testing.T) {
+	// offsets of a fleet are spread over the window rather than bunched up
+	buckets := make([]int, 10)
+	for i := 0; i < 1000; i++ {
+		offset := Offset(fmt.Sprintf("namespace-%d/cluster-%d", i, i), time.Hour)
+		buckets[offset/(6*time.Minute)]++
+	}
+	for _, count := range buckets {
+		assert.True(t, count > 50, "bucket with %d of 1000 clusters", count)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package selfservice lets application teams request paging for their
+// clusters without a change of the labels by the hub admins: a
+// ClusterDeployment with the config.ClusterDeploymentIntegrationAnnotation
+// annotation set to the name of a PagerDutyIntegration with
+// spec.selfServiceOnboarding is managed by it, whatever its labels, as long
+// as its namespace is allowed. A validating webhook rejects the annotations
+// the policy doesn't allow, and the operator ignores them when the webhook
+// is bypassed.
+package selfservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ValidatePath is where the webhook server serves the Validator
+const ValidatePath = "/validate-clusterdeployment"
+
+// Requests returns true if the ClusterDeployment asks to be managed by the
+// PagerDutyIntegration through its annotation
+func Requests(cd metav1.Object, pdi *pagerdutyv1alpha1.PagerDutyIntegration) bool {
+	return cd.GetAnnotations()[config.ClusterDeploymentIntegrationAnnotation] == pdi.Name
+}
+
+// Denied returns why the PagerDutyIntegration doesn't let the
+// ClusterDeployments in namespace opt into it, or "" if it does. The
+// namespace is read through reader.
+func Denied(reader client.Reader, pdi *pagerdutyv1alpha1.PagerDutyIntegration, namespace string) (string, error) {
+	policy := pdi.Spec.SelfServiceOnboarding
+	if policy == nil {
+		return fmt.Sprintf("PagerDutyIntegration %s/%s doesn't allow self-service onboarding", pdi.Namespace, pdi.Name), nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&policy.NamespaceSelector)
+	if err != nil {
+		return "", err
+	}
+	if selector.Empty() {
+		return "", nil
+	}
+
+	ns := &corev1.Namespace{}
+	err = reader.Get(context.TODO(), types.NamespacedName{Name: namespace}, ns)
+	if err != nil {
+		return "", err
+	}
+	if !selector.Matches(labels.Set(ns.Labels)) {
+		return fmt.Sprintf("PagerDutyIntegration %s/%s doesn't allow self-service onboarding from namespace %s", pdi.Namespace, pdi.Name, namespace), nil
+	}
+	return "", nil
+}
+
+// Validator is the validating webhook of ClusterDeployments, rejecting the
+// annotations opting into a PagerDutyIntegration that doesn't exist or
+// doesn't allow it. Only annotations being set or changed are checked, so a
+// policy made stricter doesn't block the updates of the clusters already
+// onboarded, which the operator stops managing instead.
+type Validator struct {
+	// Client lists the PagerDutyIntegrations
+	Client client.Client
+	// Reader reads the Namespaces, uncached so the Namespaces of the hub
+	// aren't all watched
+	Reader client.Reader
+}
+
+var _ admission.Handler = &Validator{}
+
+// Handle validates the annotation of the ClusterDeployment of req
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	cd := &metav1.PartialObjectMetadata{}
+	err := json.Unmarshal(req.Object.Raw, cd)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	name, ok := cd.Annotations[config.ClusterDeploymentIntegrationAnnotation]
+	if !ok {
+		return admission.Allowed("")
+	}
+
+	if len(req.OldObject.Raw) > 0 {
+		old := &metav1.PartialObjectMetadata{}
+		err = json.Unmarshal(req.OldObject.Raw, old)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if previous, ok := old.Annotations[config.ClusterDeploymentIntegrationAnnotation]; ok && previous == name {
+			return admission.Allowed("")
+		}
+	}
+
+	pdiList := &pagerdutyv1alpha1.PagerDutyIntegrationList{}
+	err = v.Client.List(ctx, pdiList)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	denied := fmt.Sprintf("no PagerDutyIntegration named %s", name)
+	for i := range pdiList.Items {
+		pdi := &pdiList.Items[i]
+		if pdi.Name != name {
+			continue
+		}
+		denied, err = Denied(v.Reader, pdi, req.Namespace)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if denied == "" {
+			return admission.Allowed("")
+		}
+	}
+	return admission.Denied(fmt.Sprintf("annotation %s: %s", config.ClusterDeploymentIntegrationAnnotation, denied))
+}
@@ -0,0 +1,182 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selfservice
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const testPDIName = "test-pdi"
+
+func testPDI(policy *pagerdutyv1alpha1.SelfServiceOnboarding) *pagerdutyv1alpha1.PagerDutyIntegration {
+	return &pagerdutyv1alpha1.PagerDutyIntegration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testPDIName,
+			Namespace: config.OperatorNamespace,
+		},
+		Spec: pagerdutyv1alpha1.PagerDutyIntegrationSpec{
+			SelfServiceOnboarding: policy,
+		},
+	}
+}
+
+func testNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+	}
+}
+
+// rawClusterDeployment returns the JSON of a ClusterDeployment with the
+// given annotations, or nil if there are none
+func rawClusterDeployment(t *testing.T, annotations map[string]string) runtime.RawExtension {
+	if annotations == nil {
+		return runtime.RawExtension{}
+	}
+	raw, err := json.Marshal(metav1.PartialObjectMetadata{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "hive.openshift.io/v1", Kind: "ClusterDeployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "team-a", Annotations: annotations},
+	})
+	assert.Nil(t, err)
+	return runtime.RawExtension{Raw: raw}
+}
+
+func TestDenied(t *testing.T) {
+	reader := fakekubeclient.NewFakeClient(
+		testNamespace("team-a", map[string]string{"paging": "allowed"}),
+		testNamespace("team-b", nil),
+	)
+
+	tests := []struct {
+		name         string
+		policy       *pagerdutyv1alpha1.SelfServiceOnboarding
+		namespace    string
+		expectDenied bool
+	}{
+		{
+			name:         "Test No Policy",
+			namespace:    "team-a",
+			expectDenied: true,
+		},
+		{
+			name:      "Test Empty Selector",
+			policy:    &pagerdutyv1alpha1.SelfServiceOnboarding{},
+			namespace: "team-b",
+		},
+		{
+			name:      "Test Namespace Selected",
+			policy:    &pagerdutyv1alpha1.SelfServiceOnboarding{NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"paging": "allowed"}}},
+			namespace: "team-a",
+		},
+		{
+			name:         "Test Namespace Not Selected",
+			policy:       &pagerdutyv1alpha1.SelfServiceOnboarding{NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"paging": "allowed"}}},
+			namespace:    "team-b",
+			expectDenied: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			denied, err := Denied(reader, testPDI(test.policy), test.namespace)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expectDenied, denied != "", denied)
+		})
+	}
+}
+
+func TestValidator(t *testing.T) {
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	allowTeamA := &pagerdutyv1alpha1.SelfServiceOnboarding{NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"paging": "allowed"}}}
+
+	tests := []struct {
+		name            string
+		pdi             *pagerdutyv1alpha1.PagerDutyIntegration
+		namespaceLabels map[string]string
+		annotations     map[string]string
+		oldAnnotation   map[string]string
+		expectAllowed   bool
+	}{
+		{
+			name:          "Test Not Annotated",
+			annotations:   map[string]string{},
+			expectAllowed: true,
+		},
+		{
+			name:            "Test Allowed",
+			pdi:             testPDI(allowTeamA),
+			namespaceLabels: map[string]string{"paging": "allowed"},
+			annotations:     map[string]string{config.ClusterDeploymentIntegrationAnnotation: testPDIName},
+			expectAllowed:   true,
+		},
+		{
+			name:        "Test Unknown PagerDutyIntegration",
+			pdi:         testPDI(allowTeamA),
+			annotations: map[string]string{config.ClusterDeploymentIntegrationAnnotation: "other-pdi"},
+		},
+		{
+			name:        "Test Self-Service Not Enabled",
+			pdi:         testPDI(nil),
+			annotations: map[string]string{config.ClusterDeploymentIntegrationAnnotation: testPDIName},
+		},
+		{
+			name:        "Test Namespace Not Allowed",
+			pdi:         testPDI(allowTeamA),
+			annotations: map[string]string{config.ClusterDeploymentIntegrationAnnotation: testPDIName},
+		},
+		{
+			name:          "Test Unchanged Annotation Not Checked",
+			pdi:           testPDI(allowTeamA),
+			annotations:   map[string]string{config.ClusterDeploymentIntegrationAnnotation: testPDIName},
+			oldAnnotation: map[string]string{config.ClusterDeploymentIntegrationAnnotation: testPDIName},
+			expectAllowed: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			objects := []runtime.Object{testNamespace("team-a", test.namespaceLabels)}
+			if test.pdi != nil {
+				objects = append(objects, test.pdi)
+			}
+			c := fakekubeclient.NewFakeClientWithScheme(scheme.Scheme, objects...)
+			v := &Validator{Client: c, Reader: c}
+
+			response := v.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Namespace: "team-a",
+				Object:    rawClusterDeployment(t, test.annotations),
+				OldObject: rawClusterDeployment(t, test.oldAnnotation),
+			}})
+			assert.Equal(t, test.expectAllowed, response.Allowed, response.Result)
+		})
+	}
+}
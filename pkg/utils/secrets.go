@@ -5,6 +5,7 @@ import (
 	"crypto/md5"
 	"fmt"
 
+	"github.com/openshift/pagerduty-operator/pkg/logging"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,6 +22,7 @@ func LoadSecretData(c client.Client, secretName, namespace, dataKey string) (str
 	if !ok {
 		return "", fmt.Errorf("secret %s did not contain key %s", secretName, dataKey)
 	}
+	logging.AddSecret(string(retStr))
 	return string(retStr), nil
 }
 
@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-logr/logr"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -130,3 +131,35 @@ func DeleteSecret(name string, namespace string, client client.Client, reqLogger
 
 	return nil
 }
+
+// DeletePagerDutyService deletes a PagerDutyService
+func DeletePagerDutyService(name string, namespace string, client client.Client, reqLogger logr.Logger) error {
+	pdService := &pagerdutyv1alpha1.PagerDutyService{}
+	err := client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, pdService)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return nil
+		}
+		// Error finding the PagerDutyService, requeue
+		return err
+	}
+
+	reqLogger.Info("Deleting PagerDutyService", "Namespace", namespace, "Name", name)
+	err = client.Delete(context.TODO(), pdService)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return nil
+		}
+		// Error deleting the PagerDutyService, requeue
+		return err
+	}
+
+	return nil
+}
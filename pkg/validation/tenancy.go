@@ -0,0 +1,69 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"fmt"
+
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// secretRef is a reference of the spec to a secret on the hub
+type secretRef struct {
+	field string
+	ref   *corev1.SecretReference
+}
+
+// hubSecretRefs returns the references of the PDI to the secrets the
+// operator reads or writes on the hub. TargetSecretRef and its like name
+// secrets in the clusters and are left out.
+func hubSecretRefs(pdi *pagerdutyv1alpha1.PagerDutyIntegration) []secretRef {
+	refs := []secretRef{
+		{"spec.pagerdutyApiKeySecretRef", &pdi.Spec.PagerdutyApiKeySecretRef},
+		{"spec.escrowSecretRef", pdi.Spec.EscrowSecretRef},
+	}
+	if pdi.Spec.AccountMigration != nil {
+		refs = append(refs, secretRef{"spec.accountMigration.pagerdutyApiKeySecretRef", &pdi.Spec.AccountMigration.PagerdutyApiKeySecretRef})
+	}
+	if pdi.Spec.SharedIntegrationKey != nil {
+		refs = append(refs, secretRef{"spec.sharedIntegrationKey.integrationKeySecretRef", &pdi.Spec.SharedIntegrationKey.IntegrationKeySecretRef})
+	}
+	if pdi.Spec.FleetHygieneService != nil {
+		refs = append(refs, secretRef{"spec.fleetHygieneService.integrationKeySecretRef", &pdi.Spec.FleetHygieneService.IntegrationKeySecretRef})
+	}
+	if pdi.Spec.CoverageReport != nil {
+		refs = append(refs, secretRef{"spec.coverageReport.changeEventSecretRef", pdi.Spec.CoverageReport.ChangeEventSecretRef})
+	}
+	return refs
+}
+
+// TenancyViolation returns an error if the PDI, outside of
+// operatorNamespace, refers to a secret on the hub outside of its own
+// namespace. The PagerDutyIntegrations of other namespaces belong to
+// tenants, who must not get the operator to use the API keys of others or
+// write to their secrets.
+func TenancyViolation(pdi *pagerdutyv1alpha1.PagerDutyIntegration, operatorNamespace string) error {
+	if operatorNamespace == "" || pdi.Namespace == operatorNamespace {
+		return nil
+	}
+	for _, secret := range hubSecretRefs(pdi) {
+		if secret.ref != nil && secret.ref.Namespace != pdi.Namespace {
+			return fmt.Errorf("%s: secret %s/%s is outside of namespace %s, a PagerDutyIntegration outside of namespace %s may only refer to secrets of its own namespace",
+				secret.field, secret.ref.Namespace, secret.ref.Name, pdi.Namespace, operatorNamespace)
+		}
+	}
+	return nil
+}
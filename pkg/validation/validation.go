@@ -0,0 +1,71 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package validation holds the validating webhook of the
+// PagerDutyIntegrations, rejecting the specs the operator can't act on
+// before they are stored.
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/openshift/pagerduty-operator/pkg/kube"
+	pd "github.com/openshift/pagerduty-operator/pkg/pagerduty"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ValidatePath is where the webhook server serves the Validator
+const ValidatePath = "/validate-pagerdutyintegration"
+
+// Validator is the validating webhook of PagerDutyIntegrations. The API
+// server converts the requests to v1alpha1, so a single version is decoded.
+type Validator struct {
+	// OperatorNamespace is the namespace the operator runs in, the
+	// PagerDutyIntegrations of other namespaces are checked with
+	// TenancyViolation. Empty skips the check.
+	OperatorNamespace string
+}
+
+var _ admission.Handler = &Validator{}
+
+// Handle validates the PagerDutyIntegration of req
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	pdi := &pagerdutyv1alpha1.PagerDutyIntegration{}
+	err := json.Unmarshal(req.Object.Raw, pdi)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if pdi.Spec.ServiceNameTemplate != "" {
+		err = pd.ValidateServiceNameTemplate(pdi.Spec.ServiceNameTemplate)
+		if err != nil {
+			return admission.Denied(fmt.Sprintf("spec.serviceNameTemplate: %v", err))
+		}
+	}
+
+	err = kube.ValidateSecretType(pdi.Spec.SecretType)
+	if err != nil {
+		return admission.Denied(fmt.Sprintf("spec.secretType: %v", err))
+	}
+
+	err = TenancyViolation(pdi, v.OperatorNamespace)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("")
+}
@@ -0,0 +1,184 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// rawPagerDutyIntegration returns the JSON of a PagerDutyIntegration with
+// the given service name template
+func rawPagerDutyIntegration(t *testing.T, serviceNameTemplate string) runtime.RawExtension {
+	raw, err := json.Marshal(&pagerdutyv1alpha1.PagerDutyIntegration{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "pagerduty.openshift.io/v1alpha1", Kind: "PagerDutyIntegration"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pdi", Namespace: "pagerduty-operator"},
+		Spec:       pagerdutyv1alpha1.PagerDutyIntegrationSpec{ServiceNameTemplate: serviceNameTemplate},
+	})
+	assert.Nil(t, err)
+	return runtime.RawExtension{Raw: raw}
+}
+
+func TestValidator(t *testing.T) {
+	tests := []struct {
+		name                string
+		serviceNameTemplate string
+		expectAllowed       bool
+	}{
+		{
+			name:          "Test No Template",
+			expectAllowed: true,
+		},
+		{
+			name:                "Test Valid Template",
+			serviceNameTemplate: "{{.Prefix}}-{{index .Labels \"region\"}}-{{.ClusterID}}",
+			expectAllowed:       true,
+		},
+		{
+			name:                "Test Unparsable Template",
+			serviceNameTemplate: "{{.Prefix}-{{.ClusterID}}",
+		},
+		{
+			name:                "Test Unknown Field",
+			serviceNameTemplate: "{{.Prefix}}-{{.ClusterName}}",
+		},
+		{
+			name:                "Test Empty Name",
+			serviceNameTemplate: "{{if .Labels.paging}}{{.ClusterID}}{{end}}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := &Validator{}
+			response := v.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Object: rawPagerDutyIntegration(t, test.serviceNameTemplate),
+			}})
+			assert.Equal(t, test.expectAllowed, response.Allowed, response.Result)
+		})
+	}
+}
+
+func TestValidatorTenancy(t *testing.T) {
+	tests := []struct {
+		name          string
+		namespace     string
+		apiKeyRef     corev1.SecretReference
+		escrowRef     *corev1.SecretReference
+		expectAllowed bool
+	}{
+		{
+			name:          "Test Operator Namespace Refers To Any Namespace",
+			namespace:     "pagerduty-operator",
+			apiKeyRef:     corev1.SecretReference{Namespace: "other", Name: "pagerduty-api-key"},
+			expectAllowed: true,
+		},
+		{
+			name:          "Test Tenant Refers To Its Namespace",
+			namespace:     "team-a",
+			apiKeyRef:     corev1.SecretReference{Namespace: "team-a", Name: "pagerduty-api-key"},
+			escrowRef:     &corev1.SecretReference{Namespace: "team-a", Name: "escrow"},
+			expectAllowed: true,
+		},
+		{
+			name:      "Test Tenant Refers To Operator API Key",
+			namespace: "team-a",
+			apiKeyRef: corev1.SecretReference{Namespace: "pagerduty-operator", Name: "pagerduty-api-key"},
+		},
+		{
+			name:      "Test Tenant Writes Escrow To Another Namespace",
+			namespace: "team-a",
+			apiKeyRef: corev1.SecretReference{Namespace: "team-a", Name: "pagerduty-api-key"},
+			escrowRef: &corev1.SecretReference{Namespace: "team-b", Name: "escrow"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			raw, err := json.Marshal(&pagerdutyv1alpha1.PagerDutyIntegration{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "pagerduty.openshift.io/v1alpha1", Kind: "PagerDutyIntegration"},
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pdi", Namespace: test.namespace},
+				Spec: pagerdutyv1alpha1.PagerDutyIntegrationSpec{
+					PagerdutyApiKeySecretRef: test.apiKeyRef,
+					EscrowSecretRef:          test.escrowRef,
+				},
+			})
+			assert.Nil(t, err)
+
+			v := &Validator{OperatorNamespace: "pagerduty-operator"}
+			response := v.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Object: runtime.RawExtension{Raw: raw},
+			}})
+			assert.Equal(t, test.expectAllowed, response.Allowed, response.Result)
+		})
+	}
+}
+
+func TestValidatorSecretType(t *testing.T) {
+	tests := []struct {
+		name          string
+		secretType    corev1.SecretType
+		expectAllowed bool
+	}{
+		{
+			name:          "Test Default",
+			expectAllowed: true,
+		},
+		{
+			name:          "Test Opaque",
+			secretType:    corev1.SecretTypeOpaque,
+			expectAllowed: true,
+		},
+		{
+			name:          "Test Custom Type",
+			secretType:    "example.com/pagerduty",
+			expectAllowed: true,
+		},
+		{
+			name:       "Test TLS",
+			secretType: corev1.SecretTypeTLS,
+		},
+		{
+			name:       "Test Docker Config",
+			secretType: corev1.SecretTypeDockerConfigJson,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			raw, err := json.Marshal(&pagerdutyv1alpha1.PagerDutyIntegration{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "pagerduty.openshift.io/v1alpha1", Kind: "PagerDutyIntegration"},
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pdi", Namespace: "pagerduty-operator"},
+				Spec:       pagerdutyv1alpha1.PagerDutyIntegrationSpec{SecretType: test.secretType},
+			})
+			assert.Nil(t, err)
+
+			v := &Validator{}
+			response := v.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Object: runtime.RawExtension{Raw: raw},
+			}})
+			assert.Equal(t, test.expectAllowed, response.Allowed, response.Result)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook receives the incident webhooks PagerDuty sends to the
+// operator, and authenticates them. Requests can be required to carry a
+// valid PagerDuty V3 signature, a client certificate signed by a trusted CA,
+// or both, so security teams can pick the mode they need.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/openshift/pagerduty-operator/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// SignatureHeader is the header of the V3 webhook signatures
+const SignatureHeader = "X-PagerDuty-Signature"
+
+// signatureVersion prefixes each signature of SignatureHeader
+const signatureVersion = "v1="
+
+// maxBodyBytes caps the body read to verify a signature
+const maxBodyBytes = 1 << 20
+
+// Auth configures how the receiver authenticates requests. The zero value
+// accepts every request.
+type Auth struct {
+	// SigningSecrets of the PagerDuty webhook subscriptions. A request
+	// must be signed with one of them, several are set while a secret is
+	// rotated. None disables the signature check.
+	SigningSecrets []string
+
+	// ClientCAs verify the certificate clients must present. Nil disables
+	// mTLS.
+	ClientCAs *x509.CertPool
+}
+
+// Disabled returns true if neither check is enabled, every request is then
+// accepted
+func (a Auth) Disabled() bool {
+	return len(a.SigningSecrets) == 0 && a.ClientCAs == nil
+}
+
+// AuthFromSecret returns the Auth the operator Secret configures: the
+// signing secrets listed one per line under config.WebhookSigningSecretsKey,
+// and the PEM CA bundle under config.WebhookClientCAKey. A key left out
+// disables its check.
+func AuthFromSecret(secret *corev1.Secret) (Auth, error) {
+	auth := Auth{}
+
+	for _, line := range strings.Split(string(secret.Data[config.WebhookSigningSecretsKey]), "\n") {
+		if s := strings.TrimSpace(line); s != "" {
+			auth.SigningSecrets = append(auth.SigningSecrets, s)
+		}
+	}
+
+	if ca, ok := secret.Data[config.WebhookClientCAKey]; ok {
+		auth.ClientCAs = x509.NewCertPool()
+		if !auth.ClientCAs.AppendCertsFromPEM(ca) {
+			return Auth{}, fmt.Errorf("no PEM certificate found in key %s of secret %s/%s", config.WebhookClientCAKey, secret.Namespace, secret.Name)
+		}
+	}
+
+	return auth, nil
+}
+
+// TLSConfig returns the TLS configuration of the receiver's server, which
+// requires and verifies a client certificate when mTLS is enabled
+func (a Auth) TLSConfig() *tls.Config {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if a.ClientCAs != nil {
+		tlsConfig.ClientCAs = a.ClientCAs
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig
+}
+
+// Handler returns next behind the signature check, rejecting unsigned or
+// wrongly signed requests with 401. The TLS configuration enforces mTLS.
+func (a Auth) Handler(next http.Handler) http.Handler {
+	if len(a.SigningSecrets) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxBodyBytes))
+		if err != nil {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		if !VerifySignature(body, req.Header.Get(SignatureHeader), a.SigningSecrets) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+		// the body was consumed, give next a fresh copy
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, req)
+	})
+}
+
+// VerifySignature returns true if header, the comma separated signatures
+// PagerDuty sends, has a HMAC-SHA256 of body made with one of secrets
+func VerifySignature(body []byte, header string, secrets []string) bool {
+	for _, signature := range strings.Split(header, ",") {
+		signature = strings.TrimSpace(signature)
+		if !strings.HasPrefix(signature, signatureVersion) {
+			continue
+		}
+		received, err := hex.DecodeString(strings.TrimPrefix(signature, signatureVersion))
+		if err != nil {
+			continue
+		}
+		for _, secret := range secrets {
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write(body)
+			if hmac.Equal(received, mac.Sum(nil)) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/pagerduty-operator/config"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const testBody = `{"event":{"event_type":"incident.triggered"}}`
+
+func sign(body, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return signatureVersion + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		expect bool
+	}{
+		{name: "Valid", header: sign(testBody, "secret1"), expect: true},
+		{name: "Rotated Secret", header: sign(testBody, "secret2"), expect: true},
+		{name: "One Of Several Signatures", header: sign(testBody, "other") + ", " + sign(testBody, "secret1"), expect: true},
+		{name: "Wrong Secret", header: sign(testBody, "other")},
+		{name: "Other Body", header: sign(`{}`, "secret1")},
+		{name: "Unknown Version", header: strings.Replace(sign(testBody, "secret1"), "v1=", "v2=", 1)},
+		{name: "Not Hex", header: "v1=zz"},
+		{name: "Missing", header: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, VerifySignature([]byte(testBody), test.header, []string{"secret1", "secret2"}))
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// the body is still readable behind the check
+		body, _ := ioutil.ReadAll(req.Body)
+		assert.Equal(t, testBody, string(body))
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	tests := []struct {
+		name         string
+		auth         Auth
+		header       string
+		expectStatus int
+	}{
+		{name: "Signature Check Disabled", auth: Auth{}, expectStatus: http.StatusAccepted},
+		{name: "Signed", auth: Auth{SigningSecrets: []string{"secret1"}}, header: sign(testBody, "secret1"), expectStatus: http.StatusAccepted},
+		{name: "Unsigned", auth: Auth{SigningSecrets: []string{"secret1"}}, expectStatus: http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(testBody))
+			if test.header != "" {
+				req.Header.Set(SignatureHeader, test.header)
+			}
+			rec := httptest.NewRecorder()
+
+			test.auth.Handler(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, test.expectStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuthFromSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			config.WebhookSigningSecretsKey: []byte("secret1\n\n  secret2 \n"),
+			config.WebhookClientCAKey:       testCA(t),
+		},
+	}
+
+	auth, err := AuthFromSecret(secret)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"secret1", "secret2"}, auth.SigningSecrets)
+	assert.NotNil(t, auth.ClientCAs)
+	assert.False(t, auth.Disabled())
+	assert.Equal(t, tls.RequireAndVerifyClientCert, auth.TLSConfig().ClientAuth)
+
+	// mTLS is off without a CA bundle, and a bundle without certificates is refused
+	auth, err = AuthFromSecret(&corev1.Secret{})
+	assert.NoError(t, err)
+	assert.Equal(t, tls.NoClientCert, auth.TLSConfig().ClientAuth)
+	assert.True(t, auth.Disabled())
+	_, err = AuthFromSecret(&corev1.Secret{Data: map[string][]byte{config.WebhookClientCAKey: []byte("not a certificate")}})
+	assert.Error(t, err)
+}
+
+// testCA returns a self-signed CA certificate in PEM
+func testCA(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -0,0 +1,252 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("webhook")
+
+// ReceiverPath is where PagerDuty delivers the incident webhooks
+const ReceiverPath = "/incidents"
+
+// incidentEvent is the part of a PagerDuty V3 webhook the receiver reads
+type incidentEvent struct {
+	Event struct {
+		EventType string `json:"event_type"`
+		Data      struct {
+			ID      string `json:"id"`
+			HTMLURL string `json:"html_url"`
+			Service struct {
+				ID string `json:"id"`
+			} `json:"service"`
+		} `json:"data"`
+	} `json:"event"`
+}
+
+// Receiver annotates the PagerDutyService of the PagerDuty service an
+// incident was triggered on or resolved, and its ClusterDeployment, with
+// their number of active incidents and the URL of the last one
+type Receiver struct {
+	client client.Client
+}
+
+// NewReceiver returns a Receiver reading and annotating the objects with c
+func NewReceiver(c client.Client) *Receiver {
+	return &Receiver{client: c}
+}
+
+// ServeHTTP records the incident of the webhook. Webhooks of other events,
+// or of services the operator didn't create, are acknowledged and ignored.
+// Failures get a 500, so PagerDuty delivers the webhook again.
+func (rc *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	event := &incidentEvent{}
+	err := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxBodyBytes)).Decode(event)
+	if err != nil {
+		http.Error(w, "invalid webhook", http.StatusBadRequest)
+		return
+	}
+
+	var active bool
+	switch event.Event.EventType {
+	case "incident.triggered", "incident.reopened":
+		active = true
+	case "incident.resolved":
+		active = false
+	default:
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	incident := event.Event.Data
+	err = rc.record(incident.Service.ID, incident.ID, incident.HTMLURL, active)
+	if err != nil {
+		log.Error(err, "Failed to record the incident", "ServiceID", incident.Service.ID, "IncidentID", incident.ID)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// record annotates the PagerDutyService of serviceID and its
+// ClusterDeployment with the incident, active once triggered and no longer
+// once resolved
+func (rc *Receiver) record(serviceID, incidentID, url string, active bool) error {
+	pdServices := &pagerdutyv1alpha1.PagerDutyServiceList{}
+	err := rc.client.List(context.TODO(), pdServices)
+	if err != nil {
+		return err
+	}
+	var pdService *pagerdutyv1alpha1.PagerDutyService
+	for i := range pdServices.Items {
+		if serviceID != "" && pdServices.Items[i].Spec.ServiceID == serviceID {
+			pdService = &pdServices.Items[i]
+			break
+		}
+	}
+	if pdService == nil {
+		return nil
+	}
+
+	if pdService.Annotations == nil {
+		pdService.Annotations = map[string]string{}
+	}
+	ids := activeIncidentIDs(pdService.Annotations[config.ActiveIncidentIDsAnnotation], incidentID, active)
+	changed := setAnnotation(pdService.Annotations, config.ActiveIncidentIDsAnnotation, strings.Join(ids, ","))
+	changed = setAnnotation(pdService.Annotations, config.ActiveIncidentsAnnotation, strconv.Itoa(len(ids))) || changed
+	if active && url != "" {
+		changed = setAnnotation(pdService.Annotations, config.LastIncidentURLAnnotation, url) || changed
+	}
+	if changed {
+		err = rc.client.Update(context.TODO(), pdService)
+		if err != nil {
+			return err
+		}
+	}
+
+	// pdService is one of the items, the count includes the incident
+	return rc.annotateClusterDeployment(pdService, pdServices.Items, url, active)
+}
+
+// annotateClusterDeployment sets the active incidents of all the
+// PagerDutyServices of the ClusterDeployment of pdService on it, and the URL
+// of the incident if it became active
+func (rc *Receiver) annotateClusterDeployment(pdService *pagerdutyv1alpha1.PagerDutyService, pdServices []pagerdutyv1alpha1.PagerDutyService, url string, active bool) error {
+	cd := &hivev1.ClusterDeployment{}
+	err := rc.client.Get(context.TODO(), types.NamespacedName{Namespace: pdService.Namespace, Name: pdService.Spec.ClusterDeploymentRef.Name}, cd)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	// the additional services of the cluster count too
+	count := 0
+	for i := range pdServices {
+		s := &pdServices[i]
+		if s.Namespace != cd.Namespace || s.Spec.ClusterDeploymentRef.Name != cd.Name {
+			continue
+		}
+		n, _ := strconv.Atoi(s.Annotations[config.ActiveIncidentsAnnotation])
+		count += n
+	}
+
+	if cd.Annotations == nil {
+		cd.Annotations = map[string]string{}
+	}
+	changed := setAnnotation(cd.Annotations, config.ActiveIncidentsAnnotation, strconv.Itoa(count))
+	if active && url != "" {
+		changed = setAnnotation(cd.Annotations, config.LastIncidentURLAnnotation, url) || changed
+	}
+	if !changed {
+		return nil
+	}
+	return rc.client.Update(context.TODO(), cd)
+}
+
+// activeIncidentIDs returns the comma separated ids, sorted, with incidentID
+// added if active and removed otherwise
+func activeIncidentIDs(ids string, incidentID string, active bool) []string {
+	set := map[string]bool{}
+	for _, id := range strings.Split(ids, ",") {
+		if id != "" {
+			set[id] = true
+		}
+	}
+	if incidentID != "" {
+		set[incidentID] = active
+	}
+
+	result := []string{}
+	for id, ok := range set {
+		if ok {
+			result = append(result, id)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// setAnnotation sets key to value in annotations and returns true if it
+// changed
+func setAnnotation(annotations map[string]string, key, value string) bool {
+	if current, ok := annotations[key]; ok && current == value {
+		return false
+	}
+	annotations[key] = value
+	return true
+}
+
+// Server serves the incident webhooks on every replica of the operator, with
+// the serving certificate of the operator's webhooks
+type Server struct {
+	// Addr is the address the server binds to
+	Addr string
+	// CertDir holds the tls.crt and tls.key the server serves
+	CertDir string
+	// Auth authenticates the webhooks
+	Auth Auth
+	// Handler records the incidents
+	Handler http.Handler
+}
+
+// Start serves the incident webhooks until stop is closed
+func (s *Server) Start(stop <-chan struct{}) error {
+	mux := http.NewServeMux()
+	mux.Handle(ReceiverPath, s.Auth.Handler(s.Handler))
+	srv := &http.Server{Addr: s.Addr, Handler: mux, TLSConfig: s.Auth.TLSConfig()}
+
+	errs := make(chan error, 1)
+	go func() {
+		log.Info("Serving PagerDuty incident webhooks", "Addr", s.Addr)
+		errs <- srv.ListenAndServeTLS(filepath.Join(s.CertDir, "tls.crt"), filepath.Join(s.CertDir, "tls.key"))
+	}()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("incident webhook server stopped: %v", err)
+	case <-stop:
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return srv.Shutdown(ctx)
+	}
+}
+
+// NeedLeaderElection returns false, every replica serves the webhooks
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
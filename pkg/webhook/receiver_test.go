@@ -0,0 +1,105 @@
+// Copyright 2020 Red Hat
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hiveapis "github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/pagerduty-operator/config"
+	pagerdutyapis "github.com/openshift/pagerduty-operator/pkg/apis"
+	pagerdutyv1alpha1 "github.com/openshift/pagerduty-operator/pkg/apis/pagerduty/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakekubeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNamespace   = "testNamespace"
+	testClusterName = "testCluster"
+)
+
+func testPagerDutyService(name, serviceID string) *pagerdutyv1alpha1.PagerDutyService {
+	return &pagerdutyv1alpha1.PagerDutyService{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+		Spec: pagerdutyv1alpha1.PagerDutyServiceSpec{
+			ClusterDeploymentRef: corev1.LocalObjectReference{Name: testClusterName},
+			ServiceID:            serviceID,
+		},
+	}
+}
+
+func incidentWebhook(eventType, incidentID, serviceID string) string {
+	return `{"event":{"event_type":"` + eventType + `","data":{"id":"` + incidentID + `","html_url":"https://example.pagerduty.com/incidents/` + incidentID + `","service":{"id":"` + serviceID + `"}}}}`
+}
+
+func TestReceiver(t *testing.T) {
+	assert.Nil(t, hiveapis.AddToScheme(scheme.Scheme))
+	assert.Nil(t, pagerdutyapis.AddToScheme(scheme.Scheme))
+
+	cd := &hivev1.ClusterDeployment{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testClusterName}}
+	c := fakekubeclient.NewFakeClient(cd, testPagerDutyService("osd-testCluster-pd-config", "SVC1"), testPagerDutyService("customer-testCluster-pd-config", "SVC2"))
+	rc := NewReceiver(c)
+
+	webhooks := []string{
+		incidentWebhook("incident.triggered", "INC1", "SVC1"),
+		// delivered twice
+		incidentWebhook("incident.triggered", "INC1", "SVC1"),
+		incidentWebhook("incident.acknowledged", "INC1", "SVC1"),
+		incidentWebhook("incident.triggered", "INC2", "SVC2"),
+		incidentWebhook("incident.triggered", "INC3", "SVC1"),
+		incidentWebhook("incident.resolved", "INC1", "SVC1"),
+		// not created by the operator
+		incidentWebhook("incident.triggered", "INC4", "OTHER"),
+	}
+	for _, body := range webhooks {
+		rec := httptest.NewRecorder()
+		rc.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, ReceiverPath, strings.NewReader(body)))
+		assert.Equal(t, http.StatusOK, rec.Code, body)
+	}
+
+	pdService := &pagerdutyv1alpha1.PagerDutyService{}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: "osd-testCluster-pd-config"}, pdService)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", pdService.Annotations[config.ActiveIncidentsAnnotation])
+	assert.Equal(t, "INC3", pdService.Annotations[config.ActiveIncidentIDsAnnotation])
+	assert.Equal(t, "https://example.pagerduty.com/incidents/INC3", pdService.Annotations[config.LastIncidentURLAnnotation])
+
+	// the incidents of both services of the cluster are counted
+	err = c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testClusterName}, cd)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", cd.Annotations[config.ActiveIncidentsAnnotation])
+	assert.Equal(t, "https://example.pagerduty.com/incidents/INC3", cd.Annotations[config.LastIncidentURLAnnotation])
+}
+
+func TestReceiverRejectsInvalidRequests(t *testing.T) {
+	rc := NewReceiver(fakekubeclient.NewFakeClient())
+
+	rec := httptest.NewRecorder()
+	rc.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ReceiverPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = httptest.NewRecorder()
+	rc.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, ReceiverPath, strings.NewReader("not json")))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}